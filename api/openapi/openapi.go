@@ -0,0 +1,9 @@
+// Package openapi embeds the OpenAPI spec of the REST API, the source of truth of pkg/client
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document of the REST API, served at /api/openapi.yaml
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	auctionpb "github.com/cristianortiz/auctionEngine/pkg/auctionpb/v1"
+	"github.com/spf13/cobra"
+)
+
+func newBidCmd() *cobra.Command {
+	var (
+		userID string
+		amount float64
+	)
+	cmd := &cobra.Command{
+		Use:   "bid LOT_ID",
+		Short: "Place a test bid on behalf of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				resp, err := client.PlaceBid(ctx, &auctionpb.PlaceBidRequest{
+					LotId:  args[0],
+					UserId: userID,
+					Amount: amount,
+				})
+				if err != nil {
+					return err
+				}
+				return printProto(resp.GetBid())
+			})
+		},
+	}
+	cmd.Flags().StringVar(&userID, "user", "", "bidder user ID (required)")
+	cmd.Flags().Float64Var(&amount, "amount", 0, "bid amount (required)")
+	_ = cmd.MarkFlagRequired("user")
+	_ = cmd.MarkFlagRequired("amount")
+	return cmd
+}
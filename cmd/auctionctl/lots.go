@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	auctionpb "github.com/cristianortiz/auctionEngine/pkg/auctionpb/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newLotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lots",
+		Short: "Manage auction lots",
+	}
+	cmd.AddCommand(newLotsCreateCmd(), newLotsStartCmd(), newLotsCancelCmd(), newLotsListCmd(), newLotsGetCmd())
+	return cmd
+}
+
+func newLotsCreateCmd() *cobra.Command {
+	var (
+		title       string
+		description string
+		price       float64
+		endsIn      time.Duration
+		startsIn    time.Duration
+		reverse     bool
+		closing     string
+		maxExtend   time.Duration
+		threshold   float64
+		extension   time.Duration
+		quantity    int
+		pricing     string
+		start       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a pending lot, optionally starting it right away",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				req := &auctionpb.CreateLotRequest{
+					Title:                title,
+					Description:          description,
+					InitialPrice:         price,
+					EndTime:              timestamppb.New(time.Now().Add(endsIn)),
+					TimeExtensionSeconds: int64(extension.Seconds()),
+				}
+				req.ClosingMode = closing
+				req.MaxExtensionSeconds = int64(maxExtend.Seconds())
+				req.ExtensionPriceThreshold = threshold
+				req.Quantity, req.Pricing = int32(quantity), pricing
+				if reverse {
+					req.Type = "reverse"
+				}
+				if startsIn > 0 {
+					req.StartTime = timestamppb.New(time.Now().Add(startsIn))
+				}
+				lot, err := client.CreateLot(ctx, req)
+				if err != nil {
+					return err
+				}
+				if start {
+					if lot, err = client.StartLot(ctx, &auctionpb.StartLotRequest{LotId: lot.GetLotId()}); err != nil {
+						return err
+					}
+				}
+				return printProto(lot)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&title, "title", "", "lot title (required)")
+	cmd.Flags().StringVar(&description, "description", "", "lot description")
+	cmd.Flags().Float64Var(&price, "price", 0, "initial price (required)")
+	cmd.Flags().DurationVar(&endsIn, "ends-in", time.Hour, "time until the lot ends")
+	cmd.Flags().DurationVar(&startsIn, "starts-in", 0, "time until the lot opens, it stays in preview until then")
+	cmd.Flags().DurationVar(&extension, "extension", 0, "anti-sniping time extension, 0 uses the server default")
+	cmd.Flags().StringVar(&closing, "closing", "", "closing policy: soft, hard or price_threshold, empty uses soft")
+	cmd.Flags().DurationVar(&maxExtend, "max-extension", 0, "cap of the total time extension, 0 means no cap")
+	cmd.Flags().Float64Var(&threshold, "extension-threshold", 0, "minimum price change extending a price_threshold lot")
+	cmd.Flags().BoolVar(&reverse, "reverse", false, "create a reverse (procurement) lot, the lowest bid wins")
+	cmd.Flags().IntVar(&quantity, "quantity", 1, "identical units of the lot, the best bidders win one each")
+	cmd.Flags().StringVar(&pricing, "pricing", "", "what the winners of several units pay: pay_as_bid or uniform, empty uses pay_as_bid")
+	cmd.Flags().BoolVar(&start, "start", false, "start the lot after creating it")
+	_ = cmd.MarkFlagRequired("title")
+	_ = cmd.MarkFlagRequired("price")
+	return cmd
+}
+
+func newLotsStartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "start LOT_ID",
+		Short: "Open a pending lot for bidding",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				lot, err := client.StartLot(ctx, &auctionpb.StartLotRequest{LotId: args[0]})
+				if err != nil {
+					return err
+				}
+				return printProto(lot)
+			})
+		},
+	}
+}
+
+func newLotsCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel LOT_ID",
+		Short: "Cancel a pending or active lot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				lot, err := client.CancelLot(ctx, &auctionpb.CancelLotRequest{LotId: args[0]})
+				if err != nil {
+					return err
+				}
+				return printProto(lot)
+			})
+		},
+	}
+}
+
+func newLotsGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get LOT_ID",
+		Short: "Show the current state of a lot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				lot, err := client.GetLotState(ctx, &auctionpb.GetLotStateRequest{LotId: args[0]})
+				if err != nil {
+					return err
+				}
+				return printProto(lot)
+			})
+		},
+	}
+}
+
+func newLotsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the active lots",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(ctx context.Context, client auctionpb.AuctionServiceClient) error {
+				resp, err := client.ListActiveLots(ctx, &auctionpb.ListActiveLotsRequest{})
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "LOT ID\tTITLE\tPRICE\tENDS\tSEQ")
+				for _, lot := range resp.GetLots() {
+					fmt.Fprintf(w, "%s\t%s\t%.2f\t%s\t%d\n",
+						lot.GetLotId(),
+						lot.GetTitle(),
+						lot.GetCurrentPrice(),
+						lot.GetEndTime().AsTime().Local().Format(time.DateTime),
+						lot.GetSeq(),
+					)
+				}
+				return w.Flush()
+			})
+		},
+	}
+}
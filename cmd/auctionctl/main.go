@@ -0,0 +1,71 @@
+// auctionctl is the admin CLI of the auction engine, it talks to the engine through its gRPC API
+// and tails live lot updates over WebSocket, useful for ops and QA without a UI
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	auctionpb "github.com/cristianortiz/auctionEngine/pkg/auctionpb/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// global flags, shared by every subcommand
+var (
+	grpcAddr string
+	timeout  time.Duration
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:           "auctionctl",
+		Short:         "Operate the auction engine: manage lots, place test bids and tail live updates",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&grpcAddr, "grpc-addr", envOr("AUCTIONCTL_GRPC_ADDR", "localhost:9090"), "engine gRPC address")
+	root.PersistentFlags().DurationVar(&timeout, "timeout", 10*time.Second, "timeout of each gRPC call")
+
+	root.AddCommand(newLotsCmd(), newBidCmd(), newTailCmd(), newTokenCmd())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// withClient dials the engine and calls fn with a client and a ctx bounded by --timeout
+func withClient(fn func(ctx context.Context, client auctionpb.AuctionServiceClient) error) error {
+	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", grpcAddr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return fn(ctx, auctionpb.NewAuctionServiceClient(conn))
+}
+
+// printProto writes msg as indented JSON to stdout
+func printProto(msg proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newTailCmd() *cobra.Command {
+	var (
+		wsURL        string
+		token        string
+		secret       string
+		lastEventSeq int64
+	)
+	cmd := &cobra.Command{
+		Use:   "tail LOT_ID",
+		Short: "Print the live WS msgs of a lot until interrupted",
+		Long: "Connects to the lot WebSocket and prints every msg received. Without --token it connects as an\n" +
+			"anonymous spectator, or mints a spectator token when --secret (AUTH_TOKEN_SECRET) is given.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" && secret != "" {
+				minted, err := auth.NewTokenService(secret).Issue(auth.Claims{UserID: uuid.New(), Role: auth.RoleSpectator}, time.Hour)
+				if err != nil {
+					return err
+				}
+				token = minted
+			}
+
+			u, err := url.Parse(wsURL)
+			if err != nil {
+				return fmt.Errorf("invalid --ws-url: %w", err)
+			}
+			u = u.JoinPath("/ws/auction", args[0])
+			query := u.Query()
+			if token != "" {
+				query.Set("token", token)
+			}
+			if cmd.Flags().Changed("last-event-seq") {
+				query.Set("last_event_seq", fmt.Sprint(lastEventSeq))
+			}
+			u.RawQuery = query.Encode()
+
+			conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+			if err != nil {
+				return fmt.Errorf("failed to connect to %s: %w", wsURL, err)
+			}
+			defer conn.Close()
+
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt)
+			go func() {
+				<-interrupt
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				_ = conn.Close()
+			}()
+
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					if websocket.IsCloseError(err, websocket.CloseNormalClosure) || errors.Is(err, net.ErrClosed) {
+						return nil
+					}
+					return err
+				}
+				fmt.Printf("%s %s\n", time.Now().Format(time.TimeOnly), data)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&wsURL, "ws-url", envOr("AUCTIONCTL_WS_URL", "ws://localhost:9000"), "engine WebSocket base URL")
+	cmd.Flags().StringVar(&token, "token", os.Getenv("AUCTIONCTL_TOKEN"), "connection token")
+	cmd.Flags().StringVar(&secret, "secret", os.Getenv("AUTH_TOKEN_SECRET"), "token secret used to mint a spectator token")
+	cmd.Flags().Int64Var(&lastEventSeq, "last-event-seq", 0, "replay the lot events after this seq")
+	return cmd
+}
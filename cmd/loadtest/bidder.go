@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+)
+
+// priceSource hands out strictly increasing bid amounts shared by all the bidders,
+// concurrent bids can still arrive out of order and be rejected, those count as rejections
+type priceSource struct {
+	mu    sync.Mutex
+	price float64
+	step  float64
+}
+
+func (p *priceSource) seed(price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if price > p.price {
+		p.price = price
+	}
+}
+
+func (p *priceSource) next() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.price += p.step
+	return p.price
+}
+
+func (p *priceSource) current() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.price
+}
+
+// bidder is a simulated user with its own WS connection, it measures the time from sending
+// a bid to receiving the lot broadcast carrying that bid amount
+type bidder struct {
+	conn   *websocket.Conn
+	lotID  uuid.UUID
+	userID uuid.UUID
+	stats  *stats
+	prices *priceSource
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[int64]time.Time // send time by bid amount in cents
+}
+
+type wsMessage struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// only the fields needed to match a broadcast with a pending bid, shared by
+// server_initial_state, server_lot_update and server_lot_delta
+type lotPayload struct {
+	CurrentPrice  *float64 `json:"current_price"`
+	LastBidAmount *float64 `json:"last_bid_amount"`
+}
+
+func dialBidder(cfg *config, stats *stats, prices *priceSource) (*bidder, error) {
+	userID := uuid.New()
+	token, err := auth.NewTokenService(cfg.secret).Issue(auth.Claims{UserID: userID, Role: auth.RoleBidder}, cfg.duration+time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(cfg.wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --ws-url: %w", err)
+	}
+	u = u.JoinPath("/ws/auction", cfg.lotID.String())
+	u.RawQuery = url.Values{"token": {token}}.Encode()
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.wsURL, err)
+	}
+
+	b := &bidder{
+		conn:    conn,
+		lotID:   cfg.lotID,
+		userID:  userID,
+		stats:   stats,
+		prices:  prices,
+		pending: make(map[int64]time.Time),
+	}
+	// the initial state seeds the shared price so the first bids are valid
+	if err := b.readInitialState(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *bidder) readInitialState() error {
+	_ = b.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer func() { _ = b.conn.SetReadDeadline(time.Time{}) }()
+	for {
+		var msg wsMessage
+		if err := b.conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("failed to read initial state: %w", err)
+		}
+		switch msg.Type {
+		case "server_initial_state":
+			var payload lotPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				return fmt.Errorf("invalid initial state: %w", err)
+			}
+			if payload.CurrentPrice != nil {
+				b.prices.seed(*payload.CurrentPrice)
+			}
+			return nil
+		case "server_error":
+			return fmt.Errorf("server error on connect: %s", msg.Payload)
+		}
+	}
+}
+
+// bid sends the next bid amount and records its send time
+func (b *bidder) bid() {
+	amount := b.prices.next()
+	msg := map[string]any{
+		"type": "client_bid",
+		"payload": map[string]any{
+			"lot_id":  b.lotID,
+			"user_id": b.userID,
+			"amount":  amount,
+		},
+	}
+
+	b.mu.Lock()
+	b.pending[cents(amount)] = time.Now()
+	b.mu.Unlock()
+
+	b.writeMu.Lock()
+	err := b.conn.WriteJSON(msg)
+	b.writeMu.Unlock()
+	if err != nil {
+		b.mu.Lock()
+		delete(b.pending, cents(amount))
+		b.mu.Unlock()
+		b.stats.sendFailed()
+		return
+	}
+	b.stats.sent()
+}
+
+// readLoop matches lot broadcasts with the pending bids of this connection until it's closed
+func (b *bidder) readLoop() {
+	for {
+		var msg wsMessage
+		if err := b.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case "server_lot_update", "server_lot_delta":
+			var payload lotPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.LastBidAmount == nil {
+				continue
+			}
+			received := time.Now()
+			b.mu.Lock()
+			sentAt, ok := b.pending[cents(*payload.LastBidAmount)]
+			delete(b.pending, cents(*payload.LastBidAmount))
+			b.mu.Unlock()
+			if ok {
+				b.stats.observe(received.Sub(sentAt))
+			}
+		case "server_error":
+			// errors don't say which bid failed, any pending bid of this connection may be the one
+			b.stats.rejected()
+		}
+	}
+}
+
+func (b *bidder) close() {
+	b.writeMu.Lock()
+	_ = b.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	b.writeMu.Unlock()
+	_ = b.conn.Close()
+}
+
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
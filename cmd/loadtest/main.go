@@ -0,0 +1,161 @@
+// loadtest drives simulated WS bidders against a lot of a running engine, ramping the bid rate,
+// and reports the bid→broadcast round trip latency so regressions in the Hub and PlaceBid path are measurable
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type config struct {
+	wsURL          string
+	secret         string
+	lotID          uuid.UUID
+	bidders        int
+	startRate      float64
+	maxRate        float64
+	ramp           time.Duration
+	duration       time.Duration
+	step           float64
+	reportInterval time.Duration
+}
+
+func main() {
+	cfg, err := parseFlags()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags() (*config, error) {
+	cfg := &config{}
+	var lotID string
+	flag.StringVar(&cfg.wsURL, "ws-url", envOr("LOADTEST_WS_URL", "ws://localhost:9000"), "engine HTTP/WS base URL")
+	flag.StringVar(&cfg.secret, "secret", os.Getenv("AUTH_TOKEN_SECRET"), "token signing secret, used to mint a bidder token per simulated bidder")
+	flag.StringVar(&lotID, "lot", "", "ID of the active lot to bid on")
+	flag.IntVar(&cfg.bidders, "bidders", 50, "number of simulated bidders, each with its own WS connection")
+	flag.Float64Var(&cfg.startRate, "start-rate", 5, "total bids per second at the start of the ramp")
+	flag.Float64Var(&cfg.maxRate, "max-rate", 100, "total bids per second at the end of the ramp")
+	flag.DurationVar(&cfg.ramp, "ramp", 30*time.Second, "time to go from --start-rate to --max-rate")
+	flag.DurationVar(&cfg.duration, "duration", time.Minute, "total test time, the rate is held at --max-rate after the ramp")
+	flag.Float64Var(&cfg.step, "step", 10, "amount added to the previous bid, must satisfy the lot increment table")
+	flag.DurationVar(&cfg.reportInterval, "report-interval", 5*time.Second, "interval of the progress reports, 0 disables them")
+	flag.Parse()
+
+	if cfg.secret == "" {
+		return nil, fmt.Errorf("--secret or AUTH_TOKEN_SECRET is required")
+	}
+	parsed, err := uuid.Parse(lotID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --lot: %w", err)
+	}
+	cfg.lotID = parsed
+	if cfg.bidders <= 0 || cfg.startRate <= 0 || cfg.maxRate < cfg.startRate || cfg.step <= 0 {
+		return nil, fmt.Errorf("--bidders, --start-rate and --step must be positive and --max-rate at least --start-rate")
+	}
+	return cfg, nil
+}
+
+func run(ctx context.Context, cfg *config) error {
+	stats := newStats()
+	prices := &priceSource{step: cfg.step}
+
+	bidders := make([]*bidder, 0, cfg.bidders)
+	for i := 0; i < cfg.bidders; i++ {
+		b, err := dialBidder(cfg, stats, prices)
+		if err != nil {
+			for _, b := range bidders {
+				b.close()
+			}
+			return fmt.Errorf("bidder %d: %w", i, err)
+		}
+		bidders = append(bidders, b)
+	}
+	fmt.Printf("connected %d bidders to lot %s, current price %.2f\n", len(bidders), cfg.lotID, prices.current())
+
+	var wg sync.WaitGroup
+	for _, b := range bidders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.readLoop()
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	if cfg.reportInterval > 0 {
+		go reportProgress(ctx, cfg, stats)
+	}
+
+	start := time.Now()
+	next := 0
+	for ctx.Err() == nil {
+		rate := currentRate(cfg, time.Since(start))
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(float64(time.Second) / rate)):
+			// round robin, so every connection carries a share of the load
+			bidders[next].bid()
+			next = (next + 1) % len(bidders)
+		}
+	}
+
+	// give the in flight bids a chance to be broadcast before closing
+	time.Sleep(2 * time.Second)
+	for _, b := range bidders {
+		b.close()
+	}
+	wg.Wait()
+
+	stats.report(os.Stdout, time.Since(start))
+	return nil
+}
+
+// currentRate interpolates the target bids per second along the ramp
+func currentRate(cfg *config, elapsed time.Duration) float64 {
+	if cfg.ramp <= 0 || elapsed >= cfg.ramp {
+		return cfg.maxRate
+	}
+	return cfg.startRate + (cfg.maxRate-cfg.startRate)*float64(elapsed)/float64(cfg.ramp)
+}
+
+func reportProgress(ctx context.Context, cfg *config, stats *stats) {
+	start := time.Now()
+	ticker := time.NewTicker(cfg.reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			elapsed := time.Since(start)
+			fmt.Printf("[%s] target %.1f bids/s, %s\n", elapsed.Truncate(time.Second), currentRate(cfg, elapsed), stats.progress())
+		}
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// stats collects the outcome of the bids sent by all the bidders
+type stats struct {
+	mu         sync.Mutex
+	sentCount  int
+	sendErrors int
+	rejections int
+	latencies  []time.Duration
+	lastReport int // latencies already included in the previous progress line
+}
+
+func newStats() *stats {
+	return &stats{}
+}
+
+func (s *stats) sent() {
+	s.mu.Lock()
+	s.sentCount++
+	s.mu.Unlock()
+}
+
+func (s *stats) sendFailed() {
+	s.mu.Lock()
+	s.sendErrors++
+	s.mu.Unlock()
+}
+
+func (s *stats) rejected() {
+	s.mu.Lock()
+	s.rejections++
+	s.mu.Unlock()
+}
+
+func (s *stats) observe(latency time.Duration) {
+	s.mu.Lock()
+	s.latencies = append(s.latencies, latency)
+	s.mu.Unlock()
+}
+
+// progress summarizes the bids sent so far and the latency of those broadcast since the previous call
+func (s *stats) progress() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	window := slices.Clone(s.latencies[s.lastReport:])
+	s.lastReport = len(s.latencies)
+	slices.Sort(window)
+	return fmt.Sprintf("sent %d, broadcast %d, rejected %d, window p50 %s p99 %s",
+		s.sentCount, len(s.latencies), s.rejections, percentile(window, 50), percentile(window, 99))
+}
+
+// report writes the final summary, bids sent but never seen in a broadcast are counted as lost,
+// they were either rejected or superseded by a higher bid before the broadcast
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sorted := slices.Clone(s.latencies)
+	slices.Sort(sorted)
+
+	fmt.Fprintf(w, "\n--- results after %s ---\n", elapsed.Truncate(time.Millisecond))
+	fmt.Fprintf(w, "bids sent:      %d (%.1f/s)\n", s.sentCount, float64(s.sentCount)/elapsed.Seconds())
+	fmt.Fprintf(w, "send errors:    %d\n", s.sendErrors)
+	fmt.Fprintf(w, "rejected:       %d\n", s.rejections)
+	fmt.Fprintf(w, "broadcast:      %d\n", len(sorted))
+	fmt.Fprintf(w, "not broadcast:  %d\n", max(0, s.sentCount-len(sorted)-s.rejections))
+	if len(sorted) == 0 {
+		fmt.Fprintln(w, "no bid was broadcast, latency unavailable")
+		return
+	}
+	fmt.Fprintln(w, "bid→broadcast latency:")
+	fmt.Fprintf(w, "  min  %s\n", sorted[0])
+	for _, p := range []float64{50, 90, 95, 99, 99.9} {
+		fmt.Fprintf(w, "  p%-4v%s\n", p, percentile(sorted, p))
+	}
+	fmt.Fprintf(w, "  max  %s\n", sorted[len(sorted)-1])
+}
+
+// percentile returns the nearest rank percentile p of sorted
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
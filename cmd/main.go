@@ -2,26 +2,103 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
+	"time"
 
+	apikeyapp "github.com/cristianortiz/auctionEngine/internal/apikey/application"
+	apikeypostgres "github.com/cristianortiz/auctionEngine/internal/apikey/infra/repository/postgres"
+	apikeyrest "github.com/cristianortiz/auctionEngine/internal/apikey/infra/rest"
 	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/chatfilter"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/fx"
+	auctiongraphql "github.com/cristianortiz/auctionEngine/internal/auction/infra/graphql"
+	auctiongrpc "github.com/cristianortiz/auctionEngine/internal/auction/infra/grpc"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/messaging"
 	"github.com/cristianortiz/auctionEngine/internal/auction/infra/repository/postgres"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/rest"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/storage"
 	wsh "github.com/cristianortiz/auctionEngine/internal/auction/infra/websocket"
+	fraudapp "github.com/cristianortiz/auctionEngine/internal/fraud/application"
+	fraudlistener "github.com/cristianortiz/auctionEngine/internal/fraud/infra/listener"
+	fraudpostgres "github.com/cristianortiz/auctionEngine/internal/fraud/infra/repository/postgres"
+	fraudrest "github.com/cristianortiz/auctionEngine/internal/fraud/infra/rest"
+	invoiceapp "github.com/cristianortiz/auctionEngine/internal/invoicing/application"
+	invoicelistener "github.com/cristianortiz/auctionEngine/internal/invoicing/infra/listener"
+	invoicepostgres "github.com/cristianortiz/auctionEngine/internal/invoicing/infra/repository/postgres"
+	invoicerest "github.com/cristianortiz/auctionEngine/internal/invoicing/infra/rest"
+	notifapp "github.com/cristianortiz/auctionEngine/internal/notification/application"
+	notifdomain "github.com/cristianortiz/auctionEngine/internal/notification/domain"
+	"github.com/cristianortiz/auctionEngine/internal/notification/infra/listener"
+	"github.com/cristianortiz/auctionEngine/internal/notification/infra/recipients"
+	notifpostgres "github.com/cristianortiz/auctionEngine/internal/notification/infra/repository/postgres"
+	"github.com/cristianortiz/auctionEngine/internal/notification/infra/sender"
+	orgapp "github.com/cristianortiz/auctionEngine/internal/organization/application"
+	orgpostgres "github.com/cristianortiz/auctionEngine/internal/organization/infra/repository/postgres"
+	orgrest "github.com/cristianortiz/auctionEngine/internal/organization/infra/rest"
+	sessionapp "github.com/cristianortiz/auctionEngine/internal/session/application"
+	sessionpostgres "github.com/cristianortiz/auctionEngine/internal/session/infra/repository/postgres"
+	sessionrest "github.com/cristianortiz/auctionEngine/internal/session/infra/rest"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	authpostgres "github.com/cristianortiz/auctionEngine/internal/shared/auth/postgres"
+	"github.com/cristianortiz/auctionEngine/internal/shared/config"
 	"github.com/cristianortiz/auctionEngine/internal/shared/db"
 	"github.com/cristianortiz/auctionEngine/internal/shared/db/migrations"
+	"github.com/cristianortiz/auctionEngine/internal/shared/geoip"
 	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
 	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	wspostgres "github.com/cristianortiz/auctionEngine/internal/shared/websocket/postgres"
+	userapp "github.com/cristianortiz/auctionEngine/internal/user/application"
+	userpostgres "github.com/cristianortiz/auctionEngine/internal/user/infra/repository/postgres"
+	userrest "github.com/cristianortiz/auctionEngine/internal/user/infra/rest"
+	webhookapp "github.com/cristianortiz/auctionEngine/internal/webhook/application"
+	webhooklistener "github.com/cristianortiz/auctionEngine/internal/webhook/infra/listener"
+	webhookpostgres "github.com/cristianortiz/auctionEngine/internal/webhook/infra/repository/postgres"
+	webhookrest "github.com/cristianortiz/auctionEngine/internal/webhook/infra/rest"
+	webhooksender "github.com/cristianortiz/auctionEngine/internal/webhook/infra/sender"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
 
 func main() {
 	_ = godotenv.Load()
-	port := os.Getenv("HTTP_PORT")
+	cfg := config.Load()
+	port := cfg.HTTPPort
 	log := logger.GetLogger()
 	defer log.Sync()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal("Migrate command failed", zap.Error(err))
+		}
+		return
+	}
+
+	if cfg.AuthTokenSecret == "" {
+		log.Fatal("AUTH_TOKEN_SECRET must be set")
+	}
+	if len(cfg.WSAllowedOrigins) == 0 {
+		log.Warn("WS_ALLOWED_ORIGINS is empty, WebSocket upgrades are accepted from any origin")
+	}
+	// production serves HTTPS and wss only, with browser origins over HTTPS
+	if cfg.Production() {
+		if !cfg.TLSEnabled() {
+			log.Fatal("TLS_CERT_FILE/TLS_KEY_FILE or TLS_AUTOCERT_DOMAINS must be set in production")
+		}
+		if len(cfg.WSAllowedOrigins) == 0 {
+			log.Fatal("WS_ALLOWED_ORIGINS must be set in production")
+		}
+		for _, origin := range cfg.WSAllowedOrigins {
+			if !strings.HasPrefix(origin, "https://") {
+				log.Fatal("WS_ALLOWED_ORIGINS must only list https origins in production", zap.String("origin", origin))
+			}
+		}
+	}
+
 	log.Info("Starting AuctionEngine server...")
 
 	log.Info("Running database migrations...")
@@ -37,31 +114,453 @@ func main() {
 	defer dbPool.Close()
 	log.Info("DB pool connected")
 
+	// lot state, bid history and search reads go to the read replica when one is configured
+	replicaPool, err := db.NewPostgresReplicaPool(context.Background())
+	if err != nil {
+		log.Fatal("failed to init DB read replica pool", zap.Error(err))
+	}
+	if replicaPool != nil {
+		defer replicaPool.Close()
+		log.Info("DB read replica pool initialized")
+	}
+	readDB := db.NewReadRouter(dbPool, replicaPool)
+
 	//--- Init repositorys ----
 	lotRepo := postgres.NewAuctionLotRepository(dbPool)
 	log.Info("Lot repository initialized")
 	bidRepo := postgres.NewBidRepository(dbPool)
 	log.Info("Lot repository initialized")
+	incrementRepo := postgres.NewBidIncrementRepository(dbPool)
+	log.Info("Bid increment repository initialized")
+	lotEventRepo := postgres.NewLotEventRepository(dbPool)
+	log.Info("Lot event store initialized")
+	mediaRepo := postgres.NewLotMediaRepository(dbPool)
+	bundleRepo := postgres.NewLotBundleRepository(dbPool)
+	categoryRepo := postgres.NewCategoryRepository(dbPool)
+	// lots without a fee schedule and without a stored default one are charged the configured rates
+	feeScheduleRepo := postgres.NewFeeScheduleRepository(dbPool,
+		domain.FlatRateFeeSchedule(cfg.InvoiceBuyerPremiumRate, cfg.InvoiceTaxRate))
+	paddleRepo := postgres.NewPaddleRepository(dbPool)
+	reservationRepo := postgres.NewBidReservationRepository(dbPool)
+	// the use cases start their transactions through it, the domain doesn't depend on pgx
+	transactor := postgres.NewTransactor(dbPool)
+	// lot rules and the scheduler read the time from it, replays and tests swap it
+	clock := domain.SystemClock{}
 
 	//--- Init uses cases
-	placeBidUC := application.NewPlaceBidUseCase(lotRepo, bidRepo, dbPool)
-	getLostStateUC := application.NewGetLotStateUseCase(lotRepo, bidRepo)
-
-	//---Init app service
-	auctionService := application.NewAuctionService(placeBidUC, getLostStateUC)
-
-	//-- Init webSocket hub and runs it in a goroutine
-	hub := websocket.NewHub()
+	// the read-only switch of the maintenances, the bids are rejected while it's on
+	maintenanceMode := application.NewMaintenanceMode(cfg.MaintenanceMode)
+	placeBidUC := application.NewPlaceBidUseCase(lotRepo, bidRepo, incrementRepo, lotEventRepo, paddleRepo,
+		postgres.NewUserLimitRepository(dbPool), reservationRepo, transactor, clock).
+		WithRetryPolicy(application.BidRetryPolicy{
+			MaxRetries: cfg.BidMaxRetries,
+			BaseDelay:  cfg.BidRetryBaseDelay,
+			MaxDelay:   cfg.BidRetryMaxDelay,
+		}).
+		WithMaintenanceMode(maintenanceMode)
+	// deployment specific bid checks, compiled in and enabled by name
+	bidValidators := application.NewBidValidatorRegistry()
+	if err := bidValidators.Register(application.MaxBidAmountValidator(cfg.BidMaxAmount)); err != nil {
+		log.Fatal("Failed to register bid validator", zap.Error(err))
+	}
+	bidValidatorChain, err := bidValidators.Chain(cfg.BidValidators)
+	if err != nil {
+		log.Fatal("Invalid bid validators", zap.Error(err))
+	}
+	placeBidUC.WithBidValidators(bidValidatorChain...)
+	// expressions set by the operators on the lots, evaluated on every bid
+	lotRulesUC := application.NewLotRulesUseCase(lotRepo, postgres.NewLotRulesRepository(dbPool),
+		postgres.NewBidderProfileRepository(dbPool), clock, cfg.LotRulesCacheTTL).
+		WithTerms(postgres.NewTermsAcceptanceRepository(dbPool))
+	if cfg.GeoIPDatabase != "" {
+		resolver, err := geoip.LoadFile(cfg.GeoIPDatabase)
+		if err != nil {
+			log.Fatal("Failed to load GeoIP database", zap.Error(err))
+		}
+		log.Info("GeoIP database loaded", zap.String("path", cfg.GeoIPDatabase), zap.Int("networks", resolver.Len()))
+		lotRulesUC.WithGeoIP(resolver)
+	}
+	// identity verification (KYC) statuses pushed by the providers, checked on the lots requiring verified bidders
+	verificationUC := userapp.NewVerificationUseCase(userpostgres.NewUserVerificationRepository(dbPool), cfg.KYCCacheTTL)
+	if cfg.KYCStub {
+		log.Warn("KYC stub enabled, every bidder is verified")
+		lotRulesUC.WithVerification(userapp.StubVerificationService{})
+	} else {
+		lotRulesUC.WithVerification(verificationUC)
+	}
+	placeBidUC.WithLotRules(lotRulesUC)
+	//-- Init webSocket hub and runs it in a goroutine, the hub also provides lot presence to use cases
+	hub := websocket.NewHubWithConfig(websocket.HubConfig{
+		Shards: cfg.WSHubShards,
+		Heartbeat: websocket.HeartbeatConfig{
+			PingPeriod:   cfg.WSPingPeriod,
+			PongWait:     cfg.WSPongWait,
+			StaleAfter:   cfg.WSStaleAfter,
+			ReapInterval: cfg.WSReapInterval,
+		},
+		Channels: websocket.ChannelConfig{
+			RegisterBuffer:   cfg.WSRegisterBuffer,
+			UnregisterBuffer: cfg.WSUnregisterBuffer,
+			BroadcastBuffer:  cfg.WSBroadcastBuffer,
+			DirectBuffer:     cfg.WSDirectBuffer,
+			InboundBuffer:    cfg.WSInboundBuffer,
+			EnqueueTimeout:   cfg.WSEnqueueTimeout,
+		},
+		Compression: websocket.CompressionConfig{
+			Enabled: cfg.WSCompression,
+			Level:   cfg.WSCompressionLevel,
+			MinSize: cfg.WSCompressionMinSize,
+		},
+		Inbound: wsh.InboundConfig(cfg.WSMaxMalformed),
+	})
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go hub.Run(ctx)
 
+	//-- indicative prices of lots in other currencies, enabled only when display currencies are configured
+	var pricer *application.IndicativePricer
+	if len(cfg.DisplayCurrencies) > 0 {
+		rates, err := fx.NewStaticRateProvider(cfg.FXBaseCurrency, cfg.FXRates)
+		if err != nil {
+			log.Fatal("failed to init FX rates", zap.Error(err))
+		}
+		pricer = application.NewIndicativePricer(rates, cfg.DisplayCurrencies)
+	}
+	go readDB.Run(ctx, 5*time.Second)
+	getLostStateUC := application.NewGetLotStateUseCase(lotRepo.WithReader(readDB), bidRepo.WithReader(readDB), mediaRepo.WithReader(readDB),
+		categoryRepo.WithReader(readDB), paddleRepo.WithReader(readDB), feeScheduleRepo.WithReader(readDB), pricer, hub).
+		WithBidding(incrementRepo, lotRulesUC, bidRepo.WithReader(readDB))
+	listActiveLotsUC := application.NewListActiveLotsUseCase(lotRepo, categoryRepo)
+	// the winners of the lots of a bundle with a bid are invoiced once the bundle is settled
+	finalizeLotUC := application.NewFinalizeLotUseCase(lotRepo, bidRepo, lotEventRepo, transactor, clock).
+		WithBundles(bundleRepo)
+	lotEventsUC := application.NewGetLotEventsUseCase(lotEventRepo)
+	createLotUC := application.NewCreateLotUseCase(lotRepo, transactor, clock)
+	updateLotUC := application.NewUpdateLotUseCase(lotRepo, lotEventRepo, transactor, clock)
+	lifecycleUC := application.NewLotLifecycleUseCase(lotRepo, lotEventRepo, reservationRepo, transactor, clock)
+	searchLotsUC := application.NewSearchLotsUseCase(lotRepo.WithReader(readDB), categoryRepo.WithReader(readDB))
+	voidBidUC := application.NewVoidBidUseCase(lotRepo, bidRepo, lotEventRepo, reservationRepo, transactor, clock)
+
+	//-- lot media, uploads are enabled only when an S3-compatible storage is configured
+	var mediaStorage application.MediaStorage
+	if cfg.MediaS3Endpoint != "" {
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:      cfg.MediaS3Endpoint,
+			Bucket:        cfg.MediaS3Bucket,
+			AccessKey:     cfg.MediaS3AccessKey,
+			SecretKey:     cfg.MediaS3SecretKey,
+			UseSSL:        cfg.MediaS3UseSSL,
+			PublicBaseURL: cfg.MediaPublicBaseURL,
+		})
+		if err != nil {
+			log.Fatal("failed to init media storage", zap.Error(err))
+		}
+		mediaStorage = s3Storage
+	}
+	lotMediaUC := application.NewLotMediaUseCase(lotRepo, mediaRepo, mediaStorage)
+	lotAuctioneersUC := application.NewLotAuctioneersUseCase(lotRepo, postgres.NewLotAuctioneerRepository(dbPool))
+	categoryUC := application.NewCategoryUseCase(categoryRepo, lotRepo)
+	feeScheduleUC := application.NewFeeScheduleUseCase(feeScheduleRepo, lotRepo)
+	userBidsUC := application.NewUserBidsUseCase(bidRepo.WithReader(readDB))
+	lotBidsUC := application.NewLotBidsUseCase(bidRepo.WithReader(readDB), paddleRepo.WithReader(readDB))
+	lotStatsUC := application.NewLotStatsUseCase(lotRepo.WithReader(readDB), bidRepo.WithReader(readDB), clock)
+	endingSoonUC := application.NewEndingSoonUseCase(lotRepo.WithReader(readDB), clock, cfg.EndingSoonWindow)
+	leaderboardUC := application.NewLeaderboardUseCase(lotRepo.WithReader(readDB), bidRepo.WithReader(readDB), application.LeaderboardConfig{
+		Size:          cfg.LeaderboardSize,
+		Anonymization: application.LeaderboardAnonymization(cfg.LeaderboardAnonymization),
+	})
+
+	//-- domain events publisher for downstream consumers (invoicing, analytics, notifications)
+	brokerPublisher, err := messaging.NewEventPublisher(messaging.PublisherConfig{
+		Broker:        cfg.EventBroker,
+		NATSURL:       cfg.NATSURL,
+		KafkaBrokers:  cfg.KafkaBrokers,
+		SubjectPrefix: cfg.EventSubjectPrefix,
+	})
+	if err != nil {
+		log.Fatal("failed to init event publisher", zap.Error(err))
+	}
+
+	//-- invoicing module, invoices lot winners and publishes invoice.created for payment processing
+	invoiceRepo := invoicepostgres.NewInvoiceRepository(dbPool)
+	createInvoiceUC := invoiceapp.NewCreateInvoiceUseCase(invoiceRepo, brokerPublisher, feeScheduleRepo)
+
+	//-- fraud module, flags suspicious bidding patterns for admin review
+	alertRepo := fraudpostgres.NewAlertRepository(dbPool)
+
+	//-- notification module, notifies lot outcomes consuming auction events in-process
+	userRepo := userpostgres.NewUserRepository(dbPool)
+	templates, err := notifapp.NewTemplates()
+	if err != nil {
+		log.Fatal("failed to load notification templates", zap.Error(err))
+	}
+	var senders []notifdomain.Sender
+	if cfg.SMTPHost != "" {
+		senders = append(senders, sender.NewEmailSender(sender.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}))
+	}
+	if cfg.NotificationWebhookURL != "" {
+		senders = append(senders, sender.NewWebhookSender(cfg.NotificationWebhookURL, 10*time.Second))
+	}
+	notifyLotOutcomeUC := notifapp.NewNotifyLotOutcomeUseCase(
+		notifpostgres.NewNotificationRepository(dbPool),
+		recipients.NewUserRecipientResolver(userRepo),
+		templates,
+		notifapp.DefaultRetryPolicy,
+		senders...,
+	)
+
+	//-- webhook module, delivers auction events to the endpoints registered by admins
+	webhookSubRepo := webhookpostgres.NewSubscriptionRepository(dbPool)
+	webhookDeliveryRepo := webhookpostgres.NewDeliveryRepository(dbPool)
+	dispatchWebhookUC := webhookapp.NewDispatchEventUseCase(
+		webhookSubRepo,
+		webhookDeliveryRepo,
+		webhooksender.NewHTTPSender(10*time.Second),
+		webhookapp.DefaultRetryPolicy,
+	)
+
+	// the absentee bids left on the lots before they open are entered as the lots open, then bid again
+	// up to their max for their users when outbid
+	absenteeBidsUC := application.NewAbsenteeBidUseCase(postgres.NewAbsenteeBidRepository(dbPool), lotRepo, clock).
+		WithLotRules(lotRulesUC)
+	absenteeResponder := messaging.NewAbsenteeBidResponder(ctx, absenteeBidsUC)
+
+	eventPublisher := messaging.NewFanoutPublisher(
+		brokerPublisher,
+		listener.NewAuctionEventListener(ctx, notifyLotOutcomeUC),
+		wsh.NewPrivateNotifier(hub), // targeted server_outbid / server_lot_won msgs
+		wsh.NewLotBroadcaster(hub),  // lot-wide server_lot_opened msgs
+		wsh.NewCategoryBroadcaster(hub, lotRepo, categoryRepo),
+		wsh.NewLeaderboardBroadcaster(hub, leaderboardUC),
+		invoicelistener.NewAuctionEventListener(ctx, createInvoiceUC),
+		fraudlistener.NewAuctionEventListener(ctx, fraudapp.NewDetectSuspiciousBiddingUseCase(
+			fraudpostgres.NewBidActivityReader(dbPool),
+			alertRepo,
+			fraudapp.DefaultDetectionConfig,
+		)),
+		webhooklistener.NewAuctionEventListener(ctx, dispatchWebhookUC),
+		absenteeResponder,
+	)
+	defer eventPublisher.Close()
+
+	//---Init app service, lot updates are published to in-process watchers (WS, gRPC)
+	lotUpdates := application.NewLotUpdateBroker()
+	// state changes of a lot are applied one at a time, idle lots release their actor after a minute.
+	// in batched mode the bids piling up on a hot lot are stored together, per bid TXs otherwise
+	bidBatching := application.BidBatching{}
+	if cfg.BidPersistenceMode == config.BidPersistenceBatched {
+		bidBatching = application.BidBatching{Window: cfg.BidBatchWindow, MaxBids: cfg.BidBatchMaxBids}
+	}
+	// in the last seconds of a lot its bids are held briefly and run in the order they were received
+	lotCommands := application.NewLotCommandQueue(cfg.LotCommandQueueSize, time.Minute, bidBatching).
+		WithFairness(application.BidFairness{Window: cfg.BidFairnessWindow, Hold: cfg.BidFairnessHold}, clock)
+	// recorded lots re-run at 1x, 10x..., a finished replay keeps its final state for late viewers
+	replays := application.NewReplayEngine(lotRepo, lotEventRepo, lotUpdates, 10*time.Minute)
+	auctionService := application.NewAuctionService(placeBidUC, getLostStateUC, listActiveLotsUC, finalizeLotUC, lotEventsUC, createLotUC, updateLotUC, lifecycleUC, searchLotsUC, voidBidUC, lotUpdates, eventPublisher, lotCommands, replays)
+	absenteeResponder.WithPlacer(auctionService)
+	// a lot is watched while it has WS clients or streams on this instance
+	lotWatched := func(lotID uuid.UUID) bool {
+		spectators, bidders := hub.CountByRole(lotID.String())
+		return spectators+bidders > 0 || lotUpdates.Watched(lotID)
+	}
+	// with several instances on the same database, the lot changes made by one reach the clients of the others
+	var lotChanges *application.LotChangeSync
+	if cfg.LotChangeNotify {
+		bridge := messaging.NewPGNotifyBridge(dbPool)
+		lotUpdates.WithNotifier(bridge)
+		lotChanges = application.NewLotChangeSync(getLostStateUC, lotUpdates, lotCommands, lotWatched,
+			lotRulesUC.Invalidate, leaderboardUC.Invalidate)
+		go bridge.Listen(ctx, lotChanges.HandleRemoteChange)
+	}
+
 	//-- init handler, remember this came from Ws handler internal/infra/websocket
-	auctionWSHandler := wsh.NewAuctionWSHandler(auctionService, hub)
+	// presence msgs are debounced, at most one per lot every interval
+	presence := wsh.NewPresenceBroadcaster(hub, 2*time.Second)
+	go presence.Run(ctx)
+	// the lobby topic, and the lobby room /ws/auction/_lobby, get the lots closing soon for the homepage tickers
+	lobby := wsh.NewLobbyBroadcaster(hub, endingSoonUC, cfg.EndingSoonInterval)
+	go lobby.Run(ctx)
+	deadLetters := application.NewDeadLetterUseCase(postgres.NewDeadLetterRepository(dbPool))
+	// lot chats are optional, a nil use case disables them
+	var chatUC *application.ChatUseCase
+	if cfg.ChatEnabled {
+		chatUC = application.NewChatUseCase(lotRepo, postgres.NewChatRepository(dbPool), paddleRepo, clock,
+			application.ChatConfig{
+				MaxLength:   cfg.ChatMaxLength,
+				RateLimit:   cfg.ChatRateLimit,
+				RateWindow:  cfg.ChatRateWindow,
+				HistorySize: cfg.ChatHistorySize,
+			},
+			chatfilter.NewWordListFilter(cfg.ChatBlockedWords, cfg.ChatRejectBlocked))
+	}
+	// the clients of a lot flooded with bids get coalesced snapshots instead of every update
+	auctionWSHandler := wsh.NewAuctionWSHandler(auctionService, hub, presence, deadLetters, chatUC, lobby, clock, cfg.WSWorkers, cfg.WSWorkerQueueSize).
+		WithHotLots(wsh.HotLotConfig{BidsPerSecond: cfg.HotLotBidsPerSecond, MaxUpdatesPerSecond: cfg.HotLotMaxUpdatesPerSecond}).
+		WithTerms(lotRulesUC)
 	go auctionWSHandler.ListenForMessages(ctx)
+	go auctionWSHandler.ListenForJoins(ctx)
+	go auctionWSHandler.ListenForDropped(ctx)
+	go auctionWSHandler.ForwardLotUpdates(ctx)
+	// the cached state of the lots nobody watches nor bids on is dropped, it's loaded again when needed
+	var hibernator *application.LotHibernator
+	if cfg.LotHibernateAfter > 0 {
+		hibernator = application.NewLotHibernator(lotUpdates, clock, cfg.LotHibernateAfter, lotWatched,
+			leaderboardUC, lotRulesUC, auctionWSHandler.LotUpdateCache())
+		go hibernator.Run(ctx, time.Minute)
+	}
 	log.Info("WebSocket Hub started.")
 
-	server := httpserver.NewServer(":"+port, hub, ctx)
+	//-- backend timer finalizing ended lots
+	// the bundles of lots bid on as a whole are settled once their lots all closed, the outcome is invoiced
+	bundlesUC := application.NewLotBundleUseCase(bundleRepo, lotRepo, wsh.NewBundleNotifier(hub), clock).
+		WithEvents(eventPublisher, bidRepo)
+	scheduler := application.NewLotScheduler(lotRepo, auctionService, time.Second, clock).
+		WithAbsenteeBids(absenteeBidsUC).
+		WithBundles(bundlesUC)
+	go scheduler.Run(ctx)
+
+	//-- API keys of the machine clients, accepted by the REST and gRPC APIs
+	apiKeysUC := apikeyapp.NewManageAPIKeysUseCase(apikeypostgres.NewAPIKeyRepository(dbPool))
+
+	//-- sessions of the users, their revocations reject the tokens and close the WS connections of every instance
+	revocations := auth.NewRevocationList()
+	tokens := auth.NewTokenService(cfg.AuthTokenSecret).WithRevocations(revocations)
+	sessionsUC := sessionapp.NewSessionsUseCase(sessionpostgres.NewSessionRepository(dbPool), tokens, revocations, hub, sessionapp.SessionsConfig{
+		AccessTTL:  cfg.SessionAccessTTL,
+		RefreshTTL: cfg.SessionRefreshTTL,
+	})
+	go sessionsUC.RunRevocationSync(ctx, cfg.SessionRevocationSync)
+	// data requests of the users, an erasure revokes the tokens of the user before reattributing their data
+	dataRequestsUC := userapp.NewDataRequestsUseCase(userRepo, userpostgres.NewUserDataRepository(dbPool),
+		userpostgres.NewErasureRequestRepository(dbPool), func(ctx context.Context, userID uuid.UUID) error {
+			_, err := sessionsUC.RevokeUser(ctx, userID)
+			return err
+		})
+	// the permissions of the roles are stored in the DB, every instance reloads them
+	go auth.RunPermissionSync(ctx, authpostgres.NewPermissionRepository(dbPool), cfg.PermissionSync)
+
+	//-- gRPC API for internal service-to-service integration
+	if cfg.GRPCPort != "" {
+		grpcServer := auctiongrpc.NewAuctionGRPCServer(auctionService).WithAPIKeys(apiKeysUC.Authenticate, cfg.GRPCRequireAPIKey)
+		go func() {
+			if err := grpcServer.Serve(ctx, ":"+cfg.GRPCPort); err != nil {
+				log.Fatal("gRPC server failed", zap.Error(err))
+			}
+		}()
+	}
+
+	// the WS connections are kept for audit, the reconnections of a client keep its client ID
+	var connectionLog websocket.ConnectionLog
+	if cfg.WSConnectionLog {
+		connectionLog = wspostgres.NewConnectionRepository(dbPool)
+	}
+	server := httpserver.NewServer(":"+port, hub, ctx, httpserver.Config{
+		AllowedOrigins: cfg.WSAllowedOrigins,
+		Tokens:         tokens,
+		TLS: httpserver.TLSConfig{
+			CertFile:         cfg.TLSCertFile,
+			KeyFile:          cfg.TLSKeyFile,
+			AutocertDomains:  cfg.TLSAutocertDomains,
+			AutocertEmail:    cfg.TLSAutocertEmail,
+			AutocertCacheDir: cfg.TLSAutocertCacheDir,
+			RedirectAddr:     cfg.TLSRedirectAddr,
+		},
+		RequireSecure: cfg.Production(),
+		HSTSMaxAge:    cfg.HSTSMaxAge,
+		DrainTimeout:  cfg.ShutdownDrainTimeout,
+		RetryAfter:    cfg.ShutdownRetryAfter,
+		WSCompression: cfg.WSCompression,
+		DemoPage:      cfg.DemoPageEnabled && !cfg.Production(),
+
+		Connections:        connectionLog,
+		ConnectionTokenTTL: cfg.WSConnectionTokenTTL,
+
+		AllowAnonymousSpectators: cfg.WSAllowAnonymousSpectators,
+		// lots and replays of other organizations are not found for tenant scoped tokens
+		LotAccess: func(ctx context.Context, lotID uuid.UUID) (bool, error) {
+			if _, ok := replays.State(ctx, lotID); ok {
+				return true, nil
+			}
+			_, err := lotRepo.GetByID(ctx, lotID)
+			if errors.Is(err, domain.ErrLotNotFound) {
+				return false, nil
+			}
+			return err == nil, err
+		},
+		// the lots restricted to some countries reject the upgrades from the others
+		GeoAccess:          lotRulesUC.CheckConnection,
+		AuctioneerAssigned: lotAuctioneersUC.IsAssigned,
+		APIKeys:            apiKeysUC.Authenticate,
+	})
+	//-- GraphQL API for catalog and history queries
+	graphqlHandler, err := auctiongraphql.NewHandler(server.Context(), auctionService, lotBidsUC, userBidsUC, userRepo)
+	if err != nil {
+		log.Fatal("failed to init GraphQL API", zap.Error(err))
+	}
+	handlers := apiHandlers{
+		lots:         rest.NewLotHandler(auctionService),
+		bids:         rest.NewBidHandler(auctionService),
+		lotStats:     rest.NewLotStatsHandler(lotStatsUC),
+		endingSoon:   rest.NewEndingSoonHandler(endingSoonUC),
+		leaderboards: rest.NewLeaderboardHandler(leaderboardUC),
+		replays:      rest.NewReplayHandler(auctionService),
+		media:        rest.NewMediaHandler(lotMediaUC),
+		categories:   rest.NewCategoryHandler(categoryUC, auctionService),
+		feeSchedules: rest.NewFeeScheduleHandler(feeScheduleUC),
+		auctioneers:  rest.NewAuctioneerHandler(lotAuctioneersUC),
+		lotRules:     rest.NewLotRulesHandler(lotRulesUC),
+		fraudAlerts:  fraudrest.NewAlertHandler(fraudapp.NewReviewAlertsUseCase(alertRepo)),
+		webhooks: webhookrest.NewSubscriptionHandler(
+			webhookapp.NewManageSubscriptionsUseCase(webhookSubRepo, webhookDeliveryRepo),
+			dispatchWebhookUC,
+		),
+		apiKeys:       apikeyrest.NewAPIKeyHandler(apiKeysUC),
+		sessions:      sessionrest.NewSessionHandler(sessionsUC),
+		organizations: orgrest.NewOrganizationHandler(orgapp.NewManageOrganizationsUseCase(orgpostgres.NewOrganizationRepository(dbPool))),
+		invoices:      invoicerest.NewInvoiceHandler(invoiceapp.NewGetInvoicesUseCase(invoiceRepo)),
+		userBids:      rest.NewUserBidsHandler(userBidsUC),
+		absenteeBids:  rest.NewAbsenteeBidHandler(absenteeBidsUC),
+		bundles:       rest.NewLotBundleHandler(bundlesUC),
+		exports:       rest.NewLotExportHandler(application.NewLotExportUseCase(lotRepo.WithReader(readDB), bidRepo.WithReader(readDB), bidRepo.WithReader(readDB), paddleRepo.WithReader(readDB), lotEventRepo)),
+		maintenance:   rest.NewMaintenanceHandler(application.NewMaintenanceUseCase(maintenanceMode, wsh.NewMaintenanceNotifier(hub), clock)),
+		dataRequests:  userrest.NewDataRequestHandler(dataRequestsUC),
+		verifications: userrest.NewVerificationHandler(verificationUC, cfg.KYCWebhookSecret),
+		graphql:       graphqlHandler,
+	}
+	if chatUC != nil {
+		handlers.chat = rest.NewChatHandler(chatUC)
+	}
+	registerRoutes(server, handlers)
+	// counters of the bid TXs retried under contention
+	server.AddDebugStats("bids", func() any { return placeBidUC.RetryStats() })
+	// custom and quick bids placed and rejected, the too low rejections mostly lost a race
+	server.AddDebugStats("bid_types", func() any { return placeBidUC.BidTypeStats() })
+	// calls, rejections and latency of every bid validator
+	server.AddDebugStats("bid_validators", func() any { return placeBidUC.ValidatorStats() })
+	server.AddDebugStats("lot_rules", func() any { return lotRulesUC.Stats() })
+	server.AddDebugStats("bid_fairness", func() any { return lotCommands.FairnessStats() })
+	if lotChanges != nil {
+		server.AddDebugStats("lot_changes", func() any { return lotChanges.Stats() })
+	}
+	server.AddDebugStats("hot_lots", func() any { return auctionWSHandler.HotLotStats() })
+	if hibernator != nil {
+		server.AddDebugStats("lot_hibernation", func() any { return hibernator.Stats() })
+	}
+	server.AddReadinessCheck("database", dbPool.Ping)
+	server.AddReadinessCheck("migrations", func(ctx context.Context) error {
+		return migrations.CheckStatus()
+	})
+	// on SIGTERM the WS clients are sent to other instances, then the bids already read are stored
+	server.AddShutdownHook("websocket", func(ctx context.Context) error {
+		return auctionWSHandler.Drain(ctx, cfg.ShutdownRetryAfter)
+	})
+	server.AddShutdownHook("lot_commands", lotCommands.Drain)
 	if err := server.Start(":" + port); err != nil {
 		log.Fatal("HTTP server failed", zap.Error(err))
 	}
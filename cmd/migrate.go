@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/db/migrations"
+)
+
+const migrateUsage = "usage: auctionengine migrate up | down [steps] | status"
+
+// runMigrateCommand handles `auctionengine migrate ...`, which operates the schema with the embedded
+// migrations and exits without starting the server. down rolls back one migration unless steps is given
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		return migrations.RunMigrations()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid steps %q, %s", args[1], migrateUsage)
+			}
+			steps = n
+		}
+		return migrations.Down(steps)
+	case "status":
+		info, err := migrations.GetInfo()
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], migrateUsage)
+	}
+}
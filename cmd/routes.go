@@ -0,0 +1,82 @@
+package main
+
+import (
+	apikeyrest "github.com/cristianortiz/auctionEngine/internal/apikey/infra/rest"
+	auctiongraphql "github.com/cristianortiz/auctionEngine/internal/auction/infra/graphql"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/rest"
+	fraudrest "github.com/cristianortiz/auctionEngine/internal/fraud/infra/rest"
+	invoicerest "github.com/cristianortiz/auctionEngine/internal/invoicing/infra/rest"
+	orgrest "github.com/cristianortiz/auctionEngine/internal/organization/infra/rest"
+	sessionrest "github.com/cristianortiz/auctionEngine/internal/session/infra/rest"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	userrest "github.com/cristianortiz/auctionEngine/internal/user/infra/rest"
+	webhookrest "github.com/cristianortiz/auctionEngine/internal/webhook/infra/rest"
+)
+
+// apiHandlers are the HTTP handlers of the modules, registered on the server by registerRoutes
+type apiHandlers struct {
+	lots          *rest.LotHandler
+	bids          *rest.BidHandler
+	lotStats      *rest.LotStatsHandler
+	endingSoon    *rest.EndingSoonHandler
+	leaderboards  *rest.LeaderboardHandler
+	replays       *rest.ReplayHandler
+	media         *rest.MediaHandler
+	categories    *rest.CategoryHandler
+	feeSchedules  *rest.FeeScheduleHandler
+	auctioneers   *rest.AuctioneerHandler
+	lotRules      *rest.LotRulesHandler
+	chat          *rest.ChatHandler // nil when the lot chats are disabled
+	fraudAlerts   *fraudrest.AlertHandler
+	webhooks      *webhookrest.SubscriptionHandler
+	apiKeys       *apikeyrest.APIKeyHandler
+	sessions      *sessionrest.SessionHandler
+	organizations *orgrest.OrganizationHandler
+	invoices      *invoicerest.InvoiceHandler
+	userBids      *rest.UserBidsHandler
+	absenteeBids  *rest.AbsenteeBidHandler
+	bundles       *rest.LotBundleHandler
+	maintenance   *rest.MaintenanceHandler
+	exports       *rest.LotExportHandler
+	dataRequests  *userrest.DataRequestHandler
+	verifications *userrest.VerificationHandler
+	graphql       *auctiongraphql.Handler
+}
+
+// registerRoutes registers the module routes on server with their permissions, every REST route must be
+// documented in api/openapi/openapi.yaml (checked by TestRoutesMatchOpenAPISpec)
+func registerRoutes(server *httpserver.Server, h apiHandlers) {
+	manageLots := server.RequirePermission(auth.PermManageLots)
+	manageCatalog := server.RequirePermission(auth.PermManageCatalog)
+	h.lots.RegisterRoutes(server.API(), manageLots, server.OptionalAuth())
+	h.bids.RegisterRoutes(server.API(), manageLots)
+	h.lotStats.RegisterRoutes(server.API())
+	h.endingSoon.RegisterRoutes(server.API(), server.OptionalAuth())
+	h.leaderboards.RegisterRoutes(server.API(), server.OptionalAuth())
+	h.replays.RegisterRoutes(server.API(), manageLots)
+	h.exports.RegisterRoutes(server.API(), manageLots)
+	h.media.RegisterRoutes(server.API(), manageLots)
+	h.categories.RegisterRoutes(server.API(), manageCatalog, manageLots, server.OptionalAuth())
+	h.feeSchedules.RegisterRoutes(server.API(), manageCatalog, manageLots)
+	h.lotRules.RegisterRoutes(server.API(), manageLots)
+	h.auctioneers.RegisterRoutes(server.API(), server.RequirePermission(auth.PermAssignAuctioneers))
+	if h.chat != nil {
+		h.chat.RegisterRoutes(server.API(), server.RequireLotPermission(auth.PermModerateChat, "id"))
+	}
+	h.fraudAlerts.RegisterRoutes(server.API(), server.RequirePermission(auth.PermReviewFraud))
+	h.webhooks.RegisterRoutes(server.API(), server.RequirePermission(auth.PermManageWebhooks))
+	h.apiKeys.RegisterRoutes(server.API(), server.RequirePermission(auth.PermManageAPIKeys))
+	h.sessions.RegisterRoutes(server.API(), server.RequireRoles(), server.RequirePermission(auth.PermManageSessions))
+	h.organizations.RegisterRoutes(server.API(), server.RequirePermission(auth.PermManageOrganizations))
+	h.invoices.RegisterRoutes(server.API(), server.RequireRoles())
+	h.userBids.RegisterRoutes(server.API(), server.RequireRoles())
+	h.absenteeBids.RegisterRoutes(server.API(), server.RequirePermission(auth.PermPlaceBids), server.RequireRoles(), manageLots)
+	h.bundles.RegisterRoutes(server.API(), manageLots, server.RequirePermission(auth.PermPlaceBids), server.OptionalAuth())
+	h.maintenance.RegisterRoutes(server.API(), server.RequirePermission(auth.PermOperatePlatform))
+	h.dataRequests.RegisterRoutes(server.API(), server.RequirePermission(auth.PermManagePersonalData))
+	// signed by the KYC providers instead of authenticated
+	h.verifications.RegisterRoutes(server.API())
+	// GraphQL API for catalog and history queries, lot updates are streamed as subscriptions over /ws/graphql
+	h.graphql.RegisterRoutes(server.API(), server.WS(), server.OptionalAuth())
+}
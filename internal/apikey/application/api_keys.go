@@ -0,0 +1,230 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/apikey/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	// keyPrefix marks the API keys, so they are easy to recognize in configs and secret scanners
+	keyPrefix = "aek_"
+	// touchInterval throttles the writes of the last use of a key
+	touchInterval = time.Minute
+)
+
+// APIKeyDTO is the output DTO of an API key, Key is only set when it's issued or rotated, it can't be read back later
+type APIKeyDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Key        string     `json:"key,omitempty"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssueAPIKeyDTO is the input of ManageAPIKeysUseCase.Issue, a nil UserID gives the key an identity of its own
+type IssueAPIKeyDTO struct {
+	Name      string
+	Scopes    []string
+	UserID    *uuid.UUID
+	CreatedBy uuid.UUID
+}
+
+// ManageAPIKeysUseCase issues, rotates and revokes the API keys of the machine clients and authenticates them
+type ManageAPIKeysUseCase struct {
+	keys domain.APIKeyRepository
+}
+
+// NewManageAPIKeysUseCase creates a new instance of ManageAPIKeysUseCase
+func NewManageAPIKeysUseCase(keys domain.APIKeyRepository) *ManageAPIKeysUseCase {
+	return &ManageAPIKeysUseCase{keys: keys}
+}
+
+// Issue creates a key in the caller organization, the key is returned only in this response
+func (uc *ManageAPIKeysUseCase) Issue(ctx context.Context, cmd IssueAPIKeyDTO) (*APIKeyDTO, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to generate key: %w", err)
+	}
+	userID := uuid.New()
+	if cmd.UserID != nil {
+		userID = *cmd.UserID
+	}
+	scopes := make([]domain.Scope, 0, len(cmd.Scopes))
+	for _, s := range cmd.Scopes {
+		scopes = append(scopes, domain.Scope(strings.TrimSpace(s)))
+	}
+	k, err := domain.NewAPIKey(tenant.OrgIDOrDefault(ctx), userID, cmd.Name, scopes, key, cmd.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: %w", err)
+	}
+	if err := uc.keys.Save(ctx, k); err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to save API key: %w", err)
+	}
+	logger.FromContext(ctx).Info("API key issued",
+		zap.String("apiKeyID", k.ID.String()),
+		zap.String("orgID", k.OrgID.String()),
+		zap.Strings("scopes", cmd.Scopes),
+	)
+	dto := toAPIKeyDTO(k)
+	dto.Key = key
+	return &dto, nil
+}
+
+// List returns a page of the keys of the caller organization, newest first
+func (uc *ManageAPIKeysUseCase) List(ctx context.Context, limit, offset int) ([]APIKeyDTO, error) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	keys, err := uc.keys.List(ctx, min(limit, maxPageLimit), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to list API keys: %w", err)
+	}
+	dtos := make([]APIKeyDTO, 0, len(keys))
+	for _, k := range keys {
+		dtos = append(dtos, toAPIKeyDTO(k))
+	}
+	return dtos, nil
+}
+
+// Get returns a key of the caller organization, without the key itself
+func (uc *ManageAPIKeysUseCase) Get(ctx context.Context, id uuid.UUID) (*APIKeyDTO, error) {
+	k, err := uc.keys.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to get API key %s: %w", id, err)
+	}
+	dto := toAPIKeyDTO(k)
+	return &dto, nil
+}
+
+// Rotate replaces the key keeping its scopes and identity, the previous key stops working right away
+func (uc *ManageAPIKeysUseCase) Rotate(ctx context.Context, id uuid.UUID) (*APIKeyDTO, error) {
+	k, err := uc.keys.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to get API key %s: %w", id, err)
+	}
+	key, err := newKey()
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to generate key: %w", err)
+	}
+	if err := k.Rotate(key, time.Now()); err != nil {
+		return nil, fmt.Errorf("manage API keys use case: %w", err)
+	}
+	if err := uc.keys.Save(ctx, k); err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to save API key %s: %w", id, err)
+	}
+	logger.FromContext(ctx).Info("API key rotated", zap.String("apiKeyID", id.String()))
+	dto := toAPIKeyDTO(k)
+	dto.Key = key
+	return &dto, nil
+}
+
+// Revoke disables the key for good
+func (uc *ManageAPIKeysUseCase) Revoke(ctx context.Context, id uuid.UUID) (*APIKeyDTO, error) {
+	k, err := uc.keys.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to get API key %s: %w", id, err)
+	}
+	if err := k.Revoke(time.Now()); err != nil {
+		return nil, fmt.Errorf("manage API keys use case: %w", err)
+	}
+	if err := uc.keys.Save(ctx, k); err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to save API key %s: %w", id, err)
+	}
+	logger.FromContext(ctx).Info("API key revoked", zap.String("apiKeyID", id.String()))
+	dto := toAPIKeyDTO(k)
+	return &dto, nil
+}
+
+// Authenticate returns the claims of a key, scoped to its organization with the permissions of its
+// scopes. it returns auth.ErrInvalidToken for unknown and revoked keys
+func (uc *ManageAPIKeysUseCase) Authenticate(ctx context.Context, key string) (*auth.Claims, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, fmt.Errorf("%w: malformed API key", auth.ErrInvalidToken)
+	}
+	k, err := uc.keys.GetByHash(ctx, domain.HashKey(key))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown API key", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("manage API keys use case: failed to get API key: %w", err)
+	}
+	if k.Revoked() {
+		return nil, fmt.Errorf("%w: API key %s is revoked", auth.ErrInvalidToken, k.ID)
+	}
+	now := time.Now()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= touchInterval {
+		if err := uc.keys.TouchLastUsed(ctx, k.ID, now); err != nil {
+			logger.FromContext(ctx).Warn("Failed to record the API key use", zap.String("apiKeyID", k.ID.String()), zap.Error(err))
+		}
+	}
+	return claimsOf(k), nil
+}
+
+// claimsOf returns the claims of a key, the role is the one of its highest scope and its
+// permissions the union of the ones of its scopes
+func claimsOf(k *domain.APIKey) *auth.Claims {
+	claims := &auth.Claims{
+		UserID:      k.UserID,
+		Role:        auth.RoleSpectator,
+		OrgID:       k.OrgID,
+		Permissions: []auth.Permission{},
+		APIKeyID:    k.ID,
+	}
+	if k.HasScope(domain.ScopeBid) {
+		claims.Role = auth.RoleBidder
+		claims.Permissions = append(claims.Permissions, auth.PermPlaceBids)
+	}
+	if k.HasScope(domain.ScopeAdmin) {
+		claims.Role = auth.RoleOrgAdmin
+		claims.Permissions = append(claims.Permissions, auth.PermissionsOf(auth.RoleOrgAdmin)...)
+	}
+	return claims
+}
+
+func newKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(b), nil
+}
+
+func toAPIKeyDTO(k *domain.APIKey) APIKeyDTO {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return APIKeyDTO{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		UserID:     k.UserID,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+		RotatedAt:  k.RotatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope is an access level granted to an API key
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // authenticated reads
+	ScopeBid   Scope = "bid"   // bids as the user of the key
+	ScopeAdmin Scope = "admin" // the admin APIs of the organization of the key
+)
+
+// Scopes are the scopes an API key can be issued with
+var Scopes = []Scope{ScopeRead, ScopeBid, ScopeAdmin}
+
+// APIKey authenticates a machine client as an alternative to the JWT tokens, only the hash of the
+// key is stored, the key itself is shown once when it's issued or rotated
+type APIKey struct {
+	ID uuid.UUID
+	// OrgID is the organization the key is scoped to
+	OrgID uuid.UUID
+	// UserID is the identity of the client, the bidder of the bids placed with the key
+	UserID uuid.UUID
+	Name   string
+	// Prefix is the start of the key, so its owner recognizes it in the listings
+	Prefix     string
+	Hash       string
+	Scopes     []Scope
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	RotatedAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey creates a new APIKey for key, it returns ErrInvalidAPIKey if the params are not valid
+func NewAPIKey(orgID, userID uuid.UUID, name string, scopes []Scope, key string, createdBy uuid.UUID) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKey)
+	}
+	unique := make([]Scope, 0, len(scopes))
+	for _, s := range scopes {
+		if !slices.Contains(Scopes, s) {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKey, s)
+		}
+		if !slices.Contains(unique, s) {
+			unique = append(unique, s)
+		}
+	}
+	k := &APIKey{
+		ID:        uuid.New(),
+		OrgID:     orgID,
+		UserID:    userID,
+		Name:      name,
+		Scopes:    unique,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	k.setKey(key)
+	return k, nil
+}
+
+// HasScope reports if the key was issued with the scope
+func (k *APIKey) HasScope(s Scope) bool {
+	return slices.Contains(k.Scopes, s)
+}
+
+// Revoked reports if the key was revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Rotate replaces the key, the previous one stops working right away
+func (k *APIKey) Rotate(key string, at time.Time) error {
+	if k.Revoked() {
+		return ErrAPIKeyRevoked
+	}
+	k.setKey(key)
+	k.RotatedAt = &at
+	return nil
+}
+
+// Revoke disables the key for good
+func (k *APIKey) Revoke(at time.Time) error {
+	if k.Revoked() {
+		return ErrAPIKeyRevoked
+	}
+	k.RevokedAt = &at
+	return nil
+}
+
+func (k *APIKey) setKey(key string) {
+	k.Hash = HashKey(key)
+	k.Prefix = key[:min(len(key), prefixLength)]
+}
+
+// prefixLength is the length of APIKey.Prefix
+const prefixLength = 12
+
+// HashKey returns the hash the keys are stored and looked up by, keys are random so a plain
+// SHA-256 is enough
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyRevoked  = errors.New("API key is revoked")
+)
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository persists the API keys
+type APIKeyRepository interface {
+	// Save inserts the key or updates it if it already exists
+	Save(ctx context.Context, k *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	// GetByHash returns the key with the hash, of any organization, or ErrAPIKeyNotFound
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+	// List returns a page of keys, newest first
+	List(ctx context.Context, limit, offset int) ([]*APIKey, error)
+	// TouchLastUsed records the last use of the key
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
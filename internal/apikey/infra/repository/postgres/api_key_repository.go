@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/apikey/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIKeyRepository implements domain.APIKeyRepository interface
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+const apiKeyColumns = `id, org_id, user_id, name, prefix, key_hash, scopes, created_by, created_at, rotated_at, last_used_at, revoked_at`
+
+// Save inserts the key or updates it if it already exists
+func (r *APIKeyRepository) Save(ctx context.Context, k *domain.APIKey) error {
+	query := `
+        INSERT INTO api_keys (` + apiKeyColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (id) DO UPDATE
+        SET
+            name = EXCLUDED.name,
+            prefix = EXCLUDED.prefix,
+            key_hash = EXCLUDED.key_hash,
+            scopes = EXCLUDED.scopes,
+            rotated_at = EXCLUDED.rotated_at,
+            revoked_at = EXCLUDED.revoked_at;
+    `
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	_, err := r.pool.Exec(ctx, query,
+		k.ID,
+		k.OrgID,
+		k.UserID,
+		k.Name,
+		k.Prefix,
+		k.Hash,
+		scopes,
+		k.CreatedBy,
+		k.CreatedAt,
+		k.RotatedAt,
+		k.LastUsedAt,
+		k.RevokedAt,
+	)
+	return err
+}
+
+// GetByID returns a key, or domain.ErrAPIKeyNotFound, keys of other organizations are not found
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+	return r.get(ctx, query, id, tenant.Filter(ctx))
+}
+
+// GetByHash returns the key with the hash, of any organization, or domain.ErrAPIKeyNotFound
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	return r.get(ctx, query, hash)
+}
+
+// List returns a page of keys, newest first
+func (r *APIKeyRepository) List(ctx context.Context, limit, offset int) ([]*domain.APIKey, error) {
+	query := `
+        SELECT ` + apiKeyColumns + `
+        FROM api_keys
+        WHERE ($3::uuid IS NULL OR org_id = $3)
+        ORDER BY created_at DESC
+        LIMIT $1 OFFSET $2
+    `
+	rows, err := r.pool.Query(ctx, query, limit, offset, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// TouchLastUsed records the last use of the key
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+func (r *APIKeyRepository) get(ctx context.Context, query string, args ...any) (*domain.APIKey, error) {
+	k, err := scanAPIKey(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	k := &domain.APIKey{}
+	var scopes []string
+	err := row.Scan(
+		&k.ID,
+		&k.OrgID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.Hash,
+		&scopes,
+		&k.CreatedBy,
+		&k.CreatedAt,
+		&k.RotatedAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scopes {
+		k.Scopes = append(k.Scopes, domain.Scope(s))
+	}
+	return k, nil
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/apikey/application"
+	"github.com/cristianortiz/auctionEngine/internal/apikey/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler exposes the API keys management API to the admins of an organization
+type APIKeyHandler struct {
+	manageUC *application.ManageAPIKeysUseCase
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler
+func NewAPIKeyHandler(manageUC *application.ManageAPIKeysUseCase) *APIKeyHandler {
+	return &APIKeyHandler{manageUC: manageUC}
+}
+
+// issueAPIKeyRequest is the JSON body of POST /admin/api-keys
+type issueAPIKeyRequest struct {
+	Name   string     `json:"name"`
+	Scopes []string   `json:"scopes"`
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+// RegisterRoutes registers the API key endpoints, all of them guarded by requireManage
+func (h *APIKeyHandler) RegisterRoutes(router fiber.Router, requireManage fiber.Handler) {
+	router.Post("/admin/api-keys", requireManage, h.issueAPIKey)
+	router.Get("/admin/api-keys", requireManage, h.listAPIKeys)
+	router.Get("/admin/api-keys/:id", requireManage, h.getAPIKey)
+	router.Post("/admin/api-keys/:id/rotate", requireManage, h.rotateAPIKey)
+	router.Delete("/admin/api-keys/:id", requireManage, h.revokeAPIKey)
+}
+
+// issueAPIKey returns the new key, the only time it's shown
+func (h *APIKeyHandler) issueAPIKey(c *fiber.Ctx) error {
+	var req issueAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	cmd := application.IssueAPIKeyDTO{Name: req.Name, Scopes: req.Scopes, UserID: req.UserID}
+	if claims := httpserver.ClaimsFrom(c); claims != nil {
+		cmd.CreatedBy = claims.UserID
+	}
+	key, err := h.manageUC.Issue(c.UserContext(), cmd)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// listAPIKeys handles GET /admin/api-keys?limit=&offset=
+func (h *APIKeyHandler) listAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.manageUC.List(c.UserContext(), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(keys)
+}
+
+func (h *APIKeyHandler) getAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid API key ID")
+	}
+	key, err := h.manageUC.Get(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(key)
+}
+
+// rotateAPIKey returns the key with its new value, the previous value stops working right away
+func (h *APIKeyHandler) rotateAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid API key ID")
+	}
+	key, err := h.manageUC.Rotate(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(key)
+}
+
+// revokeAPIKey handles DELETE /admin/api-keys/:id, the key is kept revoked for the audit
+func (h *APIKeyHandler) revokeAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid API key ID")
+	}
+	key, err := h.manageUC.Revoke(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(key)
+}
+
+// toHTTPError maps API key domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAPIKeyRevoked):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,295 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidAbsenteeBid is returned when submitting an absentee bid without a valid max amount
+	ErrInvalidAbsenteeBid = errors.New("invalid absentee bid")
+	// ErrAbsenteeBidProcessed is returned when cancelling an absentee bid already entered or cancelled
+	ErrAbsenteeBidProcessed = errors.New("the absentee bid was already processed")
+)
+
+// SubmitAbsenteeBidDTO is the input of AbsenteeBidUseCase.Submit
+type SubmitAbsenteeBidDTO struct {
+	LotID     uuid.UUID
+	UserID    uuid.UUID
+	MaxAmount float64
+	// Currency is optional, when set it must match the lot currency
+	Currency string
+	// ClientIP is the remote IP of the user, checked against the country lists of the lot and kept for its bids
+	ClientIP string
+}
+
+// AbsenteeBidDTO is an absentee bid with its processing status
+type AbsenteeBidDTO struct {
+	ID        uuid.UUID `json:"id"`
+	LotID     uuid.UUID `json:"lot_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	MaxAmount float64   `json:"max_amount"`
+	Status    string    `json:"status"`
+	// BidID and BidAmount are the last bid placed for the user once entered
+	BidID       *uuid.UUID `json:"bid_id,omitempty"`
+	BidAmount   float64    `json:"bid_amount,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// absenteeBidPlacer places the bids of the absentee bids, implemented by AuctionService
+type absenteeBidPlacer interface {
+	PlaceBid(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error)
+}
+
+// AbsenteeBidUseCase keeps the absentee bids the users leave on the lots not opened yet and enters them
+// when the lots open: in submission order, each one bids the next min bid up to its max amount until
+// none of them can outbid the leader, the way proxy bids compete. once entered they keep standing in the
+// live bidding, Respond bids again for their user when outbid until the max amount is reached
+type AbsenteeBidUseCase struct {
+	repo    domain.AbsenteeBidRepository
+	lotRepo domain.AuctionLotRepository
+	rules   *LotRulesUseCase
+	clock   domain.Clock
+}
+
+// NewAbsenteeBidUseCase creates a new instance of AbsenteeBidUseCase
+func NewAbsenteeBidUseCase(repo domain.AbsenteeBidRepository, lotRepo domain.AuctionLotRepository, clock domain.Clock) *AbsenteeBidUseCase {
+	return &AbsenteeBidUseCase{repo: repo, lotRepo: lotRepo, clock: clock}
+}
+
+// WithLotRules returns the use case checking the country, terms and verification rules of the lots on
+// the absentee bids when they are submitted
+func (uc *AbsenteeBidUseCase) WithLotRules(rules *LotRulesUseCase) *AbsenteeBidUseCase {
+	uc.rules = rules
+	return uc
+}
+
+// Submit stores an absentee bid of the user on a pending or previewed lot, a user has at most one
+// pending absentee bid per lot. a user the rules of the lot wouldn't let bid is rejected with
+// ErrBidNotAllowed here rather than when the lot opens
+func (uc *AbsenteeBidUseCase) Submit(ctx context.Context, cmd SubmitAbsenteeBidDTO) (*AbsenteeBidDTO, error) {
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	if lot.State != domain.StatePending && lot.State != domain.StatePreview {
+		return nil, fmt.Errorf("absentee bid use case: lot %s: %w", cmd.LotID, domain.ErrLotAlreadyStartedOrFinished)
+	}
+	if err := domain.ValidateAmount(cmd.MaxAmount, lot.Currency); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenteeBid, err)
+	}
+	if currency, _ := domain.NormalizeCurrency(cmd.Currency); cmd.Currency != "" && currency != lot.Currency {
+		return nil, fmt.Errorf("%w: %v: lot priced in %s", ErrInvalidAbsenteeBid, domain.ErrCurrencyMismatch, lot.Currency)
+	}
+	// the max amount must leave room for a first bid
+	if (lot.Type == domain.LotTypeReverse && cmd.MaxAmount >= lot.CurrentPrice) ||
+		(lot.Type != domain.LotTypeReverse && cmd.MaxAmount <= lot.CurrentPrice) {
+		return nil, fmt.Errorf("%w: max amount %.2f doesn't beat the starting price %.2f", ErrInvalidAbsenteeBid, cmd.MaxAmount, lot.CurrentPrice)
+	}
+	if err := uc.rules.CheckBidder(ctx, cmd.LotID, cmd.UserID, cmd.ClientIP); err != nil {
+		return nil, fmt.Errorf("absentee bid use case: lot %s: %w", cmd.LotID, err)
+	}
+	bid := &domain.AbsenteeBid{
+		ID:        uuid.New(),
+		LotID:     cmd.LotID,
+		UserID:    cmd.UserID,
+		MaxAmount: cmd.MaxAmount,
+		ClientIP:  cmd.ClientIP,
+		Status:    domain.AbsenteeBidPending,
+	}
+	if err := uc.repo.Create(ctx, bid); err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to store absentee bid on lot %s: %w", cmd.LotID, err)
+	}
+	return newAbsenteeBidDTO(bid), nil
+}
+
+// Get returns an absentee bid of the user, domain.ErrAbsenteeBidNotFound for the bids of other users
+func (uc *AbsenteeBidUseCase) Get(ctx context.Context, id, userID uuid.UUID) (*AbsenteeBidDTO, error) {
+	bid, err := uc.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newAbsenteeBidDTO(bid), nil
+}
+
+// Cancel withdraws a pending absentee bid of the user, ErrAbsenteeBidProcessed once the lot opened
+func (uc *AbsenteeBidUseCase) Cancel(ctx context.Context, id, userID uuid.UUID) (*AbsenteeBidDTO, error) {
+	bid, err := uc.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := uc.repo.Cancel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to cancel absentee bid %s: %w", id, err)
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("absentee bid use case: absentee bid %s: %w", id, ErrAbsenteeBidProcessed)
+	}
+	return uc.Get(ctx, bid.ID, userID)
+}
+
+// ListMine returns a page of the absentee bids of the user, newest first
+func (uc *AbsenteeBidUseCase) ListMine(ctx context.Context, cmd UserBidsPageDTO) ([]*AbsenteeBidDTO, error) {
+	limit, offset := pageBounds(cmd.Limit, cmd.Offset)
+	bids, err := uc.repo.ListByUserID(ctx, cmd.UserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to list absentee bids of user %s: %w", cmd.UserID, err)
+	}
+	return newAbsenteeBidDTOs(bids), nil
+}
+
+// ListByLot returns the absentee bids of the lot in submission order, for the admins
+func (uc *AbsenteeBidUseCase) ListByLot(ctx context.Context, lotID uuid.UUID) ([]*AbsenteeBidDTO, error) {
+	// the lookup keeps the admins of an organization to its lots
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	bids, err := uc.repo.ListByLotID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to list absentee bids of lot %s: %w", lotID, err)
+	}
+	return newAbsenteeBidDTOs(bids), nil
+}
+
+// LotsWithPending returns the active lots whose absentee bids were not entered yet, e.g. opened by hand
+func (uc *AbsenteeBidUseCase) LotsWithPending(ctx context.Context) ([]uuid.UUID, error) {
+	return uc.repo.LotsWithPending(ctx)
+}
+
+// Enter bids the pending absentee bids of an opened lot through placer. the bids claimed by another
+// instance are skipped, and a crash while entering leaves the claimed ones in processing
+func (uc *AbsenteeBidUseCase) Enter(ctx context.Context, lotID uuid.UUID, placer absenteeBidPlacer) error {
+	bids, err := uc.repo.ClaimPending(ctx, lotID)
+	if err != nil {
+		return fmt.Errorf("absentee bid use case: failed to claim absentee bids of lot %s: %w", lotID, err)
+	}
+	if len(bids) == 0 {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+	// every round gives each absentee bid not leading the chance to outbid the leader, a bid out of
+	// its max amount (or rejected for any other reason) leaves the competition
+	errs := make([]error, len(bids))
+	leader := uuid.Nil
+	for placed := true; placed; {
+		placed = false
+		for i, absentee := range bids {
+			if errs[i] != nil || absentee.UserID == leader {
+				continue
+			}
+			bid, err := placer.PlaceBid(ctx, absenteeQuickBid(absentee))
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			absentee.BidID, absentee.BidAmount = &bid.ID, bid.Amount
+			leader, placed = absentee.UserID, true
+		}
+	}
+
+	now := uc.clock.Now()
+	for i, absentee := range bids {
+		absentee.Status, absentee.ProcessedAt = domain.AbsenteeBidEntered, &now
+		if absentee.BidID == nil {
+			absentee.Status, absentee.Error = domain.AbsenteeBidFailed, errs[i].Error()
+		}
+		if err := uc.repo.Update(ctx, absentee); err != nil {
+			log.Error("AbsenteeBidUseCase: failed to save absentee bid outcome",
+				zap.String("absenteeBidID", absentee.ID.String()),
+				zap.String("lotID", lotID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	log.Info("Absentee bids entered",
+		zap.String("lotID", lotID.String()),
+		zap.Int("absenteeBids", len(bids)),
+		zap.String("leader", leader.String()),
+	)
+	return nil
+}
+
+// Respond bids again for userID, outbid on the lot, when the user has an entered absentee bid on it: the
+// next min bid up to its max amount, like the proxy bids. it returns nil without one or when the next min
+// bid is out of the max amount, the user is left outbid then
+func (uc *AbsenteeBidUseCase) Respond(ctx context.Context, lotID, userID uuid.UUID, placer absenteeBidPlacer) error {
+	absentee, err := uc.repo.GetEntered(ctx, lotID, userID)
+	if errors.Is(err, domain.ErrAbsenteeBidNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("absentee bid use case: failed to get absentee bid of user %s on lot %s: %w", userID, lotID, err)
+	}
+	bid, err := placer.PlaceBid(ctx, absenteeQuickBid(absentee))
+	if errors.Is(err, ErrQuickBidLimit) {
+		logger.FromContext(ctx).Info("Absentee bid reached its max amount",
+			zap.String("absenteeBidID", absentee.ID.String()),
+			zap.String("lotID", lotID.String()),
+			zap.Float64("maxAmount", absentee.MaxAmount),
+		)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("absentee bid use case: failed to bid absentee bid %s: %w", absentee.ID, err)
+	}
+	absentee.BidID, absentee.BidAmount = &bid.ID, bid.Amount
+	if err := uc.repo.Update(ctx, absentee); err != nil {
+		return fmt.Errorf("absentee bid use case: failed to save absentee bid %s: %w", absentee.ID, err)
+	}
+	return nil
+}
+
+// absenteeQuickBid is the bid placed for an absentee bid, the next min bid of the lot up to its max amount
+func absenteeQuickBid(absentee *domain.AbsenteeBid) PlaceBidDTO {
+	return PlaceBidDTO{
+		LotID:     absentee.LotID,
+		UserID:    absentee.UserID,
+		ClientIP:  absentee.ClientIP,
+		QuickBid:  true,
+		MaxAmount: absentee.MaxAmount,
+	}
+}
+
+// get returns the absentee bid id of userID
+func (uc *AbsenteeBidUseCase) get(ctx context.Context, id, userID uuid.UUID) (*domain.AbsenteeBid, error) {
+	bid, err := uc.repo.GetByID(ctx, id)
+	if err == nil && bid.UserID != userID {
+		err = domain.ErrAbsenteeBidNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("absentee bid use case: failed to get absentee bid %s: %w", id, err)
+	}
+	return bid, nil
+}
+
+func newAbsenteeBidDTOs(bids []*domain.AbsenteeBid) []*AbsenteeBidDTO {
+	dtos := make([]*AbsenteeBidDTO, 0, len(bids))
+	for _, bid := range bids {
+		dtos = append(dtos, newAbsenteeBidDTO(bid))
+	}
+	return dtos
+}
+
+func newAbsenteeBidDTO(bid *domain.AbsenteeBid) *AbsenteeBidDTO {
+	return &AbsenteeBidDTO{
+		ID:          bid.ID,
+		LotID:       bid.LotID,
+		UserID:      bid.UserID,
+		MaxAmount:   bid.MaxAmount,
+		Status:      string(bid.Status),
+		BidID:       bid.BidID,
+		BidAmount:   bid.BidAmount,
+		Error:       bid.Error,
+		SubmittedAt: bid.SubmittedAt,
+		ProcessedAt: bid.ProcessedAt,
+	}
+}
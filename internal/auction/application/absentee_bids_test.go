@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// fakeAbsenteeBidRepo keeps the absentee bids in memory in submission order
+type fakeAbsenteeBidRepo struct {
+	domain.AbsenteeBidRepository
+	bids []*domain.AbsenteeBid
+}
+
+func (r *fakeAbsenteeBidRepo) ClaimPending(_ context.Context, lotID uuid.UUID) ([]*domain.AbsenteeBid, error) {
+	var claimed []*domain.AbsenteeBid
+	for _, bid := range r.bids {
+		if bid.LotID == lotID && bid.Status == domain.AbsenteeBidPending {
+			bid.Status = domain.AbsenteeBidProcessing
+			copied := *bid
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeAbsenteeBidRepo) GetEntered(_ context.Context, lotID, userID uuid.UUID) (*domain.AbsenteeBid, error) {
+	for _, bid := range r.bids {
+		if bid.LotID == lotID && bid.UserID == userID && bid.Status == domain.AbsenteeBidEntered {
+			copied := *bid
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAbsenteeBidNotFound
+}
+
+func (r *fakeAbsenteeBidRepo) Update(_ context.Context, bid *domain.AbsenteeBid) error {
+	for i := range r.bids {
+		if r.bids[i].ID == bid.ID {
+			r.bids[i] = bid
+			return nil
+		}
+	}
+	return domain.ErrAbsenteeBidNotFound
+}
+
+// fakeQuickBidPlacer places the quick bids on a lot with a fixed increment of 10
+type fakeQuickBidPlacer struct {
+	price float64
+	bids  []float64
+}
+
+func (p *fakeQuickBidPlacer) PlaceBid(_ context.Context, cmd PlaceBidDTO) (*domain.Bid, error) {
+	next := p.price + 10
+	if next > cmd.MaxAmount {
+		return nil, ErrQuickBidLimit
+	}
+	p.price = next
+	p.bids = append(p.bids, next)
+	return &domain.Bid{ID: uuid.New(), LotID: cmd.LotID, UserID: cmd.UserID, Amount: next}, nil
+}
+
+// TestAbsenteeBidsEnter checks the absentee bids of a lot compete as proxy bids in submission order: the
+// highest max amount leads one increment above the runner up, a max amount never outbidding fails
+func TestAbsenteeBidsEnter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lotID := uuid.New()
+	absentee := func(maxAmount float64) *domain.AbsenteeBid {
+		return &domain.AbsenteeBid{ID: uuid.New(), LotID: lotID, UserID: uuid.New(), MaxAmount: maxAmount, Status: domain.AbsenteeBidPending}
+	}
+	first, second, third := absentee(150), absentee(200), absentee(120)
+	repo := &fakeAbsenteeBidRepo{bids: []*domain.AbsenteeBid{first, second, third}}
+	uc := NewAbsenteeBidUseCase(repo, nil, domain.NewManualClock(now))
+	placer := &fakeQuickBidPlacer{price: 100}
+
+	if err := uc.Enter(context.Background(), lotID, placer); err != nil {
+		t.Fatalf("Enter() error = %v", err)
+	}
+	if want := []float64{110, 120, 130, 140, 150, 160}; !slices.Equal(placer.bids, want) {
+		t.Fatalf("bids = %v, want %v", placer.bids, want)
+	}
+	results := repo.bids
+	if results[0].Status != domain.AbsenteeBidEntered || results[0].BidAmount != 150 {
+		t.Errorf("first absentee bid = %s at %v, want entered at 150", results[0].Status, results[0].BidAmount)
+	}
+	if results[1].Status != domain.AbsenteeBidEntered || results[1].BidAmount != 160 {
+		t.Errorf("second absentee bid = %s at %v, want entered leading at 160", results[1].Status, results[1].BidAmount)
+	}
+	if results[2].Status != domain.AbsenteeBidFailed || results[2].Error == "" || results[2].ProcessedAt == nil {
+		t.Errorf("third absentee bid = %+v, want failed with its error", results[2])
+	}
+
+	// the bids are only entered once
+	placer.bids = nil
+	if err := uc.Enter(context.Background(), lotID, placer); err != nil || len(placer.bids) != 0 {
+		t.Fatalf("second Enter() placed %v, %v, want nothing", placer.bids, err)
+	}
+}
+
+// TestAbsenteeBidsRespond checks an entered absentee bid outbid in the live bidding bids again up to its
+// max amount, and the users without one are left outbid
+func TestAbsenteeBidsRespond(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lotID := uuid.New()
+	entered := &domain.AbsenteeBid{ID: uuid.New(), LotID: lotID, UserID: uuid.New(), MaxAmount: 200, Status: domain.AbsenteeBidEntered}
+	repo := &fakeAbsenteeBidRepo{bids: []*domain.AbsenteeBid{entered}}
+	uc := NewAbsenteeBidUseCase(repo, nil, domain.NewManualClock(now))
+	placer := &fakeQuickBidPlacer{price: 170}
+	ctx := context.Background()
+
+	if err := uc.Respond(ctx, lotID, entered.UserID, placer); err != nil {
+		t.Fatalf("Respond() error = %v", err)
+	}
+	if !slices.Equal(placer.bids, []float64{180}) || repo.bids[0].BidAmount != 180 || repo.bids[0].BidID == nil {
+		t.Fatalf("bids = %v, absentee bid at %v, want a bid at 180 saved on the absentee bid", placer.bids, repo.bids[0].BidAmount)
+	}
+
+	// the next min bid is over the max amount
+	placer.price, placer.bids = 195, nil
+	if err := uc.Respond(ctx, lotID, entered.UserID, placer); err != nil || len(placer.bids) != 0 {
+		t.Fatalf("Respond() over the max amount placed %v, %v, want nothing", placer.bids, err)
+	}
+	if err := uc.Respond(ctx, lotID, uuid.New(), placer); err != nil || len(placer.bids) != 0 {
+		t.Fatalf("Respond() without absentee bid placed %v, %v, want nothing", placer.bids, err)
+	}
+}
+
+// absenteeLotRepo returns its lot, the other methods are not used by the absentee bids
+type absenteeLotRepo struct {
+	domain.AuctionLotRepository
+	lot *domain.AuctionLot
+}
+
+func (r *absenteeLotRepo) GetByID(context.Context, uuid.UUID) (*domain.AuctionLot, error) {
+	return r.lot, nil
+}
+
+// TestAbsenteeBidsSubmit checks the absentee bids are only taken on the lots not opened yet with a max
+// amount above the starting price
+func TestAbsenteeBidsSubmit(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := domain.NewAuctionLot(uuid.New(), "lot", "", 100, now.Add(time.Hour), 0)
+	uc := NewAbsenteeBidUseCase(nil, &absenteeLotRepo{lot: lot}, domain.NewManualClock(now))
+	ctx := context.Background()
+
+	if _, err := uc.Submit(ctx, SubmitAbsenteeBidDTO{LotID: lot.ID, UserID: uuid.New(), MaxAmount: 100}); !errors.Is(err, ErrInvalidAbsenteeBid) {
+		t.Errorf("max amount at the starting price: err = %v, want ErrInvalidAbsenteeBid", err)
+	}
+	if _, err := uc.Submit(ctx, SubmitAbsenteeBidDTO{LotID: lot.ID, UserID: uuid.New(), MaxAmount: 150.123}); !errors.Is(err, ErrInvalidAbsenteeBid) {
+		t.Errorf("max amount with too many decimals: err = %v, want ErrInvalidAbsenteeBid", err)
+	}
+	lot.State = domain.StateActive
+	if _, err := uc.Submit(ctx, SubmitAbsenteeBidDTO{LotID: lot.ID, UserID: uuid.New(), MaxAmount: 150}); !errors.Is(err, domain.ErrLotAlreadyStartedOrFinished) {
+		t.Errorf("absentee bid on an active lot: err = %v, want ErrLotAlreadyStartedOrFinished", err)
+	}
+}
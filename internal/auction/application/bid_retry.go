@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// BidRetryPolicy configures the retries of the bid transactions aborted under contention (a lot saved
+// by another instance since it was read, serialization failures, deadlocks), see domain.Transactor.Retryable
+type BidRetryPolicy struct {
+	MaxRetries int           // 0 disables the retries
+	BaseDelay  time.Duration // delay before the 1st retry, doubled on every retry
+	MaxDelay   time.Duration
+}
+
+// DefaultBidRetryPolicy retries 3 times starting at 10ms up to 200ms
+var DefaultBidRetryPolicy = BidRetryPolicy{MaxRetries: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+
+// backoff returns the delay before retry (1 based), exponential with full jitter so the conflicting
+// transactions don't collide again
+func (p BidRetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay << (retry - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+// BidRetryStats counts the retried bid transactions since start
+type BidRetryStats struct {
+	// Retries is the number of transactions run again
+	Retries int64 `json:"retries"`
+	// Recovered is the number of bids (or batches) stored after at least one retry
+	Recovered int64 `json:"recovered"`
+	// Exhausted is the number of bids (or batches) failed after the last retry
+	Exhausted int64 `json:"exhausted"`
+}
+
+// bidRetryCounters are the live BidRetryStats
+type bidRetryCounters struct {
+	retries   atomic.Int64
+	recovered atomic.Int64
+	exhausted atomic.Int64
+}
+
+// WithRetryPolicy returns the use case retrying its transactions with policy
+func (uc *PlaceBidUseCase) WithRetryPolicy(policy BidRetryPolicy) *PlaceBidUseCase {
+	uc.retry = policy
+	return uc
+}
+
+// RetryStats returns the counters of the retried bid transactions
+func (uc *PlaceBidUseCase) RetryStats() BidRetryStats {
+	return BidRetryStats{
+		Retries:   uc.retryCounters.retries.Load(),
+		Recovered: uc.retryCounters.recovered.Load(),
+		Exhausted: uc.retryCounters.exhausted.Load(),
+	}
+}
+
+// withRetries runs attempt until it succeeds, fails with an error the transactor can't retry or runs out
+// of retries. every attempt runs in its own TX and a failed one leaves nothing stored, so running the
+// bid again can't store it twice
+func (uc *PlaceBidUseCase) withRetries(ctx context.Context, name string, attempt func() error) error {
+	err := attempt()
+	for retry := 1; err != nil && uc.transactor.Retryable(err); retry++ {
+		if retry > uc.retry.MaxRetries {
+			uc.retryCounters.exhausted.Add(1)
+			logger.FromContext(ctx).Warn("PlaceBidUseCase: Transaction retries exhausted",
+				zap.String("operation", name),
+				zap.Int("retries", uc.retry.MaxRetries),
+				zap.Error(err),
+			)
+			return err
+		}
+		delay := uc.retry.backoff(retry)
+		logger.FromContext(ctx).Info("PlaceBidUseCase: Retrying conflicting transaction",
+			zap.String("operation", name),
+			zap.Int("retry", retry),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		uc.retryCounters.retries.Add(1)
+		if err = attempt(); err == nil {
+			uc.retryCounters.recovered.Add(1)
+		}
+	}
+	return err
+}
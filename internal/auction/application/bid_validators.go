@@ -0,0 +1,186 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// ErrorCodeBidNotAllowed is the error code of the bids rejected by a BidValidator
+const ErrorCodeBidNotAllowed = "BID_NOT_ALLOWED"
+
+// ErrBidNotAllowed is the parent of the rejections of the BidValidators, see RejectBid. any other error
+// of a validator counts as a failure of the hook and also rejects the bid, the chain fails closed
+var ErrBidNotAllowed = errors.New("bid not allowed")
+
+// RejectBid returns the error a BidValidator rejects a bid with, reason is sent to the bidder
+func RejectBid(reason string) error {
+	return fmt.Errorf("%w: %s", ErrBidNotAllowed, reason)
+}
+
+// BidValidator is a deployment specific check of the bids (KYC, sanctions, max exposure...) run before
+// the lot rules, in the order of the chain. it runs again when the bid TX is retried
+type BidValidator interface {
+	// Name identifies the validator in the config and the stats
+	Name() string
+	// ValidateBid returns nil to pass the bid to the next validator, RejectBid to reject it
+	ValidateBid(ctx context.Context, cmd PlaceBidDTO) error
+}
+
+// NewBidValidator returns a BidValidator calling fn
+func NewBidValidator(name string, fn func(ctx context.Context, cmd PlaceBidDTO) error) BidValidator {
+	return bidValidatorFunc{name: name, fn: fn}
+}
+
+type bidValidatorFunc struct {
+	name string
+	fn   func(ctx context.Context, cmd PlaceBidDTO) error
+}
+
+func (v bidValidatorFunc) Name() string { return v.name }
+
+func (v bidValidatorFunc) ValidateBid(ctx context.Context, cmd PlaceBidDTO) error {
+	return v.fn(ctx, cmd)
+}
+
+// BidValidatorRegistry holds the validators compiled in the binary by name, the deployment enables
+// some of them in order through the config, see Chain
+type BidValidatorRegistry struct {
+	validators map[string]BidValidator
+}
+
+// NewBidValidatorRegistry returns an empty registry
+func NewBidValidatorRegistry() *BidValidatorRegistry {
+	return &BidValidatorRegistry{validators: make(map[string]BidValidator)}
+}
+
+// Register adds v to the registry, it fails when its name is already taken
+func (r *BidValidatorRegistry) Register(v BidValidator) error {
+	if _, ok := r.validators[v.Name()]; ok {
+		return fmt.Errorf("bid validator %q is already registered", v.Name())
+	}
+	r.validators[v.Name()] = v
+	return nil
+}
+
+// Names returns the names of the registered validators sorted
+func (r *BidValidatorRegistry) Names() []string {
+	names := make([]string, 0, len(r.validators))
+	for name := range r.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Chain returns the validators named in order, it fails on an unknown name so a typo in the config
+// doesn't silently disable a check
+func (r *BidValidatorRegistry) Chain(names []string) ([]BidValidator, error) {
+	chain := make([]BidValidator, 0, len(names))
+	for _, name := range names {
+		v, ok := r.validators[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown bid validator %q, registered: %v", name, r.Names())
+		}
+		chain = append(chain, v)
+	}
+	return chain, nil
+}
+
+// MaxBidAmountValidator rejects the bids above max, a guard against mistyped amounts. it's registered as
+// max_bid_amount
+func MaxBidAmountValidator(max float64) BidValidator {
+	return NewBidValidator("max_bid_amount", func(ctx context.Context, cmd PlaceBidDTO) error {
+		if max > 0 && cmd.Amount > max {
+			return RejectBid(fmt.Sprintf("the amount is above the max of %.2f per bid", max))
+		}
+		return nil
+	})
+}
+
+// BidValidatorStats are the counters of a validator of the chain since start
+type BidValidatorStats struct {
+	Name string `json:"name"`
+	// Calls is the number of bids checked, Rejected the ones rejected with RejectBid and Failed the ones
+	// rejected because the validator failed
+	Calls    int64 `json:"calls"`
+	Rejected int64 `json:"rejected"`
+	Failed   int64 `json:"failed"`
+	// AvgLatency is the mean duration of the calls
+	AvgLatency time.Duration `json:"avg_latency_ns"`
+}
+
+// bidValidatorHook is a validator of the chain with its live counters
+type bidValidatorHook struct {
+	validator BidValidator
+	calls     atomic.Int64
+	rejected  atomic.Int64
+	failed    atomic.Int64
+	latency   atomic.Int64 // total nanoseconds
+}
+
+// WithBidValidators returns the use case running validators in order before the lot rules
+func (uc *PlaceBidUseCase) WithBidValidators(validators ...BidValidator) *PlaceBidUseCase {
+	uc.validators = make([]*bidValidatorHook, len(validators))
+	for i, v := range validators {
+		uc.validators[i] = &bidValidatorHook{validator: v}
+	}
+	return uc
+}
+
+// ValidatorStats returns the counters of the validators in the order of the chain
+func (uc *PlaceBidUseCase) ValidatorStats() []BidValidatorStats {
+	stats := make([]BidValidatorStats, len(uc.validators))
+	for i, hook := range uc.validators {
+		stats[i] = BidValidatorStats{
+			Name:     hook.validator.Name(),
+			Calls:    hook.calls.Load(),
+			Rejected: hook.rejected.Load(),
+			Failed:   hook.failed.Load(),
+		}
+		if stats[i].Calls > 0 {
+			stats[i].AvgLatency = time.Duration(hook.latency.Load() / stats[i].Calls)
+		}
+	}
+	return stats
+}
+
+// validateBid runs the chain over cmd stopping at the first validator that doesn't pass it
+func (uc *PlaceBidUseCase) validateBid(ctx context.Context, cmd PlaceBidDTO) error {
+	for _, hook := range uc.validators {
+		start := time.Now()
+		err := hook.validator.ValidateBid(ctx, cmd)
+		hook.latency.Add(int64(time.Since(start)))
+		hook.calls.Add(1)
+		if err == nil {
+			continue
+		}
+		name := hook.validator.Name()
+		if errors.Is(err, ErrBidNotAllowed) {
+			hook.rejected.Add(1)
+			logger.FromContext(ctx).Warn("PlaceBidUseCase: Bid rejected by validator",
+				zap.String("validator", name),
+				zap.String("lotID", cmd.LotID.String()),
+				zap.String("userID", cmd.UserID.String()),
+				zap.Error(err),
+			)
+			return err
+		}
+		hook.failed.Add(1)
+		logger.FromContext(ctx).Error("PlaceBidUseCase: Bid validator failed",
+			zap.String("validator", name),
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		// the cause stays in the log, the bidder only learns the check couldn't be made
+		return fmt.Errorf("%w: the %s check is unavailable", ErrBidNotAllowed, name)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestBidValidatorChain checks the chain stops at the first validator not passing the bid, a failing
+// validator rejects it too, and every call is counted on its validator
+func TestBidValidatorChain(t *testing.T) {
+	failing := NewBidValidator("kyc", func(ctx context.Context, cmd PlaceBidDTO) error {
+		if cmd.Amount > 500 {
+			return errors.New("kyc service unavailable")
+		}
+		return nil
+	})
+	uc := (&PlaceBidUseCase{}).WithBidValidators(MaxBidAmountValidator(1000), failing)
+	ctx := context.Background()
+
+	if err := uc.validateBid(ctx, PlaceBidDTO{Amount: 100}); err != nil {
+		t.Fatalf("bid of 100: %v", err)
+	}
+	err := uc.validateBid(ctx, PlaceBidDTO{Amount: 2000})
+	if BidErrorCode(err) != ErrorCodeBidNotAllowed {
+		t.Fatalf("bid of 2000: err = %v, want a %s rejection", err, ErrorCodeBidNotAllowed)
+	}
+	err = uc.validateBid(ctx, PlaceBidDTO{Amount: 800})
+	if !errors.Is(err, ErrBidNotAllowed) {
+		t.Fatalf("bid of 800: err = %v, want the failing validator to reject it", err)
+	}
+
+	stats := uc.ValidatorStats()
+	want := []BidValidatorStats{
+		{Name: "max_bid_amount", Calls: 3, Rejected: 1},
+		{Name: "kyc", Calls: 2, Failed: 1},
+	}
+	for i := range want {
+		got := stats[i]
+		got.AvgLatency = 0
+		if got != want[i] {
+			t.Errorf("stats[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
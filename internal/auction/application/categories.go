@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// slugInvalidChars matches the runs of chars replaced by "-" when deriving a slug from a name
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CategoryDTO is the output DTO of a category
+type CategoryDTO struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// CreateCategoryDTO is the input for creating a category, an empty Slug is derived from Name
+type CreateCategoryDTO struct {
+	Name     string
+	Slug     string
+	ParentID *uuid.UUID
+}
+
+// CategoryUseCase manages the browsing taxonomy and the lot categorization
+type CategoryUseCase struct {
+	categoryRepo domain.CategoryRepository
+	lotRepo      domain.AuctionLotRepository
+}
+
+// NewCategoryUseCase creates a new instance of CategoryUseCase
+func NewCategoryUseCase(categoryRepo domain.CategoryRepository, lotRepo domain.AuctionLotRepository) *CategoryUseCase {
+	return &CategoryUseCase{
+		categoryRepo: categoryRepo,
+		lotRepo:      lotRepo,
+	}
+}
+
+// Create adds a category, ParentID must reference an existing category
+func (uc *CategoryUseCase) Create(ctx context.Context, cmd CreateCategoryDTO) (*CategoryDTO, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+	slug := cmd.Slug
+	if slug == "" {
+		slug = name
+	}
+	slug = strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(slug), "-"), "-")
+	if slug == "" {
+		return nil, fmt.Errorf("%w: slug must contain letters or digits", ErrInvalidCategory)
+	}
+	if cmd.ParentID != nil {
+		if _, err := uc.categoryRepo.GetByID(ctx, *cmd.ParentID); err != nil {
+			return nil, fmt.Errorf("category use case: failed to get parent category %s: %w", cmd.ParentID, err)
+		}
+	}
+
+	category := &domain.Category{
+		ID:       uuid.New(),
+		Name:     name,
+		Slug:     slug,
+		ParentID: cmd.ParentID,
+	}
+	if err := uc.categoryRepo.Save(ctx, category); err != nil {
+		return nil, fmt.Errorf("category use case: failed to save category %s: %w", slug, err)
+	}
+	logger.FromContext(ctx).Info("CategoryUseCase: category created", zap.String("categoryID", category.ID.String()), zap.String("slug", slug))
+	dto := toCategoryDTO(category)
+	return &dto, nil
+}
+
+// List returns all the categories, clients build the tree through ParentID
+func (uc *CategoryUseCase) List(ctx context.Context) ([]CategoryDTO, error) {
+	categories, err := uc.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("category use case: failed to get categories: %w", err)
+	}
+	return toCategoryDTOs(categories), nil
+}
+
+// SetLotCategories replaces the categories of a lot
+func (uc *CategoryUseCase) SetLotCategories(ctx context.Context, lotID uuid.UUID, categoryIDs []uuid.UUID) ([]CategoryDTO, error) {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("category use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	for _, id := range categoryIDs {
+		if _, err := uc.categoryRepo.GetByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("category use case: failed to get category %s: %w", id, err)
+		}
+	}
+	if err := uc.categoryRepo.SetLotCategories(ctx, lotID, categoryIDs); err != nil {
+		return nil, fmt.Errorf("category use case: failed to set categories of lot %s: %w", lotID, err)
+	}
+	byLot, err := uc.categoryRepo.GetByLotIDs(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("category use case: failed to get categories of lot %s: %w", lotID, err)
+	}
+	return toCategoryDTOs(byLot[lotID]), nil
+}
+
+// attachCategories loads the categories of the lots in a single query and sets them in the DTOs
+func attachCategories(ctx context.Context, categoryRepo domain.CategoryRepository, dtos ...*LotStateDTO) error {
+	lotIDs := make([]uuid.UUID, 0, len(dtos))
+	for _, dto := range dtos {
+		lotIDs = append(lotIDs, dto.LotID)
+	}
+	byLot, err := categoryRepo.GetByLotIDs(ctx, lotIDs...)
+	if err != nil {
+		return err
+	}
+	for _, dto := range dtos {
+		dto.Categories = toCategoryDTOs(byLot[dto.LotID])
+	}
+	return nil
+}
+
+func toCategoryDTO(c *domain.Category) CategoryDTO {
+	return CategoryDTO{
+		ID:       c.ID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+		ParentID: c.ParentID,
+	}
+}
+
+func toCategoryDTOs(categories []*domain.Category) []CategoryDTO {
+	dtos := make([]CategoryDTO, 0, len(categories))
+	for _, c := range categories {
+		dtos = append(dtos, toCategoryDTO(c))
+	}
+	return dtos
+}
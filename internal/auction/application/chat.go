@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// ChatConfig limits the lot chats
+type ChatConfig struct {
+	// MaxLength is the max number of characters of a msg
+	MaxLength int
+	// RateLimit msgs per RateWindow are allowed to each user in each lot
+	RateLimit  int
+	RateWindow time.Duration
+	// HistorySize is the number of recent msgs sent to the clients joining a lot
+	HistorySize int
+}
+
+// ChatMessageDTO is the output DTO of a chat msg, the author is only identified by paddle
+type ChatMessageDTO struct {
+	ID     uuid.UUID `json:"id"`
+	LotID  uuid.UUID `json:"lot_id"`
+	Paddle int       `json:"paddle,omitempty"`
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// SendChatDTO is the input of a chat msg
+type SendChatDTO struct {
+	LotID  uuid.UUID
+	UserID uuid.UUID
+	Text   string
+}
+
+// MuteChatDTO is the input of a chat mute, a zero Duration mutes until unmuted
+type MuteChatDTO struct {
+	LotID    uuid.UUID
+	UserID   uuid.UUID
+	AdminID  uuid.UUID
+	Duration time.Duration
+	Reason   string
+}
+
+// ChatMuteDTO is the output DTO of a chat mute
+type ChatMuteDTO struct {
+	LotID   uuid.UUID  `json:"lot_id"`
+	UserID  uuid.UUID  `json:"user_id"`
+	MutedBy uuid.UUID  `json:"muted_by"`
+	Reason  string     `json:"reason,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+}
+
+// ChatUseCase runs the live chat of the lots: rate limiting, mutes, the filter hooks and persistence
+type ChatUseCase struct {
+	lotRepo    domain.AuctionLotRepository
+	chatRepo   domain.ChatRepository
+	paddleRepo domain.PaddleRepository
+	filters    []domain.ChatFilter
+	clock      domain.Clock
+	cfg        ChatConfig
+	limiter    *chatRateLimiter
+}
+
+// NewChatUseCase creates a new instance of ChatUseCase, the msgs go through filters in order
+func NewChatUseCase(lotRepo domain.AuctionLotRepository,
+	chatRepo domain.ChatRepository,
+	paddleRepo domain.PaddleRepository,
+	clock domain.Clock,
+	cfg ChatConfig,
+	filters ...domain.ChatFilter) *ChatUseCase {
+	return &ChatUseCase{
+		lotRepo:    lotRepo,
+		chatRepo:   chatRepo,
+		paddleRepo: paddleRepo,
+		filters:    filters,
+		clock:      clock,
+		cfg:        cfg,
+		limiter:    newChatRateLimiter(cfg.RateLimit, cfg.RateWindow),
+	}
+}
+
+// Send checks, stores and returns a chat msg, to be broadcast to the lot by the caller. chat is open
+// while the lot is pending, in preview or active
+func (uc *ChatUseCase) Send(ctx context.Context, cmd SendChatDTO) (*ChatMessageDTO, error) {
+	text := strings.TrimSpace(cmd.Text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: text is required", ErrInvalidChatMessage)
+	}
+	if uc.cfg.MaxLength > 0 && utf8.RuneCountInString(text) > uc.cfg.MaxLength {
+		return nil, fmt.Errorf("%w: text is longer than %d characters", ErrInvalidChatMessage, uc.cfg.MaxLength)
+	}
+
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("chat use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	if lot.State.Terminal() {
+		return nil, domain.ErrLotNotActive
+	}
+
+	now := uc.clock.Now()
+	mute, err := uc.chatRepo.GetMute(ctx, cmd.LotID, cmd.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("chat use case: failed to get chat mute of lot %s: %w", cmd.LotID, err)
+	}
+	if mute != nil && mute.Active(now) {
+		return nil, domain.ErrChatMuted
+	}
+	if !uc.limiter.allow(cmd.LotID, cmd.UserID, now) {
+		return nil, ErrChatRateLimited
+	}
+
+	for _, filter := range uc.filters {
+		if text, err = filter.Filter(ctx, cmd.LotID, cmd.UserID, text); err != nil {
+			return nil, err
+		}
+	}
+	paddle, err := uc.paddleRepo.Get(ctx, cmd.LotID, cmd.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("chat use case: failed to get paddle of lot %s: %w", cmd.LotID, err)
+	}
+	msg := &domain.ChatMessage{
+		ID:        uuid.New(),
+		LotID:     cmd.LotID,
+		UserID:    cmd.UserID,
+		Paddle:    paddle,
+		Text:      text,
+		CreatedAt: now,
+	}
+	if err := uc.chatRepo.Save(ctx, msg); err != nil {
+		return nil, fmt.Errorf("chat use case: failed to save chat message of lot %s: %w", cmd.LotID, err)
+	}
+	return newChatMessageDTO(msg), nil
+}
+
+// History returns the recent msgs of the lot chat, oldest first
+func (uc *ChatUseCase) History(ctx context.Context, lotID uuid.UUID) ([]*ChatMessageDTO, error) {
+	msgs, err := uc.chatRepo.GetRecent(ctx, lotID, uc.cfg.HistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("chat use case: failed to get chat history of lot %s: %w", lotID, err)
+	}
+	dtos := make([]*ChatMessageDTO, 0, len(msgs))
+	for _, msg := range msgs {
+		dtos = append(dtos, newChatMessageDTO(msg))
+	}
+	return dtos, nil
+}
+
+// Mute silences a user in the lot chat, replacing any previous mute
+func (uc *ChatUseCase) Mute(ctx context.Context, cmd MuteChatDTO) (*ChatMuteDTO, error) {
+	if cmd.Duration < 0 {
+		return nil, fmt.Errorf("%w: duration must not be negative", ErrInvalidChatMute)
+	}
+	if _, err := uc.lotRepo.GetByID(ctx, cmd.LotID); err != nil {
+		return nil, fmt.Errorf("chat use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	now := uc.clock.Now()
+	mute := &domain.ChatMute{
+		LotID:     cmd.LotID,
+		UserID:    cmd.UserID,
+		MutedBy:   cmd.AdminID,
+		Reason:    strings.TrimSpace(cmd.Reason),
+		CreatedAt: now,
+	}
+	if cmd.Duration > 0 {
+		until := now.Add(cmd.Duration)
+		mute.Until = &until
+	}
+	if err := uc.chatRepo.SaveMute(ctx, mute); err != nil {
+		return nil, fmt.Errorf("chat use case: failed to save chat mute of lot %s: %w", cmd.LotID, err)
+	}
+	return &ChatMuteDTO{LotID: mute.LotID, UserID: mute.UserID, MutedBy: mute.MutedBy, Reason: mute.Reason, Until: mute.Until}, nil
+}
+
+// Unmute lifts the mute of a user in the lot chat
+func (uc *ChatUseCase) Unmute(ctx context.Context, lotID, userID uuid.UUID) error {
+	if err := uc.chatRepo.DeleteMute(ctx, lotID, userID); err != nil {
+		return fmt.Errorf("chat use case: failed to delete chat mute of lot %s: %w", lotID, err)
+	}
+	return nil
+}
+
+func newChatMessageDTO(msg *domain.ChatMessage) *ChatMessageDTO {
+	return &ChatMessageDTO{ID: msg.ID, LotID: msg.LotID, Paddle: msg.Paddle, Text: msg.Text, SentAt: msg.CreatedAt}
+}
+
+// chatRateLimitSweep is the number of tracked users above which the expired windows are swept
+const chatRateLimitSweep = 10000
+
+// chatRateLimiter allows limit msgs per fixed window to each user in each lot, safe for concurrent use
+type chatRateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[chatRateKey]*chatRateWindow
+}
+
+type chatRateKey struct {
+	lotID  uuid.UUID
+	userID uuid.UUID
+}
+
+type chatRateWindow struct {
+	start time.Time
+	count int
+}
+
+// newChatRateLimiter creates a limiter, a limit or window <= 0 disables it
+func newChatRateLimiter(limit int, window time.Duration) *chatRateLimiter {
+	return &chatRateLimiter{limit: limit, window: window, windows: make(map[chatRateKey]*chatRateWindow)}
+}
+
+// allow counts a msg of the user in the lot at now and reports if it is within the limit
+func (l *chatRateLimiter) allow(lotID, userID uuid.UUID, now time.Time) bool {
+	if l.limit <= 0 || l.window <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := chatRateKey{lotID: lotID, userID: userID}
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		if !ok && len(l.windows) >= chatRateLimitSweep {
+			l.sweep(now)
+		}
+		w = &chatRateWindow{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// sweep drops the expired windows, l.mu must be held
+func (l *chatRateLimiter) sweep(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultTimeExtension is used when a lot is created without anti-sniping extension
+const defaultTimeExtension = 2 * time.Minute
+
+// CreateLotDTO is the input of CreateLotUseCase
+type CreateLotDTO struct {
+	Title         string
+	Description   string
+	InitialPrice  float64
+	Currency      string // ISO 4217 code, empty uses domain.DefaultCurrency
+	Type          string // forward or reverse, empty creates a forward lot
+	EndTime       time.Time
+	StartTime     *time.Time // nil creates a pending lot, otherwise the lot is in preview until then
+	TimeExtension time.Duration
+	// closing policy: soft (default), hard or price_threshold, MaxExtension 0 means no extension cap
+	ClosingMode    string
+	MaxExtension   time.Duration
+	PriceThreshold float64 // minimum price change of a bid extending a price_threshold lot
+	// units of the lot, 0 creates a single item lot. the Quantity best bidders of a forward lot win one
+	// each, paying their bid (pay_as_bid, default) or the lowest winning bid (uniform)
+	Quantity int
+	Pricing  string
+}
+
+// CreateLotUseCase creates a new pending auction lot, or a lot in preview when it has a start time
+type CreateLotUseCase struct {
+	lotRepo    domain.AuctionLotRepository
+	transactor domain.Transactor
+	clock      domain.Clock
+}
+
+// NewCreateLotUseCase creates a new instance of CreateLotUseCase
+func NewCreateLotUseCase(lotRepo domain.AuctionLotRepository, transactor domain.Transactor, clock domain.Clock) *CreateLotUseCase {
+	return &CreateLotUseCase{
+		lotRepo:    lotRepo,
+		transactor: transactor,
+		clock:      clock,
+	}
+}
+
+// Execute validates cmd and persists the new lot, it returns ErrInvalidLot on invalid data
+func (uc *CreateLotUseCase) Execute(ctx context.Context, cmd CreateLotDTO) (lot *domain.AuctionLot, err error) {
+	title := strings.TrimSpace(cmd.Title)
+	switch {
+	case title == "":
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidLot)
+	case cmd.InitialPrice <= 0:
+		return nil, fmt.Errorf("%w: initial price must be greater than zero", ErrInvalidLot)
+	case !cmd.EndTime.After(uc.clock.Now()):
+		return nil, fmt.Errorf("%w: end time must be in the future", ErrInvalidLot)
+	case cmd.TimeExtension < 0:
+		return nil, fmt.Errorf("%w: time extension cannot be negative", ErrInvalidLot)
+	case cmd.StartTime != nil && !cmd.StartTime.After(uc.clock.Now()):
+		return nil, fmt.Errorf("%w: start time must be in the future", ErrInvalidLot)
+	case cmd.StartTime != nil && !cmd.StartTime.Before(cmd.EndTime):
+		return nil, fmt.Errorf("%w: start time must be before the end time", ErrInvalidLot)
+	}
+	currency := domain.DefaultCurrency
+	if cmd.Currency != "" {
+		var ok bool
+		if currency, ok = domain.NormalizeCurrency(cmd.Currency); !ok {
+			return nil, fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidLot)
+		}
+	}
+	lotType := domain.LotTypeForward
+	switch domain.LotType(strings.ToLower(cmd.Type)) {
+	case "", domain.LotTypeForward:
+	case domain.LotTypeReverse:
+		lotType = domain.LotTypeReverse
+	default:
+		return nil, fmt.Errorf("%w: type must be forward or reverse", ErrInvalidLot)
+	}
+	closing := domain.ClosingPolicy{
+		Mode:           domain.ClosingMode(strings.ToLower(cmd.ClosingMode)),
+		MaxExtension:   cmd.MaxExtension,
+		PriceThreshold: cmd.PriceThreshold,
+	}
+	if closing.Mode == "" {
+		closing.Mode = domain.ClosingSoft
+	}
+	switch {
+	case !closing.Valid():
+		return nil, fmt.Errorf("%w: closing mode must be soft, hard or price_threshold, with no negative limits", ErrInvalidLot)
+	case closing.Mode == domain.ClosingPriceThreshold && closing.PriceThreshold <= 0:
+		return nil, fmt.Errorf("%w: price_threshold closing needs a price threshold greater than zero", ErrInvalidLot)
+	}
+	quantity := max(cmd.Quantity, 1)
+	pricing := domain.UnitPricing(strings.ToLower(cmd.Pricing))
+	switch {
+	case cmd.Quantity < 0:
+		return nil, fmt.Errorf("%w: quantity cannot be negative", ErrInvalidLot)
+	case quantity > 1 && lotType != domain.LotTypeForward:
+		return nil, fmt.Errorf("%w: only forward lots can have several units", ErrInvalidLot)
+	case pricing == "":
+		pricing = domain.PricingPayAsBid
+	case pricing != domain.PricingPayAsBid && pricing != domain.PricingUniform:
+		return nil, fmt.Errorf("%w: pricing must be pay_as_bid or uniform", ErrInvalidLot)
+	}
+	extension := cmd.TimeExtension
+	if extension == 0 {
+		extension = defaultTimeExtension
+	}
+
+	lot = domain.NewAuctionLot(uuid.New(), title, cmd.Description, cmd.InitialPrice, cmd.EndTime, extension)
+	// lots created by an organization caller belong to it, the unscoped ones (e.g. gRPC without org) to the default one
+	lot.OrgID = tenant.OrgIDOrDefault(ctx)
+	lot.Currency = currency
+	lot.Type = lotType
+	lot.Closing = closing
+	lot.Quantity, lot.Pricing = quantity, pricing
+	if cmd.StartTime != nil {
+		if err := lot.ScheduleStart(*cmd.StartTime); err != nil {
+			return nil, fmt.Errorf("create lot use case: failed to schedule start: %w", err)
+		}
+	}
+
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create lot use case: failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			lot, err = nil, fmt.Errorf("create lot use case: failed to commit transaction: %w", commitErr)
+		}
+	}()
+
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, fmt.Errorf("create lot use case: failed to save auction lot: %w", err)
+	}
+	logger.FromContext(ctx).Info("CreateLotUseCase: lot created",
+		zap.String("lotID", lot.ID.String()),
+		zap.String("orgID", lot.OrgID.String()),
+		zap.String("title", lot.Title),
+		zap.String("currency", lot.Currency),
+		zap.String("type", string(lot.Type)),
+		zap.String("closingMode", string(lot.Closing.Mode)),
+		zap.Int("quantity", lot.Quantity),
+		zap.String("pricing", string(lot.Pricing)),
+		zap.Time("endTime", lot.EndTime),
+		zap.Timep("startTime", lot.StartTime),
+	)
+	return lot, nil
+}
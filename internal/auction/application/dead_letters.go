@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// RecordDeadLetterDTO is the input of DeadLetterUseCase, a client msg dropped before processing
+type RecordDeadLetterDTO struct {
+	LotID       string
+	UserID      string // empty for anonymous connections
+	ClientID    string
+	MessageType string
+	Data        []byte
+	Reason      string
+	DroppedAt   time.Time
+}
+
+// DeadLetterUseCase keeps the client msgs dropped under load
+type DeadLetterUseCase struct {
+	repo domain.DeadLetterRepository
+}
+
+// NewDeadLetterUseCase creates a new instance of DeadLetterUseCase
+func NewDeadLetterUseCase(repo domain.DeadLetterRepository) *DeadLetterUseCase {
+	return &DeadLetterUseCase{repo: repo}
+}
+
+// Record stores a dropped msg and returns the stored dead letter
+func (uc *DeadLetterUseCase) Record(ctx context.Context, cmd RecordDeadLetterDTO) (*domain.DeadLetter, error) {
+	letter := &domain.DeadLetter{
+		ID:          uuid.New(),
+		LotID:       cmd.LotID,
+		ClientID:    cmd.ClientID,
+		MessageType: cmd.MessageType,
+		Payload:     cmd.Data,
+		Reason:      cmd.Reason,
+		DroppedAt:   cmd.DroppedAt,
+	}
+	if userID, err := uuid.Parse(cmd.UserID); err == nil {
+		letter.UserID = &userID
+	}
+	if err := uc.repo.Save(ctx, letter); err != nil {
+		return nil, fmt.Errorf("dead letter use case: failed to save dead letter of lot %s: %w", cmd.LotID, err)
+	}
+	return letter, nil
+}
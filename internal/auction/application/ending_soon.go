@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultEndingSoonWindow is the window of the ending-soon feed when none is configured
+	defaultEndingSoonWindow = 15 * time.Minute
+	// MaxEndingSoonWindow bounds the window of the ending-soon queries
+	MaxEndingSoonWindow = 24 * time.Hour
+)
+
+// EndingSoonLotDTO is a lot of the ending-soon feed
+type EndingSoonLotDTO struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	Title         string    `json:"title"`
+	CurrentPrice  float64   `json:"current_price"`
+	Currency      string    `json:"currency"`
+	EndTime       time.Time `json:"end_time"`
+	EndsInSeconds int       `json:"ends_in_seconds"`
+	// OrgID is the organization of the lot, to scope the feeds broadcast to every organization
+	OrgID uuid.UUID `json:"-"`
+}
+
+// EndingSoonDTO is the active lots closing within a window, the closest end first
+type EndingSoonDTO struct {
+	WithinSeconds int                `json:"within_seconds"`
+	Lots          []EndingSoonLotDTO `json:"lots"`
+	ComputedAt    time.Time          `json:"computed_at"`
+}
+
+// EndingSoonUseCase lists the active lots about to close, for the homepage tickers
+type EndingSoonUseCase struct {
+	lotRepo domain.AuctionLotRepository
+	clock   domain.Clock
+	window  time.Duration
+}
+
+// NewEndingSoonUseCase creates a new instance of EndingSoonUseCase, window is the default one of the queries
+func NewEndingSoonUseCase(lotRepo domain.AuctionLotRepository, clock domain.Clock, window time.Duration) *EndingSoonUseCase {
+	if window <= 0 {
+		window = defaultEndingSoonWindow
+	}
+	return &EndingSoonUseCase{lotRepo: lotRepo, clock: clock, window: min(window, MaxEndingSoonWindow)}
+}
+
+// Window returns the default window of the queries
+func (uc *EndingSoonUseCase) Window() time.Duration {
+	return uc.window
+}
+
+// Execute returns the active lots ending within the window, 0 uses the default one. the lots past their
+// end time waiting to be finalized are left out
+func (uc *EndingSoonUseCase) Execute(ctx context.Context, within time.Duration) (*EndingSoonDTO, error) {
+	if within <= 0 {
+		within = uc.window
+	}
+	within = min(within, MaxEndingSoonWindow)
+	now := uc.clock.Now()
+	lots, err := uc.lotRepo.GetLotsEndingBy(ctx, now.Add(within))
+	if err != nil {
+		return nil, fmt.Errorf("ending soon use case: failed to get lots ending by %s: %w", now.Add(within), err)
+	}
+
+	dto := &EndingSoonDTO{
+		WithinSeconds: int(within.Seconds()),
+		Lots:          make([]EndingSoonLotDTO, 0, len(lots)),
+		ComputedAt:    now,
+	}
+	for _, lot := range lots {
+		if !lot.EndTime.After(now) {
+			continue
+		}
+		dto.Lots = append(dto.Lots, EndingSoonLotDTO{
+			LotID:         lot.ID,
+			Title:         lot.Title,
+			CurrentPrice:  lot.CurrentPrice,
+			Currency:      lot.Currency,
+			EndTime:       lot.EndTime,
+			EndsInSeconds: int(lot.EndTime.Sub(now).Seconds()),
+			OrgID:         lot.OrgID,
+		})
+	}
+	slices.SortFunc(dto.Lots, func(a, b EndingSoonLotDTO) int {
+		return a.EndTime.Compare(b.EndTime)
+	})
+	return dto, nil
+}
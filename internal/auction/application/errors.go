@@ -0,0 +1,67 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/geoip"
+)
+
+// ErrorCodeMaintenance is the error code of the bids rejected by the read-only mode
+const ErrorCodeMaintenance = "MAINTENANCE"
+
+var (
+	// ErrLotNotEnded is returned when finalizing a lot whose end time was extended by a late bid
+	ErrLotNotEnded = errors.New("auction lot has not ended yet")
+	// ErrInvalidLot is returned when creating a lot with missing or inconsistent data
+	ErrInvalidLot = errors.New("invalid auction lot data")
+	// ErrInvalidMedia is returned when attaching media with an unsupported kind or URL
+	ErrInvalidMedia = errors.New("invalid lot media")
+	// ErrInvalidSearch is returned when a lot search has invalid filters
+	ErrInvalidSearch = errors.New("invalid lot search")
+	// ErrInvalidCategory is returned when creating a category with invalid data
+	ErrInvalidCategory = errors.New("invalid category")
+	// ErrMediaUploadDisabled is returned on uploads when no media storage is configured
+	ErrMediaUploadDisabled = errors.New("media uploads are not enabled")
+	// ErrLotBusy is returned when a lot has too many pending commands queued, the client should retry
+	ErrLotBusy = errors.New("auction lot is busy, retry later")
+	// ErrInvalidReplay is returned when starting a replay with an invalid speed or of a lot without events
+	ErrInvalidReplay = errors.New("invalid replay")
+	// ErrReplayNotFound is returned when stopping a replay that doesn't exist or already expired
+	ErrReplayNotFound = errors.New("replay not found")
+	// ErrInvalidChatMessage is returned when sending an empty or too long chat msg
+	ErrInvalidChatMessage = errors.New("invalid chat message")
+	// ErrChatRateLimited is returned when a user sends chat msgs faster than the configured rate
+	ErrChatRateLimited = errors.New("too many chat messages, slow down")
+	// ErrInvalidChatMute is returned when muting with a negative duration
+	ErrInvalidChatMute = errors.New("invalid chat mute")
+	// ErrInvalidMaintenanceNotice is returned when announcing a maintenance without a message, with an
+	// unknown severity or with an inverted downtime window
+	ErrInvalidMaintenanceNotice = errors.New("invalid maintenance notice")
+	// ErrMaintenanceMode is returned when placing a bid while the engine is in read-only mode
+	ErrMaintenanceMode = errors.New("the engine is in maintenance, bids are not accepted")
+)
+
+// BidErrorCode returns the error code of a rejected bid the clients can handle, e.g. MAINTENANCE,
+// GEO_RESTRICTED, TERMS_REQUIRED, VERIFICATION_REQUIRED, QUICK_BID_LIMIT or amount_precision, "" for other errors
+func BidErrorCode(err error) string {
+	if errors.Is(err, ErrMaintenanceMode) {
+		return ErrorCodeMaintenance
+	}
+	if errors.Is(err, ErrTermsRequired) {
+		return ErrorCodeTermsRequired
+	}
+	if errors.Is(err, ErrVerificationRequired) {
+		return ErrorCodeVerificationRequired
+	}
+	if errors.Is(err, geoip.ErrRestricted) {
+		return geoip.ErrorCodeGeoRestricted
+	}
+	if errors.Is(err, ErrBidNotAllowed) {
+		return ErrorCodeBidNotAllowed
+	}
+	if errors.Is(err, ErrQuickBidLimit) {
+		return ErrorCodeQuickBidLimit
+	}
+	return domain.AmountErrorCode(err)
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FeeScheduleDTO is the output DTO of a fee schedule
+type FeeScheduleDTO struct {
+	ID           uuid.UUID        `json:"id"`
+	Name         string           `json:"name"`
+	PremiumTiers []domain.FeeTier `json:"premium_tiers"`
+	FlatFee      float64          `json:"flat_fee"`
+	TaxRate      float64          `json:"tax_rate"`
+	IsDefault    bool             `json:"is_default"`
+}
+
+// CreateFeeScheduleDTO is the input for creating a fee schedule
+type CreateFeeScheduleDTO struct {
+	Name         string
+	PremiumTiers []domain.FeeTier
+	FlatFee      float64
+	TaxRate      float64
+	IsDefault    bool
+}
+
+// FeeEstimateDTO is what the buyer would pay if the lot was won at its current price
+type FeeEstimateDTO struct {
+	ScheduleID     *uuid.UUID `json:"schedule_id,omitempty"` // nil when the configured rates apply
+	BuyerPremium   float64    `json:"buyer_premium"`
+	FlatFee        float64    `json:"flat_fee"`
+	Tax            float64    `json:"tax"`
+	EstimatedTotal float64    `json:"estimated_total"`
+}
+
+// FeeScheduleUseCase manages the fee schedules and their assignment to lots
+type FeeScheduleUseCase struct {
+	feeRepo domain.FeeScheduleRepository
+	lotRepo domain.AuctionLotRepository
+}
+
+// NewFeeScheduleUseCase creates a new instance of FeeScheduleUseCase
+func NewFeeScheduleUseCase(feeRepo domain.FeeScheduleRepository, lotRepo domain.AuctionLotRepository) *FeeScheduleUseCase {
+	return &FeeScheduleUseCase{feeRepo: feeRepo, lotRepo: lotRepo}
+}
+
+// Create adds a fee schedule, with IsDefault it applies to every lot without a schedule
+func (uc *FeeScheduleUseCase) Create(ctx context.Context, cmd CreateFeeScheduleDTO) (*FeeScheduleDTO, error) {
+	schedule := &domain.FeeSchedule{
+		ID:           uuid.New(),
+		Name:         strings.TrimSpace(cmd.Name),
+		PremiumTiers: cmd.PremiumTiers,
+		FlatFee:      cmd.FlatFee,
+		TaxRate:      cmd.TaxRate,
+		IsDefault:    cmd.IsDefault,
+	}
+	if schedule.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidFeeSchedule)
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.feeRepo.Save(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("fee schedule use case: failed to save fee schedule %s: %w", schedule.Name, err)
+	}
+	logger.FromContext(ctx).Info("FeeScheduleUseCase: fee schedule created",
+		zap.String("feeScheduleID", schedule.ID.String()),
+		zap.Bool("isDefault", schedule.IsDefault),
+	)
+	dto := toFeeScheduleDTO(schedule)
+	return &dto, nil
+}
+
+// List returns all the stored fee schedules
+func (uc *FeeScheduleUseCase) List(ctx context.Context) ([]FeeScheduleDTO, error) {
+	schedules, err := uc.feeRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fee schedule use case: failed to get fee schedules: %w", err)
+	}
+	dtos := make([]FeeScheduleDTO, 0, len(schedules))
+	for _, s := range schedules {
+		dtos = append(dtos, toFeeScheduleDTO(s))
+	}
+	return dtos, nil
+}
+
+// SetLotSchedule assigns a schedule to the lot, a nil scheduleID restores the default schedule.
+// it returns the schedule now applied to the lot
+func (uc *FeeScheduleUseCase) SetLotSchedule(ctx context.Context, lotID uuid.UUID, scheduleID *uuid.UUID) (*FeeScheduleDTO, error) {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("fee schedule use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	if scheduleID != nil {
+		if _, err := uc.feeRepo.GetByID(ctx, *scheduleID); err != nil {
+			return nil, fmt.Errorf("fee schedule use case: failed to get fee schedule %s: %w", scheduleID, err)
+		}
+	}
+	if err := uc.feeRepo.SetLotSchedule(ctx, lotID, scheduleID); err != nil {
+		return nil, fmt.Errorf("fee schedule use case: failed to set fee schedule of lot %s: %w", lotID, err)
+	}
+	schedule, err := uc.feeRepo.GetForLot(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("fee schedule use case: failed to get fee schedule of lot %s: %w", lotID, err)
+	}
+	dto := toFeeScheduleDTO(schedule)
+	return &dto, nil
+}
+
+// estimateFees applies the lot fee schedule to its current price
+func estimateFees(ctx context.Context, feeRepo domain.FeeScheduleRepository, dto *LotStateDTO) error {
+	schedule, err := feeRepo.GetForLot(ctx, dto.LotID)
+	if err != nil || schedule == nil {
+		return err
+	}
+	fees := schedule.Apply(dto.CurrentPrice, dto.Currency)
+	dto.Fees = &FeeEstimateDTO{
+		BuyerPremium:   fees.BuyerPremium,
+		FlatFee:        fees.FlatFee,
+		Tax:            fees.Tax,
+		EstimatedTotal: fees.Total,
+	}
+	if schedule.ID != uuid.Nil {
+		dto.Fees.ScheduleID = &schedule.ID
+	}
+	return nil
+}
+
+func toFeeScheduleDTO(s *domain.FeeSchedule) FeeScheduleDTO {
+	return FeeScheduleDTO{
+		ID:           s.ID,
+		Name:         s.Name,
+		PremiumTiers: s.PremiumTiers,
+		FlatFee:      s.FlatFee,
+		TaxRate:      s.TaxRate,
+		IsDefault:    s.IsDefault,
+	}
+}
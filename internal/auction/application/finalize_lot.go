@@ -0,0 +1,194 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FinalizeLotResult is the output of FinalizeLotUseCase
+type FinalizeLotResult struct {
+	Lot *domain.AuctionLot
+	// WinningBid is nil when the lot finished without bids
+	WinningBid *domain.Bid
+	// WinningBids are the bids winning a unit each, best first, only the WinningBid on a single item lot
+	WinningBids []*domain.Bid
+	// OutbidUserIDs are the bidders who didn't win the lot, nor a unit of it
+	OutbidUserIDs []uuid.UUID
+	// Events are the lot events stored with the outcome, with their Seq assigned
+	Events []domain.Event
+}
+
+// FinalizeLotUseCase finishes an active lot whose end time has passed and determines its winner
+type FinalizeLotUseCase struct {
+	lotRepo    domain.AuctionLotRepository
+	bidRepo    domain.BidRepository
+	eventStore domain.LotEventStore
+	transactor domain.Transactor
+	clock      domain.Clock
+	// bundles are the lot bundles, nil without bundles
+	bundles domain.LotBundleRepository
+}
+
+// NewFinalizeLotUseCase creates a new instance of FinalizeLotUseCase
+func NewFinalizeLotUseCase(lotRepo domain.AuctionLotRepository, bidRepo domain.BidRepository, eventStore domain.LotEventStore, transactor domain.Transactor, clock domain.Clock) *FinalizeLotUseCase {
+	return &FinalizeLotUseCase{
+		lotRepo:    lotRepo,
+		bidRepo:    bidRepo,
+		eventStore: eventStore,
+		transactor: transactor,
+		clock:      clock,
+	}
+}
+
+// WithBundles returns the use case marking the winners of the lots in an open bundle with a bid, their
+// invoicing waits for the bundle to be settled
+func (uc *FinalizeLotUseCase) WithBundles(bundles domain.LotBundleRepository) *FinalizeLotUseCase {
+	uc.bundles = bundles
+	return uc
+}
+
+// Execute finishes the lot, it returns domain.ErrLotNotActive if it was already finalized
+// and ErrLotNotEnded if a late bid extended its end time
+func (uc *FinalizeLotUseCase) Execute(ctx context.Context, lotID uuid.UUID) (res *FinalizeLotResult, err error) {
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			res, err = nil, fmt.Errorf("finalize lot use case: failed to commit transaction: %w", commitErr)
+		}
+	}()
+
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	if lot.EndTime.After(uc.clock.Now()) {
+		return nil, ErrLotNotEnded
+	}
+	if err = lot.Finish(); err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to finish lot %s: %w", lotID, err)
+	}
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to save auction lot %s: %w", lotID, err)
+	}
+
+	winningBids, err := uc.winningBids(ctx, lot)
+	if err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to get winning bids of lot %s: %w", lotID, err)
+	}
+	var winningBid *domain.Bid
+	if len(winningBids) > 0 {
+		winningBid = winningBids[0]
+	}
+
+	var outbid []uuid.UUID
+	var bundleID *uuid.UUID
+	if winningBid != nil {
+		if bundleID, err = uc.biddenBundle(ctx, lotID); err != nil {
+			return nil, fmt.Errorf("finalize lot use case: failed to get bundles of lot %s: %w", lotID, err)
+		}
+		bidders, err := uc.bidRepo.GetBidderIDsByLotID(ctx, lotID)
+		if err != nil {
+			return nil, fmt.Errorf("finalize lot use case: failed to get bidders of lot %s: %w", lotID, err)
+		}
+		for _, userID := range bidders {
+			if !slices.ContainsFunc(winningBids, func(bid *domain.Bid) bool { return bid.UserID == userID }) {
+				outbid = append(outbid, userID)
+			}
+		}
+	}
+
+	now := uc.clock.Now()
+	events := []domain.Event{domain.NewEvent(domain.EventLotFinished, lotID, now, domain.LotFinishedPayload{
+		FinalPrice: lot.CurrentPrice,
+		Currency:   lot.Currency,
+		EndTime:    lot.EndTime,
+	})}
+	// the winning bids keep their bidding limit reservation until the lot is settled. every winner of a
+	// multi-unit lot gets its own event, the first one carries the outbid bidders
+	for i, bid := range winningBids {
+		finalPrice := lot.CurrentPrice
+		if lot.MultiUnit() {
+			finalPrice = lot.UnitPrice(bid)
+		}
+		payload := domain.WinnerDeterminedPayload{
+			WinnerID:   bid.UserID,
+			BidID:      bid.ID,
+			Amount:     bid.Amount,
+			FinalPrice: finalPrice,
+			Currency:   lot.Currency,
+			LotTitle:   lot.Title,
+			BundleID:   bundleID,
+		}
+		if i == 0 {
+			payload.OutbidUserIDs = outbid
+		}
+		events = append(events, domain.NewEvent(domain.EventWinnerDetermined, lotID, now, payload))
+	}
+	events, err = uc.eventStore.Append(ctx, tx, lotID, events...)
+	if err != nil {
+		return nil, fmt.Errorf("finalize lot use case: failed to append events for lot %s: %w", lotID, err)
+	}
+
+	logger.FromContext(ctx).Info("FinalizeLotUseCase: lot finalized",
+		zap.String("lotID", lotID.String()),
+		zap.Float64("finalPrice", lot.CurrentPrice),
+		zap.Bool("hasWinner", winningBid != nil),
+		zap.Int("winners", len(winningBids)),
+	)
+	return &FinalizeLotResult{Lot: lot, WinningBid: winningBid, WinningBids: winningBids, OutbidUserIDs: outbid, Events: events}, nil
+}
+
+// biddenBundle returns the open bundle with a bid holding the lot, nil without one. the bundles stop
+// taking bids as their first lot closes, so the bundle bid can still win the lot
+func (uc *FinalizeLotUseCase) biddenBundle(ctx context.Context, lotID uuid.UUID) (*uuid.UUID, error) {
+	if uc.bundles == nil {
+		return nil, nil
+	}
+	ids, err := uc.bundles.OpenWithLots(ctx, []uuid.UUID{lotID})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		bundle, err := uc.bundles.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if bundle.LeadingBid != nil {
+			return &bundle.ID, nil
+		}
+	}
+	return nil, nil
+}
+
+// winningBids returns the bids winning the lot, best first, none when it finished without bids. the
+// Quantity best bidders of a multi-unit lot win a unit each
+func (uc *FinalizeLotUseCase) winningBids(ctx context.Context, lot *domain.AuctionLot) ([]*domain.Bid, error) {
+	if lot.MultiUnit() {
+		standing, err := uc.bidRepo.GetStandingBids(ctx, lot.ID, lot.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		lot.SetStandingBids(standing)
+		return lot.Standing, nil
+	}
+	// the latest bid is always the best one, PlaceBid only accepts bids leading the current price
+	// (higher on forward lots, lower on reverse ones)
+	winningBid, err := uc.bidRepo.GetLatestBidByLotID(ctx, lot.ID)
+	if err != nil || winningBid == nil {
+		return nil, err
+	}
+	return []*domain.Bid{winningBid}, nil
+}
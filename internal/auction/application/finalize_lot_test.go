@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) Commit(context.Context) error   { return nil }
+func (fakeTx) Rollback(context.Context) error { return nil }
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) Begin(context.Context) (domain.Tx, error) { return fakeTx{}, nil }
+func (fakeTransactor) Retryable(error) bool                     { return false }
+
+type finalizeLotRepo struct {
+	domain.AuctionLotRepository
+	lot *domain.AuctionLot
+}
+
+func (r *finalizeLotRepo) GetByID(context.Context, uuid.UUID) (*domain.AuctionLot, error) {
+	return r.lot, nil
+}
+
+func (r *finalizeLotRepo) Save(context.Context, domain.Tx, *domain.AuctionLot) error { return nil }
+
+// standingBidRepo holds the valid bids of a lot, best first
+type standingBidRepo struct {
+	domain.BidRepository
+	bids []*domain.Bid
+}
+
+func (r *standingBidRepo) GetStandingBids(_ context.Context, _ uuid.UUID, limit int) ([]*domain.Bid, error) {
+	var standing []*domain.Bid
+	for _, bid := range r.bids {
+		if len(standing) < limit && !slices.ContainsFunc(standing, func(b *domain.Bid) bool { return b.UserID == bid.UserID }) {
+			standing = append(standing, bid)
+		}
+	}
+	return standing, nil
+}
+
+func (r *standingBidRepo) GetBidderIDsByLotID(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	var bidders []uuid.UUID
+	for _, bid := range r.bids {
+		if !slices.Contains(bidders, bid.UserID) {
+			bidders = append(bidders, bid.UserID)
+		}
+	}
+	return bidders, nil
+}
+
+type fakeEventStore struct {
+	domain.LotEventStore
+}
+
+func (fakeEventStore) Append(_ context.Context, _ domain.Tx, _ uuid.UUID, events ...domain.Event) ([]domain.Event, error) {
+	return events, nil
+}
+
+// TestFinalizeMultiUnitLot checks the best bid of each of the Quantity best bidders wins a unit, at
+// its own bid or at the lowest winning bid, and only the other bidders are outbid
+func TestFinalizeMultiUnitLot(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	lot := domain.NewAuctionLot(uuid.New(), "Wine case", "", 10, now.Add(-time.Minute), time.Minute)
+	lot.State = domain.StateActive
+	lot.Quantity, lot.Pricing = 2, domain.PricingUniform
+	bids := &standingBidRepo{bids: []*domain.Bid{
+		domain.NewBid(uuid.New(), lot.ID, alice, 50, now.Add(-3*time.Minute)),
+		domain.NewBid(uuid.New(), lot.ID, alice, 40, now.Add(-4*time.Minute)),
+		domain.NewBid(uuid.New(), lot.ID, bob, 30, now.Add(-5*time.Minute)),
+		domain.NewBid(uuid.New(), lot.ID, carol, 30, now.Add(-2*time.Minute)),
+	}}
+	uc := NewFinalizeLotUseCase(&finalizeLotRepo{lot: lot}, bids, fakeEventStore{}, fakeTransactor{}, domain.NewManualClock(now))
+
+	res, err := uc.Execute(context.Background(), lot.ID)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(res.WinningBids) != 2 || res.WinningBid != bids.bids[0] || res.WinningBids[1] != bids.bids[2] {
+		t.Fatalf("winning bids = %v, want the best bid of alice and the earliest tie of bob", res.WinningBids)
+	}
+	if !slices.Equal(res.OutbidUserIDs, []uuid.UUID{carol}) {
+		t.Fatalf("outbid = %v, want carol only", res.OutbidUserIDs)
+	}
+
+	var winners []domain.WinnerDeterminedPayload
+	for _, event := range res.Events {
+		if payload, ok := event.Payload.(domain.WinnerDeterminedPayload); ok {
+			winners = append(winners, payload)
+		}
+	}
+	if len(winners) != 2 || winners[0].WinnerID != alice || winners[1].WinnerID != bob {
+		t.Fatalf("winner events = %+v, want one for alice and one for bob", winners)
+	}
+	for _, winner := range winners {
+		if winner.FinalPrice != 30 {
+			t.Fatalf("uniform price of %s = %v, want the lowest winning bid 30", winner.WinnerID, winner.FinalPrice)
+		}
+	}
+	if len(winners[1].OutbidUserIDs) != 0 {
+		t.Fatalf("outbid bidders repeated in the second winner event: %v", winners[1].OutbidUserIDs)
+	}
+
+	lot.State, lot.Pricing = domain.StateActive, domain.PricingPayAsBid
+	if res, err = uc.Execute(context.Background(), lot.ID); err != nil {
+		t.Fatalf("Execute() pay as bid error = %v", err)
+	}
+	if payload := res.Events[1].Payload.(domain.WinnerDeterminedPayload); payload.FinalPrice != 50 {
+		t.Fatalf("pay as bid price of alice = %v, want the bid of alice, 50", payload.FinalPrice)
+	}
+}
+
+// TestFinalizeBundledLot checks the winner of a lot in an open bundle with a bid is marked with the
+// bundle, its invoicing waits for the bundle to be settled
+func TestFinalizeBundledLot(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := domain.NewAuctionLot(uuid.New(), "Chair", "", 10, now.Add(-time.Minute), time.Minute)
+	lot.State = domain.StateActive
+	bids := &standingBidRepo{bids: []*domain.Bid{domain.NewBid(uuid.New(), lot.ID, uuid.New(), 20, now.Add(-2*time.Minute))}}
+	bundle := &domain.LotBundle{ID: uuid.New(), LotIDs: []uuid.UUID{lot.ID, uuid.New()}, State: domain.BundleOpen}
+	bundles := &fakeBundleRepo{bundles: map[uuid.UUID]*domain.LotBundle{bundle.ID: bundle}}
+	uc := NewFinalizeLotUseCase(&finalizeLotRepo{lot: lot}, &latestBidRepo{bids}, fakeEventStore{}, fakeTransactor{}, domain.NewManualClock(now)).
+		WithBundles(bundles)
+
+	winner := func() *uuid.UUID {
+		t.Helper()
+		res, err := uc.Execute(context.Background(), lot.ID)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return res.Events[1].Payload.(domain.WinnerDeterminedPayload).BundleID
+	}
+	if bundleID := winner(); bundleID != nil {
+		t.Fatalf("bundle of the winner = %v, want none while the bundle has no bid", bundleID)
+	}
+	lot.State = domain.StateActive
+	bundle.LeadingBid = &domain.BundleBid{ID: uuid.New(), BundleID: bundle.ID, UserID: uuid.New(), Amount: 50}
+	if bundleID := winner(); bundleID == nil || *bundleID != bundle.ID {
+		t.Fatalf("bundle of the winner = %v, want %s", bundleID, bundle.ID)
+	}
+}
+
+// latestBidRepo returns the best bid of a single item lot
+type latestBidRepo struct {
+	*standingBidRepo
+}
+
+func (r *latestBidRepo) GetLatestBidByLotID(context.Context, uuid.UUID) (*domain.Bid, error) {
+	return r.bids[0], nil
+}
+
+// TestCreateLotUnits checks only forward lots can sell several units, at a known pricing
+func TestCreateLotUnits(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	uc := NewCreateLotUseCase(&finalizeLotRepo{}, fakeTransactor{}, domain.NewManualClock(now))
+	base := CreateLotDTO{Title: "Wine case", InitialPrice: 10, EndTime: now.Add(time.Hour)}
+
+	for name, cmd := range map[string]CreateLotDTO{
+		"negative quantity": {Quantity: -1},
+		"reverse":           {Quantity: 3, Type: "reverse"},
+		"unknown pricing":   {Quantity: 3, Pricing: "dutch"},
+	} {
+		cmd.Title, cmd.InitialPrice, cmd.EndTime = base.Title, base.InitialPrice, base.EndTime
+		if _, err := uc.Execute(context.Background(), cmd); !errors.Is(err, ErrInvalidLot) {
+			t.Errorf("%s: err = %v, want ErrInvalidLot", name, err)
+		}
+	}
+
+	cmd := base
+	cmd.Quantity, cmd.Pricing = 3, "Uniform"
+	lot, err := uc.Execute(context.Background(), cmd)
+	if err != nil || lot.Quantity != 3 || lot.Pricing != domain.PricingUniform {
+		t.Fatalf("multi-unit lot = %+v, %v, want 3 units at uniform pricing", lot, err)
+	}
+	if lot, err = uc.Execute(context.Background(), base); err != nil || lot.Quantity != 1 || lot.Pricing != domain.PricingPayAsBid {
+		t.Fatalf("single item lot = %+v, %v, want 1 unit paying its bid", lot, err)
+	}
+}
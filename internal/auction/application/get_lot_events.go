@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// MaxReplayEvents caps the events returned for a replay, clients further behind
+// should rely on the current lot state instead
+const MaxReplayEvents = 500
+
+// LotEventsDTO is the output of GetLotEventsUseCase
+type LotEventsDTO struct {
+	LotID  uuid.UUID      `json:"lot_id"`
+	Events []domain.Event `json:"events"`
+	// Truncated is true when more than MaxReplayEvents events were missed
+	Truncated bool `json:"truncated"`
+}
+
+// GetLotEventsUseCase retrieves the stored events of a lot after a given sequence
+type GetLotEventsUseCase struct {
+	eventStore domain.LotEventStore
+}
+
+// NewGetLotEventsUseCase creates a new instance of GetLotEventsUseCase
+func NewGetLotEventsUseCase(eventStore domain.LotEventStore) *GetLotEventsUseCase {
+	return &GetLotEventsUseCase{eventStore: eventStore}
+}
+
+func (uc *GetLotEventsUseCase) Execute(ctx context.Context, lotID uuid.UUID, afterSeq int64) (*LotEventsDTO, error) {
+	// one extra event tells if the replay was truncated
+	events, err := uc.eventStore.GetSince(ctx, lotID, afterSeq, MaxReplayEvents+1)
+	if err != nil {
+		return nil, fmt.Errorf("get lot events use case: failed to get events of lot %s: %w", lotID, err)
+	}
+	dto := &LotEventsDTO{LotID: lotID, Events: events}
+	if len(events) > MaxReplayEvents {
+		dto.Events = events[:MaxReplayEvents]
+		dto.Truncated = true
+	}
+	return dto, nil
+}
@@ -15,51 +15,268 @@ type LotStateDTO struct {
 	Description   string     `json:"description"`
 	InitialPrice  float64    `json:"initial_price"`
 	CurrentPrice  float64    `json:"current_price"`
+	Currency      string     `json:"currency"` // ISO 4217 code of the prices and bids of the lot
+	Type          string     `json:"type"`     // forward, or reverse when the lowest bid wins
 	EndTime       time.Time  `json:"end_time"`
+	StartTime     *time.Time `json:"start_time,omitempty"` // scheduled opening of a lot in preview
+	ClosingMode   string     `json:"closing_mode"`
+	MaxEndTime    *time.Time `json:"max_end_time,omitempty"` // latest end time the extensions can reach, nil without cap
 	State         string     `json:"state"`
+	PausedAt      *time.Time `json:"paused_at,omitempty"`
+	Seq           int64      `json:"seq"` // per lot monotonic sequence of the last applied event
 	LastBidAmount float64    `json:"last_bid_amount,omitempty"`
-	LastBidUserID uuid.UUID  `json:"last_bid_user_id,omitempty"`
+	LastBidUserID uuid.UUID  `json:"last_bid_user_id,omitempty"` // only for admins and the bidder, see ForViewer
+	LastBidPaddle int        `json:"last_bid_paddle,omitempty"`
 	LastBidTime   *time.Time `json:"last_bid_time,omitempty"`
+	// live connection counts by role, so UIs can show "123 watching"
+	Connections ConnectionCountsDTO `json:"connections"`
+	// images and videos of the lot in display order
+	Media []LotMediaDTO `json:"media"`
+	// categories the lot is listed in
+	Categories []CategoryDTO `json:"categories"`
+	// buyer's premium, fees and tax if the lot was won at the current price
+	Fees *FeeEstimateDTO `json:"fees,omitempty"`
+	// current price converted to the display currencies, informative only
+	IndicativePrices map[string]float64 `json:"indicative_prices,omitempty"`
+	// what the next bid needs, so the clients can check a bid before sending it
+	Bidding *BiddingTermsDTO `json:"bidding,omitempty"`
+	// units of a multi-unit lot and the bids holding them, nil for a single item
+	Units *LotUnitsDTO `json:"units,omitempty"`
+}
+
+// LotUnitsDTO is the availability of the units of a multi-unit lot, the current price is the one
+// to take a unit
+type LotUnitsDTO struct {
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"` // units no bid holds yet
+	Pricing   string `json:"pricing"`   // pay_as_bid or uniform
+	// StandingBids are the amounts of the bids holding a unit, best first, without their bidders
+	StandingBids []float64 `json:"standing_bids"`
+}
+
+// BiddingTermsDTO are the terms the next bid on a lot must meet and how it may extend the lot
+type BiddingTermsDTO struct {
+	// MinIncrement is the min price change of the next bid, NextMinBid the lowest amount accepted (the
+	// highest one on reverse lots). a min_increment rule reading the bidder may ask more of some bidders
+	MinIncrement float64 `json:"min_increment"`
+	NextMinBid   float64 `json:"next_min_bid"`
+	// ReserveMet is nil when the lot has no reserve price, the price itself is not disclosed
+	ReserveMet *bool `json:"reserve_met,omitempty"`
+	BidCount   int   `json:"bid_count"`
+	// ExtensionSeconds is how much a bid near the end extends the lot, following the closing mode. in
+	// price_threshold mode only the bids moving the price by ExtensionPriceThreshold extend it
+	ExtensionSeconds        int64   `json:"extension_seconds"`
+	ExtensionPriceThreshold float64 `json:"extension_price_threshold,omitempty"`
+}
+
+// ConnectionCountsDTO holds the number of live connections to a lot by role
+type ConnectionCountsDTO struct {
+	Spectators int `json:"spectators"`
+	Bidders    int `json:"bidders"`
+	Total      int `json:"total"`
+}
+
+// LotPresence provides live connection counts of a lot, implemented by the shared WS hub
+type LotPresence interface {
+	CountByRole(lotID string) (spectators, bidders int)
 }
 
 // GetLotStateUseCase retrieves the current state of and auction lot
 type GetLotStateUseCase struct {
-	lotRepo domain.AuctionLotRepository
-	bidRepo domain.BidRepository
+	lotRepo      domain.AuctionLotRepository
+	bidRepo      domain.BidRepository
+	mediaRepo    domain.LotMediaRepository
+	categoryRepo domain.CategoryRepository
+	paddleRepo   domain.PaddleRepository
+	feeRepo      domain.FeeScheduleRepository
+	pricer       *IndicativePricer
+	presence     LotPresence
+	// bidding terms of the states, see WithBidding
+	increments domain.BidIncrementRepository
+	rules      *LotRulesUseCase
+	bidCounter domain.BidCounter
 }
 
 // NewGetLotStateUseCase creates a new instance of GetLotStateUseCase.
-func NewGetLotStateUseCase(lotRepo domain.AuctionLotRepository, bidRepo domain.BidRepository) *GetLotStateUseCase {
+func NewGetLotStateUseCase(lotRepo domain.AuctionLotRepository,
+	bidRepo domain.BidRepository,
+	mediaRepo domain.LotMediaRepository,
+	categoryRepo domain.CategoryRepository,
+	paddleRepo domain.PaddleRepository,
+	feeRepo domain.FeeScheduleRepository,
+	pricer *IndicativePricer,
+	presence LotPresence) *GetLotStateUseCase {
 	return &GetLotStateUseCase{
-		lotRepo: lotRepo,
-		bidRepo: bidRepo,
+		lotRepo:      lotRepo,
+		bidRepo:      bidRepo,
+		mediaRepo:    mediaRepo,
+		categoryRepo: categoryRepo,
+		paddleRepo:   paddleRepo,
+		feeRepo:      feeRepo,
+		pricer:       pricer,
+		presence:     presence,
 	}
 }
 
+// WithBidding returns the use case adding the bidding terms to the states: the min increment from
+// increments and the lot rules, the reserve of the lot rules and the bid count of counter
+func (uc *GetLotStateUseCase) WithBidding(increments domain.BidIncrementRepository, rules *LotRulesUseCase, counter domain.BidCounter) *GetLotStateUseCase {
+	uc.increments = increments
+	uc.rules = rules
+	uc.bidCounter = counter
+	return uc
+}
+
 func (uc *GetLotStateUseCase) Execute(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error) {
 	lot, err := uc.lotRepo.GetByID(ctx, lotID)
 	if err != nil {
 		return nil, err
 	}
 
-	dto := &LotStateDTO{
+	dto := newLotStateDTO(lot)
+
+	// Optionally, get the latest bid for more details
+	bid, err := uc.bidRepo.GetLatestBidByLotID(ctx, lotID)
+	if err == nil && bid != nil {
+		dto.LastBidAmount = bid.Amount
+		dto.LastBidUserID = bid.UserID
+		dto.LastBidTime = &bid.Timestamp
+		if dto.LastBidPaddle, err = uc.paddleRepo.Get(ctx, lotID, bid.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if lot.MultiUnit() {
+		standing, err := uc.bidRepo.GetStandingBids(ctx, lotID, lot.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		lot.SetStandingBids(standing)
+		dto.Units = newLotUnitsDTO(lot)
+	}
+
+	media, err := uc.mediaRepo.GetByLotID(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+	dto.Media = toLotMediaDTOs(media)
+	if err := attachCategories(ctx, uc.categoryRepo, dto); err != nil {
+		return nil, err
+	}
+	if err := estimateFees(ctx, uc.feeRepo, dto); err != nil {
+		return nil, err
+	}
+	uc.pricer.attach(ctx, dto)
+	if uc.increments != nil {
+		if dto.Bidding, err = uc.biddingTerms(ctx, lot); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.presence != nil {
+		spectators, bidders := uc.presence.CountByRole(lotID.String())
+		dto.Connections = ConnectionCountsDTO{
+			Spectators: spectators,
+			Bidders:    bidders,
+			Total:      spectators + bidders,
+		}
+	}
+
+	return dto, nil
+}
+
+// biddingTerms computes the bidding terms of the lot
+func (uc *GetLotStateUseCase) biddingTerms(ctx context.Context, lot *domain.AuctionLot) (*BiddingTermsDTO, error) {
+	increments, err := uc.increments.GetIncrementTable(ctx, lot.ID)
+	if err != nil {
+		return nil, err
+	}
+	increment, reserve, err := uc.rules.BiddingTerms(ctx, lot, increments.IncrementFor(lot.CurrentPrice))
+	if err != nil {
+		return nil, err
+	}
+	terms := &BiddingTermsDTO{
+		MinIncrement:     increment,
+		NextMinBid:       lot.NextMinBid(increment),
+		ExtensionSeconds: int64(lot.TimeExtension.Seconds()),
+	}
+	if lot.Closing.Mode == domain.ClosingHard {
+		terms.ExtensionSeconds = 0
+	}
+	if lot.Closing.Mode == domain.ClosingPriceThreshold {
+		terms.ExtensionPriceThreshold = lot.Closing.PriceThreshold
+	}
+	if reserve > 0 {
+		met := lot.ReserveMet(reserve)
+		terms.ReserveMet = &met
+	}
+	if uc.bidCounter != nil {
+		if terms.BidCount, err = uc.bidCounter.CountBidsByLotID(ctx, lot.ID); err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+// GetBidderPaddle returns the paddle of the user on the lot, 0 if the user never bid on it
+func (uc *GetLotStateUseCase) GetBidderPaddle(ctx context.Context, lotID, userID uuid.UUID) (int, error) {
+	return uc.paddleRepo.Get(ctx, lotID, userID)
+}
+
+// ForViewer returns the state as seen by a user: the real identity of the last bidder is only revealed
+// to admins and to the bidder, everyone else gets the paddle number
+func (s *LotStateDTO) ForViewer(viewerID uuid.UUID, admin bool) *LotStateDTO {
+	if admin || (viewerID != uuid.Nil && viewerID == s.LastBidUserID) {
+		return s
+	}
+	anonymized := *s
+	anonymized.LastBidUserID = uuid.Nil
+	return &anonymized
+}
+
+// newLotStateDTO maps the lot fields to a LotStateDTO, without bid, media or presence details
+func newLotStateDTO(lot *domain.AuctionLot) *LotStateDTO {
+	return &LotStateDTO{
 		LotID:        lot.ID,
 		Title:        lot.Title,
 		Description:  lot.Description,
 		InitialPrice: lot.InitialPrice,
 		CurrentPrice: lot.CurrentPrice,
+		Currency:     lot.Currency,
+		Type:         string(lot.Type),
 		EndTime:      lot.EndTime,
+		StartTime:    lot.StartTime,
+		ClosingMode:  string(lot.Closing.Mode),
+		MaxEndTime:   maxEndTime(lot),
 		State:        string(lot.State),
+		PausedAt:     lot.PausedAt,
+		Seq:          lot.Seq,
 		LastBidTime:  lot.LastBidTime,
 	}
+}
 
-	// Optionally, get the latest bid for more details
-	bid, err := uc.bidRepo.GetLatestBidByLotID(ctx, lotID)
-	if err == nil && bid != nil {
-		dto.LastBidAmount = bid.Amount
-		dto.LastBidUserID = bid.UserID
-		dto.LastBidTime = &bid.Timestamp
+// newLotUnitsDTO maps the units of a multi-unit lot with its standing bids loaded
+func newLotUnitsDTO(lot *domain.AuctionLot) *LotUnitsDTO {
+	units := &LotUnitsDTO{
+		Quantity:     lot.Quantity,
+		Available:    lot.UnitsAvailable(),
+		Pricing:      string(lot.Pricing),
+		StandingBids: make([]float64, 0, len(lot.Standing)),
 	}
+	for _, bid := range lot.Standing {
+		units.StandingBids = append(units.StandingBids, bid.Amount)
+	}
+	return units
+}
 
-	return dto, nil
+// maxEndTime returns the latest end time the closing policy lets the lot reach, nil when it's not capped
+func maxEndTime(lot *domain.AuctionLot) *time.Time {
+	switch {
+	case lot.Closing.Mode == domain.ClosingHard:
+		return &lot.ScheduledEndTime
+	case lot.Closing.MaxExtension > 0:
+		limit := lot.ScheduledEndTime.Add(lot.Closing.MaxExtension)
+		return &limit
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// FXRateProvider returns how many units of the to currency one unit of the from currency buys,
+// implementations live in infra
+type FXRateProvider interface {
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// IndicativePricer converts the current price of the lots to a set of display currencies,
+// the converted prices are informative only, bids are always in the lot currency
+type IndicativePricer struct {
+	rates      FXRateProvider
+	currencies []string
+}
+
+// NewIndicativePricer creates a new instance of IndicativePricer, invalid currency codes are ignored
+func NewIndicativePricer(rates FXRateProvider, currencies []string) *IndicativePricer {
+	valid := make([]string, 0, len(currencies))
+	for _, code := range currencies {
+		if code, ok := domain.NormalizeCurrency(code); ok {
+			valid = append(valid, code)
+		} else {
+			log.Warn("IndicativePricer: ignoring invalid currency code", zap.String("currency", code))
+		}
+	}
+	return &IndicativePricer{rates: rates, currencies: valid}
+}
+
+// attach sets the indicative prices of the lot, a failing rate only skips that currency
+func (p *IndicativePricer) attach(ctx context.Context, dto *LotStateDTO) {
+	if p == nil || len(p.currencies) == 0 {
+		return
+	}
+	prices := make(map[string]float64, len(p.currencies))
+	for _, code := range p.currencies {
+		if code == dto.Currency {
+			continue
+		}
+		rate, err := p.rates.Rate(ctx, dto.Currency, code)
+		if err != nil {
+			logger.FromContext(ctx).Warn("IndicativePricer: failed to get FX rate",
+				zap.String("from", dto.Currency),
+				zap.String("to", code),
+				zap.Error(err),
+			)
+			continue
+		}
+		prices[code] = domain.RoundAmount(dto.CurrentPrice*rate, code)
+	}
+	if len(prices) > 0 {
+		dto.IndicativePrices = prices
+	}
+}
@@ -0,0 +1,284 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultLeaderboardSize is the number of bidders of a leaderboard when LeaderboardConfig.Size is not set
+const defaultLeaderboardSize = 10
+
+// LeaderboardAnonymization is how the leaderboards identify the bidders to the public
+type LeaderboardAnonymization string
+
+const (
+	LeaderboardPaddles LeaderboardAnonymization = "paddles" // entries show the paddle numbers of the bidders
+	LeaderboardMasked  LeaderboardAnonymization = "masked"  // entries only show rank and bid, bidders still see their own paddle
+)
+
+// LeaderboardConfig configures the lot leaderboards
+type LeaderboardConfig struct {
+	Size          int // bidders of each leaderboard
+	Anonymization LeaderboardAnonymization
+}
+
+// LeaderboardEntryDTO is a ranked bidder of a lot
+type LeaderboardEntryDTO struct {
+	Rank    int       `json:"rank"`
+	Paddle  int       `json:"paddle,omitempty"`
+	UserID  uuid.UUID `json:"user_id,omitzero"` // only for admins and the bidder, see ForViewer
+	BestBid float64   `json:"best_bid"`
+	BidAt   time.Time `json:"bid_at"`
+}
+
+// LeaderboardDTO is the top bidders of a lot, best bid first
+type LeaderboardDTO struct {
+	LotID    uuid.UUID             `json:"lot_id"`
+	Currency string                `json:"currency"`
+	Entries  []LeaderboardEntryDTO `json:"entries"`
+	// anonymization applied by ForViewer
+	anonymization LeaderboardAnonymization
+}
+
+// ForViewer hides the identity of the bidders from the viewer, admins see every user and the bidders
+// see their own entry. viewerID is uuid.Nil for anonymous viewers and broadcasts
+func (d *LeaderboardDTO) ForViewer(viewerID uuid.UUID, admin bool) *LeaderboardDTO {
+	if admin {
+		return d
+	}
+	view := *d
+	view.Entries = make([]LeaderboardEntryDTO, 0, len(d.Entries))
+	for _, entry := range d.Entries {
+		if viewerID == uuid.Nil || entry.UserID != viewerID {
+			entry.UserID = uuid.Nil
+			if d.anonymization == LeaderboardMasked {
+				entry.Paddle = 0
+			}
+		}
+		view.Entries = append(view.Entries, entry)
+	}
+	return &view
+}
+
+// leaderboard is the cached board of a lot
+type leaderboard struct {
+	lotType  domain.LotType
+	currency string
+	entries  []domain.LeaderboardEntry
+}
+
+// place records a bid of entry.UserID, the bid leads the lot so it ranks first unless it was already
+// counted (boards loaded after the bid was stored)
+func (b *leaderboard) place(entry domain.LeaderboardEntry, size int) {
+	better := func(amount, other float64) bool {
+		if b.lotType == domain.LotTypeReverse {
+			return amount < other
+		}
+		return amount > other
+	}
+	if i := slices.IndexFunc(b.entries, func(e domain.LeaderboardEntry) bool { return e.UserID == entry.UserID }); i >= 0 {
+		// BidAt is when the best bid was placed, the bidder who reached an amount first ranks above
+		switch {
+		case better(entry.BestBid, b.entries[i].BestBid):
+			b.entries[i].BestBid, b.entries[i].BidAt = entry.BestBid, entry.BidAt
+		case entry.BestBid == b.entries[i].BestBid && entry.BidAt.Before(b.entries[i].BidAt):
+			b.entries[i].BidAt = entry.BidAt
+		}
+	} else {
+		b.entries = append(b.entries, entry)
+	}
+	slices.SortStableFunc(b.entries, func(x, y domain.LeaderboardEntry) int {
+		switch {
+		case better(x.BestBid, y.BestBid):
+			return -1
+		case better(y.BestBid, x.BestBid):
+			return 1
+		}
+		return cmp.Compare(x.BidAt.UnixNano(), y.BidAt.UnixNano())
+	})
+	b.entries = b.entries[:min(len(b.entries), size)]
+}
+
+// LeaderboardUseCase maintains the top bidders of the lots by best bid. the boards of the lots being bid on
+// are kept in memory and updated from the bid events, the other ones are read from the bids
+type LeaderboardUseCase struct {
+	lotRepo   domain.AuctionLotRepository
+	statsRepo domain.BidStatsRepository
+	cfg       LeaderboardConfig
+
+	mu     sync.Mutex
+	boards map[uuid.UUID]*leaderboard
+}
+
+// NewLeaderboardUseCase creates a new instance of LeaderboardUseCase, an unknown anonymization shows the paddles
+func NewLeaderboardUseCase(lotRepo domain.AuctionLotRepository, statsRepo domain.BidStatsRepository, cfg LeaderboardConfig) *LeaderboardUseCase {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultLeaderboardSize
+	}
+	switch cfg.Anonymization {
+	case LeaderboardPaddles, LeaderboardMasked:
+	default:
+		log.Warn("LeaderboardUseCase: unknown anonymization, showing paddles", zap.String("anonymization", string(cfg.Anonymization)))
+		cfg.Anonymization = LeaderboardPaddles
+	}
+	return &LeaderboardUseCase{
+		lotRepo:   lotRepo,
+		statsRepo: statsRepo,
+		cfg:       cfg,
+		boards:    make(map[uuid.UUID]*leaderboard),
+	}
+}
+
+// Get returns the leaderboard of the lot, domain.ErrLotNotFound if it doesn't exist. the result holds
+// the bidder identities, it must be passed through ForViewer
+func (uc *LeaderboardUseCase) Get(ctx context.Context, lotID uuid.UUID) (*LeaderboardDTO, error) {
+	// the lot is always read, it checks the lot belongs to the organization of the caller
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	uc.mu.Lock()
+	board, ok := uc.boards[lotID]
+	if ok {
+		dto := uc.toDTO(lotID, board)
+		uc.mu.Unlock()
+		return dto, nil
+	}
+	uc.mu.Unlock()
+
+	// only the bid events fill the cache, a board read here could miss a bid being stored
+	entries, err := uc.statsRepo.GetLotLeaderboard(ctx, lotID, lot.Type, uc.cfg.Size)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard use case: failed to get leaderboard of lot %s: %w", lotID, err)
+	}
+	return uc.toDTO(lotID, &leaderboard{lotType: lot.Type, currency: lot.Currency, entries: entries}), nil
+}
+
+// Invalidate drops the cached board of the lot, e.g. after another instance stored bids on it. the next
+// read loads it from the bids
+func (uc *LeaderboardUseCase) Invalidate(lotID uuid.UUID) {
+	uc.mu.Lock()
+	delete(uc.boards, lotID)
+	uc.mu.Unlock()
+}
+
+// CachedLots returns the lots with a board in memory, implements LotCache
+func (uc *LeaderboardUseCase) CachedLots() []uuid.UUID {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return slices.Collect(maps.Keys(uc.boards))
+}
+
+// Apply updates the leaderboards with the lot events and returns the boards changed, with the bidder
+// identities. the events of a lot must be applied in order, as the lot command queue publishes them
+func (uc *LeaderboardUseCase) Apply(ctx context.Context, events ...domain.Event) []*LeaderboardDTO {
+	var changed []uuid.UUID
+	for _, event := range events {
+		switch payload := event.Payload.(type) {
+		case domain.BidPlacedPayload:
+			board, err := uc.board(ctx, event.LotID)
+			if err != nil {
+				logger.FromContext(ctx).Error("LeaderboardUseCase: failed to load leaderboard",
+					zap.String("lotID", event.LotID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			uc.mu.Lock()
+			board.place(domain.LeaderboardEntry{
+				Paddle:  payload.Paddle,
+				UserID:  payload.UserID,
+				BestBid: payload.Amount,
+				BidAt:   event.OccurredAt,
+			}, uc.cfg.Size)
+			uc.mu.Unlock()
+			changed = append(changed, event.LotID)
+		case domain.BidVoidedPayload:
+			// the voided bid may have been the best of its bidder, the board is read again
+			uc.mu.Lock()
+			delete(uc.boards, event.LotID)
+			uc.mu.Unlock()
+			if _, err := uc.board(ctx, event.LotID); err != nil {
+				logger.FromContext(ctx).Error("LeaderboardUseCase: failed to reload leaderboard",
+					zap.String("lotID", event.LotID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			changed = append(changed, event.LotID)
+		case domain.LotFinishedPayload, domain.LotCancelledPayload:
+			// no bid changes a closed lot, its board is read from the bids from now on
+			uc.mu.Lock()
+			delete(uc.boards, event.LotID)
+			uc.mu.Unlock()
+		}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	var dtos []*LeaderboardDTO
+	for i, lotID := range changed {
+		if slices.Contains(changed[:i], lotID) {
+			continue
+		}
+		if board, ok := uc.boards[lotID]; ok {
+			dtos = append(dtos, uc.toDTO(lotID, board))
+		}
+	}
+	return dtos
+}
+
+// board returns the cached board of the lot, loading it from the primary when missing
+func (uc *LeaderboardUseCase) board(ctx context.Context, lotID uuid.UUID) (*leaderboard, error) {
+	uc.mu.Lock()
+	board, ok := uc.boards[lotID]
+	uc.mu.Unlock()
+	if ok {
+		return board, nil
+	}
+	ctx = db.WithPrimaryReads(ctx)
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := uc.statsRepo.GetLotLeaderboard(ctx, lotID, lot.Type, uc.cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+	board = &leaderboard{lotType: lot.Type, currency: lot.Currency, entries: entries}
+	uc.mu.Lock()
+	uc.boards[lotID] = board
+	uc.mu.Unlock()
+	return board, nil
+}
+
+// toDTO copies board, uc.mu must be held for cached boards
+func (uc *LeaderboardUseCase) toDTO(lotID uuid.UUID, board *leaderboard) *LeaderboardDTO {
+	dto := &LeaderboardDTO{
+		LotID:         lotID,
+		Currency:      board.currency,
+		Entries:       make([]LeaderboardEntryDTO, 0, len(board.entries)),
+		anonymization: uc.cfg.Anonymization,
+	}
+	for i, entry := range board.entries {
+		dto.Entries = append(dto.Entries, LeaderboardEntryDTO{
+			Rank:    i + 1,
+			Paddle:  entry.Paddle,
+			UserID:  entry.UserID,
+			BestBid: entry.BestBid,
+			BidAt:   entry.BidAt,
+		})
+	}
+	return dto
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// TestLeaderboardPlaceTies checks equal best bids rank by the time each one was placed, a later bid
+// not improving the best one doesn't move its bidder down
+func TestLeaderboardPlaceTies(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		lotType domain.LotType
+		bids    []domain.LeaderboardEntry
+		want    []uuid.UUID
+	}{
+		{"first to reach the amount ranks above", domain.LotTypeForward, []domain.LeaderboardEntry{
+			{UserID: first, BestBid: 100, BidAt: start},
+			{UserID: second, BestBid: 100, BidAt: start.Add(time.Second)},
+		}, []uuid.UUID{first, second}},
+		{"improving the best bid moves its time", domain.LotTypeForward, []domain.LeaderboardEntry{
+			{UserID: second, BestBid: 90, BidAt: start},
+			{UserID: first, BestBid: 100, BidAt: start.Add(time.Second)},
+			{UserID: second, BestBid: 100, BidAt: start.Add(2 * time.Second)},
+		}, []uuid.UUID{first, second}},
+		{"a lower bid keeps the time of the best one", domain.LotTypeForward, []domain.LeaderboardEntry{
+			{UserID: first, BestBid: 100, BidAt: start},
+			{UserID: second, BestBid: 100, BidAt: start.Add(time.Second)},
+			{UserID: first, BestBid: 80, BidAt: start.Add(2 * time.Second)},
+		}, []uuid.UUID{first, second}},
+		{"a bid counted twice keeps its time", domain.LotTypeForward, []domain.LeaderboardEntry{
+			{UserID: first, BestBid: 100, BidAt: start},
+			{UserID: second, BestBid: 100, BidAt: start.Add(time.Second)},
+			{UserID: first, BestBid: 100, BidAt: start},
+		}, []uuid.UUID{first, second}},
+		{"reverse lots", domain.LotTypeReverse, []domain.LeaderboardEntry{
+			{UserID: second, BestBid: 50, BidAt: start},
+			{UserID: first, BestBid: 40, BidAt: start.Add(time.Second)},
+			{UserID: second, BestBid: 40, BidAt: start.Add(2 * time.Second)},
+		}, []uuid.UUID{first, second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := &leaderboard{lotType: tt.lotType, currency: "USD"}
+			for _, bid := range tt.bids {
+				board.place(bid, 10)
+			}
+			if len(board.entries) != len(tt.want) {
+				t.Fatalf("entries = %d, want %d", len(board.entries), len(tt.want))
+			}
+			for i, userID := range tt.want {
+				if board.entries[i].UserID != userID {
+					t.Errorf("rank %d = %v, want %v", i+1, board.entries[i].UserID, userID)
+				}
+			}
+		})
+	}
+}
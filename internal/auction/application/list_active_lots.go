@@ -0,0 +1,34 @@
+package application
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+)
+
+// ListActiveLotsUseCase retrieves the state of all the active auction lots
+type ListActiveLotsUseCase struct {
+	lotRepo      domain.AuctionLotRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewListActiveLotsUseCase creates a new instance of ListActiveLotsUseCase
+func NewListActiveLotsUseCase(lotRepo domain.AuctionLotRepository, categoryRepo domain.CategoryRepository) *ListActiveLotsUseCase {
+	return &ListActiveLotsUseCase{lotRepo: lotRepo, categoryRepo: categoryRepo}
+}
+
+func (uc *ListActiveLotsUseCase) Execute(ctx context.Context) ([]*LotStateDTO, error) {
+	lots, err := uc.lotRepo.GetActiveLots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*LotStateDTO, 0, len(lots))
+	for _, lot := range lots {
+		dtos = append(dtos, newLotStateDTO(lot))
+	}
+	if err := attachCategories(ctx, uc.categoryRepo, dtos...); err != nil {
+		return nil, err
+	}
+	return dtos, nil
+}
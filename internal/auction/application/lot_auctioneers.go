@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotAuctioneerDTO is the output DTO of an auctioneer assigned to a lot
+type LotAuctioneerDTO struct {
+	LotID      uuid.UUID `json:"lot_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	AssignedBy uuid.UUID `json:"assigned_by"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// LotAuctioneersUseCase manages the auctioneers assigned to run the lots live
+type LotAuctioneersUseCase struct {
+	lotRepo        domain.AuctionLotRepository
+	auctioneerRepo domain.LotAuctioneerRepository
+}
+
+// NewLotAuctioneersUseCase creates a new instance of LotAuctioneersUseCase
+func NewLotAuctioneersUseCase(lotRepo domain.AuctionLotRepository, auctioneerRepo domain.LotAuctioneerRepository) *LotAuctioneersUseCase {
+	return &LotAuctioneersUseCase{
+		lotRepo:        lotRepo,
+		auctioneerRepo: auctioneerRepo,
+	}
+}
+
+// Assign lets the user run the lot through the admin WS channel
+func (uc *LotAuctioneersUseCase) Assign(ctx context.Context, lotID, userID, assignedBy uuid.UUID) (*LotAuctioneerDTO, error) {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("lot auctioneers use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	a := &domain.LotAuctioneer{LotID: lotID, UserID: userID, AssignedBy: assignedBy}
+	if err := uc.auctioneerRepo.Assign(ctx, a); err != nil {
+		return nil, fmt.Errorf("lot auctioneers use case: failed to assign auctioneer %s to lot %s: %w", userID, lotID, err)
+	}
+	logger.FromContext(ctx).Info("Auctioneer assigned to lot",
+		zap.String("lotID", lotID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("assignedBy", assignedBy.String()),
+	)
+	dto := toLotAuctioneerDTO(a)
+	return &dto, nil
+}
+
+// Unassign removes the user from the auctioneers of the lot, its open admin connections are kept until they close
+func (uc *LotAuctioneersUseCase) Unassign(ctx context.Context, lotID, userID uuid.UUID) error {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return fmt.Errorf("lot auctioneers use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	if err := uc.auctioneerRepo.Unassign(ctx, lotID, userID); err != nil {
+		return fmt.Errorf("lot auctioneers use case: failed to unassign auctioneer %s from lot %s: %w", userID, lotID, err)
+	}
+	logger.FromContext(ctx).Info("Auctioneer unassigned from lot", zap.String("lotID", lotID.String()), zap.String("userID", userID.String()))
+	return nil
+}
+
+// List returns the auctioneers assigned to the lot
+func (uc *LotAuctioneersUseCase) List(ctx context.Context, lotID uuid.UUID) ([]LotAuctioneerDTO, error) {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("lot auctioneers use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	auctioneers, err := uc.auctioneerRepo.GetByLotID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot auctioneers use case: failed to get auctioneers of lot %s: %w", lotID, err)
+	}
+	dtos := make([]LotAuctioneerDTO, 0, len(auctioneers))
+	for _, a := range auctioneers {
+		dtos = append(dtos, toLotAuctioneerDTO(a))
+	}
+	return dtos, nil
+}
+
+// IsAssigned reports if the user is assigned to run the lot
+func (uc *LotAuctioneersUseCase) IsAssigned(ctx context.Context, lotID, userID uuid.UUID) (bool, error) {
+	assigned, err := uc.auctioneerRepo.IsAssigned(ctx, lotID, userID)
+	if err != nil {
+		return false, fmt.Errorf("lot auctioneers use case: failed to check auctioneer %s of lot %s: %w", userID, lotID, err)
+	}
+	return assigned, nil
+}
+
+func toLotAuctioneerDTO(a *domain.LotAuctioneer) LotAuctioneerDTO {
+	return LotAuctioneerDTO{
+		LotID:      a.LotID,
+		UserID:     a.UserID,
+		AssignedBy: a.AssignedBy,
+		AssignedAt: a.AssignedAt,
+	}
+}
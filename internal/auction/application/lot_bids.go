@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// LotBidsPageDTO is the input of LotBidsUseCase
+type LotBidsPageDTO struct {
+	LotID  uuid.UUID
+	Limit  int
+	Offset int
+}
+
+// LotBidDTO is a bid of the lot history, bidders are shown by paddle and UserID is only for admins and the bidder
+type LotBidDTO struct {
+	BidID     uuid.UUID `json:"bid_id"`
+	LotID     uuid.UUID `json:"lot_id"`
+	UserID    uuid.UUID `json:"user_id,omitempty"`
+	Paddle    int       `json:"paddle"`
+	Amount    float64   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LotBidsUseCase retrieves the bid history of a lot
+type LotBidsUseCase struct {
+	bidRepo    domain.BidRepository
+	paddleRepo domain.PaddleRepository
+}
+
+// NewLotBidsUseCase creates a new instance of LotBidsUseCase
+func NewLotBidsUseCase(bidRepo domain.BidRepository, paddleRepo domain.PaddleRepository) *LotBidsUseCase {
+	return &LotBidsUseCase{bidRepo: bidRepo, paddleRepo: paddleRepo}
+}
+
+// Execute returns a page of the lot bids with their paddles, newest first and without voided bids
+func (uc *LotBidsUseCase) Execute(ctx context.Context, cmd LotBidsPageDTO) ([]LotBidDTO, error) {
+	bids, err := uc.bidRepo.GetBidsByLotID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot bids use case: failed to get bids of lot %s: %w", cmd.LotID, err)
+	}
+	// the bids are stored oldest first, a lot has few enough bids to page them in memory
+	slices.Reverse(bids)
+	limit, offset := pageBounds(cmd.Limit, cmd.Offset)
+	bids = bids[min(offset, len(bids)):min(offset+limit, len(bids))]
+
+	paddles := make(map[uuid.UUID]int)
+	dtos := make([]LotBidDTO, 0, len(bids))
+	for _, bid := range bids {
+		paddle, ok := paddles[bid.UserID]
+		if !ok {
+			if paddle, err = uc.paddleRepo.Get(ctx, cmd.LotID, bid.UserID); err != nil {
+				return nil, fmt.Errorf("lot bids use case: failed to get paddle of user %s: %w", bid.UserID, err)
+			}
+			paddles[bid.UserID] = paddle
+		}
+		dtos = append(dtos, LotBidDTO{
+			BidID:     bid.ID,
+			LotID:     bid.LotID,
+			UserID:    bid.UserID,
+			Paddle:    paddle,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	}
+	return dtos, nil
+}
@@ -0,0 +1,366 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// minBundleLots is the least number of lots of a bundle
+const minBundleLots = 2
+
+// ErrInvalidBundle is returned when creating a bundle of lots that can't be bid on together
+var ErrInvalidBundle = errors.New("invalid lot bundle")
+
+// CreateBundleDTO is the input of LotBundleUseCase.Create
+type CreateBundleDTO struct {
+	Title     string
+	LotIDs    []uuid.UUID
+	CreatedBy uuid.UUID
+}
+
+// PlaceBundleBidDTO is the input of LotBundleUseCase.PlaceBid
+type PlaceBundleBidDTO struct {
+	BundleID uuid.UUID
+	UserID   uuid.UUID
+	Amount   float64
+}
+
+// BundleLotDTO is a lot of a bundle with its leading bid
+type BundleLotDTO struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	Title         string    `json:"title"`
+	State         string    `json:"state"`
+	LeadingAmount float64   `json:"leading_amount"`
+	HasBids       bool      `json:"has_bids"`
+}
+
+// BundleDTO is a lot bundle with the bids it competes against
+type BundleDTO struct {
+	ID       uuid.UUID      `json:"id"`
+	Title    string         `json:"title"`
+	Currency string         `json:"currency"`
+	State    string         `json:"state"`
+	Lots     []BundleLotDTO `json:"lots"`
+	// IndividualSum is the sum of the leading bids of the lots, of their winning bids once settled
+	IndividualSum float64    `json:"individual_sum"`
+	LeadingAmount float64    `json:"leading_amount"`
+	LeadingBidID  *uuid.UUID `json:"leading_bid_id,omitempty"`
+	LeadingUserID uuid.UUID  `json:"leading_user_id,omitempty"` // only for admins and the bidder, see ForViewer
+	// Beating reports if the leading bundle bid beats IndividualSum, the bundle wins the lots if it
+	// still does when they close
+	Beating   bool       `json:"beating"`
+	CreatedAt time.Time  `json:"created_at"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// BundleNotifier delivers the changes of a bundle to the connected clients of its lots
+type BundleNotifier interface {
+	NotifyBundle(ctx context.Context, bundle *BundleDTO)
+}
+
+// LotBundleUseCase runs the bundles of lots bid on as a whole: a bundle bid must beat the leading
+// bundle bid and the sum of the leading bids of the lots, and once all the lots closed the bundle is
+// settled against the sum of their winning bids. the winners of the lots of a bundle with a bid are not
+// invoiced when the lots finish, EventBundleSettled invoices the bundle bid of a won bundle or them
+type LotBundleUseCase struct {
+	repo     domain.LotBundleRepository
+	lotRepo  domain.AuctionLotRepository
+	notifier BundleNotifier
+	clock    domain.Clock
+	// events and bidRepo publish the settled bundles, nil without events
+	events  domain.EventPublisher
+	bidRepo domain.BidRepository
+}
+
+// NewLotBundleUseCase creates a new instance of LotBundleUseCase
+func NewLotBundleUseCase(repo domain.LotBundleRepository, lotRepo domain.AuctionLotRepository, notifier BundleNotifier, clock domain.Clock) *LotBundleUseCase {
+	return &LotBundleUseCase{repo: repo, lotRepo: lotRepo, notifier: notifier, clock: clock}
+}
+
+// WithEvents returns the use case publishing EventBundleSettled once a bundle is settled, bidRepo gives
+// the winning bids of its lots
+func (uc *LotBundleUseCase) WithEvents(events domain.EventPublisher, bidRepo domain.BidRepository) *LotBundleUseCase {
+	uc.events, uc.bidRepo = events, bidRepo
+	return uc
+}
+
+// Create groups lots of an organization not closed yet in an open bundle, the lots must be single item
+// forward lots sharing a currency and can't be in another open bundle
+func (uc *LotBundleUseCase) Create(ctx context.Context, cmd CreateBundleDTO) (*BundleDTO, error) {
+	title := strings.TrimSpace(cmd.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidBundle)
+	}
+	lotIDs := make([]uuid.UUID, 0, len(cmd.LotIDs))
+	seen := make(map[uuid.UUID]bool, len(cmd.LotIDs))
+	for _, lotID := range cmd.LotIDs {
+		if !seen[lotID] {
+			seen[lotID] = true
+			lotIDs = append(lotIDs, lotID)
+		}
+	}
+	if len(lotIDs) < minBundleLots {
+		return nil, fmt.Errorf("%w: a bundle needs at least %d distinct lots", ErrInvalidBundle, minBundleLots)
+	}
+
+	lots, err := uc.lots(ctx, lotIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, lot := range lots {
+		switch {
+		case lot.Type == domain.LotTypeReverse:
+			return nil, fmt.Errorf("%w: lot %s is a reverse lot", ErrInvalidBundle, lot.ID)
+		case lot.MultiUnit():
+			return nil, fmt.Errorf("%w: lot %s sells several units", ErrInvalidBundle, lot.ID)
+		case lot.OrgID != lots[0].OrgID:
+			return nil, fmt.Errorf("%w: lot %s belongs to another organization", ErrInvalidBundle, lot.ID)
+		case lot.Currency != lots[0].Currency:
+			return nil, fmt.Errorf("%w: lot %s is priced in %s, not %s", ErrInvalidBundle, lot.ID, lot.Currency, lots[0].Currency)
+		case lot.State == domain.StateFinished || lot.State == domain.StateCancelled:
+			return nil, fmt.Errorf("%w: lot %s is %s", ErrInvalidBundle, lot.ID, lot.State)
+		}
+	}
+	bundled, err := uc.repo.OpenWithLots(ctx, lotIDs)
+	if err != nil {
+		return nil, fmt.Errorf("bundle use case: failed to check the bundles of the lots: %w", err)
+	}
+	if len(bundled) > 0 {
+		return nil, fmt.Errorf("bundle use case: bundle %s: %w", bundled[0], domain.ErrLotInOpenBundle)
+	}
+
+	bundle := &domain.LotBundle{
+		ID:        uuid.New(),
+		OrgID:     lots[0].OrgID,
+		Title:     title,
+		LotIDs:    lotIDs,
+		Currency:  lots[0].Currency,
+		State:     domain.BundleOpen,
+		CreatedBy: cmd.CreatedBy,
+	}
+	if err := uc.repo.Create(ctx, bundle); err != nil {
+		return nil, fmt.Errorf("bundle use case: failed to store bundle: %w", err)
+	}
+	logger.FromContext(ctx).Info("Lot bundle created",
+		zap.String("bundleID", bundle.ID.String()),
+		zap.Int("lots", len(lotIDs)),
+		zap.String("createdBy", cmd.CreatedBy.String()),
+	)
+	return newBundleDTO(bundle, lots), nil
+}
+
+// Get returns a bundle with the leading bids of its lots
+func (uc *LotBundleUseCase) Get(ctx context.Context, id uuid.UUID) (*BundleDTO, error) {
+	bundle, lots, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newBundleDTO(bundle, lots), nil
+}
+
+// PlaceBid bids on all the lots of the bundle, the bundle takes bids until its first lot closes
+func (uc *LotBundleUseCase) PlaceBid(ctx context.Context, cmd PlaceBundleBidDTO) (*BundleDTO, error) {
+	bundle, lots, err := uc.get(ctx, cmd.BundleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, lot := range lots {
+		if lot.State == domain.StateFinished || lot.State == domain.StateCancelled {
+			return nil, fmt.Errorf("bundle use case: bundle %s: %w", bundle.ID, domain.ErrBundleNotOpen)
+		}
+	}
+	bid, err := bundle.NewBid(cmd.UserID, cmd.Amount, individualSum(lots), uc.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("bundle use case: bid on bundle %s: %w", bundle.ID, err)
+	}
+	if err := uc.repo.PlaceBid(ctx, bid); err != nil {
+		return nil, fmt.Errorf("bundle use case: failed to store bid on bundle %s: %w", bundle.ID, err)
+	}
+	bundle.LeadingBid = bid
+
+	dto := newBundleDTO(bundle, lots)
+	uc.notifier.NotifyBundle(ctx, dto)
+	logger.FromContext(ctx).Info("Bundle bid placed",
+		zap.String("bundleID", bundle.ID.String()),
+		zap.String("bidID", bid.ID.String()),
+		zap.String("userID", cmd.UserID.String()),
+		zap.Float64("amount", bid.Amount),
+		zap.Float64("individualSum", dto.IndividualSum),
+	)
+	return dto, nil
+}
+
+// SettleClosed settles the open bundles whose lots all closed, each bundle is settled once whatever
+// the number of instances
+func (uc *LotBundleUseCase) SettleClosed(ctx context.Context) error {
+	ids, err := uc.repo.ListSettleable(ctx)
+	if err != nil {
+		return fmt.Errorf("bundle use case: failed to list settleable bundles: %w", err)
+	}
+	log := logger.FromContext(ctx)
+	for _, id := range ids {
+		if err := uc.settle(ctx, id); err != nil {
+			log.Error("LotBundleUseCase: failed to settle bundle",
+				zap.String("bundleID", id.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (uc *LotBundleUseCase) settle(ctx context.Context, id uuid.UUID) error {
+	bundle, lots, err := uc.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := bundle.Settle(lots, uc.clock.Now()); err != nil {
+		return err
+	}
+	settled, err := uc.repo.Settle(ctx, bundle)
+	if err != nil || !settled {
+		return err
+	}
+	uc.notifier.NotifyBundle(ctx, newBundleDTO(bundle, lots))
+	// the outcome is stored, a failed publish is only logged
+	if err := uc.publishSettled(ctx, bundle, lots); err != nil {
+		logger.FromContext(ctx).Error("LotBundleUseCase: failed to publish settled bundle",
+			zap.String("bundleID", bundle.ID.String()),
+			zap.Error(err),
+		)
+	}
+	logger.FromContext(ctx).Info("Lot bundle settled",
+		zap.String("bundleID", bundle.ID.String()),
+		zap.String("state", string(bundle.State)),
+		zap.Float64("leadingAmount", bundle.LeadingAmount()),
+		zap.Float64("individualSum", bundle.IndividualSum),
+	)
+	return nil
+}
+
+// publishSettled publishes the outcome of the settled bundle for its invoicing: the winning bundle bid of
+// a won bundle, the winning bids of its finished lots otherwise. the lots of a bundle without bids were
+// invoiced as they finished, see FinalizeLotUseCase.WithBundles
+func (uc *LotBundleUseCase) publishSettled(ctx context.Context, bundle *domain.LotBundle, lots []*domain.AuctionLot) error {
+	if uc.events == nil {
+		return nil
+	}
+	payload := domain.BundleSettledPayload{
+		BundleID: bundle.ID,
+		Title:    bundle.Title,
+		State:    bundle.State,
+		LotIDs:   bundle.LotIDs,
+		Currency: bundle.Currency,
+	}
+	if bundle.State == domain.BundleWon {
+		payload.WinnerID, payload.BidID, payload.Amount = bundle.LeadingBid.UserID, bundle.LeadingBid.ID, bundle.LeadingBid.Amount
+	} else if bundle.LeadingBid != nil {
+		for _, lot := range lots {
+			if lot.State != domain.StateFinished {
+				continue
+			}
+			bid, err := uc.bidRepo.GetLatestBidByLotID(ctx, lot.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get winning bid of lot %s: %w", lot.ID, err)
+			}
+			if bid == nil {
+				continue
+			}
+			payload.LotWinners = append(payload.LotWinners, domain.BundleLotWinner{
+				LotID:      lot.ID,
+				LotTitle:   lot.Title,
+				WinnerID:   bid.UserID,
+				BidID:      bid.ID,
+				FinalPrice: lot.CurrentPrice,
+			})
+		}
+	}
+	return uc.events.Publish(ctx, domain.NewEvent(domain.EventBundleSettled, bundle.LotIDs[0], *bundle.SettledAt, payload))
+}
+
+// get returns the bundle id with its lots
+func (uc *LotBundleUseCase) get(ctx context.Context, id uuid.UUID) (*domain.LotBundle, []*domain.AuctionLot, error) {
+	bundle, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bundle use case: failed to get bundle %s: %w", id, err)
+	}
+	lots, err := uc.lots(ctx, bundle.LotIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bundle, lots, nil
+}
+
+func (uc *LotBundleUseCase) lots(ctx context.Context, lotIDs []uuid.UUID) ([]*domain.AuctionLot, error) {
+	lots := make([]*domain.AuctionLot, 0, len(lotIDs))
+	for _, lotID := range lotIDs {
+		lot, err := uc.lotRepo.GetByID(ctx, lotID)
+		if err != nil {
+			return nil, fmt.Errorf("bundle use case: failed to get auction lot %s: %w", lotID, err)
+		}
+		lots = append(lots, lot)
+	}
+	return lots, nil
+}
+
+// ForViewer returns the bundle as seen by a user: the leading bidder is only revealed to admins and
+// to the bidder
+func (b *BundleDTO) ForViewer(viewerID uuid.UUID, admin bool) *BundleDTO {
+	if admin || (viewerID != uuid.Nil && viewerID == b.LeadingUserID) {
+		return b
+	}
+	anonymized := *b
+	anonymized.LeadingUserID = uuid.Nil
+	return &anonymized
+}
+
+// individualSum returns the sum of the leading bids of lots
+func individualSum(lots []*domain.AuctionLot) float64 {
+	sum := 0.0
+	for _, lot := range lots {
+		sum += lot.LeadingBidAmount()
+	}
+	return sum
+}
+
+func newBundleDTO(bundle *domain.LotBundle, lots []*domain.AuctionLot) *BundleDTO {
+	dto := &BundleDTO{
+		ID:            bundle.ID,
+		Title:         bundle.Title,
+		Currency:      bundle.Currency,
+		State:         string(bundle.State),
+		Lots:          make([]BundleLotDTO, 0, len(lots)),
+		IndividualSum: domain.RoundAmount(individualSum(lots), bundle.Currency),
+		LeadingAmount: bundle.LeadingAmount(),
+		CreatedAt:     bundle.CreatedAt,
+		SettledAt:     bundle.SettledAt,
+	}
+	for _, lot := range lots {
+		dto.Lots = append(dto.Lots, BundleLotDTO{
+			LotID:         lot.ID,
+			Title:         lot.Title,
+			State:         string(lot.State),
+			LeadingAmount: lot.LeadingBidAmount(),
+			HasBids:       lot.LastBidTime != nil,
+		})
+	}
+	if bundle.State != domain.BundleOpen {
+		dto.IndividualSum = bundle.IndividualSum
+	}
+	if bid := bundle.LeadingBid; bid != nil {
+		dto.LeadingBidID, dto.LeadingUserID = &bid.ID, bid.UserID
+		dto.Beating = domain.MinorUnits(bid.Amount, bundle.Currency) > domain.MinorUnits(dto.IndividualSum, bundle.Currency)
+	}
+	if bundle.State == domain.BundleCancelled || bundle.State == domain.BundleLost {
+		dto.Beating = false
+	}
+	return dto
+}
@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// fakeBundleRepo keeps the bundles in memory, a bundle is settleable once all its lots closed
+type fakeBundleRepo struct {
+	bundles map[uuid.UUID]*domain.LotBundle
+	lots    map[uuid.UUID]*domain.AuctionLot
+}
+
+func (r *fakeBundleRepo) Create(_ context.Context, bundle *domain.LotBundle) error {
+	r.bundles[bundle.ID] = bundle
+	return nil
+}
+
+func (r *fakeBundleRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.LotBundle, error) {
+	bundle, ok := r.bundles[id]
+	if !ok {
+		return nil, domain.ErrBundleNotFound
+	}
+	copied := *bundle
+	return &copied, nil
+}
+
+func (r *fakeBundleRepo) OpenWithLots(_ context.Context, lotIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, bundle := range r.bundles {
+		for _, lotID := range bundle.LotIDs {
+			if bundle.State == domain.BundleOpen && slices.Contains(lotIDs, lotID) {
+				ids = append(ids, bundle.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeBundleRepo) PlaceBid(_ context.Context, bid *domain.BundleBid) error {
+	bundle := r.bundles[bid.BundleID]
+	if bundle.State != domain.BundleOpen || bid.Amount <= bundle.LeadingAmount() {
+		return domain.ErrBidAmountTooLow
+	}
+	bundle.LeadingBid = bid
+	return nil
+}
+
+func (r *fakeBundleRepo) ListSettleable(context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, bundle := range r.bundles {
+		closed := true
+		for _, lotID := range bundle.LotIDs {
+			state := r.lots[lotID].State
+			closed = closed && (state == domain.StateFinished || state == domain.StateCancelled)
+		}
+		if bundle.State == domain.BundleOpen && closed {
+			ids = append(ids, bundle.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeBundleRepo) Settle(_ context.Context, bundle *domain.LotBundle) (bool, error) {
+	if r.bundles[bundle.ID].State != domain.BundleOpen {
+		return false, nil
+	}
+	r.bundles[bundle.ID] = bundle
+	return true, nil
+}
+
+// bundleLotRepo returns the lots of the bundles
+type bundleLotRepo struct {
+	domain.AuctionLotRepository
+	lots map[uuid.UUID]*domain.AuctionLot
+}
+
+func (r *bundleLotRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.AuctionLot, error) {
+	lot, ok := r.lots[id]
+	if !ok {
+		return nil, domain.ErrLotNotFound
+	}
+	return lot, nil
+}
+
+// fakeBundleNotifier records the notified bundle states
+type fakeBundleNotifier []*BundleDTO
+
+func (n *fakeBundleNotifier) NotifyBundle(_ context.Context, bundle *BundleDTO) {
+	*n = append(*n, bundle)
+}
+
+// recordingPublisher records the published events
+type recordingPublisher struct {
+	domain.EventPublisher
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, events ...domain.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// TestLotBundles checks a bundle bid must beat the sum of the leading bids of the lots, and the bundle
+// wins at settlement when it still beats the sum of their winning bids
+func TestLotBundles(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newLot := func(price float64) *domain.AuctionLot {
+		lot := domain.NewAuctionLot(uuid.New(), "lot", "", 100, now.Add(time.Hour), 0)
+		lot.State, lot.CurrentPrice, lot.LastBidTime = domain.StateActive, price, &now
+		return lot
+	}
+	first, second, other := newLot(150), newLot(250), newLot(100)
+	other.Type = domain.LotTypeReverse
+	lots := map[uuid.UUID]*domain.AuctionLot{first.ID: first, second.ID: second, other.ID: other}
+	repo := &fakeBundleRepo{bundles: map[uuid.UUID]*domain.LotBundle{}, lots: lots}
+	notifier, events := &fakeBundleNotifier{}, &recordingPublisher{}
+	uc := NewLotBundleUseCase(repo, &bundleLotRepo{lots: lots}, notifier, domain.NewManualClock(now)).
+		WithEvents(events, nil)
+	ctx := context.Background()
+
+	if _, err := uc.Create(ctx, CreateBundleDTO{Title: "pair", LotIDs: []uuid.UUID{first.ID, other.ID}}); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("bundle with a reverse lot: err = %v, want ErrInvalidBundle", err)
+	}
+	if _, err := uc.Create(ctx, CreateBundleDTO{Title: "pair", LotIDs: []uuid.UUID{first.ID, first.ID}}); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("bundle of a single lot: err = %v, want ErrInvalidBundle", err)
+	}
+	bundle, err := uc.Create(ctx, CreateBundleDTO{Title: "pair", LotIDs: []uuid.UUID{first.ID, second.ID}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := uc.Create(ctx, CreateBundleDTO{Title: "again", LotIDs: []uuid.UUID{second.ID, first.ID}}); !errors.Is(err, domain.ErrLotInOpenBundle) {
+		t.Errorf("lot already bundled: err = %v, want ErrLotInOpenBundle", err)
+	}
+
+	bid := func(amount float64) (*BundleDTO, error) {
+		return uc.PlaceBid(ctx, PlaceBundleBidDTO{BundleID: bundle.ID, UserID: uuid.New(), Amount: amount})
+	}
+	if _, err := bid(400); !errors.Is(err, domain.ErrBidAmountTooLow) {
+		t.Errorf("bid at the sum of the lots: err = %v, want ErrBidAmountTooLow", err)
+	}
+	state, err := bid(450)
+	if err != nil || !state.Beating || state.IndividualSum != 400 {
+		t.Fatalf("bid of 450 = %+v, %v, want beating a sum of 400", state, err)
+	}
+	if _, err := bid(440); !errors.Is(err, domain.ErrBidAmountTooLow) {
+		t.Errorf("bid below the leading bundle bid: err = %v, want ErrBidAmountTooLow", err)
+	}
+
+	// the lots close with winning bids summing 410, below the bundle bid
+	second.CurrentPrice = 260
+	first.State, second.State = domain.StateFinished, domain.StateFinished
+	if _, err := bid(500); !errors.Is(err, domain.ErrBundleNotOpen) {
+		t.Errorf("bid once the lots closed: err = %v, want ErrBundleNotOpen", err)
+	}
+	if err := uc.SettleClosed(ctx); err != nil {
+		t.Fatalf("SettleClosed() error = %v", err)
+	}
+	settled, err := uc.Get(ctx, bundle.ID)
+	if err != nil || settled.State != string(domain.BundleWon) || settled.IndividualSum != 410 || settled.SettledAt == nil {
+		t.Fatalf("settled bundle = %+v, %v, want won against 410", settled, err)
+	}
+	if len(*notifier) != 2 || (*notifier)[1].State != string(domain.BundleWon) {
+		t.Errorf("notified %d bundle states, want the bid and the settlement", len(*notifier))
+	}
+	if len(events.events) != 1 || events.events[0].Type != domain.EventBundleSettled {
+		t.Fatalf("published %+v, want the settled bundle", events.events)
+	}
+	if payload := events.events[0].Payload.(domain.BundleSettledPayload); payload.State != domain.BundleWon ||
+		payload.BidID != *settled.LeadingBidID || payload.Amount != 450 || len(payload.LotWinners) != 0 {
+		t.Errorf("settled bundle payload = %+v, want the bundle bid of 450 invoiced instead of the lots", payload)
+	}
+	if anonymous := settled.ForViewer(uuid.Nil, false); anonymous.LeadingUserID != uuid.Nil {
+		t.Error("ForViewer() revealed the leading bidder to an anonymous viewer")
+	}
+}
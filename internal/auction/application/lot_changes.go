@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotChangeSyncStats counts the lot changes notified by the other instances since start
+type LotChangeSyncStats struct {
+	Received int64 `json:"received"`
+	// Broadcast is the number of changes published to the local watchers of the lot, the changes of lots
+	// nobody watches here only invalidate the caches
+	Broadcast int64 `json:"broadcast"`
+	Failed    int64 `json:"failed"`
+}
+
+// LotChangeSync applies the lot changes made by the other instances: it drops what this instance caches
+// of the lot and, when the lot has watchers here (WS clients, streams), publishes its new state to them
+type LotChangeSync struct {
+	getLotState *GetLotStateUseCase
+	updates     *LotUpdateBroker
+	commands    *LotCommandQueue
+	// watched reports if the lot has local watchers, invalidators drop the cached data of a lot
+	watched      func(lotID uuid.UUID) bool
+	invalidators []func(lotID uuid.UUID)
+
+	received  atomic.Int64
+	broadcast atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewLotChangeSync creates a new instance of LotChangeSync, watched tells the lots to publish
+func NewLotChangeSync(getLotState *GetLotStateUseCase, updates *LotUpdateBroker, commands *LotCommandQueue,
+	watched func(lotID uuid.UUID) bool, invalidators ...func(lotID uuid.UUID)) *LotChangeSync {
+	return &LotChangeSync{
+		getLotState:  getLotState,
+		updates:      updates,
+		commands:     commands,
+		watched:      watched,
+		invalidators: invalidators,
+	}
+}
+
+// HandleRemoteChange applies the change of a lot notified by another instance
+func (s *LotChangeSync) HandleRemoteChange(ctx context.Context, lotID uuid.UUID, seq int64) {
+	s.received.Add(1)
+	for _, invalidate := range s.invalidators {
+		invalidate(lotID)
+	}
+	if !s.watched(lotID) {
+		return
+	}
+	// the notification is sent after the commit, the primary already has the change
+	state, err := s.getLotState.Execute(db.WithPrimaryReads(ctx), lotID)
+	if err != nil {
+		s.failed.Add(1)
+		logger.FromContext(ctx).Error("LotChangeSync: failed to load changed lot",
+			zap.String("lotID", lotID.String()),
+			zap.Int64("seq", seq),
+			zap.Error(err),
+		)
+		return
+	}
+	s.commands.ObserveEnd(lotID, state.EndTime)
+	// published locally only, the instance that made the change already notified the others
+	s.updates.Publish(state)
+	s.broadcast.Add(1)
+}
+
+// Stats returns the counters of the notified changes
+func (s *LotChangeSync) Stats() LotChangeSyncStats {
+	return LotChangeSyncStats{
+		Received:  s.received.Load(),
+		Broadcast: s.broadcast.Load(),
+		Failed:    s.failed.Load(),
+	}
+}
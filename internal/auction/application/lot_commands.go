@@ -0,0 +1,304 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// lotCommand is a state changing operation on a lot, run by the actor of the lot
+type lotCommand struct {
+	ctx  context.Context
+	fn   func(ctx context.Context) error
+	done chan error
+	// bid is set on bids, which can be stored in a batch instead of running fn, placed is the stored bid
+	bid    *PlaceBidDTO
+	placed *domain.Bid
+	// queuedAt and seq are when and in which order the command was queued, they order the held bids
+	// of the fairness queue
+	queuedAt time.Time
+	seq      uint64
+}
+
+// BidBatching configures the batched bid persistence. when a lot actor finds more bids queued behind
+// the current one it keeps collecting bids for up to Window, or until MaxBids, and stores them all in
+// a single TX. a MaxBids of 1 or less disables it and every bid is stored in its own TX
+type BidBatching struct {
+	Window  time.Duration
+	MaxBids int
+}
+
+// LotCommandQueue serializes the commands of each lot (bids, voids, lifecycle transitions, finalization):
+// every lot with pending commands has an actor goroutine running them one at a time in arrival order.
+// concurrent bids of a lot don't compete for the lot row in the DB and the first valid bid always wins,
+// the next one is validated against the state it left. different lots still run in parallel.
+// the serialization is per process, against other instances the lots are saved with optimistic concurrency
+// (AuctionLot.Version) so a command based on a lot another instance changed fails with ErrConcurrentLotUpdate
+type LotCommandQueue struct {
+	mu          sync.Mutex
+	actors      map[uuid.UUID]chan *lotCommand
+	queueSize   int
+	idleTimeout time.Duration
+	batching    BidBatching
+	// placeBids stores a batch of bids of a lot, set by the AuctionService
+	placeBids func(ctx context.Context, cmds []PlaceBidDTO) ([]*domain.Bid, []error)
+	// fairness orders the bids of the lots about to close, see WithFairness. lotEnd reads the end time
+	// of a lot, set by the AuctionService, and ends are the last known ones of the lots with an actor
+	fairness         BidFairness
+	clock            domain.Clock
+	lotEnd           func(ctx context.Context, lotID uuid.UUID) (time.Time, error)
+	ends             map[uuid.UUID]time.Time
+	fairnessCounters fairnessCounters
+	seq              atomic.Uint64
+	// commands queued and not yet finished, across all lots
+	pending atomic.Int64
+	// avgRun is the moving average of the run time of a command in ns, it estimates the queue delays
+	avgRun atomic.Int64
+}
+
+// QueueHint is the load of the queue of a lot as seen by a command about to join it
+type QueueHint struct {
+	// Position is the number of commands waiting ahead, besides the running one
+	Position int
+	// EstimatedDelay is the expected wait until the command runs, from the recent run times
+	EstimatedDelay time.Duration
+}
+
+// NewLotCommandQueue creates a new instance of LotCommandQueue, each lot holds up to queueSize pending
+// commands and its actor stops after idleTimeout without commands
+func NewLotCommandQueue(queueSize int, idleTimeout time.Duration, batching BidBatching) *LotCommandQueue {
+	return &LotCommandQueue{
+		actors:      make(map[uuid.UUID]chan *lotCommand),
+		ends:        make(map[uuid.UUID]time.Time),
+		clock:       domain.SystemClock{},
+		queueSize:   max(queueSize, 1),
+		idleTimeout: idleTimeout,
+		batching:    batching,
+	}
+}
+
+// Do runs fn in the actor of lotID and waits for its result. it fails with ErrLotBusy without queueing
+// when the lot already has queueSize pending commands, and with ctx error if ctx is done first,
+// a command whose ctx is done before its turn is skipped
+func (q *LotCommandQueue) Do(ctx context.Context, lotID uuid.UUID, fn func(ctx context.Context) error) error {
+	return q.submit(ctx, lotID, &lotCommand{fn: fn})
+}
+
+// doBid queues a bid, fn stores it on its own and is not called when the bid is stored in a batch. fn gets
+// the bid as the queue runs it, the fairness queue stamps its receipt time
+func (q *LotCommandQueue) doBid(ctx context.Context, cmd PlaceBidDTO, fn func(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error)) (*domain.Bid, error) {
+	c := &lotCommand{bid: &cmd}
+	c.fn = func(ctx context.Context) error {
+		var err error
+		c.placed, err = fn(ctx, *c.bid)
+		return err
+	}
+	if err := q.submit(ctx, cmd.LotID, c); err != nil {
+		return nil, err
+	}
+	return c.placed, nil
+}
+
+// Hint returns the load of the queue of lotID, a zero QueueHint when nothing waits on it
+func (q *LotCommandQueue) Hint(lotID uuid.UUID) QueueHint {
+	q.mu.Lock()
+	waiting := len(q.actors[lotID])
+	q.mu.Unlock()
+	if waiting == 0 {
+		return QueueHint{}
+	}
+	// the running command is halfway done on average
+	avg := time.Duration(q.avgRun.Load())
+	return QueueHint{Position: waiting, EstimatedDelay: time.Duration(waiting)*avg + avg/2}
+}
+
+// observeRun adds the run time of a command to the moving average
+func (q *LotCommandQueue) observeRun(d time.Duration) {
+	for {
+		prev := q.avgRun.Load()
+		next := int64(d)
+		if prev > 0 {
+			next = prev + (int64(d)-prev)/8
+		}
+		if q.avgRun.CompareAndSwap(prev, next) {
+			return
+		}
+	}
+}
+
+func (q *LotCommandQueue) submit(ctx context.Context, lotID uuid.UUID, cmd *lotCommand) error {
+	cmd.ctx = ctx
+	cmd.done = make(chan error, 1)
+	cmd.queuedAt = time.Now()
+
+	// queued under the lock, so an idle actor can't stop between the lookup and the send
+	q.mu.Lock()
+	queue, ok := q.actors[lotID]
+	if !ok {
+		queue = make(chan *lotCommand, q.queueSize)
+		q.actors[lotID] = queue
+		go q.runActor(lotID, queue)
+	}
+	cmd.seq = q.seq.Add(1)
+	select {
+	case queue <- cmd:
+		q.pending.Add(1)
+	default:
+		q.mu.Unlock()
+		return ErrLotBusy
+	}
+	q.mu.Unlock()
+
+	select {
+	case err := <-cmd.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runActor runs the commands of a lot until it has been idle for idleTimeout
+func (q *LotCommandQueue) runActor(lotID uuid.UUID, queue chan *lotCommand) {
+	idle := time.NewTimer(q.idleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case cmd := <-queue:
+			// a lone bid is stored on its own, batching only kicks in when bids pile up. near the close the
+			// bids are ordered by receipt instead, one at a time
+			if cmd.bid != nil && q.closing(cmd.ctx, lotID) {
+				if next := q.runFairBids(cmd, queue); next != nil {
+					q.finish(next, q.run(next))
+				}
+			} else if cmd.bid != nil && q.batching.MaxBids > 1 && q.placeBids != nil && len(queue) > 0 {
+				if next := q.runBidBatch(cmd, queue); next != nil {
+					q.finish(next, q.run(next))
+				}
+			} else {
+				q.finish(cmd, q.run(cmd))
+			}
+			idle.Reset(q.idleTimeout)
+		case <-idle.C:
+			q.mu.Lock()
+			if len(queue) == 0 {
+				delete(q.actors, lotID)
+				delete(q.ends, lotID)
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			idle.Reset(q.idleTimeout)
+		}
+	}
+}
+
+// runBidBatch collects the bids queued after first and stores them in a single batch. collecting stops at
+// the first command that is not a bid of the same organization, which is returned to be run right after the batch, keeping the order
+func (q *LotCommandQueue) runBidBatch(first *lotCommand, queue chan *lotCommand) (next *lotCommand) {
+	batch := []*lotCommand{first}
+	window := time.NewTimer(q.batching.Window)
+	defer window.Stop()
+collect:
+	for len(batch) < q.batching.MaxBids {
+		select {
+		case cmd := <-queue:
+			// the batch runs scoped as its first bid, a bid of another organization is run on its own
+			if cmd.bid == nil || !sameOrg(cmd.ctx, first.ctx) {
+				next = cmd
+				break collect
+			}
+			batch = append(batch, cmd)
+		case <-window.C:
+			break collect
+		}
+	}
+
+	live := make([]*lotCommand, 0, len(batch))
+	dtos := make([]PlaceBidDTO, 0, len(batch))
+	for _, cmd := range batch {
+		if err := cmd.ctx.Err(); err != nil {
+			q.finish(cmd, err)
+			continue
+		}
+		live = append(live, cmd)
+		dtos = append(dtos, *cmd.bid)
+	}
+	if len(live) == 0 {
+		return next
+	}
+
+	// the batch outlives the cancellation of any single bidder, it keeps the first bid ctx values
+	start := time.Now()
+	bids, errs := q.storeBids(context.WithoutCancel(live[0].ctx), dtos)
+	q.observeRun(time.Since(start) / time.Duration(len(live)))
+	for i, cmd := range live {
+		cmd.placed = bids[i]
+		q.finish(cmd, errs[i])
+	}
+	return next
+}
+
+// finish hands the result of cmd to its submitter
+func (q *LotCommandQueue) finish(cmd *lotCommand, err error) {
+	cmd.done <- err
+	q.pending.Add(-1)
+}
+
+// drainPollInterval is how often Drain checks the pending commands
+const drainPollInterval = 10 * time.Millisecond
+
+// Drain waits until the commands already queued, bids included, have finished, e.g. before a shutdown.
+// it returns ctx error if ctx is done first
+func (q *LotCommandQueue) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for q.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("lot commands: %d still pending: %w", q.pending.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// sameOrg reports if a and b are scoped to the same organization, or both unscoped
+func sameOrg(a, b context.Context) bool {
+	orgA, okA := tenant.OrgID(a)
+	orgB, okB := tenant.OrgID(b)
+	return okA == okB && orgA == orgB
+}
+
+// storeBids calls placeBids, a panic fails every bid of the batch instead of killing the actor of the lot
+func (q *LotCommandQueue) storeBids(ctx context.Context, dtos []PlaceBidDTO) (bids []*domain.Bid, errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			bids, errs = make([]*domain.Bid, len(dtos)), make([]error, len(dtos))
+			for i := range errs {
+				errs[i] = fmt.Errorf("bid batch panicked: %v", r)
+			}
+		}
+	}()
+	return q.placeBids(ctx, dtos)
+}
+
+// run executes cmd, a panic fails the command instead of killing the actor of the lot
+func (q *LotCommandQueue) run(cmd *lotCommand) (err error) {
+	if err := cmd.ctx.Err(); err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() {
+		q.observeRun(time.Since(start))
+		if r := recover(); r != nil {
+			err = fmt.Errorf("lot command panicked: %v", r)
+		}
+	}()
+	return cmd.fn(cmd.ctx)
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestLotCommandQueueHint checks the hint counts the commands waiting behind the running one
+func TestLotCommandQueueHint(t *testing.T) {
+	q := NewLotCommandQueue(16, time.Minute, BidBatching{})
+	lotID := uuid.New()
+	ctx := context.Background()
+	if hint := q.Hint(lotID); hint != (QueueHint{}) {
+		t.Fatalf("idle lot hint %+v, want none", hint)
+	}
+
+	release := make(chan struct{})
+	block := func(context.Context) error { <-release; return nil }
+	for i := range 3 {
+		go q.Do(ctx, lotID, block)
+		waitPending(t, q, int64(i+1))
+	}
+	// the first command runs, the other two wait
+	deadline := time.Now().Add(2 * time.Second)
+	for q.Hint(lotID).Position != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("hint %+v, want position 2", q.Hint(lotID))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	waitPending(t, q, 0)
+	if hint := q.Hint(lotID); hint.Position != 0 || q.avgRun.Load() <= 0 {
+		t.Fatalf("hint %+v and avg run %d after the commands, want no position and a run time", hint, q.avgRun.Load())
+	}
+}
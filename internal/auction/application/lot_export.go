@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// export formats of LotExportUseCase
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// exportPageSize is the number of bids read from the storage at a time by an export
+const exportPageSize = 500
+
+// LotResultDTO is the outcome of a lot in an export, the winner is only set on finished lots with bids
+type LotResultDTO struct {
+	LotID        uuid.UUID  `json:"lot_id"`
+	Title        string     `json:"title"`
+	State        string     `json:"state"`
+	Currency     string     `json:"currency"`
+	InitialPrice float64    `json:"initial_price"`
+	FinalPrice   float64    `json:"final_price"`
+	EndTime      time.Time  `json:"end_time"`
+	WinningBidID *uuid.UUID `json:"winning_bid_id,omitempty"`
+	WinnerID     *uuid.UUID `json:"winner_id,omitempty"`
+	WinnerPaddle int        `json:"winner_paddle,omitempty"`
+	// WinningBidIDs are the bids winning a unit of a multi-unit lot, the best one being WinningBidID
+	WinningBidIDs []uuid.UUID `json:"winning_bid_ids,omitempty"`
+}
+
+// ExportedBidDTO is a bid in an export, with the bidder identifiers, voided bids included
+type ExportedBidDTO struct {
+	BidID     uuid.UUID `json:"bid_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Paddle    int       `json:"paddle"`
+	Amount    float64   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+	// ReceivedAt is when the server received the bid, the time its lot end was adjudicated on
+	ReceivedAt *time.Time `json:"received_at,omitempty"`
+	Winning    bool       `json:"winning"`
+	VoidedAt   *time.Time `json:"voided_at,omitempty"`
+	VoidReason string     `json:"void_reason,omitempty"`
+}
+
+// LotExportWriter encodes an export as it's read, one bid at a time
+type LotExportWriter interface {
+	WriteBid(bid *ExportedBidDTO) error
+}
+
+// LotEventWriter encodes the events of a lot as they're read, one at a time
+type LotEventWriter interface {
+	WriteEvent(event *domain.Event) error
+}
+
+// LotExportUseCase exports the result and the bids of a lot for back-office reconciliation, and its event
+// history for offline analysis. bids and events are read in pages so an export never holds all of them
+// in memory
+type LotExportUseCase struct {
+	lotRepo    domain.AuctionLotRepository
+	bidRepo    domain.BidRepository
+	exportRepo domain.BidExportRepository
+	paddleRepo domain.PaddleRepository
+	eventStore domain.LotEventStore
+}
+
+// NewLotExportUseCase creates a new instance of LotExportUseCase
+func NewLotExportUseCase(lotRepo domain.AuctionLotRepository, bidRepo domain.BidRepository,
+	exportRepo domain.BidExportRepository, paddleRepo domain.PaddleRepository, eventStore domain.LotEventStore) *LotExportUseCase {
+	return &LotExportUseCase{lotRepo: lotRepo, bidRepo: bidRepo, exportRepo: exportRepo, paddleRepo: paddleRepo, eventStore: eventStore}
+}
+
+// Result returns the outcome of the lot, the first part of its export
+func (uc *LotExportUseCase) Result(ctx context.Context, lotID uuid.UUID) (*LotResultDTO, error) {
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot export use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	result := &LotResultDTO{
+		LotID:        lot.ID,
+		Title:        lot.Title,
+		State:        string(lot.State),
+		Currency:     lot.Currency,
+		InitialPrice: lot.InitialPrice,
+		FinalPrice:   lot.CurrentPrice,
+		EndTime:      lot.EndTime,
+	}
+	if lot.State != domain.StateFinished {
+		return result, nil
+	}
+	// like FinalizeLot, the latest valid bid is the winning one, or the standing ones of a multi-unit lot
+	var winningBid *domain.Bid
+	if lot.MultiUnit() {
+		var standing []*domain.Bid
+		if standing, err = uc.bidRepo.GetStandingBids(ctx, lotID, lot.Quantity); err != nil {
+			return nil, fmt.Errorf("lot export use case: failed to get winning bids of lot %s: %w", lotID, err)
+		}
+		for _, bid := range standing {
+			result.WinningBidIDs = append(result.WinningBidIDs, bid.ID)
+		}
+		if len(standing) > 0 {
+			winningBid = standing[0]
+		}
+	} else {
+		winningBid, err = uc.bidRepo.GetLatestBidByLotID(ctx, lotID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lot export use case: failed to get winning bid of lot %s: %w", lotID, err)
+	}
+	if winningBid != nil {
+		if result.WinnerPaddle, err = uc.paddleRepo.Get(ctx, lotID, winningBid.UserID); err != nil {
+			return nil, fmt.Errorf("lot export use case: failed to get paddle of the winner of lot %s: %w", lotID, err)
+		}
+		result.WinningBidID = &winningBid.ID
+		result.WinnerID = &winningBid.UserID
+	}
+	return result, nil
+}
+
+// wins reports if the bid won the lot of the result, or a unit of it
+func (r *LotResultDTO) wins(bidID uuid.UUID) bool {
+	if len(r.WinningBidIDs) > 0 {
+		return slices.Contains(r.WinningBidIDs, bidID)
+	}
+	return r.WinningBidID != nil && *r.WinningBidID == bidID
+}
+
+// WriteBids writes every bid of the lot of result to w, oldest first
+func (uc *LotExportUseCase) WriteBids(ctx context.Context, result *LotResultDTO, w LotExportWriter) error {
+	var cursor domain.BidCursor
+	for {
+		bids, err := uc.exportRepo.GetLotBidsAfter(ctx, result.LotID, cursor, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("lot export use case: failed to get bids of lot %s: %w", result.LotID, err)
+		}
+		for _, bid := range bids {
+			err := w.WriteBid(&ExportedBidDTO{
+				BidID:      bid.ID,
+				UserID:     bid.UserID,
+				Paddle:     bid.Paddle,
+				Amount:     bid.Amount,
+				Timestamp:  bid.Timestamp,
+				ReceivedAt: bid.ReceivedAt,
+				Winning:    result.wins(bid.ID),
+				VoidedAt:   bid.VoidedAt,
+				VoidReason: bid.VoidReason,
+			})
+			if err != nil {
+				return fmt.Errorf("lot export use case: failed to write bid %s: %w", bid.ID, err)
+			}
+		}
+		if len(bids) < exportPageSize {
+			return nil
+		}
+		last := bids[len(bids)-1]
+		cursor = domain.BidCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+}
+
+// CheckLot returns domain.ErrLotNotFound if the lot doesn't exist or isn't visible in ctx, so an event
+// export can fail before its body starts
+func (uc *LotExportUseCase) CheckLot(ctx context.Context, lotID uuid.UUID) error {
+	if _, err := uc.lotRepo.GetByID(ctx, lotID); err != nil {
+		return fmt.Errorf("lot export use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	return nil
+}
+
+// WriteEvents writes the stored events of the lot with a seq greater than afterSeq to w, ordered by seq
+func (uc *LotExportUseCase) WriteEvents(ctx context.Context, lotID uuid.UUID, afterSeq int64, w LotEventWriter) error {
+	for {
+		events, err := uc.eventStore.GetSince(ctx, lotID, afterSeq, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("lot export use case: failed to get events of lot %s: %w", lotID, err)
+		}
+		for i := range events {
+			if err := w.WriteEvent(&events[i]); err != nil {
+				return fmt.Errorf("lot export use case: failed to write event %d: %w", events[i].Seq, err)
+			}
+		}
+		if len(events) < exportPageSize {
+			return nil
+		}
+		afterSeq = events[len(events)-1].Seq
+	}
+}
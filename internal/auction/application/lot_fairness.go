@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// BidFairness configures the fairness queue of the lots about to close. in the last Window of a lot its
+// actor stops running the bids as they reach it: it holds them for Hold, orders them by the time the
+// server received them (ties by the order they were queued) and runs them one at a time in that order.
+// a finish is decided by the receipt order instead of the scheduling of the connections, workers and
+// REST handlers delivering the bids. a zero Window disables it
+type BidFairness struct {
+	Window time.Duration
+	// Hold bounds how late a bid can reach the actor and still be ordered before the bids received after it
+	Hold time.Duration
+}
+
+// BidFairnessStats counts the bids run through the fairness queue since start
+type BidFairnessStats struct {
+	// Rounds is the number of held groups of bids, Bids the bids in them
+	Rounds int64 `json:"rounds"`
+	Bids   int64 `json:"bids"`
+	// Reordered is the number of bids run in another position than the one they reached the actor
+	Reordered int64 `json:"reordered"`
+}
+
+type fairnessCounters struct {
+	rounds    atomic.Int64
+	bids      atomic.Int64
+	reordered atomic.Int64
+}
+
+// WithFairness returns the queue running the bids of the lots closing within fairness.Window in receipt
+// order, the lots end times are read with lotEnd until the published lot states report them, see ObserveEnd
+func (q *LotCommandQueue) WithFairness(fairness BidFairness, clock domain.Clock) *LotCommandQueue {
+	q.fairness = fairness
+	q.clock = clock
+	return q
+}
+
+// FairnessStats returns the counters of the fairness queue
+func (q *LotCommandQueue) FairnessStats() BidFairnessStats {
+	return BidFairnessStats{
+		Rounds:    q.fairnessCounters.rounds.Load(),
+		Bids:      q.fairnessCounters.bids.Load(),
+		Reordered: q.fairnessCounters.reordered.Load(),
+	}
+}
+
+// ObserveEnd records the current end time of a lot with a running actor, called with every published
+// lot state so the actor follows the extensions and pauses
+func (q *LotCommandQueue) ObserveEnd(lotID uuid.UUID, end time.Time) {
+	q.mu.Lock()
+	if _, ok := q.actors[lotID]; ok {
+		q.ends[lotID] = end
+	}
+	q.mu.Unlock()
+}
+
+// closing reports if the lot is in its fairness window, its end time is loaded the first time it's needed
+func (q *LotCommandQueue) closing(ctx context.Context, lotID uuid.UUID) bool {
+	if q.fairness.Window <= 0 || q.lotEnd == nil {
+		return false
+	}
+	q.mu.Lock()
+	end, ok := q.ends[lotID]
+	q.mu.Unlock()
+	if !ok {
+		var err error
+		if end, err = q.lotEnd(ctx, lotID); err != nil {
+			// unknown lots and read failures keep the arrival order, the bid fails on its own
+			return false
+		}
+		q.ObserveEnd(lotID, end)
+	}
+	return !q.clock.Now().Before(end.Add(-q.fairness.Window))
+}
+
+// runFairBids holds the bids reaching the actor for fairness.Hold after first, then runs them in receipt
+// order. holding stops at the first command that is not a bid, which is returned to be run right after
+func (q *LotCommandQueue) runFairBids(first *lotCommand, queue chan *lotCommand) (next *lotCommand) {
+	held := []*lotCommand{first}
+	hold := time.NewTimer(q.fairness.Hold)
+	defer hold.Stop()
+collect:
+	for len(held) < cap(queue) {
+		select {
+		case cmd := <-queue:
+			if cmd.bid == nil {
+				next = cmd
+				break collect
+			}
+			held = append(held, cmd)
+		case <-hold.C:
+			break collect
+		}
+	}
+
+	arrival := make(map[*lotCommand]int, len(held))
+	for i, cmd := range held {
+		arrival[cmd] = i
+		// the held bids run later than they were received, they're adjudicated on their receipt
+		if cmd.bid.ReceivedAt.IsZero() {
+			cmd.bid.ReceivedAt = cmd.queuedAt
+		}
+	}
+	sort.SliceStable(held, func(i, j int) bool {
+		a, b := held[i], held[j]
+		if !a.bid.ReceivedAt.Equal(b.bid.ReceivedAt) {
+			return a.bid.ReceivedAt.Before(b.bid.ReceivedAt)
+		}
+		return a.seq < b.seq
+	})
+
+	q.fairnessCounters.rounds.Add(1)
+	q.fairnessCounters.bids.Add(int64(len(held)))
+	for i, cmd := range held {
+		if arrival[cmd] != i {
+			q.fairnessCounters.reordered.Add(1)
+		}
+		q.finish(cmd, q.run(cmd))
+	}
+	return next
+}
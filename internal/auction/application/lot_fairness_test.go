@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// TestFairnessQueueReceiptOrder checks the bids reaching a closing lot out of order run in the order they
+// were received, and the same bids run as they arrive when the lot is far from its end
+func TestFairnessQueueReceiptOrder(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		endsIn time.Duration
+		want   []int
+	}{
+		{"closing lot runs in receipt order", 5 * time.Second, []int{1, 2, 3}},
+		{"open lot runs in arrival order", time.Hour, []int{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewLotCommandQueue(16, time.Minute, BidBatching{}).
+				WithFairness(BidFairness{Window: 10 * time.Second, Hold: 20 * time.Millisecond}, domain.NewManualClock(now))
+			q.lotEnd = func(context.Context, uuid.UUID) (time.Time, error) { return now.Add(tt.endsIn), nil }
+			lotID := uuid.New()
+			ctx := context.Background()
+
+			// a running command keeps the actor busy while the bids queue up behind it
+			release := make(chan struct{})
+			go q.Do(ctx, lotID, func(context.Context) error { <-release; return nil })
+			waitPending(t, q, 1)
+
+			var mu sync.Mutex
+			var order []int
+			var wg sync.WaitGroup
+			// the bid received 3rd reaches the queue first
+			for i, received := range []int{3, 1, 2} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					cmd := PlaceBidDTO{LotID: lotID, ReceivedAt: now.Add(time.Duration(received) * time.Millisecond)}
+					q.doBid(ctx, cmd, func(context.Context, PlaceBidDTO) (*domain.Bid, error) {
+						mu.Lock()
+						order = append(order, received)
+						mu.Unlock()
+						return nil, nil
+					})
+				}()
+				waitPending(t, q, int64(i+2))
+			}
+			close(release)
+			wg.Wait()
+
+			for i := range tt.want {
+				if order[i] != tt.want[i] {
+					t.Fatalf("bids ran in order %v, want %v", order, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func waitPending(t *testing.T, q *LotCommandQueue, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for q.pending.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d commands pending, want %d", q.pending.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
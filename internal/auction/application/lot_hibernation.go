@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotCache is an in-memory structure holding per lot state that can be rebuilt from the database,
+// CachedLots lists the lots it holds and Invalidate drops one of them
+type LotCache interface {
+	CachedLots() []uuid.UUID
+	Invalidate(lotID uuid.UUID)
+}
+
+// LotHibernationStats counts the lots kept in memory and the ones hibernated since start
+type LotHibernationStats struct {
+	// Tracked is the number of lots held by some cache at the last sweep
+	Tracked    int64 `json:"tracked"`
+	Hibernated int64 `json:"hibernated"`
+}
+
+// LotHibernator evicts the cached state of the lots nobody is connected to and nobody bid on for
+// idleAfter, so memory grows with the active lots instead of every lot ever touched. the caches load
+// a lot again on their next miss, usually the first connection or bid after it hibernated. the lot
+// actors and subscriptions are not evicted here, they already stop when idle or unused
+type LotHibernator struct {
+	updates   *LotUpdateBroker
+	clock     domain.Clock
+	idleAfter time.Duration
+	// connected reports if the lot has local watchers, they keep it awake
+	connected func(lotID uuid.UUID) bool
+	caches    []LotCache
+
+	mu         sync.Mutex
+	lastActive map[uuid.UUID]time.Time
+
+	tracked    atomic.Int64
+	hibernated atomic.Int64
+}
+
+// NewLotHibernator creates a new instance of LotHibernator evicting the lots of caches idle for idleAfter
+func NewLotHibernator(updates *LotUpdateBroker, clock domain.Clock, idleAfter time.Duration,
+	connected func(lotID uuid.UUID) bool, caches ...LotCache) *LotHibernator {
+	return &LotHibernator{
+		updates:    updates,
+		clock:      clock,
+		idleAfter:  idleAfter,
+		connected:  connected,
+		caches:     caches,
+		lastActive: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Run records the lot changes as activity and sweeps the caches every interval until ctx is done
+func (h *LotHibernator) Run(ctx context.Context, interval time.Duration) {
+	updates, cancel := h.updates.SubscribeAll()
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	log.Info("LotHibernator started", zap.Duration("idleAfter", h.idleAfter), zap.Duration("interval", interval))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("LotHibernator stopped")
+			return
+		case state, ok := <-updates:
+			if !ok {
+				return
+			}
+			h.Touch(state.LotID)
+		case <-ticker.C:
+			h.Sweep()
+		}
+	}
+}
+
+// Touch marks activity on the lot, it stays in memory for idleAfter at least
+func (h *LotHibernator) Touch(lotID uuid.UUID) {
+	h.mu.Lock()
+	h.lastActive[lotID] = h.clock.Now()
+	h.mu.Unlock()
+}
+
+// Sweep evicts the lots idle for idleAfter from every cache and returns how many were evicted. a lot first
+// seen by a sweep counts as active from then, the caches don't tell when they loaded it
+func (h *LotHibernator) Sweep() int {
+	now := h.clock.Now()
+	cached := make(map[uuid.UUID]struct{})
+	for _, cache := range h.caches {
+		for _, lotID := range cache.CachedLots() {
+			cached[lotID] = struct{}{}
+		}
+	}
+	h.tracked.Store(int64(len(cached)))
+
+	var idle []uuid.UUID
+	h.mu.Lock()
+	for lotID := range cached {
+		last, ok := h.lastActive[lotID]
+		if !ok || h.connected(lotID) {
+			h.lastActive[lotID] = now
+			continue
+		}
+		if now.Sub(last) >= h.idleAfter {
+			idle = append(idle, lotID)
+			delete(h.lastActive, lotID)
+		}
+	}
+	// the activity of the lots no cache holds anymore is only kept while it matters
+	for lotID, last := range h.lastActive {
+		if _, ok := cached[lotID]; !ok && now.Sub(last) >= h.idleAfter {
+			delete(h.lastActive, lotID)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, lotID := range idle {
+		for _, cache := range h.caches {
+			cache.Invalidate(lotID)
+		}
+	}
+	if len(idle) > 0 {
+		h.hibernated.Add(int64(len(idle)))
+		log.Debug("LotHibernator: lots hibernated", zap.Int("lots", len(idle)), zap.Int("tracked", len(cached)))
+	}
+	return len(idle)
+}
+
+// Stats returns the counters of the hibernated lots
+func (h *LotHibernator) Stats() LotHibernationStats {
+	return LotHibernationStats{
+		Tracked:    h.tracked.Load(),
+		Hibernated: h.hibernated.Load(),
+	}
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+type fakeLotCache map[uuid.UUID]bool
+
+func (c fakeLotCache) CachedLots() []uuid.UUID {
+	var lots []uuid.UUID
+	for lotID := range c {
+		lots = append(lots, lotID)
+	}
+	return lots
+}
+
+func (c fakeLotCache) Invalidate(lotID uuid.UUID) { delete(c, lotID) }
+
+// TestLotHibernatorSweep checks only the lots without watchers nor activity for idleAfter are evicted
+func TestLotHibernatorSweep(t *testing.T) {
+	clock := domain.NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	idle, watched, bidOn := uuid.New(), uuid.New(), uuid.New()
+	cache := fakeLotCache{idle: true, watched: true, bidOn: true}
+	h := NewLotHibernator(NewLotUpdateBroker(), clock, 10*time.Minute,
+		func(lotID uuid.UUID) bool { return lotID == watched }, cache)
+
+	// the first sweep only starts tracking the lots
+	if n := h.Sweep(); n != 0 {
+		t.Fatalf("first sweep evicted %d lots, want 0", n)
+	}
+	clock.Advance(9 * time.Minute)
+	h.Touch(bidOn)
+	clock.Advance(time.Minute)
+	if n := h.Sweep(); n != 1 {
+		t.Fatalf("sweep evicted %d lots, want 1", n)
+	}
+	lots := cache.CachedLots()
+	if slices.Contains(lots, idle) || !slices.Contains(lots, watched) || !slices.Contains(lots, bidOn) {
+		t.Fatalf("cache holds %v after the sweep, want the watched and bid on lots", lots)
+	}
+	if stats := h.Stats(); stats.Tracked != 3 || stats.Hibernated != 1 {
+		t.Fatalf("stats %+v, want 3 tracked and 1 hibernated", stats)
+	}
+}
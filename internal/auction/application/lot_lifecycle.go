@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotTransitionResult is the output of the LotLifecycleUseCase operations
+type LotTransitionResult struct {
+	Lot *domain.AuctionLot
+	// Events are the lot events stored with the transition, with their Seq assigned
+	Events []domain.Event
+}
+
+// LotLifecycleUseCase starts, pauses, resumes and cancels auction lots, operations used by admins and auctioneers
+type LotLifecycleUseCase struct {
+	lotRepo      domain.AuctionLotRepository
+	eventStore   domain.LotEventStore
+	reservations domain.BidReservationRepository
+	transactor   domain.Transactor
+	clock        domain.Clock
+}
+
+// NewLotLifecycleUseCase creates a new instance of LotLifecycleUseCase
+func NewLotLifecycleUseCase(lotRepo domain.AuctionLotRepository,
+	eventStore domain.LotEventStore,
+	reservations domain.BidReservationRepository,
+	transactor domain.Transactor,
+	clock domain.Clock) *LotLifecycleUseCase {
+	return &LotLifecycleUseCase{
+		lotRepo:      lotRepo,
+		eventStore:   eventStore,
+		reservations: reservations,
+		transactor:   transactor,
+		clock:        clock,
+	}
+}
+
+// Start opens a pending lot for bidding, it returns domain.ErrLotAlreadyStartedOrFinished otherwise
+func (uc *LotLifecycleUseCase) Start(ctx context.Context, lotID uuid.UUID) (*LotTransitionResult, error) {
+	return uc.transition(ctx, lotID, "start", func(lot *domain.AuctionLot) (domain.Event, error) {
+		if err := lot.Start(); err != nil {
+			return domain.Event{}, err
+		}
+		return domain.NewEvent(domain.EventLotStarted, lot.ID, uc.clock.Now(), domain.LotStartedPayload{
+			InitialPrice: lot.InitialPrice,
+			EndTime:      lot.EndTime,
+		}), nil
+	})
+}
+
+// Pause suspends the bidding of an active lot, it returns domain.ErrLotNotActive otherwise
+func (uc *LotLifecycleUseCase) Pause(ctx context.Context, lotID, pausedBy uuid.UUID, reason string) (*LotTransitionResult, error) {
+	return uc.transition(ctx, lotID, "pause", func(lot *domain.AuctionLot) (domain.Event, error) {
+		if err := lot.Pause(); err != nil {
+			return domain.Event{}, err
+		}
+		return domain.NewEvent(domain.EventLotPaused, lot.ID, uc.clock.Now(), domain.LotPausedPayload{
+			PausedBy: pausedBy,
+			Reason:   strings.TrimSpace(reason),
+		}), nil
+	})
+}
+
+// Resume reopens the bidding of a paused lot, it returns domain.ErrLotNotPaused otherwise
+func (uc *LotLifecycleUseCase) Resume(ctx context.Context, lotID, resumedBy uuid.UUID) (*LotTransitionResult, error) {
+	return uc.transition(ctx, lotID, "resume", func(lot *domain.AuctionLot) (domain.Event, error) {
+		if err := lot.Resume(); err != nil {
+			return domain.Event{}, err
+		}
+		return domain.NewEvent(domain.EventLotResumed, lot.ID, uc.clock.Now(), domain.LotResumedPayload{
+			ResumedBy: resumedBy,
+			EndTime:   lot.EndTime,
+		}), nil
+	})
+}
+
+// Cancel cancels a pending or active lot, it returns domain.ErrLotAlreadyFinishedOrCancelled otherwise
+func (uc *LotLifecycleUseCase) Cancel(ctx context.Context, lotID uuid.UUID) (*LotTransitionResult, error) {
+	return uc.transition(ctx, lotID, "cancel", func(lot *domain.AuctionLot) (domain.Event, error) {
+		previous := lot.State
+		if err := lot.Cancel(); err != nil {
+			return domain.Event{}, err
+		}
+		return domain.NewEvent(domain.EventLotCancelled, lot.ID, uc.clock.Now(), domain.LotCancelledPayload{
+			PreviousState: previous,
+		}), nil
+	})
+}
+
+// transition loads the lot, applies change and stores the lot with the resulting event in a single TX
+func (uc *LotLifecycleUseCase) transition(ctx context.Context, lotID uuid.UUID, action string,
+	change func(lot *domain.AuctionLot) (domain.Event, error)) (res *LotTransitionResult, err error) {
+
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s lot use case: failed to begin transaction: %w", action, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			res, err = nil, fmt.Errorf("%s lot use case: failed to commit transaction: %w", action, commitErr)
+		}
+	}()
+
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("%s lot use case: failed to get auction lot %s: %w", action, lotID, err)
+	}
+	event, err := change(lot)
+	if err != nil {
+		return nil, fmt.Errorf("%s lot use case: %w", action, err)
+	}
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, fmt.Errorf("%s lot use case: failed to save auction lot %s: %w", action, lotID, err)
+	}
+	// a cancelled lot has no winner, the leading bid gives its reserved limit back
+	if lot.State == domain.StateCancelled {
+		if err = uc.reservations.ReleaseLot(ctx, tx, lotID); err != nil {
+			return nil, fmt.Errorf("%s lot use case: failed to release reservations of lot %s: %w", action, lotID, err)
+		}
+	}
+	events, err := uc.eventStore.Append(ctx, tx, lotID, event)
+	if err != nil {
+		return nil, fmt.Errorf("%s lot use case: failed to append event for lot %s: %w", action, lotID, err)
+	}
+
+	for _, change := range lot.TakeStateChanges() {
+		logger.FromContext(ctx).Info("LotLifecycleUseCase: lot state changed",
+			zap.String("lotID", lotID.String()),
+			zap.String("transition", string(change.Transition)),
+			zap.String("from", string(change.From)),
+			zap.String("to", string(change.To)),
+		)
+	}
+	return &LotTransitionResult{Lot: lot, Events: events}, nil
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MediaStorage stores uploaded media files, implemented by S3-compatible storages in infra
+type MediaStorage interface {
+	// Upload stores the content under key and returns its public URL
+	Upload(ctx context.Context, key, contentType string, body io.Reader, size int64) (string, error)
+}
+
+// LotMediaDTO is the output DTO of a lot media attachment
+type LotMediaDTO struct {
+	ID       uuid.UUID `json:"id"`
+	Kind     string    `json:"kind"`
+	URL      string    `json:"url"`
+	Position int       `json:"position"`
+}
+
+// AttachMediaDTO is the input for attaching an already hosted image/video to a lot
+type AttachMediaDTO struct {
+	LotID uuid.UUID
+	Kind  string
+	URL   string
+}
+
+// UploadMediaDTO is the input for uploading a media file to the storage and attaching it to a lot
+type UploadMediaDTO struct {
+	LotID       uuid.UUID
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// LotMediaUseCase manages the images and videos attached to lots
+type LotMediaUseCase struct {
+	lotRepo   domain.AuctionLotRepository
+	mediaRepo domain.LotMediaRepository
+	storage   MediaStorage // nil disables uploads, URL attachments keep working
+}
+
+// NewLotMediaUseCase creates a new instance of LotMediaUseCase, storage may be nil
+func NewLotMediaUseCase(lotRepo domain.AuctionLotRepository, mediaRepo domain.LotMediaRepository, storage MediaStorage) *LotMediaUseCase {
+	return &LotMediaUseCase{
+		lotRepo:   lotRepo,
+		mediaRepo: mediaRepo,
+		storage:   storage,
+	}
+}
+
+// Attach adds an image/video URL to the lot
+func (uc *LotMediaUseCase) Attach(ctx context.Context, cmd AttachMediaDTO) (*LotMediaDTO, error) {
+	kind := domain.MediaKind(cmd.Kind)
+	if !kind.Valid() {
+		return nil, fmt.Errorf("%w: kind must be image or video", ErrInvalidMedia)
+	}
+	if u, err := url.Parse(cmd.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidMedia)
+	}
+	if _, err := uc.lotRepo.GetByID(ctx, cmd.LotID); err != nil {
+		return nil, fmt.Errorf("lot media use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	return uc.add(ctx, cmd.LotID, kind, cmd.URL)
+}
+
+// Upload stores the file in the media storage and attaches it to the lot, the kind is taken from the content type
+func (uc *LotMediaUseCase) Upload(ctx context.Context, cmd UploadMediaDTO) (*LotMediaDTO, error) {
+	if uc.storage == nil {
+		return nil, ErrMediaUploadDisabled
+	}
+	var kind domain.MediaKind
+	switch {
+	case strings.HasPrefix(cmd.ContentType, "image/"):
+		kind = domain.MediaImage
+	case strings.HasPrefix(cmd.ContentType, "video/"):
+		kind = domain.MediaVideo
+	default:
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrInvalidMedia, cmd.ContentType)
+	}
+	if _, err := uc.lotRepo.GetByID(ctx, cmd.LotID); err != nil {
+		return nil, fmt.Errorf("lot media use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+
+	key := fmt.Sprintf("lots/%s/%s%s", cmd.LotID, uuid.NewString(), strings.ToLower(path.Ext(cmd.Filename)))
+	mediaURL, err := uc.storage.Upload(ctx, key, cmd.ContentType, cmd.Body, cmd.Size)
+	if err != nil {
+		return nil, fmt.Errorf("lot media use case: failed to upload %s: %w", key, err)
+	}
+	return uc.add(ctx, cmd.LotID, kind, mediaURL)
+}
+
+// List returns the media of a lot in display order
+func (uc *LotMediaUseCase) List(ctx context.Context, lotID uuid.UUID) ([]LotMediaDTO, error) {
+	media, err := uc.mediaRepo.GetByLotID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot media use case: failed to get media of lot %s: %w", lotID, err)
+	}
+	return toLotMediaDTOs(media), nil
+}
+
+// Remove detaches a media from the lot, uploaded files are kept in the storage
+func (uc *LotMediaUseCase) Remove(ctx context.Context, lotID, mediaID uuid.UUID) error {
+	if err := uc.mediaRepo.Delete(ctx, lotID, mediaID); err != nil {
+		return fmt.Errorf("lot media use case: failed to delete media %s: %w", mediaID, err)
+	}
+	return nil
+}
+
+// add persists the media of an existing lot
+func (uc *LotMediaUseCase) add(ctx context.Context, lotID uuid.UUID, kind domain.MediaKind, mediaURL string) (*LotMediaDTO, error) {
+	media := &domain.LotMedia{
+		ID:    uuid.New(),
+		LotID: lotID,
+		Kind:  kind,
+		URL:   mediaURL,
+	}
+	if err := uc.mediaRepo.Add(ctx, media); err != nil {
+		return nil, fmt.Errorf("lot media use case: failed to save media of lot %s: %w", lotID, err)
+	}
+	logger.FromContext(ctx).Info("LotMediaUseCase: media attached",
+		zap.String("lotID", lotID.String()),
+		zap.String("mediaID", media.ID.String()),
+		zap.String("kind", string(kind)),
+	)
+	dto := toLotMediaDTO(media)
+	return &dto, nil
+}
+
+func toLotMediaDTO(m *domain.LotMedia) LotMediaDTO {
+	return LotMediaDTO{
+		ID:       m.ID,
+		Kind:     string(m.Kind),
+		URL:      m.URL,
+		Position: m.Position,
+	}
+}
+
+func toLotMediaDTOs(media []*domain.LotMedia) []LotMediaDTO {
+	dtos := make([]LotMediaDTO, 0, len(media))
+	for _, m := range media {
+		dtos = append(dtos, toLotMediaDTO(m))
+	}
+	return dtos
+}
@@ -0,0 +1,492 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/expr"
+	"github.com/cristianortiz/auctionEngine/internal/shared/geoip"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidLotRules is returned when setting a lot rule that doesn't compile
+var ErrInvalidLotRules = errors.New("invalid lot rules")
+
+// LotRuleVars are the variables the lot rules can read, the amounts are in the lot currency and
+// increment is the one of the increment table
+var LotRuleVars = []string{
+	"amount", "current_price", "initial_price", "increment", "has_bids", "seconds_left", "lot_type",
+	"bidder.registered_hours",
+}
+
+// LotRulesDTO is the output DTO of the rules of a lot
+type LotRulesDTO struct {
+	LotID            uuid.UUID `json:"lot_id"`
+	MinIncrement     string    `json:"min_increment"`
+	Eligibility      string    `json:"eligibility"`
+	AllowedCountries []string  `json:"allowed_countries"`
+	BlockedCountries []string  `json:"blocked_countries"`
+	TermsVersion     string    `json:"terms_version"`
+	RequireVerified  bool      `json:"require_verified"`
+	ReservePrice     float64   `json:"reserve_price"`
+	UpdatedBy        uuid.UUID `json:"updated_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// SetLotRulesDTO is the input DTO to set the rules of a lot, an empty rule keeps the default behavior
+type SetLotRulesDTO struct {
+	MinIncrement string `json:"min_increment"`
+	Eligibility  string `json:"eligibility"`
+	// AllowedCountries and BlockedCountries are ISO 3166-1 alpha-2 codes, see geoip.Restriction
+	AllowedCountries []string `json:"allowed_countries"`
+	BlockedCountries []string `json:"blocked_countries"`
+	// TermsVersion is the version of the terms the bidders must accept before bidding, see AcceptTerms
+	TermsVersion string `json:"terms_version"`
+	// RequireVerified restricts the bids to the bidders with a verified identity, see WithVerification
+	RequireVerified bool `json:"require_verified"`
+	// ReservePrice is the price the seller wants reached, the clients only see if it's met. 0 sets none
+	ReservePrice float64 `json:"reserve_price"`
+}
+
+// LotRulesStats are the counters of the rule evaluations since start
+type LotRulesStats struct {
+	CacheHits   int64 `json:"cache_hits"`
+	CacheMisses int64 `json:"cache_misses"`
+	Evaluations int64 `json:"evaluations"`
+	// Ineligible counts the bids rejected by an eligibility rule, Failures the bids rejected because
+	// a rule failed to evaluate
+	Ineligible int64 `json:"ineligible"`
+	Failures   int64 `json:"failures"`
+	// GeoRejected counts the connections and bids rejected by the country lists, TermsRequired the bids
+	// of the bidders who didn't accept the terms of the lot
+	GeoRejected   int64 `json:"geo_rejected"`
+	TermsRequired int64 `json:"terms_required"`
+	// Unverified counts the bids of unverified bidders on the lots requiring verified ones
+	Unverified int64 `json:"unverified"`
+}
+
+// compiledLotRules are the programs of the rules of a lot, nil for the unset ones, and its country lists
+type compiledLotRules struct {
+	minIncrement *expr.Program
+	eligibility  *expr.Program
+	geo          geoip.Restriction
+	termsVersion string
+	// requireVerified rejects the bids of the unverified bidders
+	requireVerified bool
+	reservePrice    float64
+}
+
+type cachedLotRules struct {
+	rules   *compiledLotRules // nil for a lot without rules
+	expires time.Time
+}
+
+// LotRulesUseCase manages the rules of the lots and evaluates them on the bids. the compiled rules are
+// cached per lot for cacheTTL, a change made through another instance applies once its cache expires
+type LotRulesUseCase struct {
+	lotRepo   domain.AuctionLotRepository
+	rulesRepo domain.LotRulesRepository
+	bidders   domain.BidderProfileProvider
+	clock     domain.Clock
+	cacheTTL  time.Duration
+	// geo resolves the countries of the client IPs for the country lists, see WithGeoIP
+	geo geoip.Resolver
+	// terms records the terms accepted by the bidders, see WithTerms
+	terms domain.TermsAcceptanceRepository
+	// verification tells the verified bidders, see WithVerification
+	verification UserVerificationService
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedLotRules
+	// accepted caches the terms version each user accepted by lot, an acceptance is never withdrawn
+	accepted map[uuid.UUID]map[uuid.UUID]string
+
+	cacheHits     atomic.Int64
+	cacheMisses   atomic.Int64
+	evaluations   atomic.Int64
+	ineligible    atomic.Int64
+	failures      atomic.Int64
+	geoRejected   atomic.Int64
+	termsRequired atomic.Int64
+	unverified    atomic.Int64
+}
+
+// NewLotRulesUseCase creates a new instance of LotRulesUseCase
+func NewLotRulesUseCase(lotRepo domain.AuctionLotRepository, rulesRepo domain.LotRulesRepository,
+	bidders domain.BidderProfileProvider, clock domain.Clock, cacheTTL time.Duration) *LotRulesUseCase {
+	return &LotRulesUseCase{
+		lotRepo:   lotRepo,
+		rulesRepo: rulesRepo,
+		bidders:   bidders,
+		clock:     clock,
+		cacheTTL:  cacheTTL,
+		cache:     make(map[uuid.UUID]cachedLotRules),
+		accepted:  make(map[uuid.UUID]map[uuid.UUID]string),
+	}
+}
+
+// WithGeoIP returns the use case resolving the countries of the client IPs with resolver, without one
+// every client has an unknown country: the lots with an allow list reject everyone
+func (uc *LotRulesUseCase) WithGeoIP(resolver geoip.Resolver) *LotRulesUseCase {
+	uc.geo = resolver
+	return uc
+}
+
+// Get returns the rules of the lot, or domain.ErrLotRulesNotFound
+func (uc *LotRulesUseCase) Get(ctx context.Context, lotID uuid.UUID) (*LotRulesDTO, error) {
+	rules, err := uc.rulesRepo.Get(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: failed to get rules of lot %s: %w", lotID, err)
+	}
+	dto := toLotRulesDTO(rules)
+	return &dto, nil
+}
+
+// Set replaces the rules of the lot, they're rejected with ErrInvalidLotRules if they don't compile
+func (uc *LotRulesUseCase) Set(ctx context.Context, lotID uuid.UUID, input SetLotRulesDTO, updatedBy uuid.UUID) (*LotRulesDTO, error) {
+	allowed, err := geoip.NormalizeCountries(input.AllowedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: %w: allowed_countries: %v", ErrInvalidLotRules, err)
+	}
+	blocked, err := geoip.NormalizeCountries(input.BlockedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: %w: blocked_countries: %v", ErrInvalidLotRules, err)
+	}
+	rules := &domain.LotRules{
+		LotID:            lotID,
+		MinIncrement:     input.MinIncrement,
+		Eligibility:      input.Eligibility,
+		AllowedCountries: allowed,
+		BlockedCountries: blocked,
+		TermsVersion:     strings.TrimSpace(input.TermsVersion),
+		RequireVerified:  input.RequireVerified,
+		ReservePrice:     input.ReservePrice,
+		UpdatedBy:        updatedBy,
+	}
+	if len(rules.TermsVersion) > maxTermsVersionLength {
+		return nil, fmt.Errorf("lot rules use case: %w: terms_version is longer than %d", ErrInvalidLotRules, maxTermsVersionLength)
+	}
+	if _, err := compileLotRules(rules); err != nil {
+		return nil, fmt.Errorf("lot rules use case: %w", err)
+	}
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	if rules.ReservePrice != 0 {
+		if err := domain.ValidateAmount(rules.ReservePrice, lot.Currency); err != nil {
+			return nil, fmt.Errorf("lot rules use case: %w: reserve_price: %v", ErrInvalidLotRules, err)
+		}
+	}
+	if err := uc.rulesRepo.Save(ctx, rules); err != nil {
+		return nil, fmt.Errorf("lot rules use case: failed to save rules of lot %s: %w", lotID, err)
+	}
+	uc.Invalidate(lotID)
+	logger.FromContext(ctx).Info("Lot rules updated",
+		zap.String("lotID", lotID.String()),
+		zap.String("minIncrement", rules.MinIncrement),
+		zap.String("eligibility", rules.Eligibility),
+		zap.Strings("allowedCountries", rules.AllowedCountries),
+		zap.Strings("blockedCountries", rules.BlockedCountries),
+		zap.String("termsVersion", rules.TermsVersion),
+		zap.Bool("requireVerified", rules.RequireVerified),
+		zap.Float64("reservePrice", rules.ReservePrice),
+		zap.String("updatedBy", updatedBy.String()),
+	)
+	dto := toLotRulesDTO(rules)
+	return &dto, nil
+}
+
+// Delete removes the rules of the lot, its bids follow the increment table again
+func (uc *LotRulesUseCase) Delete(ctx context.Context, lotID uuid.UUID) error {
+	if err := uc.rulesRepo.Delete(ctx, lotID); err != nil {
+		return fmt.Errorf("lot rules use case: failed to delete rules of lot %s: %w", lotID, err)
+	}
+	uc.Invalidate(lotID)
+	logger.FromContext(ctx).Info("Lot rules deleted", zap.String("lotID", lotID.String()))
+	return nil
+}
+
+// Stats returns the counters of the rule evaluations
+func (uc *LotRulesUseCase) Stats() LotRulesStats {
+	return LotRulesStats{
+		CacheHits:     uc.cacheHits.Load(),
+		CacheMisses:   uc.cacheMisses.Load(),
+		Evaluations:   uc.evaluations.Load(),
+		Ineligible:    uc.ineligible.Load(),
+		Failures:      uc.failures.Load(),
+		GeoRejected:   uc.geoRejected.Load(),
+		TermsRequired: uc.termsRequired.Load(),
+		Unverified:    uc.unverified.Load(),
+	}
+}
+
+// Apply evaluates the rules of the lot on a bid before the lot rules: it rejects the bid with
+// ErrBidNotAllowed when the bidder isn't eligible, its country is not allowed, it didn't accept the terms
+// of the lot, it's not verified on a lot requiring it or a rule fails, and returns the min increment of the bid, increment (the one of the
+// increment table) unless a rule computes it. a nil use case applies no rules
+func (uc *LotRulesUseCase) Apply(ctx context.Context, lot *domain.AuctionLot, cmd PlaceBidDTO, increment float64) (float64, error) {
+	if uc == nil {
+		return increment, nil
+	}
+	rules, err := uc.compiled(ctx, lot.ID)
+	if err != nil {
+		return 0, err
+	}
+	if rules == nil {
+		return increment, nil
+	}
+	uc.evaluations.Add(1)
+	if err := uc.checkCountry(ctx, lot.ID, rules, cmd.ClientIP); errors.Is(err, geoip.ErrRestricted) {
+		return 0, fmt.Errorf("%w: %w", ErrBidNotAllowed, err)
+	} else if err != nil {
+		return 0, err
+	}
+	if err := uc.checkTerms(ctx, lot.ID, rules, cmd.UserID); err != nil {
+		return 0, err
+	}
+	if err := uc.checkVerified(ctx, lot.ID, rules, cmd.UserID); err != nil {
+		return 0, err
+	}
+	env, err := uc.env(ctx, lot, cmd, increment, rules)
+	if err != nil {
+		return 0, err
+	}
+	if rules.eligibility != nil {
+		eligible, err := rules.eligibility.EvalBool(env)
+		if err != nil {
+			return 0, uc.ruleFailed(ctx, lot.ID, "eligibility", err)
+		}
+		if !eligible {
+			uc.ineligible.Add(1)
+			return 0, fmt.Errorf("%w: the bidder is not eligible for this lot", ErrBidNotAllowed)
+		}
+	}
+	if rules.minIncrement != nil {
+		computed, err := rules.minIncrement.EvalNumber(env)
+		if err != nil {
+			return 0, uc.ruleFailed(ctx, lot.ID, "min_increment", err)
+		}
+		if computed < 0 {
+			return 0, uc.ruleFailed(ctx, lot.ID, "min_increment", fmt.Errorf("negative increment %.2f", computed))
+		}
+		increment = computed
+	}
+	return increment, nil
+}
+
+// CheckConnection rejects with an error wrapping geoip.ErrRestricted the connections to the lot from a
+// country its lists don't allow. a nil use case accepts every connection
+func (uc *LotRulesUseCase) CheckConnection(ctx context.Context, lotID uuid.UUID, clientIP string) error {
+	if uc == nil {
+		return nil
+	}
+	rules, err := uc.compiled(ctx, lotID)
+	if err != nil || rules == nil {
+		return err
+	}
+	return uc.checkCountry(ctx, lotID, rules, clientIP)
+}
+
+// CheckBidder runs the country, terms and verification checks of Apply on a bidder ahead of its bids, e.g.
+// for the absentee bids left before the lot opens, rejecting it with ErrBidNotAllowed. a nil use case
+// accepts every bidder
+func (uc *LotRulesUseCase) CheckBidder(ctx context.Context, lotID, userID uuid.UUID, clientIP string) error {
+	if uc == nil {
+		return nil
+	}
+	rules, err := uc.compiled(ctx, lotID)
+	if err != nil || rules == nil {
+		return err
+	}
+	if err := uc.checkCountry(ctx, lotID, rules, clientIP); errors.Is(err, geoip.ErrRestricted) {
+		return fmt.Errorf("%w: %w", ErrBidNotAllowed, err)
+	} else if err != nil {
+		return err
+	}
+	if err := uc.checkTerms(ctx, lotID, rules, userID); err != nil {
+		return err
+	}
+	return uc.checkVerified(ctx, lotID, rules, userID)
+}
+
+// BiddingTerms returns the min increment of the next bid on the lot as the clients can know it and the
+// reserve price of the lot, 0 without one. the min_increment rule replaces increment unless it reads the
+// bidder or the amount, only known when a bid is placed, or fails: the bid is rejected then anyway
+func (uc *LotRulesUseCase) BiddingTerms(ctx context.Context, lot *domain.AuctionLot, increment float64) (float64, float64, error) {
+	if uc == nil {
+		return increment, 0, nil
+	}
+	rules, err := uc.compiled(ctx, lot.ID)
+	if err != nil || rules == nil {
+		return increment, 0, err
+	}
+	if rules.minIncrement != nil && !rules.minIncrement.Uses("bidder.") && !rules.minIncrement.Uses("amount") {
+		// only the min_increment rule is passed, so no bidder is looked up for the eligibility one
+		env, err := uc.env(ctx, lot, PlaceBidDTO{}, increment, &compiledLotRules{minIncrement: rules.minIncrement})
+		if err != nil {
+			return 0, 0, err
+		}
+		if computed, err := rules.minIncrement.EvalNumber(env); err == nil && computed >= 0 {
+			increment = computed
+		}
+	}
+	return increment, rules.reservePrice, nil
+}
+
+// checkCountry checks the country of clientIP against the lists of the lot, returning geoip.ErrRestricted
+// when it's not allowed. a failure to resolve it rejects the bid or connection like a broken rule
+func (uc *LotRulesUseCase) checkCountry(ctx context.Context, lotID uuid.UUID, rules *compiledLotRules, clientIP string) error {
+	err := rules.geo.Check(ctx, uc.geo, clientIP)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, geoip.ErrRestricted):
+		uc.geoRejected.Add(1)
+		logger.FromContext(ctx).Info("Client rejected by the country lists of the lot",
+			zap.String("lotID", lotID.String()),
+			zap.String("clientIP", clientIP),
+			zap.Error(err),
+		)
+		return err
+	default:
+		return uc.ruleFailed(ctx, lotID, "countries", err)
+	}
+}
+
+// ruleFailed logs the failure of a rule and returns the error the bid is rejected with, a broken rule
+// stops the bids instead of letting them skip it
+func (uc *LotRulesUseCase) ruleFailed(ctx context.Context, lotID uuid.UUID, rule string, err error) error {
+	uc.failures.Add(1)
+	logger.FromContext(ctx).Error("Lot rule failed to evaluate",
+		zap.String("lotID", lotID.String()),
+		zap.String("rule", rule),
+		zap.Error(err),
+	)
+	return fmt.Errorf("%w: the %s rule of the lot failed", ErrBidNotAllowed, rule)
+}
+
+func (uc *LotRulesUseCase) env(ctx context.Context, lot *domain.AuctionLot, cmd PlaceBidDTO, increment float64, rules *compiledLotRules) (map[string]any, error) {
+	now := uc.clock.Now()
+	env := map[string]any{
+		"amount":        cmd.Amount,
+		"current_price": lot.CurrentPrice,
+		"initial_price": lot.InitialPrice,
+		"increment":     increment,
+		"has_bids":      lot.LastBidTime != nil,
+		"seconds_left":  lot.EndTime.Sub(now).Seconds(),
+		"lot_type":      string(lot.Type),
+	}
+	// the bidder is only read for the rules that need it
+	if rules.minIncrement.Uses("bidder.") || rules.eligibility.Uses("bidder.") {
+		profile, err := uc.bidders.GetBidderProfile(ctx, cmd.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bidder profile: %w", err)
+		}
+		env["bidder.registered_hours"] = now.Sub(profile.RegisteredAt).Hours()
+	}
+	return env, nil
+}
+
+// compiled returns the compiled rules of the lot from the cache, nil when it has none
+func (uc *LotRulesUseCase) compiled(ctx context.Context, lotID uuid.UUID) (*compiledLotRules, error) {
+	uc.mu.Lock()
+	entry, ok := uc.cache[lotID]
+	uc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		uc.cacheHits.Add(1)
+		return entry.rules, nil
+	}
+	uc.cacheMisses.Add(1)
+
+	var rules *compiledLotRules
+	stored, err := uc.rulesRepo.Get(ctx, lotID)
+	switch {
+	case errors.Is(err, domain.ErrLotRulesNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get lot rules: %w", err)
+	default:
+		if rules, err = compileLotRules(stored); err != nil {
+			// stored rules are checked on Set, only a change of LotRuleVars breaks them
+			return nil, uc.ruleFailed(ctx, lotID, "stored", err)
+		}
+	}
+	uc.mu.Lock()
+	uc.cache[lotID] = cachedLotRules{rules: rules, expires: time.Now().Add(uc.cacheTTL)}
+	uc.mu.Unlock()
+	return rules, nil
+}
+
+// Invalidate drops the cached rules and terms acceptances of the lot, e.g. after another instance changed them
+func (uc *LotRulesUseCase) Invalidate(lotID uuid.UUID) {
+	uc.mu.Lock()
+	delete(uc.cache, lotID)
+	delete(uc.accepted, lotID)
+	uc.mu.Unlock()
+}
+
+// CachedLots returns the lots with cached rules or terms acceptances, implements LotCache
+func (uc *LotRulesUseCase) CachedLots() []uuid.UUID {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	lots := slices.Collect(maps.Keys(uc.cache))
+	for lotID := range uc.accepted {
+		if _, ok := uc.cache[lotID]; !ok {
+			lots = append(lots, lotID)
+		}
+	}
+	return lots
+}
+
+// compileLotRules compiles the non empty rules, nil when the lot has no rule, country list, terms,
+// verification requirement nor reserve price
+func compileLotRules(stored *domain.LotRules) (*compiledLotRules, error) {
+	geo := geoip.Restriction{Allowed: stored.AllowedCountries, Blocked: stored.BlockedCountries}
+	if stored.MinIncrement == "" && stored.Eligibility == "" && geo.IsZero() && stored.TermsVersion == "" &&
+		!stored.RequireVerified && stored.ReservePrice == 0 {
+		return nil, nil
+	}
+	rules := &compiledLotRules{
+		geo:             geo,
+		termsVersion:    stored.TermsVersion,
+		requireVerified: stored.RequireVerified,
+		reservePrice:    stored.ReservePrice,
+	}
+	var err error
+	if stored.MinIncrement != "" {
+		if rules.minIncrement, err = expr.Compile(stored.MinIncrement, LotRuleVars); err != nil {
+			return nil, fmt.Errorf("%w: min_increment: %v", ErrInvalidLotRules, err)
+		}
+	}
+	if stored.Eligibility != "" {
+		if rules.eligibility, err = expr.Compile(stored.Eligibility, LotRuleVars); err != nil {
+			return nil, fmt.Errorf("%w: eligibility: %v", ErrInvalidLotRules, err)
+		}
+	}
+	return rules, nil
+}
+
+func toLotRulesDTO(rules *domain.LotRules) LotRulesDTO {
+	return LotRulesDTO{
+		LotID:            rules.LotID,
+		MinIncrement:     rules.MinIncrement,
+		Eligibility:      rules.Eligibility,
+		AllowedCountries: rules.AllowedCountries,
+		BlockedCountries: rules.BlockedCountries,
+		TermsVersion:     rules.TermsVersion,
+		RequireVerified:  rules.RequireVerified,
+		ReservePrice:     rules.ReservePrice,
+		UpdatedBy:        rules.UpdatedBy,
+		UpdatedAt:        rules.UpdatedAt,
+	}
+}
@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/geoip"
+	"github.com/google/uuid"
+)
+
+type fakeLotRulesRepo struct {
+	rules map[uuid.UUID]*domain.LotRules
+	gets  int
+}
+
+func (r *fakeLotRulesRepo) Get(ctx context.Context, lotID uuid.UUID) (*domain.LotRules, error) {
+	r.gets++
+	if rules, ok := r.rules[lotID]; ok {
+		return rules, nil
+	}
+	return nil, domain.ErrLotRulesNotFound
+}
+
+func (r *fakeLotRulesRepo) Save(ctx context.Context, rules *domain.LotRules) error {
+	r.rules[rules.LotID] = rules
+	return nil
+}
+
+func (r *fakeLotRulesRepo) Delete(ctx context.Context, lotID uuid.UUID) error {
+	delete(r.rules, lotID)
+	return nil
+}
+
+type fakeBidderProfiles map[uuid.UUID]time.Time
+
+func (f fakeBidderProfiles) GetBidderProfile(ctx context.Context, userID uuid.UUID) (*domain.BidderProfile, error) {
+	return &domain.BidderProfile{UserID: userID, RegisteredAt: f[userID]}, nil
+}
+
+// TestLotRulesApply checks the eligibility rule rejects the new bidders, the increment rule replaces the
+// increment of the table, and the rules are read once while cached
+func TestLotRulesApply(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	veteran, newcomer := uuid.New(), uuid.New()
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{lot.ID: {
+		LotID:        lot.ID,
+		MinIncrement: "max(increment, current_price * 0.05)",
+		Eligibility:  "bidder.registered_hours > 24",
+	}}}
+	profiles := fakeBidderProfiles{veteran: now.Add(-48 * time.Hour), newcomer: now.Add(-time.Hour)}
+	uc := NewLotRulesUseCase(nil, repo, profiles, domain.NewManualClock(now), time.Minute)
+	ctx := context.Background()
+
+	increment, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: veteran, Amount: 1100}, 10)
+	if err != nil {
+		t.Fatalf("veteran bid: %v", err)
+	}
+	if increment != 50 {
+		t.Fatalf("increment = %v, want 5%% of the price", increment)
+	}
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: newcomer, Amount: 1100}, 10); !errors.Is(err, ErrBidNotAllowed) {
+		t.Fatalf("newcomer bid: err = %v, want ErrBidNotAllowed", err)
+	}
+	if repo.gets != 1 {
+		t.Fatalf("rules read %d times, want them cached", repo.gets)
+	}
+	if stats := uc.Stats(); stats.Evaluations != 2 || stats.Ineligible != 1 || stats.CacheHits != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	// a lot without rules keeps the increment of the table
+	other := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	if increment, err := uc.Apply(ctx, other, PlaceBidDTO{UserID: newcomer}, 10); err != nil || increment != 10 {
+		t.Fatalf("lot without rules: increment = %v, err = %v", increment, err)
+	}
+}
+
+// TestLotRulesCountries checks the country lists apply to the connections and the bids, with the
+// GEO_RESTRICTED code
+func TestLotRulesCountries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{lot.ID: {
+		LotID:            lot.ID,
+		AllowedCountries: []string{"GB"},
+	}}}
+	resolver, err := geoip.ParseRanges(strings.NewReader("81.2.69.0/24,GB\n2.125.160.0/19,US\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := NewLotRulesUseCase(nil, repo, fakeBidderProfiles{}, domain.NewManualClock(now), time.Minute).WithGeoIP(resolver)
+	ctx := context.Background()
+
+	if err := uc.CheckConnection(ctx, lot.ID, "81.2.69.10"); err != nil {
+		t.Fatalf("connection from an allowed country: %v", err)
+	}
+	if err := uc.CheckConnection(ctx, lot.ID, "2.125.160.10"); !errors.Is(err, geoip.ErrRestricted) {
+		t.Fatalf("connection from another country: err = %v, want ErrRestricted", err)
+	}
+	if increment, err := uc.Apply(ctx, lot, PlaceBidDTO{ClientIP: "81.2.69.10"}, 10); err != nil || increment != 10 {
+		t.Fatalf("bid from an allowed country: increment = %v, err = %v", increment, err)
+	}
+	_, err = uc.Apply(ctx, lot, PlaceBidDTO{ClientIP: "2.125.160.10"}, 10)
+	if !errors.Is(err, ErrBidNotAllowed) || BidErrorCode(err) != geoip.ErrorCodeGeoRestricted {
+		t.Fatalf("bid from another country: err = %v, code %q, want GEO_RESTRICTED", err, BidErrorCode(err))
+	}
+	// the bids without client IP have an unknown country, the allow list rejects them
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{}, 10); !errors.Is(err, geoip.ErrRestricted) {
+		t.Fatalf("bid without client IP: err = %v, want ErrRestricted", err)
+	}
+	if stats := uc.Stats(); stats.GeoRejected != 3 {
+		t.Fatalf("stats = %+v, want 3 geo rejections", stats)
+	}
+}
+
+type fakeTermsRepo struct {
+	accepted map[string]bool
+	reads    int
+}
+
+func (r *fakeTermsRepo) Save(ctx context.Context, acceptance *domain.TermsAcceptance) error {
+	r.accepted[acceptance.UserID.String()+acceptance.LotID.String()+acceptance.TermsVersion] = true
+	acceptance.AcceptedAt = time.Now()
+	return nil
+}
+
+func (r *fakeTermsRepo) Accepted(ctx context.Context, userID, lotID uuid.UUID, termsVersion string) (bool, error) {
+	r.reads++
+	return r.accepted[userID.String()+lotID.String()+termsVersion], nil
+}
+
+// TestLotRulesTerms checks the bids are rejected with TERMS_REQUIRED until the current terms are accepted
+func TestLotRulesTerms(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{lot.ID: {LotID: lot.ID, TermsVersion: "v2"}}}
+	terms := &fakeTermsRepo{accepted: make(map[string]bool)}
+	uc := NewLotRulesUseCase(nil, repo, fakeBidderProfiles{}, domain.NewManualClock(now), time.Minute).WithTerms(terms)
+	ctx := context.Background()
+	bidder := uuid.New()
+
+	_, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: bidder}, 10)
+	if !errors.Is(err, ErrBidNotAllowed) || BidErrorCode(err) != ErrorCodeTermsRequired {
+		t.Fatalf("bid before accepting: err = %v, code %q, want TERMS_REQUIRED", err, BidErrorCode(err))
+	}
+	if _, err := uc.AcceptTerms(ctx, AcceptTermsDTO{LotID: lot.ID, UserID: bidder, TermsVersion: "v1"}); !errors.Is(err, ErrInvalidTermsVersion) {
+		t.Fatalf("accepting an old version: err = %v, want ErrInvalidTermsVersion", err)
+	}
+	if _, err := uc.AcceptTerms(ctx, AcceptTermsDTO{LotID: lot.ID, UserID: bidder, TermsVersion: "v2"}); err != nil {
+		t.Fatalf("accepting the current version: %v", err)
+	}
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: bidder}, 10); err != nil {
+		t.Fatalf("bid after accepting: %v", err)
+	}
+	if status, err := uc.TermsStatus(ctx, lot.ID, bidder); err != nil || *status != (TermsStatusDTO{Version: "v2", Accepted: true}) {
+		t.Fatalf("terms status = %+v, %v, want v2 accepted", status, err)
+	}
+	if terms.reads != 1 {
+		t.Fatalf("acceptances read %d times, want the accepted one cached", terms.reads)
+	}
+
+	// a new version must be accepted again
+	repo.rules[lot.ID].TermsVersion = "v3"
+	uc.Invalidate(lot.ID)
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: bidder}, 10); !errors.Is(err, ErrTermsRequired) {
+		t.Fatalf("bid after a new version: err = %v, want ErrTermsRequired", err)
+	}
+	if stats := uc.Stats(); stats.TermsRequired != 2 {
+		t.Fatalf("stats = %+v, want 2 bids rejected for the terms", stats)
+	}
+}
+
+type fakeVerification map[uuid.UUID]bool
+
+func (f fakeVerification) IsVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return f[userID], nil
+}
+
+// TestLotRulesRequireVerified checks only the verified bidders can bid on the lots requiring them
+func TestLotRulesRequireVerified(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{lot.ID: {LotID: lot.ID, RequireVerified: true}}}
+	verified, unverified := uuid.New(), uuid.New()
+	uc := NewLotRulesUseCase(nil, repo, fakeBidderProfiles{}, domain.NewManualClock(now), time.Minute)
+	ctx := context.Background()
+
+	// without a verification service nobody is verified
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: verified}, 10); !errors.Is(err, ErrBidNotAllowed) {
+		t.Fatalf("bid without verification service: err = %v, want ErrBidNotAllowed", err)
+	}
+	uc.WithVerification(fakeVerification{verified: true})
+	if _, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: verified}, 10); err != nil {
+		t.Fatalf("bid of a verified bidder: %v", err)
+	}
+	_, err := uc.Apply(ctx, lot, PlaceBidDTO{UserID: unverified}, 10)
+	if !errors.Is(err, ErrBidNotAllowed) || BidErrorCode(err) != ErrorCodeVerificationRequired {
+		t.Fatalf("bid of an unverified bidder: err = %v, code %q, want VERIFICATION_REQUIRED", err, BidErrorCode(err))
+	}
+	if stats := uc.Stats(); stats.Unverified != 1 || stats.Failures != 1 {
+		t.Fatalf("stats = %+v, want 1 unverified bid and 1 failure", stats)
+	}
+}
+
+// TestLotRulesBiddingTerms checks the states get the increment of the min_increment rule unless it reads
+// the bidder, and the reserve price
+func TestLotRulesBiddingTerms(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 1000, EndTime: now.Add(time.Hour)}
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{
+		lot.ID: {LotID: lot.ID, MinIncrement: "current_price * 0.1", Eligibility: "bidder.registered_hours > 1", ReservePrice: 1500},
+	}}
+	uc := NewLotRulesUseCase(nil, repo, nil, domain.NewManualClock(now), time.Minute)
+	ctx := context.Background()
+
+	increment, reserve, err := uc.BiddingTerms(ctx, lot, 10)
+	if err != nil || increment != 100 || reserve != 1500 {
+		t.Fatalf("terms = %v, %v, %v, want the rule increment 100 and reserve 1500", increment, reserve, err)
+	}
+	repo.rules[lot.ID].MinIncrement = "max(increment, bidder.registered_hours)"
+	uc.Invalidate(lot.ID)
+	if increment, _, err = uc.BiddingTerms(ctx, lot, 10); err != nil || increment != 10 {
+		t.Fatalf("increment of a rule reading the bidder = %v, %v, want the table one", increment, err)
+	}
+	if increment, reserve, err = (*LotRulesUseCase)(nil).BiddingTerms(ctx, lot, 10); err != nil || increment != 10 || reserve != 0 {
+		t.Fatalf("terms without rules = %v, %v, %v, want the table increment and no reserve", increment, reserve, err)
+	}
+}
@@ -0,0 +1,219 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// lotTimerKey identifies an armed timer of the scheduler, a lot has at most one of each action
+type lotTimerKey struct {
+	lotID  uuid.UUID
+	action string // open or close
+}
+
+// LotScheduler is the backend timer, it periodically finalizes the active lots whose end time has passed
+// and opens the lots in preview at their start time. lots whose end time can't move anymore (hard close,
+// or extension cap reached) are closed exactly at their end time instead of at the tick after it.
+// the absentee bids of the lots are entered as they open, see WithAbsenteeBids, and the bundles of
+// closed lots are settled, see WithBundles
+type LotScheduler struct {
+	lotRepo        domain.AuctionLotRepository
+	auctionService AuctionService
+	interval       time.Duration
+	clock          domain.Clock
+	// absentee enters the absentee bids of the opened lots, nil without absentee bids
+	absentee *AbsenteeBidUseCase
+	// bundles settles the bundles whose lots closed, nil without bundles
+	bundles *LotBundleUseCase
+
+	mu sync.Mutex
+	// timers are the armed openings and closings due before the next tick
+	timers map[lotTimerKey]*time.Timer
+}
+
+// NewLotScheduler creates a new instance of LotScheduler, interval is wall time while the lot end times
+// are compared with clock
+func NewLotScheduler(lotRepo domain.AuctionLotRepository, auctionService AuctionService, interval time.Duration, clock domain.Clock) *LotScheduler {
+	return &LotScheduler{
+		lotRepo:        lotRepo,
+		auctionService: auctionService,
+		interval:       interval,
+		clock:          clock,
+		timers:         make(map[lotTimerKey]*time.Timer),
+	}
+}
+
+// WithAbsenteeBids returns the scheduler entering the absentee bids of the lots it opens, and at each
+// tick of the active lots opened otherwise (by hand, or by another instance that failed to enter them)
+func (s *LotScheduler) WithAbsenteeBids(absentee *AbsenteeBidUseCase) *LotScheduler {
+	s.absentee = absentee
+	return s
+}
+
+// WithBundles returns the scheduler settling at each tick the bundles whose lots all closed
+func (s *LotScheduler) WithBundles(bundles *LotBundleUseCase) *LotScheduler {
+	s.bundles = bundles
+	return s
+}
+
+// Run executes a tick every interval until ctx is done
+func (s *LotScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.stopTimers()
+	log.Info("LotScheduler started", zap.Duration("interval", s.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("LotScheduler stopped")
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick finalizes every active lot already past its end time, arms the openings and closings due
+// before the next tick, enters the absentee bids left pending on active lots and settles the bundles
+// of the closed lots
+func (s *LotScheduler) tick(ctx context.Context) {
+	s.scheduleClosings(ctx)
+	s.scheduleOpenings(ctx)
+	s.scheduleAbsenteeBids(ctx)
+	s.settleBundles(ctx)
+}
+
+// scheduleClosings finalizes every active lot already past its end time, and arms a timer for the lots
+// with a fixed end time before the next tick
+func (s *LotScheduler) scheduleClosings(ctx context.Context) {
+	now := s.clock.Now()
+	lots, err := s.lotRepo.GetLotsEndingBy(ctx, now.Add(s.interval))
+	if err != nil {
+		log.Error("LotScheduler: failed to get ended lots", zap.Error(err))
+		return
+	}
+	for _, lot := range lots {
+		switch {
+		case !lot.EndTime.After(now):
+			s.finalize(ctx, lot.ID)
+		case lot.Closing.FixedEndTime(lot.EndTime, lot.ScheduledEndTime):
+			lotID := lot.ID
+			s.arm(ctx, lotTimerKey{lotID: lotID, action: "close"}, lot.EndTime.Sub(now), func(ctx context.Context) {
+				s.finalize(ctx, lotID)
+			})
+		}
+		// a soft closed lot may still be extended, it's finalized by the tick after its end time
+	}
+}
+
+// finalize finishes an ended lot, a lot extended or already finalized meanwhile is left alone
+func (s *LotScheduler) finalize(ctx context.Context, lotID uuid.UUID) {
+	// each finalization is traced on its own, like an inbound request
+	ctx = logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+	_, err := s.auctionService.FinalizeLot(ctx, lotID)
+	if err != nil && !errors.Is(err, ErrLotNotEnded) && !errors.Is(err, domain.ErrLotNotActive) {
+		logger.FromContext(ctx).Error("LotScheduler: failed to finalize lot", zap.String("lotID", lotID.String()), zap.Error(err))
+	}
+}
+
+// scheduleOpenings arms a timer for each lot in preview starting before the next tick, so the lot opens
+// at its start time instead of at the tick after it. lots already past their start time open right away
+func (s *LotScheduler) scheduleOpenings(ctx context.Context) {
+	now := s.clock.Now()
+	lots, err := s.lotRepo.GetLotsOpeningBy(ctx, now.Add(s.interval))
+	if err != nil {
+		log.Error("LotScheduler: failed to get opening lots", zap.Error(err))
+		return
+	}
+	for _, lot := range lots {
+		if lot.StartTime == nil {
+			continue
+		}
+		lotID := lot.ID
+		s.arm(ctx, lotTimerKey{lotID: lotID, action: "open"}, lot.StartTime.Sub(now), func(ctx context.Context) {
+			s.open(ctx, lotID)
+		})
+	}
+}
+
+// open starts a lot in preview, the start broadcasts server_lot_opened to its connections
+func (s *LotScheduler) open(ctx context.Context, lotID uuid.UUID) {
+	ctx = logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+	_, err := s.auctionService.StartLot(ctx, lotID)
+	// a lot started by hand or cancelled during its preview is not opened again
+	if err != nil && !errors.Is(err, domain.ErrLotAlreadyStartedOrFinished) {
+		logger.FromContext(ctx).Error("LotScheduler: failed to open lot", zap.String("lotID", lotID.String()), zap.Error(err))
+	}
+	if err == nil {
+		s.enterAbsenteeBids(ctx, lotID)
+	}
+}
+
+// scheduleAbsenteeBids enters the pending absentee bids of the active lots
+func (s *LotScheduler) scheduleAbsenteeBids(ctx context.Context) {
+	if s.absentee == nil {
+		return
+	}
+	lots, err := s.absentee.LotsWithPending(ctx)
+	if err != nil {
+		log.Error("LotScheduler: failed to get lots with absentee bids", zap.Error(err))
+		return
+	}
+	for _, lotID := range lots {
+		s.enterAbsenteeBids(logger.WithCorrelationID(ctx, logger.NewCorrelationID()), lotID)
+	}
+}
+
+// enterAbsenteeBids bids the pending absentee bids of an opened lot
+func (s *LotScheduler) enterAbsenteeBids(ctx context.Context, lotID uuid.UUID) {
+	if s.absentee == nil {
+		return
+	}
+	if err := s.absentee.Enter(ctx, lotID, s.auctionService); err != nil {
+		logger.FromContext(ctx).Error("LotScheduler: failed to enter absentee bids", zap.String("lotID", lotID.String()), zap.Error(err))
+	}
+}
+
+// settleBundles settles the open bundles whose lots all closed
+func (s *LotScheduler) settleBundles(ctx context.Context) {
+	if s.bundles == nil {
+		return
+	}
+	if err := s.bundles.SettleClosed(logger.WithCorrelationID(ctx, logger.NewCorrelationID())); err != nil {
+		log.Error("LotScheduler: failed to settle bundles", zap.Error(err))
+	}
+}
+
+// arm runs fn after delay unless a timer with the same key is already armed
+func (s *LotScheduler) arm(ctx context.Context, key lotTimerKey, delay time.Duration, fn func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, armed := s.timers[key]; armed {
+		return
+	}
+	s.timers[key] = time.AfterFunc(max(delay, 0), func() {
+		s.mu.Lock()
+		delete(s.timers, key)
+		s.mu.Unlock()
+		if ctx.Err() == nil {
+			fn(ctx)
+		}
+	})
+}
+
+// stopTimers disarms the pending timers, the next scheduler run arms them again
+func (s *LotScheduler) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, key)
+	}
+}
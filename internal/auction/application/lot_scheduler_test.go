@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// schedulerLotRepo returns its lots ending by the deadline, the other methods are not used by the scheduler
+type schedulerLotRepo struct {
+	domain.AuctionLotRepository
+	lots []*domain.AuctionLot
+}
+
+func (r *schedulerLotRepo) GetLotsEndingBy(_ context.Context, deadline time.Time) ([]*domain.AuctionLot, error) {
+	var lots []*domain.AuctionLot
+	for _, lot := range r.lots {
+		if !lot.EndTime.After(deadline) {
+			lots = append(lots, lot)
+		}
+	}
+	return lots, nil
+}
+
+func (r *schedulerLotRepo) GetLotsOpeningBy(context.Context, time.Time) ([]*domain.AuctionLot, error) {
+	return nil, nil
+}
+
+// finalizeRecorder records the lots finalized by the scheduler
+type finalizeRecorder struct {
+	AuctionService
+	mu        sync.Mutex
+	finalized []uuid.UUID
+}
+
+func (s *finalizeRecorder) FinalizeLot(_ context.Context, lotID uuid.UUID) (*FinalizeLotResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finalized = append(s.finalized, lotID)
+	return nil, nil
+}
+
+func (s *finalizeRecorder) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.finalized)
+}
+
+// TestLotSchedulerClosings checks which lots a tick finalizes right away, which get a timer closing them
+// at their end time and which are left for a later tick. the end times are relative to the manual clock,
+// the timers run on the wall clock so the armed ones end a few milliseconds later
+func TestLotSchedulerClosings(t *testing.T) {
+	tests := []struct {
+		name    string
+		closing domain.ClosingPolicy
+		endsIn  time.Duration
+		// extended is how far the bids already moved the end time past the scheduled end
+		extended time.Duration
+		// immediate is the finalizations expected right after the tick, timed after the timers fired
+		immediate, timed int
+	}{
+		{"ended soft lot", domain.ClosingPolicy{Mode: domain.ClosingSoft}, -time.Second, 0, 1, 1},
+		{"hard lot ending before the next tick", domain.ClosingPolicy{Mode: domain.ClosingHard}, 50 * time.Millisecond, 0, 0, 1},
+		{"soft lot at its extension cap", domain.ClosingPolicy{Mode: domain.ClosingSoft, MaxExtension: time.Minute}, 50 * time.Millisecond, time.Minute, 0, 1},
+		{"soft lot below its extension cap", domain.ClosingPolicy{Mode: domain.ClosingSoft, MaxExtension: 2 * time.Minute}, 50 * time.Millisecond, time.Minute, 0, 0},
+		{"soft lot ending before the next tick", domain.ClosingPolicy{Mode: domain.ClosingSoft}, 50 * time.Millisecond, 0, 0, 0},
+		{"hard lot ending after the next tick", domain.ClosingPolicy{Mode: domain.ClosingHard}, time.Hour, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := domain.NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+			lot := domain.NewAuctionLot(uuid.New(), "lot", "", 100, clock.Now().Add(tt.endsIn), 0)
+			lot.State = domain.StateActive
+			lot.Closing = tt.closing
+			lot.ScheduledEndTime = lot.EndTime.Add(-tt.extended)
+			service := &finalizeRecorder{}
+			scheduler := NewLotScheduler(&schedulerLotRepo{lots: []*domain.AuctionLot{lot}}, service, time.Second, clock)
+			defer scheduler.stopTimers()
+
+			scheduler.tick(context.Background())
+			if got := service.count(); got != tt.immediate {
+				t.Fatalf("finalized after the tick = %d, want %d", got, tt.immediate)
+			}
+			time.Sleep(200 * time.Millisecond)
+			if got := service.count(); got != tt.timed {
+				t.Fatalf("finalized after the timers = %d, want %d", got, tt.timed)
+			}
+		})
+	}
+}
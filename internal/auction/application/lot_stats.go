@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// statsVelocityWindow is the recent period the bid velocity of a lot is measured over
+	statsVelocityWindow = 5 * time.Minute
+	// price history points of the lot stats, by default and at most
+	defaultStatsPoints = 200
+	maxStatsPoints     = 1000
+)
+
+// LotStatsQueryDTO is the input of LotStatsUseCase
+type LotStatsQueryDTO struct {
+	LotID uuid.UUID
+	// Points is the max number of price history points, 0 uses the default
+	Points int
+}
+
+// LotStatsDTO is the bid snapshot of a lot with its aggregate statistics
+type LotStatsDTO struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	State         string    `json:"state"`
+	Currency      string    `json:"currency"`
+	InitialPrice  float64   `json:"initial_price"`
+	CurrentPrice  float64   `json:"current_price"`
+	BidCount      int       `json:"bid_count"`
+	UniqueBidders int       `json:"unique_bidders"`
+	// AvgIncrement is the mean price change between consecutive bids, rounded to the currency decimals
+	AvgIncrement float64         `json:"avg_increment"`
+	Velocity     BidVelocityDTO  `json:"velocity"`
+	FirstBidAt   *time.Time      `json:"first_bid_at,omitempty"`
+	LastBidAt    *time.Time      `json:"last_bid_at,omitempty"`
+	PriceHistory []PricePointDTO `json:"price_history"`
+	ComputedAt   time.Time       `json:"computed_at"`
+}
+
+// BidVelocityDTO is the bid rate of a lot over the recent window
+type BidVelocityDTO struct {
+	WindowSeconds int     `json:"window_seconds"`
+	Bids          int     `json:"bids"`
+	BidsPerMinute float64 `json:"bids_per_minute"`
+}
+
+// PricePointDTO is a point of the lot price chart
+type PricePointDTO struct {
+	At    time.Time `json:"at"`
+	Price float64   `json:"price"`
+}
+
+// LotStatsUseCase computes the bid statistics of a lot, read from the replicas
+type LotStatsUseCase struct {
+	lotRepo   domain.AuctionLotRepository
+	statsRepo domain.BidStatsRepository
+	clock     domain.Clock
+}
+
+// NewLotStatsUseCase creates a new instance of LotStatsUseCase
+func NewLotStatsUseCase(lotRepo domain.AuctionLotRepository, statsRepo domain.BidStatsRepository, clock domain.Clock) *LotStatsUseCase {
+	return &LotStatsUseCase{lotRepo: lotRepo, statsRepo: statsRepo, clock: clock}
+}
+
+// Execute returns the stats of the lot, domain.ErrLotNotFound if it doesn't exist
+func (uc *LotStatsUseCase) Execute(ctx context.Context, cmd LotStatsQueryDTO) (*LotStatsDTO, error) {
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot stats use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	now := uc.clock.Now()
+	stats, err := uc.statsRepo.GetLotBidStats(ctx, cmd.LotID, now.Add(-statsVelocityWindow), statsPoints(cmd.Points))
+	if err != nil {
+		return nil, fmt.Errorf("lot stats use case: failed to get bid stats of lot %s: %w", cmd.LotID, err)
+	}
+
+	scale := math.Pow10(domain.CurrencyDecimals(lot.Currency))
+	dto := &LotStatsDTO{
+		LotID:         lot.ID,
+		State:         string(lot.State),
+		Currency:      lot.Currency,
+		InitialPrice:  lot.InitialPrice,
+		CurrentPrice:  lot.CurrentPrice,
+		BidCount:      stats.BidCount,
+		UniqueBidders: stats.UniqueBidders,
+		AvgIncrement:  math.Round(stats.AvgIncrement*scale) / scale,
+		Velocity: BidVelocityDTO{
+			WindowSeconds: int(statsVelocityWindow.Seconds()),
+			Bids:          stats.RecentBids,
+			BidsPerMinute: float64(stats.RecentBids) / statsVelocityWindow.Minutes(),
+		},
+		FirstBidAt:   stats.FirstBidAt,
+		LastBidAt:    stats.LastBidAt,
+		PriceHistory: toPricePointDTOs(stats.PricePoints),
+		ComputedAt:   now,
+	}
+	return dto, nil
+}
+
+// PriceHistoryQueryDTO is the input of LotStatsUseCase.PriceHistory
+type PriceHistoryQueryDTO struct {
+	LotID uuid.UUID
+	// After skips the points at or before it, so a client resuming a chart only gets the missed ones
+	After time.Time
+	// Points is the max number of points, 0 uses the default
+	Points int
+}
+
+// PriceHistoryDTO is the price chart of a lot, live points follow as server_price_point msgs
+type PriceHistoryDTO struct {
+	LotID    uuid.UUID       `json:"lot_id"`
+	Currency string          `json:"currency"`
+	Points   []PricePointDTO `json:"points"`
+}
+
+// PriceHistory returns the price points of the lot bids, domain.ErrLotNotFound if it doesn't exist
+func (uc *LotStatsUseCase) PriceHistory(ctx context.Context, cmd PriceHistoryQueryDTO) (*PriceHistoryDTO, error) {
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot stats use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	points, err := uc.statsRepo.GetLotPriceHistory(ctx, cmd.LotID, cmd.After, statsPoints(cmd.Points))
+	if err != nil {
+		return nil, fmt.Errorf("lot stats use case: failed to get price history of lot %s: %w", cmd.LotID, err)
+	}
+	return &PriceHistoryDTO{LotID: lot.ID, Currency: lot.Currency, Points: toPricePointDTOs(points)}, nil
+}
+
+// statsPoints bounds the number of price points requested
+func statsPoints(points int) int {
+	if points <= 0 {
+		return defaultStatsPoints
+	}
+	return min(points, maxStatsPoints)
+}
+
+func toPricePointDTOs(points []domain.PricePoint) []PricePointDTO {
+	dtos := make([]PricePointDTO, 0, len(points))
+	for _, point := range points {
+		dtos = append(dtos, PricePointDTO{At: point.At, Price: point.Price})
+	}
+	return dtos
+}
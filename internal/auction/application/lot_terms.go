@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrorCodeTermsRequired is the error code of the bids of the bidders who didn't accept the current terms of the lot
+const ErrorCodeTermsRequired = "TERMS_REQUIRED"
+
+// maxTermsVersionLength bounds the terms versions, the size of their column
+const maxTermsVersionLength = 64
+
+var (
+	// ErrTermsRequired is returned when bidding on a lot without accepting its current terms
+	ErrTermsRequired = errors.New("the terms of the lot must be accepted before bidding")
+	// ErrInvalidTermsVersion is returned when accepting a version of the terms that is not the current one
+	ErrInvalidTermsVersion = errors.New("not the current terms of the lot")
+)
+
+// AcceptTermsDTO is the input DTO of a terms acceptance
+type AcceptTermsDTO struct {
+	LotID        uuid.UUID
+	UserID       uuid.UUID
+	TermsVersion string
+	ClientIP     string
+}
+
+// TermsStatusDTO is the version of the terms of a lot and whether the user accepted it, Version is ""
+// for the lots without terms
+type TermsStatusDTO struct {
+	Version  string
+	Accepted bool
+}
+
+// WithTerms returns the use case requiring the bidders to accept the terms of the lots that have some,
+// the acceptances are recorded in repo
+func (uc *LotRulesUseCase) WithTerms(repo domain.TermsAcceptanceRepository) *LotRulesUseCase {
+	uc.terms = repo
+	return uc
+}
+
+// TermsStatus returns the current terms version of the lot and whether the user accepted it
+func (uc *LotRulesUseCase) TermsStatus(ctx context.Context, lotID, userID uuid.UUID) (*TermsStatusDTO, error) {
+	if uc == nil {
+		return &TermsStatusDTO{}, nil
+	}
+	rules, err := uc.compiled(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: %w", err)
+	}
+	if rules == nil || rules.termsVersion == "" {
+		return &TermsStatusDTO{}, nil
+	}
+	status := &TermsStatusDTO{Version: rules.termsVersion}
+	if userID != uuid.Nil {
+		if status.Accepted, err = uc.hasAccepted(ctx, lotID, userID, rules.termsVersion); err != nil {
+			return nil, fmt.Errorf("lot rules use case: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// AcceptTerms records that the user accepted the terms of the lot, the version must be the current one
+// so a bidder can't accept terms it was not shown. accepting again is a no-op
+func (uc *LotRulesUseCase) AcceptTerms(ctx context.Context, cmd AcceptTermsDTO) (*domain.TermsAcceptance, error) {
+	if uc.terms == nil {
+		return nil, fmt.Errorf("lot rules use case: terms acceptance is not enabled")
+	}
+	rules, err := uc.compiled(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("lot rules use case: %w", err)
+	}
+	if rules == nil || rules.termsVersion == "" {
+		return nil, fmt.Errorf("%w: the lot has no terms", ErrInvalidTermsVersion)
+	}
+	if cmd.TermsVersion != rules.termsVersion {
+		return nil, fmt.Errorf("%w: the current version is %s", ErrInvalidTermsVersion, rules.termsVersion)
+	}
+	acceptance := &domain.TermsAcceptance{
+		UserID:       cmd.UserID,
+		LotID:        cmd.LotID,
+		TermsVersion: cmd.TermsVersion,
+		ClientIP:     cmd.ClientIP,
+	}
+	if err := uc.terms.Save(ctx, acceptance); err != nil {
+		return nil, fmt.Errorf("lot rules use case: failed to save terms acceptance of lot %s: %w", cmd.LotID, err)
+	}
+	uc.cacheAccepted(cmd.LotID, cmd.UserID, cmd.TermsVersion)
+	logger.FromContext(ctx).Info("Lot terms accepted",
+		zap.String("lotID", cmd.LotID.String()),
+		zap.String("userID", cmd.UserID.String()),
+		zap.String("termsVersion", cmd.TermsVersion),
+	)
+	return acceptance, nil
+}
+
+// checkTerms rejects the bid of userID with ErrTermsRequired when the lot has terms the user didn't accept
+func (uc *LotRulesUseCase) checkTerms(ctx context.Context, lotID uuid.UUID, rules *compiledLotRules, userID uuid.UUID) error {
+	if rules.termsVersion == "" {
+		return nil
+	}
+	accepted, err := uc.hasAccepted(ctx, lotID, userID, rules.termsVersion)
+	if err != nil {
+		return uc.ruleFailed(ctx, lotID, "terms", err)
+	}
+	if !accepted {
+		uc.termsRequired.Add(1)
+		return fmt.Errorf("%w: %w (version %s)", ErrBidNotAllowed, ErrTermsRequired, rules.termsVersion)
+	}
+	return nil
+}
+
+// hasAccepted reports if the user accepted the version of the terms of the lot, the acceptances read from
+// the repository are cached
+func (uc *LotRulesUseCase) hasAccepted(ctx context.Context, lotID, userID uuid.UUID, version string) (bool, error) {
+	uc.mu.Lock()
+	cached := uc.accepted[lotID][userID] == version
+	uc.mu.Unlock()
+	if cached {
+		return true, nil
+	}
+	if uc.terms == nil {
+		return false, errors.New("terms acceptance is not enabled")
+	}
+	accepted, err := uc.terms.Accepted(ctx, userID, lotID, version)
+	if err != nil {
+		return false, fmt.Errorf("failed to get terms acceptance: %w", err)
+	}
+	if accepted {
+		uc.cacheAccepted(lotID, userID, version)
+	}
+	return accepted, nil
+}
+
+func (uc *LotRulesUseCase) cacheAccepted(lotID, userID uuid.UUID, version string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	users, ok := uc.accepted[lotID]
+	if !ok {
+		users = make(map[uuid.UUID]string)
+		uc.accepted[lotID] = users
+	}
+	users[userID] = version
+}
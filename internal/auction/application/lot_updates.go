@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// subscriberBuffer is the number of pending updates kept for a slow subscriber before dropping
+const subscriberBuffer = 64
+
+// LotUpdateBroker fans out lot state changes to in-process subscribers (WS hub forwarder, gRPC streams, ...)
+// so every transport sees the same updates regardless of where the change originated
+type LotUpdateBroker struct {
+	mu      sync.RWMutex
+	byLot   map[uuid.UUID]map[chan *LotStateDTO]struct{}
+	allLots map[chan *LotStateDTO]struct{}
+	// notifier tells the other instances about the changes published with PublishChange, nil for none
+	notifier domain.LotChangeNotifier
+}
+
+// NewLotUpdateBroker creates a new instance of LotUpdateBroker
+func NewLotUpdateBroker() *LotUpdateBroker {
+	return &LotUpdateBroker{
+		byLot:   make(map[uuid.UUID]map[chan *LotStateDTO]struct{}),
+		allLots: make(map[chan *LotStateDTO]struct{}),
+	}
+}
+
+// WithNotifier returns the broker notifying the other instances of the changes published with PublishChange
+func (b *LotUpdateBroker) WithNotifier(notifier domain.LotChangeNotifier) *LotUpdateBroker {
+	b.notifier = notifier
+	return b
+}
+
+// Watched reports if lotID has subscribers of its own, the global subscribers don't count
+func (b *LotUpdateBroker) Watched(lotID uuid.UUID) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.byLot[lotID]) > 0
+}
+
+// Subscribe returns a channel receiving the updates of lotID and a func to cancel the subscription
+func (b *LotUpdateBroker) Subscribe(lotID uuid.UUID) (<-chan *LotStateDTO, func()) {
+	ch := make(chan *LotStateDTO, subscriberBuffer)
+	b.mu.Lock()
+	subs, ok := b.byLot[lotID]
+	if !ok {
+		subs = make(map[chan *LotStateDTO]struct{})
+		b.byLot[lotID] = subs
+	}
+	subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.byLot[lotID], ch)
+			if len(b.byLot[lotID]) == 0 {
+				delete(b.byLot, lotID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// SubscribeAll returns a channel receiving the updates of every lot and a func to cancel the subscription
+func (b *LotUpdateBroker) SubscribeAll() (<-chan *LotStateDTO, func()) {
+	ch := make(chan *LotStateDTO, subscriberBuffer)
+	b.mu.Lock()
+	b.allLots[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.allLots, ch)
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers state to the subscribers of its lot and to the global subscribers,
+// it never blocks: a subscriber with a full buffer misses the update
+func (b *LotUpdateBroker) Publish(state *LotStateDTO) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.byLot[state.LotID] {
+		b.deliver(ch, state)
+	}
+	for ch := range b.allLots {
+		b.deliver(ch, state)
+	}
+}
+
+// PublishChange publishes the state of a lot changed by this instance and notifies the other instances, a
+// failed notification is only logged, the change itself was already committed
+func (b *LotUpdateBroker) PublishChange(ctx context.Context, state *LotStateDTO) {
+	b.Publish(state)
+	if b.notifier == nil {
+		return
+	}
+	if err := b.notifier.NotifyLotChanged(ctx, state.LotID, state.Seq); err != nil {
+		logger.FromContext(ctx).Error("LotUpdateBroker: failed to notify lot change",
+			zap.String("lotID", state.LotID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func (b *LotUpdateBroker) deliver(ch chan *LotStateDTO, state *LotStateDTO) {
+	select {
+	case ch <- state:
+	default:
+		log.Warn("LotUpdateBroker: subscriber buffer full, dropping lot update",
+			zap.String("lotID", state.LotID.String()),
+		)
+	}
+}
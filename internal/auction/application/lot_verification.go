@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrorCodeVerificationRequired is the error code of the bids of unverified bidders on the lots requiring
+// verified ones
+const ErrorCodeVerificationRequired = "VERIFICATION_REQUIRED"
+
+// ErrVerificationRequired is returned when an unverified bidder bids on a lot requiring verified bidders
+var ErrVerificationRequired = errors.New("the lot requires a verified identity")
+
+// UserVerificationService tells if the identity of a user was verified (KYC), it's implemented by the user
+// module that receives the statuses of the KYC providers
+type UserVerificationService interface {
+	IsVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// WithVerification returns the use case checking with svc the bidders of the lots requiring verified
+// bidders, without one those lots reject every bid
+func (uc *LotRulesUseCase) WithVerification(svc UserVerificationService) *LotRulesUseCase {
+	uc.verification = svc
+	return uc
+}
+
+// checkVerified rejects the bid of userID with ErrVerificationRequired when the lot requires verified
+// bidders and the user is not one
+func (uc *LotRulesUseCase) checkVerified(ctx context.Context, lotID uuid.UUID, rules *compiledLotRules, userID uuid.UUID) error {
+	if !rules.requireVerified {
+		return nil
+	}
+	if uc.verification == nil {
+		return uc.ruleFailed(ctx, lotID, "require_verified", errors.New("user verification is not enabled"))
+	}
+	verified, err := uc.verification.IsVerified(ctx, userID)
+	if err != nil {
+		return uc.ruleFailed(ctx, lotID, "require_verified", err)
+	}
+	if !verified {
+		uc.unverified.Add(1)
+		return fmt.Errorf("%w: %w", ErrBidNotAllowed, ErrVerificationRequired)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// maintenance notice severities
+const (
+	NoticeInfo     = "info"
+	NoticeWarning  = "warning"
+	NoticeCritical = "critical"
+)
+
+// MaintenanceMode is the read-only switch of the engine, while it's on the bids are rejected with
+// ErrMaintenanceMode and everything else keeps working. it's safe for concurrent use, each instance
+// has its own switch
+type MaintenanceMode struct {
+	readOnly atomic.Bool
+}
+
+// NewMaintenanceMode creates the switch, on when readOnly is true
+func NewMaintenanceMode(readOnly bool) *MaintenanceMode {
+	m := &MaintenanceMode{}
+	m.readOnly.Store(readOnly)
+	return m
+}
+
+// ReadOnly reports if the bids are rejected, false for a nil switch
+func (m *MaintenanceMode) ReadOnly() bool {
+	return m != nil && m.readOnly.Load()
+}
+
+// SetReadOnly turns the read-only mode on or off
+func (m *MaintenanceMode) SetReadOnly(readOnly bool) {
+	m.readOnly.Store(readOnly)
+}
+
+// AnnounceMaintenanceDTO is the input of a maintenance notice, the downtime window is optional but
+// EndsAt needs StartsAt
+type AnnounceMaintenanceDTO struct {
+	Message string
+	// Severity is info, warning or critical, empty is info
+	Severity string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	// ReadOnly also turns the read-only mode on right away
+	ReadOnly bool
+}
+
+// MaintenanceNoticeDTO is a maintenance notice sent to every connected client
+type MaintenanceNoticeDTO struct {
+	Message  string     `json:"message"`
+	Severity string     `json:"severity"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	// ReadOnly is the read-only mode after the notice
+	ReadOnly bool      `json:"read_only"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// MaintenanceModeDTO is the state of the read-only switch
+type MaintenanceModeDTO struct {
+	ReadOnly bool `json:"read_only"`
+}
+
+// MaintenanceNotifier delivers the maintenance notices to the connected clients of every lot
+type MaintenanceNotifier interface {
+	NotifyMaintenance(ctx context.Context, notice *MaintenanceNoticeDTO)
+}
+
+// MaintenanceUseCase announces the scheduled maintenances and runs the read-only mode
+type MaintenanceUseCase struct {
+	mode     *MaintenanceMode
+	notifier MaintenanceNotifier
+	clock    domain.Clock
+}
+
+// NewMaintenanceUseCase creates a new instance of MaintenanceUseCase
+func NewMaintenanceUseCase(mode *MaintenanceMode, notifier MaintenanceNotifier, clock domain.Clock) *MaintenanceUseCase {
+	return &MaintenanceUseCase{mode: mode, notifier: notifier, clock: clock}
+}
+
+// Announce validates a maintenance notice, turns the read-only mode on when asked and sends the notice
+// to every connected client
+func (uc *MaintenanceUseCase) Announce(ctx context.Context, cmd AnnounceMaintenanceDTO) (*MaintenanceNoticeDTO, error) {
+	message := strings.TrimSpace(cmd.Message)
+	if message == "" {
+		return nil, fmt.Errorf("%w: message is required", ErrInvalidMaintenanceNotice)
+	}
+	severity := cmd.Severity
+	if severity == "" {
+		severity = NoticeInfo
+	}
+	if severity != NoticeInfo && severity != NoticeWarning && severity != NoticeCritical {
+		return nil, fmt.Errorf("%w: severity must be info, warning or critical", ErrInvalidMaintenanceNotice)
+	}
+	if cmd.EndsAt != nil && (cmd.StartsAt == nil || !cmd.EndsAt.After(*cmd.StartsAt)) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMaintenanceNotice)
+	}
+
+	if cmd.ReadOnly {
+		uc.mode.SetReadOnly(true)
+	}
+	notice := &MaintenanceNoticeDTO{
+		Message:  message,
+		Severity: severity,
+		StartsAt: cmd.StartsAt,
+		EndsAt:   cmd.EndsAt,
+		ReadOnly: uc.mode.ReadOnly(),
+		IssuedAt: uc.clock.Now(),
+	}
+	uc.notifier.NotifyMaintenance(ctx, notice)
+	logger.FromContext(ctx).Info("Maintenance notice announced",
+		zap.String("severity", notice.Severity),
+		zap.Bool("readOnly", notice.ReadOnly),
+	)
+	return notice, nil
+}
+
+// Mode returns the state of the read-only switch
+func (uc *MaintenanceUseCase) Mode() *MaintenanceModeDTO {
+	return &MaintenanceModeDTO{ReadOnly: uc.mode.ReadOnly()}
+}
+
+// SetMode turns the read-only mode on or off, e.g. around a deploy during live auctions
+func (uc *MaintenanceUseCase) SetMode(ctx context.Context, readOnly bool) *MaintenanceModeDTO {
+	uc.mode.SetReadOnly(readOnly)
+	logger.FromContext(ctx).Info("Maintenance mode changed", zap.Bool("readOnly", readOnly))
+	return uc.Mode()
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
@@ -20,48 +19,142 @@ type PlaceBidDTO struct {
 	LotID  uuid.UUID
 	UserID uuid.UUID
 	Amount float64
+	// Currency is the currency the bidder meant, optional, a bid in another currency than the lot one is rejected
+	Currency string
+	// ClientIP is the remote IP of the bidder connection, analyzed by the fraud heuristics
+	ClientIP string
+	// ReceivedAt is when the server read the bid from the connection, the lot end is checked against it.
+	// zero for the bids processed as soon as they're received
+	ReceivedAt time.Time
+	// QuickBid bids the next min bid of the lot when the bid is processed instead of Amount, up to
+	// MaxAmount when set (down to it on reverse lots)
+	QuickBid  bool
+	MaxAmount float64
+}
+
+// PlaceBidResult is the output of PlaceBidUseCase
+type PlaceBidResult struct {
+	Bid *domain.Bid
+	// PreviousLeadingBid is the bid leading the lot before this one, nil if it's the first bid. on a
+	// multi-unit lot it's the standing bid this one pushed out of the units, nil if none was
+	PreviousLeadingBid *domain.Bid
+	// Events are the lot events stored with the bid, with their Seq assigned
+	Events []domain.Event
 }
 
 // PlaceBidUseCase is useCase to make a bid in an auction lot, orchestrate bussines logic and persistence
 type PlaceBidUseCase struct {
-	lotRepo domain.AuctionLotRepository
-	bidRepo domain.BidRepository
-	dbPool  *pgxpool.Pool
+	lotRepo       domain.AuctionLotRepository
+	bidRepo       domain.BidRepository
+	incrementRepo domain.BidIncrementRepository
+	eventStore    domain.LotEventStore
+	paddleRepo    domain.PaddleRepository
+	limits        domain.BiddingLimitProvider
+	reservations  domain.BidReservationRepository
+	transactor    domain.Transactor
+	clock         domain.Clock
+	// retry of the transactions aborted under contention, see WithRetryPolicy
+	retry         BidRetryPolicy
+	retryCounters bidRetryCounters
+	// bidTypes counts the custom and quick bids, see BidTypeStats
+	bidTypes bidTypeCounters
+	// maintenance rejects the bids while it's read-only, see WithMaintenanceMode
+	maintenance *MaintenanceMode
+	// validators are the deployment checks run before the lot rules, see WithBidValidators
+	validators []*bidValidatorHook
+	// rules are the expressions set on the lots by the operators, see WithLotRules
+	rules *LotRulesUseCase
 	// userRepo domain.UserRepository // maybe useful to validates the UserID existence
 }
 
 // NewPlaceBidUseCase creates a new instace of PlaceBidUseCase struct, it receives dependency through injection
 func NewPlaceBidUseCase(lotRepo domain.AuctionLotRepository,
 	bidRepo domain.BidRepository,
-	dbPool *pgxpool.Pool) *PlaceBidUseCase {
+	incrementRepo domain.BidIncrementRepository,
+	eventStore domain.LotEventStore,
+	paddleRepo domain.PaddleRepository,
+	limits domain.BiddingLimitProvider,
+	reservations domain.BidReservationRepository,
+	transactor domain.Transactor,
+	clock domain.Clock) *PlaceBidUseCase {
 
 	return &PlaceBidUseCase{
-		lotRepo: lotRepo,
-		bidRepo: bidRepo,
-		dbPool:  dbPool,
+		lotRepo:       lotRepo,
+		bidRepo:       bidRepo,
+		incrementRepo: incrementRepo,
+		eventStore:    eventStore,
+		paddleRepo:    paddleRepo,
+		limits:        limits,
+		reservations:  reservations,
+		transactor:    transactor,
+		clock:         clock,
+		retry:         DefaultBidRetryPolicy,
 	}
 
 }
 
-func (uc *PlaceBidUseCase) Execute(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error) {
+// WithMaintenanceMode returns the use case rejecting the bids with ErrMaintenanceMode while mode is read-only
+func (uc *PlaceBidUseCase) WithMaintenanceMode(mode *MaintenanceMode) *PlaceBidUseCase {
+	uc.maintenance = mode
+	return uc
+}
+
+// WithLotRules returns the use case evaluating the rules of the lots on every bid
+func (uc *PlaceBidUseCase) WithLotRules(rules *LotRulesUseCase) *PlaceBidUseCase {
+	uc.rules = rules
+	return uc
+}
+
+// Execute places a bid in its own TX, run again when the storage aborts it under contention
+func (uc *PlaceBidUseCase) Execute(ctx context.Context, cmd PlaceBidDTO) (result *PlaceBidResult, err error) {
+	if uc.maintenance.ReadOnly() {
+		return nil, fmt.Errorf("place bid use case: %w", ErrMaintenanceMode)
+	}
+	err = uc.withRetries(ctx, "place_bid", func() error {
+		result, err = uc.execute(ctx, cmd)
+		return err
+	})
+	uc.recordBidType(cmd, err)
+	return result, err
+}
+
+// validateInput checks the amount of cmd and runs the bid validators over it. the precision of the lot
+// currency is checked again by the lot, the bid currency is optional
+func (uc *PlaceBidUseCase) validateInput(ctx context.Context, cmd PlaceBidDTO) error {
+	if err := domain.ValidateAmount(cmd.Amount, cmd.Currency); err != nil {
+		logger.FromContext(ctx).Warn("PlaceBidUseCase: Invalid bid amount",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Float64("amount", cmd.Amount),
+			zap.Error(err),
+		)
+		return err
+	}
+	if err := uc.validateBid(ctx, cmd); err != nil {
+		return fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
+	}
+	return nil
+}
+
+func (uc *PlaceBidUseCase) execute(ctx context.Context, cmd PlaceBidDTO) (res *PlaceBidResult, err error) {
+	log := logger.FromContext(ctx)
 	log.Info("Executing PlaceBidUseCase",
 		zap.String("lotID", cmd.LotID.String()),
 		zap.String("userID", cmd.UserID.String()),
 		zap.Float64("amount", cmd.Amount),
+		zap.Bool("quickBid", cmd.QuickBid),
 	)
-	// 1. validates input DTO (basics validations, relative to the input data, not bussiles logic)
-	if cmd.Amount <= 0 {
-		log.Warn("PlaceBidUseCase: Invalid bid amount",
-			zap.String("lotID", cmd.LotID.String()),
-			zap.String("userID", cmd.UserID.String()),
-			zap.Float64("amount", cmd.Amount),
-		)
-		return nil, domain.ErrInvalidAmount // Asegúrate de que ErrInvalidAmount esté definido en domain/errors.go
+	// 1. validates input DTO (basics validations, relative to the input data, not bussiles logic),
+	// the amount of a quick bid is only known once the lot is loaded
+	if !cmd.QuickBid {
+		if err := uc.validateInput(ctx, cmd); err != nil {
+			return nil, err
+		}
 	}
 	//TODO: maybe validates if UserID exists using userRepo.GetByID()
 
 	//2. starts a DB TX, to ensures an atomic operations for save the bid and upates de lot
-	tx, err := uc.dbPool.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := uc.transactor.Begin(ctx)
 	if err != nil {
 		log.Error("PlaceBidUseCase: Failed to begin transaction",
 			zap.String("lotID", cmd.LotID.String()),
@@ -104,8 +197,10 @@ func (uc *PlaceBidUseCase) Execute(ctx context.Context, cmd PlaceBidDTO) (*domai
 				zap.String("userID", cmd.UserID.String()),
 				zap.Error(commitErr),
 			)
-			// Assign the commitError to 'err' variable to be returned by Execute() main function
-			err = fmt.Errorf("place bid use case: failed to commit transaction: %w", commitErr)
+			// the bid was never stored, the named results make Execute() return the commit error so
+			// a serialization failure at commit time is retried as well
+			res, err = nil, fmt.Errorf("place bid use case: failed to commit transaction: %w", commitErr)
+			return
 		}
 		//at this point the tx has beaing completed succefully
 		log.Info("PlaceBidUseCase: Transaction committed successfully",
@@ -129,15 +224,87 @@ func (uc *PlaceBidUseCase) Execute(ctx context.Context, cmd PlaceBidDTO) (*domai
 		// Return the error (a domain or repository error)
 		return nil, fmt.Errorf("place bid use case: failed to get auction lot %s: %w", cmd.LotID, err)
 	}
+	// the anti-sniping extension and the bid time follow the clock of the use case
+	lot.SetClock(uc.clock)
+	if err = uc.loadStandingBids(ctx, lot); err != nil {
+		log.Error("PlaceBidUseCase: Failed to get standing bids",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("place bid use case: failed to get standing bids for lot %s: %w", cmd.LotID, err)
+	}
+
+	// amounts are always in the lot currency, a client assuming another one is rejected instead of converted
+	if currency, _ := domain.NormalizeCurrency(cmd.Currency); cmd.Currency != "" && currency != lot.Currency {
+		err = fmt.Errorf("%w: bid in %q, lot priced in %s", domain.ErrCurrencyMismatch, cmd.Currency, lot.Currency)
+		return nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
+	}
 
 	// 4. call domain method to make the bid, where the bussines logic is executed (validations, state updates
 	// time extension). Domain returns new Bid entity if is succefully created
-	minIncrement := 0.0 //temporal configuration
-	newBid, err := lot.PlaceBid(cmd.UserID, cmd.Amount, minIncrement)
+	increments, err := uc.incrementRepo.GetIncrementTable(ctx, lot.ID)
+	if err != nil {
+		log.Error("PlaceBidUseCase: Failed to get bid increment table",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("place bid use case: failed to get increment table for lot %s: %w", cmd.LotID, err)
+	}
+	if cmd.QuickBid {
+		if cmd, err = uc.quickBid(ctx, lot, cmd, increments); err != nil {
+			return nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
+		}
+		if err = uc.validateInput(ctx, cmd); err != nil {
+			return nil, err
+		}
+	}
+	// the rules of the lot may reject the bidder or replace the increment of the table
+	minIncrement, err := uc.rules.Apply(ctx, lot, cmd, increments.IncrementFor(lot.CurrentPrice))
 	if err != nil {
 		return nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
 	}
 
+	// the current leader becomes outbid if this bid is accepted
+	var previousLeadingBid *domain.Bid
+	if !lot.MultiUnit() {
+		previousLeadingBid, err = uc.bidRepo.GetLatestBidByLotID(ctx, lot.ID)
+	}
+	if err != nil {
+		log.Error("PlaceBidUseCase: Failed to get leading bid",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("place bid use case: failed to get leading bid for lot %s: %w", cmd.LotID, err)
+	}
+	newBid, err := lot.PlaceBidAt(cmd.UserID, cmd.Amount, minIncrement, cmd.ReceivedAt)
+	if err != nil {
+		return nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
+	}
+	if lot.MultiUnit() {
+		previousLeadingBid = lot.TakeDisplacedBid()
+	}
+
+	newBid.ClientIP = cmd.ClientIP
+
+	// the leading bid reserves its amount from the bidder limit, raising your own bid replaces
+	// your reservation on the lot so only the other lots count against the limit
+	if err = uc.reserveBiddingLimit(ctx, tx, newBid); err != nil {
+		return nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", cmd.LotID, err)
+	}
+	if previousLeadingBid != nil && previousLeadingBid.UserID != newBid.UserID {
+		if err = uc.reservations.Release(ctx, tx, previousLeadingBid.UserID, lot.ID); err != nil {
+			log.Error("PlaceBidUseCase: Failed to release outbid reservation",
+				zap.String("lotID", cmd.LotID.String()),
+				zap.String("outbidUserID", previousLeadingBid.UserID.String()),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("place bid use case: failed to release reservation for lot %s: %w", cmd.LotID, err)
+		}
+	}
+
 	// 5. persist in repository methods inside TX
 	err = uc.bidRepo.Save(ctx, tx, newBid)
 	if err != nil {
@@ -160,7 +327,76 @@ func (uc *PlaceBidUseCase) Execute(ctx context.Context, cmd PlaceBidDTO) (*domai
 		return nil, fmt.Errorf("place bid use case: failed to save updated auction lot %s: %w", cmd.LotID, err)
 	}
 
-	//6. if everthing goes right, defer() makes the commit, and then the newBid is returned
-	return newBid, nil
+	// the lot row is locked by the save above, so paddle numbers of the lot are assigned one at a time
+	newBid.Paddle, err = uc.paddleRepo.Assign(ctx, tx, lot.ID, cmd.UserID)
+	if err != nil {
+		log.Error("PlaceBidUseCase: Failed to assign bidder paddle",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("place bid use case: failed to assign paddle for lot %s: %w", cmd.LotID, err)
+	}
+
+	// 6. append the lot event in the same TX, so the event sequence never diverges from the lot state
+	events, err := uc.eventStore.Append(ctx, tx, lot.ID, domain.NewEvent(domain.EventBidPlaced, lot.ID, newBid.Timestamp, domain.BidPlacedPayload{
+		BidID:        newBid.ID,
+		UserID:       newBid.UserID,
+		Paddle:       newBid.Paddle,
+		Amount:       newBid.Amount,
+		CurrentPrice: lot.CurrentPrice,
+		EndTime:      lot.EndTime,
+	}))
+	if err != nil {
+		log.Error("PlaceBidUseCase: Failed to append lot event",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("userID", cmd.UserID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("place bid use case: failed to append event for lot %s: %w", cmd.LotID, err)
+	}
+
+	//7. if everthing goes right, defer() makes the commit, and then the newBid is returned
+	return &PlaceBidResult{Bid: newBid, PreviousLeadingBid: previousLeadingBid, Events: events}, nil
+
+}
+
+// loadStandingBids loads the bids holding the units of a multi-unit lot, the ones a new bid competes with
+func (uc *PlaceBidUseCase) loadStandingBids(ctx context.Context, lot *domain.AuctionLot) error {
+	if !lot.MultiUnit() {
+		return nil
+	}
+	standing, err := uc.bidRepo.GetStandingBids(ctx, lot.ID, lot.Quantity)
+	if err != nil {
+		return err
+	}
+	lot.SetStandingBids(standing)
+	return nil
+}
 
+// reserveBiddingLimit rejects the bid with domain.ErrBiddingLimitExceeded when it doesn't fit in the
+// bidder available limit (limit minus the amounts reserved on other lots), otherwise it reserves the amount
+func (uc *PlaceBidUseCase) reserveBiddingLimit(ctx context.Context, tx domain.Tx, bid *domain.Bid) error {
+	limit, limited, err := uc.limits.GetLimit(ctx, bid.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get bidding limit: %w", err)
+	}
+	reserved, err := uc.reservations.ReservedTotal(ctx, tx, bid.UserID, bid.LotID)
+	if err != nil {
+		return fmt.Errorf("failed to get reserved bidding limit: %w", err)
+	}
+	if limited && reserved+bid.Amount > limit {
+		logger.FromContext(ctx).Warn("Bid rejected: Bidding limit exceeded",
+			zap.String("lotID", bid.LotID.String()),
+			zap.String("userID", bid.UserID.String()),
+			zap.Float64("bidAmount", bid.Amount),
+			zap.Float64("limit", limit),
+			zap.Float64("reserved", reserved),
+		)
+		return fmt.Errorf("%w: available %.2f", domain.ErrBiddingLimitExceeded, max(limit-reserved, 0))
+	}
+	if err := uc.reservations.Reserve(ctx, tx, bid.UserID, bid.LotID, bid.Amount); err != nil {
+		return fmt.Errorf("failed to reserve bidding limit: %w", err)
+	}
+	return nil
 }
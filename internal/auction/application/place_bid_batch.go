@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// ExecuteBatch applies in order many bids of the same lot inside a single TX: the lot is loaded once,
+// every bid is validated against the state left by the previous ones, and the accepted bids are inserted
+// together at the end. a rejected bid gets its error in errs without affecting the others, while err fails
+// the whole batch (nothing is stored). it's the batched counterpart of Execute for very hot lots, and it's
+// run again as a whole when the storage aborts it under contention
+func (uc *PlaceBidUseCase) ExecuteBatch(ctx context.Context, cmds []PlaceBidDTO) (results []*PlaceBidResult, errs []error, err error) {
+	if uc.maintenance.ReadOnly() {
+		return nil, nil, fmt.Errorf("place bid use case: %w", ErrMaintenanceMode)
+	}
+	err = uc.withRetries(ctx, "place_bid_batch", func() error {
+		results, errs, err = uc.executeBatch(ctx, cmds)
+		return err
+	})
+	if err == nil {
+		for i, cmd := range cmds {
+			uc.recordBidType(cmd, errs[i])
+		}
+	}
+	return results, errs, err
+}
+
+func (uc *PlaceBidUseCase) executeBatch(ctx context.Context, cmds []PlaceBidDTO) (results []*PlaceBidResult, errs []error, err error) {
+	log := logger.FromContext(ctx)
+	if len(cmds) == 0 {
+		return nil, nil, nil
+	}
+	lotID := cmds[0].LotID
+	results = make([]*PlaceBidResult, len(cmds))
+	errs = make([]error, len(cmds))
+
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to begin batch transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			log.Warn("PlaceBidUseCase: Rolling back bid batch due to error",
+				zap.String("lotID", lotID.String()),
+				zap.Int("bids", len(cmds)),
+				zap.Error(err),
+			)
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			err = fmt.Errorf("place bid use case: failed to commit batch transaction: %w", commitErr)
+		}
+	}()
+
+	lot, err := uc.lotRepo.GetByID(ctx, lotID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to get auction lot %s: %w", lotID, err)
+	}
+	lot.SetClock(uc.clock)
+	if err = uc.loadStandingBids(ctx, lot); err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to get standing bids for lot %s: %w", lotID, err)
+	}
+	// locks the lot row first, as the per bid path does with its save, so paddles of the lot are assigned one at a time
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to lock auction lot %s: %w", lotID, err)
+	}
+	increments, err := uc.incrementRepo.GetIncrementTable(ctx, lot.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to get increment table for lot %s: %w", lotID, err)
+	}
+	var leadingBid *domain.Bid
+	if !lot.MultiUnit() {
+		leadingBid, err = uc.bidRepo.GetLatestBidByLotID(ctx, lot.ID)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to get leading bid for lot %s: %w", lotID, err)
+	}
+
+	accepted := make([]*domain.Bid, 0, len(cmds))
+	for i, cmd := range cmds {
+		// a quick bid bids the next min bid after the bids of the batch before it
+		if cmd.QuickBid {
+			var quickErr error
+			if cmd, quickErr = uc.quickBid(ctx, lot, cmd, increments); quickErr != nil {
+				if !errors.Is(quickErr, ErrQuickBidLimit) {
+					return nil, nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, quickErr)
+				}
+				errs[i] = fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, quickErr)
+				continue
+			}
+		}
+		if err := uc.validateInput(ctx, cmd); err != nil {
+			errs[i] = err
+			continue
+		}
+		if currency, _ := domain.NormalizeCurrency(cmd.Currency); cmd.Currency != "" && currency != lot.Currency {
+			errs[i] = fmt.Errorf("place bid use case: bid failed for lot %s: %w: bid in %q, lot priced in %s",
+				lotID, domain.ErrCurrencyMismatch, cmd.Currency, lot.Currency)
+			continue
+		}
+
+		// the lot fields changed by a bid, restored if the bid is rejected after being applied
+		price, endTime, lastBidTime, bidCount, standing := lot.CurrentPrice, lot.EndTime, lot.LastBidTime, len(lot.Bids), lot.Standing
+		minIncrement, rulesErr := uc.rules.Apply(ctx, lot, cmd, increments.IncrementFor(lot.CurrentPrice))
+		if rulesErr != nil {
+			if !errors.Is(rulesErr, ErrBidNotAllowed) {
+				return nil, nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, rulesErr)
+			}
+			errs[i] = fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, rulesErr)
+			continue
+		}
+		newBid, bidErr := lot.PlaceBidAt(cmd.UserID, cmd.Amount, minIncrement, cmd.ReceivedAt)
+		if bidErr != nil {
+			errs[i] = fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, bidErr)
+			continue
+		}
+		newBid.ClientIP = cmd.ClientIP
+		// on a multi-unit lot the outbid bidder is the one pushed out of the units, if any
+		previousBid := leadingBid
+		if lot.MultiUnit() {
+			previousBid = lot.TakeDisplacedBid()
+		}
+
+		if limitErr := uc.reserveBiddingLimit(ctx, tx, newBid); limitErr != nil {
+			if !errors.Is(limitErr, domain.ErrBiddingLimitExceeded) {
+				return nil, nil, fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, limitErr)
+			}
+			lot.CurrentPrice, lot.EndTime, lot.LastBidTime, lot.Bids = price, endTime, lastBidTime, lot.Bids[:bidCount]
+			lot.Standing = standing
+			errs[i] = fmt.Errorf("place bid use case: bid failed for lot %s: %w", lotID, limitErr)
+			continue
+		}
+		if previousBid != nil && previousBid.UserID != newBid.UserID {
+			if err = uc.reservations.Release(ctx, tx, previousBid.UserID, lot.ID); err != nil {
+				return nil, nil, fmt.Errorf("place bid use case: failed to release reservation for lot %s: %w", lotID, err)
+			}
+		}
+		if newBid.Paddle, err = uc.paddleRepo.Assign(ctx, tx, lot.ID, cmd.UserID); err != nil {
+			return nil, nil, fmt.Errorf("place bid use case: failed to assign paddle for lot %s: %w", lotID, err)
+		}
+		var events []domain.Event
+		events, err = uc.eventStore.Append(ctx, tx, lot.ID, domain.NewEvent(domain.EventBidPlaced, lot.ID, newBid.Timestamp, domain.BidPlacedPayload{
+			BidID:        newBid.ID,
+			UserID:       newBid.UserID,
+			Paddle:       newBid.Paddle,
+			Amount:       newBid.Amount,
+			CurrentPrice: lot.CurrentPrice,
+			EndTime:      lot.EndTime,
+		}))
+		if err != nil {
+			return nil, nil, fmt.Errorf("place bid use case: failed to append event for lot %s: %w", lotID, err)
+		}
+
+		results[i] = &PlaceBidResult{Bid: newBid, PreviousLeadingBid: previousBid, Events: events}
+		leadingBid = newBid
+		accepted = append(accepted, newBid)
+	}
+	if len(accepted) == 0 {
+		return results, errs, nil
+	}
+
+	start := time.Now()
+	if err = uc.bidRepo.SaveBatch(ctx, tx, accepted); err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to save bid batch for lot %s: %w", lotID, err)
+	}
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, nil, fmt.Errorf("place bid use case: failed to save updated auction lot %s: %w", lotID, err)
+	}
+	log.Debug("PlaceBidUseCase: Bid batch stored",
+		zap.String("lotID", lotID.String()),
+		zap.Int("bids", len(cmds)),
+		zap.Int("accepted", len(accepted)),
+		zap.Duration("saveDuration", time.Since(start)),
+	)
+	return results, errs, nil
+}
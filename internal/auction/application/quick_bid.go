@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+)
+
+// ErrorCodeQuickBidLimit is the error code of the quick bids whose amount went past the limit of the bidder
+const ErrorCodeQuickBidLimit = "QUICK_BID_LIMIT"
+
+// ErrQuickBidLimit is returned when the next min bid of a lot is beyond the MaxAmount of a quick bid
+var ErrQuickBidLimit = errors.New("the next min bid is beyond the quick bid limit")
+
+// quickBid returns cmd bidding the next min bid of lot, the current price moved by the increment of the
+// table or of the min_increment rule, as broadcast in next_min_bid. it's resolved against the lot loaded
+// to place the bid, so a quick bid never lands below a bid processed before it
+func (uc *PlaceBidUseCase) quickBid(ctx context.Context, lot *domain.AuctionLot, cmd PlaceBidDTO, increments domain.IncrementTable) (PlaceBidDTO, error) {
+	minIncrement, _, err := uc.rules.BiddingTerms(ctx, lot, increments.IncrementFor(lot.CurrentPrice))
+	if err != nil {
+		return cmd, err
+	}
+	cmd.Amount = lot.NextMinBid(minIncrement)
+	if cmd.MaxAmount <= 0 {
+		return cmd, nil
+	}
+	over := cmd.Amount > cmd.MaxAmount
+	if lot.Type == domain.LotTypeReverse {
+		over = cmd.Amount < cmd.MaxAmount
+	}
+	if over {
+		return cmd, fmt.Errorf("%w: next min bid %.2f, limit %.2f", ErrQuickBidLimit, cmd.Amount, cmd.MaxAmount)
+	}
+	return cmd, nil
+}
+
+// BidTypeStats counts the bids processed since start by how the bidder set the amount: custom bids carry
+// the amount of the bidder, quick bids the next min bid resolved by the engine
+type BidTypeStats struct {
+	Custom BidTypeCounts `json:"custom"`
+	Quick  BidTypeCounts `json:"quick"`
+}
+
+// BidTypeCounts are the outcomes of the bids of a type
+type BidTypeCounts struct {
+	Placed   int64 `json:"placed"`
+	Rejected int64 `json:"rejected"`
+	// TooLow is the part of Rejected below the next min bid, mostly bids raced by another one on the lot
+	TooLow int64 `json:"too_low"`
+}
+
+// bidTypeCounters are the live BidTypeStats
+type bidTypeCounters struct {
+	custom, quick struct {
+		placed, rejected, tooLow atomic.Int64
+	}
+}
+
+// BidTypeStats returns the counters of the bids by type
+func (uc *PlaceBidUseCase) BidTypeStats() BidTypeStats {
+	c := &uc.bidTypes
+	return BidTypeStats{
+		Custom: BidTypeCounts{Placed: c.custom.placed.Load(), Rejected: c.custom.rejected.Load(), TooLow: c.custom.tooLow.Load()},
+		Quick:  BidTypeCounts{Placed: c.quick.placed.Load(), Rejected: c.quick.rejected.Load(), TooLow: c.quick.tooLow.Load()},
+	}
+}
+
+// recordBidType counts the outcome err of cmd, nil when the bid was placed
+func (uc *PlaceBidUseCase) recordBidType(cmd PlaceBidDTO, err error) {
+	counters := &uc.bidTypes.custom
+	if cmd.QuickBid {
+		counters = &uc.bidTypes.quick
+	}
+	if err == nil {
+		counters.placed.Add(1)
+		return
+	}
+	counters.rejected.Add(1)
+	if errors.Is(err, domain.ErrBidAmountTooLow) || errors.Is(err, domain.ErrBidAmountTooHigh) ||
+		errors.Is(err, domain.ErrBidIncrementTooSmall) {
+		counters.tooLow.Add(1)
+	}
+}
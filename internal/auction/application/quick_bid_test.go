@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// TestQuickBid checks a quick bid bids the next min bid of the increment tier of the current price, or
+// of the min_increment rule, and is rejected past its max amount
+func TestQuickBid(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	increments := domain.NewIncrementTable(
+		domain.IncrementTier{MinPrice: 0, Increment: 5},
+		domain.IncrementTier{MinPrice: 100, Increment: 10},
+	)
+	lot := &domain.AuctionLot{ID: uuid.New(), CurrentPrice: 120, Currency: "USD", EndTime: now.Add(time.Hour)}
+	uc := &PlaceBidUseCase{}
+	ctx := context.Background()
+
+	cmd, err := uc.quickBid(ctx, lot, PlaceBidDTO{LotID: lot.ID, QuickBid: true}, increments)
+	if err != nil || cmd.Amount != 130 {
+		t.Fatalf("quick bid = %v, %v, want 130", cmd.Amount, err)
+	}
+	if _, err = uc.quickBid(ctx, lot, PlaceBidDTO{LotID: lot.ID, QuickBid: true, MaxAmount: 125}, increments); !errors.Is(err, ErrQuickBidLimit) || BidErrorCode(err) != ErrorCodeQuickBidLimit {
+		t.Fatalf("quick bid over its max amount: err = %v, want ErrQuickBidLimit", err)
+	}
+
+	reverse := &domain.AuctionLot{ID: uuid.New(), Type: domain.LotTypeReverse, CurrentPrice: 120, Currency: "USD", EndTime: now.Add(time.Hour)}
+	if _, err = uc.quickBid(ctx, reverse, PlaceBidDTO{LotID: reverse.ID, QuickBid: true, MaxAmount: 115}, increments); !errors.Is(err, ErrQuickBidLimit) {
+		t.Fatalf("reverse quick bid under its limit: err = %v, want ErrQuickBidLimit", err)
+	}
+
+	repo := &fakeLotRulesRepo{rules: map[uuid.UUID]*domain.LotRules{
+		lot.ID: {LotID: lot.ID, MinIncrement: "current_price * 0.5"},
+	}}
+	uc.rules = NewLotRulesUseCase(nil, repo, nil, domain.NewManualClock(now), time.Minute)
+	if cmd, err = uc.quickBid(ctx, lot, PlaceBidDTO{LotID: lot.ID, QuickBid: true}, increments); err != nil || cmd.Amount != 180 {
+		t.Fatalf("quick bid with a min_increment rule = %v, %v, want 180", cmd.Amount, err)
+	}
+}
+
+// TestBidTypeStats checks the bids are counted by type, with the too low rejections apart
+func TestBidTypeStats(t *testing.T) {
+	uc := &PlaceBidUseCase{}
+	uc.recordBidType(PlaceBidDTO{}, nil)
+	uc.recordBidType(PlaceBidDTO{}, fmt.Errorf("place bid use case: %w", domain.ErrBidIncrementTooSmall))
+	uc.recordBidType(PlaceBidDTO{QuickBid: true}, nil)
+	uc.recordBidType(PlaceBidDTO{QuickBid: true}, ErrQuickBidLimit)
+
+	want := BidTypeStats{
+		Custom: BidTypeCounts{Placed: 1, Rejected: 1, TooLow: 1},
+		Quick:  BidTypeCounts{Placed: 1, Rejected: 1},
+	}
+	if stats := uc.BidTypeStats(); stats != want {
+		t.Fatalf("stats = %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,332 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxReplaySpeed caps the speed factor of a replay
+const MaxReplaySpeed = 100
+
+// StartReplayDTO is the input of ReplayEngine.Start, Speed 1 replays at the recorded pace, 10 ten times faster
+type StartReplayDTO struct {
+	LotID uuid.UUID `json:"lot_id"`
+	Speed float64   `json:"speed"`
+}
+
+// ReplayDTO describes a replay, clients watch it connecting to the lot WS endpoint with the replay ID
+type ReplayDTO struct {
+	ID        uuid.UUID `json:"id"`
+	LotID     uuid.UUID `json:"lot_id"` // the recorded lot
+	Speed     float64   `json:"speed"`
+	Events    int       `json:"events"`
+	Played    int       `json:"played"`
+	StartedAt time.Time `json:"started_at"`
+	Done      bool      `json:"done"`
+}
+
+// replayClock is the recorded time of a replay: it starts at the first recorded event and runs speed
+// times faster than the wall clock
+type replayClock struct {
+	origin  time.Time
+	started time.Time
+	speed   float64
+}
+
+// Now implements domain.Clock
+func (c *replayClock) Now() time.Time {
+	return c.origin.Add(time.Duration(float64(time.Since(c.started)) * c.speed))
+}
+
+// replay is a running or finished replay, guarded by the ReplayEngine mutex
+type replay struct {
+	dto    ReplayDTO
+	state  *LotStateDTO
+	cancel context.CancelFunc
+	// orgID is the organization of the recorded lot, a replay is only visible inside it
+	orgID uuid.UUID
+}
+
+// visibleIn reports if the replay is visible to the organization ctx is scoped to
+func (r *replay) visibleIn(ctx context.Context) bool {
+	orgID, ok := tenant.OrgID(ctx)
+	return !ok || orgID == r.orgID
+}
+
+// ReplayEngine re-runs the stored events of a lot at a configurable speed. each replay is published as a
+// lot of its own, with the replay ID as lot ID, so the WS clients connected to it receive the recorded
+// updates as if they were live. replays are read-only, they never touch the recorded lot
+type ReplayEngine struct {
+	lotRepo    domain.AuctionLotRepository
+	eventStore domain.LotEventStore
+	updates    *LotUpdateBroker
+	// retention is how long a finished replay keeps serving its final state
+	retention time.Duration
+
+	mu      sync.Mutex
+	replays map[uuid.UUID]*replay
+}
+
+// NewReplayEngine creates a new instance of ReplayEngine
+func NewReplayEngine(lotRepo domain.AuctionLotRepository, eventStore domain.LotEventStore, updates *LotUpdateBroker, retention time.Duration) *ReplayEngine {
+	return &ReplayEngine{
+		lotRepo:    lotRepo,
+		eventStore: eventStore,
+		updates:    updates,
+		retention:  retention,
+		replays:    make(map[uuid.UUID]*replay),
+	}
+}
+
+// Start loads the recorded events of the lot and starts replaying them in the background, the replay
+// runs until its last event or until Stop, ctx only bounds the loading
+func (e *ReplayEngine) Start(ctx context.Context, cmd StartReplayDTO) (*ReplayDTO, error) {
+	if cmd.Speed <= 0 || cmd.Speed > MaxReplaySpeed {
+		return nil, fmt.Errorf("%w: speed must be greater than 0 and at most %d", ErrInvalidReplay, MaxReplaySpeed)
+	}
+	lot, err := e.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("replay engine: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	events, err := e.loadEvents(ctx, cmd.LotID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: lot %s has no recorded events", ErrInvalidReplay, cmd.LotID)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r := &replay{
+		dto: ReplayDTO{
+			ID:        uuid.New(),
+			LotID:     lot.ID,
+			Speed:     cmd.Speed,
+			Events:    len(events),
+			StartedAt: time.Now(),
+		},
+		state: &LotStateDTO{
+			Title:        "[replay] " + lot.Title,
+			Description:  lot.Description,
+			InitialPrice: lot.InitialPrice,
+			CurrentPrice: lot.InitialPrice,
+			Currency:     lot.Currency,
+			Type:         string(lot.Type),
+			EndTime:      lot.EndTime,
+			State:        string(domain.StatePending),
+			Media:        []LotMediaDTO{},
+			Categories:   []CategoryDTO{},
+		},
+		cancel: cancel,
+		orgID:  lot.OrgID,
+	}
+	r.state.LotID = r.dto.ID
+
+	e.mu.Lock()
+	e.replays[r.dto.ID] = r
+	e.mu.Unlock()
+
+	// copied before the replay starts updating it
+	dto := r.dto
+	clock := &replayClock{origin: events[0].OccurredAt, started: time.Now(), speed: cmd.Speed}
+	go e.run(runCtx, r, clock, events)
+
+	logger.FromContext(ctx).Info("Replay started",
+		zap.String("replayID", dto.ID.String()),
+		zap.String("lotID", lot.ID.String()),
+		zap.Float64("speed", cmd.Speed),
+		zap.Int("events", len(events)),
+	)
+	return &dto, nil
+}
+
+// loadEvents reads every stored event of the lot, page by page
+func (e *ReplayEngine) loadEvents(ctx context.Context, lotID uuid.UUID) ([]domain.Event, error) {
+	var events []domain.Event
+	afterSeq := int64(0)
+	for {
+		page, err := e.eventStore.GetSince(ctx, lotID, afterSeq, MaxReplayEvents)
+		if err != nil {
+			return nil, fmt.Errorf("replay engine: failed to get events of lot %s: %w", lotID, err)
+		}
+		events = append(events, page...)
+		if len(page) < MaxReplayEvents {
+			return events, nil
+		}
+		afterSeq = page[len(page)-1].Seq
+	}
+}
+
+// run publishes the state after each event when the replay clock reaches the event time
+func (e *ReplayEngine) run(ctx context.Context, r *replay, clock *replayClock, events []domain.Event) {
+	defer e.finish(r)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for _, event := range events {
+		if wait := event.OccurredAt.Sub(clock.Now()); wait > 0 {
+			timer.Reset(time.Duration(float64(wait) / clock.speed))
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		e.mu.Lock()
+		state := *r.state
+		if err := applyReplayEvent(&state, event); err != nil {
+			log.Warn("Replay: skipping undecodable event",
+				zap.String("replayID", r.dto.ID.String()),
+				zap.Int64("seq", event.Seq),
+				zap.Error(err),
+			)
+		}
+		r.state = &state
+		r.dto.Played++
+		e.mu.Unlock()
+		e.updates.Publish(&state)
+	}
+}
+
+// finish marks the replay as done and forgets it after the retention
+func (e *ReplayEngine) finish(r *replay) {
+	e.mu.Lock()
+	r.dto.Done = true
+	e.mu.Unlock()
+	time.AfterFunc(e.retention, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.replays[r.dto.ID] == r {
+			delete(e.replays, r.dto.ID)
+		}
+	})
+}
+
+// Stop ends a replay and forgets it right away
+func (e *ReplayEngine) Stop(ctx context.Context, id uuid.UUID) error {
+	e.mu.Lock()
+	r, ok := e.replays[id]
+	if ok && !r.visibleIn(ctx) {
+		ok = false
+	}
+	if ok {
+		delete(e.replays, id)
+	}
+	e.mu.Unlock()
+	if !ok {
+		return ErrReplayNotFound
+	}
+	r.cancel()
+	return nil
+}
+
+// List returns the running and recently finished replays
+func (e *ReplayEngine) List(ctx context.Context) []*ReplayDTO {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	replays := make([]*ReplayDTO, 0, len(e.replays))
+	for _, r := range e.replays {
+		if !r.visibleIn(ctx) {
+			continue
+		}
+		dto := r.dto
+		replays = append(replays, &dto)
+	}
+	return replays
+}
+
+// State returns the current state of the replay with the given ID, false if id is not a replay
+func (e *ReplayEngine) State(ctx context.Context, id uuid.UUID) (*LotStateDTO, bool) {
+	if e == nil {
+		return nil, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.replays[id]
+	if !ok || !r.visibleIn(ctx) {
+		return nil, false
+	}
+	return r.state, true
+}
+
+// applyReplayEvent updates state with a recorded event
+func applyReplayEvent(state *LotStateDTO, event domain.Event) error {
+	state.Seq = event.Seq
+	switch event.Type {
+	case domain.EventLotStarted:
+		var p domain.LotStartedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		state.State = string(domain.StateActive)
+		state.InitialPrice, state.CurrentPrice, state.EndTime = p.InitialPrice, p.InitialPrice, p.EndTime
+	case domain.EventBidPlaced:
+		var p domain.BidPlacedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		at := event.OccurredAt
+		state.CurrentPrice, state.EndTime = p.CurrentPrice, p.EndTime
+		state.LastBidAmount, state.LastBidUserID, state.LastBidPaddle, state.LastBidTime = p.Amount, p.UserID, p.Paddle, &at
+	case domain.EventBidVoided:
+		var p domain.BidVoidedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		state.CurrentPrice = p.CurrentPrice
+		// the leading bid was voided, the recorded events don't say which bid leads now
+		if state.LastBidUserID == p.UserID && state.LastBidAmount == p.Amount {
+			state.LastBidAmount, state.LastBidUserID, state.LastBidPaddle, state.LastBidTime = 0, uuid.Nil, 0, nil
+		}
+	case domain.EventLotFinished:
+		var p domain.LotFinishedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		state.State = string(domain.StateFinished)
+		state.CurrentPrice, state.EndTime = p.FinalPrice, p.EndTime
+	case domain.EventLotCancelled:
+		state.State = string(domain.StateCancelled)
+	case domain.EventLotUpdated:
+		var p domain.LotUpdatedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		state.Title, state.Description, state.EndTime = p.Title, p.Description, p.EndTime
+		state.InitialPrice, state.CurrentPrice = p.InitialPrice, p.InitialPrice
+	case domain.EventLotPaused:
+		at := event.OccurredAt
+		state.State, state.PausedAt = string(domain.StatePaused), &at
+	case domain.EventLotResumed:
+		var p domain.LotResumedPayload
+		if err := decodePayload(event, &p); err != nil {
+			return err
+		}
+		state.State, state.PausedAt = string(domain.StateActive), nil
+		state.EndTime = p.EndTime
+	}
+	return nil
+}
+
+// decodePayload decodes the payload of an event read from the store, stored as raw JSON
+func decodePayload(event domain.Event, target any) error {
+	raw, ok := event.Payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event.Payload); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, target)
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchLotsDTO is the input of SearchLotsUseCase
+type SearchLotsDTO struct {
+	Query string
+	State string
+	// Category is a category ID or slug, its subcategories are included
+	Category string
+	Limit    int
+	Offset   int
+}
+
+// SearchLotsUseCase finds lots by title/description with Postgres full-text search
+type SearchLotsUseCase struct {
+	searchRepo   domain.LotSearchRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewSearchLotsUseCase creates a new instance of SearchLotsUseCase
+func NewSearchLotsUseCase(searchRepo domain.LotSearchRepository, categoryRepo domain.CategoryRepository) *SearchLotsUseCase {
+	return &SearchLotsUseCase{searchRepo: searchRepo, categoryRepo: categoryRepo}
+}
+
+func (uc *SearchLotsUseCase) Execute(ctx context.Context, cmd SearchLotsDTO) ([]*LotStateDTO, error) {
+	criteria := domain.LotSearchCriteria{
+		Query: strings.TrimSpace(cmd.Query),
+		State: domain.AuctionLotState(cmd.State),
+	}
+	criteria.Limit, criteria.Offset = pageBounds(cmd.Limit, cmd.Offset)
+	switch criteria.State {
+	case "", domain.StatePending, domain.StatePreview, domain.StateActive, domain.StatePaused, domain.StateFinished, domain.StateCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unknown state %q", ErrInvalidSearch, cmd.State)
+	}
+	if cmd.Category != "" {
+		category, err := uc.resolveCategory(ctx, cmd.Category)
+		if err != nil {
+			return nil, fmt.Errorf("search lots use case: %w", err)
+		}
+		criteria.CategoryID = &category.ID
+	}
+
+	lots, err := uc.searchRepo.Search(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("search lots use case: %w", err)
+	}
+	dtos := make([]*LotStateDTO, 0, len(lots))
+	for _, lot := range lots {
+		dtos = append(dtos, newLotStateDTO(lot))
+	}
+	if err := attachCategories(ctx, uc.categoryRepo, dtos...); err != nil {
+		return nil, fmt.Errorf("search lots use case: %w", err)
+	}
+	return dtos, nil
+}
+
+// resolveCategory finds a category by ID or slug
+func (uc *SearchLotsUseCase) resolveCategory(ctx context.Context, idOrSlug string) (*domain.Category, error) {
+	if id, err := uuid.Parse(idOrSlug); err == nil {
+		return uc.categoryRepo.GetByID(ctx, id)
+	}
+	return uc.categoryRepo.GetBySlug(ctx, idOrSlug)
+}
@@ -2,9 +2,13 @@ package application
 
 import (
 	"context"
+	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // AuctionService defines application interface layer of auction module
@@ -14,27 +18,368 @@ type AuctionService interface {
 	// receives a command with necesary data and returns the created bid or an error
 	PlaceBid(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error)
 	GetLotState(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error)
+	ListActiveLots(ctx context.Context) ([]*LotStateDTO, error)
+	// SearchLots finds lots by title/description, optionally filtered by state
+	SearchLots(ctx context.Context, cmd SearchLotsDTO) ([]*LotStateDTO, error)
+	// CreateLot creates a new pending lot
+	CreateLot(ctx context.Context, cmd CreateLotDTO) (*LotStateDTO, error)
+	// UpdateLot edits the listing of a lot not opened yet, its connected clients get the updated state
+	UpdateLot(ctx context.Context, cmd UpdateLotDTO) (*LotStateDTO, error)
+	// StartLot opens a pending lot for bidding
+	StartLot(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error)
+	// CancelLot cancels a pending or active lot
+	CancelLot(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error)
+	// PauseLot suspends the bidding of an active lot, by is the auctioneer or admin pausing it
+	PauseLot(ctx context.Context, lotID, by uuid.UUID, reason string) (*LotStateDTO, error)
+	// ResumeLot reopens the bidding of a paused lot
+	ResumeLot(ctx context.Context, lotID, by uuid.UUID) (*LotStateDTO, error)
+	// VoidBid retracts a bid of an active lot and broadcasts the corrected lot state
+	VoidBid(ctx context.Context, cmd VoidBidDTO) (*LotStateDTO, error)
+	// GetBidderPaddle returns the paddle of the user on the lot, 0 if the user never bid on it
+	GetBidderPaddle(ctx context.Context, lotID, userID uuid.UUID) (int, error)
+	// FinalizeLot finishes an ended lot, determines its winner and notifies watchers and downstream consumers
+	FinalizeLot(ctx context.Context, lotID uuid.UUID) (*FinalizeLotResult, error)
+	// WatchLot subscribes to the state updates of a lot, the returned func cancels the subscription
+	WatchLot(lotID uuid.UUID) (<-chan *LotStateDTO, func())
+	// WatchAllLots subscribes to the state updates of every lot
+	WatchAllLots() (<-chan *LotStateDTO, func())
+	// WaitForLotChange blocks until the lot state has a seq greater than sinceSeq or ctx is done,
+	// it returns the latest known state in both cases
+	WaitForLotChange(ctx context.Context, lotID uuid.UUID, sinceSeq int64) (*LotStateDTO, error)
+	// GetLotEventsSince returns the stored events of a lot after afterSeq, used to replay missed events
+	GetLotEventsSince(ctx context.Context, lotID uuid.UUID, afterSeq int64) (*LotEventsDTO, error)
+	// StartReplay re-runs the recorded events of a lot, the replay is watched as a lot with the replay ID
+	StartReplay(ctx context.Context, cmd StartReplayDTO) (*ReplayDTO, error)
+	// StopReplay ends a replay
+	StopReplay(ctx context.Context, replayID uuid.UUID) error
+	// ListReplays returns the running and recently finished replays
+	ListReplays(ctx context.Context) []*ReplayDTO
+	// BidQueueHint returns the load of the command queue a new bid on the lot would join
+	BidQueueHint(lotID uuid.UUID) QueueHint
 }
 
 // concret implementation of AuctionService (struct)
 type auctionService struct {
-	placeBidUC    *PlaceBidUseCase
-	getLotStateUC *GetLotStateUseCase
+	placeBidUC       *PlaceBidUseCase
+	getLotStateUC    *GetLotStateUseCase
+	listActiveLotsUC *ListActiveLotsUseCase
+	finalizeLotUC    *FinalizeLotUseCase
+	lotEventsUC      *GetLotEventsUseCase
+	createLotUC      *CreateLotUseCase
+	updateLotUC      *UpdateLotUseCase
+	lifecycleUC      *LotLifecycleUseCase
+	searchLotsUC     *SearchLotsUseCase
+	voidBidUC        *VoidBidUseCase
+	updates          *LotUpdateBroker
+	events           domain.EventPublisher
+	commands         *LotCommandQueue
+	replays          *ReplayEngine
 }
 
-func NewAuctionService(placeBidUC *PlaceBidUseCase, getLotStateUC *GetLotStateUseCase) AuctionService {
-	return &auctionService{
-		placeBidUC:    placeBidUC,
-		getLotStateUC: getLotStateUC,
+// NewAuctionService creates the AuctionService, the state changes of each lot run in commands
+func NewAuctionService(placeBidUC *PlaceBidUseCase,
+	getLotStateUC *GetLotStateUseCase,
+	listActiveLotsUC *ListActiveLotsUseCase,
+	finalizeLotUC *FinalizeLotUseCase,
+	lotEventsUC *GetLotEventsUseCase,
+	createLotUC *CreateLotUseCase,
+	updateLotUC *UpdateLotUseCase,
+	lifecycleUC *LotLifecycleUseCase,
+	searchLotsUC *SearchLotsUseCase,
+	voidBidUC *VoidBidUseCase,
+	updates *LotUpdateBroker,
+	events domain.EventPublisher,
+	commands *LotCommandQueue,
+	replays *ReplayEngine) AuctionService {
+	as := &auctionService{
+		placeBidUC:       placeBidUC,
+		getLotStateUC:    getLotStateUC,
+		listActiveLotsUC: listActiveLotsUC,
+		finalizeLotUC:    finalizeLotUC,
+		lotEventsUC:      lotEventsUC,
+		createLotUC:      createLotUC,
+		updateLotUC:      updateLotUC,
+		lifecycleUC:      lifecycleUC,
+		searchLotsUC:     searchLotsUC,
+		voidBidUC:        voidBidUC,
+		updates:          updates,
+		events:           events,
+		commands:         commands,
+		replays:          replays,
 	}
+	commands.placeBids = as.placeBidBatch
+	commands.lotEnd = as.lotEnd
+	return as
 }
 
-// PlaceBid implements AuctionService.
+// PlaceBid implements AuctionService, publishing the updated lot state to watchers on success.
+// bids of a lot are applied one at a time in the lot command queue, so they are published in the same order
 func (as *auctionService) PlaceBid(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error) {
-	return as.placeBidUC.Execute(ctx, cmd)
+	return as.commands.doBid(ctx, cmd, func(ctx context.Context, cmd PlaceBidDTO) (*domain.Bid, error) {
+		res, err := as.placeBidUC.Execute(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		as.publishLotState(ctx, cmd.LotID)
+		as.publishEvents(ctx, bidEvents(res)...)
+		return res.Bid, nil
+	})
+}
+
+// placeBidBatch stores a batch of bids of a lot, the lot state is published once with the last accepted bid
+func (as *auctionService) placeBidBatch(ctx context.Context, cmds []PlaceBidDTO) ([]*domain.Bid, []error) {
+	bids := make([]*domain.Bid, len(cmds))
+	results, errs, err := as.placeBidUC.ExecuteBatch(ctx, cmds)
+	if err != nil {
+		errs = make([]error, len(cmds))
+		for i := range errs {
+			errs[i] = err
+		}
+		return bids, errs
+	}
+
+	var events []domain.Event
+	for i, res := range results {
+		if res != nil {
+			bids[i] = res.Bid
+			events = append(events, bidEvents(res)...)
+		}
+	}
+	if len(events) > 0 {
+		as.publishLotState(ctx, cmds[0].LotID)
+		as.publishEvents(ctx, events...)
+	}
+	return bids, errs
+}
+
+// bidEvents returns the stored events of a placed bid plus the outbid event of the previous leader.
+// outbid events are private to the user and are not stored in the lot event store,
+// raising your own bid doesn't outbid you
+func bidEvents(res *PlaceBidResult) []domain.Event {
+	bid, events := res.Bid, res.Events
+	if prev := res.PreviousLeadingBid; prev != nil && prev.UserID != bid.UserID {
+		events = append(events, domain.NewEvent(domain.EventUserOutbid, bid.LotID, bid.Timestamp, domain.UserOutbidPayload{
+			UserID:         prev.UserID,
+			PreviousBidID:  prev.ID,
+			PreviousAmount: prev.Amount,
+			NewBidID:       bid.ID,
+			NewAmount:      bid.Amount,
+		}))
+	}
+	return events
+}
+
+// FinalizeLot implements AuctionService, queued behind the pending bids of the lot
+func (as *auctionService) FinalizeLot(ctx context.Context, lotID uuid.UUID) (*FinalizeLotResult, error) {
+	var res *FinalizeLotResult
+	err := as.commands.Do(ctx, lotID, func(ctx context.Context) error {
+		var err error
+		if res, err = as.finalizeLotUC.Execute(ctx, lotID); err != nil {
+			return err
+		}
+		as.publishLotState(ctx, lotID)
+		as.publishEvents(ctx, res.Events...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateLot implements AuctionService
+func (as *auctionService) CreateLot(ctx context.Context, cmd CreateLotDTO) (*LotStateDTO, error) {
+	lot, err := as.createLotUC.Execute(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return as.getLotStateUC.Execute(db.WithPrimaryReads(ctx), lot.ID)
+}
+
+// UpdateLot implements AuctionService
+func (as *auctionService) UpdateLot(ctx context.Context, cmd UpdateLotDTO) (*LotStateDTO, error) {
+	return as.transition(ctx, cmd.LotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.updateLotUC.Execute(ctx, cmd)
+	})
+}
+
+// StartLot implements AuctionService
+func (as *auctionService) StartLot(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error) {
+	return as.transition(ctx, lotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.lifecycleUC.Start(ctx, lotID)
+	})
+}
+
+// CancelLot implements AuctionService
+func (as *auctionService) CancelLot(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error) {
+	return as.transition(ctx, lotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.lifecycleUC.Cancel(ctx, lotID)
+	})
+}
+
+// PauseLot implements AuctionService
+func (as *auctionService) PauseLot(ctx context.Context, lotID, by uuid.UUID, reason string) (*LotStateDTO, error) {
+	return as.transition(ctx, lotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.lifecycleUC.Pause(ctx, lotID, by, reason)
+	})
+}
+
+// ResumeLot implements AuctionService
+func (as *auctionService) ResumeLot(ctx context.Context, lotID, by uuid.UUID) (*LotStateDTO, error) {
+	return as.transition(ctx, lotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.lifecycleUC.Resume(ctx, lotID, by)
+	})
+}
+
+// VoidBid implements AuctionService
+func (as *auctionService) VoidBid(ctx context.Context, cmd VoidBidDTO) (*LotStateDTO, error) {
+	return as.transition(ctx, cmd.LotID, func(ctx context.Context) (*LotTransitionResult, error) {
+		return as.voidBidUC.Execute(ctx, cmd)
+	})
+}
+
+// transition runs a lifecycle transition of the lot in its command queue and publishes the result
+func (as *auctionService) transition(ctx context.Context, lotID uuid.UUID, fn func(ctx context.Context) (*LotTransitionResult, error)) (*LotStateDTO, error) {
+	var state *LotStateDTO
+	err := as.commands.Do(ctx, lotID, func(ctx context.Context) error {
+		res, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		state, err = as.publishTransition(ctx, res)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// publishTransition publishes the lot state and events of a lifecycle transition and returns the new state
+func (as *auctionService) publishTransition(ctx context.Context, res *LotTransitionResult) (*LotStateDTO, error) {
+	as.publishEvents(ctx, res.Events...)
+	state, err := as.getLotStateUC.Execute(db.WithPrimaryReads(ctx), res.Lot.ID)
+	if err != nil {
+		return nil, err
+	}
+	as.updates.PublishChange(ctx, state)
+	return state, nil
 }
 
 // GetLotState to implementss AuctionService
 func (as *auctionService) GetLotState(ctx context.Context, lotID uuid.UUID) (*LotStateDTO, error) {
+	// clients watching a replay connect with the replay ID as lot ID
+	if state, ok := as.replays.State(ctx, lotID); ok {
+		return state, nil
+	}
 	return as.getLotStateUC.Execute(ctx, lotID)
 }
+
+// GetBidderPaddle implements AuctionService
+func (as *auctionService) GetBidderPaddle(ctx context.Context, lotID, userID uuid.UUID) (int, error) {
+	return as.getLotStateUC.GetBidderPaddle(ctx, lotID, userID)
+}
+
+// ListActiveLots implements AuctionService
+func (as *auctionService) ListActiveLots(ctx context.Context) ([]*LotStateDTO, error) {
+	return as.listActiveLotsUC.Execute(ctx)
+}
+
+// GetLotEventsSince implements AuctionService
+func (as *auctionService) GetLotEventsSince(ctx context.Context, lotID uuid.UUID, afterSeq int64) (*LotEventsDTO, error) {
+	return as.lotEventsUC.Execute(ctx, lotID, afterSeq)
+}
+
+// StartReplay implements AuctionService
+func (as *auctionService) StartReplay(ctx context.Context, cmd StartReplayDTO) (*ReplayDTO, error) {
+	return as.replays.Start(ctx, cmd)
+}
+
+// StopReplay implements AuctionService
+func (as *auctionService) StopReplay(ctx context.Context, replayID uuid.UUID) error {
+	return as.replays.Stop(ctx, replayID)
+}
+
+// ListReplays implements AuctionService
+func (as *auctionService) ListReplays(ctx context.Context) []*ReplayDTO {
+	return as.replays.List(ctx)
+}
+
+// SearchLots implements AuctionService
+func (as *auctionService) SearchLots(ctx context.Context, cmd SearchLotsDTO) ([]*LotStateDTO, error) {
+	return as.searchLotsUC.Execute(ctx, cmd)
+}
+
+// WaitForLotChange implements AuctionService, subscribing before loading the state from the primary
+// so a change committed in between is not missed
+func (as *auctionService) WaitForLotChange(ctx context.Context, lotID uuid.UUID, sinceSeq int64) (*LotStateDTO, error) {
+	updates, cancel := as.updates.Subscribe(lotID)
+	defer cancel()
+
+	state, err := as.getLotStateUC.Execute(db.WithPrimaryReads(ctx), lotID)
+	if err != nil {
+		return nil, err
+	}
+	for state.Seq <= sinceSeq {
+		select {
+		case <-ctx.Done():
+			return state, nil
+		case update, ok := <-updates:
+			if !ok {
+				return state, nil
+			}
+			if update.Seq > state.Seq {
+				state = update
+			}
+		}
+	}
+	return state, nil
+}
+
+// WatchLot implements AuctionService
+func (as *auctionService) WatchLot(lotID uuid.UUID) (<-chan *LotStateDTO, func()) {
+	return as.updates.Subscribe(lotID)
+}
+
+// WatchAllLots implements AuctionService
+func (as *auctionService) WatchAllLots() (<-chan *LotStateDTO, func()) {
+	return as.updates.SubscribeAll()
+}
+
+// publishLotState loads the current lot state and publishes it, failures are only logged
+// because the change itself was already committed. it's read from the primary, a lagging
+// replica would publish the state before the change
+func (as *auctionService) publishLotState(ctx context.Context, lotID uuid.UUID) {
+	state, err := as.getLotStateUC.Execute(db.WithPrimaryReads(ctx), lotID)
+	if err != nil {
+		logger.FromContext(ctx).Error("AuctionService: failed to load lot state for publishing",
+			zap.String("lotID", lotID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	as.commands.ObserveEnd(lotID, state.EndTime)
+	as.updates.PublishChange(ctx, state)
+}
+
+// lotEnd returns the current end time of a lot, for the fairness queue of the lot commands
+func (as *auctionService) lotEnd(ctx context.Context, lotID uuid.UUID) (time.Time, error) {
+	state, err := as.getLotStateUC.Execute(ctx, lotID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return state.EndTime, nil
+}
+
+// publishEvents sends domain events to downstream consumers, failures are only logged
+// because the change itself was already committed
+func (as *auctionService) publishEvents(ctx context.Context, events ...domain.Event) {
+	if err := as.events.Publish(ctx, events...); err != nil {
+		logger.FromContext(ctx).Error("AuctionService: failed to publish domain events", zap.Int("events", len(events)), zap.Error(err))
+	}
+}
+
+// BidQueueHint implements AuctionService
+func (as *auctionService) BidQueueHint(lotID uuid.UUID) QueueHint {
+	return as.commands.Hint(lotID)
+}
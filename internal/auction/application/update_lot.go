@@ -0,0 +1,127 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// UpdateLotDTO is the input of UpdateLotUseCase, nil fields are left as they are
+type UpdateLotDTO struct {
+	LotID        uuid.UUID
+	UpdatedBy    uuid.UUID // admin editing the lot
+	Title        *string
+	Description  *string
+	InitialPrice *float64
+	EndTime      *time.Time
+}
+
+// UpdateLotUseCase edits the listing of a lot before it opens, the change is recorded in the lot event log
+type UpdateLotUseCase struct {
+	lotRepo    domain.AuctionLotRepository
+	eventStore domain.LotEventStore
+	transactor domain.Transactor
+	clock      domain.Clock
+}
+
+// NewUpdateLotUseCase creates a new instance of UpdateLotUseCase
+func NewUpdateLotUseCase(lotRepo domain.AuctionLotRepository,
+	eventStore domain.LotEventStore,
+	transactor domain.Transactor,
+	clock domain.Clock) *UpdateLotUseCase {
+	return &UpdateLotUseCase{
+		lotRepo:    lotRepo,
+		eventStore: eventStore,
+		transactor: transactor,
+		clock:      clock,
+	}
+}
+
+// Execute applies cmd to a pending or preview lot and stores it with an EventLotUpdated listing the changes.
+// it returns ErrInvalidLot on invalid data and domain.ErrLotAlreadyStartedOrFinished for lots already open
+func (uc *UpdateLotUseCase) Execute(ctx context.Context, cmd UpdateLotDTO) (res *LotTransitionResult, err error) {
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("update lot use case: failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			res, err = nil, fmt.Errorf("update lot use case: failed to commit transaction: %w", commitErr)
+		}
+	}()
+
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("update lot use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	title, description, initialPrice, endTime := lot.Title, lot.Description, lot.InitialPrice, lot.EndTime
+	var changes []domain.LotFieldChange
+	if cmd.Title != nil && strings.TrimSpace(*cmd.Title) != title {
+		changes = append(changes, domain.LotFieldChange{Field: "title", From: title, To: strings.TrimSpace(*cmd.Title)})
+		title = strings.TrimSpace(*cmd.Title)
+	}
+	if cmd.Description != nil && *cmd.Description != description {
+		changes = append(changes, domain.LotFieldChange{Field: "description", From: description, To: *cmd.Description})
+		description = *cmd.Description
+	}
+	if cmd.InitialPrice != nil && *cmd.InitialPrice != initialPrice {
+		changes = append(changes, domain.LotFieldChange{Field: "initial_price", From: initialPrice, To: *cmd.InitialPrice})
+		initialPrice = *cmd.InitialPrice
+	}
+	if cmd.EndTime != nil && !cmd.EndTime.Equal(endTime) {
+		changes = append(changes, domain.LotFieldChange{Field: "end_time", From: endTime, To: *cmd.EndTime})
+		endTime = *cmd.EndTime
+	}
+	switch {
+	case len(changes) == 0:
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidLot)
+	case title == "":
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidLot)
+	case initialPrice <= 0:
+		return nil, fmt.Errorf("%w: initial price must be greater than zero", ErrInvalidLot)
+	case !endTime.After(uc.clock.Now()):
+		return nil, fmt.Errorf("%w: end time must be in the future", ErrInvalidLot)
+	case lot.StartTime != nil && !lot.StartTime.Before(endTime):
+		return nil, fmt.Errorf("%w: end time must be after the start time", ErrInvalidLot)
+	}
+
+	if err = lot.UpdateListing(title, description, initialPrice, endTime); err != nil {
+		return nil, fmt.Errorf("update lot use case: %w", err)
+	}
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, fmt.Errorf("update lot use case: failed to save auction lot %s: %w", cmd.LotID, err)
+	}
+	event := domain.NewEvent(domain.EventLotUpdated, lot.ID, uc.clock.Now(), domain.LotUpdatedPayload{
+		UpdatedBy:    cmd.UpdatedBy,
+		Changes:      changes,
+		Title:        lot.Title,
+		Description:  lot.Description,
+		InitialPrice: lot.InitialPrice,
+		EndTime:      lot.EndTime,
+	})
+	events, err := uc.eventStore.Append(ctx, tx, lot.ID, event)
+	if err != nil {
+		return nil, fmt.Errorf("update lot use case: failed to append event for lot %s: %w", cmd.LotID, err)
+	}
+
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	logger.FromContext(ctx).Info("UpdateLotUseCase: lot updated",
+		zap.String("lotID", lot.ID.String()),
+		zap.String("updatedBy", cmd.UpdatedBy.String()),
+		zap.Strings("fields", fields),
+	)
+	return &LotTransitionResult{Lot: lot, Events: events}, nil
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// UserBidsPageDTO is the input of UserBidsUseCase queries
+type UserBidsPageDTO struct {
+	UserID uuid.UUID
+	State  string // lot state filter, only used by Lots
+	Limit  int
+	Offset int
+}
+
+// UserBidDTO is a bid of the user history
+type UserBidDTO struct {
+	BidID     uuid.UUID `json:"bid_id"`
+	LotID     uuid.UUID `json:"lot_id"`
+	Amount    float64   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+	// VoidedAt is set when an admin retracted the bid
+	VoidedAt *time.Time `json:"voided_at,omitempty"`
+}
+
+// UserLotDTO is a lot the user bid on with the user position on it
+type UserLotDTO struct {
+	LotID        uuid.UUID `json:"lot_id"`
+	Title        string    `json:"title"`
+	State        string    `json:"state"`
+	CurrentPrice float64   `json:"current_price"`
+	Currency     string    `json:"currency"`
+	EndTime      time.Time `json:"end_time"`
+	Seq          int64     `json:"seq"`
+	HighestBid   float64   `json:"highest_bid"` // highest amount bid by the user
+	BidCount     int       `json:"bid_count"`
+	LastBidAt    time.Time `json:"last_bid_at"`
+	Leading      bool      `json:"leading"`
+}
+
+// UserBidsUseCase retrieves the bid history of a user and the lots they bid on
+type UserBidsUseCase struct {
+	bidRepo domain.BidRepository
+}
+
+// NewUserBidsUseCase creates a new instance of UserBidsUseCase
+func NewUserBidsUseCase(bidRepo domain.BidRepository) *UserBidsUseCase {
+	return &UserBidsUseCase{bidRepo: bidRepo}
+}
+
+// Bids returns a page of the user bids, newest first
+func (uc *UserBidsUseCase) Bids(ctx context.Context, cmd UserBidsPageDTO) ([]UserBidDTO, error) {
+	limit, offset := pageBounds(cmd.Limit, cmd.Offset)
+	bids, err := uc.bidRepo.GetBidsByUserID(ctx, cmd.UserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("user bids use case: failed to get bids of user %s: %w", cmd.UserID, err)
+	}
+	dtos := make([]UserBidDTO, 0, len(bids))
+	for _, bid := range bids {
+		dtos = append(dtos, UserBidDTO{
+			BidID:     bid.ID,
+			LotID:     bid.LotID,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+			VoidedAt:  bid.VoidedAt,
+		})
+	}
+	return dtos, nil
+}
+
+// Lots returns a page of the lots the user bid on, most recently bid first
+func (uc *UserBidsUseCase) Lots(ctx context.Context, cmd UserBidsPageDTO) ([]UserLotDTO, error) {
+	state := domain.AuctionLotState(cmd.State)
+	switch state {
+	case "", domain.StatePending, domain.StatePreview, domain.StateActive, domain.StatePaused, domain.StateFinished, domain.StateCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unknown state %q", ErrInvalidSearch, cmd.State)
+	}
+	limit, offset := pageBounds(cmd.Limit, cmd.Offset)
+	summaries, err := uc.bidRepo.GetLotsByBidderID(ctx, cmd.UserID, state, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("user bids use case: failed to get lots of user %s: %w", cmd.UserID, err)
+	}
+	dtos := make([]UserLotDTO, 0, len(summaries))
+	for _, s := range summaries {
+		dtos = append(dtos, UserLotDTO{
+			LotID:        s.Lot.ID,
+			Title:        s.Lot.Title,
+			State:        string(s.Lot.State),
+			CurrentPrice: s.Lot.CurrentPrice,
+			Currency:     s.Lot.Currency,
+			EndTime:      s.Lot.EndTime,
+			Seq:          s.Lot.Seq,
+			HighestBid:   s.HighestBid,
+			BidCount:     s.BidCount,
+			LastBidAt:    s.LastBidAt,
+			Leading:      s.Leading,
+		})
+	}
+	return dtos, nil
+}
+
+// pageBounds applies the default and max page size of the listing endpoints
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	return min(limit, maxSearchLimit), max(offset, 0)
+}
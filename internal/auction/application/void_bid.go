@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// VoidBidDTO is the input of VoidBidUseCase
+type VoidBidDTO struct {
+	LotID   uuid.UUID
+	BidID   uuid.UUID
+	AdminID uuid.UUID // admin retracting the bid, recorded in the lot events
+	Reason  string
+}
+
+// VoidBidUseCase retracts a bid of an active lot (fat-finger protection), an admin operation.
+// the bid is kept but ignored from then on, and the void is recorded in the lot event store
+type VoidBidUseCase struct {
+	lotRepo      domain.AuctionLotRepository
+	bidRepo      domain.BidRepository
+	eventStore   domain.LotEventStore
+	reservations domain.BidReservationRepository
+	transactor   domain.Transactor
+	clock        domain.Clock
+}
+
+// NewVoidBidUseCase creates a new instance of VoidBidUseCase
+func NewVoidBidUseCase(lotRepo domain.AuctionLotRepository,
+	bidRepo domain.BidRepository,
+	eventStore domain.LotEventStore,
+	reservations domain.BidReservationRepository,
+	transactor domain.Transactor,
+	clock domain.Clock) *VoidBidUseCase {
+	return &VoidBidUseCase{
+		lotRepo:      lotRepo,
+		bidRepo:      bidRepo,
+		eventStore:   eventStore,
+		reservations: reservations,
+		transactor:   transactor,
+		clock:        clock,
+	}
+}
+
+// Execute voids the bid and stores the lot with its recomputed price in a single TX
+func (uc *VoidBidUseCase) Execute(ctx context.Context, cmd VoidBidDTO) (res *LotTransitionResult, err error) {
+	tx, err := uc.transactor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			res, err = nil, fmt.Errorf("void bid use case: failed to commit transaction: %w", commitErr)
+		}
+	}()
+
+	lot, err := uc.lotRepo.GetByID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to get auction lot %s: %w", cmd.LotID, err)
+	}
+	lot.SetClock(uc.clock)
+	bid, err := uc.bidRepo.GetByID(ctx, cmd.BidID)
+	if err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to get bid %s: %w", cmd.BidID, err)
+	}
+	remaining, err := uc.bidRepo.GetBidsByLotID(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to get bids of lot %s: %w", cmd.LotID, err)
+	}
+	reason := strings.TrimSpace(cmd.Reason)
+	if err = lot.VoidBid(bid, remaining, cmd.AdminID, reason); err != nil {
+		return nil, fmt.Errorf("void bid use case: %w", err)
+	}
+
+	if err = uc.bidRepo.Void(ctx, tx, bid); err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to void bid %s: %w", bid.ID, err)
+	}
+	if err = uc.lotRepo.Save(ctx, tx, lot); err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to save auction lot %s: %w", lot.ID, err)
+	}
+	if err = uc.moveReservation(ctx, tx, lot, bid, remaining); err != nil {
+		return nil, fmt.Errorf("void bid use case: %w", err)
+	}
+	events, err := uc.eventStore.Append(ctx, tx, lot.ID, domain.NewEvent(domain.EventBidVoided, lot.ID, uc.clock.Now(), domain.BidVoidedPayload{
+		BidID:        bid.ID,
+		UserID:       bid.UserID,
+		Amount:       bid.Amount,
+		VoidedBy:     cmd.AdminID,
+		Reason:       reason,
+		CurrentPrice: lot.CurrentPrice,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("void bid use case: failed to append event for lot %s: %w", lot.ID, err)
+	}
+
+	logger.FromContext(ctx).Info("VoidBidUseCase: bid voided",
+		zap.String("lotID", lot.ID.String()),
+		zap.String("bidID", bid.ID.String()),
+		zap.String("adminID", cmd.AdminID.String()),
+		zap.Float64("currentPrice", lot.CurrentPrice),
+	)
+	return &LotTransitionResult{Lot: lot, Events: events}, nil
+}
+
+// moveReservation releases the reservation of the voided bidder and reserves the amount of the new
+// leading bid, which wasn't checked against its bidder limit again because it was valid when placed.
+// on a multi-unit lot every standing bid left by the void holds its reservation
+func (uc *VoidBidUseCase) moveReservation(ctx context.Context, tx domain.Tx, lot *domain.AuctionLot, voided *domain.Bid, remaining []*domain.Bid) error {
+	if err := uc.reservations.Release(ctx, tx, voided.UserID, voided.LotID); err != nil {
+		return fmt.Errorf("failed to release reservation of bid %s: %w", voided.ID, err)
+	}
+	if lot.MultiUnit() {
+		for _, b := range lot.Standing {
+			if err := uc.reservations.Reserve(ctx, tx, b.UserID, b.LotID, b.Amount); err != nil {
+				return fmt.Errorf("failed to reserve standing bid %s: %w", b.ID, err)
+			}
+		}
+		return nil
+	}
+	var leading *domain.Bid
+	for _, b := range remaining {
+		if b.ID != voided.ID && (leading == nil || b.Timestamp.After(leading.Timestamp)) {
+			leading = b
+		}
+	}
+	if leading == nil {
+		return nil
+	}
+	if err := uc.reservations.Reserve(ctx, tx, leading.UserID, leading.LotID, leading.Amount); err != nil {
+		return fmt.Errorf("failed to reserve leading bid %s: %w", leading.ID, err)
+	}
+	return nil
+}
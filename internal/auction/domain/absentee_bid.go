@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AbsenteeBidStatus is the processing status of an absentee bid
+type AbsenteeBidStatus string
+
+const (
+	AbsenteeBidPending    AbsenteeBidStatus = "pending"    // waiting for the lot to open
+	AbsenteeBidProcessing AbsenteeBidStatus = "processing" // claimed by the engine entering the bids of the lot
+	AbsenteeBidEntered    AbsenteeBidStatus = "entered"    // placed at least one bid on the lot, bids again when outbid
+	AbsenteeBidFailed     AbsenteeBidStatus = "failed"     // couldn't bid, e.g. outbid by a higher absentee bid
+	AbsenteeBidCancelled  AbsenteeBidStatus = "cancelled"  // withdrawn by the user before the lot opened
+)
+
+// AbsenteeBid is a bid left by a user on a lot not opened yet: when the lot opens, the engine bids the
+// next min bid for the user up to MaxAmount, like a proxy bid, in the order the absentee bids were submitted.
+// once entered it stands as a max: each time the user is outbid the engine bids again up to MaxAmount
+type AbsenteeBid struct {
+	ID        uuid.UUID
+	LotID     uuid.UUID
+	UserID    uuid.UUID
+	MaxAmount float64
+	// ClientIP is the remote IP the absentee bid was submitted from, its bids are placed from it
+	ClientIP string
+	Status   AbsenteeBidStatus
+	// BidID and BidAmount are the last bid placed for the user, set once entered
+	BidID     *uuid.UUID
+	BidAmount float64
+	// Error is why the absentee bid failed
+	Error       string
+	SubmittedAt time.Time
+	ProcessedAt *time.Time
+}
@@ -5,18 +5,253 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
 type AuctionLotRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*AuctionLot, error)
-	Save(ctx context.Context, tx pgx.Tx, lot *AuctionLot) error
+	Save(ctx context.Context, tx Tx, lot *AuctionLot) error
 	GetActiveLots(ctx context.Context) ([]*AuctionLot, error)
-	GetLotsEndingSoon(ctx context.Context, threshold time.Duration) ([]*AuctionLot, error)
+	// GetLotsEndingBy returns the active lots whose end time is at or before deadline
+	GetLotsEndingBy(ctx context.Context, deadline time.Time) ([]*AuctionLot, error)
+	// GetLotsOpeningBy returns the lots in preview whose start time is at or before deadline
+	GetLotsOpeningBy(ctx context.Context, deadline time.Time) ([]*AuctionLot, error)
 }
 
 type BidRepository interface {
-	Save(ctx context.Context, tx pgx.Tx, bid *Bid) error
+	Save(ctx context.Context, tx Tx, bid *Bid) error
+	// SaveBatch inserts many bids in a single round trip, used by the batched bid persistence
+	SaveBatch(ctx context.Context, tx Tx, bids []*Bid) error
+	// GetByID returns a bid, voided or not, or ErrBidNotFound
+	GetByID(ctx context.Context, id uuid.UUID) (*Bid, error)
+	// Void stores the void fields of bid
+	Void(ctx context.Context, tx Tx, bid *Bid) error
+	// GetBidsByLotID, GetLatestBidByLotID and GetBidderIDsByLotID ignore voided bids
 	GetBidsByLotID(ctx context.Context, lotID uuid.UUID) ([]*Bid, error)
 	GetLatestBidByLotID(ctx context.Context, lotID uuid.UUID) (*Bid, error)
+	GetBidderIDsByLotID(ctx context.Context, lotID uuid.UUID) ([]uuid.UUID, error)
+	// GetStandingBids returns the best valid bid of each bidder of the lot, the limit best ones best first,
+	// the bids holding the units of a multi-unit lot
+	GetStandingBids(ctx context.Context, lotID uuid.UUID, limit int) ([]*Bid, error)
+	// GetBidsByUserID returns a page of the user bids, newest first
+	GetBidsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Bid, error)
+	// GetLotsByBidderID returns a page of the lots the user bid on, most recently bid first,
+	// an empty state matches any lot state
+	GetLotsByBidderID(ctx context.Context, userID uuid.UUID, state AuctionLotState, limit, offset int) ([]*UserLotBids, error)
+}
+
+// BidCursor is the position of a bid in the bids of a lot ordered by time, the zero cursor is before the first bid
+type BidCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// BidExportRepository reads every bid of a lot in pages for the exports
+type BidExportRepository interface {
+	// GetLotBidsAfter returns up to limit bids of the lot after the cursor, oldest first, the voided ones
+	// included and with their Paddle set (0 for the bidders without a paddle)
+	GetLotBidsAfter(ctx context.Context, lotID uuid.UUID, after BidCursor, limit int) ([]*Bid, error)
+}
+
+// BidStatsRepository computes the aggregate bid statistics of the lots, over the valid bids only
+type BidStatsRepository interface {
+	// GetLotBidStats aggregates the bids of the lot, RecentBids counts the bids at or after since and at
+	// most maxPoints price points are returned, always with the first and the last bid
+	GetLotBidStats(ctx context.Context, lotID uuid.UUID, since time.Time, maxPoints int) (*LotBidStats, error)
+	// GetLotPriceHistory returns the price points of the bids placed after after (zero time for all),
+	// oldest first and downsampled to maxPoints like the ones of GetLotBidStats
+	GetLotPriceHistory(ctx context.Context, lotID uuid.UUID, after time.Time, maxPoints int) ([]PricePoint, error)
+	// GetLotLeaderboard returns the limit bidders of the lot with the best bids, best first: the highest
+	// bids on forward lots and the lowest ones on reverse lots, equal best bids placed earlier first
+	GetLotLeaderboard(ctx context.Context, lotID uuid.UUID, lotType LotType, limit int) ([]LeaderboardEntry, error)
+}
+
+// BidCounter counts the valid bids of the lots
+type BidCounter interface {
+	CountBidsByLotID(ctx context.Context, lotID uuid.UUID) (int, error)
+}
+
+// PaddleRepository stores the per lot paddle numbers, the public alias of each bidder
+type PaddleRepository interface {
+	// Assign returns the paddle of the user on the lot, assigning a new one on the user first bid
+	Assign(ctx context.Context, tx Tx, lotID, userID uuid.UUID) (int, error)
+	// Get returns the paddle of the user on the lot, 0 if none was assigned
+	Get(ctx context.Context, lotID, userID uuid.UUID) (int, error)
+}
+
+// BiddingLimitProvider provides the max amount a user can commit in leading bids at the same time,
+// implemented over the user_limits table or by an external payment service
+type BiddingLimitProvider interface {
+	// GetLimit returns the user limit, ok is false when no limit applies to the user
+	GetLimit(ctx context.Context, userID uuid.UUID) (limit float64, ok bool, err error)
+}
+
+// BidReservationRepository tracks the amount reserved from the user limit by their leading bid on each lot
+type BidReservationRepository interface {
+	// ReservedTotal locks the user reservations until tx ends and returns their total, excluding lotID
+	ReservedTotal(ctx context.Context, tx Tx, userID, excludeLotID uuid.UUID) (float64, error)
+	// Reserve sets the amount reserved by the user on the lot
+	Reserve(ctx context.Context, tx Tx, userID, lotID uuid.UUID, amount float64) error
+	// Release frees the user reservation on the lot, if any
+	Release(ctx context.Context, tx Tx, userID, lotID uuid.UUID) error
+	// ReleaseLot frees every reservation on the lot
+	ReleaseLot(ctx context.Context, tx Tx, lotID uuid.UUID) error
+}
+
+// FeeScheduleRepository persists the fee schedules and their assignment to lots
+type FeeScheduleRepository interface {
+	Save(ctx context.Context, schedule *FeeSchedule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*FeeSchedule, error)
+	GetAll(ctx context.Context) ([]*FeeSchedule, error)
+	// GetForLot returns the lot schedule, or the default one when the lot has none
+	GetForLot(ctx context.Context, lotID uuid.UUID) (*FeeSchedule, error)
+	// SetLotSchedule assigns a schedule to the lot, nil restores the default one
+	SetLotSchedule(ctx context.Context, lotID uuid.UUID, scheduleID *uuid.UUID) error
+}
+
+// BidIncrementRepository provides the increment table that applies to a lot,
+// a lot specific table takes precedence over the global one
+type BidIncrementRepository interface {
+	GetIncrementTable(ctx context.Context, lotID uuid.UUID) (IncrementTable, error)
+}
+
+// LotEventStore persists the events of a lot with a per lot monotonic sequence
+type LotEventStore interface {
+	// Append stores the events inside tx, assigning their Seq, and returns them
+	Append(ctx context.Context, tx Tx, lotID uuid.UUID, events ...Event) ([]Event, error)
+	// GetSince returns up to limit events of the lot with Seq > afterSeq, ordered by Seq
+	GetSince(ctx context.Context, lotID uuid.UUID, afterSeq int64, limit int) ([]Event, error)
+}
+
+// LotMediaRepository persists the media attached to lots
+type LotMediaRepository interface {
+	// Add inserts the media at the end of the lot media list, setting its Position
+	Add(ctx context.Context, media *LotMedia) error
+	GetByLotID(ctx context.Context, lotID uuid.UUID) ([]*LotMedia, error)
+	// Delete returns ErrMediaNotFound if the media doesn't exist in the lot
+	Delete(ctx context.Context, lotID, mediaID uuid.UUID) error
+}
+
+// DeadLetterRepository persists the inbound client msgs dropped before processing
+type DeadLetterRepository interface {
+	Save(ctx context.Context, letter *DeadLetter) error
+}
+
+// ChatRepository persists the lot chat msgs and mutes
+type ChatRepository interface {
+	Save(ctx context.Context, msg *ChatMessage) error
+	// GetRecent returns the last limit msgs of the lot, oldest first
+	GetRecent(ctx context.Context, lotID uuid.UUID, limit int) ([]*ChatMessage, error)
+	// SaveMute creates or replaces the mute of the user in the lot chat
+	SaveMute(ctx context.Context, mute *ChatMute) error
+	// GetMute returns the mute of the user in the lot chat, nil if none
+	GetMute(ctx context.Context, lotID, userID uuid.UUID) (*ChatMute, error)
+	DeleteMute(ctx context.Context, lotID, userID uuid.UUID) error
+}
+
+// LotSearchCriteria filters a lot search, empty fields are not applied
+type LotSearchCriteria struct {
+	// Query is a free text query over title and description (web search syntax: "quoted", -excluded, or)
+	Query string
+	State AuctionLotState
+	// CategoryID also matches the lots of its subcategories
+	CategoryID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// LotSearchRepository runs full-text searches over the lots
+type LotSearchRepository interface {
+	// Search returns the lots matching criteria, by relevance and then by end time
+	Search(ctx context.Context, criteria LotSearchCriteria) ([]*AuctionLot, error)
+}
+
+// CategoryRepository persists categories and the lot categorization
+type CategoryRepository interface {
+	// Save inserts the category, it returns ErrCategorySlugTaken on duplicated slugs
+	Save(ctx context.Context, category *Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Category, error)
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+	GetAll(ctx context.Context) ([]*Category, error)
+	// GetByLotIDs returns the categories of each lot
+	GetByLotIDs(ctx context.Context, lotIDs ...uuid.UUID) (map[uuid.UUID][]*Category, error)
+	// SetLotCategories replaces the categories of a lot
+	SetLotCategories(ctx context.Context, lotID uuid.UUID, categoryIDs []uuid.UUID) error
+}
+
+// LotAuctioneerRepository persists the auctioneers assigned to the lots
+type LotAuctioneerRepository interface {
+	// Assign adds the assignment, assigning an already assigned auctioneer keeps the first assignment
+	Assign(ctx context.Context, a *LotAuctioneer) error
+	// Unassign returns ErrAuctioneerNotAssigned if the user is not assigned to the lot
+	Unassign(ctx context.Context, lotID, userID uuid.UUID) error
+	GetByLotID(ctx context.Context, lotID uuid.UUID) ([]*LotAuctioneer, error)
+	IsAssigned(ctx context.Context, lotID, userID uuid.UUID) (bool, error)
+}
+
+// LotRulesRepository persists the rules set on the lots
+type LotRulesRepository interface {
+	// Get returns ErrLotRulesNotFound for a lot without rules
+	Get(ctx context.Context, lotID uuid.UUID) (*LotRules, error)
+	// Save inserts or replaces the rules of the lot
+	Save(ctx context.Context, rules *LotRules) error
+	// Delete returns ErrLotRulesNotFound for a lot without rules
+	Delete(ctx context.Context, lotID uuid.UUID) error
+}
+
+// TermsAcceptanceRepository persists the terms accepted by the bidders
+type TermsAcceptanceRepository interface {
+	// Save records the acceptance, accepting the same version again keeps the first acceptance
+	Save(ctx context.Context, acceptance *TermsAcceptance) error
+	// Accepted reports if the user accepted the version of the terms of the lot
+	Accepted(ctx context.Context, userID, lotID uuid.UUID, termsVersion string) (bool, error)
+}
+
+// AbsenteeBidRepository persists the absentee bids of the lots not opened yet
+type AbsenteeBidRepository interface {
+	// Create stores a pending absentee bid, ErrAbsenteeBidExists when the user has one pending on the lot
+	Create(ctx context.Context, bid *AbsenteeBid) error
+	// GetByID returns ErrAbsenteeBidNotFound for an unknown ID
+	GetByID(ctx context.Context, id uuid.UUID) (*AbsenteeBid, error)
+	// ListByUserID returns a page of the absentee bids of the user, newest first
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*AbsenteeBid, error)
+	// ListByLotID returns the absentee bids of the lot in submission order
+	ListByLotID(ctx context.Context, lotID uuid.UUID) ([]*AbsenteeBid, error)
+	// ClaimPending marks the pending absentee bids of the lot as processing and returns them in submission
+	// order, a bid is only claimed once whatever the number of instances
+	ClaimPending(ctx context.Context, lotID uuid.UUID) ([]*AbsenteeBid, error)
+	// GetEntered returns the entered absentee bid of the user on the lot, ErrAbsenteeBidNotFound without one
+	GetEntered(ctx context.Context, lotID, userID uuid.UUID) (*AbsenteeBid, error)
+	// LotsWithPending returns the active lots with pending absentee bids
+	LotsWithPending(ctx context.Context) ([]uuid.UUID, error)
+	// Cancel marks the absentee bid as cancelled if it's still pending, it reports false otherwise
+	Cancel(ctx context.Context, id uuid.UUID) (bool, error)
+	// Update saves the status and outcome of the absentee bid
+	Update(ctx context.Context, bid *AbsenteeBid) error
+}
+
+// LotBundleRepository persists the lot bundles and their bids
+type LotBundleRepository interface {
+	// Create stores an open bundle with its lots
+	Create(ctx context.Context, bundle *LotBundle) error
+	// GetByID returns ErrBundleNotFound for an unknown ID or a bundle of another organization
+	GetByID(ctx context.Context, id uuid.UUID) (*LotBundle, error)
+	// OpenWithLots returns the open bundles holding any of lotIDs
+	OpenWithLots(ctx context.Context, lotIDs []uuid.UUID) ([]uuid.UUID, error)
+	// PlaceBid stores bid and makes it the leading one if it still beats the leading bid of the open
+	// bundle, ErrBidAmountTooLow when outbid or settled meanwhile
+	PlaceBid(ctx context.Context, bid *BundleBid) error
+	// ListSettleable returns the open bundles whose lots are all finished or cancelled
+	ListSettleable(ctx context.Context) ([]uuid.UUID, error)
+	// Settle saves the outcome of a bundle still open, it reports false when it was settled already
+	Settle(ctx context.Context, bundle *LotBundle) (bool, error)
+}
+
+// BidderProfileProvider provides the bidder data read by the lot rules
+type BidderProfileProvider interface {
+	GetBidderProfile(ctx context.Context, userID uuid.UUID) (*BidderProfile, error)
+}
+
+// LotChangeNotifier tells the other instances that a lot changed, so they refresh what they keep of it
+type LotChangeNotifier interface {
+	NotifyLotChanged(ctx context.Context, lotID uuid.UUID, seq int64) error
 }
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math"
 	"sync"
 	"time"
 
@@ -16,49 +17,153 @@ type AuctionLotState string
 
 const (
 	StatePending   AuctionLotState = "pending"
+	StatePreview   AuctionLotState = "preview" // scheduled to open at StartTime, can be watched but not bid on
 	StateActive    AuctionLotState = "active"
+	StatePaused    AuctionLotState = "paused" // bidding suspended by the auctioneer until resumed
 	StateFinished  AuctionLotState = "finished"
 	StateCancelled AuctionLotState = "cancelled"
 )
 
+// LotType is the auction format of a lot
+type LotType string
+
+const (
+	LotTypeForward LotType = "forward" // highest bid wins, bids must increase
+	LotTypeReverse LotType = "reverse" // procurement, lowest bid wins and bids must decrease
+)
+
 type AuctionLot struct {
 	ID            uuid.UUID
+	OrgID         uuid.UUID // organization (auction house) owning the lot
 	Title         string
 	Description   string
 	InitialPrice  float64
 	CurrentPrice  float64
+	Currency      string // ISO 4217 code of the lot prices and bids
+	Type          LotType
 	EndTime       time.Time
+	StartTime     *time.Time // scheduled opening, nil for lots opened by hand
 	State         AuctionLotState
 	LastBidTime   *time.Time    //for time extension logic
 	TimeExtension time.Duration // time extension period  for bid
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Closing       ClosingPolicy // how the bids near the end extend the lot
+	Seq           int64         // sequence of the last lot event, advanced by the LotEventStore
+	// ScheduledEndTime is the end time the lot was created with, the base of the extension cap
+	ScheduledEndTime time.Time
+	// PausedAt is the start of the current pause, nil unless the lot is paused
+	PausedAt *time.Time
+	// Quantity is the number of identical units of the lot, 1 for a single item. the Quantity best
+	// bidders of a multi-unit lot win one unit each, paying as Pricing says
+	Quantity int
+	Pricing  UnitPricing
+	// Standing are the bids holding the units of a multi-unit lot, best first, see SetStandingBids
+	Standing []*Bid
+	// Version is the optimistic concurrency token of the stored lot, checked and advanced on every save
+	// so a save based on a stale read fails with ErrConcurrentLotUpdate, 0 for a lot never stored
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
 	//to protect concurrent state of lot during bids flow
 	//very important for thread safety in concurrent environment (websockets)
 	mu sync.Mutex
+	// clock is the time of the bid and void rules, the system clock unless SetClock is used
+	clock Clock
+	// stateChanges are the transitions applied since the last TakeStateChanges
+	stateChanges []LotStateChange
+	// displaced is the standing bid pushed out by the last bid, see TakeDisplacedBid
+	displaced *Bid
 	//list of bids associeted whit this lot, for simplicity we take it all in this MVP
 	Bids []*Bid
 }
 
 func NewAuctionLot(id uuid.UUID, title, description string, initialPrice float64, endTime time.Time, timeExtension time.Duration) *AuctionLot {
 	return &AuctionLot{
-		ID:            id,
-		Title:         title,
-		InitialPrice:  initialPrice,
-		CurrentPrice:  initialPrice, //current price starts at initial price
-		EndTime:       endTime,
-		State:         StatePending, //starts pendind
-		TimeExtension: timeExtension,
-		Bids:          []*Bid{},
+		ID:               id,
+		Title:            title,
+		Description:      description,
+		InitialPrice:     initialPrice,
+		CurrentPrice:     initialPrice, //current price starts at initial price
+		Currency:         DefaultCurrency,
+		Type:             LotTypeForward,
+		EndTime:          endTime,
+		ScheduledEndTime: endTime,
+		State:            StatePending, //starts pendind
+		TimeExtension:    timeExtension,
+		Closing:          ClosingPolicy{Mode: ClosingSoft},
+		Quantity:         1,
+		Pricing:          PricingPayAsBid,
+		Bids:             []*Bid{},
 	}
 }
 
+// SetClock makes the lot read the current time from clock, the system clock is used otherwise
+func (al *AuctionLot) SetClock(clock Clock) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.clock = clock
+}
+
+// now returns the current time of the lot clock, al.mu must be held
+func (al *AuctionLot) now() time.Time {
+	if al.clock == nil {
+		return time.Now()
+	}
+	return al.clock.Now()
+}
+
+// ScheduleStart puts a pending lot in preview until startTime, when the scheduler opens it
+func (al *AuctionLot) ScheduleStart(startTime time.Time) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if _, err := al.transition(TransitionSchedule); err != nil {
+		return err
+	}
+	al.StartTime = &startTime
+	return nil
+}
+
 func (al *AuctionLot) PlaceBid(userID uuid.UUID, amount float64, minIncrement float64) (*Bid, error) {
+	return al.PlaceBidAt(userID, amount, minIncrement, time.Time{})
+}
+
+// PlaceBidAt places a bid received by the server at receivedAt: the end of the lot and the anti-sniping
+// extension are adjudicated on the receive time instead of the processing one, so a bid queued behind
+// others near the close is not rejected for the server latency. whatever the client claims, a bid received
+// at or after the end time is too late. a zero receivedAt, or one ahead of the lot clock, is the processing time
+func (al *AuctionLot) PlaceBidAt(userID uuid.UUID, amount float64, minIncrement float64, receivedAt time.Time) (*Bid, error) {
 	//blocks concurrent acces to lot state
 	al.mu.Lock()
 	//ensures the mutex is released when function ends
 	defer al.mu.Unlock()
 	//bussiles logic validations
+	if err := ValidateAmount(amount, al.Currency); err != nil {
+		log.Warn("Bid rejected: Invalid amount",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.String("currency", al.Currency),
+			zap.String("userID", userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if al.State == StatePreview {
+		log.Warn("Bid rejected: Lot not open yet",
+			zap.String("lotID", al.ID.String()),
+			zap.Timep("startTime", al.StartTime),
+			zap.Float64("bidAmount", amount),
+			zap.String("userID", userID.String()),
+		)
+		return nil, ErrLotNotOpenYet
+	}
+	if al.State == StatePaused {
+		log.Warn("Bid rejected: Lot paused",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.String("userID", userID.String()),
+		)
+		return nil, ErrLotPaused
+	}
 	if al.State != StateActive {
 		log.Warn("Bid rejected: Lot not active",
 			zap.String("lotID", al.ID.String()),
@@ -69,7 +174,28 @@ func (al *AuctionLot) PlaceBid(userID uuid.UUID, amount float64, minIncrement fl
 		return nil, ErrLotNotActive
 	}
 
-	if amount <= al.CurrentPrice {
+	// bids arriving after the end time but before the scheduler closes the lot are too late
+	now := al.now()
+	bidTime := now
+	if !receivedAt.IsZero() && receivedAt.Before(now) {
+		bidTime = receivedAt
+	}
+	if !bidTime.Before(al.EndTime) {
+		log.Warn("Bid rejected: Lot bidding closed",
+			zap.String("lotID", al.ID.String()),
+			zap.Time("endTime", al.EndTime),
+			zap.Time("receivedAt", bidTime),
+			zap.Float64("bidAmount", amount),
+			zap.String("userID", userID.String()),
+		)
+		return nil, ErrBiddingClosed
+	}
+
+	if al.Type == LotTypeReverse {
+		if err := al.validateReverseBid(userID, amount, minIncrement); err != nil {
+			return nil, err
+		}
+	} else if amount <= al.CurrentPrice {
 		log.Warn("Bid rejected: Amount too low",
 			zap.String("lotID", al.ID.String()),
 			zap.Float64("bidAmount", amount),
@@ -78,39 +204,56 @@ func (al *AuctionLot) PlaceBid(userID uuid.UUID, amount float64, minIncrement fl
 		)
 		return nil, ErrBidAmountTooLow
 	}
+	// on a multi-unit lot the price is the one of a unit, a bidder holding one can only raise its bid
+	if held := al.StandingBidOf(userID); held != nil && amount <= held.Amount {
+		log.Warn("Bid rejected: Amount below the standing bid of the bidder",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.Float64("standingAmount", held.Amount),
+			zap.String("userID", userID.String()),
+		)
+		return nil, ErrBidAmountTooLow
+	}
 
-	// Optional: validates minimum increment
-	// if amount < al.CurrentPrice + minIncrement {
-	// 	log.Warn("Bid rejected: Increment too small",
-	// 		zap.String("lotID", al.ID.String()),
-	// 		zap.Float64("bidAmount", amount),
-	// 		zap.Float64("currentPrice", al.CurrentPrice),
-	// 		zap.Float64("minIncrement", minIncrement),
-	// 		zap.String("userID", userID.String()),
-	// 	)
-	// 	return nil, ErrBidIncrementTooSmall
-	// }
+	// validates minimum increment, computed by the caller from the lot increment table
+	if al.Type != LotTypeReverse && minIncrement > 0 &&
+		MinorUnits(amount, al.Currency) < MinorUnits(al.CurrentPrice, al.Currency)+MinorUnits(minIncrement, al.Currency) {
+		log.Warn("Bid rejected: Increment too small",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.Float64("currentPrice", al.CurrentPrice),
+			zap.Float64("minIncrement", minIncrement),
+			zap.String("userID", userID.String()),
+		)
+		return nil, ErrBidIncrementTooSmall
+	}
 
-	//time extension logic, if the bid occurs near to the end
+	//time extension logic, if the bid occurs near to the end the closing policy decides if it extends the lot
 	originalEndTime := al.EndTime
-	now := time.Now()
-	if time.Now().Add(al.TimeExtension).After(al.EndTime) {
-		al.EndTime = time.Now().Add(al.TimeExtension)
+	if end := al.Closing.ExtendedEndTime(al.EndTime, al.ScheduledEndTime, bidTime, al.TimeExtension, amount-al.CurrentPrice); end.After(al.EndTime) {
+		al.EndTime = end
 		//a log entry musy be useful, consider it
 		log.Info("Auction time extended",
 			zap.String("lotID", al.ID.String()),
 			zap.Time("originalEndTime", originalEndTime),
 			zap.Time("newEndTime", al.EndTime),
 			zap.Duration("extension", al.TimeExtension),
+			zap.String("closingMode", string(al.Closing.Mode)),
 			zap.String("userID", userID.String()),
 		)
 	}
 
-	//updates lot state
-	al.CurrentPrice = amount
-	al.LastBidTime = &now
 	//cretes new bid
 	newBid := NewBid(uuid.New(), al.ID, userID, amount, now)
+	if !receivedAt.IsZero() {
+		newBid.ReceivedAt = &bidTime
+	}
+	//updates lot state, the bid takes a unit of a multi-unit lot
+	al.CurrentPrice = amount
+	if al.MultiUnit() {
+		al.standBid(newBid)
+	}
+	al.LastBidTime = &now
 	// adds the bid to the list, remember this is a simplyfied way to do it
 	al.Bids = append(al.Bids, newBid)
 
@@ -127,19 +270,137 @@ func (al *AuctionLot) PlaceBid(userID uuid.UUID, amount float64, minIncrement fl
 
 }
 
-// Start initiate he auction if is pending
-func (al *AuctionLot) Start() error {
+// validateReverseBid checks that a bid on a reverse lot undercuts the current price by at least
+// minIncrement, al.mu must be held
+func (al *AuctionLot) validateReverseBid(userID uuid.UUID, amount float64, minIncrement float64) error {
+	if amount >= al.CurrentPrice {
+		log.Warn("Bid rejected: Amount too high",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.Float64("currentPrice", al.CurrentPrice),
+			zap.String("userID", userID.String()),
+		)
+		return ErrBidAmountTooHigh
+	}
+	if minIncrement > 0 &&
+		MinorUnits(amount, al.Currency) > MinorUnits(al.CurrentPrice, al.Currency)-MinorUnits(minIncrement, al.Currency) {
+		log.Warn("Bid rejected: Decrement too small",
+			zap.String("lotID", al.ID.String()),
+			zap.Float64("bidAmount", amount),
+			zap.Float64("currentPrice", al.CurrentPrice),
+			zap.Float64("minDecrement", minIncrement),
+			zap.String("userID", userID.String()),
+		)
+		return ErrBidIncrementTooSmall
+	}
+	return nil
+}
+
+// leads reports if a bid of amount is better than one of other: higher on forward lots, lower on reverse ones
+func (al *AuctionLot) leads(amount, other float64) bool {
+	if al.Type == LotTypeReverse {
+		return amount < other
+	}
+	return amount > other
+}
+
+// NextMinBid returns the lowest amount the lot accepts as next bid with minIncrement, the highest one on
+// reverse lots: the current price moved by minIncrement, by one minor unit without increment. 0 when no
+// bid can undercut the price of a reverse lot
+func (al *AuctionLot) NextMinBid(minIncrement float64) float64 {
+	step := max(MinorUnits(minIncrement, al.Currency), 1)
+	next := MinorUnits(al.CurrentPrice, al.Currency) + step
+	if al.Type == LotTypeReverse {
+		next = max(MinorUnits(al.CurrentPrice, al.Currency)-step, 0)
+	}
+	return float64(next) / math.Pow10(CurrencyDecimals(al.Currency))
+}
+
+// LeadingBidAmount returns the amount of the leading bid of the lot, 0 for a lot without bids
+func (al *AuctionLot) LeadingBidAmount() float64 {
+	if al.LastBidTime == nil {
+		return 0
+	}
+	return al.CurrentPrice
+}
+
+// ReserveMet reports if the bids on the lot reached reserve, at or above it on forward lots and at or
+// below it on reverse ones. a lot without bids never met its reserve
+func (al *AuctionLot) ReserveMet(reserve float64) bool {
+	if al.LastBidTime == nil {
+		return false
+	}
+	price, target := MinorUnits(al.CurrentPrice, al.Currency), MinorUnits(reserve, al.Currency)
+	if al.Type == LotTypeReverse {
+		return price <= target
+	}
+	return price >= target
+}
+
+// VoidBid retracts bid of an active lot and recomputes the lot price from the best remaining valid bid,
+// falling back to the initial price when no bid is left
+func (al *AuctionLot) VoidBid(bid *Bid, remaining []*Bid, voidedBy uuid.UUID, reason string) error {
 	al.mu.Lock()
 	defer al.mu.Unlock()
 
-	if al.State != StatePending {
-		log.Warn("Attempted to start lot that is not pending",
+	if al.State != StateActive {
+		log.Warn("Attempted to void a bid of a lot that is not active",
 			zap.String("lotID", al.ID.String()),
+			zap.String("bidID", bid.ID.String()),
 			zap.String("state", string(al.State)),
 		)
-		return ErrLotAlreadyStartedOrFinished
+		return ErrLotNotActive
+	}
+	if bid.LotID != al.ID {
+		return ErrBidNotFound
+	}
+	if bid.VoidedAt != nil {
+		return ErrBidAlreadyVoided
+	}
+
+	now := al.now()
+	bid.VoidedAt = &now
+	bid.VoidedBy = &voidedBy
+	bid.VoidReason = reason
+
+	al.CurrentPrice = al.InitialPrice
+	al.LastBidTime = nil
+	valid := make([]*Bid, 0, len(remaining))
+	for _, b := range remaining {
+		if b.ID == bid.ID || b.VoidedAt != nil {
+			continue
+		}
+		valid = append(valid, b)
+		if al.leads(b.Amount, al.CurrentPrice) {
+			al.CurrentPrice = b.Amount
+		}
+		if al.LastBidTime == nil || b.Timestamp.After(*al.LastBidTime) {
+			ts := b.Timestamp
+			al.LastBidTime = &ts
+		}
+	}
+	if al.MultiUnit() {
+		al.setStanding(valid)
+	}
+
+	log.Info("Bid voided",
+		zap.String("lotID", al.ID.String()),
+		zap.String("bidID", bid.ID.String()),
+		zap.String("voidedBy", voidedBy.String()),
+		zap.Float64("voidedAmount", bid.Amount),
+		zap.Float64("newCurrentPrice", al.CurrentPrice),
+	)
+	return nil
+}
+
+// Start initiate he auction if is pending, a lot in preview can be started before its start time
+func (al *AuctionLot) Start() error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if _, err := al.transition(TransitionStart); err != nil {
+		return err
 	}
-	al.State = StateActive
 	log.Info("Auction lot started",
 		zap.String("lotID", al.ID.String()),
 		zap.Time("endTime", al.EndTime),
@@ -148,19 +409,54 @@ func (al *AuctionLot) Start() error {
 	return nil
 }
 
+// Pause suspends the bidding of an active lot, the bids are rejected until Resume
+func (al *AuctionLot) Pause() error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	change, err := al.transition(TransitionPause)
+	if err != nil {
+		return err
+	}
+	al.PausedAt = &change.At
+	log.Info("Auction lot paused", zap.String("lotID", al.ID.String()))
+	return nil
+}
+
+// Resume reopens the bidding of a paused lot, the end times are moved by the paused duration so the
+// bidders get back the time the lot was paused
+func (al *AuctionLot) Resume() error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	change, err := al.transition(TransitionResume)
+	if err != nil {
+		return err
+	}
+	var paused time.Duration
+	if al.PausedAt != nil {
+		paused = max(change.At.Sub(*al.PausedAt), 0)
+	}
+	al.EndTime = al.EndTime.Add(paused)
+	// the extension cap is relative to the scheduled end, it moves too so the pause doesn't eat into it
+	al.ScheduledEndTime = al.ScheduledEndTime.Add(paused)
+	al.PausedAt = nil
+	log.Info("Auction lot resumed",
+		zap.String("lotID", al.ID.String()),
+		zap.Duration("paused", paused),
+		zap.Time("endTime", al.EndTime),
+	)
+	return nil
+}
+
 // Finish ends an active lot
 func (al *AuctionLot) Finish() error {
 	al.mu.Lock()
 	defer al.mu.Unlock()
 
-	if al.State != StateActive {
-		log.Warn("Attempted to finish lot that is not active",
-			zap.String("lotID", al.ID.String()),
-			zap.String("state", string(al.State)),
-		)
-		return ErrLotNotActive
+	if _, err := al.transition(TransitionFinish); err != nil {
+		return err
 	}
-	al.State = StateFinished
 	log.Info("Auction lot finished",
 		zap.String("lotID", al.ID.String()),
 		zap.Float64("finalPrice", al.CurrentPrice),
@@ -168,23 +464,45 @@ func (al *AuctionLot) Finish() error {
 	return nil
 }
 
-// Cancel auction
-func (al *AuctionLot) Cancel() error {
+// UpdateListing changes the listing of a lot not opened yet (pending or in preview), it returns
+// ErrLotAlreadyStartedOrFinished otherwise. the lot has no bids yet, so its price restarts at initialPrice
+func (al *AuctionLot) UpdateListing(title, description string, initialPrice float64, endTime time.Time) error {
 	al.mu.Lock()
 	defer al.mu.Unlock()
 
-	if al.State == StateFinished || al.State == StateCancelled {
-		log.Warn("Attempted to cancel lot that is already finished or cancelled",
+	if al.State != StatePending && al.State != StatePreview {
+		log.Warn("Attempted to edit lot that is already open",
 			zap.String("lotID", al.ID.String()),
 			zap.String("state", string(al.State)),
 		)
-		return ErrLotAlreadyFinishedOrCancelled
+		return ErrLotAlreadyStartedOrFinished
 	}
+	al.Title = title
+	al.Description = description
+	al.InitialPrice = initialPrice
+	al.CurrentPrice = initialPrice
+	al.EndTime = endTime
+	al.ScheduledEndTime = endTime
+	log.Info("Auction lot listing updated",
+		zap.String("lotID", al.ID.String()),
+		zap.Float64("initialPrice", al.InitialPrice),
+		zap.Time("endTime", al.EndTime),
+	)
+	return nil
+}
 
-	al.State = StateCancelled
+// Cancel cancels a lot not finished yet, from any other state
+func (al *AuctionLot) Cancel() error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	change, err := al.transition(TransitionCancel)
+	if err != nil {
+		return err
+	}
 	log.Info("Auction lot cancelled",
 		zap.String("lotID", al.ID.String()),
-		zap.String("state", string(al.State)),
+		zap.String("previousState", string(change.From)),
 	)
 	return nil
 }
@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newActiveLot returns an active lot of currency at price, ending an hour after the clock time
+func newActiveLot(clock *ManualClock, lotType LotType, currency string, price float64) *AuctionLot {
+	lot := NewAuctionLot(uuid.New(), "lot", "", price, clock.Now().Add(time.Hour), 0)
+	lot.Type = lotType
+	lot.Currency = currency
+	lot.State = StateActive
+	lot.SetClock(clock)
+	return lot
+}
+
+// TestPlaceBidIncrementBoundary checks a bid of exactly the current price plus the increment is
+// accepted, whatever the float64 error of the sum, and one minor unit less is rejected
+func TestPlaceBidIncrementBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		lotType   LotType
+		currency  string
+		price     float64
+		increment float64
+		amount    float64
+		wantErr   error
+	}{
+		{"forward at the minimum", LotTypeForward, "USD", 20.30, 0.10, 20.40, nil},
+		{"forward a cent short", LotTypeForward, "USD", 20.30, 0.10, 20.39, ErrBidIncrementTooSmall},
+		{"forward sum with float error", LotTypeForward, "USD", 0.70, 0.10, 0.80, nil},
+		{"forward large price", LotTypeForward, "USD", 999.99, 5, 1004.99, nil},
+		{"forward zero decimals", LotTypeForward, "JPY", 1000, 50, 1050, nil},
+		{"forward zero decimals short", LotTypeForward, "JPY", 1000, 50, 1049, ErrBidIncrementTooSmall},
+		{"forward three decimals", LotTypeForward, "KWD", 1.105, 0.005, 1.11, nil},
+		{"forward three decimals short", LotTypeForward, "KWD", 1.105, 0.005, 1.109, ErrBidIncrementTooSmall},
+		{"reverse at the minimum", LotTypeReverse, "USD", 20.40, 0.10, 20.30, nil},
+		{"reverse a cent short", LotTypeReverse, "USD", 20.40, 0.10, 20.31, ErrBidIncrementTooSmall},
+		{"reverse with float error", LotTypeReverse, "USD", 0.80, 0.10, 0.70, nil},
+		{"reverse zero decimals", LotTypeReverse, "JPY", 1000, 50, 950, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+			lot := newActiveLot(clock, tt.lotType, tt.currency, tt.price)
+			_, err := lot.PlaceBid(uuid.New(), tt.amount, tt.increment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PlaceBid(%v) over %v + %v: err = %v, want %v", tt.amount, tt.price, tt.increment, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestPlaceBidIncrementCents sweeps the cent prices, a bid of price + increment is always accepted
+func TestPlaceBidIncrementCents(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	for _, increment := range []float64{0.01, 0.10, 1, 5, 10, 50} {
+		for cents := 0; cents < 100000; cents += 7 {
+			price := float64(cents) / 100
+			amount := float64(cents+int(increment*100)) / 100
+			lot := newActiveLot(clock, LotTypeForward, "USD", price)
+			if _, err := lot.PlaceBid(uuid.New(), amount, increment); err != nil {
+				t.Fatalf("PlaceBid(%v) over %v + %v: %v", amount, price, increment, err)
+			}
+		}
+	}
+}
+
+// TestPlaceBidSoftCloseExtension checks the bids within the time extension of the end move the end time
+// to the bid time plus the extension, up to the extension cap, and the bids after the end are rejected
+func TestPlaceBidSoftCloseExtension(t *testing.T) {
+	tests := []struct {
+		name    string
+		closing ClosingPolicy
+		advance time.Duration // bid time after the clock start, the lot ends an hour after it
+		wantEnd time.Duration // end time after the clock start
+		wantErr error
+	}{
+		{"far from the end", ClosingPolicy{Mode: ClosingSoft}, 30 * time.Minute, time.Hour, nil},
+		{"within the extension", ClosingPolicy{Mode: ClosingSoft}, 59 * time.Minute, 64 * time.Minute, nil},
+		{"last second", ClosingPolicy{Mode: ClosingSoft}, time.Hour - time.Second, time.Hour + 5*time.Minute - time.Second, nil},
+		{"capped", ClosingPolicy{Mode: ClosingSoft, MaxExtension: 2 * time.Minute}, 59 * time.Minute, 62 * time.Minute, nil},
+		{"hard close", ClosingPolicy{Mode: ClosingHard}, 59 * time.Minute, time.Hour, nil},
+		{"at the end", ClosingPolicy{Mode: ClosingSoft}, time.Hour, time.Hour, ErrBiddingClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			clock := NewManualClock(start)
+			lot := newActiveLot(clock, LotTypeForward, "USD", 100)
+			lot.TimeExtension = 5 * time.Minute
+			lot.Closing = tt.closing
+			clock.Advance(tt.advance)
+			bid, err := lot.PlaceBid(uuid.New(), 110, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PlaceBid() err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !bid.Timestamp.Equal(clock.Now()) {
+				t.Errorf("bid timestamp = %v, want the clock time %v", bid.Timestamp, clock.Now())
+			}
+			if want := start.Add(tt.wantEnd); !lot.EndTime.Equal(want) {
+				t.Errorf("EndTime = %v, want %v", lot.EndTime, want)
+			}
+		})
+	}
+}
+
+// TestPlaceBidAtReceiveTime checks the end of the lot is adjudicated on the time the server received the
+// bid, not the one it was processed at, and a receive time ahead of the lot clock is ignored
+func TestPlaceBidAtReceiveTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		received time.Duration // receive time after the clock start, the lot ends an hour after it
+		wantErr  error
+	}{
+		{"received before the end", time.Hour - time.Millisecond, nil},
+		{"received at the end", time.Hour, ErrBiddingClosed},
+		{"received after the end", time.Hour + time.Second, ErrBiddingClosed},
+		{"ahead of the clock", 2 * time.Hour, ErrBiddingClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			clock := NewManualClock(start)
+			lot := newActiveLot(clock, LotTypeForward, "USD", 100)
+			// processed after the end, behind other msgs
+			clock.Advance(time.Hour + 50*time.Millisecond)
+			bid, err := lot.PlaceBidAt(uuid.New(), 110, 0, start.Add(tt.received))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PlaceBidAt() err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if bid.ReceivedAt == nil || !bid.ReceivedAt.Equal(start.Add(tt.received)) {
+				t.Errorf("bid ReceivedAt = %v, want %v", bid.ReceivedAt, start.Add(tt.received))
+			}
+			if !bid.Timestamp.Equal(clock.Now()) {
+				t.Errorf("bid timestamp = %v, want the processing time %v", bid.Timestamp, clock.Now())
+			}
+		})
+	}
+}
+
+// TestPauseResumeShiftsEndTime checks resuming a lot gives back the paused time to its end time and to
+// the extension cap, and the bids are rejected while paused
+func TestPauseResumeShiftsEndTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		paused time.Duration
+	}{
+		{"seconds", 45 * time.Second},
+		{"longer than the remaining time", 2 * time.Hour},
+		{"resumed right away", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+			lot := newActiveLot(clock, LotTypeForward, "USD", 100)
+			end, scheduledEnd := lot.EndTime, lot.ScheduledEndTime
+
+			clock.Advance(10 * time.Minute)
+			if err := lot.Pause(); err != nil {
+				t.Fatalf("Pause() = %v", err)
+			}
+			if lot.PausedAt == nil || !lot.PausedAt.Equal(clock.Now()) {
+				t.Fatalf("PausedAt = %v, want %v", lot.PausedAt, clock.Now())
+			}
+			clock.Advance(tt.paused)
+			if _, err := lot.PlaceBid(uuid.New(), 110, 0); !errors.Is(err, ErrLotPaused) {
+				t.Fatalf("PlaceBid() while paused = %v, want %v", err, ErrLotPaused)
+			}
+			if err := lot.Resume(); err != nil {
+				t.Fatalf("Resume() = %v", err)
+			}
+			if !lot.EndTime.Equal(end.Add(tt.paused)) {
+				t.Errorf("EndTime = %v, want %v", lot.EndTime, end.Add(tt.paused))
+			}
+			if !lot.ScheduledEndTime.Equal(scheduledEnd.Add(tt.paused)) {
+				t.Errorf("ScheduledEndTime = %v, want %v", lot.ScheduledEndTime, scheduledEnd.Add(tt.paused))
+			}
+			if lot.PausedAt != nil {
+				t.Errorf("PausedAt = %v, want nil", lot.PausedAt)
+			}
+			if _, err := lot.PlaceBid(uuid.New(), 110, 0); err != nil {
+				t.Errorf("PlaceBid() after resume = %v", err)
+			}
+		})
+	}
+}
+
+// TestNextMinBid checks the next min bid moves the price by the increment in the lot direction, by one
+// minor unit without increment
+func TestNextMinBid(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name      string
+		lotType   LotType
+		currency  string
+		price     float64
+		increment float64
+		want      float64
+	}{
+		{"forward", LotTypeForward, "USD", 20.30, 0.10, 20.40},
+		{"forward without increment", LotTypeForward, "USD", 20.30, 0, 20.31},
+		{"forward zero decimals", LotTypeForward, "JPY", 1000, 0, 1001},
+		{"reverse", LotTypeReverse, "USD", 100, 5, 95},
+		{"reverse at the bottom", LotTypeReverse, "USD", 0.01, 0.10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lot := newActiveLot(clock, tt.lotType, tt.currency, tt.price)
+			if got := lot.NextMinBid(tt.increment); got != tt.want {
+				t.Fatalf("NextMinBid(%v) = %v, want %v", tt.increment, got, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			if _, err := lot.PlaceBid(uuid.New(), tt.want, tt.increment); err != nil {
+				t.Fatalf("bid of the next min bid %v rejected: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestReserveMet checks the reserve is met once a bid reaches it in the lot direction
+func TestReserveMet(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	lot := newActiveLot(clock, LotTypeForward, "USD", 100)
+	if lot.ReserveMet(100) {
+		t.Fatal("reserve met without bids")
+	}
+	if _, err := lot.PlaceBid(uuid.New(), 149.99, 0); err != nil {
+		t.Fatal(err)
+	}
+	if lot.ReserveMet(150) {
+		t.Fatal("reserve met below it")
+	}
+	if _, err := lot.PlaceBid(uuid.New(), 150, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !lot.ReserveMet(150) {
+		t.Fatal("reserve not met at it")
+	}
+
+	reverse := newActiveLot(clock, LotTypeReverse, "USD", 100)
+	if _, err := reverse.PlaceBid(uuid.New(), 80, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !reverse.ReserveMet(90) || reverse.ReserveMet(70) {
+		t.Fatal("reverse reserve must be met at or below it")
+	}
+}
+
+// TestPlaceBidMultiUnit checks the bids on a lot of 2 units: the price is the one of a unit once both are
+// taken, a higher bid displaces the lowest standing one and a bidder holding a unit can only raise it
+func TestPlaceBidMultiUnit(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	lot := newActiveLot(clock, LotTypeForward, "USD", 100)
+	lot.Quantity, lot.Pricing = 2, PricingUniform
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	bid := func(userID uuid.UUID, amount float64) (*Bid, error) {
+		clock.Advance(time.Second)
+		return lot.PlaceBid(userID, amount, 0)
+	}
+	firstBid, err := bid(first, 110)
+	if err != nil || lot.CurrentPrice != 100 || lot.UnitsAvailable() != 1 {
+		t.Fatalf("first bid: err = %v, price %v with %d units available, want 100 with 1", err, lot.CurrentPrice, lot.UnitsAvailable())
+	}
+	secondBid, err := bid(second, 120)
+	if err != nil || lot.CurrentPrice != 110 || lot.TakeDisplacedBid() != nil {
+		t.Fatalf("second bid: err = %v, price %v, want the price of the last unit 110 without displacing", err, lot.CurrentPrice)
+	}
+	if _, err := bid(third, 110); !errors.Is(err, ErrBidAmountTooLow) {
+		t.Errorf("bid at the unit price: err = %v, want ErrBidAmountTooLow", err)
+	}
+	thirdBid, err := bid(third, 115)
+	if err != nil || lot.CurrentPrice != 115 {
+		t.Fatalf("third bid: err = %v, price %v, want 115", err, lot.CurrentPrice)
+	}
+	if displaced := lot.TakeDisplacedBid(); displaced != firstBid {
+		t.Errorf("displaced bid = %+v, want the first bid", displaced)
+	}
+	if _, err := bid(second, 118); !errors.Is(err, ErrBidAmountTooLow) {
+		t.Errorf("bid below the standing bid of the bidder: err = %v, want ErrBidAmountTooLow", err)
+	}
+	if price := lot.UnitPrice(lot.Standing[0]); price != 115 {
+		t.Errorf("uniform unit price = %v, want the lowest standing bid 115", price)
+	}
+
+	// voiding a standing bid gives its unit back to the best other bidder
+	if err := lot.VoidBid(thirdBid, []*Bid{firstBid, secondBid, thirdBid}, uuid.New(), "test"); err != nil {
+		t.Fatalf("VoidBid() error = %v", err)
+	}
+	if lot.StandingBidOf(first) == nil || lot.StandingBidOf(third) != nil || lot.CurrentPrice != 110 {
+		t.Errorf("standing bids after the void = %+v, want the first bid back", lot.Standing)
+	}
+}
@@ -15,6 +15,18 @@ type Bid struct {
 	Amount    float64
 	Timestamp time.Time
 	CreatedAt time.Time
+	// ClientIP is the remote IP the bid was placed from, empty for service-to-service bids
+	ClientIP string
+	// ReceivedAt is when the server received the bid, the lot end is adjudicated on it. nil when the bid
+	// was placed without it, Timestamp is the time it was processed at
+	ReceivedAt *time.Time
+	// Paddle is the bidder alias on the lot, shown to other users instead of UserID.
+	// the mapping is stored by the PaddleRepository, it's only set on bids returned by PlaceBid
+	Paddle int
+	// a voided bid was retracted by an admin, it's kept for auditing only
+	VoidedAt   *time.Time
+	VoidedBy   *uuid.UUID
+	VoidReason string
 }
 
 // NewBid creates a new Bid instance
@@ -29,3 +41,40 @@ func NewBid(id, lotID, userID uuid.UUID, amount float64, timestamp time.Time) *B
 	}
 
 }
+
+// UserLotBids summarizes the bids of a user on a lot
+type UserLotBids struct {
+	Lot        *AuctionLot
+	HighestBid float64   // highest amount the user bid on the lot
+	BidCount   int       // number of bids the user placed on the lot
+	LastBidAt  time.Time // time of the most recent bid of the user on the lot
+	Leading    bool      // the latest bid of the lot belongs to the user
+}
+
+// LotBidStats aggregates the valid bids of a lot
+type LotBidStats struct {
+	BidCount      int
+	UniqueBidders int
+	// AvgIncrement is the mean price change between consecutive bids, as a positive amount on reverse
+	// lots too, 0 with fewer than two bids
+	AvgIncrement float64
+	RecentBids   int // bids placed since the start of the requested window
+	FirstBidAt   *time.Time
+	LastBidAt    *time.Time
+	// PricePoints is the price history of the lot, oldest first and downsampled on lots with many bids
+	PricePoints []PricePoint
+}
+
+// PricePoint is the price of a lot after a bid
+type PricePoint struct {
+	At    time.Time
+	Price float64
+}
+
+// LeaderboardEntry is a bidder of a lot ranked by their best bid
+type LeaderboardEntry struct {
+	Paddle  int
+	UserID  uuid.UUID
+	BestBid float64   // highest bid of the user, lowest on reverse lots
+	BidAt   time.Time // time the best bid was placed, equal best bids rank by it
+}
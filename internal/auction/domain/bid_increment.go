@@ -0,0 +1,34 @@
+package domain
+
+import "sort"
+
+// IncrementTier defines the minimum increment applied while the current price is at or above MinPrice
+type IncrementTier struct {
+	MinPrice  float64
+	Increment float64
+}
+
+// IncrementTable is an auction-house style increment table (e.g. +5 under 100, +10 under 500, +50 above).
+// it's a value object, tiers are kept sorted by MinPrice
+type IncrementTable []IncrementTier
+
+// NewIncrementTable builds an IncrementTable sorting the tiers by MinPrice
+func NewIncrementTable(tiers ...IncrementTier) IncrementTable {
+	table := make(IncrementTable, len(tiers))
+	copy(table, tiers)
+	sort.Slice(table, func(i, j int) bool { return table[i].MinPrice < table[j].MinPrice })
+	return table
+}
+
+// IncrementFor returns the minimum increment for the given current price,
+// an empty table (or a price below the first tier) means no minimum increment
+func (t IncrementTable) IncrementFor(price float64) float64 {
+	increment := 0.0
+	for _, tier := range t {
+		if price < tier.MinPrice {
+			break
+		}
+		increment = tier.Increment
+	}
+	return increment
+}
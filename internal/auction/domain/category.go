@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category groups lots for browsing, categories form a tree through ParentID
+type Category struct {
+	ID   uuid.UUID
+	Name string
+	// Slug is the unique URL friendly name, e.g. "fine-art"
+	Slug string
+	// ParentID is nil for top level categories
+	ParentID  *uuid.UUID
+	CreatedAt time.Time
+}
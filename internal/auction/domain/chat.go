@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatMessage is a msg of the live chat of a lot, authors are shown by their paddle like in the bids
+type ChatMessage struct {
+	ID     uuid.UUID
+	LotID  uuid.UUID
+	UserID uuid.UUID
+	Paddle int // 0 when the author never bid on the lot
+	Text   string
+	// CreatedAt is set by the use case, not by the storage
+	CreatedAt time.Time
+}
+
+// ChatMute silences a user in the chat of a lot, an admin moderation action
+type ChatMute struct {
+	LotID   uuid.UUID
+	UserID  uuid.UUID
+	MutedBy uuid.UUID
+	Reason  string
+	Until   *time.Time // nil mutes the user until unmuted
+	// CreatedAt is the time of the mute
+	CreatedAt time.Time
+}
+
+// Active reports if the mute still silences the user at now
+func (m *ChatMute) Active(now time.Time) bool {
+	return m.Until == nil || now.Before(*m.Until)
+}
+
+// ChatFilter is a hook checking the chat msgs before they are stored and published, e.g. a profanity
+// filter. it returns the text to publish, possibly masked, or an error wrapping ErrChatMessageRejected
+type ChatFilter interface {
+	Filter(ctx context.Context, lotID, userID uuid.UUID, text string) (string, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time of the lots, use cases and scheduler. the time driven rules
+// (anti-sniping extension, lot closing) read it instead of time.Now, so they can run on a controlled time
+// in unit tests and replays
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock, used in production
+type SystemClock struct{}
+
+// Now implements Clock
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// ManualClock is a Clock that only moves when told to, safe for concurrent use
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock creates a ManualClock stopped at now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now implements Clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now, backwards too
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
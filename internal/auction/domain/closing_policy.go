@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ClosingMode is how the bids placed near the end of a lot move its end time
+type ClosingMode string
+
+const (
+	ClosingSoft           ClosingMode = "soft"            // every bid within the time extension extends the lot
+	ClosingHard           ClosingMode = "hard"            // the lot closes at its end time, bids never extend it
+	ClosingPriceThreshold ClosingMode = "price_threshold" // only bids moving the price by PriceThreshold or more extend the lot
+)
+
+// ClosingPolicy is a value object deciding if a bid extends the end time of a lot, the zero value is a
+// soft close without cap
+type ClosingPolicy struct {
+	Mode ClosingMode
+	// MaxExtension caps the total time the bids can add to the scheduled end time, 0 means no cap
+	MaxExtension time.Duration
+	// PriceThreshold is the minimum price change of a bid extending the lot in ClosingPriceThreshold mode
+	PriceThreshold float64
+}
+
+// Valid reports if the policy mode is known and its limits are not negative
+func (p ClosingPolicy) Valid() bool {
+	switch p.Mode {
+	case "", ClosingSoft, ClosingHard, ClosingPriceThreshold:
+	default:
+		return false
+	}
+	return p.MaxExtension >= 0 && p.PriceThreshold >= 0
+}
+
+// ExtendedEndTime returns the end time of a lot after a bid placed at now that moved the price by
+// priceChange, endTime when the policy doesn't extend it. the extension is capped at scheduledEnd plus
+// MaxExtension
+func (p ClosingPolicy) ExtendedEndTime(endTime, scheduledEnd, now time.Time, extension time.Duration, priceChange float64) time.Time {
+	switch p.Mode {
+	case ClosingHard:
+		return endTime
+	case ClosingPriceThreshold:
+		if math.Abs(priceChange) < p.PriceThreshold {
+			return endTime
+		}
+	}
+	extended := now.Add(extension)
+	if !extended.After(endTime) {
+		return endTime
+	}
+	if p.MaxExtension > 0 {
+		if limit := scheduledEnd.Add(p.MaxExtension); extended.After(limit) {
+			extended = limit
+		}
+	}
+	if extended.Before(endTime) {
+		return endTime
+	}
+	return extended
+}
+
+// FixedEndTime reports if the end time of the lot can't move anymore, so closing it exactly at its
+// end time is safe
+func (p ClosingPolicy) FixedEndTime(endTime, scheduledEnd time.Time) bool {
+	return p.Mode == ClosingHard || (p.MaxExtension > 0 && !endTime.Before(scheduledEnd.Add(p.MaxExtension)))
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClosingPolicyExtendedEndTime(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	extension := 2 * time.Minute
+	tests := []struct {
+		name        string
+		policy      ClosingPolicy
+		advance     time.Duration // time of the bid after the clock start
+		endTime     time.Time
+		priceChange float64
+		want        time.Time
+	}{
+		{"soft extends from the bid", ClosingPolicy{Mode: ClosingSoft}, 30 * time.Second, end, 10, end.Add(90 * time.Second)},
+		{"soft outside the extension", ClosingPolicy{Mode: ClosingSoft}, -5 * time.Minute, end, 10, end},
+		{"zero value is soft", ClosingPolicy{}, 30 * time.Second, end, 10, end.Add(90 * time.Second)},
+		{"hard never extends", ClosingPolicy{Mode: ClosingHard}, 30 * time.Second, end, 10, end},
+		{"threshold reached", ClosingPolicy{Mode: ClosingPriceThreshold, PriceThreshold: 10}, 30 * time.Second, end, 10, end.Add(90 * time.Second)},
+		{"threshold reached downwards", ClosingPolicy{Mode: ClosingPriceThreshold, PriceThreshold: 10}, 30 * time.Second, end, -10, end.Add(90 * time.Second)},
+		{"threshold not reached", ClosingPolicy{Mode: ClosingPriceThreshold, PriceThreshold: 10}, 30 * time.Second, end, 9.99, end},
+		{"capped at the max extension", ClosingPolicy{Mode: ClosingSoft, MaxExtension: time.Minute}, 30 * time.Second, end, 10, end.Add(time.Minute)},
+		{"cap already reached", ClosingPolicy{Mode: ClosingSoft, MaxExtension: time.Minute}, 90 * time.Second, end.Add(time.Minute), 10, end.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(start)
+			clock.Advance(tt.advance)
+			got := tt.policy.ExtendedEndTime(tt.endTime, end, clock.Now(), extension, tt.priceChange)
+			if !got.Equal(tt.want) {
+				t.Errorf("ExtendedEndTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of lots created without one
+const DefaultCurrency = "USD"
+
+// NormalizeCurrency upper cases an ISO 4217 code, ok is false when code is not three letters
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return code, true
+}
+
+// MaxBidAmount is the largest amount a bid can have, in any currency. far above any real lot, it keeps the
+// amounts where float64 still represents every minor unit exactly
+const MaxBidAmount = 1e12
+
+// currencyDecimals are the minor unit digits of the ISO 4217 currencies not using two decimals
+var currencyDecimals = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyDecimals returns the number of decimals of the amounts in currency, 2 for the codes not listed
+func CurrencyDecimals(currency string) int {
+	code, _ := NormalizeCurrency(currency)
+	if decimals, ok := currencyDecimals[code]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// MinorUnits returns amount in the minor units of currency (cents for USD, yen for JPY), rounded to the
+// nearest one. the money comparisons go through it, 20.30+0.10 is 20.400000000000002 in float64
+func MinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(CurrencyDecimals(currency))))
+}
+
+// RoundAmount rounds amount to the minor units of currency, 2 decimals for USD, none for JPY
+func RoundAmount(amount float64, currency string) float64 {
+	return float64(MinorUnits(amount, currency)) / math.Pow10(CurrencyDecimals(currency))
+}
+
+// amount validation error codes, stable identifiers sent to the clients with the rejection
+const (
+	AmountErrorInvalid   = "invalid_amount"
+	AmountErrorNotFinite = "amount_not_finite"
+	AmountErrorPrecision = "amount_precision"
+	AmountErrorTooLarge  = "amount_too_large"
+)
+
+// ValidateAmount checks a bid amount is a finite positive number not above MaxBidAmount and, when
+// currency is set, without more decimals than the currency has (100.001 USD is rejected)
+func ValidateAmount(amount float64, currency string) error {
+	switch {
+	case math.IsNaN(amount) || math.IsInf(amount, 0):
+		return ErrAmountNotFinite
+	case amount <= 0:
+		return ErrInvalidAmount
+	case amount > MaxBidAmount:
+		return fmt.Errorf("%w: %v is above %v", ErrAmountTooLarge, amount, float64(MaxBidAmount))
+	}
+	if currency == "" {
+		return nil
+	}
+	// the shortest representation of the amount is what the bidder typed, 100.01 stays 100.01
+	text := strconv.FormatFloat(amount, 'f', -1, 64)
+	if dot := strings.IndexByte(text, '.'); dot >= 0 && len(text)-dot-1 > CurrencyDecimals(currency) {
+		return fmt.Errorf("%w: %s %s has more than %d decimals", ErrAmountPrecision, text, currency, CurrencyDecimals(currency))
+	}
+	return nil
+}
+
+// AmountErrorCode returns the error code of an amount rejected by ValidateAmount, "" for other errors
+func AmountErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrAmountNotFinite):
+		return AmountErrorNotFinite
+	case errors.Is(err, ErrAmountPrecision):
+		return AmountErrorPrecision
+	case errors.Is(err, ErrAmountTooLarge):
+		return AmountErrorTooLarge
+	case errors.Is(err, ErrInvalidAmount):
+		return AmountErrorInvalid
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter is an inbound client msg dropped before it was processed, kept so a possibly winning bid
+// is not lost silently and can be audited
+type DeadLetter struct {
+	ID uuid.UUID
+	// LotID is the lot of the connection, as received
+	LotID    string
+	UserID   *uuid.UUID // nil for anonymous connections
+	ClientID string
+	// MessageType is the type of the msg, empty when it couldn't be decoded
+	MessageType string
+	Payload     []byte
+	// Reason tells where the msg was dropped, e.g. inbound_full or worker_busy
+	Reason    string
+	DroppedAt time.Time
+}
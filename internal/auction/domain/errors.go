@@ -5,9 +5,37 @@ import "errors"
 var (
 	ErrLotNotFound                   = errors.New("auction lo not found")
 	ErrLotNotActive                  = errors.New("auction lot is not active")
+	ErrLotNotOpenYet                 = errors.New("auction lot is not open for bidding yet")
+	ErrBiddingClosed                 = errors.New("auction lot bidding has closed")
+	ErrLotPaused                     = errors.New("auction lot is paused")
+	ErrLotNotPaused                  = errors.New("auction lot is not paused")
 	ErrBidAmountTooLow               = errors.New("bid amount is too low")
+	ErrBidAmountTooHigh              = errors.New("bid amount is too high") // reverse lots only take bids below the current price
 	ErrInvalidAmount                 = errors.New("bid amount cannot be zero o less than zero")
-	ErrBidIncrementTooSmall          = errors.New("bid increment is too small") // if increment validations is implemented later
+	ErrAmountNotFinite               = errors.New("bid amount is not a finite number")
+	ErrAmountPrecision               = errors.New("bid amount has more decimals than the lot currency")
+	ErrAmountTooLarge                = errors.New("bid amount exceeds the maximum bid")
+	ErrBidIncrementTooSmall          = errors.New("bid increment is too small")
 	ErrLotAlreadyStartedOrFinished   = errors.New("auction lot is already started or finished")
 	ErrLotAlreadyFinishedOrCancelled = errors.New("auction lot is already finished or cancelled")
+	ErrCurrencyMismatch              = errors.New("bid currency does not match the lot currency")
+	ErrFeeScheduleNotFound           = errors.New("fee schedule not found")
+	ErrInvalidFeeSchedule            = errors.New("invalid fee schedule")
+	ErrBiddingLimitExceeded          = errors.New("bid exceeds the available bidding limit")
+	ErrBidNotFound                   = errors.New("bid not found")
+	ErrBidAlreadyVoided              = errors.New("bid is already voided")
+	ErrMediaNotFound                 = errors.New("lot media not found")
+	ErrCategoryNotFound              = errors.New("category not found")
+	ErrCategorySlugTaken             = errors.New("category slug is already taken")
+	ErrConcurrentLotUpdate           = errors.New("auction lot was modified concurrently")
+	ErrChatMuted                     = errors.New("user is muted in the lot chat")
+	ErrChatMessageRejected           = errors.New("chat message rejected by the filters")
+	ErrAuctioneerNotAssigned         = errors.New("auctioneer is not assigned to the lot")
+	ErrLotRulesNotFound              = errors.New("lot rules not found")
+	ErrAbsenteeBidNotFound           = errors.New("absentee bid not found")
+	ErrAbsenteeBidExists             = errors.New("the user already has a pending absentee bid on the lot")
+	ErrBundleNotFound                = errors.New("lot bundle not found")
+	ErrBundleNotOpen                 = errors.New("lot bundle is not open for bidding")
+	ErrBundleLotsOpen                = errors.New("lot bundle has lots still open")
+	ErrLotInOpenBundle               = errors.New("auction lot is already in an open bundle")
 )
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies a domain event, it's also used as routing key (subject/topic) by publishers
+type EventType string
+
+const (
+	EventBidPlaced        EventType = "bid.placed"
+	EventUserOutbid       EventType = "bid.outbid"
+	EventBidVoided        EventType = "bid.voided"
+	EventLotFinished      EventType = "lot.finished"
+	EventLotStarted       EventType = "lot.started"
+	EventLotCancelled     EventType = "lot.cancelled"
+	EventLotUpdated       EventType = "lot.updated"
+	EventLotPaused        EventType = "lot.paused"
+	EventLotResumed       EventType = "lot.resumed"
+	EventWinnerDetermined EventType = "lot.winner_determined"
+	EventBundleSettled    EventType = "bundle.settled"
+)
+
+// Event is a fact that happened in the auction domain, consumed by downstream services
+// (invoicing, analytics, notifications)
+type Event struct {
+	ID uuid.UUID `json:"id"`
+	// Seq is the per lot monotonic sequence, assigned when the event is appended to the LotEventStore
+	Seq        int64     `json:"seq,omitempty"`
+	Type       EventType `json:"type"`
+	LotID      uuid.UUID `json:"lot_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Payload    any       `json:"payload"`
+}
+
+// NewEvent creates a new Event with a random ID
+func NewEvent(eventType EventType, lotID uuid.UUID, occurredAt time.Time, payload any) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		LotID:      lotID,
+		OccurredAt: occurredAt,
+		Payload:    payload,
+	}
+}
+
+// BidPlacedPayload is the payload of EventBidPlaced
+type BidPlacedPayload struct {
+	BidID  uuid.UUID `json:"bid_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Paddle int       `json:"paddle"`
+	Amount float64   `json:"amount"`
+	// lot state after the bid, EndTime may have been extended
+	CurrentPrice float64   `json:"current_price"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// UserOutbidPayload is the payload of EventUserOutbid, UserID is the bidder who lost the lead
+type UserOutbidPayload struct {
+	UserID         uuid.UUID `json:"user_id"`
+	PreviousBidID  uuid.UUID `json:"previous_bid_id"`
+	PreviousAmount float64   `json:"previous_amount"`
+	NewBidID       uuid.UUID `json:"new_bid_id"`
+	NewAmount      float64   `json:"new_amount"`
+}
+
+// BidVoidedPayload is the payload of EventBidVoided, the lot price is recomputed from the remaining bids
+type BidVoidedPayload struct {
+	BidID    uuid.UUID `json:"bid_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Amount   float64   `json:"amount"`
+	VoidedBy uuid.UUID `json:"voided_by"`
+	Reason   string    `json:"reason"`
+	// lot state after the void
+	CurrentPrice float64 `json:"current_price"`
+}
+
+// LotFinishedPayload is the payload of EventLotFinished
+type LotFinishedPayload struct {
+	FinalPrice float64   `json:"final_price"`
+	Currency   string    `json:"currency"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// LotStartedPayload is the payload of EventLotStarted
+type LotStartedPayload struct {
+	InitialPrice float64   `json:"initial_price"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// LotCancelledPayload is the payload of EventLotCancelled
+type LotCancelledPayload struct {
+	// PreviousState is the state the lot was cancelled from, pending or active
+	PreviousState AuctionLotState `json:"previous_state"`
+}
+
+// LotUpdatedPayload is the payload of EventLotUpdated, the audit record of an edit of a lot not opened yet
+type LotUpdatedPayload struct {
+	UpdatedBy uuid.UUID `json:"updated_by"` // admin who edited the lot
+	// Changes are the edited fields, the fields left as they were are not listed
+	Changes []LotFieldChange `json:"changes"`
+	// lot listing after the edit
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	InitialPrice float64   `json:"initial_price"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// LotFieldChange is a field of a lot changed by an edit, with its values before and after it
+type LotFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// LotPausedPayload is the payload of EventLotPaused
+type LotPausedPayload struct {
+	PausedBy uuid.UUID `json:"paused_by"` // auctioneer or admin who paused the lot
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// LotResumedPayload is the payload of EventLotResumed
+type LotResumedPayload struct {
+	ResumedBy uuid.UUID `json:"resumed_by"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// WinnerDeterminedPayload is the payload of EventWinnerDetermined
+type WinnerDeterminedPayload struct {
+	WinnerID   uuid.UUID `json:"winner_id"`
+	BidID      uuid.UUID `json:"bid_id"`
+	Amount     float64   `json:"amount"`
+	FinalPrice float64   `json:"final_price"`
+	Currency   string    `json:"currency"`
+	LotTitle   string    `json:"lot_title"`
+	// OutbidUserIDs are the other bidders of the lot, who didn't win
+	OutbidUserIDs []uuid.UUID `json:"outbid_user_ids,omitempty"`
+	// BundleID is the open bundle with a bid the lot is part of: the winner only buys the lot if the
+	// bundle isn't won, the outcome is invoiced with EventBundleSettled
+	BundleID *uuid.UUID `json:"bundle_id,omitempty"`
+}
+
+// BundleSettledPayload is the payload of EventBundleSettled, published with the first lot of the bundle
+type BundleSettledPayload struct {
+	BundleID uuid.UUID   `json:"bundle_id"`
+	Title    string      `json:"title"`
+	State    BundleState `json:"state"` // won, lost or cancelled
+	LotIDs   []uuid.UUID `json:"lot_ids"`
+	Currency string      `json:"currency"`
+	// WinnerID, BidID and Amount are the winning bundle bid, set when the bundle was won
+	WinnerID uuid.UUID `json:"winner_id,omitempty"`
+	BidID    uuid.UUID `json:"bid_id,omitempty"`
+	Amount   float64   `json:"amount,omitempty"`
+	// LotWinners are the winners of the lots waiting for the bundle, set when it was not won
+	LotWinners []BundleLotWinner `json:"lot_winners,omitempty"`
+}
+
+// BundleLotWinner is the winning bid of a lot of a bundle not won
+type BundleLotWinner struct {
+	LotID      uuid.UUID `json:"lot_id"`
+	LotTitle   string    `json:"lot_title"`
+	WinnerID   uuid.UUID `json:"winner_id"`
+	BidID      uuid.UUID `json:"bid_id"`
+	FinalPrice float64   `json:"final_price"`
+}
+
+// EventPublisher publishes domain events to a message broker, implementations live in infra
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+	Close() error
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeeTier is a buyer's premium rate applied to the part of the hammer price up to UpTo,
+// from the previous tier bound. UpTo 0 means unbounded and is only valid in the last tier
+type FeeTier struct {
+	UpTo float64 `json:"up_to"`
+	Rate float64 `json:"rate"` // fraction, 0.25 is 25%
+}
+
+// FeeSchedule is the set of charges added to the hammer price of a lot when it's invoiced
+type FeeSchedule struct {
+	ID           uuid.UUID
+	Name         string
+	PremiumTiers []FeeTier // ascending by UpTo
+	FlatFee      float64
+	TaxRate      float64 // applied over hammer price, premium and flat fee
+	IsDefault    bool
+	CreatedAt    time.Time
+}
+
+// FeeBreakdown is the result of applying a fee schedule to a hammer price
+type FeeBreakdown struct {
+	HammerPrice  float64
+	BuyerPremium float64
+	FlatFee      float64
+	Tax          float64
+	Total        float64
+}
+
+// Validate checks the tiers are ascending, only the last one is unbounded and all amounts are not negative
+func (s *FeeSchedule) Validate() error {
+	if s.FlatFee < 0 {
+		return fmt.Errorf("%w: flat fee can't be negative", ErrInvalidFeeSchedule)
+	}
+	if s.TaxRate < 0 || s.TaxRate > 1 {
+		return fmt.Errorf("%w: tax rate must be between 0 and 1", ErrInvalidFeeSchedule)
+	}
+	prev := 0.0
+	for i, tier := range s.PremiumTiers {
+		if tier.Rate < 0 || tier.Rate > 1 {
+			return fmt.Errorf("%w: tier %d rate must be between 0 and 1", ErrInvalidFeeSchedule, i)
+		}
+		if tier.UpTo == 0 && i != len(s.PremiumTiers)-1 {
+			return fmt.Errorf("%w: only the last tier can be unbounded", ErrInvalidFeeSchedule)
+		}
+		if tier.UpTo != 0 && tier.UpTo <= prev {
+			return fmt.Errorf("%w: tier %d must end above the previous one", ErrInvalidFeeSchedule, i)
+		}
+		prev = tier.UpTo
+	}
+	return nil
+}
+
+// Apply computes the charges of hammerPrice, amounts are rounded to the minor units of currency.
+// the part of the price above the last bounded tier has no premium
+func (s *FeeSchedule) Apply(hammerPrice float64, currency string) FeeBreakdown {
+	premium, lower := 0.0, 0.0
+	for _, tier := range s.PremiumTiers {
+		upper := tier.UpTo
+		if upper == 0 || upper > hammerPrice {
+			upper = hammerPrice
+		}
+		if upper > lower {
+			premium += (upper - lower) * tier.Rate
+		}
+		if tier.UpTo == 0 || tier.UpTo >= hammerPrice {
+			break
+		}
+		lower = tier.UpTo
+	}
+	premium = RoundAmount(premium, currency)
+	tax := RoundAmount((hammerPrice+premium+s.FlatFee)*s.TaxRate, currency)
+	return FeeBreakdown{
+		HammerPrice:  hammerPrice,
+		BuyerPremium: premium,
+		FlatFee:      s.FlatFee,
+		Tax:          tax,
+		Total:        RoundAmount(hammerPrice+premium+s.FlatFee+tax, currency),
+	}
+}
+
+// FlatRateFeeSchedule is a schedule with a single premium rate, used when no schedule is configured
+func FlatRateFeeSchedule(premiumRate, taxRate float64) *FeeSchedule {
+	return &FeeSchedule{
+		Name:         "flat rate",
+		PremiumTiers: []FeeTier{{Rate: premiumRate}},
+		TaxRate:      taxRate,
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "testing"
+
+// TestFeeScheduleApplyCurrencyDecimals checks the charges are rounded to the minor units of the lot
+// currency, one currency of each precision
+func TestFeeScheduleApplyCurrencyDecimals(t *testing.T) {
+	schedule := &FeeSchedule{
+		PremiumTiers: []FeeTier{{UpTo: 1000, Rate: 0.25}, {Rate: 0.20}},
+		TaxRate:      0.07,
+	}
+	tests := []struct {
+		currency string
+		hammer   float64
+		want     FeeBreakdown
+	}{
+		{"JPY", 1234, FeeBreakdown{HammerPrice: 1234, BuyerPremium: 297, Tax: 107, Total: 1638}},
+		{"USD", 1234.56, FeeBreakdown{HammerPrice: 1234.56, BuyerPremium: 296.91, Tax: 107.20, Total: 1638.67}},
+		{"KWD", 1234.567, FeeBreakdown{HammerPrice: 1234.567, BuyerPremium: 296.913, Tax: 107.204, Total: 1638.684}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := schedule.Apply(tt.hammer, tt.currency); got != tt.want {
+				t.Errorf("Apply(%v, %s) = %+v, want %+v", tt.hammer, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
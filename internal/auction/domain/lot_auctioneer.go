@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LotAuctioneer is the assignment of an auctioneer to a lot, only assigned auctioneers run it live
+type LotAuctioneer struct {
+	LotID      uuid.UUID
+	UserID     uuid.UUID
+	AssignedBy uuid.UUID
+	AssignedAt time.Time
+}
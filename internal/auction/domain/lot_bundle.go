@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BundleState is the state of a lot bundle
+type BundleState string
+
+const (
+	BundleOpen      BundleState = "open"      // taking bids until its lots close
+	BundleWon       BundleState = "won"       // the leading bundle bid beat the sum of the winning bids of the lots
+	BundleLost      BundleState = "lost"      // no bundle bid beat the sum of the winning bids of the lots
+	BundleCancelled BundleState = "cancelled" // a lot of the bundle was cancelled
+)
+
+// LotBundle is a combination of forward lots bid on as a whole: the bundle bids compete against the
+// sum of the leading bids of its lots and, once all the lots closed, the bundle wins them together if
+// its leading bid beats the sum of their winning bids
+type LotBundle struct {
+	ID       uuid.UUID
+	OrgID    uuid.UUID // organization (auction house) owning the lots
+	Title    string
+	LotIDs   []uuid.UUID
+	Currency string // shared by all the lots of the bundle
+	State    BundleState
+	// LeadingBid is the highest bundle bid, nil until the first one
+	LeadingBid *BundleBid
+	// IndividualSum is the sum of the winning bids of the lots, set at settlement
+	IndividualSum float64
+	CreatedBy     uuid.UUID
+	CreatedAt     time.Time
+	SettledAt     *time.Time
+}
+
+// BundleBid is a bid on all the lots of a bundle
+type BundleBid struct {
+	ID        uuid.UUID
+	BundleID  uuid.UUID
+	UserID    uuid.UUID
+	Amount    float64
+	Timestamp time.Time
+}
+
+// LeadingAmount returns the amount of the leading bundle bid, 0 without bids
+func (b *LotBundle) LeadingAmount() float64 {
+	if b.LeadingBid == nil {
+		return 0
+	}
+	return b.LeadingBid.Amount
+}
+
+// NewBid validates a bid of amount on the bundle, it must beat both the leading bundle bid and
+// individualSum, the sum of the leading bids of the lots
+func (b *LotBundle) NewBid(userID uuid.UUID, amount, individualSum float64, now time.Time) (*BundleBid, error) {
+	if b.State != BundleOpen {
+		return nil, ErrBundleNotOpen
+	}
+	if err := ValidateAmount(amount, b.Currency); err != nil {
+		return nil, err
+	}
+	floor := max(b.LeadingAmount(), individualSum)
+	if MinorUnits(amount, b.Currency) <= MinorUnits(floor, b.Currency) {
+		return nil, ErrBidAmountTooLow
+	}
+	return &BundleBid{
+		ID:        uuid.New(),
+		BundleID:  b.ID,
+		UserID:    userID,
+		Amount:    amount,
+		Timestamp: now,
+	}, nil
+}
+
+// Settle decides the bundle once all its lots closed: cancelled when one of them was cancelled, won
+// when the leading bundle bid beats the sum of their winning bids and lost otherwise.
+// ErrBundleLotsOpen is returned while a lot is still open
+func (b *LotBundle) Settle(lots []*AuctionLot, now time.Time) error {
+	if b.State != BundleOpen {
+		return ErrBundleNotOpen
+	}
+	state, sum := BundleLost, 0.0
+	for _, lot := range lots {
+		switch lot.State {
+		case StateCancelled:
+			state = BundleCancelled
+		case StateFinished:
+		default:
+			return ErrBundleLotsOpen
+		}
+		sum += lot.LeadingBidAmount()
+	}
+	if state != BundleCancelled && b.LeadingBid != nil &&
+		MinorUnits(b.LeadingBid.Amount, b.Currency) > MinorUnits(sum, b.Currency) {
+		state = BundleWon
+	}
+	b.State, b.IndividualSum, b.SettledAt = state, RoundAmount(sum, b.Currency), &now
+	return nil
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaKind is the type of a lot media attachment
+type MediaKind string
+
+const (
+	MediaImage MediaKind = "image"
+	MediaVideo MediaKind = "video"
+)
+
+// Valid reports if k is a supported media kind
+func (k MediaKind) Valid() bool {
+	return k == MediaImage || k == MediaVideo
+}
+
+// LotMedia is an image or video attached to a lot, so bidders see what they're bidding on
+type LotMedia struct {
+	ID    uuid.UUID
+	LotID uuid.UUID
+	Kind  MediaKind
+	URL   string
+	// Position is the display order inside the lot, assigned by the repository on insert
+	Position  int
+	CreatedAt time.Time
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LotRules are the expressions the operators set on a lot to change its bid rules without a deploy
+type LotRules struct {
+	LotID uuid.UUID
+	// MinIncrement computes the min increment of the next bid, replacing the increment table, "" keeps it
+	MinIncrement string
+	// Eligibility must be true for a bidder to bid on the lot, "" lets everyone bid
+	Eligibility string
+	// AllowedCountries and BlockedCountries restrict the connections and bids to some jurisdictions by
+	// the country of the client IP, ISO 3166-1 alpha-2 codes. empty lets every country in
+	AllowedCountries []string
+	BlockedCountries []string
+	// TermsVersion is the version of the terms the bidders must accept before bidding, "" requires none
+	TermsVersion string
+	// RequireVerified restricts the bids to the bidders whose identity was verified (KYC), e.g. on the
+	// high value lots
+	RequireVerified bool
+	// ReservePrice is the price the seller wants the lot to reach, the bidders only see whether it's met.
+	// it's informative, the best bid still wins the lot. 0 means no reserve
+	ReservePrice float64
+	UpdatedBy    uuid.UUID
+	UpdatedAt    time.Time
+}
+
+// TermsAcceptance records that a user accepted a version of the terms of a lot
+type TermsAcceptance struct {
+	UserID       uuid.UUID
+	LotID        uuid.UUID
+	TermsVersion string
+	ClientIP     string
+	AcceptedAt   time.Time
+}
+
+// BidderProfile is what the lot rules know about a bidder
+type BidderProfile struct {
+	UserID       uuid.UUID
+	RegisteredAt time.Time
+}
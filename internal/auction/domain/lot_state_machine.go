@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotTransition is a change of the state of a lot requested by an operation
+type LotTransition string
+
+const (
+	TransitionSchedule LotTransition = "schedule" // pending → preview, until the scheduled start time
+	TransitionStart    LotTransition = "start"    // pending or preview → active
+	TransitionPause    LotTransition = "pause"    // active → paused
+	TransitionResume   LotTransition = "resume"   // paused → active
+	TransitionFinish   LotTransition = "finish"   // active → finished
+	TransitionCancel   LotTransition = "cancel"   // any state but finished → cancelled
+)
+
+// ErrInvalidTransition is matched by every TransitionError, whatever the transition
+var ErrInvalidTransition = errors.New("invalid auction lot state transition")
+
+// lotTransitionRule is a row of the transition table
+type lotTransitionRule struct {
+	from []AuctionLotState
+	to   AuctionLotState
+	// err is the sentinel wrapped by the TransitionError of a lot in a state not listed in from
+	err error
+}
+
+// lotTransitions is the transition table of the lot state machine, a transition missing here is never allowed
+var lotTransitions = map[LotTransition]lotTransitionRule{
+	TransitionSchedule: {from: []AuctionLotState{StatePending, StatePreview}, to: StatePreview, err: ErrLotAlreadyStartedOrFinished},
+	TransitionStart:    {from: []AuctionLotState{StatePending, StatePreview}, to: StateActive, err: ErrLotAlreadyStartedOrFinished},
+	TransitionPause:    {from: []AuctionLotState{StateActive}, to: StatePaused, err: ErrLotNotActive},
+	TransitionResume:   {from: []AuctionLotState{StatePaused}, to: StateActive, err: ErrLotNotPaused},
+	TransitionFinish:   {from: []AuctionLotState{StateActive}, to: StateFinished, err: ErrLotNotActive},
+	TransitionCancel: {
+		from: []AuctionLotState{StatePending, StatePreview, StateActive, StatePaused},
+		to:   StateCancelled,
+		err:  ErrLotAlreadyFinishedOrCancelled,
+	},
+}
+
+// TransitionError is returned by the lot operations not allowed in the current state of the lot, it
+// matches both ErrInvalidTransition and the sentinel of the transition (e.g. ErrLotNotActive for pause)
+type TransitionError struct {
+	LotID      uuid.UUID
+	Transition LotTransition
+	From       AuctionLotState
+	err        error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%v: cannot %s a %s lot", e.err, e.Transition, e.From)
+}
+
+func (e *TransitionError) Unwrap() []error {
+	return []error{e.err, ErrInvalidTransition}
+}
+
+// LotStateChange is a transition applied to a lot, recorded until the lot changes are taken
+type LotStateChange struct {
+	LotID      uuid.UUID
+	Transition LotTransition
+	From       AuctionLotState
+	To         AuctionLotState
+	At         time.Time
+}
+
+// CanTransition reports if a lot in state from allows transition t
+func CanTransition(from AuctionLotState, t LotTransition) bool {
+	rule, ok := lotTransitions[t]
+	return ok && slices.Contains(rule.from, from)
+}
+
+// Terminal reports if no transition leaves the state, a finished or cancelled lot never changes again
+func (s AuctionLotState) Terminal() bool {
+	for t := range lotTransitions {
+		if CanTransition(s, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// transition moves the lot to the target state of t and records the change, the lot is left as it was
+// when the table doesn't allow t from the current state. al.mu must be held
+func (al *AuctionLot) transition(t LotTransition) (LotStateChange, error) {
+	rule, ok := lotTransitions[t]
+	if !ok || !slices.Contains(rule.from, al.State) {
+		log.Warn("Lot state transition rejected",
+			zap.String("lotID", al.ID.String()),
+			zap.String("transition", string(t)),
+			zap.String("state", string(al.State)),
+		)
+		err := rule.err
+		if err == nil {
+			err = ErrInvalidTransition
+		}
+		return LotStateChange{}, &TransitionError{LotID: al.ID, Transition: t, From: al.State, err: err}
+	}
+	change := LotStateChange{LotID: al.ID, Transition: t, From: al.State, To: rule.to, At: al.now()}
+	al.State = rule.to
+	al.stateChanges = append(al.stateChanges, change)
+	return change, nil
+}
+
+// TakeStateChanges returns the transitions applied to the lot since the last call, oldest first, and
+// clears them
+func (al *AuctionLot) TakeStateChanges() []LotStateChange {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	changes := al.stateChanges
+	al.stateChanges = nil
+	return changes
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// UnitPricing is what the winners of a lot of several units pay for their unit
+type UnitPricing string
+
+const (
+	PricingPayAsBid UnitPricing = "pay_as_bid" // every winner pays its own bid
+	PricingUniform  UnitPricing = "uniform"    // every winner pays the lowest winning bid
+)
+
+// MultiUnit reports if the lot sells several identical units, its Quantity best bidders win one each
+func (al *AuctionLot) MultiUnit() bool {
+	return al.Quantity > 1
+}
+
+// SetStandingBids loads the bids holding the units of a multi-unit lot from the valid bids of the lot:
+// the best bid of each bidder, the Quantity best ones. the current price becomes the price to take a
+// unit, the lowest standing bid once all the units are taken and the initial price before
+func (al *AuctionLot) SetStandingBids(bids []*Bid) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.setStanding(bids)
+}
+
+// setStanding sets Standing and the current price from bids, al.mu must be held
+func (al *AuctionLot) setStanding(bids []*Bid) {
+	best := make(map[uuid.UUID]*Bid, len(bids))
+	for _, bid := range bids {
+		if bid.VoidedAt != nil {
+			continue
+		}
+		if held, ok := best[bid.UserID]; !ok || standsBefore(bid, held) {
+			best[bid.UserID] = bid
+		}
+	}
+	standing := make([]*Bid, 0, len(best))
+	for _, bid := range best {
+		standing = append(standing, bid)
+	}
+	al.Standing = al.rankStanding(standing)
+}
+
+// standBid makes bid the standing bid of its bidder, displacing the lowest standing bid of another
+// bidder when all the units were taken, al.mu must be held
+func (al *AuctionLot) standBid(bid *Bid) {
+	al.displaced = nil
+	// a new slice, so a caller holding the previous Standing can restore it
+	standing := make([]*Bid, 0, len(al.Standing)+1)
+	for _, held := range al.Standing {
+		if held.UserID != bid.UserID {
+			standing = append(standing, held)
+		}
+	}
+	standing = append(standing, bid)
+	if len(standing) > al.Quantity {
+		slices.SortFunc(standing, compareStanding)
+		al.displaced = standing[al.Quantity]
+	}
+	al.Standing = al.rankStanding(standing)
+}
+
+// rankStanding orders the standing bids best first, keeps the Quantity best ones and sets the current
+// price to the price of a unit, al.mu must be held
+func (al *AuctionLot) rankStanding(standing []*Bid) []*Bid {
+	slices.SortFunc(standing, compareStanding)
+	if len(standing) > al.Quantity {
+		standing = standing[:al.Quantity]
+	}
+	al.CurrentPrice = al.InitialPrice
+	if len(standing) == al.Quantity {
+		al.CurrentPrice = standing[len(standing)-1].Amount
+	}
+	return standing
+}
+
+// StandingBidOf returns the standing bid of the user on a multi-unit lot, nil if it holds no unit
+func (al *AuctionLot) StandingBidOf(userID uuid.UUID) *Bid {
+	for _, bid := range al.Standing {
+		if bid.UserID == userID {
+			return bid
+		}
+	}
+	return nil
+}
+
+// UnitsAvailable returns the units of a multi-unit lot no bid holds yet
+func (al *AuctionLot) UnitsAvailable() int {
+	return max(al.Quantity-len(al.Standing), 0)
+}
+
+// UnitPrice returns what the standing bid pays for its unit: its amount, or the lowest standing bid
+// when the lot has uniform pricing
+func (al *AuctionLot) UnitPrice(bid *Bid) float64 {
+	if al.Pricing == PricingUniform && len(al.Standing) > 0 {
+		return al.Standing[len(al.Standing)-1].Amount
+	}
+	return bid.Amount
+}
+
+// TakeDisplacedBid returns the standing bid the last bid on a multi-unit lot pushed out of the units,
+// nil when it took a free unit or raised the bid of its bidder, and forgets it
+func (al *AuctionLot) TakeDisplacedBid() *Bid {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	displaced := al.displaced
+	al.displaced = nil
+	return displaced
+}
+
+// compareStanding orders the bids best first: the highest amount, the earliest on ties
+func compareStanding(a, b *Bid) int {
+	switch {
+	case standsBefore(a, b):
+		return -1
+	case standsBefore(b, a):
+		return 1
+	}
+	return 0
+}
+
+// standsBefore reports if bid a ranks before bid b for a unit
+func standsBefore(a, b *Bid) bool {
+	if a.Amount != b.Amount {
+		return a.Amount > b.Amount
+	}
+	return a.Timestamp.Before(b.Timestamp)
+}
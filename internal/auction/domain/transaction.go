@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// Tx is a unit of work spanning several repository calls, it's opaque to the domain and application
+// layers: each storage implementation only accepts the transactions started by its own Transactor
+type Tx interface {
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// Transactor starts the transactions passed to the repository writes
+type Transactor interface {
+	Begin(ctx context.Context) (Tx, error)
+	// Retryable reports if err aborted a transaction because of a concurrent one (ErrConcurrentLotUpdate,
+	// serialization failures, deadlocks), the whole transaction may succeed when run again
+	Retryable(err error) bool
+}
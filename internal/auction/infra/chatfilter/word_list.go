@@ -0,0 +1,67 @@
+package chatfilter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// WordListFilter implements domain.ChatFilter over a list of blocked words, matched case insensitive
+// as whole words. the blocked words are masked with asterisks, or the whole msg is rejected
+type WordListFilter struct {
+	words  map[string]struct{}
+	reject bool
+}
+
+// NewWordListFilter creates a new instance of WordListFilter, reject refuses the msgs with blocked words
+// instead of masking them
+func NewWordListFilter(words []string, reject bool) *WordListFilter {
+	f := &WordListFilter{words: make(map[string]struct{}, len(words)), reject: reject}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			f.words[word] = struct{}{}
+		}
+	}
+	return f
+}
+
+// Filter implements domain.ChatFilter
+func (f *WordListFilter) Filter(ctx context.Context, lotID, userID uuid.UUID, text string) (string, error) {
+	if len(f.words) == 0 {
+		return text, nil
+	}
+	runes := []rune(text)
+	blocked := false
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		if _, ok := f.words[strings.ToLower(string(runes[start:end]))]; ok {
+			blocked = true
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+		start = end
+	}
+	if !blocked {
+		return text, nil
+	}
+	if f.reject {
+		return "", fmt.Errorf("%w: blocked words", domain.ErrChatMessageRejected)
+	}
+	return string(runes), nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
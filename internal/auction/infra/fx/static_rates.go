@@ -0,0 +1,57 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+)
+
+// ErrUnknownCurrency is returned when there is no rate configured for a currency
+var ErrUnknownCurrency = errors.New("no FX rate for currency")
+
+// StaticRateProvider implements application.FXRateProvider with fixed rates against a base currency,
+// enough for indicative prices, a live provider can replace it behind the same interface
+type StaticRateProvider struct {
+	base  string
+	rates map[string]float64 // units of the currency per unit of base
+}
+
+// NewStaticRateProvider creates a new instance of StaticRateProvider from "CODE=rate" items,
+// e.g. "EUR=0.92" means one unit of base buys 0.92 EUR
+func NewStaticRateProvider(base string, items []string) (*StaticRateProvider, error) {
+	base, ok := domain.NormalizeCurrency(base)
+	if !ok {
+		return nil, fmt.Errorf("invalid FX base currency %q", base)
+	}
+	rates := map[string]float64{base: 1}
+	for _, item := range items {
+		rawCode, rawRate, found := strings.Cut(item, "=")
+		code, ok := domain.NormalizeCurrency(rawCode)
+		if !found || !ok {
+			return nil, fmt.Errorf("invalid FX rate %q, expected CODE=rate", item)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid FX rate %q, rate must be a positive number", item)
+		}
+		rates[code] = rate
+	}
+	return &StaticRateProvider{base: base, rates: rates}, nil
+}
+
+// Rate implements application.FXRateProvider crossing both currencies through the base one
+func (p *StaticRateProvider) Rate(_ context.Context, from, to string) (float64, error) {
+	fromRate, ok := p.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := p.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrUnknownCurrency, to)
+	}
+	return toRate / fromRate, nil
+}
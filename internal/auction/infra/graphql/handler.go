@@ -0,0 +1,74 @@
+// Package graphql serves the GraphQL API of the auction module. it uses graph-gophers/graphql-go instead of
+// gqlgen: the schema.graphql SDL is bound to the resolvers at runtime, so there is no generated code to keep
+// in sync with the schema, and the handlers run on Fiber directly where gqlgen ships net/http handlers
+// (its websocket transport included) that would need an adaptor over fasthttp
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	userdomain "github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/gofiber/fiber/v2"
+	fws "github.com/gofiber/websocket/v2" // Alias to avoid name conflicts
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxQueryDepth bounds the nesting of the queries, lot -> bids is the deepest legit path
+const maxQueryDepth = 8
+
+// graphqlRequest is the JSON body of POST /graphql and the payload of a WS subscribe msg
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler exposes the lots, bids, results and users through GraphQL, queries over HTTP and
+// subscriptions over WS with the graphql-transport-ws protocol
+type Handler struct {
+	ctx    context.Context
+	schema *gql.Schema
+}
+
+// NewHandler parses the schema and binds it to the resolvers, ctx ends the running subscriptions
+func NewHandler(ctx context.Context,
+	auctionService application.AuctionService,
+	lotBids *application.LotBidsUseCase,
+	userBids *application.UserBidsUseCase,
+	users userdomain.UserRepository) (*Handler, error) {
+	resolver := &Resolver{
+		auctionService: auctionService,
+		lotBids:        lotBids,
+		userBids:       userBids,
+		users:          users,
+	}
+	schema, err := gql.ParseSchema(schemaSDL, resolver, gql.MaxDepth(maxQueryDepth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema: %w", err)
+	}
+	return &Handler{ctx: ctx, schema: schema}, nil
+}
+
+// RegisterRoutes registers POST /graphql on api and the subscriptions endpoint /graphql on ws (the WS
+// router, whose upgrades are already authenticated), optionalAuth identifies the HTTP callers
+func (h *Handler) RegisterRoutes(api, ws fiber.Router, optionalAuth fiber.Handler) {
+	api.Post("/graphql", optionalAuth, h.serveHTTP)
+	ws.Get("/graphql", fws.New(h.serveWS, fws.Config{Subprotocols: []string{subprotocol}}))
+}
+
+// serveHTTP runs a query, the response carries the data and errors as the GraphQL spec says
+func (h *Handler) serveHTTP(c *fiber.Ctx) error {
+	var req graphqlRequest
+	if err := c.BodyParser(&req); err != nil || req.Query == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid GraphQL request")
+	}
+	ctx := withClaims(c.UserContext(), httpserver.ClaimsFrom(c))
+	return c.JSON(h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
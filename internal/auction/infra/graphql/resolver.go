@@ -0,0 +1,272 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	userdomain "github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// errForbidden is returned by the fields restricted to admins
+var errForbidden = errors.New("insufficient role")
+
+// pageArgs are the pagination arguments of the list fields
+type pageArgs struct {
+	Limit  *int32
+	Offset *int32
+}
+
+// bounds returns the page as ints, the use cases apply the defaults and caps
+func (a pageArgs) bounds() (int, int) {
+	return int(deref(a.Limit)), int(deref(a.Offset))
+}
+
+// Resolver is the root resolver of the schema, its fields are the Query and Subscription fields
+type Resolver struct {
+	auctionService application.AuctionService
+	lotBids        *application.LotBidsUseCase
+	userBids       *application.UserBidsUseCase
+	users          userdomain.UserRepository
+}
+
+// Lot resolves Query.lot
+func (r *Resolver) Lot(ctx context.Context, args struct{ ID gql.ID }) (*lotResolver, error) {
+	lotID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := r.auctionService.GetLotState(ctx, lotID)
+	if errors.Is(err, domain.ErrLotNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return r.newLot(ctx, state), nil
+}
+
+// Lots resolves Query.lots
+func (r *Resolver) Lots(ctx context.Context, args struct {
+	Query    *string
+	State    *string
+	Category *string
+	Limit    *int32
+	Offset   *int32
+}) ([]*lotResolver, error) {
+	limit, offset := pageArgs{args.Limit, args.Offset}.bounds()
+	lots, err := r.auctionService.SearchLots(ctx, application.SearchLotsDTO{
+		Query:    deref(args.Query),
+		State:    deref(args.State),
+		Category: deref(args.Category),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return r.newLots(ctx, lots), nil
+}
+
+// ActiveLots resolves Query.activeLots
+func (r *Resolver) ActiveLots(ctx context.Context) ([]*lotResolver, error) {
+	lots, err := r.auctionService.ListActiveLots(ctx)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return r.newLots(ctx, lots), nil
+}
+
+// Bids resolves Query.bids
+func (r *Resolver) Bids(ctx context.Context, args struct {
+	LotID  gql.ID
+	Limit  *int32
+	Offset *int32
+}) ([]*bidResolver, error) {
+	lotID, err := parseID(args.LotID)
+	if err != nil {
+		return nil, err
+	}
+	return r.lotBidsPage(ctx, lotID, pageArgs{args.Limit, args.Offset})
+}
+
+// lotBidsPage returns a page of the bids of a lot as seen by the caller
+func (r *Resolver) lotBidsPage(ctx context.Context, lotID uuid.UUID, page pageArgs) ([]*bidResolver, error) {
+	limit, offset := page.bounds()
+	bids, err := r.lotBids.Execute(ctx, application.LotBidsPageDTO{LotID: lotID, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	viewerID, admin := viewerOf(ctx)
+	resolvers := make([]*bidResolver, 0, len(bids))
+	for _, bid := range bids {
+		resolvers = append(resolvers, &bidResolver{bid: bid, identified: admin || bid.UserID == viewerID})
+	}
+	return resolvers, nil
+}
+
+// Results resolves Query.results, the finished lots are loaded in full to get their winner
+func (r *Resolver) Results(ctx context.Context, args pageArgs) ([]*resultResolver, error) {
+	limit, offset := args.bounds()
+	lots, err := r.auctionService.SearchLots(ctx, application.SearchLotsDTO{
+		State:  string(domain.StateFinished),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	results := make([]*resultResolver, 0, len(lots))
+	for _, lot := range lots {
+		state, err := r.auctionService.GetLotState(ctx, lot.LotID)
+		if err != nil {
+			return nil, toGraphQLError(ctx, err)
+		}
+		results = append(results, &resultResolver{lot: r.newLot(ctx, state)})
+	}
+	return results, nil
+}
+
+// Me resolves Query.me
+func (r *Resolver) Me(ctx context.Context) (*userResolver, error) {
+	claims := claimsFrom(ctx)
+	if claims == nil || claims.UserID == uuid.Nil {
+		return nil, nil
+	}
+	return r.user(ctx, claims.UserID)
+}
+
+// User resolves Query.user, admins only
+func (r *Resolver) User(ctx context.Context, args struct{ ID gql.ID }) (*userResolver, error) {
+	if _, admin := viewerOf(ctx); !admin {
+		return nil, errForbidden
+	}
+	userID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.user(ctx, userID)
+}
+
+func (r *Resolver) user(ctx context.Context, userID uuid.UUID) (*userResolver, error) {
+	user, err := r.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// LotUpdated resolves Subscription.lotUpdated, the stream ends when the subscription ctx is done
+func (r *Resolver) LotUpdated(ctx context.Context, args struct{ LotID gql.ID }) (<-chan *lotResolver, error) {
+	lotID, err := parseID(args.LotID)
+	if err != nil {
+		return nil, err
+	}
+	// the lot must be visible to the caller organization before its updates are streamed
+	if _, err := r.auctionService.GetLotState(ctx, lotID); err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	updates, cancel := r.auctionService.WatchLot(lotID)
+	stream := make(chan *lotResolver)
+	go func() {
+		defer close(stream)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case state, ok := <-updates:
+				if !ok {
+					return
+				}
+				select {
+				case stream <- r.newLot(ctx, state):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return stream, nil
+}
+
+// newLot wraps state, hiding the last bidder identity from callers other than admins and the bidder
+func (r *Resolver) newLot(ctx context.Context, state *application.LotStateDTO) *lotResolver {
+	return &lotResolver{root: r, state: state.ForViewer(viewerOf(ctx))}
+}
+
+func (r *Resolver) newLots(ctx context.Context, states []*application.LotStateDTO) []*lotResolver {
+	resolvers := make([]*lotResolver, 0, len(states))
+	for _, state := range states {
+		resolvers = append(resolvers, r.newLot(ctx, state))
+	}
+	return resolvers
+}
+
+// parseID parses a GraphQL ID as an UUID
+func parseID(id gql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid ID")
+	}
+	return parsed, nil
+}
+
+// toGraphQLError returns the errors meant for the caller as they are, other errors are logged and
+// reported without details, like the REST and gRPC APIs do
+func toGraphQLError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrLotNotFound),
+		errors.Is(err, domain.ErrCategoryNotFound),
+		errors.Is(err, application.ErrInvalidSearch),
+		errors.Is(err, application.ErrLotBusy):
+		return err
+	default:
+		logger.FromContext(ctx).Error("GraphQL request failed", zap.Error(err))
+		return errors.New("internal error, request id " + logger.CorrelationID(ctx))
+	}
+}
+
+// claimsKey is the ctx key of the caller claims
+type claimsKey struct{}
+
+// withClaims returns ctx carrying the caller claims, nil for anonymous callers
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	if claims != nil {
+		ctx = tenant.WithOrgID(ctx, claims.OrgID)
+	}
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func claimsFrom(ctx context.Context) *auth.Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*auth.Claims)
+	return claims
+}
+
+// viewerOf returns the caller user ID and if it's an admin, uuid.Nil for anonymous callers
+func viewerOf(ctx context.Context) (uuid.UUID, bool) {
+	claims := claimsFrom(ctx)
+	if claims == nil {
+		return uuid.Nil, false
+	}
+	return claims.UserID, claims.Can(auth.PermViewBidders)
+}
+
+// deref returns the value of p, the zero value when p is nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,191 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	fws "github.com/gofiber/websocket/v2" // Alias to avoid name conflicts
+	gql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// subprotocol is the WS subprotocol of the subscriptions, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const subprotocol = "graphql-transport-ws"
+
+const (
+	// initTimeout is how long a new connection has to send connection_init
+	initTimeout = 10 * time.Second
+	// writeWait is the time allowed to write a msg to the connection
+	writeWait = 10 * time.Second
+)
+
+// graphql-transport-ws msg types
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+)
+
+// graphql-transport-ws close codes
+const (
+	closeBadRequest        = 4400
+	closeUnauthorized      = 4401
+	closeInitTimeout       = 4408
+	closeDuplicateID       = 4409
+	closeTooManyInitialise = 4429
+)
+
+// wsMessage is a graphql-transport-ws msg, Payload depends on Type
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// subscriptionConn is a WS connection running the subscriptions of a client
+type subscriptionConn struct {
+	conn   *fws.Conn
+	schema *gql.Schema
+	ctx    context.Context
+	// writes come from the read loop and every subscription
+	writeMu sync.Mutex
+	subsMu  sync.Mutex
+	subs    map[string]context.CancelFunc
+}
+
+// serveWS runs the graphql-transport-ws protocol until the client closes the connection or the
+// handler ctx is done, every subscription of the connection ends with it
+func (h *Handler) serveWS(c *fws.Conn) {
+	ctx, cancel := context.WithCancel(withClaims(h.ctx, httpserver.ConnClaims(c)))
+	defer cancel()
+	sc := &subscriptionConn{conn: c, schema: h.schema, ctx: ctx, subs: make(map[string]context.CancelFunc)}
+	// the read loop is blocked on the connection, closing it on shutdown makes it return
+	go func() {
+		<-ctx.Done()
+		_ = c.Close()
+	}()
+
+	_ = c.SetReadDeadline(time.Now().Add(initTimeout))
+	acknowledged := false
+	for {
+		var msg wsMessage
+		if err := c.ReadJSON(&msg); err != nil {
+			if !acknowledged {
+				sc.close(closeInitTimeout, "connection initialisation timeout")
+			}
+			return
+		}
+		switch msg.Type {
+		case msgConnectionInit:
+			if acknowledged {
+				sc.close(closeTooManyInitialise, "too many initialisation requests")
+				return
+			}
+			acknowledged = true
+			_ = c.SetReadDeadline(time.Time{})
+			sc.write(wsMessage{Type: msgConnectionAck})
+		case msgPing:
+			sc.write(wsMessage{Type: msgPong})
+		case msgPong:
+		case msgSubscribe:
+			if !acknowledged {
+				sc.close(closeUnauthorized, "unauthorized")
+				return
+			}
+			var req graphqlRequest
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil || req.Query == "" {
+				sc.close(closeBadRequest, "invalid subscribe message")
+				return
+			}
+			if !sc.start(msg.ID, req) {
+				sc.close(closeDuplicateID, "subscriber for "+msg.ID+" already exists")
+				return
+			}
+		case msgComplete:
+			sc.stop(msg.ID)
+		default:
+			sc.close(closeBadRequest, "invalid message type")
+			return
+		}
+	}
+}
+
+// start runs the operation req under id, it returns false if id is already running
+func (sc *subscriptionConn) start(id string, req graphqlRequest) bool {
+	sc.subsMu.Lock()
+	defer sc.subsMu.Unlock()
+	if _, ok := sc.subs[id]; ok {
+		return false
+	}
+	ctx, cancel := context.WithCancel(sc.ctx)
+	sc.subs[id] = cancel
+	go sc.run(ctx, id, req)
+	return true
+}
+
+// stop ends the operation id, the client completed it
+func (sc *subscriptionConn) stop(id string) {
+	sc.subsMu.Lock()
+	defer sc.subsMu.Unlock()
+	if cancel, ok := sc.subs[id]; ok {
+		cancel()
+		delete(sc.subs, id)
+	}
+}
+
+// run streams the responses of the operation, queries send a single one. the operation is completed
+// when its stream ends unless the client or the connection ended it first
+func (sc *subscriptionConn) run(ctx context.Context, id string, req graphqlRequest) {
+	responses, err := sc.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		sc.writePayload(id, msgError, []map[string]string{{"message": err.Error()}})
+		sc.stop(id)
+		return
+	}
+	for res := range responses {
+		if ctx.Err() != nil {
+			continue // drain until the resolvers see the cancellation
+		}
+		sc.writePayload(id, msgNext, res)
+	}
+	if ctx.Err() == nil {
+		sc.stop(id)
+		sc.write(wsMessage{ID: id, Type: msgComplete})
+	}
+}
+
+// writePayload sends a msg of the operation id with payload serialized as JSON
+func (sc *subscriptionConn) writePayload(id, msgType string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logger.FromContext(sc.ctx).Error("failed to marshal GraphQL response", zap.String("id", id), zap.Error(err))
+		return
+	}
+	sc.write(wsMessage{ID: id, Type: msgType, Payload: data})
+}
+
+func (sc *subscriptionConn) write(msg wsMessage) {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+	_ = sc.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := sc.conn.WriteJSON(msg); err != nil {
+		logger.FromContext(sc.ctx).Debug("failed to write GraphQL WS message", zap.String("type", msg.Type), zap.Error(err))
+	}
+}
+
+// close ends the connection with a graphql-transport-ws close code
+func (sc *subscriptionConn) close(code int, reason string) {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+	_ = sc.conn.WriteControl(fws.CloseMessage, fws.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
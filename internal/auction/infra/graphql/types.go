@@ -0,0 +1,217 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	userdomain "github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// lotResolver resolves the Lot type from the lot state, already filtered for the caller
+type lotResolver struct {
+	root  *Resolver
+	state *application.LotStateDTO
+}
+
+func (l *lotResolver) ID() gql.ID             { return gql.ID(l.state.LotID.String()) }
+func (l *lotResolver) Title() string          { return l.state.Title }
+func (l *lotResolver) Description() string    { return l.state.Description }
+func (l *lotResolver) InitialPrice() float64  { return l.state.InitialPrice }
+func (l *lotResolver) CurrentPrice() float64  { return l.state.CurrentPrice }
+func (l *lotResolver) Currency() string       { return l.state.Currency }
+func (l *lotResolver) Type() string           { return l.state.Type }
+func (l *lotResolver) State() string          { return l.state.State }
+func (l *lotResolver) StartTime() *gql.Time   { return timePtr(l.state.StartTime) }
+func (l *lotResolver) EndTime() gql.Time      { return gql.Time{Time: l.state.EndTime} }
+func (l *lotResolver) MaxEndTime() *gql.Time  { return timePtr(l.state.MaxEndTime) }
+func (l *lotResolver) ClosingMode() string    { return l.state.ClosingMode }
+func (l *lotResolver) PausedAt() *gql.Time    { return timePtr(l.state.PausedAt) }
+func (l *lotResolver) Seq() int32             { return int32(l.state.Seq) }
+func (l *lotResolver) LastBidTime() *gql.Time { return timePtr(l.state.LastBidTime) }
+
+func (l *lotResolver) LastBidAmount() *float64 {
+	if l.state.LastBidTime == nil {
+		return nil
+	}
+	return &l.state.LastBidAmount
+}
+
+func (l *lotResolver) LastBidPaddle() *int32 {
+	if l.state.LastBidTime == nil {
+		return nil
+	}
+	paddle := int32(l.state.LastBidPaddle)
+	return &paddle
+}
+
+func (l *lotResolver) LastBidUserID() *gql.ID {
+	return idPtr(l.state.LastBidUserID)
+}
+
+func (l *lotResolver) Connections() *connectionsResolver {
+	return &connectionsResolver{counts: l.state.Connections}
+}
+
+func (l *lotResolver) Categories() []*categoryResolver {
+	resolvers := make([]*categoryResolver, 0, len(l.state.Categories))
+	for _, cat := range l.state.Categories {
+		resolvers = append(resolvers, &categoryResolver{category: cat})
+	}
+	return resolvers
+}
+
+func (l *lotResolver) Media() []*mediaResolver {
+	resolvers := make([]*mediaResolver, 0, len(l.state.Media))
+	for _, m := range l.state.Media {
+		resolvers = append(resolvers, &mediaResolver{media: m})
+	}
+	return resolvers
+}
+
+func (l *lotResolver) Bids(ctx context.Context, args pageArgs) ([]*bidResolver, error) {
+	return l.root.lotBidsPage(ctx, l.state.LotID, args)
+}
+
+type connectionsResolver struct {
+	counts application.ConnectionCountsDTO
+}
+
+func (c *connectionsResolver) Spectators() int32 { return int32(c.counts.Spectators) }
+func (c *connectionsResolver) Bidders() int32    { return int32(c.counts.Bidders) }
+func (c *connectionsResolver) Total() int32      { return int32(c.counts.Total) }
+
+type categoryResolver struct {
+	category application.CategoryDTO
+}
+
+func (c *categoryResolver) ID() gql.ID   { return gql.ID(c.category.ID.String()) }
+func (c *categoryResolver) Name() string { return c.category.Name }
+func (c *categoryResolver) Slug() string { return c.category.Slug }
+
+type mediaResolver struct {
+	media application.LotMediaDTO
+}
+
+func (m *mediaResolver) ID() gql.ID   { return gql.ID(m.media.ID.String()) }
+func (m *mediaResolver) Kind() string { return m.media.Kind }
+func (m *mediaResolver) URL() string  { return m.media.URL }
+
+// bidResolver resolves the Bid type, identified tells if the caller may see the bidder identity
+type bidResolver struct {
+	bid        application.LotBidDTO
+	identified bool
+}
+
+func (b *bidResolver) ID() gql.ID          { return gql.ID(b.bid.BidID.String()) }
+func (b *bidResolver) LotID() gql.ID       { return gql.ID(b.bid.LotID.String()) }
+func (b *bidResolver) Paddle() int32       { return int32(b.bid.Paddle) }
+func (b *bidResolver) Amount() float64     { return b.bid.Amount }
+func (b *bidResolver) Timestamp() gql.Time { return gql.Time{Time: b.bid.Timestamp} }
+
+func (b *bidResolver) UserID() *gql.ID {
+	if !b.identified {
+		return nil
+	}
+	return idPtr(b.bid.UserID)
+}
+
+// resultResolver resolves the Result type from the full state of a finished lot, its last bid won
+type resultResolver struct {
+	lot *lotResolver
+}
+
+func (r *resultResolver) Lot() *lotResolver    { return r.lot }
+func (r *resultResolver) FinalPrice() float64  { return r.lot.state.CurrentPrice }
+func (r *resultResolver) Currency() string     { return r.lot.state.Currency }
+func (r *resultResolver) EndTime() gql.Time    { return gql.Time{Time: r.lot.state.EndTime} }
+func (r *resultResolver) WinnerPaddle() *int32 { return r.lot.LastBidPaddle() }
+
+type userResolver struct {
+	root *Resolver
+	user *userdomain.User
+}
+
+func (u *userResolver) ID() gql.ID       { return gql.ID(u.user.ID.String()) }
+func (u *userResolver) Username() string { return u.user.Username }
+
+func (u *userResolver) Bids(ctx context.Context, args pageArgs) ([]*userBidResolver, error) {
+	limit, offset := args.bounds()
+	bids, err := u.root.userBids.Bids(ctx, application.UserBidsPageDTO{UserID: u.user.ID, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	resolvers := make([]*userBidResolver, 0, len(bids))
+	for _, bid := range bids {
+		resolvers = append(resolvers, &userBidResolver{bid: bid})
+	}
+	return resolvers, nil
+}
+
+func (u *userResolver) Lots(ctx context.Context, args struct {
+	State  *string
+	Limit  *int32
+	Offset *int32
+}) ([]*userLotResolver, error) {
+	limit, offset := pageArgs{args.Limit, args.Offset}.bounds()
+	lots, err := u.root.userBids.Lots(ctx, application.UserBidsPageDTO{
+		UserID: u.user.ID,
+		State:  deref(args.State),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	resolvers := make([]*userLotResolver, 0, len(lots))
+	for _, lot := range lots {
+		resolvers = append(resolvers, &userLotResolver{root: u.root, lot: lot})
+	}
+	return resolvers, nil
+}
+
+type userBidResolver struct {
+	bid application.UserBidDTO
+}
+
+func (b *userBidResolver) ID() gql.ID          { return gql.ID(b.bid.BidID.String()) }
+func (b *userBidResolver) LotID() gql.ID       { return gql.ID(b.bid.LotID.String()) }
+func (b *userBidResolver) Amount() float64     { return b.bid.Amount }
+func (b *userBidResolver) Timestamp() gql.Time { return gql.Time{Time: b.bid.Timestamp} }
+func (b *userBidResolver) VoidedAt() *gql.Time { return timePtr(b.bid.VoidedAt) }
+
+// userLotResolver resolves the UserLot type, the lot is loaded only when selected
+type userLotResolver struct {
+	root *Resolver
+	lot  application.UserLotDTO
+}
+
+func (u *userLotResolver) HighestBid() float64 { return u.lot.HighestBid }
+func (u *userLotResolver) BidCount() int32     { return int32(u.lot.BidCount) }
+func (u *userLotResolver) LastBidAt() gql.Time { return gql.Time{Time: u.lot.LastBidAt} }
+func (u *userLotResolver) Leading() bool       { return u.lot.Leading }
+
+func (u *userLotResolver) Lot(ctx context.Context) (*lotResolver, error) {
+	state, err := u.root.auctionService.GetLotState(ctx, u.lot.LotID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return u.root.newLot(ctx, state), nil
+}
+
+func timePtr(t *time.Time) *gql.Time {
+	if t == nil {
+		return nil
+	}
+	return &gql.Time{Time: *t}
+}
+
+func idPtr(id uuid.UUID) *gql.ID {
+	if id == uuid.Nil {
+		return nil
+	}
+	gqlID := gql.ID(id.String())
+	return &gqlID
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata is the metadata key carrying the correlation ID of a call, a client value is kept
+// and it's always returned in the response header
+const requestIDMetadata = "x-request-id"
+
+// maxRequestIDLength bounds the accepted client request IDs
+const maxRequestIDLength = 128
+
+// orgIDMetadata is the metadata key scoping a call to the lots of an organization, calls without it
+// see the lots of every organization
+const orgIDMetadata = "x-org-id"
+
+// unaryCorrelation stores the call correlation ID and organization in its context
+func unaryCorrelation(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := withCallOrgID(withCallCorrelationID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamCorrelation stores the stream correlation ID and organization in its context
+func streamCorrelation(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := withCallOrgID(withCallCorrelationID(ss.Context()))
+	if err != nil {
+		return err
+	}
+	return handler(srv, &correlatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// correlatedStream overrides the context of a server stream
+type correlatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *correlatedStream) Context() context.Context { return s.ctx }
+
+func withCallCorrelationID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadata); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = logger.NewCorrelationID()
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id)); err != nil {
+		logger.GetLogger().Warn("failed to set gRPC request ID header")
+	}
+	return logger.WithCorrelationID(ctx, id)
+}
+
+// withCallOrgID scopes ctx to the organization of the call metadata, if any
+func withCallOrgID(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	values := md.Get(orgIDMetadata)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	orgID, err := uuid.Parse(values[0])
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s metadata", orgIDMetadata)
+	}
+	return tenant.WithOrgID(ctx, orgID), nil
+}
@@ -0,0 +1,330 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	auctionpb "github.com/cristianortiz/auctionEngine/pkg/auctionpb/v1"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var log = logger.GetLogger()
+
+// AuctionGRPCServer implements auctionpb.AuctionServiceServer on top of the application layer,
+// it's meant for service-to-service integration (payments, inventory), not for browsers
+type AuctionGRPCServer struct {
+	auctionpb.UnimplementedAuctionServiceServer
+	auctionService application.AuctionService
+	// apiKeys authenticates the x-api-key metadata of the calls, see WithAPIKeys
+	apiKeys       func(ctx context.Context, key string) (*auth.Claims, error)
+	requireAPIKey bool
+}
+
+// NewAuctionGRPCServer creates a new instance of AuctionGRPCServer
+func NewAuctionGRPCServer(auctionService application.AuctionService) *AuctionGRPCServer {
+	return &AuctionGRPCServer{auctionService: auctionService}
+}
+
+// Serve registers the service in a new grpc.Server and serves on addr until ctx is done
+func (s *AuctionGRPCServer) Serve(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryCorrelation, s.unaryAPIKey),
+		grpc.ChainStreamInterceptor(streamCorrelation, s.streamAPIKey),
+	)
+	auctionpb.RegisterAuctionServiceServer(srv, s)
+
+	go func() {
+		<-ctx.Done()
+		log.Info("Shutting down gRPC server...")
+		srv.GracefulStop()
+	}()
+
+	log.Info("gRPC server started", zap.String("addr", addr))
+	return srv.Serve(lis)
+}
+
+// PlaceBid implements auctionpb.AuctionServiceServer, calls with an API key bid as the user of the
+// key when user_id is empty, and only the keys allowed to manage lots may bid for other users
+func (s *AuctionGRPCServer) PlaceBid(ctx context.Context, req *auctionpb.PlaceBidRequest) (*auctionpb.PlaceBidResponse, error) {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	claims := callClaims(ctx)
+	var userID uuid.UUID
+	if claims != nil && req.GetUserId() == "" {
+		userID = claims.UserID
+	} else if userID, err = uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	if claims != nil && userID != claims.UserID && !claims.Can(auth.PermManageLots) {
+		return nil, status.Error(codes.PermissionDenied, "API key can't bid for other users")
+	}
+
+	bid, err := s.auctionService.PlaceBid(ctx, application.PlaceBidDTO{
+		LotID:    lotID,
+		UserID:   userID,
+		Amount:   req.GetAmount(),
+		Currency: req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &auctionpb.PlaceBidResponse{Bid: &auctionpb.Bid{
+		Id:        bid.ID.String(),
+		LotId:     bid.LotID.String(),
+		UserId:    bid.UserID.String(),
+		Amount:    bid.Amount,
+		Timestamp: timestamppb.New(bid.Timestamp),
+	}}, nil
+}
+
+// GetLotState implements auctionpb.AuctionServiceServer
+func (s *AuctionGRPCServer) GetLotState(ctx context.Context, req *auctionpb.GetLotStateRequest) (*auctionpb.LotState, error) {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	state, err := s.auctionService.GetLotState(ctx, lotID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoLotState(state), nil
+}
+
+// ListActiveLots implements auctionpb.AuctionServiceServer
+func (s *AuctionGRPCServer) ListActiveLots(ctx context.Context, _ *auctionpb.ListActiveLotsRequest) (*auctionpb.ListActiveLotsResponse, error) {
+	lots, err := s.auctionService.ListActiveLots(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &auctionpb.ListActiveLotsResponse{Lots: make([]*auctionpb.LotState, 0, len(lots))}
+	for _, lot := range lots {
+		resp.Lots = append(resp.Lots, toProtoLotState(lot))
+	}
+	return resp, nil
+}
+
+// CreateLot implements auctionpb.AuctionServiceServer
+func (s *AuctionGRPCServer) CreateLot(ctx context.Context, req *auctionpb.CreateLotRequest) (*auctionpb.LotState, error) {
+	if req.GetEndTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, "end_time is required")
+	}
+	cmd := application.CreateLotDTO{
+		Title:          req.GetTitle(),
+		Description:    req.GetDescription(),
+		InitialPrice:   req.GetInitialPrice(),
+		Currency:       req.GetCurrency(),
+		Type:           req.GetType(),
+		EndTime:        req.GetEndTime().AsTime(),
+		TimeExtension:  time.Duration(req.GetTimeExtensionSeconds()) * time.Second,
+		ClosingMode:    req.GetClosingMode(),
+		MaxExtension:   time.Duration(req.GetMaxExtensionSeconds()) * time.Second,
+		PriceThreshold: req.GetExtensionPriceThreshold(),
+		Quantity:       int(req.GetQuantity()),
+		Pricing:        req.GetPricing(),
+	}
+	if req.GetStartTime() != nil {
+		startTime := req.GetStartTime().AsTime()
+		cmd.StartTime = &startTime
+	}
+	state, err := s.auctionService.CreateLot(ctx, cmd)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoLotState(state), nil
+}
+
+// UpdateLot implements auctionpb.AuctionServiceServer, only the fields set in req are changed
+func (s *AuctionGRPCServer) UpdateLot(ctx context.Context, req *auctionpb.UpdateLotRequest) (*auctionpb.LotState, error) {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	cmd := application.UpdateLotDTO{
+		LotID:        lotID,
+		Title:        req.Title,
+		Description:  req.Description,
+		InitialPrice: req.InitialPrice,
+	}
+	if req.GetUpdatedBy() != "" {
+		if cmd.UpdatedBy, err = uuid.Parse(req.GetUpdatedBy()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid updated_by")
+		}
+	}
+	if req.GetEndTime() != nil {
+		endTime := req.GetEndTime().AsTime()
+		cmd.EndTime = &endTime
+	}
+	state, err := s.auctionService.UpdateLot(ctx, cmd)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoLotState(state), nil
+}
+
+// StartLot implements auctionpb.AuctionServiceServer
+func (s *AuctionGRPCServer) StartLot(ctx context.Context, req *auctionpb.StartLotRequest) (*auctionpb.LotState, error) {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	state, err := s.auctionService.StartLot(ctx, lotID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoLotState(state), nil
+}
+
+// CancelLot implements auctionpb.AuctionServiceServer
+func (s *AuctionGRPCServer) CancelLot(ctx context.Context, req *auctionpb.CancelLotRequest) (*auctionpb.LotState, error) {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	state, err := s.auctionService.CancelLot(ctx, lotID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoLotState(state), nil
+}
+
+// WatchLot implements auctionpb.AuctionServiceServer, sends the current state and then every update
+func (s *AuctionGRPCServer) WatchLot(req *auctionpb.WatchLotRequest, stream grpc.ServerStreamingServer[auctionpb.LotState]) error {
+	lotID, err := uuid.Parse(req.GetLotId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid lot_id")
+	}
+	ctx := stream.Context()
+
+	// subscribe before reading the current state so no update is lost in between
+	updates, cancel := s.auctionService.WatchLot(lotID)
+	defer cancel()
+
+	state, err := s.auctionService.GetLotState(ctx, lotID)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	if err := stream.Send(toProtoLotState(state)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toProtoLotState(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toStatus maps domain errors to gRPC status codes
+func toStatus(ctx context.Context, err error) error {
+	switch {
+	case domain.AmountErrorCode(err) != "":
+		return codedStatus(codes.InvalidArgument, domain.AmountErrorCode(err), err)
+	case errors.Is(err, domain.ErrLotNotFound),
+		errors.Is(err, domain.ErrBidNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, application.ErrInvalidLot):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrLotNotActive),
+		errors.Is(err, domain.ErrLotNotOpenYet),
+		errors.Is(err, domain.ErrLotPaused),
+		errors.Is(err, domain.ErrLotNotPaused),
+		errors.Is(err, domain.ErrBiddingClosed),
+		errors.Is(err, domain.ErrBidAmountTooLow),
+		errors.Is(err, domain.ErrBidAmountTooHigh),
+		errors.Is(err, domain.ErrBidIncrementTooSmall),
+		errors.Is(err, domain.ErrBiddingLimitExceeded),
+		errors.Is(err, domain.ErrLotAlreadyStartedOrFinished),
+		errors.Is(err, domain.ErrLotAlreadyFinishedOrCancelled),
+		errors.Is(err, domain.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	// the bidder rejections carry the same code the WS clients get, e.g. TERMS_REQUIRED or GEO_RESTRICTED
+	case errors.Is(err, application.ErrBidNotAllowed):
+		return codedStatus(codes.PermissionDenied, application.BidErrorCode(err), err)
+	case errors.Is(err, application.ErrQuickBidLimit):
+		return codedStatus(codes.FailedPrecondition, application.BidErrorCode(err), err)
+	case errors.Is(err, domain.ErrConcurrentLotUpdate):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, application.ErrMaintenanceMode):
+		return codedStatus(codes.Unavailable, application.ErrorCodeMaintenance, err)
+	case errors.Is(err, application.ErrLotBusy):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		logger.FromContext(ctx).Error("gRPC request failed", zap.Error(err))
+		return status.Errorf(codes.Internal, "internal error, request id %s", logger.CorrelationID(ctx))
+	}
+}
+
+func toProtoLotState(dto *application.LotStateDTO) *auctionpb.LotState {
+	state := &auctionpb.LotState{
+		LotId:            dto.LotID.String(),
+		Title:            dto.Title,
+		Description:      dto.Description,
+		InitialPrice:     dto.InitialPrice,
+		CurrentPrice:     dto.CurrentPrice,
+		Currency:         dto.Currency,
+		Type:             dto.Type,
+		ClosingMode:      dto.ClosingMode,
+		IndicativePrices: dto.IndicativePrices,
+		EndTime:          timestamppb.New(dto.EndTime),
+		State:            dto.State,
+		Seq:              dto.Seq,
+		LastBidAmount:    dto.LastBidAmount,
+		LastBidPaddle:    int32(dto.LastBidPaddle),
+		Quantity:         1,
+	}
+	if dto.LastBidUserID != uuid.Nil {
+		state.LastBidUserId = dto.LastBidUserID.String()
+	}
+	if dto.LastBidTime != nil {
+		state.LastBidTime = timestamppb.New(*dto.LastBidTime)
+	}
+	if dto.StartTime != nil {
+		state.StartTime = timestamppb.New(*dto.StartTime)
+	}
+	if dto.MaxEndTime != nil {
+		state.MaxEndTime = timestamppb.New(*dto.MaxEndTime)
+	}
+	if dto.Units != nil {
+		state.Quantity = int32(dto.Units.Quantity)
+		state.UnitsAvailable = int32(dto.Units.Available)
+	}
+	return state
+}
+
+// codedStatus is the status of a rejected bid the clients can handle, its ErrorInfo detail carries the
+// error code (e.g. amount_precision, MAINTENANCE)
+func codedStatus(code codes.Code, reason string, err error) error {
+	st := status.New(code, err.Error())
+	withInfo, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: "auction"})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
@@ -0,0 +1,70 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// respondTimeout bounds the bid placed for an outbid absentee bid
+const respondTimeout = 30 * time.Second
+
+// AbsenteeBidResponder bids again for the users with an entered absentee bid each time they are outbid, it
+// implements domain.EventPublisher so it can be plugged next to the broker publisher
+type AbsenteeBidResponder struct {
+	ctx        context.Context
+	absenteeUC *application.AbsenteeBidUseCase
+	placer     application.AuctionService
+}
+
+// NewAbsenteeBidResponder creates a new instance of AbsenteeBidResponder, ctx bounds the background bids
+func NewAbsenteeBidResponder(ctx context.Context, absenteeUC *application.AbsenteeBidUseCase) *AbsenteeBidResponder {
+	return &AbsenteeBidResponder{ctx: ctx, absenteeUC: absenteeUC}
+}
+
+// WithPlacer returns the responder placing its bids through service, which publishes to the responder
+// itself so it's set once both exist, before any event is published. without one no bid is placed
+func (r *AbsenteeBidResponder) WithPlacer(service application.AuctionService) *AbsenteeBidResponder {
+	r.placer = service
+	return r
+}
+
+// Publish implements domain.EventPublisher, the bids are placed in background because the outbid events
+// are published from the command of the lot the bids queue behind
+func (r *AbsenteeBidResponder) Publish(ctx context.Context, events ...domain.Event) error {
+	if r.placer == nil {
+		return nil
+	}
+	// the background work keeps the correlation ID of the request that produced the events
+	correlationID := logger.CorrelationID(ctx)
+	for _, event := range events {
+		if event.Type != domain.EventUserOutbid {
+			continue
+		}
+		payload, ok := event.Payload.(domain.UserOutbidPayload)
+		if !ok {
+			logger.FromContext(ctx).Error("AbsenteeBidResponder: unexpected payload", zap.String("type", string(event.Type)))
+			continue
+		}
+		lotID := event.LotID
+		go func() {
+			ctx, cancel := context.WithTimeout(logger.WithCorrelationID(r.ctx, correlationID), respondTimeout)
+			defer cancel()
+			if err := r.absenteeUC.Respond(ctx, lotID, payload.UserID, r.placer); err != nil {
+				logger.FromContext(ctx).Warn("AbsenteeBidResponder: failed to bid for outbid absentee bid",
+					zap.String("lotID", lotID.String()),
+					zap.String("userID", payload.UserID.String()),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+	return nil
+}
+
+// Close implements domain.EventPublisher
+func (r *AbsenteeBidResponder) Close() error { return nil }
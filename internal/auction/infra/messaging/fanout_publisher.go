@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+)
+
+// FanoutPublisher implements domain.EventPublisher delivering every event to all its publishers,
+// e.g. the external broker and in-process listeners of other modules
+type FanoutPublisher struct {
+	publishers []domain.EventPublisher
+}
+
+// NewFanoutPublisher creates a new instance of FanoutPublisher
+func NewFanoutPublisher(publishers ...domain.EventPublisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+// Publish implements domain.EventPublisher, a failing publisher doesn't prevent delivery to the others
+func (p *FanoutPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	var errs []error
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, events...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements domain.EventPublisher
+func (p *FanoutPublisher) Close() error {
+	var errs []error
+	for _, pub := range p.publishers {
+		if err := pub.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher implements domain.EventPublisher writing each event to topic <prefix>.<event type>,
+// keyed by lot ID so the events of a lot keep their order inside a partition
+type KafkaPublisher struct {
+	writer *kafka.Writer
+	prefix string
+}
+
+// NewKafkaPublisher creates a new instance of KafkaPublisher for the given brokers
+func NewKafkaPublisher(brokers []string, prefix string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+		prefix: prefix,
+	}
+}
+
+// Publish implements domain.EventPublisher
+func (p *KafkaPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("kafka publisher: failed to marshal event %s: %w", event.Type, err)
+		}
+		msgs = append(msgs, kafka.Message{
+			Topic: subject(p.prefix, event.Type),
+			Key:   []byte(event.LotID.String()),
+			Value: data,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(event.ID.String())},
+				{Key: "event_type", Value: []byte(event.Type)},
+			},
+		})
+	}
+	if err := p.writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("kafka publisher: failed to write events: %w", err)
+	}
+	return nil
+}
+
+// Close implements domain.EventPublisher
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,47 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher implements domain.EventPublisher publishing each event on subject <prefix>.<event type>
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSPublisher connects to the NATS server at url
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("auctionEngine"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("nats publisher: failed to connect to %s: %w", url, err)
+	}
+	return &NATSPublisher{conn: conn, prefix: prefix}, nil
+}
+
+// Publish implements domain.EventPublisher
+func (p *NATSPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("nats publisher: failed to marshal event %s: %w", event.Type, err)
+		}
+		msg := nats.NewMsg(subject(p.prefix, event.Type))
+		msg.Header.Set(nats.MsgIdHdr, event.ID.String()) // enables JetStream de-duplication
+		msg.Data = data
+		if err := p.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("nats publisher: failed to publish event %s: %w", event.Type, err)
+		}
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+// Close implements domain.EventPublisher
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
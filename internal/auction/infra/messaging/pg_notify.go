@@ -0,0 +1,100 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// LotChangesChannel is the Postgres channel of the lot change notifications
+const LotChangesChannel = "lot_changes"
+
+// listenRetryDelay is the wait before listening again after the listening connection failed
+const listenRetryDelay = time.Second
+
+// lotChangeNotification is the payload of a notification, origin tells the instances to skip their own
+type lotChangeNotification struct {
+	LotID  uuid.UUID `json:"lot_id"`
+	Seq    int64     `json:"seq"`
+	Origin string    `json:"origin"`
+}
+
+// PGNotifyBridge propagates the lot changes between the instances sharing a database with LISTEN/NOTIFY,
+// a lighter alternative to a broker. notifications are not persisted: the ones sent while an instance
+// is reconnecting are lost, its caches catch up when they expire and its clients with the next change
+type PGNotifyBridge struct {
+	pool   *pgxpool.Pool
+	origin string
+}
+
+// NewPGNotifyBridge creates a new instance of PGNotifyBridge with a random identity for this instance
+func NewPGNotifyBridge(pool *pgxpool.Pool) *PGNotifyBridge {
+	return &PGNotifyBridge{pool: pool, origin: uuid.NewString()}
+}
+
+// NotifyLotChanged implements domain.LotChangeNotifier
+func (b *PGNotifyBridge) NotifyLotChanged(ctx context.Context, lotID uuid.UUID, seq int64) error {
+	payload, err := json.Marshal(lotChangeNotification{LotID: lotID, Seq: seq, Origin: b.origin})
+	if err != nil {
+		return fmt.Errorf("pg notify bridge: failed to marshal notification: %w", err)
+	}
+	if _, err := b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, LotChangesChannel, string(payload)); err != nil {
+		return fmt.Errorf("pg notify bridge: failed to notify lot %s: %w", lotID, err)
+	}
+	return nil
+}
+
+// Listen calls handle with the lot changes notified by the other instances until ctx is done. it holds a
+// connection of the pool and takes another one when it fails
+func (b *PGNotifyBridge) Listen(ctx context.Context, handle func(ctx context.Context, lotID uuid.UUID, seq int64)) {
+	log.Info("PGNotifyBridge listening for lot changes", zap.String("channel", LotChangesChannel))
+	for {
+		err := b.listen(ctx, handle)
+		if ctx.Err() != nil {
+			log.Info("PGNotifyBridge stopped listening")
+			return
+		}
+		log.Error("PGNotifyBridge: listening connection failed, retrying", zap.Error(err))
+		select {
+		case <-time.After(listenRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *PGNotifyBridge) listen(ctx context.Context, handle func(ctx context.Context, lotID uuid.UUID, seq int64)) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// the session keeps listening, it's closed instead of going back to the pool
+	defer func() {
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{LotChangesChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var change lotChangeNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			log.Warn("PGNotifyBridge: invalid notification payload", zap.String("payload", notification.Payload), zap.Error(err))
+			continue
+		}
+		if change.Origin == b.origin {
+			continue
+		}
+		handle(ctx, change.LotID, change.Seq)
+	}
+}
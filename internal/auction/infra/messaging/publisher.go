@@ -0,0 +1,74 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// Broker names accepted by NewEventPublisher
+const (
+	BrokerNATS  = "nats"
+	BrokerKafka = "kafka"
+	BrokerNone  = ""
+)
+
+// PublisherConfig selects and configures the event broker
+type PublisherConfig struct {
+	Broker       string   // nats, kafka or empty to only log events
+	NATSURL      string   // e.g. nats://localhost:4222
+	KafkaBrokers []string // e.g. localhost:9092
+	// SubjectPrefix is prepended to the event type to build the NATS subject / Kafka topic, e.g. auction.bid.placed
+	SubjectPrefix string
+}
+
+// NewEventPublisher builds the domain.EventPublisher for the configured broker
+func NewEventPublisher(cfg PublisherConfig) (domain.EventPublisher, error) {
+	switch cfg.Broker {
+	case BrokerNATS:
+		return NewNATSPublisher(cfg.NATSURL, cfg.SubjectPrefix)
+	case BrokerKafka:
+		return NewKafkaPublisher(cfg.KafkaBrokers, cfg.SubjectPrefix), nil
+	case BrokerNone:
+		return NewLogPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown event broker %q", cfg.Broker)
+	}
+}
+
+// subject builds the routing key of an event
+func subject(prefix string, eventType domain.EventType) string {
+	if prefix == "" {
+		return string(eventType)
+	}
+	return prefix + "." + string(eventType)
+}
+
+// LogPublisher only logs the events, used when no broker is configured
+type LogPublisher struct{}
+
+// NewLogPublisher creates a new instance of LogPublisher
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+// Publish implements domain.EventPublisher
+func (p *LogPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("log publisher: failed to marshal event %s: %w", event.Type, err)
+		}
+		log.Debug("Domain event", zap.String("type", string(event.Type)), zap.ByteString("event", data))
+	}
+	return nil
+}
+
+// Close implements domain.EventPublisher
+func (p *LogPublisher) Close() error { return nil }
@@ -0,0 +1,294 @@
+//go:build mongo
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// bidDocument is the stored form of a bid, IDs are stored as strings
+type bidDocument struct {
+	ID         string     `bson:"_id"`
+	LotID      string     `bson:"lot_id"`
+	UserID     string     `bson:"user_id"`
+	Amount     float64    `bson:"amount"`
+	Timestamp  time.Time  `bson:"timestamp"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	ClientIP   string     `bson:"client_ip"`
+	ReceivedAt *time.Time `bson:"received_at,omitempty"`
+	VoidedAt   *time.Time `bson:"voided_at,omitempty"`
+	VoidedBy   *string    `bson:"voided_by,omitempty"`
+	VoidReason string     `bson:"void_reason,omitempty"`
+}
+
+func newBidDocument(bid *domain.Bid) bidDocument {
+	return bidDocument{
+		ID:         bid.ID.String(),
+		LotID:      bid.LotID.String(),
+		UserID:     bid.UserID.String(),
+		Amount:     bid.Amount,
+		Timestamp:  bid.Timestamp,
+		CreatedAt:  bid.CreatedAt,
+		ClientIP:   bid.ClientIP,
+		ReceivedAt: bid.ReceivedAt,
+	}
+}
+
+func (d *bidDocument) toDomain() (*domain.Bid, error) {
+	bid := &domain.Bid{
+		Amount:     d.Amount,
+		Timestamp:  d.Timestamp,
+		CreatedAt:  d.CreatedAt,
+		ClientIP:   d.ClientIP,
+		ReceivedAt: d.ReceivedAt,
+		VoidedAt:   d.VoidedAt,
+		VoidReason: d.VoidReason,
+	}
+	var err error
+	if bid.ID, err = uuid.Parse(d.ID); err != nil {
+		return nil, fmt.Errorf("invalid bid id %q: %w", d.ID, err)
+	}
+	if bid.LotID, err = uuid.Parse(d.LotID); err != nil {
+		return nil, fmt.Errorf("invalid lot id %q: %w", d.LotID, err)
+	}
+	if bid.UserID, err = uuid.Parse(d.UserID); err != nil {
+		return nil, fmt.Errorf("invalid user id %q: %w", d.UserID, err)
+	}
+	if d.VoidedBy != nil {
+		voidedBy, err := uuid.Parse(*d.VoidedBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid voided_by %q: %w", *d.VoidedBy, err)
+		}
+		bid.VoidedBy = &voidedBy
+	}
+	return bid, nil
+}
+
+// notVoided matches the bids that count for the lot
+var notVoided = bson.M{"voided_at": bson.M{"$exists": false}}
+
+// BidRepository implements domain.BidRepository
+type BidRepository struct {
+	bids *mongo.Collection
+}
+
+// NewBidRepository creates a new instance of BidRepository
+func NewBidRepository(db *mongo.Database) *BidRepository {
+	return &BidRepository{bids: db.Collection(bidsCollection)}
+}
+
+// Save implements domain.BidRepository
+func (r *BidRepository) Save(ctx context.Context, tx domain.Tx, bid *domain.Bid) error {
+	ctx, err := txContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+	_, err = r.bids.InsertOne(ctx, newBidDocument(bid))
+	return err
+}
+
+// SaveBatch implements domain.BidRepository with a single ordered InsertMany
+func (r *BidRepository) SaveBatch(ctx context.Context, tx domain.Tx, bids []*domain.Bid) error {
+	if len(bids) == 0 {
+		return nil
+	}
+	ctx, err := txContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+	docs := make([]any, len(bids))
+	for i, bid := range bids {
+		docs[i] = newBidDocument(bid)
+	}
+	_, err = r.bids.InsertMany(ctx, docs)
+	return err
+}
+
+// GetByID implements domain.BidRepository
+func (r *BidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	var doc bidDocument
+	if err := r.bids.FindOne(ctx, bson.M{"_id": id.String()}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrBidNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain()
+}
+
+// Void implements domain.BidRepository
+func (r *BidRepository) Void(ctx context.Context, tx domain.Tx, bid *domain.Bid) error {
+	ctx, err := txContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+	set := bson.M{"voided_at": bid.VoidedAt, "void_reason": bid.VoidReason}
+	if bid.VoidedBy != nil {
+		set["voided_by"] = bid.VoidedBy.String()
+	}
+	_, err = r.bids.UpdateByID(ctx, bid.ID.String(), bson.M{"$set": set})
+	return err
+}
+
+// GetBidsByLotID implements domain.BidRepository, oldest first
+func (r *BidRepository) GetBidsByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.Bid, error) {
+	filter := bson.M{"lot_id": lotID.String(), "voided_at": notVoided["voided_at"]}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+}
+
+// GetLatestBidByLotID implements domain.BidRepository, nil if the lot has no bids
+func (r *BidRepository) GetLatestBidByLotID(ctx context.Context, lotID uuid.UUID) (*domain.Bid, error) {
+	filter := bson.M{"lot_id": lotID.String(), "voided_at": notVoided["voided_at"]}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	var doc bidDocument
+	if err := r.bids.FindOne(ctx, filter, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc.toDomain()
+}
+
+// GetBidderIDsByLotID implements domain.BidRepository
+func (r *BidRepository) GetBidderIDsByLotID(ctx context.Context, lotID uuid.UUID) ([]uuid.UUID, error) {
+	values, err := r.bids.Distinct(ctx, "user_id", bson.M{"lot_id": lotID.String(), "voided_at": notVoided["voided_at"]})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		raw, _ := v.(string)
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q: %w", raw, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// GetStandingBids implements domain.BidRepository, the best bid of each bidder is picked from the bids
+// of the lot sorted best first
+func (r *BidRepository) GetStandingBids(ctx context.Context, lotID uuid.UUID, limit int) ([]*domain.Bid, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}})
+	bids, err := r.find(ctx, bson.M{"lot_id": lotID.String(), "voided_at": notVoided["voided_at"]}, opts)
+	if err != nil {
+		return nil, err
+	}
+	standing := make([]*domain.Bid, 0, limit)
+	seen := make(map[uuid.UUID]bool)
+	for _, bid := range bids {
+		if len(standing) == limit {
+			break
+		}
+		if !seen[bid.UserID] {
+			seen[bid.UserID] = true
+			standing = append(standing, bid)
+		}
+	}
+	return standing, nil
+}
+
+// GetBidsByUserID implements domain.BidRepository, voided bids included as in postgres
+func (r *BidRepository) GetBidsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bid, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	return r.find(ctx, bson.M{"user_id": userID.String()}, opts)
+}
+
+// GetLotsByBidderID implements domain.BidRepository with an aggregation equivalent to the postgres query:
+// the user bids grouped by lot, joined with the lot and with the latest bid of the lot
+func (r *BidRepository) GetLotsByBidderID(ctx context.Context, userID uuid.UUID, state domain.AuctionLotState, limit, offset int) ([]*domain.UserLotBids, error) {
+	lotMatch := bson.M{}
+	if state != "" {
+		lotMatch["lot.state"] = string(state)
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID.String(), "voided_at": notVoided["voided_at"]}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$lot_id",
+			"highest_bid": bson.M{"$max": "$amount"},
+			"bid_count":   bson.M{"$sum": 1},
+			"last_bid_at": bson.M{"$max": "$timestamp"},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": lotsCollection, "localField": "_id", "foreignField": "_id", "as": "lot"}}},
+		{{Key: "$unwind", Value: "$lot"}},
+		{{Key: "$match", Value: scoped(ctx, "lot.org_id", lotMatch)}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": bidsCollection,
+			"let":  bson.M{"lot_id": "$_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$lot_id", "$$lot_id"}}, "voided_at": notVoided["voided_at"]}}},
+				{{Key: "$sort", Value: bson.M{"timestamp": -1}}},
+				{{Key: "$limit", Value: 1}},
+				{{Key: "$project", Value: bson.M{"user_id": 1}}},
+			},
+			"as": "latest",
+		}}},
+		{{Key: "$sort", Value: bson.M{"last_bid_at": -1}}},
+		{{Key: "$skip", Value: offset}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.bids.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		HighestBid float64       `bson:"highest_bid"`
+		BidCount   int           `bson:"bid_count"`
+		LastBidAt  time.Time     `bson:"last_bid_at"`
+		Lot        lotDocument   `bson:"lot"`
+		Latest     []bidDocument `bson:"latest"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*domain.UserLotBids, 0, len(rows))
+	for _, row := range rows {
+		lot, err := row.Lot.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &domain.UserLotBids{
+			Lot:        lot,
+			HighestBid: row.HighestBid,
+			BidCount:   row.BidCount,
+			LastBidAt:  row.LastBidAt,
+			Leading:    len(row.Latest) > 0 && row.Latest[0].UserID == userID.String(),
+		})
+	}
+	return summaries, nil
+}
+
+func (r *BidRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.Bid, error) {
+	cursor, err := r.bids.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []bidDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	bids := make([]*domain.Bid, 0, len(docs))
+	for i := range docs {
+		bid, err := docs[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, nil
+}
@@ -0,0 +1,48 @@
+//go:build mongo
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// collection names, matching the postgres tables
+const (
+	lotsCollection = "auction_lots"
+	bidsCollection = "bids"
+)
+
+// EnsureIndexes creates the indexes equivalent to the postgres ones used by the lot and bid queries,
+// it's idempotent and meant to run at startup
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	lotIndexes := []mongo.IndexModel{
+		// active lots and lots ending soon (idx_auction_lots_state_end_time)
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "end_time", Value: 1}}},
+		// lots in preview opening soon (idx_auction_lots_state_start_time)
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "start_time", Value: 1}}},
+		// full-text search over title and description (search_vector)
+		{Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none")},
+	}
+	if _, err := db.Collection(lotsCollection).Indexes().CreateMany(ctx, lotIndexes); err != nil {
+		return fmt.Errorf("failed to create %s indexes: %w", lotsCollection, err)
+	}
+
+	bidIndexes := []mongo.IndexModel{
+		// bids of a lot and the latest bid of a lot
+		{Keys: bson.D{{Key: "lot_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		// bid history of a user
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		// shared IP lookups of the fraud heuristics
+		{Keys: bson.D{{Key: "lot_id", Value: 1}, {Key: "client_ip", Value: 1}}},
+	}
+	if _, err := db.Collection(bidsCollection).Indexes().CreateMany(ctx, bidIndexes); err != nil {
+		return fmt.Errorf("failed to create %s indexes: %w", bidsCollection, err)
+	}
+	return nil
+}
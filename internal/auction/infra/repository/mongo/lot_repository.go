@@ -0,0 +1,272 @@
+//go:build mongo
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lotDocument is the stored form of a lot, IDs are stored as strings
+type lotDocument struct {
+	ID            string        `bson:"_id"`
+	OrgID         string        `bson:"org_id,omitempty"` // documents stored without it belong to the default organization
+	Title         string        `bson:"title"`
+	Description   string        `bson:"description"`
+	InitialPrice  float64       `bson:"initial_price"`
+	CurrentPrice  float64       `bson:"current_price"`
+	Currency      string        `bson:"currency"`
+	LotType       string        `bson:"lot_type"`
+	EndTime       time.Time     `bson:"end_time"`
+	StartTime     *time.Time    `bson:"start_time,omitempty"`
+	State         string        `bson:"state"`
+	LastBidTime   *time.Time    `bson:"last_bid_time,omitempty"`
+	TimeExtension time.Duration `bson:"time_extension"`
+	// closing policy, documents stored without it are soft closed
+	ClosingMode           string        `bson:"closing_mode,omitempty"`
+	ClosingMaxExtension   time.Duration `bson:"closing_max_extension"`
+	ClosingPriceThreshold float64       `bson:"closing_price_threshold"`
+	ScheduledEndTime      time.Time     `bson:"scheduled_end_time"`
+	PausedAt              *time.Time    `bson:"paused_at,omitempty"`
+	EventSeq              int64         `bson:"event_seq"`
+	Version               int64         `bson:"version"`
+	CreatedAt             time.Time     `bson:"created_at"`
+	UpdatedAt             time.Time     `bson:"updated_at"`
+	// units of the lot, documents stored without them are single items
+	Quantity    int    `bson:"quantity,omitempty"`
+	UnitPricing string `bson:"unit_pricing,omitempty"`
+}
+
+func (d *lotDocument) toDomain() (*domain.AuctionLot, error) {
+	id, err := uuid.Parse(d.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lot id %q: %w", d.ID, err)
+	}
+	orgID := tenant.DefaultOrgID
+	if d.OrgID != "" {
+		if orgID, err = uuid.Parse(d.OrgID); err != nil {
+			return nil, fmt.Errorf("invalid org id %q of lot %s: %w", d.OrgID, d.ID, err)
+		}
+	}
+	quantity, pricing := max(d.Quantity, 1), domain.UnitPricing(d.UnitPricing)
+	if pricing == "" {
+		pricing = domain.PricingPayAsBid
+	}
+	return &domain.AuctionLot{
+		ID:            id,
+		OrgID:         orgID,
+		Title:         d.Title,
+		Description:   d.Description,
+		InitialPrice:  d.InitialPrice,
+		CurrentPrice:  d.CurrentPrice,
+		Currency:      d.Currency,
+		Type:          domain.LotType(d.LotType),
+		EndTime:       d.EndTime,
+		StartTime:     d.StartTime,
+		State:         domain.AuctionLotState(d.State),
+		LastBidTime:   d.LastBidTime,
+		TimeExtension: d.TimeExtension,
+		Closing: domain.ClosingPolicy{
+			Mode:           domain.ClosingMode(d.ClosingMode),
+			MaxExtension:   d.ClosingMaxExtension,
+			PriceThreshold: d.ClosingPriceThreshold,
+		},
+		ScheduledEndTime: d.ScheduledEndTime,
+		PausedAt:         d.PausedAt,
+		Quantity:         quantity,
+		Pricing:          pricing,
+		Seq:              d.EventSeq,
+		Version:          d.Version,
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+	}, nil
+}
+
+// AuctionLotRepository implements domain.AuctionLotRepository and domain.LotSearchRepository
+type AuctionLotRepository struct {
+	lots *mongo.Collection
+}
+
+// NewAuctionLotRepository creates a new instance of AuctionLotRepository
+func NewAuctionLotRepository(db *mongo.Database) *AuctionLotRepository {
+	return &AuctionLotRepository{lots: db.Collection(lotsCollection)}
+}
+
+// Save inserts a new lot or updates a stored one with optimistic concurrency: the update only applies
+// if the stored version is still the one the lot was loaded with, otherwise it fails with
+// domain.ErrConcurrentLotUpdate. the version of lot is advanced on success
+func (r *AuctionLotRepository) Save(ctx context.Context, tx domain.Tx, lot *domain.AuctionLot) error {
+	ctx, err := txContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if lot.Version == 0 {
+		doc := lotDocument{
+			ID:                    lot.ID.String(),
+			OrgID:                 lot.OrgID.String(),
+			Title:                 lot.Title,
+			Description:           lot.Description,
+			InitialPrice:          lot.InitialPrice,
+			CurrentPrice:          lot.CurrentPrice,
+			Currency:              lot.Currency,
+			LotType:               string(lot.Type),
+			EndTime:               lot.EndTime,
+			StartTime:             lot.StartTime,
+			State:                 string(lot.State),
+			LastBidTime:           lot.LastBidTime,
+			TimeExtension:         lot.TimeExtension,
+			ClosingMode:           string(lot.Closing.Mode),
+			ClosingMaxExtension:   lot.Closing.MaxExtension,
+			ClosingPriceThreshold: lot.Closing.PriceThreshold,
+			ScheduledEndTime:      lot.ScheduledEndTime,
+			PausedAt:              lot.PausedAt,
+			Quantity:              lot.Quantity,
+			UnitPricing:           string(lot.Pricing),
+			Version:               1,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+		}
+		if _, err := r.lots.InsertOne(ctx, doc); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return domain.ErrConcurrentLotUpdate
+			}
+			return err
+		}
+		lot.Version, lot.CreatedAt, lot.UpdatedAt = 1, now, now
+		return nil
+	}
+
+	// event_seq is owned by the event store, like in postgres it's not written here
+	update := bson.M{
+		"$set": bson.M{
+			"title":                   lot.Title,
+			"description":             lot.Description,
+			"initial_price":           lot.InitialPrice,
+			"current_price":           lot.CurrentPrice,
+			"currency":                lot.Currency,
+			"lot_type":                string(lot.Type),
+			"end_time":                lot.EndTime,
+			"start_time":              lot.StartTime,
+			"state":                   string(lot.State),
+			"last_bid_time":           lot.LastBidTime,
+			"time_extension":          lot.TimeExtension,
+			"closing_mode":            string(lot.Closing.Mode),
+			"closing_max_extension":   lot.Closing.MaxExtension,
+			"closing_price_threshold": lot.Closing.PriceThreshold,
+			"scheduled_end_time":      lot.ScheduledEndTime,
+			"paused_at":               lot.PausedAt,
+			"updated_at":              now,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	res, err := r.lots.UpdateOne(ctx, bson.M{"_id": lot.ID.String(), "version": lot.Version}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrConcurrentLotUpdate
+	}
+	lot.Version++
+	lot.UpdatedAt = now
+	return nil
+}
+
+// GetByID implements domain.AuctionLotRepository
+func (r *AuctionLotRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AuctionLot, error) {
+	var doc lotDocument
+	if err := r.lots.FindOne(ctx, scoped(ctx, "org_id", bson.M{"_id": id.String()})).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrLotNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain()
+}
+
+// GetActiveLots implements domain.AuctionLotRepository
+func (r *AuctionLotRepository) GetActiveLots(ctx context.Context) ([]*domain.AuctionLot, error) {
+	return r.find(ctx, bson.M{"state": string(domain.StateActive)}, nil)
+}
+
+// GetLotsEndingBy implements domain.AuctionLotRepository
+func (r *AuctionLotRepository) GetLotsEndingBy(ctx context.Context, deadline time.Time) ([]*domain.AuctionLot, error) {
+	filter := bson.M{
+		"state":    string(domain.StateActive),
+		"end_time": bson.M{"$lte": deadline},
+	}
+	return r.find(ctx, filter, nil)
+}
+
+// GetLotsOpeningBy implements domain.AuctionLotRepository
+func (r *AuctionLotRepository) GetLotsOpeningBy(ctx context.Context, deadline time.Time) ([]*domain.AuctionLot, error) {
+	filter := bson.M{
+		"state":      string(domain.StatePreview),
+		"start_time": bson.M{"$lte": deadline},
+	}
+	return r.find(ctx, filter, nil)
+}
+
+// Search implements domain.LotSearchRepository with the text index, matches are ordered by text score
+// and then by end time. category filters need the categories collection and are not supported yet
+func (r *AuctionLotRepository) Search(ctx context.Context, criteria domain.LotSearchCriteria) ([]*domain.AuctionLot, error) {
+	if criteria.CategoryID != nil {
+		return nil, fmt.Errorf("mongo lot search: category filters are not supported")
+	}
+	filter := bson.M{}
+	opts := options.Find().SetLimit(int64(criteria.Limit)).SetSkip(int64(criteria.Offset))
+	if criteria.Query != "" {
+		filter["$text"] = bson.M{"$search": criteria.Query}
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+		opts.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "end_time", Value: 1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "end_time", Value: 1}})
+	}
+	if criteria.State != "" {
+		filter["state"] = string(criteria.State)
+	}
+	return r.find(ctx, filter, opts)
+}
+
+// scoped adds the organization of ctx on field to filter, unscoped work matches every organization
+func scoped(ctx context.Context, field string, filter bson.M) bson.M {
+	orgID, ok := tenant.OrgID(ctx)
+	switch {
+	case !ok:
+	case orgID == tenant.DefaultOrgID:
+		// nil also matches the documents stored before multi-tenancy, without org_id
+		filter[field] = bson.M{"$in": bson.A{orgID.String(), nil}}
+	default:
+		filter[field] = orgID.String()
+	}
+	return filter
+}
+
+func (r *AuctionLotRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.AuctionLot, error) {
+	cursor, err := r.lots.Find(ctx, scoped(ctx, "org_id", filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []lotDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	lots := make([]*domain.AuctionLot, 0, len(docs))
+	for i := range docs {
+		lot, err := docs[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, nil
+}
@@ -0,0 +1,72 @@
+//go:build mongo
+
+// Package mongo is a MongoDB implementation of the lot and bid repositories, for deployments standardized
+// on a document store. it's built with the mongo build tag (go build -tags mongo) and needs a replica set,
+// multi document transactions are not available on standalone servers
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor implements domain.Transactor with MongoDB sessions
+type Transactor struct {
+	client *mongo.Client
+}
+
+// NewTransactor creates a new instance of Transactor
+func NewTransactor(client *mongo.Client) *Transactor {
+	return &Transactor{client: client}
+}
+
+// sessionTx is a MongoDB transaction, the session ends with the commit or the rollback
+type sessionTx struct {
+	session mongo.Session
+}
+
+// Begin implements domain.Transactor
+func (t *Transactor) Begin(ctx context.Context) (domain.Tx, error) {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	if err := session.StartTransaction(); err != nil {
+		session.EndSession(ctx)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	return &sessionTx{session: session}, nil
+}
+
+func (tx *sessionTx) Commit(ctx context.Context) error {
+	defer tx.session.EndSession(ctx)
+	return tx.session.CommitTransaction(ctx)
+}
+
+func (tx *sessionTx) Rollback(ctx context.Context) error {
+	defer tx.session.EndSession(ctx)
+	return tx.session.AbortTransaction(ctx)
+}
+
+// Retryable implements domain.Transactor, the write conflicts of a transaction are labeled transient
+// by the server and the optimistic lot updates fail with domain.ErrConcurrentLotUpdate
+func (t *Transactor) Retryable(err error) bool {
+	if errors.Is(err, domain.ErrConcurrentLotUpdate) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorLabel("TransientTransactionError")
+}
+
+// txContext returns ctx bound to the session of tx, the operations run with it belong to the transaction
+func txContext(ctx context.Context, tx domain.Tx) (context.Context, error) {
+	stx, ok := tx.(*sessionTx)
+	if !ok {
+		return nil, fmt.Errorf("mongo repository: unsupported transaction type %T", tx)
+	}
+	return mongo.NewSessionContext(ctx, stx.session), nil
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// absenteeBidColumns are the columns read by scanAbsenteeBid
+const absenteeBidColumns = `id, lot_id, user_id, max_amount, client_ip, status, bid_id, bid_amount, error, submitted_at, processed_at`
+
+// AbsenteeBidRepository implements domain.AbsenteeBidRepository interface
+type AbsenteeBidRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAbsenteeBidRepository creates a new instance of AbsenteeBidRepository
+func NewAbsenteeBidRepository(pool *pgxpool.Pool) *AbsenteeBidRepository {
+	return &AbsenteeBidRepository{pool: pool}
+}
+
+// Create inserts a pending absentee bid, or returns domain.ErrAbsenteeBidExists
+func (r *AbsenteeBidRepository) Create(ctx context.Context, bid *domain.AbsenteeBid) error {
+	query := `
+        INSERT INTO absentee_bids (id, lot_id, user_id, max_amount, client_ip, status, submitted_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW())
+        RETURNING submitted_at
+    `
+	err := r.pool.QueryRow(ctx, query, bid.ID, bid.LotID, bid.UserID, bid.MaxAmount, bid.ClientIP, bid.Status).Scan(&bid.SubmittedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return domain.ErrAbsenteeBidExists
+	}
+	return err
+}
+
+// GetByID returns an absentee bid, or domain.ErrAbsenteeBidNotFound
+func (r *AbsenteeBidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AbsenteeBid, error) {
+	bid, err := scanAbsenteeBid(r.pool.QueryRow(ctx, `SELECT `+absenteeBidColumns+` FROM absentee_bids WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrAbsenteeBidNotFound
+	}
+	return bid, err
+}
+
+// ListByUserID returns a page of the absentee bids of the user, newest first
+func (r *AbsenteeBidRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.AbsenteeBid, error) {
+	query := `
+        SELECT ` + absenteeBidColumns + `
+        FROM absentee_bids
+        WHERE user_id = $1
+        ORDER BY submitted_at DESC, id
+        LIMIT $2 OFFSET $3
+    `
+	return r.list(ctx, query, userID, limit, offset)
+}
+
+// ListByLotID returns the absentee bids of the lot in submission order
+func (r *AbsenteeBidRepository) ListByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.AbsenteeBid, error) {
+	query := `
+        SELECT ` + absenteeBidColumns + `
+        FROM absentee_bids
+        WHERE lot_id = $1
+        ORDER BY submitted_at, id
+    `
+	return r.list(ctx, query, lotID)
+}
+
+// ClaimPending marks the pending absentee bids of the lot as processing, the update locks the rows so
+// concurrent claims of the lot get each bid at most once
+func (r *AbsenteeBidRepository) ClaimPending(ctx context.Context, lotID uuid.UUID) ([]*domain.AbsenteeBid, error) {
+	query := `
+        UPDATE absentee_bids SET status = $2
+        WHERE lot_id = $1 AND status = $3
+        RETURNING ` + absenteeBidColumns
+	bids, err := r.list(ctx, query, lotID, domain.AbsenteeBidProcessing, domain.AbsenteeBidPending)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING has no order
+	sort.Slice(bids, func(i, j int) bool {
+		if !bids[i].SubmittedAt.Equal(bids[j].SubmittedAt) {
+			return bids[i].SubmittedAt.Before(bids[j].SubmittedAt)
+		}
+		return bids[i].ID.String() < bids[j].ID.String()
+	})
+	return bids, nil
+}
+
+// GetEntered returns the latest entered absentee bid of the user on the lot, or domain.ErrAbsenteeBidNotFound
+func (r *AbsenteeBidRepository) GetEntered(ctx context.Context, lotID, userID uuid.UUID) (*domain.AbsenteeBid, error) {
+	query := `
+        SELECT ` + absenteeBidColumns + `
+        FROM absentee_bids
+        WHERE lot_id = $1 AND user_id = $2 AND status = $3
+        ORDER BY submitted_at DESC
+        LIMIT 1
+    `
+	bid, err := scanAbsenteeBid(r.pool.QueryRow(ctx, query, lotID, userID, domain.AbsenteeBidEntered))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrAbsenteeBidNotFound
+	}
+	return bid, err
+}
+
+// LotsWithPending returns the active lots with pending absentee bids
+func (r *AbsenteeBidRepository) LotsWithPending(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+        SELECT DISTINCT ab.lot_id
+        FROM absentee_bids ab
+        JOIN auction_lots l ON l.id = ab.lot_id
+        WHERE ab.status = $1 AND l.state = $2
+    `
+	rows, err := r.pool.Query(ctx, query, domain.AbsenteeBidPending, domain.StateActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lots []uuid.UUID
+	for rows.Next() {
+		var lotID uuid.UUID
+		if err := rows.Scan(&lotID); err != nil {
+			return nil, err
+		}
+		lots = append(lots, lotID)
+	}
+	return lots, rows.Err()
+}
+
+// Cancel marks the absentee bid as cancelled if it's still pending
+func (r *AbsenteeBidRepository) Cancel(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE absentee_bids SET status = $2, processed_at = NOW() WHERE id = $1 AND status = $3`
+	tag, err := r.pool.Exec(ctx, query, id, domain.AbsenteeBidCancelled, domain.AbsenteeBidPending)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Update saves the status and outcome of the absentee bid, or returns domain.ErrAbsenteeBidNotFound
+func (r *AbsenteeBidRepository) Update(ctx context.Context, bid *domain.AbsenteeBid) error {
+	query := `
+        UPDATE absentee_bids SET status = $2, bid_id = $3, bid_amount = $4, error = $5, processed_at = $6
+        WHERE id = $1
+    `
+	tag, err := r.pool.Exec(ctx, query, bid.ID, bid.Status, bid.BidID, bid.BidAmount, bid.Error, bid.ProcessedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAbsenteeBidNotFound
+	}
+	return nil
+}
+
+func (r *AbsenteeBidRepository) list(ctx context.Context, query string, args ...any) ([]*domain.AbsenteeBid, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var bids []*domain.AbsenteeBid
+	for rows.Next() {
+		bid, err := scanAbsenteeBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
+
+func scanAbsenteeBid(row pgx.Row) (*domain.AbsenteeBid, error) {
+	bid := &domain.AbsenteeBid{}
+	err := row.Scan(&bid.ID, &bid.LotID, &bid.UserID, &bid.MaxAmount, &bid.ClientIP, &bid.Status, &bid.BidID, &bid.BidAmount,
+		&bid.Error, &bid.SubmittedAt, &bid.ProcessedAt)
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
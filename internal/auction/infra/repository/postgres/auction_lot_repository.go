@@ -6,41 +6,106 @@ import (
 	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // AuctionLotRepository implements domain.AuctionLotRepository interface
 type AuctionLotRepository struct {
 	pool *pgxpool.Pool
+	read db.Reader // read-only queries, the primary pool unless WithReader is used
 }
 
 // NewAuctionLotRepository creates a new instance of AuctionRepository
 func NewAuctionLotRepository(pool *pgxpool.Pool) *AuctionLotRepository {
-	return &AuctionLotRepository{pool: pool}
+	return &AuctionLotRepository{pool: pool, read: pool}
 }
 
-// Save guarda o actualiza un AuctionLot en la base de datos.
-// Utiliza INSERT ON CONFLICT para manejar tanto la creación como la actualización.
+// WithReader returns a copy of the repository running its read-only queries on reader,
+// e.g. a db.ReadRouter over a read replica, writes keep going to the primary
+func (r *AuctionLotRepository) WithReader(reader db.Reader) *AuctionLotRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+// Save guarda un AuctionLot nuevo (Version 0) o actualiza uno ya guardado con concurrencia optimista:
+// el UPDATE solo aplica si la version guardada sigue siendo la que tenía el lote al leerlo, si otra
+// instancia lo guardó entre medio falla con domain.ErrConcurrentLotUpdate. la version del lote avanza al guardar.
 // Omitimos created_at y updated_at en el INSERT inicial para usar los DEFAULT/TRIGGER de la DB.
-func (r *AuctionLotRepository) Save(ctx context.Context, tx pgx.Tx, lot *domain.AuctionLot) error {
+func (r *AuctionLotRepository) Save(ctx context.Context, tx domain.Tx, lot *domain.AuctionLot) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	if lot.Version == 0 {
+		query := `
+        INSERT INTO auction_lots (id, title, description, initial_price, current_price, end_time, state, last_bid_time, time_extension, currency, start_time, lot_type,
+            closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, org_id, quantity, unit_pricing, version)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, 1)
+    `
+		_, err = pgTx.Exec(ctx, query,
+			lot.ID,
+			lot.Title,
+			lot.Description,
+			lot.InitialPrice,
+			lot.CurrentPrice,
+			lot.EndTime,
+			lot.State,
+			lot.LastBidTime,
+			lot.TimeExtension,
+			lot.Currency,
+			lot.StartTime,
+			lot.Type,
+			lot.Closing.Mode,
+			lot.Closing.MaxExtension,
+			lot.Closing.PriceThreshold,
+			lot.ScheduledEndTime,
+			lot.PausedAt,
+			lot.OrgID,
+			lot.Quantity,
+			lot.Pricing,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+				return domain.ErrConcurrentLotUpdate
+			}
+			return err
+		}
+		lot.Version = 1
+		return nil
+	}
+
+	// the owner organization and the units never change and event_seq is owned by the event store, none is updated
 	query := `
-        INSERT INTO auction_lots (id, title, description, initial_price, current_price, end_time, state, last_bid_time, time_extension)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-        ON CONFLICT (id) DO UPDATE
+        UPDATE auction_lots
         SET
-            title = EXCLUDED.title,
-            description = EXCLUDED.description,
-            initial_price = EXCLUDED.initial_price,
-            current_price = EXCLUDED.current_price,
-            end_time = EXCLUDED.end_time,
-            state = EXCLUDED.state,
-            last_bid_time = EXCLUDED.last_bid_time,
-            time_extension = EXCLUDED.time_extension,
-            updated_at = NOW(); 
+            title = $2,
+            description = $3,
+            initial_price = $4,
+            current_price = $5,
+            end_time = $6,
+            state = $7,
+            last_bid_time = $8,
+            time_extension = $9,
+            currency = $10,
+            start_time = $11,
+            lot_type = $12,
+            closing_mode = $13,
+            closing_max_extension = $14,
+            closing_price_threshold = $15,
+            scheduled_end_time = $16,
+            paused_at = $17,
+            version = version + 1,
+            updated_at = NOW()
+        WHERE id = $1 AND version = $18
     `
-	_, err := tx.Exec(ctx, query,
+	tag, err := pgTx.Exec(ctx, query,
 		lot.ID,
 		lot.Title,
 		lot.Description,
@@ -50,31 +115,60 @@ func (r *AuctionLotRepository) Save(ctx context.Context, tx pgx.Tx, lot *domain.
 		lot.State,
 		lot.LastBidTime,
 		lot.TimeExtension,
+		lot.Currency,
+		lot.StartTime,
+		lot.Type,
+		lot.Closing.Mode,
+		lot.Closing.MaxExtension,
+		lot.Closing.PriceThreshold,
+		lot.ScheduledEndTime,
+		lot.PausedAt,
+		lot.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrConcurrentLotUpdate
+	}
+	lot.Version++
+	return nil
 }
 
-// GetByID recupera un AuctionLot por su ID.
+// GetByID recupera un AuctionLot por su ID, limitado a la organización de ctx si la tiene.
 // Incluimos created_at y updated_at en el SELECT y SCAN.
 func (r *AuctionLotRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AuctionLot, error) {
 	query := `
-        SELECT id, title, description, initial_price, current_price, end_time, state, last_bid_time, time_extension, created_at, updated_at
+        SELECT id, title, description, initial_price, current_price, end_time, start_time, state, last_bid_time, time_extension, currency, lot_type, closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, quantity, unit_pricing, event_seq, version, org_id, created_at, updated_at
         FROM auction_lots
-        WHERE id = $1
+        WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)
     `
 	lot := &domain.AuctionLot{}
 	var lastBidTime *time.Time // pointer to handle NULL
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.read.QueryRow(ctx, query, id, tenant.Filter(ctx)).Scan(
 		&lot.ID,
 		&lot.Title,
 		&lot.Description,
 		&lot.InitialPrice,
 		&lot.CurrentPrice,
 		&lot.EndTime,
+		&lot.StartTime,
 		&lot.State,
 		&lastBidTime, // scan pointer
 		&lot.TimeExtension,
+		&lot.Currency,
+		&lot.Type,
+		&lot.Closing.Mode,
+		&lot.Closing.MaxExtension,
+		&lot.Closing.PriceThreshold,
+		&lot.ScheduledEndTime,
+		&lot.PausedAt,
+		&lot.Quantity,
+		&lot.Pricing,
+		&lot.Seq,
+		&lot.Version,
+		&lot.OrgID,
 		&lot.CreatedAt, // Incluido en SCAN
 		&lot.UpdatedAt, // Incluido en SCAN
 	)
@@ -95,11 +189,11 @@ func (r *AuctionLotRepository) GetByID(ctx context.Context, id uuid.UUID) (*doma
 // Incluimos created_at y updated_at en el SELECT y SCAN.
 func (r *AuctionLotRepository) GetActiveLots(ctx context.Context) ([]*domain.AuctionLot, error) {
 	query := `
-        SELECT id, title, description, initial_price, current_price, end_time, state, last_bid_time, time_extension, created_at, updated_at
+        SELECT id, title, description, initial_price, current_price, end_time, start_time, state, last_bid_time, time_extension, currency, lot_type, closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, quantity, unit_pricing, event_seq, version, org_id, created_at, updated_at
         FROM auction_lots
-        WHERE state = $1
+        WHERE state = $1 AND ($2::uuid IS NULL OR org_id = $2)
     `
-	rows, err := r.pool.Query(ctx, query, domain.StateActive)
+	rows, err := r.read.Query(ctx, query, domain.StateActive, tenant.Filter(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -116,9 +210,22 @@ func (r *AuctionLotRepository) GetActiveLots(ctx context.Context) ([]*domain.Auc
 			&lot.InitialPrice,
 			&lot.CurrentPrice,
 			&lot.EndTime,
+			&lot.StartTime,
 			&lot.State,
 			&lastBidTime,
 			&lot.TimeExtension,
+			&lot.Currency,
+			&lot.Type,
+			&lot.Closing.Mode,
+			&lot.Closing.MaxExtension,
+			&lot.Closing.PriceThreshold,
+			&lot.ScheduledEndTime,
+			&lot.PausedAt,
+			&lot.Quantity,
+			&lot.Pricing,
+			&lot.Seq,
+			&lot.Version,
+			&lot.OrgID,
 			&lot.CreatedAt, // Incluido en SCAN
 			&lot.UpdatedAt, // Incluido en SCAN
 		)
@@ -136,16 +243,16 @@ func (r *AuctionLotRepository) GetActiveLots(ctx context.Context) ([]*domain.Auc
 	return lots, nil
 }
 
-// GetLotsEndingSoon recupera lotes activos que terminan pronto.
-// 'threshold' define cuánto tiempo antes del fin se consideran "ending soon".
+// GetLotsEndingBy recupera lotes activos que terminan a más tardar en 'deadline', tomado del Clock
+// del llamador y no de NOW() para que el cierre siga al mismo reloj que el dominio.
 // Incluimos created_at y updated_at en el SELECT y SCAN.
-func (r *AuctionLotRepository) GetLotsEndingSoon(ctx context.Context, threshold time.Duration) ([]*domain.AuctionLot, error) {
+func (r *AuctionLotRepository) GetLotsEndingBy(ctx context.Context, deadline time.Time) ([]*domain.AuctionLot, error) {
 	query := `
-        SELECT id, title, description, initial_price, current_price, end_time, state, last_bid_time, time_extension, created_at, updated_at
+        SELECT id, title, description, initial_price, current_price, end_time, start_time, state, last_bid_time, time_extension, currency, lot_type, closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, quantity, unit_pricing, event_seq, version, org_id, created_at, updated_at
         FROM auction_lots
-        WHERE state = $1 AND end_time <= NOW() + $2
+        WHERE state = $1 AND end_time <= $2 AND ($3::uuid IS NULL OR org_id = $3)
     `
-	rows, err := r.pool.Query(ctx, query, domain.StateActive, threshold)
+	rows, err := r.read.Query(ctx, query, domain.StateActive, deadline, tenant.Filter(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -162,9 +269,22 @@ func (r *AuctionLotRepository) GetLotsEndingSoon(ctx context.Context, threshold
 			&lot.InitialPrice,
 			&lot.CurrentPrice,
 			&lot.EndTime,
+			&lot.StartTime,
 			&lot.State,
 			&lastBidTime,
 			&lot.TimeExtension,
+			&lot.Currency,
+			&lot.Type,
+			&lot.Closing.Mode,
+			&lot.Closing.MaxExtension,
+			&lot.Closing.PriceThreshold,
+			&lot.ScheduledEndTime,
+			&lot.PausedAt,
+			&lot.Quantity,
+			&lot.Pricing,
+			&lot.Seq,
+			&lot.Version,
+			&lot.OrgID,
 			&lot.CreatedAt, // Incluido en SCAN
 			&lot.UpdatedAt, // Incluido en SCAN
 		)
@@ -181,3 +301,133 @@ func (r *AuctionLotRepository) GetLotsEndingSoon(ctx context.Context, threshold
 
 	return lots, nil
 }
+
+// GetLotsOpeningBy recupera lotes en preview cuyo start_time es a más tardar 'deadline'.
+func (r *AuctionLotRepository) GetLotsOpeningBy(ctx context.Context, deadline time.Time) ([]*domain.AuctionLot, error) {
+	query := `
+        SELECT id, title, description, initial_price, current_price, end_time, start_time, state, last_bid_time, time_extension, currency, lot_type, closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, quantity, unit_pricing, event_seq, version, org_id, created_at, updated_at
+        FROM auction_lots
+        WHERE state = $1 AND start_time <= $2 AND ($3::uuid IS NULL OR org_id = $3)
+    `
+	rows, err := r.read.Query(ctx, query, domain.StatePreview, deadline, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*domain.AuctionLot
+	for rows.Next() {
+		lot := &domain.AuctionLot{}
+		var lastBidTime *time.Time
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Title,
+			&lot.Description,
+			&lot.InitialPrice,
+			&lot.CurrentPrice,
+			&lot.EndTime,
+			&lot.StartTime,
+			&lot.State,
+			&lastBidTime,
+			&lot.TimeExtension,
+			&lot.Currency,
+			&lot.Type,
+			&lot.Closing.Mode,
+			&lot.Closing.MaxExtension,
+			&lot.Closing.PriceThreshold,
+			&lot.ScheduledEndTime,
+			&lot.PausedAt,
+			&lot.Quantity,
+			&lot.Pricing,
+			&lot.Seq,
+			&lot.Version,
+			&lot.OrgID,
+			&lot.CreatedAt,
+			&lot.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lot.LastBidTime = lastBidTime
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// Search implements domain.LotSearchRepository with the search_vector GIN index,
+// without query the matching lots are ordered by end time only
+func (r *AuctionLotRepository) Search(ctx context.Context, criteria domain.LotSearchCriteria) ([]*domain.AuctionLot, error) {
+	query := `
+        WITH RECURSIVE category_tree AS (
+            SELECT id FROM categories WHERE id = $5
+            UNION
+            SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id
+        )
+        SELECT id, title, description, initial_price, current_price, end_time, start_time, state, last_bid_time, time_extension, currency, lot_type, closing_mode, closing_max_extension, closing_price_threshold, scheduled_end_time, paused_at, quantity, unit_pricing, event_seq, version, org_id, created_at, updated_at
+        FROM auction_lots
+        WHERE ($1 = '' OR search_vector @@ websearch_to_tsquery('simple', $1))
+          AND ($2 = '' OR state = $2)
+          AND ($5::uuid IS NULL OR EXISTS (
+              SELECT 1 FROM lot_categories lc
+              WHERE lc.lot_id = auction_lots.id AND lc.category_id IN (SELECT id FROM category_tree)
+          ))
+          AND ($6::uuid IS NULL OR org_id = $6)
+        ORDER BY
+            CASE WHEN $1 = '' THEN 0 ELSE ts_rank(search_vector, websearch_to_tsquery('simple', $1)) END DESC,
+            end_time ASC
+        LIMIT $3 OFFSET $4
+    `
+	rows, err := r.read.Query(ctx, query, criteria.Query, string(criteria.State), criteria.Limit, criteria.Offset, criteria.CategoryID, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*domain.AuctionLot
+	for rows.Next() {
+		lot := &domain.AuctionLot{}
+		var lastBidTime *time.Time
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Title,
+			&lot.Description,
+			&lot.InitialPrice,
+			&lot.CurrentPrice,
+			&lot.EndTime,
+			&lot.StartTime,
+			&lot.State,
+			&lastBidTime,
+			&lot.TimeExtension,
+			&lot.Currency,
+			&lot.Type,
+			&lot.Closing.Mode,
+			&lot.Closing.MaxExtension,
+			&lot.Closing.PriceThreshold,
+			&lot.ScheduledEndTime,
+			&lot.PausedAt,
+			&lot.Quantity,
+			&lot.Pricing,
+			&lot.Seq,
+			&lot.Version,
+			&lot.OrgID,
+			&lot.CreatedAt,
+			&lot.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lot.LastBidTime = lastBidTime
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BidIncrementRepository implements domain.BidIncrementRepository interface
+type BidIncrementRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBidIncrementRepository creates a new instance of BidIncrementRepository
+func NewBidIncrementRepository(pool *pgxpool.Pool) *BidIncrementRepository {
+	return &BidIncrementRepository{pool: pool}
+}
+
+// GetIncrementTable returns the lot specific increment table, or the global one (lot_id IS NULL)
+// if the lot doesn't define its own tiers. An empty table means no minimum increment
+func (r *BidIncrementRepository) GetIncrementTable(ctx context.Context, lotID uuid.UUID) (domain.IncrementTable, error) {
+	query := `
+        SELECT min_price, increment
+        FROM bid_increments
+        WHERE lot_id IS NOT DISTINCT FROM (
+            SELECT lot_id FROM bid_increments
+            WHERE lot_id = $1 OR lot_id IS NULL
+            ORDER BY lot_id NULLS LAST
+            LIMIT 1
+        )
+        ORDER BY min_price ASC
+    `
+	rows, err := r.pool.Query(ctx, query, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tiers []domain.IncrementTier
+	for rows.Next() {
+		var tier domain.IncrementTier
+		if err := rows.Scan(&tier.MinPrice, &tier.Increment); err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, tier)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return domain.NewIncrementTable(tiers...), nil
+}
@@ -3,8 +3,11 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -13,37 +16,122 @@ import (
 // BidRepository implements domain.BidRepository interface
 type BidRepository struct {
 	pool *pgxpool.Pool
+	read db.Reader
 }
 
 // NewBidRepository creates new instance of BidRepository.
 func NewBidRepository(pool *pgxpool.Pool) *BidRepository {
-	return &BidRepository{pool: pool}
+	return &BidRepository{pool: pool, read: pool}
+}
+
+// WithReader returns a copy of the repository reading bids and bid history from reader
+func (r *BidRepository) WithReader(reader db.Reader) *BidRepository {
+	c := *r
+	c.read = reader
+	return &c
 }
 
 // this method only inserts a new bid, the logic for the transaction for update the lot, will be created in application layer
-func (r *BidRepository) Save(ctx context.Context, tx pgx.Tx, bid *domain.Bid) error {
+func (r *BidRepository) Save(ctx context.Context, tx domain.Tx, bid *domain.Bid) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
 	query := `
-        INSERT INTO bids (id, lot_id, user_id, amount, timestamp, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO bids (id, lot_id, user_id, amount, timestamp, created_at, client_ip, received_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     `
-	_, err := tx.Exec(ctx, query,
+	_, err = pgTx.Exec(ctx, query,
 		bid.ID,
 		bid.LotID,
 		bid.UserID,
 		bid.Amount,
 		bid.Timestamp,
+		bid.CreatedAt,
+		bid.ClientIP,
+		bid.ReceivedAt,
+	)
+	return err
+}
+
+// SaveBatch implements domain.BidRepository with the COPY protocol
+func (r *BidRepository) SaveBatch(ctx context.Context, tx domain.Tx, bids []*domain.Bid) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	if len(bids) == 0 {
+		return nil
+	}
+	_, err = pgTx.CopyFrom(ctx,
+		pgx.Identifier{"bids"},
+		[]string{"id", "lot_id", "user_id", "amount", "timestamp", "created_at", "client_ip", "received_at"},
+		pgx.CopyFromSlice(len(bids), func(i int) ([]any, error) {
+			bid := bids[i]
+			return []any{bid.ID, bid.LotID, bid.UserID, bid.Amount, bid.Timestamp, bid.CreatedAt, bid.ClientIP, bid.ReceivedAt}, nil
+		}),
+	)
+	return err
+}
+
+// GetByID returns a bid including its void fields, or domain.ErrBidNotFound
+func (r *BidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	query := `
+        SELECT id, lot_id, user_id, amount, timestamp, created_at, voided_at, voided_by, void_reason
+        FROM bids
+        WHERE id = $1
+    `
+	bid := &domain.Bid{}
+	err := r.read.QueryRow(ctx, query, id).Scan(
+		&bid.ID,
+		&bid.LotID,
+		&bid.UserID,
+		&bid.Amount,
+		&bid.Timestamp,
+		&bid.CreatedAt,
+		&bid.VoidedAt,
+		&bid.VoidedBy,
+		&bid.VoidReason,
 	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrBidNotFound
+		}
+		return nil, err
+	}
+	return bid, nil
+}
+
+// Void stores the void fields of a bid, bids are never deleted
+func (r *BidRepository) Void(ctx context.Context, tx domain.Tx, bid *domain.Bid) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	query := `
+        UPDATE bids
+        SET voided_at = $2, voided_by = $3, void_reason = $4
+        WHERE id = $1
+    `
+	_, err = pgTx.Exec(ctx, query, bid.ID, bid.VoidedAt, bid.VoidedBy, bid.VoidReason)
 	return err
 }
 
+// CountBidsByLotID returns the number of valid bids of the lot, implements domain.BidCounter
+func (r *BidRepository) CountBidsByLotID(ctx context.Context, lotID uuid.UUID) (int, error) {
+	var count int
+	err := r.read.QueryRow(ctx, `SELECT COUNT(*) FROM bids WHERE lot_id = $1 AND voided_at IS NULL`, lotID).Scan(&count)
+	return count, err
+}
+
 func (r *BidRepository) GetBidsByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.Bid, error) {
 	query := `
         SELECT id, lot_id, user_id, amount, timestamp, created_at
         FROM bids
-        WHERE lot_id = $1
-        ORDER BY timestamp ASC 
+        WHERE lot_id = $1 AND voided_at IS NULL
+        ORDER BY timestamp ASC
     `
-	rows, err := r.pool.Query(ctx, query, lotID)
+	rows, err := r.read.Query(ctx, query, lotID)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +146,7 @@ func (r *BidRepository) GetBidsByLotID(ctx context.Context, lotID uuid.UUID) ([]
 			&bid.UserID,
 			&bid.Amount,
 			&bid.Timestamp,
+			&bid.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -76,17 +165,18 @@ func (r *BidRepository) GetLatestBidByLotID(ctx context.Context, lotID uuid.UUID
 	query := `
         SELECT id, lot_id, user_id, amount, timestamp, created_at
         FROM bids
-        WHERE lot_id = $1
+        WHERE lot_id = $1 AND voided_at IS NULL
         ORDER BY timestamp DESC
         LIMIT 1
     `
 	bid := &domain.Bid{}
-	err := r.pool.QueryRow(ctx, query, lotID).Scan(
+	err := r.read.QueryRow(ctx, query, lotID).Scan(
 		&bid.ID,
 		&bid.LotID,
 		&bid.UserID,
 		&bid.Amount,
 		&bid.Timestamp,
+		&bid.CreatedAt,
 	)
 
 	if err != nil {
@@ -99,3 +189,316 @@ func (r *BidRepository) GetLatestBidByLotID(ctx context.Context, lotID uuid.UUID
 
 	return bid, nil
 }
+
+// GetStandingBids returns the best valid bid of each bidder of the lot, the limit best ones: highest
+// amount first, the earliest on ties
+func (r *BidRepository) GetStandingBids(ctx context.Context, lotID uuid.UUID, limit int) ([]*domain.Bid, error) {
+	query := `
+        SELECT id, lot_id, user_id, amount, timestamp, created_at
+        FROM (
+            SELECT DISTINCT ON (user_id) id, lot_id, user_id, amount, timestamp, created_at
+            FROM bids
+            WHERE lot_id = $1 AND voided_at IS NULL
+            ORDER BY user_id, amount DESC, timestamp ASC
+        ) best
+        ORDER BY amount DESC, timestamp ASC
+        LIMIT $2
+    `
+	rows, err := r.read.Query(ctx, query, lotID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []*domain.Bid
+	for rows.Next() {
+		bid := &domain.Bid{}
+		if err := rows.Scan(&bid.ID, &bid.LotID, &bid.UserID, &bid.Amount, &bid.Timestamp, &bid.CreatedAt); err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
+
+// GetBidderIDsByLotID returns the distinct users who placed at least one bid on the lot
+func (r *BidRepository) GetBidderIDsByLotID(ctx context.Context, lotID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+        SELECT DISTINCT user_id
+        FROM bids
+        WHERE lot_id = $1 AND voided_at IS NULL
+    `
+	rows, err := r.read.Query(ctx, query, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// GetBidsByUserID returns a page of the user bids, newest first
+func (r *BidRepository) GetBidsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bid, error) {
+	query := `
+        SELECT id, lot_id, user_id, amount, timestamp, created_at, voided_at, voided_by, void_reason
+        FROM bids
+        WHERE user_id = $1
+        ORDER BY timestamp DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.read.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []*domain.Bid
+	for rows.Next() {
+		bid := &domain.Bid{}
+		err := rows.Scan(
+			&bid.ID,
+			&bid.LotID,
+			&bid.UserID,
+			&bid.Amount,
+			&bid.Timestamp,
+			&bid.CreatedAt,
+			&bid.VoidedAt,
+			&bid.VoidedBy,
+			&bid.VoidReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
+
+// GetLotsByBidderID returns a page of the lots the user bid on with the user bids summary,
+// the user leads a lot when the latest bid of the lot is theirs
+func (r *BidRepository) GetLotsByBidderID(ctx context.Context, userID uuid.UUID, state domain.AuctionLotState, limit, offset int) ([]*domain.UserLotBids, error) {
+	query := `
+        SELECT l.id, l.title, l.description, l.initial_price, l.current_price, l.end_time, l.state, l.last_bid_time,
+               l.time_extension, l.currency, l.lot_type, l.closing_mode, l.closing_max_extension, l.closing_price_threshold,
+               l.scheduled_end_time, l.paused_at, l.event_seq, l.created_at, l.updated_at,
+               ub.highest_bid, ub.bid_count, ub.last_bid_at, COALESCE(lb.user_id = $1, false)
+        FROM (
+            SELECT lot_id, MAX(amount) AS highest_bid, COUNT(*) AS bid_count, MAX(timestamp) AS last_bid_at
+            FROM bids
+            WHERE user_id = $1 AND voided_at IS NULL
+            GROUP BY lot_id
+        ) ub
+        JOIN auction_lots l ON l.id = ub.lot_id
+        LEFT JOIN LATERAL (
+            SELECT user_id FROM bids WHERE lot_id = l.id AND voided_at IS NULL ORDER BY timestamp DESC LIMIT 1
+        ) lb ON true
+        WHERE ($2 = '' OR l.state = $2) AND ($5::uuid IS NULL OR l.org_id = $5)
+        ORDER BY ub.last_bid_at DESC
+        LIMIT $3 OFFSET $4
+    `
+	rows, err := r.read.Query(ctx, query, userID, string(state), limit, offset, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*domain.UserLotBids
+	for rows.Next() {
+		lot := &domain.AuctionLot{}
+		summary := &domain.UserLotBids{Lot: lot}
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Title,
+			&lot.Description,
+			&lot.InitialPrice,
+			&lot.CurrentPrice,
+			&lot.EndTime,
+			&lot.State,
+			&lot.LastBidTime,
+			&lot.TimeExtension,
+			&lot.Currency,
+			&lot.Type,
+			&lot.Closing.Mode,
+			&lot.Closing.MaxExtension,
+			&lot.Closing.PriceThreshold,
+			&lot.ScheduledEndTime,
+			&lot.PausedAt,
+			&lot.Seq,
+			&lot.CreatedAt,
+			&lot.UpdatedAt,
+			&summary.HighestBid,
+			&summary.BidCount,
+			&summary.LastBidAt,
+			&summary.Leading,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// GetLotBidStats implements domain.BidStatsRepository with window queries over the lot bids
+func (r *BidRepository) GetLotBidStats(ctx context.Context, lotID uuid.UUID, since time.Time, maxPoints int) (*domain.LotBidStats, error) {
+	query := `
+        SELECT COUNT(*), COUNT(DISTINCT user_id), COALESCE(AVG(ABS(increment)), 0),
+               COUNT(*) FILTER (WHERE timestamp >= $2), MIN(timestamp), MAX(timestamp)
+        FROM (
+            SELECT user_id, timestamp, amount - LAG(amount) OVER (ORDER BY timestamp) AS increment
+            FROM bids
+            WHERE lot_id = $1 AND voided_at IS NULL
+        ) b
+    `
+	stats := &domain.LotBidStats{}
+	err := r.read.QueryRow(ctx, query, lotID, since).Scan(
+		&stats.BidCount,
+		&stats.UniqueBidders,
+		&stats.AvgIncrement,
+		&stats.RecentBids,
+		&stats.FirstBidAt,
+		&stats.LastBidAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if stats.BidCount == 0 || maxPoints <= 0 {
+		return stats, nil
+	}
+	if stats.PricePoints, err = r.GetLotPriceHistory(ctx, lotID, time.Time{}, maxPoints); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetLotPriceHistory implements domain.BidStatsRepository, it keeps the first bid after after, every
+// step-th bid after it and the last one, so busy lots stay within maxPoints
+func (r *BidRepository) GetLotPriceHistory(ctx context.Context, lotID uuid.UUID, after time.Time, maxPoints int) ([]domain.PricePoint, error) {
+	query := `
+        SELECT timestamp, amount
+        FROM (
+            SELECT timestamp, amount,
+                   ROW_NUMBER() OVER (ORDER BY timestamp) AS n,
+                   COUNT(*) OVER () AS total
+            FROM bids
+            WHERE lot_id = $1 AND voided_at IS NULL AND timestamp > $3
+        ) b
+        WHERE (n - 1) % GREATEST(CEIL((total - 1)::float8 / GREATEST($2 - 1, 1))::bigint, 1) = 0 OR n = total
+        ORDER BY timestamp ASC
+    `
+	rows, err := r.read.Query(ctx, query, lotID, maxPoints, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []domain.PricePoint
+	for rows.Next() {
+		var point domain.PricePoint
+		if err := rows.Scan(&point.At, &point.Price); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// GetLotLeaderboard implements domain.BidStatsRepository, the bidders without a paddle get 0
+func (r *BidRepository) GetLotLeaderboard(ctx context.Context, lotID uuid.UUID, lotType domain.LotType, limit int) ([]domain.LeaderboardEntry, error) {
+	query := `
+        SELECT COALESCE(p.paddle_number, 0), b.user_id, b.best_bid, b.bid_at
+        FROM (
+            -- the best bid of each bidder and the time it was placed, the tie break of equal best bids
+            SELECT DISTINCT ON (user_id) user_id, amount AS best_bid, timestamp AS bid_at
+            FROM bids
+            WHERE lot_id = $1 AND voided_at IS NULL
+            ORDER BY user_id, CASE WHEN $2 = 'reverse' THEN -amount ELSE amount END DESC, timestamp ASC
+        ) b
+        LEFT JOIN lot_paddles p ON p.lot_id = $1 AND p.user_id = b.user_id
+        ORDER BY CASE WHEN $2 = 'reverse' THEN -b.best_bid ELSE b.best_bid END DESC, b.bid_at ASC
+        LIMIT $3
+    `
+	rows, err := r.read.Query(ctx, query, lotID, string(lotType), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.LeaderboardEntry
+	for rows.Next() {
+		var entry domain.LeaderboardEntry
+		if err := rows.Scan(&entry.Paddle, &entry.UserID, &entry.BestBid, &entry.BidAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetLotBidsAfter implements domain.BidExportRepository with keyset pagination over (timestamp, id), so the
+// exports never hold more than a page of bids
+func (r *BidRepository) GetLotBidsAfter(ctx context.Context, lotID uuid.UUID, after domain.BidCursor, limit int) ([]*domain.Bid, error) {
+	query := `
+        SELECT b.id, b.lot_id, b.user_id, b.amount, b.timestamp, b.created_at, b.received_at, b.voided_at, b.voided_by,
+            b.void_reason, COALESCE(p.paddle_number, 0)
+        FROM bids b
+        LEFT JOIN lot_paddles p ON p.lot_id = b.lot_id AND p.user_id = b.user_id
+        WHERE b.lot_id = $1 AND (b.timestamp, b.id) > ($2, $3)
+        ORDER BY b.timestamp ASC, b.id ASC
+        LIMIT $4
+    `
+	rows, err := r.read.Query(ctx, query, lotID, after.Timestamp, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []*domain.Bid
+	for rows.Next() {
+		bid := &domain.Bid{}
+		err := rows.Scan(
+			&bid.ID,
+			&bid.LotID,
+			&bid.UserID,
+			&bid.Amount,
+			&bid.Timestamp,
+			&bid.CreatedAt,
+			&bid.ReceivedAt,
+			&bid.VoidedAt,
+			&bid.VoidedBy,
+			&bid.VoidReason,
+			&bid.Paddle,
+		)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserLimitRepository implements domain.BiddingLimitProvider over the user_limits table
+type UserLimitRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserLimitRepository creates a new instance of UserLimitRepository
+func NewUserLimitRepository(pool *pgxpool.Pool) *UserLimitRepository {
+	return &UserLimitRepository{pool: pool}
+}
+
+// GetLimit returns the user bidding limit, users without a row have no limit
+func (r *UserLimitRepository) GetLimit(ctx context.Context, userID uuid.UUID) (float64, bool, error) {
+	query := `SELECT bidding_limit FROM user_limits WHERE user_id = $1`
+	var limit float64
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&limit)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return limit, true, nil
+}
+
+// BidReservationRepository implements domain.BidReservationRepository interface
+type BidReservationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBidReservationRepository creates a new instance of BidReservationRepository
+func NewBidReservationRepository(pool *pgxpool.Pool) *BidReservationRepository {
+	return &BidReservationRepository{pool: pool}
+}
+
+// ReservedTotal takes a TX scoped advisory lock on the user, so concurrent bids of the same user
+// on different lots check their limit one at a time, and returns the reserved total excluding lotID
+func (r *BidReservationRepository) ReservedTotal(ctx context.Context, tx domain.Tx, userID, excludeLotID uuid.UUID) (float64, error) {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = pgTx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))`, userID); err != nil {
+		return 0, err
+	}
+	query := `SELECT COALESCE(SUM(amount), 0) FROM bid_reservations WHERE user_id = $1 AND lot_id <> $2`
+	var total float64
+	err = pgTx.QueryRow(ctx, query, userID, excludeLotID).Scan(&total)
+	return total, err
+}
+
+// Reserve inserts or replaces the user reservation on the lot
+func (r *BidReservationRepository) Reserve(ctx context.Context, tx domain.Tx, userID, lotID uuid.UUID, amount float64) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO bid_reservations (user_id, lot_id, amount)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, lot_id) DO UPDATE
+        SET amount = EXCLUDED.amount, updated_at = NOW()
+    `
+	_, err = pgTx.Exec(ctx, query, userID, lotID, amount)
+	return err
+}
+
+// Release deletes the user reservation on the lot
+func (r *BidReservationRepository) Release(ctx context.Context, tx domain.Tx, userID, lotID uuid.UUID) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	_, err = pgTx.Exec(ctx, `DELETE FROM bid_reservations WHERE user_id = $1 AND lot_id = $2`, userID, lotID)
+	return err
+}
+
+// ReleaseLot deletes every reservation on the lot
+func (r *BidReservationRepository) ReleaseLot(ctx context.Context, tx domain.Tx, lotID uuid.UUID) error {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return err
+	}
+	_, err = pgTx.Exec(ctx, `DELETE FROM bid_reservations WHERE lot_id = $1`, lotID)
+	return err
+}
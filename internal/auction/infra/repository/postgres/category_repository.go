@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUniqueViolation is the Postgres error code of unique constraint violations
+const pgUniqueViolation = "23505"
+
+// CategoryRepository implements domain.CategoryRepository interface
+type CategoryRepository struct {
+	pool *pgxpool.Pool
+	read db.Reader
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{pool: pool, read: pool}
+}
+
+// WithReader returns a copy of the repository with its lookups running on reader
+func (r *CategoryRepository) WithReader(reader db.Reader) *CategoryRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+// Save inserts a new category
+func (r *CategoryRepository) Save(ctx context.Context, c *domain.Category) error {
+	query := `
+        INSERT INTO categories (id, name, slug, parent_id)
+        VALUES ($1, $2, $3, $4)
+        RETURNING created_at
+    `
+	err := r.pool.QueryRow(ctx, query, c.ID, c.Name, c.Slug, c.ParentID).Scan(&c.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return domain.ErrCategorySlugTaken
+	}
+	return err
+}
+
+// GetByID returns a category, or domain.ErrCategoryNotFound
+func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	query := `SELECT id, name, slug, parent_id, created_at FROM categories WHERE id = $1`
+	c := &domain.Category{}
+	err := r.read.QueryRow(ctx, query, id).Scan(&c.ID, &c.Name, &c.Slug, &c.ParentID, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetBySlug returns a category, or domain.ErrCategoryNotFound
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	query := `SELECT id, name, slug, parent_id, created_at FROM categories WHERE slug = $1`
+	c := &domain.Category{}
+	err := r.read.QueryRow(ctx, query, slug).Scan(&c.ID, &c.Name, &c.Slug, &c.ParentID, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetAll returns every category ordered by name
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]*domain.Category, error) {
+	query := `SELECT id, name, slug, parent_id, created_at FROM categories ORDER BY name ASC`
+	rows, err := r.read.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		c := &domain.Category{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.ParentID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetByLotIDs returns the categories of the given lots in a single query
+func (r *CategoryRepository) GetByLotIDs(ctx context.Context, lotIDs ...uuid.UUID) (map[uuid.UUID][]*domain.Category, error) {
+	byLot := make(map[uuid.UUID][]*domain.Category, len(lotIDs))
+	if len(lotIDs) == 0 {
+		return byLot, nil
+	}
+	query := `
+        SELECT lc.lot_id, c.id, c.name, c.slug, c.parent_id, c.created_at
+        FROM lot_categories lc
+        JOIN categories c ON c.id = lc.category_id
+        WHERE lc.lot_id = ANY($1)
+        ORDER BY c.name ASC
+    `
+	rows, err := r.read.Query(ctx, query, lotIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lotID uuid.UUID
+		c := &domain.Category{}
+		if err := rows.Scan(&lotID, &c.ID, &c.Name, &c.Slug, &c.ParentID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		byLot[lotID] = append(byLot[lotID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return byLot, nil
+}
+
+// SetLotCategories replaces the lot categories in a single TX
+func (r *CategoryRepository) SetLotCategories(ctx context.Context, lotID uuid.UUID, categoryIDs []uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM lot_categories WHERE lot_id = $1`, lotID); err != nil {
+		return err
+	}
+	if len(categoryIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO lot_categories (lot_id, category_id)
+            SELECT $1, unnest($2::uuid[])
+            ON CONFLICT DO NOTHING
+        `, lotID, categoryIDs)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
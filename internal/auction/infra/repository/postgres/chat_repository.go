@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChatRepository implements domain.ChatRepository interface
+type ChatRepository struct {
+	pool *pgxpool.Pool
+	read db.Reader
+}
+
+// NewChatRepository creates a new instance of ChatRepository
+func NewChatRepository(pool *pgxpool.Pool) *ChatRepository {
+	return &ChatRepository{pool: pool, read: pool}
+}
+
+// WithReader returns a copy of the repository reading the chat history from reader
+func (r *ChatRepository) WithReader(reader db.Reader) *ChatRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+// Save inserts a chat msg
+func (r *ChatRepository) Save(ctx context.Context, msg *domain.ChatMessage) error {
+	query := `
+        INSERT INTO lot_chat_messages (id, lot_id, user_id, paddle, text, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := r.pool.Exec(ctx, query, msg.ID, msg.LotID, msg.UserID, msg.Paddle, msg.Text, msg.CreatedAt)
+	return err
+}
+
+// GetRecent returns the last limit msgs of the lot, oldest first
+func (r *ChatRepository) GetRecent(ctx context.Context, lotID uuid.UUID, limit int) ([]*domain.ChatMessage, error) {
+	query := `
+        SELECT id, lot_id, user_id, paddle, text, created_at
+        FROM lot_chat_messages
+        WHERE lot_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2
+    `
+	rows, err := r.read.Query(ctx, query, lotID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []*domain.ChatMessage
+	for rows.Next() {
+		m := &domain.ChatMessage{}
+		if err := rows.Scan(&m.ID, &m.LotID, &m.UserID, &m.Paddle, &m.Text, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.Reverse(msgs)
+	return msgs, nil
+}
+
+// SaveMute creates or replaces the mute of the user in the lot chat
+func (r *ChatRepository) SaveMute(ctx context.Context, mute *domain.ChatMute) error {
+	query := `
+        INSERT INTO lot_chat_mutes (lot_id, user_id, muted_by, reason, until, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (lot_id, user_id) DO UPDATE
+        SET muted_by = EXCLUDED.muted_by, reason = EXCLUDED.reason, until = EXCLUDED.until, created_at = EXCLUDED.created_at
+    `
+	_, err := r.pool.Exec(ctx, query, mute.LotID, mute.UserID, mute.MutedBy, mute.Reason, mute.Until, mute.CreatedAt)
+	return err
+}
+
+// GetMute returns the mute of the user in the lot chat, nil if none. it reads from the primary so a
+// fresh mute applies right away
+func (r *ChatRepository) GetMute(ctx context.Context, lotID, userID uuid.UUID) (*domain.ChatMute, error) {
+	query := `
+        SELECT lot_id, user_id, muted_by, reason, until, created_at
+        FROM lot_chat_mutes
+        WHERE lot_id = $1 AND user_id = $2
+    `
+	m := &domain.ChatMute{}
+	err := r.pool.QueryRow(ctx, query, lotID, userID).
+		Scan(&m.LotID, &m.UserID, &m.MutedBy, &m.Reason, &m.Until, &m.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeleteMute removes the mute of the user in the lot chat, if any
+func (r *ChatRepository) DeleteMute(ctx context.Context, lotID, userID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM lot_chat_mutes WHERE lot_id = $1 AND user_id = $2`, lotID, userID)
+	return err
+}
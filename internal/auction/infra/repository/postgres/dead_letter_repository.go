@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeadLetterRepository implements domain.DeadLetterRepository interface
+type DeadLetterRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDeadLetterRepository creates a new instance of DeadLetterRepository
+func NewDeadLetterRepository(pool *pgxpool.Pool) *DeadLetterRepository {
+	return &DeadLetterRepository{pool: pool}
+}
+
+// Save inserts a dead letter
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *domain.DeadLetter) error {
+	query := `
+        INSERT INTO ws_dead_letters (id, lot_id, user_id, client_id, message_type, payload, reason, dropped_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `
+	_, err := r.pool.Exec(ctx, query, letter.ID, letter.LotID, letter.UserID, letter.ClientID,
+		letter.MessageType, letter.Payload, letter.Reason, letter.DroppedAt)
+	return err
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FeeScheduleRepository implements domain.FeeScheduleRepository interface
+type FeeScheduleRepository struct {
+	pool     *pgxpool.Pool
+	read     db.Reader // read-only queries, the primary pool unless WithReader is used
+	fallback *domain.FeeSchedule
+}
+
+// NewFeeScheduleRepository creates a new instance of FeeScheduleRepository, fallback is returned
+// by GetForLot when the lot has no schedule and there is no default one stored
+func NewFeeScheduleRepository(pool *pgxpool.Pool, fallback *domain.FeeSchedule) *FeeScheduleRepository {
+	return &FeeScheduleRepository{pool: pool, read: pool, fallback: fallback}
+}
+
+// WithReader returns a copy of the repository reading schedules from reader
+func (r *FeeScheduleRepository) WithReader(reader db.Reader) *FeeScheduleRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+const feeScheduleColumns = `id, name, premium_tiers, flat_fee, tax_rate, is_default, created_at`
+
+// Save inserts a new schedule, a default schedule replaces the previous default one
+func (r *FeeScheduleRepository) Save(ctx context.Context, s *domain.FeeSchedule) error {
+	tiers, err := json.Marshal(s.PremiumTiers)
+	if err != nil {
+		return err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if s.IsDefault {
+		if _, err := tx.Exec(ctx, `UPDATE fee_schedules SET is_default = FALSE WHERE is_default`); err != nil {
+			return err
+		}
+	}
+	query := `
+        INSERT INTO fee_schedules (id, name, premium_tiers, flat_fee, tax_rate, is_default)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at
+    `
+	if err := tx.QueryRow(ctx, query, s.ID, s.Name, tiers, s.FlatFee, s.TaxRate, s.IsDefault).Scan(&s.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetByID returns a schedule, or domain.ErrFeeScheduleNotFound
+func (r *FeeScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.FeeSchedule, error) {
+	query := `SELECT ` + feeScheduleColumns + ` FROM fee_schedules WHERE id = $1`
+	s, err := scanFeeSchedule(r.read.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrFeeScheduleNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetAll returns every schedule ordered by name
+func (r *FeeScheduleRepository) GetAll(ctx context.Context) ([]*domain.FeeSchedule, error) {
+	query := `SELECT ` + feeScheduleColumns + ` FROM fee_schedules ORDER BY name ASC`
+	rows, err := r.read.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*domain.FeeSchedule
+	for rows.Next() {
+		s, err := scanFeeSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// GetForLot returns the lot schedule, else the default one, else the fallback schedule
+func (r *FeeScheduleRepository) GetForLot(ctx context.Context, lotID uuid.UUID) (*domain.FeeSchedule, error) {
+	query := `
+        SELECT ` + feeScheduleColumns + `
+        FROM fee_schedules
+        WHERE id = (SELECT fee_schedule_id FROM auction_lots WHERE id = $1) OR is_default
+        ORDER BY is_default ASC
+        LIMIT 1
+    `
+	s, err := scanFeeSchedule(r.read.QueryRow(ctx, query, lotID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.fallback, nil
+	}
+	return s, err
+}
+
+// SetLotSchedule assigns the schedule to the lot, or domain.ErrLotNotFound
+func (r *FeeScheduleRepository) SetLotSchedule(ctx context.Context, lotID uuid.UUID, scheduleID *uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE auction_lots SET fee_schedule_id = $2 WHERE id = $1`, lotID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrLotNotFound
+	}
+	return nil
+}
+
+func scanFeeSchedule(row pgx.Row) (*domain.FeeSchedule, error) {
+	s := &domain.FeeSchedule{}
+	var tiers []byte
+	if err := row.Scan(&s.ID, &s.Name, &tiers, &s.FlatFee, &s.TaxRate, &s.IsDefault, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tiers, &s.PremiumTiers); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LotAuctioneerRepository implements domain.LotAuctioneerRepository interface
+type LotAuctioneerRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLotAuctioneerRepository creates a new instance of LotAuctioneerRepository
+func NewLotAuctioneerRepository(pool *pgxpool.Pool) *LotAuctioneerRepository {
+	return &LotAuctioneerRepository{pool: pool}
+}
+
+// Assign inserts the assignment, an existing one is kept as it is
+func (r *LotAuctioneerRepository) Assign(ctx context.Context, a *domain.LotAuctioneer) error {
+	query := `
+        INSERT INTO lot_auctioneers (lot_id, user_id, assigned_by)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (lot_id, user_id) DO UPDATE SET lot_id = EXCLUDED.lot_id
+        RETURNING assigned_by, assigned_at
+    `
+	return r.pool.QueryRow(ctx, query, a.LotID, a.UserID, a.AssignedBy).Scan(&a.AssignedBy, &a.AssignedAt)
+}
+
+// Unassign removes the assignment, or returns domain.ErrAuctioneerNotAssigned
+func (r *LotAuctioneerRepository) Unassign(ctx context.Context, lotID, userID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lot_auctioneers WHERE lot_id = $1 AND user_id = $2`, lotID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAuctioneerNotAssigned
+	}
+	return nil
+}
+
+// GetByLotID returns the auctioneers of a lot in assignment order
+func (r *LotAuctioneerRepository) GetByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.LotAuctioneer, error) {
+	query := `
+        SELECT lot_id, user_id, assigned_by, assigned_at
+        FROM lot_auctioneers
+        WHERE lot_id = $1
+        ORDER BY assigned_at ASC
+    `
+	rows, err := r.pool.Query(ctx, query, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auctioneers []*domain.LotAuctioneer
+	for rows.Next() {
+		a := &domain.LotAuctioneer{}
+		if err := rows.Scan(&a.LotID, &a.UserID, &a.AssignedBy, &a.AssignedAt); err != nil {
+			return nil, err
+		}
+		auctioneers = append(auctioneers, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return auctioneers, nil
+}
+
+// IsAssigned reports if the user is assigned to the lot
+func (r *LotAuctioneerRepository) IsAssigned(ctx context.Context, lotID, userID uuid.UUID) (bool, error) {
+	var assigned bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM lot_auctioneers WHERE lot_id = $1 AND user_id = $2)`, lotID, userID).
+		Scan(&assigned)
+	return assigned, err
+}
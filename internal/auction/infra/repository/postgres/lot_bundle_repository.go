@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LotBundleRepository implements domain.LotBundleRepository interface
+type LotBundleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLotBundleRepository creates a new instance of LotBundleRepository
+func NewLotBundleRepository(pool *pgxpool.Pool) *LotBundleRepository {
+	return &LotBundleRepository{pool: pool}
+}
+
+// Create inserts the bundle and its lots in a transaction
+func (r *LotBundleRepository) Create(ctx context.Context, bundle *domain.LotBundle) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+        INSERT INTO lot_bundles (id, org_id, title, currency, state, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW())
+        RETURNING created_at
+    `
+	err = tx.QueryRow(ctx, query, bundle.ID, bundle.OrgID, bundle.Title, bundle.Currency, bundle.State,
+		bundle.CreatedBy).Scan(&bundle.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for _, lotID := range bundle.LotIDs {
+		if _, err := tx.Exec(ctx, `INSERT INTO lot_bundle_lots (bundle_id, lot_id) VALUES ($1, $2)`, bundle.ID, lotID); err != nil {
+			return fmt.Errorf("failed to add lot %s to bundle: %w", lotID, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// GetByID returns a bundle with its lots, limited to the organization of ctx if it has one
+func (r *LotBundleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LotBundle, error) {
+	query := `
+        SELECT id, org_id, title, currency, state, leading_bid_id, leading_user_id, leading_amount, leading_at,
+            individual_sum, created_by, created_at, settled_at
+        FROM lot_bundles
+        WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)
+    `
+	bundle := &domain.LotBundle{}
+	var leadingBidID, leadingUserID *uuid.UUID
+	var leadingAmount float64
+	var leadingAt *time.Time
+	err := r.pool.QueryRow(ctx, query, id, tenant.Filter(ctx)).Scan(&bundle.ID, &bundle.OrgID, &bundle.Title,
+		&bundle.Currency, &bundle.State, &leadingBidID, &leadingUserID, &leadingAmount, &leadingAt,
+		&bundle.IndividualSum, &bundle.CreatedBy, &bundle.CreatedAt, &bundle.SettledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrBundleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if leadingBidID != nil {
+		bundle.LeadingBid = &domain.BundleBid{
+			ID:        *leadingBidID,
+			BundleID:  bundle.ID,
+			UserID:    *leadingUserID,
+			Amount:    leadingAmount,
+			Timestamp: *leadingAt,
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT lot_id FROM lot_bundle_lots WHERE bundle_id = $1 ORDER BY lot_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.LotIDs, err = r.ids(rows); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// OpenWithLots returns the open bundles holding any of lotIDs
+func (r *LotBundleRepository) OpenWithLots(ctx context.Context, lotIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+        SELECT DISTINCT b.id
+        FROM lot_bundles b
+        JOIN lot_bundle_lots bl ON bl.bundle_id = b.id
+        WHERE b.state = $1 AND bl.lot_id = ANY($2)
+    `
+	rows, err := r.pool.Query(ctx, query, domain.BundleOpen, lotIDs)
+	if err != nil {
+		return nil, err
+	}
+	return r.ids(rows)
+}
+
+// PlaceBid inserts the bid and moves the leading bid of the bundle to it in a transaction, the
+// conditional update keeps the leading bid the highest one whatever the concurrent bids
+func (r *LotBundleRepository) PlaceBid(ctx context.Context, bid *domain.BundleBid) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+        UPDATE lot_bundles
+        SET leading_bid_id = $2, leading_user_id = $3, leading_amount = $4, leading_at = $5
+        WHERE id = $1 AND state = $6 AND (leading_bid_id IS NULL OR leading_amount < $4)
+    `
+	tag, err := tx.Exec(ctx, query, bid.BundleID, bid.ID, bid.UserID, bid.Amount, bid.Timestamp, domain.BundleOpen)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrBidAmountTooLow
+	}
+	query = `INSERT INTO lot_bundle_bids (id, bundle_id, user_id, amount, timestamp) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(ctx, query, bid.ID, bid.BundleID, bid.UserID, bid.Amount, bid.Timestamp); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ListSettleable returns the open bundles whose lots are all finished or cancelled
+func (r *LotBundleRepository) ListSettleable(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+        SELECT b.id
+        FROM lot_bundles b
+        WHERE b.state = $1 AND NOT EXISTS (
+            SELECT 1 FROM lot_bundle_lots bl
+            JOIN auction_lots l ON l.id = bl.lot_id
+            WHERE bl.bundle_id = b.id AND l.state NOT IN ($2, $3)
+        )
+    `
+	rows, err := r.pool.Query(ctx, query, domain.BundleOpen, domain.StateFinished, domain.StateCancelled)
+	if err != nil {
+		return nil, err
+	}
+	return r.ids(rows)
+}
+
+// Settle saves the state, individual sum and settlement time of a bundle still open
+func (r *LotBundleRepository) Settle(ctx context.Context, bundle *domain.LotBundle) (bool, error) {
+	query := `UPDATE lot_bundles SET state = $2, individual_sum = $3, settled_at = $4 WHERE id = $1 AND state = $5`
+	tag, err := r.pool.Exec(ctx, query, bundle.ID, bundle.State, bundle.IndividualSum, bundle.SettledAt, domain.BundleOpen)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ids reads and closes rows of a single uuid column
+func (r *LotBundleRepository) ids(rows pgx.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
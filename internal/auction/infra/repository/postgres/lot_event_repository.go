@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LotEventRepository implements domain.LotEventStore interface
+type LotEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLotEventRepository creates a new instance of LotEventRepository
+func NewLotEventRepository(pool *pgxpool.Pool) *LotEventRepository {
+	return &LotEventRepository{pool: pool}
+}
+
+// Append reserves len(events) sequence numbers on the lot row and inserts the events,
+// the row lock taken by the UPDATE serializes concurrent appends on the same lot
+func (r *LotEventRepository) Append(ctx context.Context, tx domain.Tx, lotID uuid.UUID, events ...domain.Event) ([]domain.Event, error) {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	var lastSeq int64
+	err = pgTx.QueryRow(ctx,
+		`UPDATE auction_lots SET event_seq = event_seq + $2 WHERE id = $1 RETURNING event_seq`,
+		lotID, len(events),
+	).Scan(&lastSeq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve event sequence: %w", err)
+	}
+
+	query := `
+        INSERT INTO lot_events (lot_id, seq, id, type, payload, occurred_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	stored := make([]domain.Event, len(events))
+	firstSeq := lastSeq - int64(len(events)) + 1
+	for i, event := range events {
+		event.Seq = firstSeq + int64(i)
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s payload: %w", event.Type, err)
+		}
+		if _, err = pgTx.Exec(ctx, query, lotID, event.Seq, event.ID, event.Type, payload, event.OccurredAt); err != nil {
+			return nil, err
+		}
+		stored[i] = event
+	}
+	return stored, nil
+}
+
+// GetSince returns the events of the lot after afterSeq, payloads are returned as json.RawMessage
+func (r *LotEventRepository) GetSince(ctx context.Context, lotID uuid.UUID, afterSeq int64, limit int) ([]domain.Event, error) {
+	query := `
+        SELECT id, seq, type, payload, occurred_at
+        FROM lot_events
+        WHERE lot_id = $1 AND seq > $2
+        ORDER BY seq ASC
+        LIMIT $3
+    `
+	rows, err := r.pool.Query(ctx, query, lotID, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		event := domain.Event{LotID: lotID}
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.Seq, &event.Type, &payload, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+		event.Payload = json.RawMessage(payload)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LotMediaRepository implements domain.LotMediaRepository interface
+type LotMediaRepository struct {
+	pool *pgxpool.Pool
+	read db.Reader
+}
+
+// NewLotMediaRepository creates a new instance of LotMediaRepository
+func NewLotMediaRepository(pool *pgxpool.Pool) *LotMediaRepository {
+	return &LotMediaRepository{pool: pool, read: pool}
+}
+
+// WithReader returns a copy of the repository listing media from reader
+func (r *LotMediaRepository) WithReader(reader db.Reader) *LotMediaRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+// Add inserts the media after the last one of the lot
+func (r *LotMediaRepository) Add(ctx context.Context, media *domain.LotMedia) error {
+	query := `
+        INSERT INTO lot_media (id, lot_id, kind, url, position)
+        SELECT $1, $2, $3, $4, COALESCE(MAX(position) + 1, 0)
+        FROM lot_media
+        WHERE lot_id = $2
+        RETURNING position, created_at
+    `
+	return r.pool.QueryRow(ctx, query, media.ID, media.LotID, media.Kind, media.URL).
+		Scan(&media.Position, &media.CreatedAt)
+}
+
+// GetByLotID returns the media of a lot in display order
+func (r *LotMediaRepository) GetByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.LotMedia, error) {
+	query := `
+        SELECT id, lot_id, kind, url, position, created_at
+        FROM lot_media
+        WHERE lot_id = $1
+        ORDER BY position ASC
+    `
+	rows, err := r.read.Query(ctx, query, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*domain.LotMedia
+	for rows.Next() {
+		m := &domain.LotMedia{}
+		if err := rows.Scan(&m.ID, &m.LotID, &m.Kind, &m.URL, &m.Position, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		media = append(media, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// Delete removes a media from the lot
+func (r *LotMediaRepository) Delete(ctx context.Context, lotID, mediaID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lot_media WHERE id = $1 AND lot_id = $2`, mediaID, lotID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMediaNotFound
+	}
+	return nil
+}
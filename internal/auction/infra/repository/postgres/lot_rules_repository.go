@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LotRulesRepository implements domain.LotRulesRepository interface
+type LotRulesRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLotRulesRepository creates a new instance of LotRulesRepository
+func NewLotRulesRepository(pool *pgxpool.Pool) *LotRulesRepository {
+	return &LotRulesRepository{pool: pool}
+}
+
+// Get returns the rules of the lot, or domain.ErrLotRulesNotFound
+func (r *LotRulesRepository) Get(ctx context.Context, lotID uuid.UUID) (*domain.LotRules, error) {
+	query := `
+        SELECT lot_id, min_increment, eligibility, allowed_countries, blocked_countries, terms_version, require_verified,
+            reserve_price, updated_by, updated_at
+        FROM lot_rules
+        WHERE lot_id = $1
+    `
+	rules := &domain.LotRules{}
+	err := r.pool.QueryRow(ctx, query, lotID).
+		Scan(&rules.LotID, &rules.MinIncrement, &rules.Eligibility, &rules.AllowedCountries, &rules.BlockedCountries,
+			&rules.TermsVersion, &rules.RequireVerified, &rules.ReservePrice, &rules.UpdatedBy, &rules.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrLotRulesNotFound
+		}
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Save upserts the rules of the lot
+func (r *LotRulesRepository) Save(ctx context.Context, rules *domain.LotRules) error {
+	query := `
+        INSERT INTO lot_rules (lot_id, min_increment, eligibility, allowed_countries, blocked_countries, terms_version,
+            require_verified, reserve_price, updated_by, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+        ON CONFLICT (lot_id) DO UPDATE SET
+            min_increment = EXCLUDED.min_increment,
+            eligibility = EXCLUDED.eligibility,
+            allowed_countries = EXCLUDED.allowed_countries,
+            blocked_countries = EXCLUDED.blocked_countries,
+            terms_version = EXCLUDED.terms_version,
+            require_verified = EXCLUDED.require_verified,
+            reserve_price = EXCLUDED.reserve_price,
+            updated_by = EXCLUDED.updated_by,
+            updated_at = EXCLUDED.updated_at
+        RETURNING updated_at
+    `
+	return r.pool.QueryRow(ctx, query, rules.LotID, rules.MinIncrement, rules.Eligibility,
+		rules.AllowedCountries, rules.BlockedCountries, rules.TermsVersion, rules.RequireVerified,
+		rules.ReservePrice, rules.UpdatedBy).
+		Scan(&rules.UpdatedAt)
+}
+
+// Delete removes the rules of the lot, or returns domain.ErrLotRulesNotFound
+func (r *LotRulesRepository) Delete(ctx context.Context, lotID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lot_rules WHERE lot_id = $1`, lotID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrLotRulesNotFound
+	}
+	return nil
+}
+
+// TermsAcceptanceRepository implements domain.TermsAcceptanceRepository interface
+type TermsAcceptanceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTermsAcceptanceRepository creates a new instance of TermsAcceptanceRepository
+func NewTermsAcceptanceRepository(pool *pgxpool.Pool) *TermsAcceptanceRepository {
+	return &TermsAcceptanceRepository{pool: pool}
+}
+
+// Save records the acceptance, the time of the first acceptance of the version is kept
+func (r *TermsAcceptanceRepository) Save(ctx context.Context, acceptance *domain.TermsAcceptance) error {
+	query := `
+        INSERT INTO terms_acceptance (user_id, lot_id, terms_version, client_ip, accepted_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (user_id, lot_id, terms_version) DO UPDATE SET accepted_at = terms_acceptance.accepted_at
+        RETURNING accepted_at
+    `
+	return r.pool.QueryRow(ctx, query, acceptance.UserID, acceptance.LotID, acceptance.TermsVersion, acceptance.ClientIP).
+		Scan(&acceptance.AcceptedAt)
+}
+
+// Accepted reports if the user accepted the version of the terms of the lot
+func (r *TermsAcceptanceRepository) Accepted(ctx context.Context, userID, lotID uuid.UUID, termsVersion string) (bool, error) {
+	var accepted bool
+	err := r.pool.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM terms_acceptance WHERE user_id = $1 AND lot_id = $2 AND terms_version = $3)
+    `, userID, lotID, termsVersion).Scan(&accepted)
+	return accepted, err
+}
+
+// BidderProfileRepository implements domain.BidderProfileProvider over the users table
+type BidderProfileRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBidderProfileRepository creates a new instance of BidderProfileRepository
+func NewBidderProfileRepository(pool *pgxpool.Pool) *BidderProfileRepository {
+	return &BidderProfileRepository{pool: pool}
+}
+
+// GetBidderProfile returns the registration time of the user
+func (r *BidderProfileRepository) GetBidderProfile(ctx context.Context, userID uuid.UUID) (*domain.BidderProfile, error) {
+	profile := &domain.BidderProfile{UserID: userID}
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE(created_at, NOW()) FROM users WHERE id = $1`, userID).Scan(&profile.RegisteredAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("bidder %s not found", userID)
+		}
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PaddleRepository implements domain.PaddleRepository interface
+type PaddleRepository struct {
+	pool *pgxpool.Pool
+	read db.Reader
+}
+
+// NewPaddleRepository creates a new instance of PaddleRepository
+func NewPaddleRepository(pool *pgxpool.Pool) *PaddleRepository {
+	return &PaddleRepository{pool: pool, read: pool}
+}
+
+// WithReader returns a copy of the repository reading paddles from reader
+func (r *PaddleRepository) WithReader(reader db.Reader) *PaddleRepository {
+	c := *r
+	c.read = reader
+	return &c
+}
+
+// Assign returns the paddle of the user on the lot, assigning the next free number on the first call.
+// callers must hold the lot row lock in tx (the lot is saved first) so numbers are not raced
+func (r *PaddleRepository) Assign(ctx context.Context, tx domain.Tx, lotID, userID uuid.UUID) (int, error) {
+	pgTx, err := pgxTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	query := `
+        INSERT INTO lot_paddles (lot_id, user_id, paddle_number)
+        SELECT $1, $2, COALESCE(MAX(paddle_number), 0) + 1 FROM lot_paddles WHERE lot_id = $1
+        ON CONFLICT (lot_id, user_id) DO UPDATE SET paddle_number = lot_paddles.paddle_number
+        RETURNING paddle_number
+    `
+	var paddle int
+	err = pgTx.QueryRow(ctx, query, lotID, userID).Scan(&paddle)
+	return paddle, err
+}
+
+// Get returns the paddle of the user on the lot, 0 if the user never bid on it
+func (r *PaddleRepository) Get(ctx context.Context, lotID, userID uuid.UUID) (int, error) {
+	query := `SELECT paddle_number FROM lot_paddles WHERE lot_id = $1 AND user_id = $2`
+	var paddle int
+	err := r.read.QueryRow(ctx, query, lotID, userID).Scan(&paddle)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return paddle, err
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres error codes of the transactions aborted by a concurrent one
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// Transactor implements domain.Transactor over a pgx pool, its transactions can only be
+// used with the repositories of this package
+type Transactor struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactor creates a new instance of Transactor
+func NewTransactor(pool *pgxpool.Pool) *Transactor {
+	return &Transactor{pool: pool}
+}
+
+// Begin implements domain.Transactor, pgx.Tx already satisfies domain.Tx
+func (t *Transactor) Begin(ctx context.Context) (domain.Tx, error) {
+	return t.pool.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// Retryable implements domain.Transactor. the transactions run at READ COMMITTED so the lot conflicts
+// show up as the optimistic version check of the lot failing with domain.ErrConcurrentLotUpdate,
+// serialization failures and deadlocks are retried too
+func (t *Transactor) Retryable(err error) bool {
+	if errors.Is(err, domain.ErrConcurrentLotUpdate) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected)
+}
+
+// pgxTx unwraps a transaction started by Transactor
+func pgxTx(tx domain.Tx) (pgx.Tx, error) {
+	pgTx, ok := tx.(pgx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("postgres repository: unsupported transaction type %T", tx)
+	}
+	return pgTx, nil
+}
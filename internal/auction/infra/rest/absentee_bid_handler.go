@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AbsenteeBidHandler exposes the absentee bids of the lots not opened yet through REST endpoints
+type AbsenteeBidHandler struct {
+	absenteeUC *application.AbsenteeBidUseCase
+}
+
+// NewAbsenteeBidHandler creates a new instance of AbsenteeBidHandler
+func NewAbsenteeBidHandler(absenteeUC *application.AbsenteeBidUseCase) *AbsenteeBidHandler {
+	return &AbsenteeBidHandler{absenteeUC: absenteeUC}
+}
+
+// submitAbsenteeBidRequest is the JSON body of POST /lots/:id/absentee-bids
+type submitAbsenteeBidRequest struct {
+	MaxAmount float64 `json:"max_amount"`
+	Currency  string  `json:"currency"`
+}
+
+// RegisterRoutes registers the absentee bid endpoints: requireBidder guards the submission, requireUser
+// the "me" endpoints and requireAdmin the listing of the absentee bids of a lot
+func (h *AbsenteeBidHandler) RegisterRoutes(router fiber.Router, requireBidder, requireUser, requireAdmin fiber.Handler) {
+	router.Post("/lots/:id/absentee-bids", requireBidder, h.submit)
+	router.Get("/lots/:id/absentee-bids", requireAdmin, h.listByLot)
+	router.Get("/users/me/absentee-bids", requireUser, h.listMine)
+	router.Get("/users/me/absentee-bids/:absenteeBidID", requireUser, h.get)
+	router.Delete("/users/me/absentee-bids/:absenteeBidID", requireUser, h.cancel)
+}
+
+// submit handles POST /lots/:id/absentee-bids, the bid is entered by the engine when the lot opens. the
+// bidder is checked against the country, terms and verification rules of the lot on submission
+func (h *AbsenteeBidHandler) submit(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req submitAbsenteeBidRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	bid, err := h.absenteeUC.Submit(c.UserContext(), application.SubmitAbsenteeBidDTO{
+		LotID:     lotID,
+		UserID:    httpserver.ClaimsFrom(c).UserID,
+		MaxAmount: req.MaxAmount,
+		Currency:  req.Currency,
+		ClientIP:  c.IP(),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(bid)
+}
+
+// listByLot handles GET /lots/:id/absentee-bids, in submission order
+func (h *AbsenteeBidHandler) listByLot(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	bids, err := h.absenteeUC.ListByLot(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bids)
+}
+
+// listMine handles GET /users/me/absentee-bids?limit=&offset=
+func (h *AbsenteeBidHandler) listMine(c *fiber.Ctx) error {
+	bids, err := h.absenteeUC.ListMine(c.UserContext(), application.UserBidsPageDTO{
+		UserID: httpserver.ClaimsFrom(c).UserID,
+		Limit:  c.QueryInt("limit"),
+		Offset: c.QueryInt("offset"),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bids)
+}
+
+// get handles GET /users/me/absentee-bids/:absenteeBidID, the status of an absentee bid
+func (h *AbsenteeBidHandler) get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("absenteeBidID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid absentee bid ID")
+	}
+	bid, err := h.absenteeUC.Get(c.UserContext(), id, httpserver.ClaimsFrom(c).UserID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bid)
+}
+
+// cancel handles DELETE /users/me/absentee-bids/:absenteeBidID, only the pending bids can be cancelled
+func (h *AbsenteeBidHandler) cancel(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("absenteeBidID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid absentee bid ID")
+	}
+	bid, err := h.absenteeUC.Cancel(c.UserContext(), id, httpserver.ClaimsFrom(c).UserID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bid)
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuctioneerHandler exposes the assignment of the auctioneers running the lots through REST endpoints
+type AuctioneerHandler struct {
+	auctioneersUC *application.LotAuctioneersUseCase
+}
+
+// NewAuctioneerHandler creates a new instance of AuctioneerHandler
+func NewAuctioneerHandler(auctioneersUC *application.LotAuctioneersUseCase) *AuctioneerHandler {
+	return &AuctioneerHandler{auctioneersUC: auctioneersUC}
+}
+
+// RegisterRoutes registers the auctioneer endpoints, all of them guarded by requireAssign
+func (h *AuctioneerHandler) RegisterRoutes(router fiber.Router, requireAssign fiber.Handler) {
+	router.Get("/lots/:id/auctioneers", requireAssign, h.listAuctioneers)
+	router.Put("/lots/:id/auctioneers/:userID", requireAssign, h.assignAuctioneer)
+	router.Delete("/lots/:id/auctioneers/:userID", requireAssign, h.unassignAuctioneer)
+}
+
+func (h *AuctioneerHandler) listAuctioneers(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	auctioneers, err := h.auctioneersUC.List(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(auctioneers)
+}
+
+// assignAuctioneer lets the user run the lot through the admin WS channel, assigning twice is a no-op
+func (h *AuctioneerHandler) assignAuctioneer(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	auctioneer, err := h.auctioneersUC.Assign(c.UserContext(), lotID, userID, httpserver.ClaimsFrom(c).UserID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(auctioneer)
+}
+
+func (h *AuctioneerHandler) unassignAuctioneer(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	if err := h.auctioneersUC.Unassign(c.UserContext(), lotID, userID); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// BidHandler exposes the administrative bid actions through REST endpoints
+type BidHandler struct {
+	auctionService application.AuctionService
+}
+
+// NewBidHandler creates a new instance of BidHandler
+func NewBidHandler(auctionService application.AuctionService) *BidHandler {
+	return &BidHandler{auctionService: auctionService}
+}
+
+// voidBidRequest is the optional JSON body of POST /lots/:id/bids/:bidID/void
+type voidBidRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RegisterRoutes registers the bid endpoints guarded by requireAdmin
+func (h *BidHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Post("/lots/:id/bids/:bidID/void", requireAdmin, h.voidBid)
+}
+
+// voidBid retracts a bid and returns the corrected lot state, also broadcast to the lot subscribers
+func (h *BidHandler) voidBid(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	bidID, err := uuid.Parse(c.Params("bidID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid bid ID")
+	}
+	var req voidBidRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+	state, err := h.auctionService.VoidBid(c.UserContext(), application.VoidBidDTO{
+		LotID:   lotID,
+		BidID:   bidID,
+		AdminID: httpserver.ClaimsFrom(c).UserID,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(state)
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CategoryHandler exposes the browsing taxonomy and the lots of each category through REST endpoints
+type CategoryHandler struct {
+	categoryUC     *application.CategoryUseCase
+	auctionService application.AuctionService
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler
+func NewCategoryHandler(categoryUC *application.CategoryUseCase, auctionService application.AuctionService) *CategoryHandler {
+	return &CategoryHandler{categoryUC: categoryUC, auctionService: auctionService}
+}
+
+// createCategoryRequest is the JSON body of POST /categories
+type createCategoryRequest struct {
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"` // optional, derived from name
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// setLotCategoriesRequest is the JSON body of PUT /lots/:id/categories
+type setLotCategoriesRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+}
+
+// RegisterRoutes registers the category endpoints, browsing is public, the taxonomy shared by every
+// organization is guarded by manageCatalog and the lot categorization by manageLots
+func (h *CategoryHandler) RegisterRoutes(router fiber.Router, manageCatalog, manageLots, optionalAuth fiber.Handler) {
+	router.Get("/categories", h.listCategories)
+	router.Post("/categories", manageCatalog, h.createCategory)
+	router.Get("/categories/:id/lots", optionalAuth, h.listCategoryLots)
+	router.Put("/lots/:id/categories", manageLots, h.setLotCategories)
+}
+
+func (h *CategoryHandler) listCategories(c *fiber.Ctx) error {
+	categories, err := h.categoryUC.List(c.UserContext())
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(categories)
+}
+
+func (h *CategoryHandler) createCategory(c *fiber.Ctx) error {
+	var req createCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	category, err := h.categoryUC.Create(c.UserContext(), application.CreateCategoryDTO{
+		Name:     req.Name,
+		Slug:     req.Slug,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(category)
+}
+
+// listCategoryLots handles GET /categories/:id/lots?state=&limit=&offset=, :id is an ID or a slug
+// and the lots of its subcategories are included
+func (h *CategoryHandler) listCategoryLots(c *fiber.Ctx) error {
+	lots, err := h.auctionService.SearchLots(c.UserContext(), application.SearchLotsDTO{
+		State:    c.Query("state"),
+		Category: c.Params("id"),
+		Limit:    c.QueryInt("limit"),
+		Offset:   c.QueryInt("offset"),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(forViewer(c, lots))
+}
+
+func (h *CategoryHandler) setLotCategories(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req setLotCategoriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	categories, err := h.categoryUC.SetLotCategories(c.UserContext(), lotID, req.CategoryIDs)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(categories)
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ChatHandler exposes the lot chat history and its moderation through REST endpoints
+type ChatHandler struct {
+	chatUC *application.ChatUseCase
+}
+
+// NewChatHandler creates a new instance of ChatHandler
+func NewChatHandler(chatUC *application.ChatUseCase) *ChatHandler {
+	return &ChatHandler{chatUC: chatUC}
+}
+
+// muteRequest is the JSON body of POST /lots/:id/chat/mutes
+type muteRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+	// DurationSeconds of the mute, 0 mutes until unmuted
+	DurationSeconds int    `json:"duration_seconds"`
+	Reason          string `json:"reason"`
+}
+
+// RegisterRoutes registers the chat endpoints, the history is public and the mutes are guarded by
+// requireModerator, checked on the lot of the route
+func (h *ChatHandler) RegisterRoutes(router fiber.Router, requireModerator fiber.Handler) {
+	router.Get("/lots/:id/chat", h.history)
+	router.Post("/lots/:id/chat/mutes", requireModerator, h.mute)
+	router.Delete("/lots/:id/chat/mutes/:userID", requireModerator, h.unmute)
+}
+
+func (h *ChatHandler) history(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	msgs, err := h.chatUC.History(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(msgs)
+}
+
+// mute silences a user in the lot chat, the user keeps receiving the chat
+func (h *ChatHandler) mute(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req muteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.UserID == uuid.Nil {
+		return fiber.NewError(fiber.StatusBadRequest, "user_id is required")
+	}
+	mute, err := h.chatUC.Mute(c.UserContext(), application.MuteChatDTO{
+		LotID:    lotID,
+		UserID:   req.UserID,
+		AdminID:  httpserver.ClaimsFrom(c).UserID,
+		Duration: time.Duration(req.DurationSeconds) * time.Second,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(mute)
+}
+
+func (h *ChatHandler) unmute(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	if err := h.chatUC.Unmute(c.UserContext(), lotID, userID); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
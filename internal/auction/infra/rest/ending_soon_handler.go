@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EndingSoonHandler exposes the ending-soon feed of the homepage tickers through REST endpoints
+type EndingSoonHandler struct {
+	endingSoon *application.EndingSoonUseCase
+}
+
+// NewEndingSoonHandler creates a new instance of EndingSoonHandler
+func NewEndingSoonHandler(endingSoon *application.EndingSoonUseCase) *EndingSoonHandler {
+	return &EndingSoonHandler{endingSoon: endingSoon}
+}
+
+// RegisterRoutes registers the public ending-soon endpoint, optionalAuth scopes the feed to the
+// organization of the caller
+func (h *EndingSoonHandler) RegisterRoutes(router fiber.Router, optionalAuth fiber.Handler) {
+	router.Get("/lots/ending-soon", optionalAuth, h.getEndingSoon)
+}
+
+// getEndingSoon handles GET /lots/ending-soon?minutes=&limit=, minutes defaults to the configured window and
+// limit keeps the lots closing first
+func (h *EndingSoonHandler) getEndingSoon(c *fiber.Ctx) error {
+	minutes := c.QueryInt("minutes")
+	if minutes < 0 || time.Duration(minutes)*time.Minute > application.MaxEndingSoonWindow {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid minutes")
+	}
+	limit := c.QueryInt("limit")
+	if limit < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+	}
+	feed, err := h.endingSoon.Execute(c.UserContext(), time.Duration(minutes)*time.Minute)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	if limit > 0 {
+		feed.Lots = feed.Lots[:min(len(feed.Lots), limit)]
+	}
+	return c.JSON(feed)
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// FeeScheduleHandler exposes the management of fee schedules through admin REST endpoints
+type FeeScheduleHandler struct {
+	feeScheduleUC *application.FeeScheduleUseCase
+}
+
+// NewFeeScheduleHandler creates a new instance of FeeScheduleHandler
+func NewFeeScheduleHandler(feeScheduleUC *application.FeeScheduleUseCase) *FeeScheduleHandler {
+	return &FeeScheduleHandler{feeScheduleUC: feeScheduleUC}
+}
+
+// createFeeScheduleRequest is the JSON body of POST /fee-schedules
+type createFeeScheduleRequest struct {
+	Name         string           `json:"name"`
+	PremiumTiers []domain.FeeTier `json:"premium_tiers"`
+	FlatFee      float64          `json:"flat_fee"`
+	TaxRate      float64          `json:"tax_rate"`
+	IsDefault    bool             `json:"is_default"`
+}
+
+// setLotFeeScheduleRequest is the JSON body of PUT /lots/:id/fee-schedule, null restores the default schedule
+type setLotFeeScheduleRequest struct {
+	FeeScheduleID *uuid.UUID `json:"fee_schedule_id"`
+}
+
+// RegisterRoutes registers the fee schedule endpoints, the schedules shared by every organization are
+// created under manageCatalog, listing and assigning them to lots is guarded by manageLots
+func (h *FeeScheduleHandler) RegisterRoutes(router fiber.Router, manageCatalog, manageLots fiber.Handler) {
+	router.Get("/fee-schedules", manageLots, h.listFeeSchedules)
+	router.Post("/fee-schedules", manageCatalog, h.createFeeSchedule)
+	router.Put("/lots/:id/fee-schedule", manageLots, h.setLotFeeSchedule)
+}
+
+func (h *FeeScheduleHandler) listFeeSchedules(c *fiber.Ctx) error {
+	schedules, err := h.feeScheduleUC.List(c.UserContext())
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(schedules)
+}
+
+func (h *FeeScheduleHandler) createFeeSchedule(c *fiber.Ctx) error {
+	var req createFeeScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	schedule, err := h.feeScheduleUC.Create(c.UserContext(), application.CreateFeeScheduleDTO{
+		Name:         req.Name,
+		PremiumTiers: req.PremiumTiers,
+		FlatFee:      req.FlatFee,
+		TaxRate:      req.TaxRate,
+		IsDefault:    req.IsDefault,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(schedule)
+}
+
+func (h *FeeScheduleHandler) setLotFeeSchedule(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req setLotFeeScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	schedule, err := h.feeScheduleUC.SetLotSchedule(c.UserContext(), lotID, req.FeeScheduleID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(schedule)
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// LeaderboardHandler exposes the lot leaderboards through REST endpoints
+type LeaderboardHandler struct {
+	leaderboards *application.LeaderboardUseCase
+}
+
+// NewLeaderboardHandler creates a new instance of LeaderboardHandler
+func NewLeaderboardHandler(leaderboards *application.LeaderboardUseCase) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboards: leaderboards}
+}
+
+// RegisterRoutes registers the leaderboard endpoints, they are public and optionalAuth identifies the
+// callers allowed to see the user of each entry
+func (h *LeaderboardHandler) RegisterRoutes(router fiber.Router, optionalAuth fiber.Handler) {
+	router.Get("/lots/:id/leaderboard", optionalAuth, h.getLeaderboard)
+}
+
+// getLeaderboard handles GET /lots/:id/leaderboard, the same board broadcast as server_leaderboard
+func (h *LeaderboardHandler) getLeaderboard(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	board, err := h.leaderboards.Get(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	viewerID, admin := viewerOf(c)
+	return c.JSON(board.ForViewer(viewerID, admin))
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// LotBundleHandler exposes the bundles of lots bid on as a whole through REST endpoints
+type LotBundleHandler struct {
+	bundleUC *application.LotBundleUseCase
+}
+
+// NewLotBundleHandler creates a new instance of LotBundleHandler
+func NewLotBundleHandler(bundleUC *application.LotBundleUseCase) *LotBundleHandler {
+	return &LotBundleHandler{bundleUC: bundleUC}
+}
+
+// createBundleRequest is the JSON body of POST /bundles
+type createBundleRequest struct {
+	Title  string      `json:"title"`
+	LotIDs []uuid.UUID `json:"lot_ids"`
+}
+
+// placeBundleBidRequest is the JSON body of POST /bundles/:id/bids
+type placeBundleBidRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// RegisterRoutes registers the bundle endpoints: requireAdmin guards the creation, requireBidder the
+// bids and optionalAuth the public reads
+func (h *LotBundleHandler) RegisterRoutes(router fiber.Router, requireAdmin, requireBidder, optionalAuth fiber.Handler) {
+	router.Post("/bundles", requireAdmin, h.create)
+	router.Get("/bundles/:id", optionalAuth, h.get)
+	router.Post("/bundles/:id/bids", requireBidder, h.placeBid)
+}
+
+// create handles POST /bundles, the bundle takes bids until its first lot closes
+func (h *LotBundleHandler) create(c *fiber.Ctx) error {
+	var req createBundleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	bundle, err := h.bundleUC.Create(c.UserContext(), application.CreateBundleDTO{
+		Title:     req.Title,
+		LotIDs:    req.LotIDs,
+		CreatedBy: httpserver.ClaimsFrom(c).UserID,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(bundle)
+}
+
+// get handles GET /bundles/:id, the bundle with the leading bids of its lots
+func (h *LotBundleHandler) get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid bundle ID")
+	}
+	bundle, err := h.bundleUC.Get(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bundle.ForViewer(viewerOf(c)))
+}
+
+// placeBid handles POST /bundles/:id/bids, the amount must beat the leading bundle bid and the sum of
+// the leading bids of the lots
+func (h *LotBundleHandler) placeBid(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid bundle ID")
+	}
+	var req placeBundleBidRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	bundle, err := h.bundleUC.PlaceBid(c.UserContext(), application.PlaceBundleBidDTO{
+		BundleID: id,
+		UserID:   httpserver.ClaimsFrom(c).UserID,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(bundle)
+}
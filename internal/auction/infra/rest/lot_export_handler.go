@@ -0,0 +1,213 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LotExportHandler exposes the exports of the auction results for back-office reconciliation, an
+// auction is a lot like in the /ws/auction/<lot id> endpoint
+type LotExportHandler struct {
+	exportUC *application.LotExportUseCase
+}
+
+// NewLotExportHandler creates a new instance of LotExportHandler
+func NewLotExportHandler(exportUC *application.LotExportUseCase) *LotExportHandler {
+	return &LotExportHandler{exportUC: exportUC}
+}
+
+// exportCSVHeader are the columns of a CSV export, the first row is the lot result (its bid_id, user_id,
+// paddle and amount are the winning ones) and the next ones are its bids
+var exportCSVHeader = []string{"record", "lot_id", "title", "state", "currency", "bid_id", "user_id", "paddle",
+	"amount", "timestamp", "winning", "voided_at", "void_reason", "received_at"}
+
+// mimeNDJSON is the content type of the event exports, a JSON event per line
+const mimeNDJSON = "application/x-ndjson"
+
+// RegisterRoutes registers the export endpoints guarded by requireAdmin
+func (h *LotExportHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Get("/auctions/:id/export", requireAdmin, h.export)
+	router.Get("/lots/:id/events.ndjson", requireAdmin, h.exportEvents)
+}
+
+// export streams the result and the bids of a lot as CSV or JSON, the bids are written as they're read.
+// once the body started an error can only cut it short, it's logged
+func (h *LotExportHandler) export(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	format := c.Query("format", application.ExportFormatCSV)
+	if format != application.ExportFormatCSV && format != application.ExportFormatJSON {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be csv or json")
+	}
+	// the body is written after the handler returns, the fiber ctx must not be used by the stream
+	ctx := c.UserContext()
+	result, err := h.exportUC.Result(ctx, lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+
+	filename := "auction-" + lotID.String() + "." + format
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	if format == application.ExportFormatCSV {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	}
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var err error
+		if format == application.ExportFormatCSV {
+			err = h.writeCSV(ctx, w, result)
+		} else {
+			err = h.writeJSON(ctx, w, result)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("Lot export failed", zap.String("lotID", lotID.String()), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// exportEvents streams the event history of a lot as NDJSON, ordered by seq. ?after_seq= resumes an
+// interrupted export after the last event received
+func (h *LotExportHandler) exportEvents(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	afterSeq := int64(0)
+	if raw := c.Query("after_seq"); raw != "" {
+		if afterSeq, err = strconv.ParseInt(raw, 10, 64); err != nil || afterSeq < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid after_seq")
+		}
+	}
+	// the body is written after the handler returns, the fiber ctx must not be used by the stream
+	ctx := c.UserContext()
+	if err := h.exportUC.CheckLot(ctx, lotID); err != nil {
+		return toHTTPError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="lot-`+lotID.String()+`-events.ndjson"`)
+	c.Set(fiber.HeaderContentType, mimeNDJSON)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := h.exportUC.WriteEvents(ctx, lotID, afterSeq, ndjsonEventWriter{w: w})
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("Lot events export failed", zap.String("lotID", lotID.String()), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+func (h *LotExportHandler) writeCSV(ctx context.Context, w *bufio.Writer, result *application.LotResultDTO) error {
+	out := csv.NewWriter(w)
+	resultRow := []string{"result", result.LotID.String(), result.Title, result.State, result.Currency,
+		"", "", "", formatAmount(result.FinalPrice), result.EndTime.Format(time.RFC3339Nano), "", "", "", ""}
+	if result.WinningBidID != nil {
+		resultRow[5] = result.WinningBidID.String()
+		resultRow[6] = result.WinnerID.String()
+		resultRow[7] = strconv.Itoa(result.WinnerPaddle)
+	}
+	if err := out.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	if err := out.Write(resultRow); err != nil {
+		return err
+	}
+	err := h.exportUC.WriteBids(ctx, result, csvBidWriter{out: out, lotID: result.LotID.String(), currency: result.Currency})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (h *LotExportHandler) writeJSON(ctx context.Context, w *bufio.Writer, result *application.LotResultDTO) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	w.WriteString(`{"result":`)
+	w.Write(data)
+	w.WriteString(`,"bids":[`)
+	if err := h.exportUC.WriteBids(ctx, result, &jsonBidWriter{w: w}); err != nil {
+		return err
+	}
+	w.WriteString("]}\n")
+	return w.Flush()
+}
+
+// csvBidWriter writes the bids of an export as CSV rows, they reach the client every time the buffer
+// of the stream fills
+type csvBidWriter struct {
+	out      *csv.Writer
+	lotID    string
+	currency string
+}
+
+func (cw csvBidWriter) WriteBid(bid *application.ExportedBidDTO) error {
+	voidedAt, receivedAt := "", ""
+	if bid.VoidedAt != nil {
+		voidedAt = bid.VoidedAt.Format(time.RFC3339Nano)
+	}
+	if bid.ReceivedAt != nil {
+		receivedAt = bid.ReceivedAt.Format(time.RFC3339Nano)
+	}
+	return cw.out.Write([]string{"bid", cw.lotID, "", "", cw.currency, bid.BidID.String(), bid.UserID.String(),
+		strconv.Itoa(bid.Paddle), formatAmount(bid.Amount), bid.Timestamp.Format(time.RFC3339Nano),
+		strconv.FormatBool(bid.Winning), voidedAt, bid.VoidReason, receivedAt})
+}
+
+// jsonBidWriter writes the bids of an export as the elements of a JSON array
+type jsonBidWriter struct {
+	w       *bufio.Writer
+	written int
+}
+
+func (jw *jsonBidWriter) WriteBid(bid *application.ExportedBidDTO) error {
+	data, err := json.Marshal(bid)
+	if err != nil {
+		return err
+	}
+	if jw.written > 0 {
+		jw.w.WriteByte(',')
+	}
+	jw.written++
+	_, err = jw.w.Write(data)
+	return err
+}
+
+// ndjsonEventWriter writes the events of an export as JSON lines
+type ndjsonEventWriter struct {
+	w *bufio.Writer
+}
+
+func (nw ndjsonEventWriter) WriteEvent(event *domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	nw.w.Write(data)
+	return nw.w.WriteByte('\n')
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxLongPollWait caps the wait param of long-poll requests
+const maxLongPollWait = 60 * time.Second
+
+// LotHandler exposes the auction lots through REST endpoints
+type LotHandler struct {
+	auctionService application.AuctionService
+}
+
+// NewLotHandler creates a new instance of LotHandler
+func NewLotHandler(auctionService application.AuctionService) *LotHandler {
+	return &LotHandler{auctionService: auctionService}
+}
+
+// updateLotRequest is the JSON body of PATCH /lots/:id, omitted fields are left as they are
+type updateLotRequest struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	InitialPrice *float64   `json:"initial_price"`
+	EndTime      *time.Time `json:"end_time"`
+}
+
+// RegisterRoutes registers the lot endpoints on router (usually the /api group), reads are public
+// and optionalAuth identifies the callers allowed to see the last bidder identity, edits are guarded by requireAdmin
+func (h *LotHandler) RegisterRoutes(router fiber.Router, requireAdmin, optionalAuth fiber.Handler) {
+	router.Get("/lots/search", optionalAuth, h.searchLots)
+	router.Get("/lots/:id/state", optionalAuth, h.getLotState)
+	router.Patch("/lots/:id", requireAdmin, h.updateLot)
+}
+
+// searchLots handles GET /lots/search?q=&state=&category=&limit=&offset=, category is an ID or a slug
+func (h *LotHandler) searchLots(c *fiber.Ctx) error {
+	lots, err := h.auctionService.SearchLots(c.UserContext(), application.SearchLotsDTO{
+		Query:    c.Query("q"),
+		State:    c.Query("state"),
+		Category: c.Query("category"),
+		Limit:    c.QueryInt("limit"),
+		Offset:   c.QueryInt("offset"),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(forViewer(c, lots))
+}
+
+// getLotState returns the lot state, with ?wait=30s&since_seq=N it long-polls: the request
+// blocks until the lot seq is greater than N or the wait elapses, then returns the latest state
+func (h *LotHandler) getLotState(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	wait, err := parseWait(c.Query("wait"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid wait, use a duration like 30s")
+	}
+
+	var state *application.LotStateDTO
+	if raw := c.Query("since_seq"); raw != "" && wait > 0 {
+		sinceSeq, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || sinceSeq < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid since_seq")
+		}
+		ctx, cancel := context.WithTimeout(c.UserContext(), wait)
+		defer cancel()
+		state, err = h.auctionService.WaitForLotChange(ctx, lotID, sinceSeq)
+	} else {
+		state, err = h.auctionService.GetLotState(c.UserContext(), lotID)
+	}
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(state.ForViewer(viewerOf(c)))
+}
+
+// updateLot edits a lot not opened yet and returns its new state, also pushed to the clients previewing it
+func (h *LotHandler) updateLot(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req updateLotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	state, err := h.auctionService.UpdateLot(c.UserContext(), application.UpdateLotDTO{
+		LotID:        lotID,
+		UpdatedBy:    httpserver.ClaimsFrom(c).UserID,
+		Title:        req.Title,
+		Description:  req.Description,
+		InitialPrice: req.InitialPrice,
+		EndTime:      req.EndTime,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(state)
+}
+
+// parseWait accepts a Go duration ("30s") or plain seconds ("30"), capped to maxLongPollWait
+func parseWait(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return 0, err
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait < 0 {
+		return 0, errors.New("negative wait")
+	}
+	return min(wait, maxLongPollWait), nil
+}
+
+// viewerOf returns the caller user ID and if it's an admin, uuid.Nil for anonymous callers
+func viewerOf(c *fiber.Ctx) (uuid.UUID, bool) {
+	claims := httpserver.ClaimsFrom(c)
+	if claims == nil {
+		return uuid.Nil, false
+	}
+	return claims.UserID, claims.Can(auth.PermViewBidders)
+}
+
+// forViewer hides the last bidder identity of the lots from callers other than admins and the bidder
+func forViewer(c *fiber.Ctx, lots []*application.LotStateDTO) []*application.LotStateDTO {
+	viewerID, admin := viewerOf(c)
+	visible := make([]*application.LotStateDTO, 0, len(lots))
+	for _, lot := range lots {
+		visible = append(visible, lot.ForViewer(viewerID, admin))
+	}
+	return visible
+}
+
+// toHTTPError maps application and domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrLotNotFound),
+		errors.Is(err, domain.ErrMediaNotFound),
+		errors.Is(err, domain.ErrBidNotFound),
+		errors.Is(err, domain.ErrCategoryNotFound),
+		errors.Is(err, domain.ErrFeeScheduleNotFound),
+		errors.Is(err, application.ErrReplayNotFound),
+		errors.Is(err, domain.ErrAuctioneerNotAssigned),
+		errors.Is(err, domain.ErrLotRulesNotFound),
+		errors.Is(err, domain.ErrAbsenteeBidNotFound),
+		errors.Is(err, domain.ErrBundleNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidLot),
+		errors.Is(err, application.ErrInvalidMedia),
+		errors.Is(err, application.ErrInvalidSearch),
+		errors.Is(err, application.ErrInvalidCategory),
+		errors.Is(err, domain.ErrInvalidFeeSchedule),
+		errors.Is(err, application.ErrInvalidReplay),
+		errors.Is(err, application.ErrInvalidChatMessage),
+		errors.Is(err, application.ErrInvalidChatMute),
+		errors.Is(err, application.ErrInvalidMaintenanceNotice),
+		errors.Is(err, application.ErrInvalidLotRules),
+		errors.Is(err, application.ErrInvalidAbsenteeBid),
+		errors.Is(err, application.ErrInvalidBundle),
+		domain.AmountErrorCode(err) != "":
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrChatMuted),
+		errors.Is(err, domain.ErrChatMessageRejected),
+		errors.Is(err, application.ErrBidNotAllowed):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, application.ErrChatRateLimited):
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	case errors.Is(err, domain.ErrCategorySlugTaken),
+		errors.Is(err, domain.ErrBidAlreadyVoided),
+		errors.Is(err, domain.ErrLotNotActive),
+		errors.Is(err, domain.ErrLotNotOpenYet),
+		errors.Is(err, domain.ErrLotAlreadyStartedOrFinished),
+		errors.Is(err, domain.ErrLotPaused),
+		errors.Is(err, domain.ErrLotNotPaused),
+		errors.Is(err, domain.ErrInvalidTransition),
+		errors.Is(err, domain.ErrBiddingClosed),
+		errors.Is(err, domain.ErrConcurrentLotUpdate),
+		errors.Is(err, domain.ErrAbsenteeBidExists),
+		errors.Is(err, application.ErrAbsenteeBidProcessed),
+		errors.Is(err, domain.ErrBundleNotOpen),
+		errors.Is(err, domain.ErrLotInOpenBundle),
+		errors.Is(err, domain.ErrBidAmountTooLow):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrMediaUploadDisabled):
+		return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+	case errors.Is(err, application.ErrLotBusy),
+		errors.Is(err, application.ErrMaintenanceMode):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// LotRulesHandler exposes the rules of the lots through REST endpoints
+type LotRulesHandler struct {
+	rulesUC *application.LotRulesUseCase
+}
+
+// NewLotRulesHandler creates a new instance of LotRulesHandler
+func NewLotRulesHandler(rulesUC *application.LotRulesUseCase) *LotRulesHandler {
+	return &LotRulesHandler{rulesUC: rulesUC}
+}
+
+// RegisterRoutes registers the lot rules endpoints, all of them guarded by requireAdmin
+func (h *LotRulesHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Get("/lots/:id/rules", requireAdmin, h.getRules)
+	router.Put("/lots/:id/rules", requireAdmin, h.setRules)
+	router.Delete("/lots/:id/rules", requireAdmin, h.deleteRules)
+}
+
+func (h *LotRulesHandler) getRules(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	rules, err := h.rulesUC.Get(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(rules)
+}
+
+// setRules replaces the rules of the lot, they apply to the next bids
+func (h *LotRulesHandler) setRules(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var input application.SetLotRulesDTO
+	if err := c.BodyParser(&input); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	rules, err := h.rulesUC.Set(c.UserContext(), lotID, input, httpserver.ClaimsFrom(c).UserID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(rules)
+}
+
+func (h *LotRulesHandler) deleteRules(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	if err := h.rulesUC.Delete(c.UserContext(), lotID); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
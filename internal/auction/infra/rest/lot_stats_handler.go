@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// LotStatsHandler exposes the bid statistics of the lots through REST endpoints
+type LotStatsHandler struct {
+	statsUC *application.LotStatsUseCase
+}
+
+// NewLotStatsHandler creates a new instance of LotStatsHandler
+func NewLotStatsHandler(statsUC *application.LotStatsUseCase) *LotStatsHandler {
+	return &LotStatsHandler{statsUC: statsUC}
+}
+
+// RegisterRoutes registers the public stats endpoints, the stats don't identify the bidders
+func (h *LotStatsHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/lots/:id/stats", h.getLotStats)
+	router.Get("/lots/:id/price-history", h.getPriceHistory)
+}
+
+// getLotStats handles GET /lots/:id/stats?points=, points caps the price history length
+func (h *LotStatsHandler) getLotStats(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	points := c.QueryInt("points")
+	if points < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid points")
+	}
+	stats, err := h.statsUC.Execute(c.UserContext(), application.LotStatsQueryDTO{LotID: lotID, Points: points})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(stats)
+}
+
+// getPriceHistory handles GET /lots/:id/price-history?after=&points=, after is an RFC 3339 time and only
+// the later points are returned
+func (h *LotStatsHandler) getPriceHistory(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var after time.Time
+	if raw := c.Query("after"); raw != "" {
+		if after, err = time.Parse(time.RFC3339Nano, raw); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid after, use an RFC 3339 time")
+		}
+	}
+	points := c.QueryInt("points")
+	if points < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid points")
+	}
+	history, err := h.statsUC.PriceHistory(c.UserContext(), application.PriceHistoryQueryDTO{LotID: lotID, After: after, Points: points})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(history)
+}
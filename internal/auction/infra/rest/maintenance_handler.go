@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceHandler exposes the maintenance announcements and the read-only mode through admin REST endpoints
+type MaintenanceHandler struct {
+	maintenanceUC *application.MaintenanceUseCase
+}
+
+// NewMaintenanceHandler creates a new instance of MaintenanceHandler
+func NewMaintenanceHandler(maintenanceUC *application.MaintenanceUseCase) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceUC: maintenanceUC}
+}
+
+// maintenanceNoticeRequest is the JSON body of POST /admin/maintenance-notices
+type maintenanceNoticeRequest struct {
+	Message  string     `json:"message"`
+	Severity string     `json:"severity"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	ReadOnly bool       `json:"read_only"`
+}
+
+// maintenanceModeRequest is the JSON body of PUT /admin/maintenance-mode
+type maintenanceModeRequest struct {
+	ReadOnly *bool `json:"read_only"`
+}
+
+// RegisterRoutes registers the maintenance endpoints guarded by requireOperator
+func (h *MaintenanceHandler) RegisterRoutes(router fiber.Router, requireOperator fiber.Handler) {
+	router.Post("/admin/maintenance-notices", requireOperator, h.announce)
+	router.Get("/admin/maintenance-mode", requireOperator, h.mode)
+	router.Put("/admin/maintenance-mode", requireOperator, h.setMode)
+}
+
+func (h *MaintenanceHandler) announce(c *fiber.Ctx) error {
+	var req maintenanceNoticeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	notice, err := h.maintenanceUC.Announce(c.UserContext(), application.AnnounceMaintenanceDTO{
+		Message:  req.Message,
+		Severity: req.Severity,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		ReadOnly: req.ReadOnly,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(notice)
+}
+
+func (h *MaintenanceHandler) mode(c *fiber.Ctx) error {
+	return c.JSON(h.maintenanceUC.Mode())
+}
+
+func (h *MaintenanceHandler) setMode(c *fiber.Ctx) error {
+	var req maintenanceModeRequest
+	if err := c.BodyParser(&req); err != nil || req.ReadOnly == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "read_only is required")
+	}
+	return c.JSON(h.maintenanceUC.SetMode(c.UserContext(), *req.ReadOnly))
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// MediaHandler exposes the lot media attachments through REST endpoints
+type MediaHandler struct {
+	mediaUC *application.LotMediaUseCase
+}
+
+// NewMediaHandler creates a new instance of MediaHandler
+func NewMediaHandler(mediaUC *application.LotMediaUseCase) *MediaHandler {
+	return &MediaHandler{mediaUC: mediaUC}
+}
+
+// attachMediaRequest is the JSON body of POST /lots/:id/media
+type attachMediaRequest struct {
+	Kind string `json:"kind"` // image or video
+	URL  string `json:"url"`
+}
+
+// RegisterRoutes registers the media endpoints, listing is public and changes are guarded by requireAdmin
+func (h *MediaHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Get("/lots/:id/media", h.listMedia)
+	router.Post("/lots/:id/media", requireAdmin, h.attachMedia)
+	router.Post("/lots/:id/media/upload", requireAdmin, h.uploadMedia)
+	router.Delete("/lots/:id/media/:mediaID", requireAdmin, h.removeMedia)
+}
+
+func (h *MediaHandler) listMedia(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	media, err := h.mediaUC.List(c.UserContext(), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(media)
+}
+
+func (h *MediaHandler) attachMedia(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	var req attachMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	media, err := h.mediaUC.Attach(c.UserContext(), application.AttachMediaDTO{
+		LotID: lotID,
+		Kind:  req.Kind,
+		URL:   req.URL,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(media)
+}
+
+// uploadMedia expects a multipart form with the file in the "file" field
+func (h *MediaHandler) uploadMedia(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "missing file")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "unreadable file")
+	}
+	defer file.Close()
+
+	media, err := h.mediaUC.Upload(c.UserContext(), application.UploadMediaDTO{
+		LotID:       lotID,
+		Filename:    header.Filename,
+		ContentType: header.Header.Get(fiber.HeaderContentType),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(media)
+}
+
+func (h *MediaHandler) removeMedia(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	mediaID, err := uuid.Parse(c.Params("mediaID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid media ID")
+	}
+	if err := h.mediaUC.Remove(c.UserContext(), lotID, mediaID); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ReplayHandler exposes the auction replays through admin REST endpoints, a started replay is watched
+// connecting to /ws/auction/<replay id>
+type ReplayHandler struct {
+	auctionService application.AuctionService
+}
+
+// NewReplayHandler creates a new instance of ReplayHandler
+func NewReplayHandler(auctionService application.AuctionService) *ReplayHandler {
+	return &ReplayHandler{auctionService: auctionService}
+}
+
+// startReplayRequest is the JSON body of POST /admin/replays, speed defaults to 1x
+type startReplayRequest struct {
+	LotID uuid.UUID `json:"lot_id"`
+	Speed float64   `json:"speed"`
+}
+
+// RegisterRoutes registers the replay endpoints guarded by requireAdmin
+func (h *ReplayHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Post("/admin/replays", requireAdmin, h.startReplay)
+	router.Get("/admin/replays", requireAdmin, h.listReplays)
+	router.Delete("/admin/replays/:id", requireAdmin, h.stopReplay)
+}
+
+func (h *ReplayHandler) startReplay(c *fiber.Ctx) error {
+	var req startReplayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Speed == 0 {
+		req.Speed = 1
+	}
+	replay, err := h.auctionService.StartReplay(c.UserContext(), application.StartReplayDTO{LotID: req.LotID, Speed: req.Speed})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(replay)
+}
+
+func (h *ReplayHandler) listReplays(c *fiber.Ctx) error {
+	return c.JSON(h.auctionService.ListReplays(c.UserContext()))
+}
+
+func (h *ReplayHandler) stopReplay(c *fiber.Ctx) error {
+	replayID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid replay ID")
+	}
+	if err := h.auctionService.StopReplay(c.UserContext(), replayID); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserBidsHandler exposes the bid history of the authenticated user through REST endpoints
+type UserBidsHandler struct {
+	userBidsUC *application.UserBidsUseCase
+}
+
+// NewUserBidsHandler creates a new instance of UserBidsHandler
+func NewUserBidsHandler(userBidsUC *application.UserBidsUseCase) *UserBidsHandler {
+	return &UserBidsHandler{userBidsUC: userBidsUC}
+}
+
+// RegisterRoutes registers the "me" endpoints, requireUser must authenticate the caller
+func (h *UserBidsHandler) RegisterRoutes(router fiber.Router, requireUser fiber.Handler) {
+	router.Get("/users/me/bids", requireUser, h.listMyBids)
+	router.Get("/users/me/lots", requireUser, h.listMyLots)
+}
+
+// listMyBids handles GET /users/me/bids?limit=&offset=
+func (h *UserBidsHandler) listMyBids(c *fiber.Ctx) error {
+	bids, err := h.userBidsUC.Bids(c.UserContext(), application.UserBidsPageDTO{
+		UserID: httpserver.ClaimsFrom(c).UserID,
+		Limit:  c.QueryInt("limit"),
+		Offset: c.QueryInt("offset"),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(bids)
+}
+
+// listMyLots handles GET /users/me/lots?state=&limit=&offset=, state=active lists the "my active bids" view
+func (h *UserBidsHandler) listMyLots(c *fiber.Ctx) error {
+	lots, err := h.userBidsUC.Lots(c.UserContext(), application.UserBidsPageDTO{
+		UserID: httpserver.ClaimsFrom(c).UserID,
+		State:  c.Query("state"),
+		Limit:  c.QueryInt("limit"),
+		Offset: c.QueryInt("offset"),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(lots)
+}
@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// BundleNotifier sends the bundle changes as server_bundle_update to every connection of the lots of
+// the bundle, it implements application.BundleNotifier. the leading bidder isn't disclosed
+type BundleNotifier struct {
+	hub *websocket.Hub
+}
+
+// NewBundleNotifier creates a new instance of BundleNotifier
+func NewBundleNotifier(hub *websocket.Hub) *BundleNotifier {
+	return &BundleNotifier{hub: hub}
+}
+
+// NotifyBundle implements application.BundleNotifier
+func (n *BundleNotifier) NotifyBundle(_ context.Context, bundle *application.BundleDTO) {
+	msg := wsproto.ServerBundleUpdateMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerBundleUpdate}}
+	msg.Payload.BundleID = bundle.ID
+	msg.Payload.Title = bundle.Title
+	msg.Payload.Currency = bundle.Currency
+	msg.Payload.State = bundle.State
+	msg.Payload.Lots = make([]wsproto.BundleLot, 0, len(bundle.Lots))
+	for _, lot := range bundle.Lots {
+		msg.Payload.Lots = append(msg.Payload.Lots, wsproto.BundleLot{LotID: lot.LotID, LeadingAmount: lot.LeadingAmount})
+	}
+	msg.Payload.IndividualSum = bundle.IndividualSum
+	msg.Payload.LeadingAmount = bundle.LeadingAmount
+	msg.Payload.Beating = bundle.Beating
+	msg.Payload.ServerTime = time.Now().UnixMilli()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("BundleNotifier: failed to marshal message", zap.Error(err))
+		return
+	}
+	for _, lot := range bundle.Lots {
+		n.hub.BroadcastMessageToLot(lot.LotID.String(), data)
+	}
+}
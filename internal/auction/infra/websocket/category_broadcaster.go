@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// CategoryBroadcaster consumes auction domain events in-process and publishes the server_lot_opened msgs
+// to the category topics of the lot, for the category feeds. it implements domain.EventPublisher
+type CategoryBroadcaster struct {
+	hub          *websocket.Hub
+	lotRepo      domain.AuctionLotRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewCategoryBroadcaster creates a new instance of CategoryBroadcaster
+func NewCategoryBroadcaster(hub *websocket.Hub, lotRepo domain.AuctionLotRepository, categoryRepo domain.CategoryRepository) *CategoryBroadcaster {
+	return &CategoryBroadcaster{hub: hub, lotRepo: lotRepo, categoryRepo: categoryRepo}
+}
+
+// Publish implements domain.EventPublisher, the msg of a lot goes to the topics of its organization and
+// to the unscoped ones of the anonymous connections
+func (b *CategoryBroadcaster) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		payload, ok := event.Payload.(domain.LotStartedPayload)
+		if !ok {
+			continue
+		}
+		log := logger.FromContext(ctx).With(zap.String("lotID", event.LotID.String()))
+		lot, err := b.lotRepo.GetByID(ctx, event.LotID)
+		if err != nil {
+			log.Error("CategoryBroadcaster: failed to get auction lot", zap.Error(err))
+			continue
+		}
+		categories, err := b.categoryRepo.GetByLotIDs(ctx, event.LotID)
+		if err != nil {
+			log.Error("CategoryBroadcaster: failed to get lot categories", zap.Error(err))
+			continue
+		}
+		if len(categories[event.LotID]) == 0 {
+			continue
+		}
+		msg := wsproto.ServerLotOpenedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLotOpened}}
+		msg.Payload.LotID = event.LotID
+		msg.Payload.OpenedAt = event.OccurredAt
+		msg.Payload.InitialPrice = payload.InitialPrice
+		msg.Payload.EndTime = payload.EndTime
+		data, err := json.Marshal(msg)
+		if err != nil {
+			log.Error("CategoryBroadcaster: failed to marshal message", zap.Error(err))
+			continue
+		}
+		for _, category := range categories[event.LotID] {
+			topic := wsproto.TopicCategoryPrefix + category.Slug
+			b.hub.Publish(topic, data)
+			b.hub.Publish(websocket.ScopedTopic(topic, lot.OrgID.String()), data)
+		}
+	}
+	return nil
+}
+
+// Close implements domain.EventPublisher
+func (b *CategoryBroadcaster) Close() error { return nil }
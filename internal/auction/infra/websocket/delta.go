@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
+)
+
+const (
+	// snapshotEvery is the max number of deltas sent for a lot between two full snapshots
+	snapshotEvery = 20
+	// snapshotInterval is the max time between two full snapshots of a lot
+	snapshotInterval = 30 * time.Second
+)
+
+// lotUpdateEncoder decides, for every published lot state, whether clients get a full
+// server_lot_update snapshot or a compact server_lot_delta with only the changed fields.
+// ForwardLotUpdates encodes the updates, the lot hibernator evicts the idle lots through CachedLots and Invalidate
+type lotUpdateEncoder struct {
+	mu   sync.Mutex
+	lots map[string]*encodedLot
+}
+
+// encodedLot is the last state broadcast for a lot
+type encodedLot struct {
+	state        *application.LotStateDTO
+	deltas       int
+	lastSnapshot time.Time
+}
+
+func newLotUpdateEncoder() *lotUpdateEncoder {
+	return &lotUpdateEncoder{lots: make(map[string]*encodedLot)}
+}
+
+// Encode returns the msg to broadcast for state at now, either a ServerLotUpdateMessage or a ServerLotDeltaMessage
+func (e *lotUpdateEncoder) Encode(state *application.LotStateDTO, now time.Time) any {
+	lotID := state.LotID.String()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if prev, ok := e.lots[lotID]; ok && !prev.needsSnapshot(state, now) {
+		delta := newLotDeltaMessage(prev.state, state, now)
+		prev.state = state
+		prev.deltas++
+		return delta
+	}
+	return e.snapshot(lotID, state, now)
+}
+
+// Snapshot returns the full ServerLotUpdateMessage of state at now, the following deltas are computed from it
+func (e *lotUpdateEncoder) Snapshot(state *application.LotStateDTO, now time.Time) *wsproto.ServerLotUpdateMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.snapshot(state.LotID.String(), state, now)
+}
+
+// snapshot records state as the last snapshot of the lot, e.mu must be held
+func (e *lotUpdateEncoder) snapshot(lotID string, state *application.LotStateDTO, now time.Time) *wsproto.ServerLotUpdateMessage {
+	if state.State == string(domain.StateActive) || state.State == string(domain.StatePaused) {
+		e.lots[lotID] = &encodedLot{state: state, lastSnapshot: now}
+	} else {
+		// finished and cancelled lots won't change anymore, stop tracking them
+		delete(e.lots, lotID)
+	}
+	return newLotUpdateMessage(state, now)
+}
+
+// CachedLots returns the lots with a last broadcast state, implements application.LotCache
+func (e *lotUpdateEncoder) CachedLots() []uuid.UUID {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lots := make([]uuid.UUID, 0, len(e.lots))
+	for lotID := range e.lots {
+		if id, err := uuid.Parse(lotID); err == nil {
+			lots = append(lots, id)
+		}
+	}
+	return lots
+}
+
+// Invalidate forgets the last state broadcast for the lot, its next update is a full snapshot
+func (e *lotUpdateEncoder) Invalidate(lotID uuid.UUID) {
+	e.mu.Lock()
+	delete(e.lots, lotID.String())
+	e.mu.Unlock()
+}
+
+// needsSnapshot reports if a full snapshot is required instead of a delta: periodically, so
+// clients that missed a delta converge, whenever the lot changes its state and when the
+// update is older than the last one broadcast
+func (l *encodedLot) needsSnapshot(state *application.LotStateDTO, now time.Time) bool {
+	return l.deltas >= snapshotEvery ||
+		now.Sub(l.lastSnapshot) >= snapshotInterval ||
+		state.State != l.state.State ||
+		state.Seq < l.state.Seq
+}
+
+// newLotUpdateMessage builds the full snapshot msg of a lot state sent at now
+func newLotUpdateMessage(state *application.LotStateDTO, now time.Time) *wsproto.ServerLotUpdateMessage {
+	updateMsg := &wsproto.ServerLotUpdateMessage{
+		BaseMessage: wsproto.BaseMessage{
+			Type: wsproto.MessageTypeServerLotUpdate,
+		},
+	}
+	updateMsg.Payload.LotID = state.LotID
+	updateMsg.Payload.CurrentPrice = state.CurrentPrice
+	updateMsg.Payload.EndTime = state.EndTime
+	updateMsg.Payload.State = state.State
+	updateMsg.Payload.Seq = state.Seq
+	updateMsg.Payload.LastBidAmount = state.LastBidAmount
+	updateMsg.Payload.LastBidPaddle = state.LastBidPaddle
+	updateMsg.Payload.LastBidTime = state.LastBidTime
+	updateMsg.Payload.Connections = wsproto.ConnectionCounts(state.Connections)
+	updateMsg.Payload.ServerTime = now.UnixMilli()
+	updateMsg.Payload.NextMinBid = nextMinBid(state)
+	updateMsg.Payload.Units = (*wsproto.LotUnits)(state.Units)
+	return updateMsg
+}
+
+// newLotDeltaMessage builds a delta msg sent at now with the fields of state that differ from prev
+func newLotDeltaMessage(prev, state *application.LotStateDTO, now time.Time) *wsproto.ServerLotDeltaMessage {
+	deltaMsg := &wsproto.ServerLotDeltaMessage{
+		BaseMessage: wsproto.BaseMessage{
+			Type: wsproto.MessageTypeServerLotDelta,
+		},
+	}
+	deltaMsg.Payload.LotID = state.LotID
+	deltaMsg.Payload.Seq = state.Seq
+	deltaMsg.Payload.BaseSeq = prev.Seq
+	deltaMsg.Payload.ServerTime = now.UnixMilli()
+	if state.CurrentPrice != prev.CurrentPrice {
+		deltaMsg.Payload.CurrentPrice = &state.CurrentPrice
+	}
+	if !state.EndTime.Equal(prev.EndTime) {
+		deltaMsg.Payload.EndTime = &state.EndTime
+	}
+	if state.LastBidAmount != prev.LastBidAmount {
+		deltaMsg.Payload.LastBidAmount = &state.LastBidAmount
+	}
+	if state.LastBidPaddle != prev.LastBidPaddle {
+		deltaMsg.Payload.LastBidPaddle = &state.LastBidPaddle
+	}
+	if state.LastBidTime != nil && (prev.LastBidTime == nil || !state.LastBidTime.Equal(*prev.LastBidTime)) {
+		deltaMsg.Payload.LastBidTime = state.LastBidTime
+	}
+	if state.Connections != prev.Connections {
+		connections := wsproto.ConnectionCounts(state.Connections)
+		deltaMsg.Payload.Connections = &connections
+	}
+	if next := nextMinBid(state); next != nextMinBid(prev) {
+		deltaMsg.Payload.NextMinBid = &next
+	}
+	if !unitsEqual(state.Units, prev.Units) {
+		deltaMsg.Payload.Units = (*wsproto.LotUnits)(state.Units)
+	}
+	return deltaMsg
+}
+
+// unitsEqual reports if two states hold the same units of a multi-unit lot
+func unitsEqual(a, b *application.LotUnitsDTO) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Quantity == b.Quantity && a.Available == b.Available && a.Pricing == b.Pricing &&
+		slices.Equal(a.StandingBids, b.StandingBids)
+}
+
+// nextMinBid is the lowest amount accepted for the next bid on the lot of state, 0 when the state has
+// no bidding terms
+func nextMinBid(state *application.LotStateDTO) float64 {
+	if state.Bidding == nil {
+		return 0
+	}
+	return state.Bidding.NextMinBid
+}
@@ -3,10 +3,17 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
 	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -16,116 +23,783 @@ var log = logger.GetLogger()
 type AuctionWSHandler struct {
 	auctionService application.AuctionService // application layer dependency
 	hub            *websocket.Hub             // shared hub dependency to send msgs
+	presence       *PresenceBroadcaster       // tracks active bidders for presence msgs
+	encoder        *lotUpdateEncoder          // chooses between full snapshots and deltas
+	workers        *messageWorkerPool         // processes inbound msgs with per-lot ordering
+	deadLetters    *application.DeadLetterUseCase
+	chat           *application.ChatUseCase // nil disables the lot chats
+	lobby          *LobbyBroadcaster        // nil disables the lobby room
+	clock          domain.Clock             // time of the auctioneer msgs, the same as the lot rules
+	hotLots        *hotLotThrottle          // nil broadcasts every lot update
+	// terms records the terms acceptances of the clients, nil disables them, see WithTerms
+	terms *application.LotRulesUseCase
 }
 
-// NewAuctionWSHandler creates a new instance of AuctionWSHandler
-func NewAuctionWSHandler(auctionService application.AuctionService, hub *websocket.Hub) *AuctionWSHandler {
+// dropReasonWorkerBusy is the dead letter reason of the msgs rejected by a saturated worker
+const dropReasonWorkerBusy = "worker_busy"
+
+// dropRetryAfter is the wait suggested to the clients before resending a dropped msg
+const dropRetryAfter = 500 * time.Millisecond
+
+// NewAuctionWSHandler creates a new instance of AuctionWSHandler, inbound msgs are processed by
+// workers goroutines, each holding up to queueSize pending msgs. msgs dropped under load are kept in deadLetters,
+// chat may be nil to disable the lot chats and lobby to disable the lobby room
+func NewAuctionWSHandler(auctionService application.AuctionService, hub *websocket.Hub, presence *PresenceBroadcaster,
+	deadLetters *application.DeadLetterUseCase, chat *application.ChatUseCase, lobby *LobbyBroadcaster, clock domain.Clock, workers, queueSize int) *AuctionWSHandler {
 	return &AuctionWSHandler{
 		auctionService: auctionService,
 		hub:            hub,
+		presence:       presence,
+		encoder:        newLotUpdateEncoder(),
+		workers:        newMessageWorkerPool(workers, queueSize),
+		deadLetters:    deadLetters,
+		chat:           chat,
+		lobby:          lobby,
+		clock:          clock,
 	}
 }
 
-// ListenForMessages starts a go routine that listen the Hub inbound channel for messages and proccess every one of them
+// WithHotLots returns the handler throttling the lot updates broadcast to the clients of the hot lots
+func (h *AuctionWSHandler) WithHotLots(cfg HotLotConfig) *AuctionWSHandler {
+	if cfg.BidsPerSecond > 0 {
+		h.hotLots = newHotLotThrottle(cfg)
+	}
+	return h
+}
+
+// WithTerms returns the handler recording the terms acceptances of the clients with rules, the initial
+// states then carry the terms version of the lot
+func (h *AuctionWSHandler) WithTerms(rules *application.LotRulesUseCase) *AuctionWSHandler {
+	h.terms = rules
+	return h
+}
+
+// HotLotStats returns the counters of the hot lot throttling, zero when it's disabled
+func (h *AuctionWSHandler) HotLotStats() HotLotStats {
+	if h.hotLots == nil {
+		return HotLotStats{}
+	}
+	return h.hotLots.Stats()
+}
+
+// ListenForMessages listens the Hub inbound channel for messages and hands every one of them to the
+// worker pool, msgs of the same lot are processed in arrival order. when the worker of a lot is
+// saturated the msg is dead-lettered instead of blocking the msgs of other lots
 func (h *AuctionWSHandler) ListenForMessages(ctx context.Context) {
-	log.Info("AuctionWSHandler started listening for inbound messages from hub")
+	h.workers.start(ctx, func(ctx context.Context, msg *websocket.ClientMessage) {
+		h.processMessage(ctx, msg)
+	})
+	log.Info("AuctionWSHandler started listening for inbound messages from hub", zap.Int("workers", len(h.workers.queues)))
 	for {
 		select {
 		case <-ctx.Done():
+			h.workers.wait()
 			log.Info("AuctionWSHandler stopped listening for inbound messages from hub")
 			return
 		case msg := <-h.hub.InboundMessages:
-			go h.processMessage(ctx, msg.Client, msg.Data)
+			if !h.workers.submit(msg) {
+				log.Warn("AuctionWSHandler worker queue is full, rejecting message",
+					zap.String("clientID", msg.Client.ID),
+					zap.String("lotID", msg.Client.LotID),
+				)
+				// stored outside of the listener loop, the other lots keep flowing
+				go h.deadLetter(ctx, msg, dropReasonWorkerBusy, time.Now())
+			}
+		}
+	}
+}
+
+// drainPollInterval is how often Drain checks if the inbound msgs have been processed
+const drainPollInterval = 10 * time.Millisecond
+
+// Drain closes every connection with a restart close frame asking the clients to reconnect after
+// retryAfter, then waits until the inbound msgs already read, bids included, have been processed.
+// it returns ctx error if ctx is done first
+func (h *AuctionWSHandler) Drain(ctx context.Context, retryAfter time.Duration) error {
+	reason, err := json.Marshal(wsproto.RestartCloseReason{RetryAfterMs: retryAfter.Milliseconds()})
+	if err != nil {
+		return err
+	}
+	if err := h.hub.CloseAll(ctx, wsproto.CloseServiceRestart, string(reason)); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for len(h.hub.InboundMessages) > 0 || !h.workers.idle() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	log.Info("AuctionWSHandler drained inbound messages")
+	return nil
+}
+
+// ListenForDropped keeps the msgs dropped by the hub as dead letters and asks their clients to retry
+func (h *AuctionWSHandler) ListenForDropped(ctx context.Context) {
+	log.Info("AuctionWSHandler started listening for dropped messages")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("AuctionWSHandler stopped listening for dropped messages")
+			return
+		case msg := <-h.hub.Dropped:
+			h.deadLetter(ctx, &msg.ClientMessage, msg.Reason, msg.DroppedAt)
 		}
 	}
+}
+
+// deadLetter stores a msg dropped before processing and sends a server_retry to its client, the client
+// is notified even if the msg couldn't be stored
+func (h *AuctionWSHandler) deadLetter(ctx context.Context, msg *websocket.ClientMessage, reason string, droppedAt time.Time) {
+	ctx = logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+	log := logger.FromContext(ctx)
+	var baseMsg wsproto.Envelope
+	_ = json.Unmarshal(msg.Data, &baseMsg) // undecodable msgs are stored without type
+
+	retry := wsproto.ServerRetryMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerRetry}}
+	retry.Payload.MessageType = string(baseMsg.Type)
+	retry.Payload.MessageID = baseMsg.MessageID
+	retry.Payload.Reason = reason
+	retry.Payload.RetryAfterMs = dropRetryAfter.Milliseconds()
+	letter, err := h.deadLetters.Record(ctx, application.RecordDeadLetterDTO{
+		LotID:       msg.Client.LotID,
+		UserID:      msg.Client.UserID,
+		ClientID:    msg.Client.ID,
+		MessageType: string(baseMsg.Type),
+		Data:        msg.Data,
+		Reason:      reason,
+		DroppedAt:   droppedAt,
+	})
+	if err != nil {
+		log.Error("failed to store dead letter",
+			zap.String("clientID", msg.Client.ID),
+			zap.String("lotID", msg.Client.LotID),
+			zap.String("reason", reason),
+			zap.ByteString("message", msg.Data),
+			zap.Error(err),
+		)
+	} else {
+		retry.Payload.DeadLetterID = &letter.ID
+		log.Warn("Inbound message dead-lettered",
+			zap.String("deadLetterID", letter.ID.String()),
+			zap.String("clientID", msg.Client.ID),
+			zap.String("lotID", msg.Client.LotID),
+			zap.String("reason", reason),
+		)
+	}
+
+	data, err := json.Marshal(retry)
+	if err != nil {
+		log.Error("failed to marshal ServerRetryMessage", zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(msg.Client.ID, data)
+}
+
+// ListenForJoins sends the initial lot state to every client registered in the hub,
+// preceded by the missed events replay when the client resumes with last_event_seq
+func (h *AuctionWSHandler) ListenForJoins(ctx context.Context) {
+	log.Info("AuctionWSHandler started listening for joined clients")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("AuctionWSHandler stopped listening for joined clients")
+			return
+		case client := <-h.hub.Joined:
+			go h.sendInitialState(ctx, client)
+		}
+	}
+}
+
+// sendInitialState replays the events missed by client, if any, and sends it the current lot state.
+// live updates may interleave with these msgs, clients discard anything older than the seq they already applied
+func (h *AuctionWSHandler) sendInitialState(ctx context.Context, client *websocket.Client) {
+	ctx = clientContext(logger.WithCorrelationID(ctx, logger.NewCorrelationID()), client)
+	log := logger.FromContext(ctx)
+	// first of all the server time, so the client countdowns are corrected from the start
+	h.sendServerTime(ctx, client, 0)
+	if h.lobby != nil && websocket.IsLobbyRoom(client.LotID) {
+		h.lobby.SendSnapshot(ctx, client)
+		return
+	}
+	lotID, err := uuid.Parse(client.LotID)
+	if err != nil {
+		h.sendErrorToClient(ctx, client, "invalid lot ID")
+		return
+	}
+
+	if raw, ok := client.Query[wsproto.QueryLastEventSeq]; ok && raw != "" {
+		lastSeq, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || lastSeq < 0 {
+			h.sendErrorToClient(ctx, client, "invalid last_event_seq")
+		} else {
+			h.replayEvents(ctx, client, lotID, lastSeq)
+		}
+	}
+
+	lotState, err := h.auctionService.GetLotState(ctx, lotID)
+	if err != nil {
+		h.sendErrorToClient(ctx, client, err.Error())
+		return
+	}
+	userID, _ := uuid.Parse(client.UserID) // anonymous spectators have no user
+	lotState = lotState.ForViewer(userID, false)
+	initialMsg := newInitialStateMessage(lotState, h.clock.Now())
+	if userID != uuid.Nil {
+		if initialMsg.Payload.YourPaddle, err = h.auctionService.GetBidderPaddle(ctx, lotID, userID); err != nil {
+			log.Warn("failed to get client paddle", zap.String("clientID", client.ID), zap.Error(err))
+		}
+	}
+	if h.terms != nil {
+		if terms, err := h.terms.TermsStatus(ctx, lotID, userID); err != nil {
+			log.Warn("failed to get lot terms", zap.String("clientID", client.ID), zap.Error(err))
+		} else {
+			initialMsg.Payload.TermsVersion, initialMsg.Payload.TermsAccepted = terms.Version, terms.Accepted
+		}
+	}
+	data, err := json.Marshal(initialMsg)
+	if err != nil {
+		log.Error("failed to marshal ServerInitialStateMessage", zap.String("lotID", client.LotID), zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(client.ID, data)
+	if h.chat != nil {
+		h.sendChatHistory(ctx, client, lotID)
+	}
+}
+
+// newInitialStateMessage builds the server_initial_state msg of lotState sent at now, without the paddle of the viewer
+func newInitialStateMessage(lotState *application.LotStateDTO, now time.Time) *wsproto.ServerInitialStateMessage {
+	initialMsg := &wsproto.ServerInitialStateMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerInitialState}}
+	initialMsg.Payload.LotID = lotState.LotID
+	initialMsg.Payload.Title = lotState.Title
+	initialMsg.Payload.Description = lotState.Description
+	initialMsg.Payload.InitialPrice = lotState.InitialPrice
+	initialMsg.Payload.CurrentPrice = lotState.CurrentPrice
+	initialMsg.Payload.Currency = lotState.Currency
+	initialMsg.Payload.Type = lotState.Type
+	initialMsg.Payload.EndTime = lotState.EndTime
+	initialMsg.Payload.StartTime = lotState.StartTime
+	initialMsg.Payload.ClosingMode = lotState.ClosingMode
+	initialMsg.Payload.MaxEndTime = lotState.MaxEndTime
+	initialMsg.Payload.State = lotState.State
+	initialMsg.Payload.PausedAt = lotState.PausedAt
+	initialMsg.Payload.Seq = lotState.Seq
+	initialMsg.Payload.LastBidAmount = lotState.LastBidAmount
+	initialMsg.Payload.LastBidUserID = lotState.LastBidUserID
+	initialMsg.Payload.LastBidPaddle = lotState.LastBidPaddle
+	initialMsg.Payload.LastBidTime = lotState.LastBidTime
+	initialMsg.Payload.Connections = wsproto.ConnectionCounts(lotState.Connections)
+	initialMsg.Payload.Media = make([]wsproto.MediaItem, 0, len(lotState.Media))
+	for _, m := range lotState.Media {
+		initialMsg.Payload.Media = append(initialMsg.Payload.Media, wsproto.MediaItem{ID: m.ID, Kind: m.Kind, URL: m.URL})
+	}
+	initialMsg.Payload.Categories = make([]wsproto.CategoryItem, 0, len(lotState.Categories))
+	for _, cat := range lotState.Categories {
+		initialMsg.Payload.Categories = append(initialMsg.Payload.Categories, wsproto.CategoryItem{ID: cat.ID, Name: cat.Name, Slug: cat.Slug})
+	}
+	if lotState.Fees != nil {
+		initialMsg.Payload.EstimatedTotal = lotState.Fees.EstimatedTotal
+	}
+	initialMsg.Payload.IndicativePrices = lotState.IndicativePrices
+	if lotState.Bidding != nil {
+		initialMsg.Payload.MinIncrement = lotState.Bidding.MinIncrement
+		initialMsg.Payload.NextMinBid = lotState.Bidding.NextMinBid
+		initialMsg.Payload.ReserveMet = lotState.Bidding.ReserveMet
+		initialMsg.Payload.BidCount = lotState.Bidding.BidCount
+		initialMsg.Payload.ExtensionSeconds = lotState.Bidding.ExtensionSeconds
+		initialMsg.Payload.ExtensionPriceThreshold = lotState.Bidding.ExtensionPriceThreshold
+	}
+	initialMsg.Payload.Units = (*wsproto.LotUnits)(lotState.Units)
+	initialMsg.Payload.ServerTime = now.UnixMilli()
+	return initialMsg
+}
+
+// sendChatHistory sends the recent chat msgs of the lot to a joining client
+func (h *AuctionWSHandler) sendChatHistory(ctx context.Context, client *websocket.Client, lotID uuid.UUID) {
+	log := logger.FromContext(ctx)
+	history, err := h.chat.History(ctx, lotID)
+	if err != nil {
+		log.Error("failed to load chat history", zap.String("lotID", client.LotID), zap.Error(err))
+		return
+	}
+	historyMsg := wsproto.ServerChatHistoryMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerChatHistory}}
+	historyMsg.Payload.LotID = lotID
+	historyMsg.Payload.Messages = make([]wsproto.ChatItem, 0, len(history))
+	for _, msg := range history {
+		historyMsg.Payload.Messages = append(historyMsg.Payload.Messages, newChatItem(msg))
+	}
+	data, err := json.Marshal(historyMsg)
+	if err != nil {
+		log.Error("failed to marshal ServerChatHistoryMessage", zap.String("lotID", client.LotID), zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(client.ID, data)
+}
+
+// replayEvents sends in a single msg the lot events stored after lastSeq
+func (h *AuctionWSHandler) replayEvents(ctx context.Context, client *websocket.Client, lotID uuid.UUID, lastSeq int64) {
+	log := logger.FromContext(ctx)
+	missed, err := h.auctionService.GetLotEventsSince(ctx, lotID, lastSeq)
+	if err != nil {
+		log.Error("failed to load lot events for replay",
+			zap.String("lotID", client.LotID),
+			zap.Int64("lastEventSeq", lastSeq),
+			zap.Error(err),
+		)
+		h.sendErrorToClient(ctx, client, "failed to replay missed events")
+		return
+	}
+	replayMsg := wsproto.ServerReplayMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerReplay}}
+	replayMsg.Payload.LotID = lotID
+	replayMsg.Payload.Truncated = missed.Truncated
+	replayMsg.Payload.Events = make([]wsproto.ReplayEvent, 0, len(missed.Events))
+	for _, event := range missed.Events {
+		replayMsg.Payload.Events = append(replayMsg.Payload.Events, wsproto.ReplayEvent{
+			Seq:        event.Seq,
+			Type:       string(event.Type),
+			OccurredAt: event.OccurredAt,
+			Payload:    anonymizePayload(event.Payload, client.UserID),
+		})
+	}
+	data, err := json.Marshal(replayMsg)
+	if err != nil {
+		log.Error("failed to marshal ServerReplayMessage", zap.String("lotID", client.LotID), zap.Error(err))
+		return
+	}
+	log.Info("Replaying missed lot events",
+		zap.String("clientID", client.ID),
+		zap.String("lotID", client.LotID),
+		zap.Int64("lastEventSeq", lastSeq),
+		zap.Int("events", len(missed.Events)),
+	)
+	h.hub.SendToClient(client.ID, data)
+}
+
+// anonymizedPayloadKeys are the user identity fields removed from replayed event payloads,
+// bid events carry the paddle number instead
+var anonymizedPayloadKeys = []string{"user_id", "voided_by"}
+
+// anonymizePayload removes the identity of other users from a stored event payload,
+// the client keeps seeing its own user ID
+func anonymizePayload(payload any, clientUserID string) any {
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		return payload
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return payload
+	}
+	for _, key := range anonymizedPayloadKeys {
+		if id, ok := fields[key].(string); ok && (clientUserID == "" || id != clientUserID) {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
 
+// clientContext scopes ctx to the organization of the client token, the lot was checked to belong to it
+// on the upgrade and the use cases run by the client keep being scoped to it
+func clientContext(ctx context.Context, client *websocket.Client) context.Context {
+	orgID, _ := uuid.Parse(client.OrgID) // anonymous spectators have no organization
+	return tenant.WithOrgID(ctx, orgID)
 }
 
 // processMesssage dispatch the message by this type
-func (h *AuctionWSHandler) processMessage(ctx context.Context, client *websocket.Client, data []byte) {
-	var baseMsg BaseMessage
+func (h *AuctionWSHandler) processMessage(ctx context.Context, msg *websocket.ClientMessage) {
+	client, data := msg.Client, msg.Data
+	ctx = clientContext(logger.WithCorrelationID(ctx, logger.NewCorrelationID()), client)
+	var baseMsg wsproto.BaseMessage
 	if err := json.Unmarshal(data, &baseMsg); err != nil {
-		h.sendErrorToClient(client, "invalid message format")
+		h.sendErrorToClient(ctx, client, "invalid message format")
 		return
 	}
 	switch baseMsg.Type {
-	case MessageTypeClientBid:
-		h.handleClientBidMessage(ctx, client, data)
+	case wsproto.MessageTypeClientBid:
+		h.handleClientBidMessage(ctx, client, data, msg.ReceivedAt)
+	case wsproto.MessageTypeClientQuickBid:
+		h.handleClientQuickBidMessage(ctx, client, data, msg.ReceivedAt)
+	case wsproto.MessageTypeClientChat:
+		h.handleClientChatMessage(ctx, client, data)
+	case wsproto.MessageTypeClientAuctioneer:
+		h.handleAuctioneerMessage(ctx, client, data)
+	case wsproto.MessageTypeClientSubscribe, wsproto.MessageTypeClientUnsubscribe:
+		h.handleSubscriptionMessage(ctx, client, data)
+	case wsproto.MessageTypeClientPingTime:
+		h.handlePingTimeMessage(ctx, client, data)
+	case wsproto.MessageTypeClientAcceptTerms:
+		h.handleAcceptTermsMessage(ctx, client, data)
 	//adds more case for other types of messages
 	default:
-		h.sendErrorToClient(client, "unknown message type")
+		h.sendErrorToClient(ctx, client, "unknown message type")
 	}
 }
 
-func (h *AuctionWSHandler) handleClientBidMessage(ctx context.Context, client *websocket.Client, data []byte) {
-	var bidMsg ClientBidMessage
+// handleClientBidMessage places a bid read from the connection at receivedAt, the lot end is checked against
+// that time so the queueing in the server doesn't count against the bidder
+func (h *AuctionWSHandler) handleClientBidMessage(ctx context.Context, client *websocket.Client, data []byte, receivedAt time.Time) {
+	var bidMsg wsproto.ClientBidMessage
 	if err := json.Unmarshal(data, &bidMsg); err != nil {
-		h.sendErrorToClient(client, "invalid bid message format")
+		h.sendErrorToClient(ctx, client, "invalid bid message format")
+		return
+	}
+	h.placeClientBid(ctx, client, &bidMsg, receivedAt, false, 0)
+}
+
+// handleClientQuickBidMessage places a quick bid read from the connection at receivedAt, the amount is the
+// next min bid of the lot when the bid is processed. it's answered as a client_bid with the amount bid
+func (h *AuctionWSHandler) handleClientQuickBidMessage(ctx context.Context, client *websocket.Client, data []byte, receivedAt time.Time) {
+	var quickMsg wsproto.ClientQuickBidMessage
+	if err := json.Unmarshal(data, &quickMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid quick bid message format")
+		return
+	}
+	bidMsg := wsproto.ClientBidMessage{BaseMessage: quickMsg.BaseMessage, MessageID: quickMsg.MessageID}
+	bidMsg.Payload.LotID = quickMsg.Payload.LotID
+	h.placeClientBid(ctx, client, &bidMsg, receivedAt, true, quickMsg.Payload.MaxAmount)
+}
+
+// placeClientBid places the bid of bidMsg for the user of the connection, a quick bid ignores the amount
+// of bidMsg and bids the next min bid of the lot up to maxAmount when set
+func (h *AuctionWSHandler) placeClientBid(ctx context.Context, client *websocket.Client, bidMsg *wsproto.ClientBidMessage, receivedAt time.Time, quickBid bool, maxAmount float64) {
+	// bids with a message ID are answered with an ack and a bid result, the others keep the
+	// server_bid_accepted / server_error flow
+	reject := func(code, errorMessage string) {
+		if bidMsg.MessageID == "" {
+			h.sendErrorCodeToClient(ctx, client, code, errorMessage)
+			return
+		}
+		h.sendBidResult(ctx, client, bidMsg, receivedAt, nil, code, errorMessage)
+	}
+
+	// spectators are read-only connections
+	if client.Role != websocket.RoleBidder {
+		reject("", "role error: spectators are not allowed to bid")
 		return
 	}
 
 	//validates LotId
 	if bidMsg.Payload.LotID.String() != client.LotID {
-		h.sendErrorToClient(client, "lot ID mismatch")
+		reject("", "lot ID mismatch")
 		return
 	}
 
+	// the bidder is always the authenticated user of the connection, never the payload
+	userID, err := uuid.Parse(client.UserID)
+	if err != nil {
+		reject("", "unauthenticated connection")
+		return
+	}
+	if bidMsg.Payload.UserID != uuid.Nil && bidMsg.Payload.UserID != userID {
+		reject("", "user ID mismatch")
+		return
+	}
+	// the amount is checked before the ack, the lot checks it again against the precision of its currency
+	if !quickBid {
+		if err := domain.ValidateAmount(bidMsg.Payload.Amount, bidMsg.Payload.Currency); err != nil {
+			reject(domain.AmountErrorCode(err), err.Error())
+			return
+		}
+	}
+
+	if bidMsg.MessageID != "" {
+		h.sendBidAck(ctx, client, bidMsg, receivedAt)
+	}
 	cmd := application.PlaceBidDTO{
-		LotID:  bidMsg.Payload.LotID,
-		UserID: bidMsg.Payload.UserID,
-		Amount: bidMsg.Payload.Amount,
+		LotID:      bidMsg.Payload.LotID,
+		UserID:     userID,
+		Amount:     bidMsg.Payload.Amount,
+		Currency:   bidMsg.Payload.Currency,
+		ClientIP:   client.RemoteIP,
+		ReceivedAt: receivedAt,
+		QuickBid:   quickBid,
+		MaxAmount:  maxAmount,
+	}
+	bid, err := h.auctionService.PlaceBid(ctx, cmd)
+	if err != nil {
+		reject(application.BidErrorCode(err), err.Error())
+		return
+	}
+	h.presence.RecordBid(client.LotID, client.UserID)
+
+	if bidMsg.MessageID != "" {
+		h.sendBidResult(ctx, client, bidMsg, receivedAt, bid, "", "")
+		return
+	}
+	accepted := wsproto.ServerBidAcceptedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerBidAccepted}}
+	accepted.Payload.BidID = bid.ID
+	accepted.Payload.LotID = bid.LotID
+	accepted.Payload.Amount = bid.Amount
+	accepted.Payload.Paddle = bid.Paddle
+	accepted.Payload.Timestamp = bid.Timestamp
+	if data, err := json.Marshal(accepted); err == nil {
+		h.hub.SendToClient(client.ID, data)
+	}
+	// the lot update is broadcast by ForwardLotUpdates once the service publishes the new state
+}
+
+// handleClientChatMessage stores a chat msg of an authenticated client and broadcasts it to the lot
+func (h *AuctionWSHandler) handleClientChatMessage(ctx context.Context, client *websocket.Client, data []byte) {
+	if h.chat == nil {
+		h.sendErrorToClient(ctx, client, "chat is not enabled")
+		return
+	}
+	var chatMsg wsproto.ClientChatMessage
+	if err := json.Unmarshal(data, &chatMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid chat message format")
+		return
+	}
+	if chatMsg.Payload.LotID.String() != client.LotID {
+		h.sendErrorToClient(ctx, client, "lot ID mismatch")
+		return
+	}
+	// anonymous spectators can read the chat but not write in it
+	userID, err := uuid.Parse(client.UserID)
+	if err != nil {
+		h.sendErrorToClient(ctx, client, "unauthenticated connection")
+		return
+	}
+
+	msg, err := h.chat.Send(ctx, application.SendChatDTO{LotID: chatMsg.Payload.LotID, UserID: userID, Text: chatMsg.Payload.Text})
+	if err != nil {
+		h.sendErrorToClient(ctx, client, err.Error())
+		return
+	}
+	out := wsproto.ServerChatMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerChat}}
+	out.Payload.LotID = msg.LotID
+	out.Payload.ChatItem = newChatItem(msg)
+	outData, err := json.Marshal(out)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal ServerChatMessage", zap.Error(err))
+		return
+	}
+	h.hub.BroadcastMessageToLot(client.LotID, outData)
+}
+
+// handleAuctioneerMessage runs a live control action of the auctioneer of the lot. pauses and resumes go
+// through the auction service, their broadcasts come from the lot events
+func (h *AuctionWSHandler) handleAuctioneerMessage(ctx context.Context, client *websocket.Client, data []byte) {
+	var ctrlMsg wsproto.ClientAuctioneerMessage
+	if err := json.Unmarshal(data, &ctrlMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid auctioneer message format")
+		return
+	}
+	// only the connections of the admin WS channel run the lots
+	if client.Role != websocket.RoleAuctioneer {
+		h.sendErrorToClient(ctx, client, "role error: only auctioneers can control the lot")
+		return
+	}
+	if ctrlMsg.Payload.LotID.String() != client.LotID {
+		h.sendErrorToClient(ctx, client, "lot ID mismatch")
+		return
+	}
+	auctioneerID, _ := uuid.Parse(client.UserID)
+	lotID := ctrlMsg.Payload.LotID
+	logger.FromContext(ctx).Info("Auctioneer action",
+		zap.String("lotID", client.LotID),
+		zap.String("auctioneerID", client.UserID),
+		zap.String("action", ctrlMsg.Payload.Action),
+	)
+
+	var err error
+	switch ctrlMsg.Payload.Action {
+	case wsproto.AuctioneerActionPause:
+		_, err = h.auctionService.PauseLot(ctx, lotID, auctioneerID, ctrlMsg.Payload.Text)
+	case wsproto.AuctioneerActionResume:
+		_, err = h.auctionService.ResumeLot(ctx, lotID, auctioneerID)
+	case wsproto.AuctioneerActionFairWarning:
+		var lotState *application.LotStateDTO
+		if lotState, err = h.auctionService.GetLotState(ctx, lotID); err == nil {
+			if lotState.State != string(domain.StateActive) {
+				err = domain.ErrLotNotActive
+				break
+			}
+			warning := wsproto.ServerFairWarningMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerFairWarning}}
+			warning.Payload.LotID = lotID
+			warning.Payload.Text = ctrlMsg.Payload.Text
+			warning.Payload.CurrentPrice = lotState.CurrentPrice
+			warning.Payload.EndTime = lotState.EndTime
+			warning.Payload.IssuedAt = h.clock.Now()
+			h.broadcastToLot(ctx, client.LotID, warning)
+		}
+	case wsproto.AuctioneerActionCommentary:
+		if strings.TrimSpace(ctrlMsg.Payload.Text) == "" {
+			h.sendErrorToClient(ctx, client, "commentary text is required")
+			return
+		}
+		commentary := wsproto.ServerCommentaryMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerCommentary}}
+		commentary.Payload.LotID = lotID
+		commentary.Payload.Text = strings.TrimSpace(ctrlMsg.Payload.Text)
+		commentary.Payload.SentAt = h.clock.Now()
+		h.broadcastToLot(ctx, client.LotID, commentary)
+	default:
+		h.sendErrorToClient(ctx, client, "unknown auctioneer action")
+		return
 	}
-	_, err := h.auctionService.PlaceBid(ctx, cmd)
 	if err != nil {
-		h.sendErrorToClient(client, err.Error())
+		h.sendErrorToClient(ctx, client, err.Error())
+	}
+}
+
+// broadcastToLot serializes msg and sends it to every connection of the lot
+func (h *AuctionWSHandler) broadcastToLot(ctx context.Context, lotID string, msg any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal lot message", zap.String("lotID", lotID), zap.Error(err))
 		return
 	}
+	h.hub.BroadcastMessageToLot(lotID, data)
+}
+
+func newChatItem(msg *application.ChatMessageDTO) wsproto.ChatItem {
+	return wsproto.ChatItem{ID: msg.ID, Paddle: msg.Paddle, Text: msg.Text, SentAt: msg.SentAt}
+}
 
-	//1. get updated lot state
-	lotState, err := h.auctionService.GetLotState(ctx, cmd.LotID)
+// sendBidAck tells the client that its bid was taken for processing and, when the lot queue is busy,
+// how far back it's queued
+func (h *AuctionWSHandler) sendBidAck(ctx context.Context, client *websocket.Client, bidMsg *wsproto.ClientBidMessage, receivedAt time.Time) {
+	ack := wsproto.ServerBidAckMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerBidAck}}
+	ack.Payload.MessageID = bidMsg.MessageID
+	ack.Payload.LotID = bidMsg.Payload.LotID
+	ack.Payload.Amount = bidMsg.Payload.Amount
+	ack.Payload.ReceivedAt = receivedAt
+	hint := h.auctionService.BidQueueHint(bidMsg.Payload.LotID)
+	ack.Payload.QueuePosition = hint.Position
+	ack.Payload.EstimatedDelayMs = hint.EstimatedDelay.Milliseconds()
+	data, err := json.Marshal(ack)
 	if err != nil {
-		h.sendErrorToClient(client, "failed to get updated lost state")
+		logger.FromContext(ctx).Error("failed to marshal ServerBidAckMessage", zap.Error(err))
 		return
 	}
-	//2. build update message
-	updateMsg := ServerLotUpdateMessage{
-		BaseMessage: BaseMessage{
-			Type: MessageTypeServerLotUpdate,
-		},
+	h.hub.SendToClient(client.ID, data)
+}
+
+// sendBidResult sends the outcome of a bid sent with a message ID and received at receivedAt, with the
+// time the server took to decide it. bid is nil when it was rejected
+func (h *AuctionWSHandler) sendBidResult(ctx context.Context, client *websocket.Client, bidMsg *wsproto.ClientBidMessage, receivedAt time.Time, bid *domain.Bid, errorCode, errorMessage string) {
+	result := wsproto.ServerBidResultMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerBidResult}}
+	result.Payload.MessageID = bidMsg.MessageID
+	result.Payload.LotID = bidMsg.Payload.LotID
+	result.Payload.Amount = bidMsg.Payload.Amount
+	result.Payload.CorrelationID = logger.CorrelationID(ctx)
+	result.Payload.ReceivedAt = receivedAt
+	result.Payload.ProcessingMs = time.Since(receivedAt).Milliseconds()
+	if bid != nil {
+		result.Payload.Status = wsproto.BidResultAccepted
+		result.Payload.BidID = &bid.ID
+		result.Payload.Amount = bid.Amount // the amount of a quick bid is only known once placed
+		result.Payload.Paddle = bid.Paddle
+		result.Payload.Timestamp = &bid.Timestamp
+	} else {
+		result.Payload.Status = wsproto.BidResultRejected
+		result.Payload.Error = errorMessage
+		result.Payload.Code = errorCode
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal ServerBidResultMessage", zap.Error(err))
+		return
 	}
-	updateMsg.Payload.LotID = lotState.LotID
-	updateMsg.Payload.CurrentPrice = lotState.CurrentPrice
-	updateMsg.Payload.EndTime = lotState.EndTime
-	updateMsg.Payload.State = lotState.State
-	updateMsg.Payload.LastBidAmount = lotState.LastBidAmount
-	updateMsg.Payload.LastBidUserID = lotState.LastBidUserID
-	updateMsg.Payload.LastBidTime = lotState.LastBidTime
+	h.hub.SendToClient(client.ID, data)
+}
+
+// ForwardLotUpdates broadcasts every published lot state change to the WS clients of that lot,
+// whatever the origin of the change (WS bid, gRPC, admin actions)
+func (h *AuctionWSHandler) ForwardLotUpdates(ctx context.Context) {
+	updates, cancel := h.auctionService.WatchAllLots()
+	defer cancel()
+	// the updates held back for the hot lots are sent as soon as their lot can be broadcast again
+	var flush <-chan time.Time
+	if h.hotLots != nil {
+		ticker := time.NewTicker(h.hotLots.interval / 2)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+	log.Info("AuctionWSHandler started forwarding lot updates to hub")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("AuctionWSHandler stopped forwarding lot updates")
+			return
+		case lotState, ok := <-updates:
+			if !ok {
+				return
+			}
+			h.broadcastLotUpdate(lotState)
+		case <-flush:
+			for _, lotState := range h.hotLots.Due(h.clock.Now()) {
+				h.sendLotUpdate(lotState, true)
+			}
+		}
+	}
+}
+
+// LotUpdateCache returns the last states broadcast per lot, for the lot hibernator to evict the idle ones
+func (h *AuctionWSHandler) LotUpdateCache() application.LotCache {
+	return h.encoder
+}
 
-	// 3. serialize and send to all lot clients
-	updateDate, err := json.Marshal(updateMsg)
+// isNotOpenYet reports if the lot state is pending or preview, these lots only change by admin edits
+func isNotOpenYet(lotState *application.LotStateDTO) bool {
+	return lotState.State == string(domain.StatePending) || lotState.State == string(domain.StatePreview)
+}
+
+// broadcastLotUpdate sends lotState to all lot clients unless its lot is hot and was just broadcast,
+// then it's held back and coalesced with the following updates
+func (h *AuctionWSHandler) broadcastLotUpdate(lotState *application.LotStateDTO) {
+	snapshot := false
+	if h.hotLots != nil && !isNotOpenYet(lotState) {
+		var send bool
+		if send, snapshot = h.hotLots.Offer(lotState, h.clock.Now()); !send {
+			return
+		}
+	}
+	h.sendLotUpdate(lotState, snapshot)
+}
+
+// sendLotUpdate sends lotState to all lot clients, as a full snapshot or as a delta. the clients
+// previewing a lot not open yet get its whole initial state again, the listing may have been edited
+func (h *AuctionWSHandler) sendLotUpdate(lotState *application.LotStateDTO, snapshot bool) {
+	var msg any
+	now := h.clock.Now()
+	switch {
+	case isNotOpenYet(lotState):
+		msg = newInitialStateMessage(lotState.ForViewer(uuid.Nil, false), now)
+	case snapshot:
+		msg = h.encoder.Snapshot(lotState, now)
+	default:
+		msg = h.encoder.Encode(lotState, now)
+	}
+	updateData, err := json.Marshal(msg)
 	if err != nil {
-		h.sendErrorToClient(client, "failed to serialize lot update")
+		log.Error("failed to marshal lot update", zap.String("lotID", lotState.LotID.String()), zap.Error(err))
 		return
 	}
-	h.hub.BroadcastMessageToLot(client.LotID, updateDate)
+	h.hub.BroadcastMessageToLot(lotState.LotID.String(), updateData)
+}
 
+// sendErrorToClient serializes and sends an error msg to a specific client, with the correlation ID
+// of the message that failed so it can be looked up in the logs
+func (h *AuctionWSHandler) sendErrorToClient(ctx context.Context, client *websocket.Client, errorMessage string) {
+	h.sendErrorCodeToClient(ctx, client, "", errorMessage)
 }
 
-// sendErrorToClient serializes and sends an error msg to a specific client
-func (h *AuctionWSHandler) sendErrorToClient(client *websocket.Client, errorMessage string) {
-	errMsg := ServerErrorMessage{
-		BaseMessage: BaseMessage{MessageTypeServerError},
+// sendErrorCodeToClient sends an error with its code, for the errors the clients can tell apart
+func (h *AuctionWSHandler) sendErrorCodeToClient(ctx context.Context, client *websocket.Client, code, errorMessage string) {
+	errMsg := wsproto.ServerErrorMessage{
+		BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerError},
 	}
 	errMsg.Payload.Error = errorMessage
+	errMsg.Payload.Code = code
+	errMsg.Payload.CorrelationID = logger.CorrelationID(ctx)
 	data, err := json.Marshal(errMsg)
 	if err != nil {
-		log.Error("failed to marshal ServerErrorMessage", zap.Error(err))
+		logger.FromContext(ctx).Error("failed to marshal ServerErrorMessage", zap.Error(err))
 		return
 	}
-	select {
-	case client.Send <- data:
-		log.Debug("sent error message to client")
-	default:
-		log.Warn("client send channel full or closed, could not send error msg")
-	}
+	// routed through the hub, which owns the client channels
+	h.hub.SendToClient(client.ID, data)
 }
@@ -0,0 +1,171 @@
+package websocket
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// hotLotForget is how long a lot without updates is tracked by the hot lot throttle
+const hotLotForget = time.Minute
+
+// HotLotConfig configures the broadcast throttling of the hot lots: a lot receiving BidsPerSecond or more
+// over a second becomes hot and its clients get at most MaxUpdatesPerSecond full snapshots per second,
+// the states published in between are coalesced into the next one. it cools down below half the rate.
+// the bids are stored and acknowledged as usual, only the lot broadcasts are thinned. a zero BidsPerSecond
+// disables it
+type HotLotConfig struct {
+	BidsPerSecond       float64
+	MaxUpdatesPerSecond int
+}
+
+// HotLotStats counts the hot lots and the lot updates coalesced since start
+type HotLotStats struct {
+	// Hot is the number of lots hot right now, Detected the times a lot became hot
+	Hot       int   `json:"hot"`
+	Detected  int64 `json:"detected"`
+	Coalesced int64 `json:"coalesced"`
+}
+
+// hotLotThrottle measures the bid rate of the lots and holds back the updates of the hot ones
+type hotLotThrottle struct {
+	cfg      HotLotConfig
+	interval time.Duration
+
+	mu   sync.Mutex
+	lots map[uuid.UUID]*hotLot
+
+	detected  atomic.Int64
+	coalesced atomic.Int64
+}
+
+// hotLot is the bid rate and broadcast state of a lot
+type hotLot struct {
+	windowStart time.Time
+	bids        int
+	lastBid     time.Time // last bid time seen in the states of the lot, a new one is a new bid
+	lastSeen    time.Time
+	hot         bool
+	lastSent    time.Time
+	pending     *application.LotStateDTO // latest state held back while hot
+}
+
+func newHotLotThrottle(cfg HotLotConfig) *hotLotThrottle {
+	if cfg.MaxUpdatesPerSecond <= 0 {
+		cfg.MaxUpdatesPerSecond = 1
+	}
+	return &hotLotThrottle{
+		cfg:      cfg,
+		interval: time.Second / time.Duration(cfg.MaxUpdatesPerSecond),
+		lots:     make(map[uuid.UUID]*hotLot),
+	}
+}
+
+// Offer records state and reports if it must be broadcast now, and if as a full snapshot because the lot
+// is hot. a state held back is returned later by Due, unless a newer one replaces it
+func (t *hotLotThrottle) Offer(state *application.LotStateDTO, now time.Time) (send, snapshot bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lot, ok := t.lots[state.LotID]
+	if !ok {
+		lot = &hotLot{windowStart: now}
+		t.lots[state.LotID] = lot
+	}
+	lot.lastSeen = now
+	if state.LastBidTime != nil && !state.LastBidTime.Equal(lot.lastBid) {
+		lot.bids++
+		lot.lastBid = *state.LastBidTime
+	}
+	t.measure(state.LotID, lot, now)
+
+	if state.State != string(domain.StateActive) {
+		// pauses and closes reach the clients right away, the held state is older
+		if lot.pending != nil {
+			t.coalesced.Add(1)
+		}
+		delete(t.lots, state.LotID)
+		return true, false
+	}
+	if !lot.hot {
+		if lot.pending != nil {
+			lot.pending = nil
+			t.coalesced.Add(1)
+		}
+		lot.lastSent = now
+		return true, false
+	}
+	if lot.pending == nil && now.Sub(lot.lastSent) >= t.interval {
+		lot.lastSent = now
+		return true, true
+	}
+	// only the newest of the held state and state will be sent
+	if lot.pending != nil {
+		t.coalesced.Add(1)
+	}
+	if lot.pending == nil || state.Seq >= lot.pending.Seq {
+		lot.pending = state
+	}
+	return false, false
+}
+
+// Due returns the held states whose lot can be broadcast again at now, they're sent as full snapshots
+func (t *hotLotThrottle) Due(now time.Time) []*application.LotStateDTO {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var due []*application.LotStateDTO
+	for lotID, lot := range t.lots {
+		t.measure(lotID, lot, now)
+		if lot.pending != nil && now.Sub(lot.lastSent) >= t.interval {
+			due = append(due, lot.pending)
+			lot.pending = nil
+			lot.lastSent = now
+			continue
+		}
+		if lot.pending == nil && !lot.hot && now.Sub(lot.lastSeen) >= hotLotForget {
+			delete(t.lots, lotID)
+		}
+	}
+	return due
+}
+
+// measure closes the rate window of the lot once a second old, switching the lot hot or back to normal
+func (t *hotLotThrottle) measure(lotID uuid.UUID, lot *hotLot, now time.Time) {
+	elapsed := now.Sub(lot.windowStart)
+	if elapsed < time.Second {
+		return
+	}
+	rate := float64(lot.bids) / elapsed.Seconds()
+	switch {
+	case !lot.hot && rate >= t.cfg.BidsPerSecond:
+		lot.hot = true
+		t.detected.Add(1)
+		log.Info("Hot lot detected, throttling its broadcasts",
+			zap.String("lotID", lotID.String()),
+			zap.Float64("bidsPerSecond", rate),
+			zap.Int("maxUpdatesPerSecond", t.cfg.MaxUpdatesPerSecond),
+		)
+	case lot.hot && rate < t.cfg.BidsPerSecond/2:
+		lot.hot = false
+		log.Info("Hot lot cooled down", zap.String("lotID", lotID.String()), zap.Float64("bidsPerSecond", rate))
+	}
+	lot.windowStart = now
+	lot.bids = 0
+}
+
+// Stats returns the counters of the throttle
+func (t *hotLotThrottle) Stats() HotLotStats {
+	t.mu.Lock()
+	hot := 0
+	for _, lot := range t.lots {
+		if lot.hot {
+			hot++
+		}
+	}
+	t.mu.Unlock()
+	return HotLotStats{Hot: hot, Detected: t.detected.Load(), Coalesced: t.coalesced.Load()}
+}
@@ -0,0 +1,31 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+)
+
+// InboundConfig validates the client msgs of the lot connections against the wsproto DTOs before they
+// are handled, maxMalformed is the number of rejected msgs that closes a connection
+func InboundConfig(maxMalformed int) websocket.InboundConfig {
+	return websocket.InboundConfig{
+		MaxMessageSize: wsproto.MaxInboundSize(),
+		Validate:       wsproto.ValidateInbound,
+		Rejection:      malformedReply,
+		MaxMalformed:   maxMalformed,
+	}
+}
+
+// malformedReply is the server_error answering a msg rejected by wsproto.ValidateInbound
+func malformedReply(err error) []byte {
+	errMsg := wsproto.ServerErrorMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerError}}
+	errMsg.Payload.Error = err.Error()
+	errMsg.Payload.Code = wsproto.CodeMalformedMessage
+	data, err := json.Marshal(errMsg)
+	if err != nil {
+		return nil
+	}
+	return data
+}
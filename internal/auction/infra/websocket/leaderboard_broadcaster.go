@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LeaderboardBroadcaster consumes auction domain events in-process, keeps the lot leaderboards up to date
+// and broadcasts the changed ones as server_leaderboard msgs, it implements domain.EventPublisher
+type LeaderboardBroadcaster struct {
+	hub          *websocket.Hub
+	leaderboards *application.LeaderboardUseCase
+}
+
+// NewLeaderboardBroadcaster creates a new instance of LeaderboardBroadcaster
+func NewLeaderboardBroadcaster(hub *websocket.Hub, leaderboards *application.LeaderboardUseCase) *LeaderboardBroadcaster {
+	return &LeaderboardBroadcaster{hub: hub, leaderboards: leaderboards}
+}
+
+// Publish implements domain.EventPublisher, the broadcast boards never identify the users
+func (b *LeaderboardBroadcaster) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, board := range b.leaderboards.Apply(ctx, events...) {
+		board = board.ForViewer(uuid.Nil, false)
+		msg := wsproto.ServerLeaderboardMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLeaderboard}}
+		msg.Payload.LotID = board.LotID
+		msg.Payload.Currency = board.Currency
+		msg.Payload.Entries = make([]wsproto.LeaderboardEntry, 0, len(board.Entries))
+		for _, entry := range board.Entries {
+			msg.Payload.Entries = append(msg.Payload.Entries, wsproto.LeaderboardEntry{
+				Rank:    entry.Rank,
+				Paddle:  entry.Paddle,
+				BestBid: entry.BestBid,
+				BidAt:   entry.BidAt,
+			})
+		}
+		data, err := json.Marshal(msg)
+		if err != nil {
+			log.Error("LeaderboardBroadcaster: failed to marshal message", zap.String("lotID", board.LotID.String()), zap.Error(err))
+			continue
+		}
+		b.hub.BroadcastMessageToLot(board.LotID.String(), data)
+	}
+	return nil
+}
+
+// Close implements domain.EventPublisher
+func (b *LeaderboardBroadcaster) Close() error { return nil }
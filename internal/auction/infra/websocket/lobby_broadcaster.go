@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// lobbyMaxLots caps the lots of a server_ending_soon msg, the closest ends are kept
+const lobbyMaxLots = 50
+
+// LobbyBroadcaster periodically publishes the lots closing soon to the lobby topics, reaching the lobby rooms
+// and the lobby subscribers. the anonymous lobby gets the lots of every organization and the lobby of an
+// organization only its own lots
+type LobbyBroadcaster struct {
+	hub        *websocket.Hub
+	endingSoon *application.EndingSoonUseCase
+	interval   time.Duration
+	// organizations with lots in the last broadcast, their lobbies get an empty feed when the lots are gone
+	orgs map[string]struct{}
+}
+
+// NewLobbyBroadcaster creates a new instance of LobbyBroadcaster, broadcasting every interval
+func NewLobbyBroadcaster(hub *websocket.Hub, endingSoon *application.EndingSoonUseCase, interval time.Duration) *LobbyBroadcaster {
+	return &LobbyBroadcaster{hub: hub, endingSoon: endingSoon, interval: interval, orgs: make(map[string]struct{})}
+}
+
+// Run broadcasts the ending-soon feed every interval until ctx is done
+func (b *LobbyBroadcaster) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	log.Info("LobbyBroadcaster started", zap.Duration("interval", b.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("LobbyBroadcaster stopped")
+			return
+		case <-ticker.C:
+			b.broadcast(ctx)
+		}
+	}
+}
+
+// broadcast reads the feed of every organization and publishes it to each lobby topic
+func (b *LobbyBroadcaster) broadcast(ctx context.Context) {
+	feed, err := b.endingSoon.Execute(ctx, 0)
+	if err != nil {
+		log.Error("LobbyBroadcaster: failed to get the lots ending soon", zap.Error(err))
+		return
+	}
+	byOrg := make(map[string][]application.EndingSoonLotDTO)
+	for org := range b.orgs {
+		byOrg[org] = nil
+	}
+	for _, lot := range feed.Lots {
+		if lot.OrgID != uuid.Nil {
+			org := lot.OrgID.String()
+			byOrg[org] = append(byOrg[org], lot)
+		}
+	}
+	b.send(websocket.LobbyRoom(""), feed, feed.Lots)
+	clear(b.orgs)
+	for org, lots := range byOrg {
+		b.send(websocket.LobbyRoom(org), feed, lots)
+		if len(lots) > 0 {
+			b.orgs[org] = struct{}{}
+		}
+	}
+}
+
+// SendSnapshot sends the current feed of its organization to a client joining a lobby room
+func (b *LobbyBroadcaster) SendSnapshot(ctx context.Context, client *websocket.Client) {
+	feed, err := b.endingSoon.Execute(ctx, 0)
+	if err != nil {
+		logger.FromContext(ctx).Error("LobbyBroadcaster: failed to get the lots ending soon", zap.String("clientID", client.ID), zap.Error(err))
+		return
+	}
+	if data, ok := endingSoonMessage(feed, feed.Lots); ok {
+		b.hub.SendToClient(client.ID, data)
+	}
+}
+
+func (b *LobbyBroadcaster) send(room string, feed *application.EndingSoonDTO, lots []application.EndingSoonLotDTO) {
+	if data, ok := endingSoonMessage(feed, lots); ok {
+		b.hub.Publish(room, data)
+	}
+}
+
+// endingSoonMessage encodes the server_ending_soon msg of lots, a subset of feed
+func endingSoonMessage(feed *application.EndingSoonDTO, lots []application.EndingSoonLotDTO) ([]byte, bool) {
+	msg := wsproto.ServerEndingSoonMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerEndingSoon}}
+	msg.Payload.WithinSeconds = feed.WithinSeconds
+	msg.Payload.ComputedAt = feed.ComputedAt
+	lots = lots[:min(len(lots), lobbyMaxLots)]
+	msg.Payload.Lots = make([]wsproto.EndingSoonLot, 0, len(lots))
+	for _, lot := range lots {
+		msg.Payload.Lots = append(msg.Payload.Lots, wsproto.EndingSoonLot{
+			LotID:         lot.LotID,
+			Title:         lot.Title,
+			CurrentPrice:  lot.CurrentPrice,
+			Currency:      lot.Currency,
+			EndTime:       lot.EndTime,
+			EndsInSeconds: lot.EndsInSeconds,
+		})
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("failed to marshal ServerEndingSoonMessage", zap.Error(err))
+		return nil, false
+	}
+	return data, true
+}
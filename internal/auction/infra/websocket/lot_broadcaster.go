@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// LotBroadcaster consumes auction domain events in-process and broadcasts lot-wide msgs (server_lot_opened,
+// server_lot_paused, server_lot_resumed, server_price_point) to every connection of the lot, it implements domain.EventPublisher
+type LotBroadcaster struct {
+	hub *websocket.Hub
+}
+
+// NewLotBroadcaster creates a new instance of LotBroadcaster
+func NewLotBroadcaster(hub *websocket.Hub) *LotBroadcaster {
+	return &LotBroadcaster{hub: hub}
+}
+
+// Publish implements domain.EventPublisher
+func (b *LotBroadcaster) Publish(_ context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		switch payload := event.Payload.(type) {
+		case domain.LotStartedPayload:
+			msg := wsproto.ServerLotOpenedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLotOpened}}
+			msg.Payload.LotID = event.LotID
+			msg.Payload.OpenedAt = event.OccurredAt
+			msg.Payload.InitialPrice = payload.InitialPrice
+			msg.Payload.EndTime = payload.EndTime
+			b.broadcast(event.LotID.String(), msg)
+		case domain.LotPausedPayload:
+			msg := wsproto.ServerLotPausedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLotPaused}}
+			msg.Payload.LotID = event.LotID
+			msg.Payload.Reason = payload.Reason
+			msg.Payload.PausedAt = event.OccurredAt
+			b.broadcast(event.LotID.String(), msg)
+		case domain.BidPlacedPayload:
+			b.broadcastPricePoint(event, payload.CurrentPrice)
+		case domain.BidVoidedPayload:
+			b.broadcastPricePoint(event, payload.CurrentPrice)
+		case domain.LotResumedPayload:
+			msg := wsproto.ServerLotResumedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLotResumed}}
+			msg.Payload.LotID = event.LotID
+			msg.Payload.ResumedAt = event.OccurredAt
+			msg.Payload.EndTime = payload.EndTime
+			b.broadcast(event.LotID.String(), msg)
+		}
+	}
+	return nil
+}
+
+// broadcastPricePoint sends the lot price after event to the live charts of the lot
+func (b *LotBroadcaster) broadcastPricePoint(event domain.Event, price float64) {
+	msg := wsproto.ServerPricePointMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerPricePoint}}
+	msg.Payload.LotID = event.LotID
+	msg.Payload.Seq = event.Seq
+	msg.Payload.At = event.OccurredAt
+	msg.Payload.Price = price
+	b.broadcast(event.LotID.String(), msg)
+}
+
+func (b *LotBroadcaster) broadcast(lotID string, msg any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("LotBroadcaster: failed to marshal message", zap.String("lotID", lotID), zap.Error(err))
+		return
+	}
+	b.hub.BroadcastMessageToLot(lotID, data)
+}
+
+// Close implements domain.EventPublisher
+func (b *LotBroadcaster) Close() error { return nil }
@@ -0,0 +1,38 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// MaintenanceNotifier sends the maintenance notices as server_info to every connection of every lot,
+// it implements application.MaintenanceNotifier
+type MaintenanceNotifier struct {
+	hub *websocket.Hub
+}
+
+// NewMaintenanceNotifier creates a new instance of MaintenanceNotifier
+func NewMaintenanceNotifier(hub *websocket.Hub) *MaintenanceNotifier {
+	return &MaintenanceNotifier{hub: hub}
+}
+
+// NotifyMaintenance implements application.MaintenanceNotifier
+func (n *MaintenanceNotifier) NotifyMaintenance(_ context.Context, notice *application.MaintenanceNoticeDTO) {
+	msg := wsproto.ServerInfoMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerInfo}}
+	msg.Payload.Message = notice.Message
+	msg.Payload.Severity = notice.Severity
+	msg.Payload.StartsAt = notice.StartsAt
+	msg.Payload.EndsAt = notice.EndsAt
+	msg.Payload.ReadOnly = notice.ReadOnly
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("MaintenanceNotifier: failed to marshal message", zap.Error(err))
+		return
+	}
+	n.hub.BroadcastToAll(data)
+}
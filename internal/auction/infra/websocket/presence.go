@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// activeBidderWindow is how long a user counts as an active bidder after placing a bid
+const activeBidderWindow = time.Minute
+
+// PresenceBroadcaster periodically broadcasts server_presence msgs for lots whose participation changed,
+// debouncing joins/leaves so a storm of connections produces at most one msg per lot and interval
+type PresenceBroadcaster struct {
+	hub      *websocket.Hub
+	interval time.Duration
+
+	mu sync.Mutex
+	// last bid time by lot and user, used to compute active bidders
+	lastBids map[string]map[string]time.Time
+	// last active bidders count sent by lot, to detect changes caused by expiration
+	lastActive map[string]int
+}
+
+// NewPresenceBroadcaster creates a new instance of PresenceBroadcaster
+func NewPresenceBroadcaster(hub *websocket.Hub, interval time.Duration) *PresenceBroadcaster {
+	return &PresenceBroadcaster{
+		hub:        hub,
+		interval:   interval,
+		lastBids:   make(map[string]map[string]time.Time),
+		lastActive: make(map[string]int),
+	}
+}
+
+// RecordBid marks userID as an active bidder of lotID
+func (p *PresenceBroadcaster) RecordBid(lotID, userID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	users, ok := p.lastBids[lotID]
+	if !ok {
+		users = make(map[string]time.Time)
+		p.lastBids[lotID] = users
+	}
+	users[userID] = time.Now()
+}
+
+// Run broadcasts presence changes every interval until ctx is done
+func (p *PresenceBroadcaster) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	log.Info("PresenceBroadcaster started", zap.Duration("interval", p.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("PresenceBroadcaster stopped")
+			return
+		case <-ticker.C:
+			p.flush()
+		}
+	}
+}
+
+// flush sends a presence msg to every lot with changed connections or active bidders
+func (p *PresenceBroadcaster) flush() {
+	changed := make(map[string]struct{})
+	for _, lotID := range p.hub.DrainPresenceChanges() {
+		changed[lotID] = struct{}{}
+	}
+
+	active := p.activeBidders(changed)
+	for lotID := range changed {
+		spectators, bidders := p.hub.CountByRole(lotID)
+		if spectators+bidders == 0 {
+			continue // nobody to notify
+		}
+		msg := wsproto.ServerPresenceMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerPresence}}
+		msg.Payload.LotID = lotID
+		msg.Payload.Viewers = spectators + bidders
+		msg.Payload.Spectators = spectators
+		msg.Payload.Bidders = bidders
+		msg.Payload.ActiveBidders = active[lotID]
+
+		data, err := json.Marshal(msg)
+		if err != nil {
+			log.Error("failed to marshal ServerPresenceMessage", zap.String("lotID", lotID), zap.Error(err))
+			continue
+		}
+		p.hub.BroadcastMessageToLot(lotID, data)
+	}
+}
+
+// activeBidders expires old bid activity and returns the active bidders count by lot,
+// lots whose count changed since the last flush are added to changed
+func (p *PresenceBroadcaster) activeBidders(changed map[string]struct{}) map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cutoff := time.Now().Add(-activeBidderWindow)
+	counts := make(map[string]int, len(p.lastBids))
+	for lotID, users := range p.lastBids {
+		for userID, at := range users {
+			if at.Before(cutoff) {
+				delete(users, userID)
+			}
+		}
+		if len(users) == 0 {
+			delete(p.lastBids, lotID)
+		}
+		counts[lotID] = len(users)
+	}
+	for lotID, prev := range p.lastActive {
+		if _, ok := counts[lotID]; !ok && prev != 0 {
+			changed[lotID] = struct{}{}
+			delete(p.lastActive, lotID)
+		}
+	}
+	for lotID, n := range counts {
+		if p.lastActive[lotID] != n {
+			changed[lotID] = struct{}{}
+		}
+		p.lastActive[lotID] = n
+	}
+	return counts
+}
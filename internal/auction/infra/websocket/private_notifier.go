@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// PrivateNotifier consumes auction domain events in-process and pushes targeted msgs
+// (server_outbid, server_lot_won) to the connections of the concerned user, it implements domain.EventPublisher
+type PrivateNotifier struct {
+	hub *websocket.Hub
+}
+
+// NewPrivateNotifier creates a new instance of PrivateNotifier
+func NewPrivateNotifier(hub *websocket.Hub) *PrivateNotifier {
+	return &PrivateNotifier{hub: hub}
+}
+
+// Publish implements domain.EventPublisher
+func (n *PrivateNotifier) Publish(_ context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		switch payload := event.Payload.(type) {
+		case domain.UserOutbidPayload:
+			msg := wsproto.ServerOutbidMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerOutbid}}
+			msg.Payload.LotID = event.LotID
+			msg.Payload.YourAmount = payload.PreviousAmount
+			msg.Payload.NewAmount = payload.NewAmount
+			msg.Payload.OutbidAt = event.OccurredAt
+			n.sendToUser(payload.UserID.String(), msg)
+
+		case domain.WinnerDeterminedPayload:
+			msg := wsproto.ServerLotWonMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerLotWon}}
+			msg.Payload.LotID = event.LotID
+			msg.Payload.BidID = payload.BidID
+			msg.Payload.Amount = payload.Amount
+			msg.Payload.FinalPrice = payload.FinalPrice
+			n.sendToUser(payload.WinnerID.String(), msg)
+		}
+	}
+	return nil
+}
+
+func (n *PrivateNotifier) sendToUser(userID string, msg any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("PrivateNotifier: failed to marshal message", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	n.hub.SendToUser(userID, data)
+}
+
+// Close implements domain.EventPublisher
+func (n *PrivateNotifier) Close() error { return nil }
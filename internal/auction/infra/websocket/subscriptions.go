@@ -0,0 +1,72 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleSubscriptionMessage subscribes the client to a topic, or unsubscribes it, and confirms it with a
+// server_subscribed msg
+func (h *AuctionWSHandler) handleSubscriptionMessage(ctx context.Context, client *websocket.Client, data []byte) {
+	var subMsg wsproto.ClientSubscriptionMessage
+	if err := json.Unmarshal(data, &subMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid subscription message format")
+		return
+	}
+	subscribe := subMsg.Type == wsproto.MessageTypeClientSubscribe
+	key, ok := h.topicKey(ctx, client, subMsg.Payload.Topic, subscribe)
+	if !ok {
+		h.sendErrorToClient(ctx, client, "invalid topic")
+		return
+	}
+	if subscribe {
+		h.hub.Subscribe(client, key)
+	} else {
+		h.hub.Unsubscribe(client, key)
+	}
+	logger.FromContext(ctx).Debug("Client subscription changed",
+		zap.String("clientID", client.ID),
+		zap.String("topic", subMsg.Payload.Topic),
+		zap.Bool("subscribed", subscribe),
+	)
+
+	confirm := wsproto.ServerSubscribedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerSubscribed}}
+	confirm.Payload.Topic = subMsg.Payload.Topic
+	confirm.Payload.Subscribed = subscribe
+	out, err := json.Marshal(confirm)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal ServerSubscribedMessage", zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(client.ID, out)
+}
+
+// topicKey returns the hub key of a topic requested by client, scoped to its organization. the lots of
+// other organizations can't be followed, checked when subscribing
+func (h *AuctionWSHandler) topicKey(ctx context.Context, client *websocket.Client, topic string, subscribe bool) (string, bool) {
+	switch {
+	case topic == wsproto.TopicLobby:
+		return websocket.LobbyRoom(client.OrgID), true
+	case strings.HasPrefix(topic, wsproto.TopicAuctionPrefix):
+		lotID, err := uuid.Parse(strings.TrimPrefix(topic, wsproto.TopicAuctionPrefix))
+		if err != nil {
+			return "", false
+		}
+		if subscribe {
+			if _, err := h.auctionService.GetLotState(ctx, lotID); err != nil {
+				return "", false
+			}
+		}
+		return websocket.AuctionTopic(lotID.String()), true
+	case strings.HasPrefix(topic, wsproto.TopicCategoryPrefix) && len(topic) > len(wsproto.TopicCategoryPrefix):
+		return websocket.ScopedTopic(topic, client.OrgID), true
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleAcceptTermsMessage records the acceptance of the terms of the lot by the client user and confirms
+// it with server_terms_accepted, the next bids of the user on the lot pass the terms check
+func (h *AuctionWSHandler) handleAcceptTermsMessage(ctx context.Context, client *websocket.Client, data []byte) {
+	if h.terms == nil {
+		h.sendErrorToClient(ctx, client, "terms acceptance is not enabled")
+		return
+	}
+	var termsMsg wsproto.ClientAcceptTermsMessage
+	if err := json.Unmarshal(data, &termsMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid accept terms message format")
+		return
+	}
+	if termsMsg.Payload.LotID.String() != client.LotID {
+		h.sendErrorToClient(ctx, client, "lot ID mismatch")
+		return
+	}
+	// anonymous spectators can't bid, nor accept terms
+	userID, err := uuid.Parse(client.UserID)
+	if err != nil {
+		h.sendErrorToClient(ctx, client, "unauthenticated connection")
+		return
+	}
+
+	acceptance, err := h.terms.AcceptTerms(ctx, application.AcceptTermsDTO{
+		LotID:        termsMsg.Payload.LotID,
+		UserID:       userID,
+		TermsVersion: termsMsg.Payload.TermsVersion,
+		ClientIP:     client.RemoteIP,
+	})
+	if err != nil {
+		h.sendErrorToClient(ctx, client, err.Error())
+		return
+	}
+	out := wsproto.ServerTermsAcceptedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerTermsAccepted}}
+	out.Payload.LotID = acceptance.LotID
+	out.Payload.TermsVersion = acceptance.TermsVersion
+	out.Payload.AcceptedAt = acceptance.AcceptedAt
+	outData, err := json.Marshal(out)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal ServerTermsAcceptedMessage", zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(client.ID, outData)
+}
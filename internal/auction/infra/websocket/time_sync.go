@@ -0,0 +1,36 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// handlePingTimeMessage answers a client_ping_time with the server time, echoing the client time so the
+// client can measure the round trip
+func (h *AuctionWSHandler) handlePingTimeMessage(ctx context.Context, client *websocket.Client, data []byte) {
+	var pingMsg wsproto.ClientPingTimeMessage
+	if err := json.Unmarshal(data, &pingMsg); err != nil {
+		h.sendErrorToClient(ctx, client, "invalid ping time message format")
+		return
+	}
+	h.sendServerTime(ctx, client, pingMsg.Payload.ClientTime)
+}
+
+// sendServerTime sends the time of the clock the lot rules run on to client, clientTime is 0 when it
+// didn't ask for it
+func (h *AuctionWSHandler) sendServerTime(ctx context.Context, client *websocket.Client, clientTime int64) {
+	timeMsg := wsproto.ServerTimeMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerTime}}
+	timeMsg.Payload.ServerTime = h.clock.Now().UnixMilli()
+	timeMsg.Payload.ClientTime = clientTime
+	data, err := json.Marshal(timeMsg)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal ServerTimeMessage", zap.Error(err))
+		return
+	}
+	h.hub.SendToClient(client.ID, data)
+}
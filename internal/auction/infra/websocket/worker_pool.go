@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+)
+
+// messageWorkerPool processes inbound WS msgs with a fixed number of workers. msgs are routed to
+// a worker by lot, so the msgs of a lot are processed one at a time in arrival order while
+// different lots are processed in parallel
+type messageWorkerPool struct {
+	queues []chan *websocket.ClientMessage
+	wg     sync.WaitGroup
+	// msgs submitted and not yet handled
+	pending atomic.Int64
+}
+
+// newMessageWorkerPool creates a pool of workers, each with a queue of queueSize pending msgs
+func newMessageWorkerPool(workers, queueSize int) *messageWorkerPool {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 1)
+	p := &messageWorkerPool{queues: make([]chan *websocket.ClientMessage, workers)}
+	for i := range p.queues {
+		p.queues[i] = make(chan *websocket.ClientMessage, queueSize)
+	}
+	return p
+}
+
+// start runs the workers until ctx is done, handle is called sequentially within each worker
+func (p *messageWorkerPool) start(ctx context.Context, handle func(ctx context.Context, msg *websocket.ClientMessage)) {
+	for _, queue := range p.queues {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-queue:
+					handle(ctx, msg)
+					p.pending.Add(-1)
+				}
+			}
+		}()
+	}
+}
+
+// submit queues msg in the worker of its lot, it returns false without blocking when that queue is full
+func (p *messageWorkerPool) submit(msg *websocket.ClientMessage) bool {
+	p.pending.Add(1)
+	select {
+	case p.queues[p.workerFor(msg.Client.LotID)] <- msg:
+		return true
+	default:
+		p.pending.Add(-1)
+		return false
+	}
+}
+
+// idle reports if every submitted msg has been handled
+func (p *messageWorkerPool) idle() bool {
+	return p.pending.Load() == 0
+}
+
+// wait blocks until all the workers have stopped
+func (p *messageWorkerPool) wait() {
+	p.wg.Wait()
+}
+
+func (p *messageWorkerPool) workerFor(lotID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(lotID))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
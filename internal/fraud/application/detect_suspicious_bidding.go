@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/fraud/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DetectionConfig holds the thresholds of the shill bidding heuristics
+type DetectionConfig struct {
+	// Window is how far back the bids of a lot are analyzed
+	Window time.Duration
+	// SharedIPMinOutbids is the number of times accounts sharing an IP must outbid each other to raise an alert
+	SharedIPMinOutbids int
+	// SelfOutbidMinBids consecutive bids of the leading user within SelfOutbidWindow raise an alert
+	SelfOutbidMinBids int
+	SelfOutbidWindow  time.Duration
+}
+
+// DefaultDetectionConfig is the detection config used in production
+var DefaultDetectionConfig = DetectionConfig{
+	Window:             time.Hour,
+	SharedIPMinOutbids: 3,
+	SelfOutbidMinBids:  3,
+	SelfOutbidWindow:   time.Minute,
+}
+
+// DetectSuspiciousBiddingUseCase analyzes the recent bids of a lot and records an alert
+// for every suspicious pattern found, patterns already under review are not duplicated
+type DetectSuspiciousBiddingUseCase struct {
+	bids   domain.BidActivityReader
+	alerts domain.AlertRepository
+	cfg    DetectionConfig
+}
+
+// NewDetectSuspiciousBiddingUseCase creates a new instance of DetectSuspiciousBiddingUseCase
+func NewDetectSuspiciousBiddingUseCase(bids domain.BidActivityReader, alerts domain.AlertRepository, cfg DetectionConfig) *DetectSuspiciousBiddingUseCase {
+	return &DetectSuspiciousBiddingUseCase{bids: bids, alerts: alerts, cfg: cfg}
+}
+
+// Execute runs the heuristics over the lot bids, it's called after every bid
+func (uc *DetectSuspiciousBiddingUseCase) Execute(ctx context.Context, lotID uuid.UUID) error {
+	bids, err := uc.bids.GetRecentBids(ctx, lotID, time.Now().Add(-uc.cfg.Window))
+	if err != nil {
+		return fmt.Errorf("detect suspicious bidding use case: failed to get bids of lot %s: %w", lotID, err)
+	}
+
+	alerts := append(uc.sharedIPAlerts(lotID, bids), uc.rapidSelfOutbidAlerts(lotID, bids)...)
+	for _, alert := range alerts {
+		created, err := uc.alerts.Create(ctx, alert)
+		if err != nil {
+			return fmt.Errorf("detect suspicious bidding use case: failed to save alert for lot %s: %w", lotID, err)
+		}
+		if created {
+			logger.FromContext(ctx).Warn("Suspicious bidding detected",
+				zap.String("alertID", alert.ID.String()),
+				zap.String("lotID", lotID.String()),
+				zap.String("rule", string(alert.Rule)),
+				zap.String("details", alert.Details),
+			)
+		}
+	}
+	return nil
+}
+
+// sharedIPAlerts flags pairs of accounts that outbid each other from the same IP
+func (uc *DetectSuspiciousBiddingUseCase) sharedIPAlerts(lotID uuid.UUID, bids []domain.BidActivity) []*domain.Alert {
+	type pair struct{ a, b uuid.UUID }
+	outbids := make(map[pair]int)
+	ips := make(map[pair]string)
+	for i := 1; i < len(bids); i++ {
+		prev, cur := bids[i-1], bids[i]
+		if prev.UserID == cur.UserID || cur.ClientIP == "" || prev.ClientIP != cur.ClientIP {
+			continue
+		}
+		p := pair{prev.UserID, cur.UserID}
+		if p.a.String() > p.b.String() {
+			p = pair{p.b, p.a}
+		}
+		outbids[p]++
+		ips[p] = cur.ClientIP
+	}
+
+	var alerts []*domain.Alert
+	for p, count := range outbids {
+		if count < uc.cfg.SharedIPMinOutbids {
+			continue
+		}
+		alerts = append(alerts, domain.NewAlert(lotID, domain.RuleSharedIPBidding, []uuid.UUID{p.a, p.b},
+			fmt.Sprintf("%d outbids between accounts connected from %s", count, ips[p])))
+	}
+	return alerts
+}
+
+// rapidSelfOutbidAlerts flags users raising their own leading bid several times within a short window
+func (uc *DetectSuspiciousBiddingUseCase) rapidSelfOutbidAlerts(lotID uuid.UUID, bids []domain.BidActivity) []*domain.Alert {
+	minBids := max(uc.cfg.SelfOutbidMinBids, 2)
+	flagged := make(map[uuid.UUID]bool)
+	var alerts []*domain.Alert
+	runStart := 0
+	for i := 1; i <= len(bids); i++ {
+		if i < len(bids) && bids[i].UserID == bids[runStart].UserID {
+			continue
+		}
+		// bids[runStart:i] are consecutive bids of the same user
+		run := bids[runStart:i]
+		userID := bids[runStart].UserID
+		for j := 0; j+minBids <= len(run) && !flagged[userID]; j++ {
+			first, last := run[j], run[j+minBids-1]
+			if last.Timestamp.Sub(first.Timestamp) <= uc.cfg.SelfOutbidWindow {
+				flagged[userID] = true
+				alerts = append(alerts, domain.NewAlert(lotID, domain.RuleRapidSelfOutbid, []uuid.UUID{userID},
+					fmt.Sprintf("%d consecutive bids from %.2f to %.2f in %s", minBids, first.Amount, last.Amount,
+						last.Timestamp.Sub(first.Timestamp).Round(time.Second))))
+			}
+		}
+		runStart = i
+	}
+	return alerts
+}
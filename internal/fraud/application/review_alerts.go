@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/fraud/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAlertsLimit = 50
+	maxAlertsLimit     = 200
+)
+
+// AlertDTO is the output DTO of a fraud alert
+type AlertDTO struct {
+	ID         uuid.UUID   `json:"id"`
+	LotID      uuid.UUID   `json:"lot_id"`
+	Rule       string      `json:"rule"`
+	UserIDs    []uuid.UUID `json:"user_ids"`
+	Details    string      `json:"details"`
+	Status     string      `json:"status"`
+	ReviewedBy *uuid.UUID  `json:"reviewed_by,omitempty"`
+	ReviewNote string      `json:"review_note,omitempty"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// ReviewAlertDTO is the input of ReviewAlertsUseCase.Review
+type ReviewAlertDTO struct {
+	AlertID    uuid.UUID
+	ReviewerID uuid.UUID
+	Status     string // dismissed or confirmed
+	Note       string
+}
+
+// ReviewAlertsUseCase lists the fraud alerts and records the admin reviews
+type ReviewAlertsUseCase struct {
+	alerts domain.AlertRepository
+}
+
+// NewReviewAlertsUseCase creates a new instance of ReviewAlertsUseCase
+func NewReviewAlertsUseCase(alerts domain.AlertRepository) *ReviewAlertsUseCase {
+	return &ReviewAlertsUseCase{alerts: alerts}
+}
+
+// List returns a page of alerts, newest first, status filters them when not empty
+func (uc *ReviewAlertsUseCase) List(ctx context.Context, status string, limit, offset int) ([]AlertDTO, error) {
+	switch domain.Status(status) {
+	case "", domain.StatusOpen, domain.StatusDismissed, domain.StatusConfirmed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidReviewStatus, status)
+	}
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+	alerts, err := uc.alerts.List(ctx, domain.Status(status), min(limit, maxAlertsLimit), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("review alerts use case: failed to list alerts: %w", err)
+	}
+	dtos := make([]AlertDTO, 0, len(alerts))
+	for _, alert := range alerts {
+		dtos = append(dtos, toAlertDTO(alert))
+	}
+	return dtos, nil
+}
+
+// Review closes an open alert as dismissed or confirmed
+func (uc *ReviewAlertsUseCase) Review(ctx context.Context, cmd ReviewAlertDTO) (*AlertDTO, error) {
+	alert, err := uc.alerts.GetByID(ctx, cmd.AlertID)
+	if err != nil {
+		return nil, fmt.Errorf("review alerts use case: failed to get alert %s: %w", cmd.AlertID, err)
+	}
+	if err := alert.Review(cmd.ReviewerID, domain.Status(cmd.Status), strings.TrimSpace(cmd.Note), time.Now()); err != nil {
+		return nil, fmt.Errorf("review alerts use case: %w", err)
+	}
+	if err := uc.alerts.SaveReview(ctx, alert); err != nil {
+		return nil, fmt.Errorf("review alerts use case: failed to save review of alert %s: %w", alert.ID, err)
+	}
+	logger.FromContext(ctx).Info("Fraud alert reviewed",
+		zap.String("alertID", alert.ID.String()),
+		zap.String("reviewerID", cmd.ReviewerID.String()),
+		zap.String("status", string(alert.Status)),
+	)
+	dto := toAlertDTO(alert)
+	return &dto, nil
+}
+
+func toAlertDTO(alert *domain.Alert) AlertDTO {
+	return AlertDTO{
+		ID:         alert.ID,
+		LotID:      alert.LotID,
+		Rule:       string(alert.Rule),
+		UserIDs:    alert.UserIDs,
+		Details:    alert.Details,
+		Status:     string(alert.Status),
+		ReviewedBy: alert.ReviewedBy,
+		ReviewNote: alert.ReviewNote,
+		ReviewedAt: alert.ReviewedAt,
+		CreatedAt:  alert.CreatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rule identifies the heuristic that raised an alert
+type Rule string
+
+const (
+	// RuleSharedIPBidding flags accounts connected from the same IP outbidding each other on a lot
+	RuleSharedIPBidding Rule = "shared_ip_bidding"
+	// RuleRapidSelfOutbid flags a leading bidder repeatedly raising their own bid in a short time
+	RuleRapidSelfOutbid Rule = "rapid_self_outbid"
+)
+
+// Status is the review status of an alert
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusDismissed Status = "dismissed" // reviewed, not fraud
+	StatusConfirmed Status = "confirmed" // reviewed, fraud
+)
+
+// Alert is a suspicious bidding pattern waiting for, or after, an admin review
+type Alert struct {
+	ID         uuid.UUID
+	LotID      uuid.UUID
+	Rule       Rule
+	UserIDs    []uuid.UUID // involved accounts, sorted
+	Details    string
+	Status     Status
+	ReviewedBy *uuid.UUID
+	ReviewNote string
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAlert creates a new open Alert
+func NewAlert(lotID uuid.UUID, rule Rule, userIDs []uuid.UUID, details string) *Alert {
+	sorted := slices.Clone(userIDs)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+	return &Alert{
+		ID:        uuid.New(),
+		LotID:     lotID,
+		Rule:      rule,
+		UserIDs:   slices.Compact(sorted),
+		Details:   details,
+		Status:    StatusOpen,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Review closes an open alert as dismissed or confirmed
+func (a *Alert) Review(reviewerID uuid.UUID, status Status, note string, at time.Time) error {
+	if status != StatusDismissed && status != StatusConfirmed {
+		return ErrInvalidReviewStatus
+	}
+	if a.Status != StatusOpen {
+		return ErrAlertAlreadyReviewed
+	}
+	a.Status = status
+	a.ReviewedBy = &reviewerID
+	a.ReviewNote = note
+	a.ReviewedAt = &at
+	return nil
+}
+
+// BidActivity is a bid as seen by the fraud heuristics
+type BidActivity struct {
+	BidID     uuid.UUID
+	UserID    uuid.UUID
+	Amount    float64
+	ClientIP  string
+	Timestamp time.Time
+}
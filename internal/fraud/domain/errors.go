@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrAlertNotFound        = errors.New("fraud alert not found")
+	ErrAlertAlreadyReviewed = errors.New("fraud alert is already reviewed")
+	ErrInvalidReviewStatus  = errors.New("review status must be dismissed or confirmed")
+)
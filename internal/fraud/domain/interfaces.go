@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertRepository persists the fraud alerts
+type AlertRepository interface {
+	// Create stores a new alert, it returns false when an open alert of the same pattern already exists
+	Create(ctx context.Context, alert *Alert) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Alert, error)
+	// List returns a page of alerts, newest first, an empty status matches any status
+	List(ctx context.Context, status Status, limit, offset int) ([]*Alert, error)
+	// SaveReview stores the review fields of alert
+	SaveReview(ctx context.Context, alert *Alert) error
+}
+
+// BidActivityReader provides the recent bids of a lot, implemented over the auction bids
+type BidActivityReader interface {
+	// GetRecentBids returns the valid bids of the lot placed after since, oldest first
+	GetRecentBids(ctx context.Context, lotID uuid.UUID, since time.Time) ([]BidActivity, error)
+}
@@ -0,0 +1,53 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/fraud/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// detectTimeout bounds the analysis triggered by a single bid
+const detectTimeout = 30 * time.Second
+
+// AuctionEventListener runs the fraud heuristics on every placed bid, it implements
+// auction domain.EventPublisher so it can be plugged next to the broker publisher
+type AuctionEventListener struct {
+	ctx      context.Context
+	detectUC *application.DetectSuspiciousBiddingUseCase
+}
+
+// NewAuctionEventListener creates a new instance of AuctionEventListener, ctx bounds the background analysis
+func NewAuctionEventListener(ctx context.Context, detectUC *application.DetectSuspiciousBiddingUseCase) *AuctionEventListener {
+	return &AuctionEventListener{ctx: ctx, detectUC: detectUC}
+}
+
+// Publish implements auction domain.EventPublisher, the analysis runs in background so it never
+// delays the bid flow
+func (l *AuctionEventListener) Publish(ctx context.Context, events ...auctiondomain.Event) error {
+	// the background work keeps the correlation ID of the request that produced the events
+	correlationID := logger.CorrelationID(ctx)
+	for _, event := range events {
+		if event.Type != auctiondomain.EventBidPlaced {
+			continue
+		}
+		lotID := event.LotID
+		go func() {
+			ctx, cancel := context.WithTimeout(logger.WithCorrelationID(l.ctx, correlationID), detectTimeout)
+			defer cancel()
+			if err := l.detectUC.Execute(ctx, lotID); err != nil {
+				logger.FromContext(ctx).Error("fraud AuctionEventListener: failed to analyze lot bids",
+					zap.String("lotID", lotID.String()),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+	return nil
+}
+
+// Close implements auction domain.EventPublisher
+func (l *AuctionEventListener) Close() error { return nil }
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristianortiz/auctionEngine/internal/fraud/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AlertRepository implements domain.AlertRepository interface
+type AlertRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAlertRepository creates a new instance of AlertRepository
+func NewAlertRepository(pool *pgxpool.Pool) *AlertRepository {
+	return &AlertRepository{pool: pool}
+}
+
+const alertColumns = `id, lot_id, rule, user_ids, details, status, reviewed_by, review_note, reviewed_at, created_at`
+
+// alertOrgFilter limits the alerts to the lots of the organization in the param, all of them when it's NULL
+const alertOrgFilter = `(%[1]s::uuid IS NULL OR lot_id IN (SELECT id FROM auction_lots WHERE org_id = %[1]s))`
+
+// Create inserts the alert unless an open alert with the same lot, rule and users exists
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) (bool, error) {
+	query := `
+        INSERT INTO fraud_alerts (id, lot_id, rule, user_ids, details, status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (lot_id, rule, user_ids) WHERE status = 'open' DO NOTHING
+    `
+	tag, err := r.pool.Exec(ctx, query,
+		alert.ID,
+		alert.LotID,
+		alert.Rule,
+		alert.UserIDs,
+		alert.Details,
+		alert.Status,
+		alert.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetByID returns an alert, or domain.ErrAlertNotFound, alerts on lots of other organizations are not found
+func (r *AlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM fraud_alerts WHERE id = $1 AND ` + fmt.Sprintf(alertOrgFilter, "$2")
+	alert, err := scanAlert(r.pool.QueryRow(ctx, query, id, tenant.Filter(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAlertNotFound
+		}
+		return nil, err
+	}
+	return alert, nil
+}
+
+// List returns a page of alerts, newest first
+func (r *AlertRepository) List(ctx context.Context, status domain.Status, limit, offset int) ([]*domain.Alert, error) {
+	query := `
+        SELECT ` + alertColumns + `
+        FROM fraud_alerts
+        WHERE ($1 = '' OR status = $1) AND ` + fmt.Sprintf(alertOrgFilter, "$4") + `
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.pool.Query(ctx, query, string(status), limit, offset, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// SaveReview stores the review fields of the alert
+func (r *AlertRepository) SaveReview(ctx context.Context, alert *domain.Alert) error {
+	query := `
+        UPDATE fraud_alerts
+        SET status = $2, reviewed_by = $3, review_note = $4, reviewed_at = $5
+        WHERE id = $1
+    `
+	_, err := r.pool.Exec(ctx, query, alert.ID, alert.Status, alert.ReviewedBy, alert.ReviewNote, alert.ReviewedAt)
+	return err
+}
+
+func scanAlert(row pgx.Row) (*domain.Alert, error) {
+	alert := &domain.Alert{}
+	err := row.Scan(
+		&alert.ID,
+		&alert.LotID,
+		&alert.Rule,
+		&alert.UserIDs,
+		&alert.Details,
+		&alert.Status,
+		&alert.ReviewedBy,
+		&alert.ReviewNote,
+		&alert.ReviewedAt,
+		&alert.CreatedAt,
+	)
+	return alert, err
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/fraud/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BidActivityReader implements domain.BidActivityReader over the auction bids table
+type BidActivityReader struct {
+	pool *pgxpool.Pool
+}
+
+// NewBidActivityReader creates a new instance of BidActivityReader
+func NewBidActivityReader(pool *pgxpool.Pool) *BidActivityReader {
+	return &BidActivityReader{pool: pool}
+}
+
+// GetRecentBids returns the not voided bids of the lot placed after since, oldest first
+func (r *BidActivityReader) GetRecentBids(ctx context.Context, lotID uuid.UUID, since time.Time) ([]domain.BidActivity, error) {
+	query := `
+        SELECT id, user_id, amount, client_ip, timestamp
+        FROM bids
+        WHERE lot_id = $1 AND timestamp > $2 AND voided_at IS NULL
+        ORDER BY timestamp ASC
+    `
+	rows, err := r.pool.Query(ctx, query, lotID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []domain.BidActivity
+	for rows.Next() {
+		var bid domain.BidActivity
+		if err := rows.Scan(&bid.BidID, &bid.UserID, &bid.Amount, &bid.ClientIP, &bid.Timestamp); err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
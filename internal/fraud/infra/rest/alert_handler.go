@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/fraud/application"
+	"github.com/cristianortiz/auctionEngine/internal/fraud/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AlertHandler exposes the fraud alerts review API to admins
+type AlertHandler struct {
+	reviewUC *application.ReviewAlertsUseCase
+}
+
+// NewAlertHandler creates a new instance of AlertHandler
+func NewAlertHandler(reviewUC *application.ReviewAlertsUseCase) *AlertHandler {
+	return &AlertHandler{reviewUC: reviewUC}
+}
+
+// reviewAlertRequest is the JSON body of POST /admin/fraud-alerts/:id/review
+type reviewAlertRequest struct {
+	Status string `json:"status"` // dismissed or confirmed
+	Note   string `json:"note"`
+}
+
+// RegisterRoutes registers the review endpoints, all of them guarded by requireAdmin
+func (h *AlertHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Get("/admin/fraud-alerts", requireAdmin, h.listAlerts)
+	router.Post("/admin/fraud-alerts/:id/review", requireAdmin, h.reviewAlert)
+}
+
+// listAlerts handles GET /admin/fraud-alerts?status=&limit=&offset=
+func (h *AlertHandler) listAlerts(c *fiber.Ctx) error {
+	alerts, err := h.reviewUC.List(c.UserContext(), c.Query("status"), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(alerts)
+}
+
+func (h *AlertHandler) reviewAlert(c *fiber.Ctx) error {
+	alertID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid alert ID")
+	}
+	var req reviewAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	alert, err := h.reviewUC.Review(c.UserContext(), application.ReviewAlertDTO{
+		AlertID:    alertID,
+		ReviewerID: httpserver.ClaimsFrom(c).UserID,
+		Status:     req.Status,
+		Note:       req.Note,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(alert)
+}
+
+// toHTTPError maps fraud domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrAlertNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidReviewStatus):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAlertAlreadyReviewed):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
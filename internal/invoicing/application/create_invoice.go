@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// LotWonDTO is the input of CreateInvoiceUseCase, built from the auction winner_determined event or, for
+// the lots of a bundle with a bid, from the bundle.settled one
+type LotWonDTO struct {
+	LotID       uuid.UUID
+	LotTitle    string
+	BuyerID     uuid.UUID
+	BidID       uuid.UUID
+	HammerPrice float64
+	Currency    string
+}
+
+// CreateInvoiceUseCase invoices the winner of a lot and announces the invoice for payment processing
+type CreateInvoiceUseCase struct {
+	invoiceRepo domain.InvoiceRepository
+	events      auctiondomain.EventPublisher
+	fees        domain.FeeScheduleProvider
+}
+
+// NewCreateInvoiceUseCase creates a new instance of CreateInvoiceUseCase
+func NewCreateInvoiceUseCase(invoiceRepo domain.InvoiceRepository, events auctiondomain.EventPublisher, fees domain.FeeScheduleProvider) *CreateInvoiceUseCase {
+	return &CreateInvoiceUseCase{invoiceRepo: invoiceRepo, events: events, fees: fees}
+}
+
+// Execute creates the invoice of the winning bid, a bid already invoiced is ignored so redeliveries are
+// safe. the winners of a multi-unit lot get an invoice each
+func (uc *CreateInvoiceUseCase) Execute(ctx context.Context, cmd LotWonDTO) (*domain.Invoice, error) {
+	log := logger.FromContext(ctx)
+	schedule, err := uc.fees.GetForLot(ctx, cmd.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("create invoice use case: failed to get fee schedule of lot %s: %w", cmd.LotID, err)
+	}
+	invoice := domain.NewInvoice(cmd.LotID, cmd.BuyerID, cmd.BidID, cmd.LotTitle, cmd.HammerPrice, cmd.Currency, schedule)
+	created, err := uc.invoiceRepo.Create(ctx, invoice)
+	if err != nil {
+		return nil, fmt.Errorf("create invoice use case: failed to save invoice of lot %s: %w", cmd.LotID, err)
+	}
+	if !created {
+		log.Info("CreateInvoiceUseCase: bid already invoiced",
+			zap.String("lotID", cmd.LotID.String()),
+			zap.String("bidID", cmd.BidID.String()),
+		)
+		return uc.invoiceRepo.GetByBidID(ctx, cmd.BidID)
+	}
+
+	log.Info("CreateInvoiceUseCase: invoice created",
+		zap.String("invoiceID", invoice.ID.String()),
+		zap.String("lotID", cmd.LotID.String()),
+		zap.String("buyerID", cmd.BuyerID.String()),
+		zap.Float64("total", invoice.Total),
+	)
+	event := auctiondomain.NewEvent(domain.EventInvoiceCreated, invoice.LotID, invoice.CreatedAt, domain.InvoiceCreatedPayload{
+		InvoiceID:    invoice.ID,
+		BuyerID:      invoice.BuyerID,
+		HammerPrice:  invoice.HammerPrice,
+		Currency:     invoice.Currency,
+		BuyerPremium: invoice.BuyerPremium,
+		FlatFee:      invoice.FlatFee,
+		Tax:          invoice.Tax,
+		Total:        invoice.Total,
+	})
+	// the invoice is stored, a failed publish is only logged
+	if err := uc.events.Publish(ctx, event); err != nil {
+		log.Error("CreateInvoiceUseCase: failed to publish invoice created event",
+			zap.String("invoiceID", invoice.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return invoice, nil
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultInvoicesLimit = 20
+	maxInvoicesLimit     = 100
+)
+
+// InvoiceDTO is the output DTO of an invoice
+type InvoiceDTO struct {
+	ID               uuid.UUID  `json:"id"`
+	LotID            uuid.UUID  `json:"lot_id"`
+	BuyerID          uuid.UUID  `json:"buyer_id"`
+	BidID            uuid.UUID  `json:"bid_id"`
+	LotTitle         string     `json:"lot_title"`
+	HammerPrice      float64    `json:"hammer_price"`
+	Currency         string     `json:"currency"`
+	FeeScheduleID    *uuid.UUID `json:"fee_schedule_id,omitempty"`
+	BuyerPremiumRate float64    `json:"buyer_premium_rate"`
+	BuyerPremium     float64    `json:"buyer_premium"`
+	FlatFee          float64    `json:"flat_fee"`
+	TaxRate          float64    `json:"tax_rate"`
+	Tax              float64    `json:"tax"`
+	Total            float64    `json:"total"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Viewer is the caller of the invoice queries, only admins and the buyer can see an invoice
+type Viewer struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// GetInvoicesUseCase retrieves invoices for their buyer or for admins
+type GetInvoicesUseCase struct {
+	invoiceRepo domain.InvoiceRepository
+}
+
+// NewGetInvoicesUseCase creates a new instance of GetInvoicesUseCase
+func NewGetInvoicesUseCase(invoiceRepo domain.InvoiceRepository) *GetInvoicesUseCase {
+	return &GetInvoicesUseCase{invoiceRepo: invoiceRepo}
+}
+
+// GetByID returns an invoice, domain.ErrInvoiceNotFound also hides the invoices of other buyers
+func (uc *GetInvoicesUseCase) GetByID(ctx context.Context, viewer Viewer, id uuid.UUID) (*InvoiceDTO, error) {
+	invoice, err := uc.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get invoices use case: failed to get invoice %s: %w", id, err)
+	}
+	return visibleInvoice(viewer, invoice)
+}
+
+// GetByLotID returns the invoice of a lot, the one of the viewer on a multi-unit lot. admins who didn't
+// win a unit get the invoice with the highest hammer price
+func (uc *GetInvoicesUseCase) GetByLotID(ctx context.Context, viewer Viewer, lotID uuid.UUID) (*InvoiceDTO, error) {
+	invoices, err := uc.invoiceRepo.ListByLotID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("get invoices use case: failed to get invoices of lot %s: %w", lotID, err)
+	}
+	if len(invoices) == 0 {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	for _, invoice := range invoices {
+		if invoice.BuyerID == viewer.UserID {
+			return visibleInvoice(viewer, invoice)
+		}
+	}
+	return visibleInvoice(viewer, invoices[0])
+}
+
+// ListByBuyer returns a page of the buyer invoices, newest first
+func (uc *GetInvoicesUseCase) ListByBuyer(ctx context.Context, buyerID uuid.UUID, limit, offset int) ([]InvoiceDTO, error) {
+	if limit <= 0 {
+		limit = defaultInvoicesLimit
+	}
+	invoices, err := uc.invoiceRepo.ListByBuyer(ctx, buyerID, min(limit, maxInvoicesLimit), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("get invoices use case: failed to list invoices of buyer %s: %w", buyerID, err)
+	}
+	dtos := make([]InvoiceDTO, 0, len(invoices))
+	for _, invoice := range invoices {
+		dtos = append(dtos, toInvoiceDTO(invoice))
+	}
+	return dtos, nil
+}
+
+func visibleInvoice(viewer Viewer, invoice *domain.Invoice) (*InvoiceDTO, error) {
+	if !viewer.Admin && viewer.UserID != invoice.BuyerID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	dto := toInvoiceDTO(invoice)
+	return &dto, nil
+}
+
+func toInvoiceDTO(invoice *domain.Invoice) InvoiceDTO {
+	return InvoiceDTO{
+		ID:               invoice.ID,
+		LotID:            invoice.LotID,
+		BuyerID:          invoice.BuyerID,
+		BidID:            invoice.BidID,
+		LotTitle:         invoice.LotTitle,
+		HammerPrice:      invoice.HammerPrice,
+		Currency:         invoice.Currency,
+		FeeScheduleID:    invoice.FeeScheduleID,
+		BuyerPremiumRate: invoice.BuyerPremiumRate,
+		BuyerPremium:     invoice.BuyerPremium,
+		FlatFee:          invoice.FlatFee,
+		TaxRate:          invoice.TaxRate,
+		Tax:              invoice.Tax,
+		Total:            invoice.Total,
+		Status:           string(invoice.Status),
+		CreatedAt:        invoice.CreatedAt,
+	}
+}
@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvoiceNotFound = errors.New("invoice not found")
+)
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// InvoiceRepository persists the invoices
+type InvoiceRepository interface {
+	// Create stores a new invoice, it returns false when the winning bid was already invoiced
+	Create(ctx context.Context, invoice *Invoice) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Invoice, error)
+	GetByBidID(ctx context.Context, bidID uuid.UUID) (*Invoice, error)
+	// ListByLotID returns the invoices of a lot, one per unit won on a multi-unit lot, highest hammer price first
+	ListByLotID(ctx context.Context, lotID uuid.UUID) ([]*Invoice, error)
+	// ListByBuyer returns a page of the buyer invoices, newest first
+	ListByBuyer(ctx context.Context, buyerID uuid.UUID, limit, offset int) ([]*Invoice, error)
+}
+
+// FeeScheduleProvider returns the fee schedule applied to a lot, implemented by the auction module
+type FeeScheduleProvider interface {
+	GetForLot(ctx context.Context, lotID uuid.UUID) (*auctiondomain.FeeSchedule, error)
+}
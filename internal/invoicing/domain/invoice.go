@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/google/uuid"
+)
+
+// EventInvoiceCreated is published for downstream payment processing once a lot is invoiced
+const EventInvoiceCreated auctiondomain.EventType = "invoice.created"
+
+// Status is the payment status of an invoice
+type Status string
+
+const (
+	StatusIssued Status = "issued"
+	StatusPaid   Status = "paid"
+)
+
+// Invoice is the amount owed by the winner of a lot: the hammer price plus the buyer's premium
+// and flat fee of the lot fee schedule, with the tax applied over all of them. a won bundle is invoiced
+// once, on its first lot with the bundle bid as winning bid
+type Invoice struct {
+	ID               uuid.UUID
+	LotID            uuid.UUID
+	BuyerID          uuid.UUID
+	BidID            uuid.UUID // winning bid
+	LotTitle         string
+	HammerPrice      float64
+	Currency         string     // the lot currency, every amount is in it
+	FeeScheduleID    *uuid.UUID // nil when the configured rates applied
+	BuyerPremiumRate float64    // effective fraction of the hammer price, e.g. 0.15
+	BuyerPremium     float64
+	FlatFee          float64
+	TaxRate          float64 // fraction of hammer price, premium and flat fee
+	Tax              float64
+	Total            float64
+	Status           Status
+	CreatedAt        time.Time
+}
+
+// NewInvoice creates a new issued Invoice applying the fee schedule to the hammer price
+func NewInvoice(lotID, buyerID, bidID uuid.UUID, lotTitle string, hammerPrice float64, currency string, schedule *auctiondomain.FeeSchedule) *Invoice {
+	fees := schedule.Apply(hammerPrice, currency)
+	invoice := &Invoice{
+		ID:           uuid.New(),
+		LotID:        lotID,
+		BuyerID:      buyerID,
+		BidID:        bidID,
+		LotTitle:     lotTitle,
+		HammerPrice:  hammerPrice,
+		Currency:     currency,
+		BuyerPremium: fees.BuyerPremium,
+		FlatFee:      fees.FlatFee,
+		TaxRate:      schedule.TaxRate,
+		Tax:          fees.Tax,
+		Total:        fees.Total,
+		Status:       StatusIssued,
+		CreatedAt:    time.Now(),
+	}
+	if schedule.ID != uuid.Nil {
+		invoice.FeeScheduleID = &schedule.ID
+	}
+	// tiered premiums have no single rate, the effective one is kept for display
+	if hammerPrice > 0 {
+		invoice.BuyerPremiumRate = math.Round(fees.BuyerPremium/hammerPrice*10000) / 10000
+	}
+	return invoice
+}
+
+// InvoiceCreatedPayload is the payload of EventInvoiceCreated
+type InvoiceCreatedPayload struct {
+	InvoiceID    uuid.UUID `json:"invoice_id"`
+	BuyerID      uuid.UUID `json:"buyer_id"`
+	HammerPrice  float64   `json:"hammer_price"`
+	Currency     string    `json:"currency"`
+	BuyerPremium float64   `json:"buyer_premium"`
+	FlatFee      float64   `json:"flat_fee"`
+	Tax          float64   `json:"tax"`
+	Total        float64   `json:"total"`
+}
@@ -0,0 +1,99 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// invoiceTimeout bounds the creation of the invoice of a lot
+const invoiceTimeout = 30 * time.Second
+
+// AuctionEventListener invoices the winner of every finished lot, and the bundle bid of the won bundles
+// instead of the winners of their lots. it implements auction domain.EventPublisher so it can be plugged
+// next to the broker publisher
+type AuctionEventListener struct {
+	ctx       context.Context
+	invoiceUC *application.CreateInvoiceUseCase
+}
+
+// NewAuctionEventListener creates a new instance of AuctionEventListener, ctx bounds the background work
+func NewAuctionEventListener(ctx context.Context, invoiceUC *application.CreateInvoiceUseCase) *AuctionEventListener {
+	return &AuctionEventListener{ctx: ctx, invoiceUC: invoiceUC}
+}
+
+// Publish implements auction domain.EventPublisher, invoices are created in background
+func (l *AuctionEventListener) Publish(ctx context.Context, events ...auctiondomain.Event) error {
+	// the background work keeps the correlation ID of the request that produced the events
+	correlationID := logger.CorrelationID(ctx)
+	for _, event := range events {
+		var won []application.LotWonDTO
+		switch payload := event.Payload.(type) {
+		case auctiondomain.WinnerDeterminedPayload:
+			// the lots of a bundle with a bid are invoiced once the bundle is settled
+			if payload.BundleID != nil {
+				continue
+			}
+			won = append(won, application.LotWonDTO{
+				LotID:       event.LotID,
+				LotTitle:    payload.LotTitle,
+				BuyerID:     payload.WinnerID,
+				BidID:       payload.BidID,
+				HammerPrice: payload.FinalPrice,
+				Currency:    payload.Currency,
+			})
+		case auctiondomain.BundleSettledPayload:
+			won = bundleWon(event, payload)
+		default:
+			continue
+		}
+		for _, lotWon := range won {
+			go func() {
+				ctx, cancel := context.WithTimeout(logger.WithCorrelationID(l.ctx, correlationID), invoiceTimeout)
+				defer cancel()
+				if _, err := l.invoiceUC.Execute(ctx, lotWon); err != nil {
+					logger.FromContext(ctx).Error("invoicing AuctionEventListener: failed to invoice lot",
+						zap.String("lotID", lotWon.LotID.String()),
+						zap.String("bidID", lotWon.BidID.String()),
+						zap.Error(err),
+					)
+				}
+			}()
+		}
+	}
+	return nil
+}
+
+// bundleWon returns what is invoiced for a settled bundle: the bundle bid of a won bundle, as a single
+// invoice on the first lot of the bundle, or the winners of its lots otherwise
+func bundleWon(event auctiondomain.Event, payload auctiondomain.BundleSettledPayload) []application.LotWonDTO {
+	if payload.State == auctiondomain.BundleWon {
+		return []application.LotWonDTO{{
+			LotID:       event.LotID,
+			LotTitle:    payload.Title,
+			BuyerID:     payload.WinnerID,
+			BidID:       payload.BidID,
+			HammerPrice: payload.Amount,
+			Currency:    payload.Currency,
+		}}
+	}
+	won := make([]application.LotWonDTO, 0, len(payload.LotWinners))
+	for _, winner := range payload.LotWinners {
+		won = append(won, application.LotWonDTO{
+			LotID:       winner.LotID,
+			LotTitle:    winner.LotTitle,
+			BuyerID:     winner.WinnerID,
+			BidID:       winner.BidID,
+			HammerPrice: winner.FinalPrice,
+			Currency:    payload.Currency,
+		})
+	}
+	return won
+}
+
+// Close implements auction domain.EventPublisher
+func (l *AuctionEventListener) Close() error { return nil }
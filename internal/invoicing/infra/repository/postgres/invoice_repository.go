@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InvoiceRepository implements domain.InvoiceRepository interface
+type InvoiceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewInvoiceRepository creates a new instance of InvoiceRepository
+func NewInvoiceRepository(pool *pgxpool.Pool) *InvoiceRepository {
+	return &InvoiceRepository{pool: pool}
+}
+
+const invoiceColumns = `id, lot_id, buyer_id, bid_id, lot_title, hammer_price, currency, fee_schedule_id, buyer_premium_rate,
+        buyer_premium, flat_fee, tax_rate, tax, total, status, created_at`
+
+// invoiceOrgFilter limits the invoices to the lots of the organization in $2, all of them when it's NULL
+const invoiceOrgFilter = `($2::uuid IS NULL OR lot_id IN (SELECT id FROM auction_lots WHERE org_id = $2))`
+
+// Create inserts the invoice unless its winning bid already has one
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *domain.Invoice) (bool, error) {
+	query := `
+        INSERT INTO invoices (` + invoiceColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        ON CONFLICT (bid_id) DO NOTHING
+    `
+	tag, err := r.pool.Exec(ctx, query,
+		invoice.ID,
+		invoice.LotID,
+		invoice.BuyerID,
+		invoice.BidID,
+		invoice.LotTitle,
+		invoice.HammerPrice,
+		invoice.Currency,
+		invoice.FeeScheduleID,
+		invoice.BuyerPremiumRate,
+		invoice.BuyerPremium,
+		invoice.FlatFee,
+		invoice.TaxRate,
+		invoice.Tax,
+		invoice.Total,
+		invoice.Status,
+		invoice.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetByID returns an invoice, or domain.ErrInvoiceNotFound, invoices of lots of other organizations are not found
+func (r *InvoiceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE id = $1 AND ` + invoiceOrgFilter
+	return getInvoice(r.pool.QueryRow(ctx, query, id, tenant.Filter(ctx)))
+}
+
+// GetByBidID returns the invoice of a winning bid, or domain.ErrInvoiceNotFound, invoices of lots of other
+// organizations are not found
+func (r *InvoiceRepository) GetByBidID(ctx context.Context, bidID uuid.UUID) (*domain.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE bid_id = $1 AND ` + invoiceOrgFilter
+	return getInvoice(r.pool.QueryRow(ctx, query, bidID, tenant.Filter(ctx)))
+}
+
+// ListByLotID returns the invoices of a lot, highest hammer price first, invoices of lots of other
+// organizations are not listed
+func (r *InvoiceRepository) ListByLotID(ctx context.Context, lotID uuid.UUID) ([]*domain.Invoice, error) {
+	query := `
+        SELECT ` + invoiceColumns + `
+        FROM invoices
+        WHERE lot_id = $1 AND ` + invoiceOrgFilter + `
+        ORDER BY hammer_price DESC, created_at
+    `
+	rows, err := r.pool.Query(ctx, query, lotID, tenant.Filter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*domain.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// ListByBuyer returns a page of the buyer invoices, newest first
+func (r *InvoiceRepository) ListByBuyer(ctx context.Context, buyerID uuid.UUID, limit, offset int) ([]*domain.Invoice, error) {
+	query := `
+        SELECT ` + invoiceColumns + `
+        FROM invoices
+        WHERE buyer_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.pool.Query(ctx, query, buyerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*domain.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func getInvoice(row pgx.Row) (*domain.Invoice, error) {
+	invoice, err := scanInvoice(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func scanInvoice(row pgx.Row) (*domain.Invoice, error) {
+	invoice := &domain.Invoice{}
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.LotID,
+		&invoice.BuyerID,
+		&invoice.BidID,
+		&invoice.LotTitle,
+		&invoice.HammerPrice,
+		&invoice.Currency,
+		&invoice.FeeScheduleID,
+		&invoice.BuyerPremiumRate,
+		&invoice.BuyerPremium,
+		&invoice.FlatFee,
+		&invoice.TaxRate,
+		&invoice.Tax,
+		&invoice.Total,
+		&invoice.Status,
+		&invoice.CreatedAt,
+	)
+	return invoice, err
+}
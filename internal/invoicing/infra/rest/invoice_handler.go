@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/application"
+	"github.com/cristianortiz/auctionEngine/internal/invoicing/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InvoiceHandler exposes the invoices to their buyers and to admins
+type InvoiceHandler struct {
+	invoicesUC *application.GetInvoicesUseCase
+}
+
+// NewInvoiceHandler creates a new instance of InvoiceHandler
+func NewInvoiceHandler(invoicesUC *application.GetInvoicesUseCase) *InvoiceHandler {
+	return &InvoiceHandler{invoicesUC: invoicesUC}
+}
+
+// RegisterRoutes registers the invoice endpoints, requireUser must authenticate the caller
+func (h *InvoiceHandler) RegisterRoutes(router fiber.Router, requireUser fiber.Handler) {
+	router.Get("/invoices/:id", requireUser, h.getInvoice)
+	router.Get("/lots/:id/invoice", requireUser, h.getLotInvoice)
+	router.Get("/users/me/invoices", requireUser, h.listMyInvoices)
+}
+
+func (h *InvoiceHandler) getInvoice(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid invoice ID")
+	}
+	invoice, err := h.invoicesUC.GetByID(c.UserContext(), viewerOf(c), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(invoice)
+}
+
+func (h *InvoiceHandler) getLotInvoice(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	invoice, err := h.invoicesUC.GetByLotID(c.UserContext(), viewerOf(c), lotID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(invoice)
+}
+
+// listMyInvoices handles GET /users/me/invoices?limit=&offset=
+func (h *InvoiceHandler) listMyInvoices(c *fiber.Ctx) error {
+	invoices, err := h.invoicesUC.ListByBuyer(c.UserContext(), httpserver.ClaimsFrom(c).UserID, c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(invoices)
+}
+
+func viewerOf(c *fiber.Ctx) application.Viewer {
+	claims := httpserver.ClaimsFrom(c)
+	return application.Viewer{UserID: claims.UserID, Admin: claims.Can(auth.PermReadInvoices)}
+}
+
+// toHTTPError maps invoicing domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
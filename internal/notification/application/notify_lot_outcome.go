@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy configures the delivery retries of a notification
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // delay before the 2nd attempt, doubled on every retry
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries 5 times starting at 1s up to 1m
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+// backoff returns the delay before attempt (1 based), exponential with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+// LotOutcomeDTO is the input DTO of NotifyLotOutcomeUseCase
+type LotOutcomeDTO struct {
+	LotID         uuid.UUID
+	LotTitle      string
+	FinalPrice    float64
+	WinnerID      uuid.UUID
+	OutbidUserIDs []uuid.UUID
+}
+
+// NotifyLotOutcomeUseCase notifies the winner and the outbid users of a finished lot
+// through every configured channel, tracking the delivery status of each notification
+type NotifyLotOutcomeUseCase struct {
+	repo       domain.NotificationRepository
+	recipients domain.RecipientResolver
+	senders    []domain.Sender
+	templates  *Templates
+	retry      RetryPolicy
+}
+
+// NewNotifyLotOutcomeUseCase creates a new instance of NotifyLotOutcomeUseCase
+func NewNotifyLotOutcomeUseCase(repo domain.NotificationRepository,
+	recipients domain.RecipientResolver,
+	templates *Templates,
+	retry RetryPolicy,
+	senders ...domain.Sender) *NotifyLotOutcomeUseCase {
+	return &NotifyLotOutcomeUseCase{
+		repo:       repo,
+		recipients: recipients,
+		senders:    senders,
+		templates:  templates,
+		retry:      retry,
+	}
+}
+
+// Execute sends all the notifications of a lot outcome, it only returns an error if
+// a notification could not be created, delivery failures are tracked in the repository
+func (uc *NotifyLotOutcomeUseCase) Execute(ctx context.Context, outcome LotOutcomeDTO) error {
+	var errs []error
+	if err := uc.notifyUser(ctx, outcome, outcome.WinnerID, domain.KindLotWon); err != nil {
+		errs = append(errs, err)
+	}
+	for _, userID := range outcome.OutbidUserIDs {
+		if err := uc.notifyUser(ctx, outcome, userID, domain.KindOutbid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *NotifyLotOutcomeUseCase) notifyUser(ctx context.Context, outcome LotOutcomeDTO, userID uuid.UUID, kind domain.Kind) error {
+	recipient, err := uc.recipients.GetRecipient(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("notify lot outcome: failed to get recipient %s: %w", userID, err)
+	}
+	if recipient == nil {
+		return fmt.Errorf("notify lot outcome: %w: %s", domain.ErrRecipientNotFound, userID)
+	}
+
+	subject, body, err := uc.templates.Render(kind, TemplateData{
+		RecipientName: recipient.Name,
+		LotTitle:      outcome.LotTitle,
+		FinalPrice:    outcome.FinalPrice,
+	})
+	if err != nil {
+		return fmt.Errorf("notify lot outcome: failed to render %s template: %w", kind, err)
+	}
+
+	for _, sender := range uc.senders {
+		n := domain.NewNotification(userID, outcome.LotID, kind, sender.Channel(), subject, body)
+		if err := uc.repo.Save(ctx, n); err != nil {
+			return fmt.Errorf("notify lot outcome: failed to save notification: %w", err)
+		}
+		uc.deliver(ctx, sender, recipient, n)
+	}
+	return nil
+}
+
+// deliver sends n retrying with exponential backoff, persisting the status after every attempt
+func (uc *NotifyLotOutcomeUseCase) deliver(ctx context.Context, sender domain.Sender, recipient *domain.Recipient, n *domain.Notification) {
+	for attempt := 1; attempt <= uc.retry.MaxAttempts; attempt++ {
+		err := sender.Send(ctx, recipient, n)
+		if err == nil {
+			n.MarkSent(time.Now())
+			uc.saveStatus(ctx, n)
+			return
+		}
+
+		// a recipient without address for this channel will never succeed
+		giveUp := attempt == uc.retry.MaxAttempts || errors.Is(err, domain.ErrNoAddress)
+		n.MarkAttemptFailed(err, giveUp)
+		uc.saveStatus(ctx, n)
+		logger.FromContext(ctx).Warn("Notification delivery failed",
+			zap.String("notificationID", n.ID.String()),
+			zap.String("channel", string(n.Channel)),
+			zap.Int("attempt", attempt),
+			zap.Bool("giveUp", giveUp),
+			zap.Error(err),
+		)
+		if giveUp {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(uc.retry.backoff(attempt)):
+		}
+	}
+}
+
+func (uc *NotifyLotOutcomeUseCase) saveStatus(ctx context.Context, n *domain.Notification) {
+	if err := uc.repo.Save(ctx, n); err != nil {
+		logger.FromContext(ctx).Error("Failed to save notification status",
+			zap.String("notificationID", n.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
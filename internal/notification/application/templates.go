@@ -0,0 +1,55 @@
+package application
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// TemplateData is the data available to notification templates
+type TemplateData struct {
+	RecipientName string
+	LotTitle      string
+	FinalPrice    float64
+}
+
+// Templates renders the subject and body of each notification kind,
+// every kind has a templates/<kind>.tmpl file defining "subject" and "body"
+type Templates struct {
+	byKind map[domain.Kind]*template.Template
+}
+
+// NewTemplates parses the embedded templates
+func NewTemplates() (*Templates, error) {
+	t := &Templates{byKind: make(map[domain.Kind]*template.Template)}
+	for _, kind := range []domain.Kind{domain.KindLotWon, domain.KindOutbid} {
+		tmpl, err := template.ParseFS(templatesFS, "templates/"+string(kind)+".tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s notification template: %w", kind, err)
+		}
+		t.byKind[kind] = tmpl
+	}
+	return t, nil
+}
+
+// Render returns the subject and body for kind
+func (t *Templates) Render(kind domain.Kind, data TemplateData) (subject, body string, err error) {
+	tmpl, ok := t.byKind[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification kind %s", kind)
+	}
+	var sb, bb bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&sb, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&bb, "body", data); err != nil {
+		return "", "", err
+	}
+	return sb.String(), bb.String(), nil
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrRecipientNotFound = errors.New("notification recipient not found")
+	ErrNoAddress         = errors.New("recipient has no address for this channel")
+)
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Save(ctx context.Context, n *Notification) error
+}
+
+// Sender delivers notifications through a single channel
+type Sender interface {
+	Channel() Channel
+	Send(ctx context.Context, recipient *Recipient, n *Notification) error
+}
+
+// RecipientResolver provides the contact data of a user, it returns nil if the user doesn't exist
+type RecipientResolver interface {
+	GetRecipient(ctx context.Context, userID uuid.UUID) (*Recipient, error)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the reason of a notification
+type Kind string
+
+const (
+	KindLotWon Kind = "lot_won" // the user won the lot
+	KindOutbid Kind = "outbid"  // the user bid on the lot but didn't win it
+)
+
+// Channel is the delivery channel of a notification
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelWebhook Channel = "webhook"
+)
+
+// Status is the delivery status of a notification
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed"
+)
+
+// Notification is a message to a user through a channel, tracked until it's delivered or gives up
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	LotID     uuid.UUID
+	Kind      Kind
+	Channel   Channel
+	Subject   string
+	Body      string
+	Status    Status
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	SentAt    *time.Time
+}
+
+// NewNotification creates a new pending Notification
+func NewNotification(userID, lotID uuid.UUID, kind Kind, channel Channel, subject, body string) *Notification {
+	return &Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		LotID:     lotID,
+		Kind:      kind,
+		Channel:   channel,
+		Subject:   subject,
+		Body:      body,
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// MarkAttemptFailed records a failed delivery attempt, the notification is failed once it gives up
+func (n *Notification) MarkAttemptFailed(err error, giveUp bool) {
+	n.Attempts++
+	n.LastError = err.Error()
+	if giveUp {
+		n.Status = StatusFailed
+	}
+}
+
+// MarkSent records a successful delivery
+func (n *Notification) MarkSent(at time.Time) {
+	n.Attempts++
+	n.Status = StatusSent
+	n.LastError = ""
+	n.SentAt = &at
+}
+
+// Recipient holds the contact data of a user
+type Recipient struct {
+	UserID uuid.UUID
+	Name   string
+	Email  string
+}
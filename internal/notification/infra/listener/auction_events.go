@@ -0,0 +1,64 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/notification/application"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// notifyTimeout bounds the delivery (including retries) of the notifications of a lot outcome
+const notifyTimeout = 10 * time.Minute
+
+// AuctionEventListener consumes auction domain events in-process, it implements auction domain.EventPublisher
+// so it can be plugged next to the broker publisher
+type AuctionEventListener struct {
+	ctx      context.Context
+	notifyUC *application.NotifyLotOutcomeUseCase
+}
+
+// NewAuctionEventListener creates a new instance of AuctionEventListener, ctx bounds the background deliveries
+func NewAuctionEventListener(ctx context.Context, notifyUC *application.NotifyLotOutcomeUseCase) *AuctionEventListener {
+	return &AuctionEventListener{ctx: ctx, notifyUC: notifyUC}
+}
+
+// Publish implements auction domain.EventPublisher, notifications are sent in background
+// so retries never block the auction flow
+func (l *AuctionEventListener) Publish(ctx context.Context, events ...auctiondomain.Event) error {
+	// the background work keeps the correlation ID of the request that produced the events
+	correlationID := logger.CorrelationID(ctx)
+	for _, event := range events {
+		if event.Type != auctiondomain.EventWinnerDetermined {
+			continue
+		}
+		payload, ok := event.Payload.(auctiondomain.WinnerDeterminedPayload)
+		if !ok {
+			logger.FromContext(ctx).Error("AuctionEventListener: unexpected payload", zap.String("type", string(event.Type)))
+			continue
+		}
+		outcome := application.LotOutcomeDTO{
+			LotID:         event.LotID,
+			LotTitle:      payload.LotTitle,
+			FinalPrice:    payload.FinalPrice,
+			WinnerID:      payload.WinnerID,
+			OutbidUserIDs: payload.OutbidUserIDs,
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(logger.WithCorrelationID(l.ctx, correlationID), notifyTimeout)
+			defer cancel()
+			if err := l.notifyUC.Execute(ctx, outcome); err != nil {
+				logger.FromContext(ctx).Error("AuctionEventListener: failed to notify lot outcome",
+					zap.String("lotID", outcome.LotID.String()),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+	return nil
+}
+
+// Close implements auction domain.EventPublisher
+func (l *AuctionEventListener) Close() error { return nil }
@@ -0,0 +1,28 @@
+package recipients
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+	userdomain "github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+)
+
+// UserRecipientResolver implements domain.RecipientResolver on top of the user module repository
+type UserRecipientResolver struct {
+	users userdomain.UserRepository
+}
+
+// NewUserRecipientResolver creates a new instance of UserRecipientResolver
+func NewUserRecipientResolver(users userdomain.UserRepository) *UserRecipientResolver {
+	return &UserRecipientResolver{users: users}
+}
+
+// GetRecipient implements domain.RecipientResolver
+func (r *UserRecipientResolver) GetRecipient(ctx context.Context, userID uuid.UUID) (*domain.Recipient, error) {
+	user, err := r.users.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &domain.Recipient{UserID: user.ID, Name: user.Username, Email: user.Email}, nil
+}
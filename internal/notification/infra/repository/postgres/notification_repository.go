@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationRepository implements domain.NotificationRepository interface
+type NotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{pool: pool}
+}
+
+// Save inserts the notification or updates its delivery status if it already exists
+func (r *NotificationRepository) Save(ctx context.Context, n *domain.Notification) error {
+	query := `
+        INSERT INTO notifications (id, user_id, lot_id, kind, channel, subject, body, status, attempts, last_error, created_at, sent_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (id) DO UPDATE
+        SET
+            status = EXCLUDED.status,
+            attempts = EXCLUDED.attempts,
+            last_error = EXCLUDED.last_error,
+            sent_at = EXCLUDED.sent_at,
+            updated_at = NOW();
+    `
+	_, err := r.pool.Exec(ctx, query,
+		n.ID,
+		n.UserID,
+		n.LotID,
+		n.Kind,
+		n.Channel,
+		n.Subject,
+		n.Body,
+		n.Status,
+		n.Attempts,
+		n.LastError,
+		n.CreatedAt,
+		n.SentAt,
+	)
+	return err
+}
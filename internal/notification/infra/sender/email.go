@@ -0,0 +1,65 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+)
+
+// SMTPConfig holds the SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// EmailSender implements domain.Sender sending plain text emails through SMTP
+type EmailSender struct {
+	cfg SMTPConfig
+}
+
+// NewEmailSender creates a new instance of EmailSender
+func NewEmailSender(cfg SMTPConfig) *EmailSender {
+	return &EmailSender{cfg: cfg}
+}
+
+// Channel implements domain.Sender
+func (s *EmailSender) Channel() domain.Channel { return domain.ChannelEmail }
+
+// Send implements domain.Sender
+func (s *EmailSender) Send(ctx context.Context, recipient *domain.Recipient, n *domain.Notification) error {
+	if recipient.Email == "" {
+		return domain.ErrNoAddress
+	}
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", recipient.Email)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(n.Body)
+
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	// net/smtp has no context support, run it in a goroutine so ctx cancellation is honored
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, s.cfg.From, []string{recipient.Email}, []byte(msg.String()))
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,70 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/notification/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookSender implements domain.Sender posting notifications as JSON to an outbound webhook
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSender creates a new instance of WebhookSender
+func NewWebhookSender(url string, timeout time.Duration) *WebhookSender {
+	return &WebhookSender{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+type webhookPayload struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	Kind           string    `json:"kind"`
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email,omitempty"`
+	LotID          uuid.UUID `json:"lot_id"`
+	Subject        string    `json:"subject"`
+	Body           string    `json:"body"`
+}
+
+// Channel implements domain.Sender
+func (s *WebhookSender) Channel() domain.Channel { return domain.ChannelWebhook }
+
+// Send implements domain.Sender, any non 2xx response is a failed attempt
+func (s *WebhookSender) Send(ctx context.Context, recipient *domain.Recipient, n *domain.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		NotificationID: n.ID,
+		Kind:           string(n.Kind),
+		UserID:         n.UserID,
+		Email:          recipient.Email,
+		LotID:          n.LotID,
+		Subject:        n.Subject,
+		Body:           n.Body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// lets the receiver de-duplicate retried deliveries
+	req.Header.Set("Idempotency-Key", n.ID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/organization/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// slugInvalidChars matches the runs of chars replaced by "-" when deriving a slug from a name
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// OrganizationDTO is the output DTO of an organization
+type OrganizationDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOrganizationDTO is the input of ManageOrganizationsUseCase.Create, an empty Slug is derived from Name
+type CreateOrganizationDTO struct {
+	Name string
+	Slug string
+}
+
+// ManageOrganizationsUseCase is the platform management of the organizations hosted by the deployment
+type ManageOrganizationsUseCase struct {
+	orgRepo domain.OrganizationRepository
+}
+
+// NewManageOrganizationsUseCase creates a new instance of ManageOrganizationsUseCase
+func NewManageOrganizationsUseCase(orgRepo domain.OrganizationRepository) *ManageOrganizationsUseCase {
+	return &ManageOrganizationsUseCase{orgRepo: orgRepo}
+}
+
+// Create adds an organization, its admins get tokens with its ID as org_id claim
+func (uc *ManageOrganizationsUseCase) Create(ctx context.Context, cmd CreateOrganizationDTO) (*OrganizationDTO, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidOrganization)
+	}
+	slug := cmd.Slug
+	if slug == "" {
+		slug = name
+	}
+	slug = strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(slug), "-"), "-")
+	if slug == "" {
+		return nil, fmt.Errorf("%w: slug must contain letters or digits", domain.ErrInvalidOrganization)
+	}
+
+	org := &domain.Organization{
+		ID:   uuid.New(),
+		Name: name,
+		Slug: slug,
+	}
+	if err := uc.orgRepo.Save(ctx, org); err != nil {
+		return nil, fmt.Errorf("manage organizations use case: failed to save organization %s: %w", slug, err)
+	}
+	logger.FromContext(ctx).Info("Organization created", zap.String("orgID", org.ID.String()), zap.String("slug", slug))
+	dto := toOrganizationDTO(org)
+	return &dto, nil
+}
+
+// Get returns an organization
+func (uc *ManageOrganizationsUseCase) Get(ctx context.Context, id uuid.UUID) (*OrganizationDTO, error) {
+	org, err := uc.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("manage organizations use case: failed to get organization %s: %w", id, err)
+	}
+	dto := toOrganizationDTO(org)
+	return &dto, nil
+}
+
+// List returns a page of organizations ordered by name
+func (uc *ManageOrganizationsUseCase) List(ctx context.Context, limit, offset int) ([]OrganizationDTO, error) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	limit = min(limit, maxPageLimit)
+	offset = max(offset, 0)
+	orgs, err := uc.orgRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("manage organizations use case: failed to list organizations: %w", err)
+	}
+	dtos := make([]OrganizationDTO, 0, len(orgs))
+	for _, org := range orgs {
+		dtos = append(dtos, toOrganizationDTO(org))
+	}
+	return dtos, nil
+}
+
+func toOrganizationDTO(org *domain.Organization) OrganizationDTO {
+	return OrganizationDTO{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		CreatedAt: org.CreatedAt,
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidOrganization  = errors.New("invalid organization")
+	ErrSlugTaken            = errors.New("organization slug is already taken")
+)
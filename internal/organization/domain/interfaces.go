@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationRepository persists the organizations
+type OrganizationRepository interface {
+	// Save inserts the organization, it returns ErrSlugTaken on duplicated slugs
+	Save(ctx context.Context, org *Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	// List returns a page of organizations ordered by name
+	List(ctx context.Context, limit, offset int) ([]*Organization, error)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization is an auction house hosted by the deployment, it owns its lots and webhook subscriptions
+type Organization struct {
+	ID   uuid.UUID
+	Name string
+	// Slug is the unique URL friendly name, e.g. "north-auctions"
+	Slug      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/organization/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUniqueViolation is the Postgres error code of unique constraint violations
+const pgUniqueViolation = "23505"
+
+// OrganizationRepository implements domain.OrganizationRepository interface
+type OrganizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrganizationRepository creates a new instance of OrganizationRepository
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{pool: pool}
+}
+
+// Save inserts a new organization
+func (r *OrganizationRepository) Save(ctx context.Context, org *domain.Organization) error {
+	query := `
+        INSERT INTO organizations (id, name, slug)
+        VALUES ($1, $2, $3)
+        RETURNING created_at
+    `
+	err := r.pool.QueryRow(ctx, query, org.ID, org.Name, org.Slug).Scan(&org.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return domain.ErrSlugTaken
+	}
+	return err
+}
+
+// GetByID returns an organization, or domain.ErrOrganizationNotFound
+func (r *OrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	query := `SELECT id, name, slug, created_at FROM organizations WHERE id = $1`
+	org := &domain.Organization{}
+	err := r.pool.QueryRow(ctx, query, id).Scan(&org.ID, &org.Name, &org.Slug, &org.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+// List returns a page of organizations ordered by name
+func (r *OrganizationRepository) List(ctx context.Context, limit, offset int) ([]*domain.Organization, error) {
+	query := `
+        SELECT id, name, slug, created_at
+        FROM organizations
+        ORDER BY name
+        LIMIT $1 OFFSET $2
+    `
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []*domain.Organization
+	for rows.Next() {
+		org := &domain.Organization{}
+		if err := rows.Scan(&org.ID, &org.Name, &org.Slug, &org.CreatedAt); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/organization/application"
+	"github.com/cristianortiz/auctionEngine/internal/organization/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OrganizationHandler exposes the organizations management API to the super admins of the platform
+type OrganizationHandler struct {
+	manageUC *application.ManageOrganizationsUseCase
+}
+
+// NewOrganizationHandler creates a new instance of OrganizationHandler
+func NewOrganizationHandler(manageUC *application.ManageOrganizationsUseCase) *OrganizationHandler {
+	return &OrganizationHandler{manageUC: manageUC}
+}
+
+// createOrganizationRequest is the JSON body of POST /admin/organizations
+type createOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// RegisterRoutes registers the organization endpoints, all of them guarded by requireManage
+func (h *OrganizationHandler) RegisterRoutes(router fiber.Router, requireManage fiber.Handler) {
+	router.Post("/admin/organizations", requireManage, h.createOrganization)
+	router.Get("/admin/organizations", requireManage, h.listOrganizations)
+	router.Get("/admin/organizations/:id", requireManage, h.getOrganization)
+}
+
+func (h *OrganizationHandler) createOrganization(c *fiber.Ctx) error {
+	var req createOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	org, err := h.manageUC.Create(c.UserContext(), application.CreateOrganizationDTO{Name: req.Name, Slug: req.Slug})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(org)
+}
+
+// listOrganizations handles GET /admin/organizations?limit=&offset=
+func (h *OrganizationHandler) listOrganizations(c *fiber.Ctx) error {
+	orgs, err := h.manageUC.List(c.UserContext(), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(orgs)
+}
+
+func (h *OrganizationHandler) getOrganization(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid organization ID")
+	}
+	org, err := h.manageUC.Get(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(org)
+}
+
+// toHTTPError maps organization domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrganizationNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidOrganization):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrSlugTaken):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// syncOverlap is how far back each sync reads again, covering the clock skew between the instances,
+// applying a revocation twice is a no-op
+const syncOverlap = time.Minute
+
+// RunRevocationSync loads the revocations into the revocation list and then reads the ones made by
+// every instance each interval, closing the WS connections of this instance they cover, until ctx is done
+func (uc *SessionsUseCase) RunRevocationSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	log := logger.FromContext(ctx)
+	log.Info("Revocation sync started", zap.Duration("interval", interval))
+	// sessions revoked before the access TTL have no valid token left, user revocations are all kept
+	sessionsSince := time.Now().Add(-uc.cfg.AccessTTL)
+	var usersSince time.Time
+	for {
+		now := time.Now()
+		if err := uc.syncRevocations(ctx, sessionsSince, usersSince); err != nil {
+			log.Error("Revocation sync failed", zap.Error(err))
+		} else {
+			sessionsSince = now.Add(-syncOverlap)
+			usersSince = sessionsSince
+		}
+		uc.revocations.Prune(now)
+
+		select {
+		case <-ctx.Done():
+			log.Info("Revocation sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *SessionsUseCase) syncRevocations(ctx context.Context, sessionsSince, usersSince time.Time) error {
+	sessions, err := uc.sessions.RevokedSince(ctx, sessionsSince)
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		uc.applySession(s)
+	}
+	users, err := uc.sessions.UserRevocationsSince(ctx, usersSince)
+	if err != nil {
+		return err
+	}
+	for _, r := range users {
+		uc.applyUser(r)
+	}
+	return nil
+}
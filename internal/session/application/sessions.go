@@ -0,0 +1,321 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/session/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	// refreshTokenPrefix marks the refresh tokens, so they are easy to recognize in secret scanners
+	refreshTokenPrefix = "aer_"
+
+	// reasons of the revocations
+	ReasonSignOut       = "sign_out"
+	ReasonRevoked       = "revoked"
+	ReasonUserRevoked   = "user_revoked"
+	ReasonRefreshReused = "refresh_token_reused"
+)
+
+// Disconnector closes the live WS connections of a user, narrowed to an organization and a session
+// when they are not empty, implemented by the WS hub
+type Disconnector interface {
+	Disconnect(userID, orgID, sessionID string)
+}
+
+// SessionsConfig sets the lifetimes of the sessions tokens
+type SessionsConfig struct {
+	// AccessTTL is the lifetime of the access tokens, a revocation is kept in memory for as long
+	AccessTTL time.Duration
+	// RefreshTTL is the lifetime of a session, its refresh tokens are not accepted after it
+	RefreshTTL time.Duration
+}
+
+// TokensDTO are the tokens of a session, the refresh token is only shown in this response
+type TokensDTO struct {
+	SessionID        uuid.UUID `json:"session_id"`
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SessionDTO is the output DTO of a session
+type SessionDTO struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	OrgID        *uuid.UUID `json:"org_id,omitempty"`
+	Role         string     `json:"role"`
+	UserAgent    string     `json:"user_agent,omitempty"`
+	IP           string     `json:"ip,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RefreshedAt  time.Time  `json:"refreshed_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
+
+// UserRevocationDTO is the result of SessionsUseCase.RevokeUser
+type UserRevocationDTO struct {
+	UserID          uuid.UUID `json:"user_id"`
+	RevokedBefore   time.Time `json:"revoked_before"`
+	RevokedSessions int       `json:"revoked_sessions"`
+}
+
+// StartSessionDTO is the input of SessionsUseCase.Start
+type StartSessionDTO struct {
+	// Claims are the verified claims of the token signing in
+	Claims    *auth.Claims
+	UserAgent string
+	IP        string
+}
+
+// SessionsUseCase starts the sessions of the users, rotates their refresh tokens and revokes them,
+// the revocations reject the tokens right away and close the WS connections opened with them
+type SessionsUseCase struct {
+	sessions     domain.SessionRepository
+	tokens       *auth.TokenService
+	revocations  *auth.RevocationList
+	disconnector Disconnector
+	cfg          SessionsConfig
+}
+
+// NewSessionsUseCase creates a new instance of SessionsUseCase, tokens must verify with revocations
+func NewSessionsUseCase(sessions domain.SessionRepository, tokens *auth.TokenService, revocations *auth.RevocationList, disconnector Disconnector, cfg SessionsConfig) *SessionsUseCase {
+	return &SessionsUseCase{
+		sessions:     sessions,
+		tokens:       tokens,
+		revocations:  revocations,
+		disconnector: disconnector,
+		cfg:          cfg,
+	}
+}
+
+// Start opens a session for the holder of a token issued outside a session, e.g. by the identity
+// provider, and returns its first tokens
+func (uc *SessionsUseCase) Start(ctx context.Context, cmd StartSessionDTO) (*TokensDTO, error) {
+	if cmd.Claims.APIKeyID != uuid.Nil {
+		return nil, fmt.Errorf("%w: API keys can't start sessions", domain.ErrInvalidSession)
+	}
+	// a leaked access token would otherwise outlive the revocation of its session
+	if cmd.Claims.SessionID != uuid.Nil {
+		return nil, fmt.Errorf("%w: the tokens of a session can't start another one", domain.ErrInvalidSession)
+	}
+	s := domain.NewSession(cmd.Claims.UserID, cmd.Claims.OrgID, string(cmd.Claims.Role), cmd.UserAgent, cmd.IP, uc.cfg.RefreshTTL)
+	if err := uc.sessions.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to save session: %w", err)
+	}
+	tokens, err := uc.issue(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Info("Session started",
+		zap.String("sessionID", s.ID.String()),
+		zap.String("userID", s.UserID.String()),
+	)
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for new tokens of its session, the refresh token can't be used
+// again. a reused refresh token leaked, so its session is revoked
+func (uc *SessionsUseCase) Refresh(ctx context.Context, refreshToken string) (*TokensDTO, error) {
+	now := time.Now()
+	t, err := uc.sessions.UseRefreshToken(ctx, domain.HashToken(refreshToken), now)
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		logger.FromContext(ctx).Warn("Refresh token reused, revoking session", zap.String("sessionID", t.SessionID.String()))
+		if _, revokeErr := uc.revoke(ctx, t.SessionID, ReasonRefreshReused); revokeErr != nil && !errors.Is(revokeErr, domain.ErrSessionRevoked) {
+			return nil, revokeErr
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: %w", err)
+	}
+	s, err := uc.sessions.GetByID(ctx, t.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to get session %s: %w", t.SessionID, err)
+	}
+	if err := s.Usable(now); err != nil {
+		return nil, fmt.Errorf("sessions use case: %w", err)
+	}
+	s.RefreshedAt = now
+	if err := uc.sessions.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to save session %s: %w", s.ID, err)
+	}
+	return uc.issue(ctx, s)
+}
+
+// ListByUser returns a page of the sessions of a user, newest first
+func (uc *SessionsUseCase) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]SessionDTO, error) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	sessions, err := uc.sessions.ListByUser(ctx, userID, min(limit, maxPageLimit), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to list sessions: %w", err)
+	}
+	dtos := make([]SessionDTO, 0, len(sessions))
+	for _, s := range sessions {
+		dtos = append(dtos, toSessionDTO(s))
+	}
+	return dtos, nil
+}
+
+// Revoke revokes a session, its tokens are rejected and its WS connections closed right away
+func (uc *SessionsUseCase) Revoke(ctx context.Context, id uuid.UUID) (*SessionDTO, error) {
+	s, err := uc.revoke(ctx, id, ReasonRevoked)
+	if err != nil {
+		return nil, err
+	}
+	dto := toSessionDTO(s)
+	return &dto, nil
+}
+
+// RevokeOwn revokes a session of userID, the sessions of other users are not found
+func (uc *SessionsUseCase) RevokeOwn(ctx context.Context, userID, id uuid.UUID, reason string) error {
+	s, err := uc.sessions.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("sessions use case: failed to get session %s: %w", id, err)
+	}
+	if s.UserID != userID {
+		return fmt.Errorf("sessions use case: session %s: %w", id, domain.ErrSessionNotFound)
+	}
+	_, err = uc.revoke(ctx, id, reason)
+	return err
+}
+
+// RevokeUser revokes every session of a user and every token issued to it up to now, the ones issued
+// outside a session included, in the caller organization or in every one when unscoped
+func (uc *SessionsUseCase) RevokeUser(ctx context.Context, userID uuid.UUID) (*UserRevocationDTO, error) {
+	now := time.Now()
+	orgID, _ := tenant.OrgID(ctx)
+	revocation := domain.UserRevocation{UserID: userID, OrgID: orgID, Before: now}
+	if err := uc.sessions.SaveUserRevocation(ctx, revocation); err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to save user revocation: %w", err)
+	}
+	sessions, err := uc.sessions.RevokeByUser(ctx, userID, now, ReasonUserRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to revoke sessions of user %s: %w", userID, err)
+	}
+	uc.applyUser(revocation)
+	for _, s := range sessions {
+		uc.applySession(s)
+	}
+	logger.FromContext(ctx).Info("User tokens revoked",
+		zap.String("userID", userID.String()),
+		zap.String("orgID", orgID.String()),
+		zap.Int("sessions", len(sessions)),
+	)
+	return &UserRevocationDTO{UserID: userID, RevokedBefore: now, RevokedSessions: len(sessions)}, nil
+}
+
+func (uc *SessionsUseCase) revoke(ctx context.Context, id uuid.UUID, reason string) (*domain.Session, error) {
+	s, err := uc.sessions.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to get session %s: %w", id, err)
+	}
+	if err := s.Revoke(time.Now(), reason); err != nil {
+		return nil, fmt.Errorf("sessions use case: %w", err)
+	}
+	if err := uc.sessions.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to save session %s: %w", id, err)
+	}
+	uc.applySession(s)
+	logger.FromContext(ctx).Info("Session revoked", zap.String("sessionID", id.String()), zap.String("reason", reason))
+	return s, nil
+}
+
+// applySession adds a revoked session to the revocation list and closes its connections, once
+func (uc *SessionsUseCase) applySession(s *domain.Session) {
+	if !uc.revocations.RevokeSession(s.ID, s.RevokedAt.Add(uc.cfg.AccessTTL)) {
+		return
+	}
+	if uc.disconnector != nil {
+		uc.disconnector.Disconnect(s.UserID.String(), "", s.ID.String())
+	}
+}
+
+// applyUser adds a user revocation to the revocation list and closes the connections it covers, once
+func (uc *SessionsUseCase) applyUser(r domain.UserRevocation) {
+	if !uc.revocations.RevokeUser(r.UserID, r.OrgID, r.Before) {
+		return
+	}
+	if uc.disconnector == nil {
+		return
+	}
+	orgID := ""
+	if r.OrgID != uuid.Nil {
+		orgID = r.OrgID.String()
+	}
+	uc.disconnector.Disconnect(r.UserID.String(), orgID, "")
+}
+
+// issue returns new tokens of a session, saving its new refresh token
+func (uc *SessionsUseCase) issue(ctx context.Context, s *domain.Session) (*TokensDTO, error) {
+	access, err := uc.tokens.Issue(auth.Claims{
+		UserID:    s.UserID,
+		Role:      auth.Role(s.Role),
+		OrgID:     s.OrgID,
+		SessionID: s.ID,
+	}, uc.cfg.AccessTTL)
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to issue access token: %w", err)
+	}
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to generate refresh token: %w", err)
+	}
+	if err := uc.sessions.AddRefreshToken(ctx, domain.NewRefreshToken(s.ID, refresh)); err != nil {
+		return nil, fmt.Errorf("sessions use case: failed to save refresh token: %w", err)
+	}
+	return &TokensDTO{
+		SessionID:        s.ID,
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(uc.cfg.AccessTTL.Seconds()),
+		RefreshToken:     refresh,
+		RefreshExpiresAt: s.ExpiresAt,
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return refreshTokenPrefix + hex.EncodeToString(b), nil
+}
+
+func toSessionDTO(s *domain.Session) SessionDTO {
+	dto := SessionDTO{
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Role:         s.Role,
+		UserAgent:    s.UserAgent,
+		IP:           s.IP,
+		CreatedAt:    s.CreatedAt,
+		RefreshedAt:  s.RefreshedAt,
+		ExpiresAt:    s.ExpiresAt,
+		RevokedAt:    s.RevokedAt,
+		RevokeReason: s.RevokeReason,
+	}
+	if s.OrgID != uuid.Nil {
+		orgID := s.OrgID
+		dto.OrgID = &orgID
+	}
+	return dto
+}
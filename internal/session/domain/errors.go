@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session is revoked")
+	ErrSessionExpired      = errors.New("session is expired")
+	ErrInvalidSession      = errors.New("invalid session")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session is revoked")
+)
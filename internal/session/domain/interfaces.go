@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionRepository persists the sessions, their refresh tokens and the user revocations
+type SessionRepository interface {
+	// Save inserts the session or updates it if it already exists
+	Save(ctx context.Context, s *Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	// ListByUser returns a page of the sessions of a user, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Session, error)
+	// RevokeByUser revokes the active sessions of a user and returns them
+	RevokeByUser(ctx context.Context, userID uuid.UUID, at time.Time, reason string) ([]*Session, error)
+	// RevokedSince returns the sessions of every organization revoked after since
+	RevokedSince(ctx context.Context, since time.Time) ([]*Session, error)
+
+	AddRefreshToken(ctx context.Context, t *RefreshToken) error
+	// UseRefreshToken marks the token with the hash as used at, it returns ErrInvalidRefreshToken if
+	// it doesn't exist, and the token with ErrRefreshTokenReused if it was already used
+	UseRefreshToken(ctx context.Context, hash string, at time.Time) (*RefreshToken, error)
+
+	// SaveUserRevocation inserts the revocation or moves it forward if it already exists
+	SaveUserRevocation(ctx context.Context, r UserRevocation) error
+	// UserRevocationsSince returns the user revocations of every organization made after since
+	UserRevocationsSince(ctx context.Context, since time.Time) ([]UserRevocation, error)
+}
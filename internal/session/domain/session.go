@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a sign in of a user, its access tokens are short lived and renewed with refresh tokens
+// rotated on every use. revoking a session rejects its tokens right away
+type Session struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// OrgID is the organization of the session tokens, uuid.Nil for the super admins of the whole platform
+	OrgID uuid.UUID
+	// Role is the role of the session tokens
+	Role      string
+	UserAgent string
+	IP        string
+	CreatedAt time.Time
+	// RefreshedAt is the last rotation of the refresh token
+	RefreshedAt time.Time
+	// ExpiresAt ends the session, the refresh tokens are not accepted after it
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	RevokeReason string
+}
+
+// NewSession creates a new Session of a user lasting ttl
+func NewSession(userID, orgID uuid.UUID, role, userAgent, ip string, ttl time.Duration) *Session {
+	now := time.Now()
+	return &Session{
+		ID:          uuid.New(),
+		UserID:      userID,
+		OrgID:       orgID,
+		Role:        role,
+		UserAgent:   userAgent,
+		IP:          ip,
+		CreatedAt:   now,
+		RefreshedAt: now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+// Revoked reports if the session was revoked
+func (s *Session) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+// Usable returns the reason the session can't issue tokens at now, nil if it can
+func (s *Session) Usable(now time.Time) error {
+	if s.Revoked() {
+		return ErrSessionRevoked
+	}
+	if !now.Before(s.ExpiresAt) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// Revoke ends the session for good
+func (s *Session) Revoke(at time.Time, reason string) error {
+	if s.Revoked() {
+		return ErrSessionRevoked
+	}
+	s.RevokedAt = &at
+	s.RevokeReason = reason
+	return nil
+}
+
+// RefreshToken is a single use token renewing the access token of a session, only its hash is stored
+type RefreshToken struct {
+	Hash      string
+	SessionID uuid.UUID
+	CreatedAt time.Time
+	// UsedAt is when it was exchanged, a second use means it leaked and revokes the session
+	UsedAt *time.Time
+}
+
+// NewRefreshToken creates the RefreshToken of token for a session
+func NewRefreshToken(sessionID uuid.UUID, token string) *RefreshToken {
+	return &RefreshToken{Hash: HashToken(token), SessionID: sessionID, CreatedAt: time.Now()}
+}
+
+// HashToken returns the hash the refresh tokens are stored and looked up by, tokens are random so a
+// plain SHA-256 is enough
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// UserRevocation revokes the tokens of a user in an organization issued up to Before, including the
+// ones issued outside a session. uuid.Nil OrgID revokes them in every organization
+type UserRevocation struct {
+	UserID uuid.UUID
+	OrgID  uuid.UUID
+	Before time.Time
+}
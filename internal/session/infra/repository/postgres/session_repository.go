@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/session/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SessionRepository implements domain.SessionRepository interface
+type SessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSessionRepository creates a new instance of SessionRepository
+func NewSessionRepository(pool *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{pool: pool}
+}
+
+const sessionColumns = `id, user_id, org_id, role, user_agent, ip, created_at, refreshed_at, expires_at, revoked_at, revoke_reason`
+
+// Save inserts the session or updates it if it already exists
+func (r *SessionRepository) Save(ctx context.Context, s *domain.Session) error {
+	query := `
+        INSERT INTO sessions (` + sessionColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (id) DO UPDATE
+        SET
+            refreshed_at = EXCLUDED.refreshed_at,
+            revoked_at = EXCLUDED.revoked_at,
+            revoke_reason = EXCLUDED.revoke_reason;
+    `
+	_, err := r.pool.Exec(ctx, query,
+		s.ID,
+		s.UserID,
+		nullableUUID(s.OrgID),
+		s.Role,
+		s.UserAgent,
+		s.IP,
+		s.CreatedAt,
+		s.RefreshedAt,
+		s.ExpiresAt,
+		s.RevokedAt,
+		s.RevokeReason,
+	)
+	return err
+}
+
+// GetByID returns a session, or domain.ErrSessionNotFound, sessions of other organizations are not found
+func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+	s, err := scanSession(r.pool.QueryRow(ctx, query, id, tenant.Filter(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListByUser returns a page of the sessions of a user, newest first
+func (r *SessionRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Session, error) {
+	query := `
+        SELECT ` + sessionColumns + `
+        FROM sessions
+        WHERE user_id = $1 AND ($4::uuid IS NULL OR org_id = $4)
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+	return r.query(ctx, query, userID, limit, offset, tenant.Filter(ctx))
+}
+
+// RevokeByUser revokes the active sessions of a user and returns them
+func (r *SessionRepository) RevokeByUser(ctx context.Context, userID uuid.UUID, at time.Time, reason string) ([]*domain.Session, error) {
+	query := `
+        UPDATE sessions
+        SET revoked_at = $2, revoke_reason = $3
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+          AND ($4::uuid IS NULL OR org_id = $4)
+        RETURNING ` + sessionColumns
+	return r.query(ctx, query, userID, at, reason, tenant.Filter(ctx))
+}
+
+// RevokedSince returns the sessions of every organization revoked after since
+func (r *SessionRepository) RevokedSince(ctx context.Context, since time.Time) ([]*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE revoked_at > $1`
+	return r.query(ctx, query, since)
+}
+
+// AddRefreshToken inserts a refresh token of a session
+func (r *SessionRepository) AddRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	query := `INSERT INTO session_refresh_tokens (token_hash, session_id, created_at) VALUES ($1, $2, $3)`
+	_, err := r.pool.Exec(ctx, query, t.Hash, t.SessionID, t.CreatedAt)
+	return err
+}
+
+// UseRefreshToken marks the token with the hash as used at, the update is atomic so concurrent uses
+// of a token see it as reused
+func (r *SessionRepository) UseRefreshToken(ctx context.Context, hash string, at time.Time) (*domain.RefreshToken, error) {
+	t := &domain.RefreshToken{Hash: hash}
+	query := `
+        UPDATE session_refresh_tokens
+        SET used_at = $2
+        WHERE token_hash = $1 AND used_at IS NULL
+        RETURNING session_id, created_at, used_at
+    `
+	err := r.pool.QueryRow(ctx, query, hash, at).Scan(&t.SessionID, &t.CreatedAt, &t.UsedAt)
+	if err == nil {
+		return t, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	query = `SELECT session_id, created_at, used_at FROM session_refresh_tokens WHERE token_hash = $1`
+	err = r.pool.QueryRow(ctx, query, hash).Scan(&t.SessionID, &t.CreatedAt, &t.UsedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, domain.ErrRefreshTokenReused
+}
+
+// SaveUserRevocation inserts the revocation or moves it forward if it already exists
+func (r *SessionRepository) SaveUserRevocation(ctx context.Context, rev domain.UserRevocation) error {
+	query := `
+        INSERT INTO token_revocations (user_id, org_id, revoked_before)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, org_id) DO UPDATE
+        SET revoked_before = GREATEST(token_revocations.revoked_before, EXCLUDED.revoked_before);
+    `
+	_, err := r.pool.Exec(ctx, query, rev.UserID, rev.OrgID, rev.Before)
+	return err
+}
+
+// UserRevocationsSince returns the user revocations of every organization made after since
+func (r *SessionRepository) UserRevocationsSince(ctx context.Context, since time.Time) ([]domain.UserRevocation, error) {
+	query := `SELECT user_id, org_id, revoked_before FROM token_revocations WHERE revoked_before > $1`
+	rows, err := r.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revocations []domain.UserRevocation
+	for rows.Next() {
+		var rev domain.UserRevocation
+		if err := rows.Scan(&rev.UserID, &rev.OrgID, &rev.Before); err != nil {
+			return nil, err
+		}
+		revocations = append(revocations, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revocations, nil
+}
+
+func (r *SessionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Session, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func scanSession(row pgx.Row) (*domain.Session, error) {
+	s := &domain.Session{}
+	var orgID *uuid.UUID
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&orgID,
+		&s.Role,
+		&s.UserAgent,
+		&s.IP,
+		&s.CreatedAt,
+		&s.RefreshedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+		&s.RevokeReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if orgID != nil {
+		s.OrgID = *orgID
+	}
+	return s, nil
+}
+
+// nullableUUID maps uuid.Nil to NULL
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
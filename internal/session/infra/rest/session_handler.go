@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/session/application"
+	"github.com/cristianortiz/auctionEngine/internal/session/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SessionHandler exposes the sessions of the users, their refresh and their revocation
+type SessionHandler struct {
+	sessionsUC *application.SessionsUseCase
+}
+
+// NewSessionHandler creates a new instance of SessionHandler
+func NewSessionHandler(sessionsUC *application.SessionsUseCase) *SessionHandler {
+	return &SessionHandler{sessionsUC: sessionsUC}
+}
+
+// refreshRequest is the JSON body of POST /auth/refresh
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RegisterRoutes registers the session endpoints, the own sessions ones guarded by requireUser and the
+// ones on the sessions of other users by requireManage
+func (h *SessionHandler) RegisterRoutes(router fiber.Router, requireUser, requireManage fiber.Handler) {
+	router.Post("/auth/sessions", requireUser, h.startSession)
+	router.Post("/auth/refresh", h.refresh)
+	router.Post("/auth/sign-out", requireUser, h.signOut)
+	router.Get("/users/me/sessions", requireUser, h.listMySessions)
+	router.Delete("/users/me/sessions/:id", requireUser, h.revokeMySession)
+	router.Get("/admin/users/:id/sessions", requireManage, h.listUserSessions)
+	router.Post("/admin/users/:id/revoke-tokens", requireManage, h.revokeUserTokens)
+	router.Delete("/admin/sessions/:id", requireManage, h.revokeSession)
+}
+
+// startSession exchanges the token of the request, issued outside a session, for the tokens of a new session
+func (h *SessionHandler) startSession(c *fiber.Ctx) error {
+	tokens, err := h.sessionsUC.Start(c.UserContext(), application.StartSessionDTO{
+		Claims:    httpserver.ClaimsFrom(c),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(tokens)
+}
+
+// refresh rotates a refresh token, every failure is a 401 so the client signs in again
+func (h *SessionHandler) refresh(c *fiber.Ctx) error {
+	var req refreshRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	tokens, err := h.sessionsUC.Refresh(c.UserContext(), req.RefreshToken)
+	switch {
+	case errors.Is(err, domain.ErrInvalidRefreshToken), errors.Is(err, domain.ErrRefreshTokenReused),
+		errors.Is(err, domain.ErrSessionRevoked), errors.Is(err, domain.ErrSessionExpired),
+		errors.Is(err, domain.ErrSessionNotFound):
+		logger.FromContext(c.UserContext()).Warn("Refresh rejected", zap.String("remote_addr", c.IP()), zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	case err != nil:
+		return toHTTPError(c, err)
+	}
+	return c.JSON(tokens)
+}
+
+// signOut revokes the session of the token of the request
+func (h *SessionHandler) signOut(c *fiber.Ctx) error {
+	claims := httpserver.ClaimsFrom(c)
+	if claims.SessionID == uuid.Nil {
+		return fiber.NewError(fiber.StatusBadRequest, "token was not issued for a session")
+	}
+	if err := h.sessionsUC.RevokeOwn(c.UserContext(), claims.UserID, claims.SessionID, application.ReasonSignOut); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// listMySessions handles GET /users/me/sessions?limit=&offset=
+func (h *SessionHandler) listMySessions(c *fiber.Ctx) error {
+	sessions, err := h.sessionsUC.ListByUser(c.UserContext(), httpserver.ClaimsFrom(c).UserID, c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(sessions)
+}
+
+func (h *SessionHandler) revokeMySession(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid session ID")
+	}
+	if err := h.sessionsUC.RevokeOwn(c.UserContext(), httpserver.ClaimsFrom(c).UserID, id, application.ReasonSignOut); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// listUserSessions handles GET /admin/users/:id/sessions?limit=&offset=
+func (h *SessionHandler) listUserSessions(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	sessions, err := h.sessionsUC.ListByUser(c.UserContext(), userID, c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(sessions)
+}
+
+// revokeUserTokens revokes every session and token of a user, e.g. when its tokens were compromised,
+// closing its WS connections
+func (h *SessionHandler) revokeUserTokens(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	revocation, err := h.sessionsUC.RevokeUser(c.UserContext(), userID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(revocation)
+}
+
+func (h *SessionHandler) revokeSession(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid session ID")
+	}
+	session, err := h.sessionsUC.Revoke(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(session)
+}
+
+// toHTTPError maps session domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidSession):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrSessionRevoked), errors.Is(err, domain.ErrSessionExpired):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
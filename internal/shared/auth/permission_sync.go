@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// PermissionStore reads the permissions table stored in the DB, implemented in auth/postgres
+type PermissionStore interface {
+	RolePermissions(ctx context.Context) (map[Role][]Permission, error)
+}
+
+// RunPermissionSync loads the permissions table of store and reloads it each interval until ctx is done,
+// so a change of the stored table reaches every instance. a failed load keeps the table in use, and so
+// does an empty one: a table granting nothing is taken as not seeded rather than as locking everyone out
+func RunPermissionSync(ctx context.Context, store PermissionStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	log := logger.FromContext(ctx)
+	log.Info("Permission sync started", zap.Duration("interval", interval))
+	for {
+		table, err := store.RolePermissions(ctx)
+		switch {
+		case err != nil:
+			log.Error("Permission sync failed", zap.Error(err))
+		case len(table) == 0:
+			log.Warn("Permission sync: the permissions table is empty, keeping the one in use")
+		default:
+			SetPermissions(table)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Permission sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
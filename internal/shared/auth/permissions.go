@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// Permission is an action on the REST and WS APIs granted to roles by the permissions table
+type Permission string
+
+const (
+	PermPlaceBids           Permission = "bids:place"              // bid on the lots through the WS channel
+	PermRunLots             Permission = "lots:run"                // pause, resume and announce on the admin WS channel
+	PermModerateChat        Permission = "chat:moderate"           // mute and unmute in the lot chat
+	PermManageLots          Permission = "lots:manage"             // edit lots, their media and fees, void bids, replays
+	PermViewBidders         Permission = "bidders:view"            // see the identity of the bidders of a lot
+	PermAssignAuctioneers   Permission = "lots:assign_auctioneers" // assign the auctioneers running a lot
+	PermReviewFraud         Permission = "fraud:review"            // review the fraud alerts
+	PermManageWebhooks      Permission = "webhooks:manage"         // manage the webhook subscriptions
+	PermReadInvoices        Permission = "invoices:read"           // read the invoices of every buyer
+	PermManageAPIKeys       Permission = "api_keys:manage"         // issue, rotate and revoke the API keys of the machine clients
+	PermManageSessions      Permission = "sessions:manage"         // list and revoke the sessions and tokens of the users
+	PermManageCatalog       Permission = "catalog:manage"          // categories and fee schedules, shared by every organization
+	PermManageOrganizations Permission = "organizations:manage"    // add the organizations hosted by the deployment
+	PermOperatePlatform     Permission = "platform:operate"        // log levels, migrations, maintenance and diagnostics
+	PermManagePersonalData  Permission = "personal_data:manage"    // export and erase the personal data of the users
+)
+
+// orgAdminPermissions are the permissions of the org admins, the super admins add the platform ones
+var orgAdminPermissions = []Permission{
+	PermRunLots,
+	PermModerateChat,
+	PermManageLots,
+	PermViewBidders,
+	PermAssignAuctioneers,
+	PermReviewFraud,
+	PermManageWebhooks,
+	PermReadInvoices,
+	PermManageAPIKeys,
+	PermManageSessions,
+}
+
+// DefaultPermissions is the built-in permissions table, the role_permissions table is seeded with it and
+// it applies until the stored one is loaded
+var DefaultPermissions = map[Role][]Permission{
+	RoleBidder:     {PermPlaceBids},
+	RoleAuctioneer: {PermRunLots, PermModerateChat},
+	RoleOrgAdmin:   orgAdminPermissions,
+	RoleSuperAdmin: append(slices.Clone(orgAdminPermissions), PermManageCatalog, PermManageOrganizations, PermOperatePlatform,
+		PermManagePersonalData),
+}
+
+// rolePermissions is the permissions table in use, roles missing from it have no permissions
+var rolePermissions atomic.Pointer[map[Role][]Permission]
+
+func init() {
+	SetPermissions(DefaultPermissions)
+}
+
+// SetPermissions replaces the permissions table, the checks made from now on use table
+func SetPermissions(table map[Role][]Permission) {
+	cloned := make(map[Role][]Permission, len(table))
+	for role, perms := range table {
+		cloned[role] = slices.Clone(perms)
+	}
+	rolePermissions.Store(&cloned)
+}
+
+// Can reports if the role is granted the permission
+func (r Role) Can(p Permission) bool {
+	return slices.Contains((*rolePermissions.Load())[r], p)
+}
+
+// PermissionsOf returns the permissions granted to the role
+func PermissionsOf(r Role) []Permission {
+	return slices.Clone((*rolePermissions.Load())[r])
+}
+
+// Can reports if the holder of the claims is granted the permission, by its Permissions if set or by its role
+func (c *Claims) Can(p Permission) bool {
+	if c.Permissions != nil {
+		return slices.Contains(c.Permissions, p)
+	}
+	return c.Role.Can(p)
+}
+
+// LotAssigned reports if the permissions of the role only apply on the lots it's assigned to, as the
+// auctioneers running a lot
+func (r Role) LotAssigned() bool {
+	return r == RoleAuctioneer
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PermissionRepository implements auth.PermissionStore interface over the role_permissions table
+type PermissionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPermissionRepository creates a new instance of PermissionRepository
+func NewPermissionRepository(pool *pgxpool.Pool) *PermissionRepository {
+	return &PermissionRepository{pool: pool}
+}
+
+// RolePermissions returns the permissions granted to each role
+func (r *PermissionRepository) RolePermissions(ctx context.Context) (map[auth.Role][]auth.Permission, error) {
+	rows, err := r.pool.Query(ctx, `SELECT role, permission FROM role_permissions ORDER BY role, permission`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	table := make(map[auth.Role][]auth.Permission)
+	for rows.Next() {
+		var role auth.Role
+		var permission auth.Permission
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, err
+		}
+		table[role] = append(table[role], permission)
+	}
+	return table, rows.Err()
+}
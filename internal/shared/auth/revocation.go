@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTokenRevoked is the cause of the Verify errors of the tokens of revoked sessions and users
+var ErrTokenRevoked = errors.New("token is revoked")
+
+// RevocationList holds the revoked sessions and users checked by TokenService.Verify, it's filled from
+// the sessions store and safe for concurrent use
+type RevocationList struct {
+	mu sync.RWMutex
+	// revoked sessions, with the time their access tokens are expired by and the entry can be dropped
+	sessions map[uuid.UUID]time.Time
+	// tokens of a user in an organization issued up to the time are revoked, uuid.Nil org matches every organization
+	users map[userOrg]time.Time
+}
+
+type userOrg struct {
+	userID uuid.UUID
+	orgID  uuid.UUID
+}
+
+// NewRevocationList creates a new empty RevocationList
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		sessions: make(map[uuid.UUID]time.Time),
+		users:    make(map[userOrg]time.Time),
+	}
+}
+
+// RevokeSession revokes the tokens of a session until their expiry, it reports if the session was not revoked yet
+func (l *RevocationList) RevokeSession(id uuid.UUID, until time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, revoked := l.sessions[id]
+	if !revoked || until.After(l.sessions[id]) {
+		l.sessions[id] = until
+	}
+	return !revoked
+}
+
+// RevokeUser revokes the tokens of a user in an organization issued up to the time, uuid.Nil org
+// revokes them in every organization. the iat claims have second precision, so the tokens issued in
+// the second of before are revoked too. it reports if the revocation moved forward
+func (l *RevocationList) RevokeUser(userID, orgID uuid.UUID, before time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := userOrg{userID: userID, orgID: orgID}
+	if current, ok := l.users[key]; ok && !before.After(current) {
+		return false
+	}
+	l.users[key] = before
+	return true
+}
+
+// Revoked reports if the token of the claims is revoked
+func (l *RevocationList) Revoked(c *Claims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if c.SessionID != uuid.Nil {
+		if _, ok := l.sessions[c.SessionID]; ok {
+			return true
+		}
+	}
+	for _, orgID := range []uuid.UUID{c.OrgID, uuid.Nil} {
+		if before, ok := l.users[userOrg{userID: c.UserID, orgID: orgID}]; ok && !c.IssuedAt.After(before) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune drops the revoked sessions whose tokens are expired by now
+func (l *RevocationList) Prune(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, until := range l.sessions {
+		if now.After(until) {
+			delete(l.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMissingToken = errors.New("authentication token is missing")
+	ErrInvalidToken = errors.New("authentication token is invalid")
+)
+
+// Role of the token holder on WS connections and REST APIs
+type Role string
+
+const (
+	RoleBidder     Role = "bidder"      // can watch and place bids
+	RoleSpectator  Role = "spectator"   // read-only, receives lot updates
+	RoleAuctioneer Role = "auctioneer"  // runs the lots assigned to it through the admin WS channel
+	RoleOrgAdmin   Role = "org_admin"   // manages the lots, auctioneers and webhooks of its organization
+	RoleSuperAdmin Role = "super_admin" // operates the platform, every organization unless its token has an org_id
+	// RoleAdmin is the admin role of the tokens issued before the org and super admin split, Verify
+	// resolves it to RoleOrgAdmin when the token has an org_id and to RoleSuperAdmin otherwise
+	RoleAdmin Role = "admin"
+)
+
+// Claims are the identity claims carried by a connection token or an API key
+type Claims struct {
+	UserID uuid.UUID
+	Role   Role
+	// OrgID is the organization (tenant) of the holder, lots and admin APIs are scoped to it,
+	// uuid.Nil for the super admins of the whole platform
+	OrgID uuid.UUID
+	// Permissions replace the permissions of Role when not nil, the API keys get the ones of their scopes
+	Permissions []Permission
+	// APIKeyID is the API key the caller authenticated with, uuid.Nil for tokens
+	APIKeyID uuid.UUID
+	// SessionID is the session the token was issued for, uuid.Nil for the tokens issued outside a session
+	SessionID uuid.UUID
+	// IssuedAt is when the token was issued, zero for the tokens without iat claim
+	IssuedAt time.Time
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Role      Role   `json:"role,omitempty"`
+	OrgID     string `json:"org_id,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+}
+
+// TokenService issues and verifies HMAC (HS256) signed JWT tokens
+type TokenService struct {
+	secret []byte
+	// revocations rejects the tokens of the revoked sessions and users, nil accepts every valid token
+	revocations *RevocationList
+}
+
+// NewTokenService creates a new instance of TokenService with the given signing secret
+func NewTokenService(secret string) *TokenService {
+	return &TokenService{secret: []byte(secret)}
+}
+
+// WithRevocations makes Verify reject the tokens revoked in list
+func (s *TokenService) WithRevocations(list *RevocationList) *TokenService {
+	s.revocations = list
+	return s
+}
+
+// Issue signs a new token for the given claims valid for ttl
+func (s *TokenService) Issue(c Claims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   c.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role: c.Role,
+	}
+	if c.OrgID != uuid.Nil {
+		claims.OrgID = c.OrgID.String()
+	}
+	if c.SessionID != uuid.Nil {
+		claims.SessionID = c.SessionID.String()
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// Verify validates the token signature and expiration and returns its claims
+func (s *TokenService) Verify(token string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	// a connection token only identifies a WS connection, it's never an access token
+	if slices.Contains(claims.Audience, connectionAudience) {
+		return nil, fmt.Errorf("%w: connection token", ErrInvalidToken)
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+	role := claims.Role
+	if role == "" {
+		role = RoleBidder // tokens issued before roles existed are bidder tokens
+	}
+	if role == RoleAdmin {
+		role = RoleSuperAdmin
+		if claims.OrgID != "" {
+			role = RoleOrgAdmin
+		}
+	}
+	// tokens issued before multi-tenancy belong to the default organization, super admins without
+	// org_id are not scoped to any
+	orgID := tenant.DefaultOrgID
+	if role == RoleSuperAdmin {
+		orgID = uuid.Nil
+	}
+	if claims.OrgID != "" {
+		if orgID, err = uuid.Parse(claims.OrgID); err != nil {
+			return nil, fmt.Errorf("%w: invalid org_id", ErrInvalidToken)
+		}
+	}
+	verified := &Claims{UserID: userID, Role: role, OrgID: orgID}
+	if claims.SessionID != "" {
+		if verified.SessionID, err = uuid.Parse(claims.SessionID); err != nil {
+			return nil, fmt.Errorf("%w: invalid sid", ErrInvalidToken)
+		}
+	}
+	if claims.IssuedAt != nil {
+		verified.IssuedAt = claims.IssuedAt.Time
+	}
+	if s.revocations != nil && s.revocations.Revoked(verified) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrTokenRevoked)
+	}
+	return verified, nil
+}
+
+// connectionAudience is the audience of the connection tokens
+const connectionAudience = "ws_connection"
+
+type connectionClaims struct {
+	jwt.RegisteredClaims
+	LotID string `json:"lot_id"`
+}
+
+// IssueConnection signs a token binding the WS connection clientID of userID on lotID, valid for ttl.
+// a reconnection presenting it keeps clientID, see VerifyConnection. it grants no access
+func (s *TokenService) IssueConnection(clientID string, userID uuid.UUID, lotID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := connectionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        clientID,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{connectionAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		LotID: lotID,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// VerifyConnection validates a connection token presented by userID reconnecting to lotID and returns
+// its client ID, a token of another user or lot is invalid
+func (s *TokenService) VerifyConnection(token string, userID uuid.UUID, lotID string) (string, error) {
+	if token == "" {
+		return "", ErrMissingToken
+	}
+	var claims connectionClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(),
+		jwt.WithAudience(connectionAudience), jwt.WithSubject(userID.String()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.LotID != lotID || claims.ID == "" {
+		return "", fmt.Errorf("%w: connection of another lot", ErrInvalidToken)
+	}
+	return claims.ID, nil
+}
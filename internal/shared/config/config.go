@@ -0,0 +1,368 @@
+package config
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// bid persistence modes
+const (
+	BidPersistencePerBid  = "per_bid"
+	BidPersistenceBatched = "batched"
+)
+
+// app environments
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Config holds the global application configuration, loaded from environment variables (.env supported)
+type Config struct {
+	// AppEnv is development (default) or production, production enforces TLS and wss
+	AppEnv   string
+	HTTPPort string
+	// TLS settings of the native HTTPS server, TLSCertFile/TLSKeyFile or TLSAutocertDomains (Let's Encrypt)
+	// enable it. TLSRedirectAddr serves plain HTTP redirects to HTTPS (and the ACME challenges), e.g. ":80"
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string
+	TLSAutocertEmail    string
+	TLSAutocertCacheDir string
+	TLSRedirectAddr     string
+	// HSTSMaxAge of the Strict-Transport-Security header sent on HTTPS responses, 0 disables it
+	HSTSMaxAge time.Duration
+	// ShutdownDrainTimeout bounds the drain on SIGTERM: closing the WS connections and waiting for the
+	// queued bids. ShutdownRetryAfter is the reconnect wait sent to the clients of a draining instance
+	ShutdownDrainTimeout time.Duration
+	ShutdownRetryAfter   time.Duration
+	// GRPCPort for the internal service-to-service API, empty disables it
+	GRPCPort string
+	// GRPCRequireAPIKey rejects the gRPC calls without an x-api-key metadata
+	GRPCRequireAPIKey bool
+	// WSAllowedOrigins lists the Origin headers accepted on WS upgrades, "*" allows any origin
+	WSAllowedOrigins []string
+	// AuthTokenSecret is the HMAC secret used to sign and verify connection tokens
+	AuthTokenSecret string
+	// SessionAccessTTL is the lifetime of the access tokens issued to the sessions, renewed with their refresh tokens
+	SessionAccessTTL time.Duration
+	// SessionRefreshTTL is the lifetime of a session, it must sign in again after it
+	SessionRefreshTTL time.Duration
+	// SessionRevocationSync is how often the revocations made by every instance are read
+	SessionRevocationSync time.Duration
+	// PermissionSync is how often the permissions table of the roles is read from the DB
+	PermissionSync time.Duration
+	// WSAllowAnonymousSpectators lets token-less connections join lots as read-only spectators
+	WSAllowAnonymousSpectators bool
+	// WSWorkers is the number of goroutines processing inbound WS msgs, msgs of a lot always go to the same one
+	WSWorkers int
+	// WSWorkerQueueSize is the number of pending msgs per worker, msgs beyond it are rejected as busy
+	WSWorkerQueueSize int
+	// WSPingPeriod and WSPongWait are the heartbeat of the WS connections, a connection without a pong
+	// for WSPongWait is closed. WSStaleAfter reaps silent connections earlier, checked every WSReapInterval
+	WSPingPeriod   time.Duration
+	WSPongWait     time.Duration
+	WSStaleAfter   time.Duration
+	WSReapInterval time.Duration
+	// WSHubShards is the number of hub goroutines owning the connections, lots are spread among them by hash
+	WSHubShards int
+	// WS hub channel buffers, register/unregister/broadcast/direct are per shard. a send on a full channel
+	// waits up to WSEnqueueTimeout before the work is dropped
+	WSRegisterBuffer   int
+	WSUnregisterBuffer int
+	WSBroadcastBuffer  int
+	WSDirectBuffer     int
+	WSInboundBuffer    int
+	WSEnqueueTimeout   time.Duration
+	// WSCompression negotiates permessage-deflate on the WS upgrades, the msgs of WSCompressionMinSize bytes
+	// or more are compressed with WSCompressionLevel (-2 to 9)
+	WSCompression        bool
+	WSCompressionLevel   int
+	WSCompressionMinSize int
+	// WSConnectionTokenTTL is how long a WS client can reconnect keeping its client ID, WSConnectionLog
+	// records every connection in the connections table
+	WSConnectionTokenTTL time.Duration
+	WSConnectionLog      bool
+	// DemoPageEnabled serves the demo auction room at /demo/:lotid, never in production
+	DemoPageEnabled bool
+	// WSMaxMalformed closes a WS connection once it sent that many msgs failing the validation, 0 never does
+	WSMaxMalformed int
+	// LotCommandQueueSize is the number of pending bids and other commands per lot, more are rejected as busy
+	LotCommandQueueSize int
+	// BidPersistenceMode is per_bid (default, every bid in its own TX) or batched (bids piling up on a lot are
+	// stored together in one TX, waiting up to BidBatchWindow for up to BidBatchMaxBids bids)
+	BidPersistenceMode string
+	BidBatchWindow     time.Duration
+	BidBatchMaxBids    int
+	// BidFairnessWindow is the final period of a lot whose bids are run in receipt order, held for
+	// BidFairnessHold before being ordered. 0 disables the fairness queue
+	BidFairnessWindow time.Duration
+	BidFairnessHold   time.Duration
+	// BidMaxRetries is the number of times a bid TX aborted under contention (serialization failure,
+	// deadlock) is run again, after a jittered backoff from BidRetryBaseDelay up to BidRetryMaxDelay
+	BidMaxRetries     int
+	BidRetryBaseDelay time.Duration
+	BidRetryMaxDelay  time.Duration
+	// BidValidators are the names of the compiled-in bid validators run in order before the lot rules, e.g.
+	// max_bid_amount, an unknown name fails the start
+	BidValidators []string
+	// BidMaxAmount is the max amount of a single bid checked by the max_bid_amount validator
+	BidMaxAmount float64
+	// LotRulesCacheTTL is how long the rules of a lot are cached, a change made through another instance
+	// applies once it expires
+	LotRulesCacheTTL time.Duration
+	// GeoIPDatabase is the network to country CSV resolving the client countries for the country lists
+	// of the lots, see geoip.ParseRanges. without it the lots with an allow list reject every client
+	GeoIPDatabase string
+	// KYCWebhookSecret signs the verification statuses the KYC providers push to /api/webhooks/kyc, empty
+	// rejects them. KYCCacheTTL is how long the status of a user is cached
+	KYCWebhookSecret string
+	KYCCacheTTL      time.Duration
+	// KYCStub reports every bidder as verified, for the environments without a KYC provider
+	KYCStub bool
+	// LotChangeNotify propagates the lot changes between the instances with Postgres LISTEN/NOTIFY, their
+	// clients get the changes made through the other instances
+	LotChangeNotify bool
+	// LotHibernateAfter is how long a lot without connected clients nor bids keeps its cached state in
+	// memory, it's loaded again from the database when needed. zero keeps every lot in memory
+	LotHibernateAfter time.Duration
+	// HotLotBidsPerSecond is the bid rate making a lot hot, its WS clients then get at most
+	// HotLotMaxUpdatesPerSecond lot updates per second. zero disables the throttling
+	HotLotBidsPerSecond       float64
+	HotLotMaxUpdatesPerSecond int
+	// MaintenanceMode starts the engine read-only: the bids are rejected with a MAINTENANCE error while the
+	// state queries and the WS connections keep working, it's toggled at runtime by the admin API
+	MaintenanceMode bool
+	// EventBroker selects where domain events are published: nats, kafka or empty (log only)
+	EventBroker  string
+	NATSURL      string
+	KafkaBrokers []string
+	// EventSubjectPrefix prefixes the NATS subjects / Kafka topics of domain events
+	EventSubjectPrefix string
+	// SMTP settings for email notifications, empty SMTPHost disables the email channel
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// NotificationWebhookURL receives notifications as JSON, empty disables the webhook channel
+	NotificationWebhookURL string
+	// S3-compatible storage for lot media uploads, empty MediaS3Endpoint disables uploads
+	MediaS3Endpoint    string
+	MediaS3Bucket      string
+	MediaS3AccessKey   string
+	MediaS3SecretKey   string
+	MediaS3UseSSL      bool
+	MediaPublicBaseURL string
+	// invoice charges over the hammer price, as fractions (0.15 is 15%), used for lots without a fee schedule
+	// when no default schedule is stored
+	InvoiceBuyerPremiumRate float64
+	InvoiceTaxRate          float64
+	// ChatEnabled opens a live chat in every lot over the WS connections, limited to ChatRateLimit msgs per
+	// ChatRateWindow per user. ChatBlockedWords are masked, or rejected with ChatRejectBlocked
+	ChatEnabled       bool
+	ChatMaxLength     int
+	ChatRateLimit     int
+	ChatRateWindow    time.Duration
+	ChatHistorySize   int
+	ChatBlockedWords  []string
+	ChatRejectBlocked bool
+	// LeaderboardSize is the number of top bidders of the lot leaderboards, LeaderboardAnonymization is
+	// "paddles" to show their paddle numbers or "masked" to only show the ranks and bids
+	LeaderboardSize          int
+	LeaderboardAnonymization string
+	// EndingSoonWindow is the default window of the ending-soon feed, the lots closing within it are listed.
+	// the feed is published to the lobby WS topic every EndingSoonInterval
+	EndingSoonWindow   time.Duration
+	EndingSoonInterval time.Duration
+	// DisplayCurrencies are the currencies lot prices are converted to for display, empty disables conversions
+	DisplayCurrencies []string
+	// FXBaseCurrency and FXRates ("EUR=0.92,GBP=0.79") are the static rates of the conversions
+	FXBaseCurrency string
+	FXRates        []string
+}
+
+// Load reads the configuration from the environment
+func Load() *Config {
+	_ = godotenv.Load()
+	return &Config{
+		AppEnv:           getEnv("APP_ENV", EnvDevelopment),
+		HTTPPort:         getEnv("HTTP_PORT", "9000"),
+		GRPCPort:         os.Getenv("GRPC_PORT"),
+		WSAllowedOrigins: getEnvList("WS_ALLOWED_ORIGINS"),
+		AuthTokenSecret:  os.Getenv("AUTH_TOKEN_SECRET"),
+
+		TLSCertFile:         os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:          os.Getenv("TLS_KEY_FILE"),
+		TLSAutocertDomains:  getEnvList("TLS_AUTOCERT_DOMAINS"),
+		TLSAutocertEmail:    os.Getenv("TLS_AUTOCERT_EMAIL"),
+		TLSAutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "certs"),
+		TLSRedirectAddr:     os.Getenv("TLS_REDIRECT_ADDR"),
+		HSTSMaxAge:          getEnvDuration("HSTS_MAX_AGE", 365*24*time.Hour),
+
+		ShutdownDrainTimeout: getEnvDuration("SHUTDOWN_DRAIN_TIMEOUT", 20*time.Second),
+		ShutdownRetryAfter:   getEnvDuration("SHUTDOWN_RETRY_AFTER", 2*time.Second),
+
+		GRPCRequireAPIKey:          getEnvBool("GRPC_REQUIRE_API_KEY", false),
+		SessionAccessTTL:           getEnvDuration("SESSION_ACCESS_TTL", 15*time.Minute),
+		SessionRefreshTTL:          getEnvDuration("SESSION_REFRESH_TTL", 30*24*time.Hour),
+		SessionRevocationSync:      getEnvDuration("SESSION_REVOCATION_SYNC", 5*time.Second),
+		PermissionSync:             getEnvDuration("PERMISSION_SYNC", time.Minute),
+		WSAllowAnonymousSpectators: getEnvBool("WS_ALLOW_ANONYMOUS_SPECTATORS", false),
+		WSWorkers:                  getEnvInt("WS_WORKERS", 4*runtime.NumCPU()),
+		WSWorkerQueueSize:          getEnvInt("WS_WORKER_QUEUE_SIZE", 256),
+		WSPingPeriod:               getEnvDuration("WS_PING_PERIOD", 54*time.Second),
+		WSPongWait:                 getEnvDuration("WS_PONG_WAIT", 60*time.Second),
+		WSStaleAfter:               getEnvDuration("WS_STALE_AFTER", 0),
+		WSReapInterval:             getEnvDuration("WS_REAP_INTERVAL", 10*time.Second),
+		WSHubShards:                getEnvInt("WS_HUB_SHARDS", runtime.NumCPU()),
+		WSRegisterBuffer:           getEnvInt("WS_REGISTER_BUFFER", 256),
+		WSUnregisterBuffer:         getEnvInt("WS_UNREGISTER_BUFFER", 256),
+		WSBroadcastBuffer:          getEnvInt("WS_BROADCAST_BUFFER", 1024),
+		WSDirectBuffer:             getEnvInt("WS_DIRECT_BUFFER", 256),
+		WSInboundBuffer:            getEnvInt("WS_INBOUND_BUFFER", 1024),
+		WSEnqueueTimeout:           getEnvDuration("WS_ENQUEUE_TIMEOUT", time.Second),
+		WSCompression:              getEnvBool("WS_COMPRESSION", true),
+		WSCompressionLevel:         getEnvInt("WS_COMPRESSION_LEVEL", 1),
+		WSCompressionMinSize:       getEnvInt("WS_COMPRESSION_MIN_SIZE", 256),
+		WSConnectionTokenTTL:       getEnvDuration("WS_CONNECTION_TOKEN_TTL", time.Hour),
+		WSConnectionLog:            getEnvBool("WS_CONNECTION_LOG", true),
+		WSMaxMalformed:             getEnvInt("WS_MAX_MALFORMED", 5),
+		DemoPageEnabled:            getEnvBool("DEMO_PAGE_ENABLED", false),
+		LotCommandQueueSize:        getEnvInt("LOT_COMMAND_QUEUE_SIZE", 128),
+		BidPersistenceMode:         getEnv("BID_PERSISTENCE_MODE", BidPersistencePerBid),
+		BidBatchWindow:             getEnvDuration("BID_BATCH_WINDOW", 5*time.Millisecond),
+		BidBatchMaxBids:            getEnvInt("BID_BATCH_MAX_BIDS", 100),
+		BidFairnessWindow:          getEnvDuration("BID_FAIRNESS_WINDOW", 10*time.Second),
+		BidFairnessHold:            getEnvDuration("BID_FAIRNESS_HOLD", 50*time.Millisecond),
+		BidMaxRetries:              getEnvInt("BID_MAX_RETRIES", 3),
+		BidRetryBaseDelay:          getEnvDuration("BID_RETRY_BASE_DELAY", 10*time.Millisecond),
+		BidRetryMaxDelay:           getEnvDuration("BID_RETRY_MAX_DELAY", 200*time.Millisecond),
+		BidValidators:              getEnvList("BID_VALIDATORS"),
+		BidMaxAmount:               getEnvFloat("BID_MAX_AMOUNT", 1_000_000),
+		LotRulesCacheTTL:           getEnvDuration("LOT_RULES_CACHE_TTL", 30*time.Second),
+		GeoIPDatabase:              getEnv("GEOIP_DATABASE", ""),
+		KYCWebhookSecret:           getEnv("KYC_WEBHOOK_SECRET", ""),
+		KYCCacheTTL:                getEnvDuration("KYC_CACHE_TTL", time.Minute),
+		KYCStub:                    getEnvBool("KYC_STUB", false),
+		LotChangeNotify:            getEnvBool("LOT_CHANGE_NOTIFY", false),
+		LotHibernateAfter:          getEnvDuration("LOT_HIBERNATE_AFTER", 10*time.Minute),
+		HotLotBidsPerSecond:        getEnvFloat("HOT_LOT_BIDS_PER_SECOND", 20),
+		HotLotMaxUpdatesPerSecond:  getEnvInt("HOT_LOT_MAX_UPDATES_PER_SECOND", 5),
+		MaintenanceMode:            getEnvBool("MAINTENANCE_MODE", false),
+
+		EventBroker:        os.Getenv("EVENT_BROKER"),
+		NATSURL:            getEnv("NATS_URL", "nats://localhost:4222"),
+		KafkaBrokers:       getEnvList("KAFKA_BROKERS"),
+		EventSubjectPrefix: getEnv("EVENT_SUBJECT_PREFIX", "auction"),
+
+		SMTPHost:               os.Getenv("SMTP_HOST"),
+		SMTPPort:               getEnv("SMTP_PORT", "587"),
+		SMTPUsername:           os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:           os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:               getEnv("SMTP_FROM", "no-reply@auctionengine.local"),
+		NotificationWebhookURL: os.Getenv("NOTIFICATION_WEBHOOK_URL"),
+
+		MediaS3Endpoint:    os.Getenv("MEDIA_S3_ENDPOINT"),
+		MediaS3Bucket:      getEnv("MEDIA_S3_BUCKET", "lot-media"),
+		MediaS3AccessKey:   os.Getenv("MEDIA_S3_ACCESS_KEY"),
+		MediaS3SecretKey:   os.Getenv("MEDIA_S3_SECRET_KEY"),
+		MediaS3UseSSL:      getEnvBool("MEDIA_S3_USE_SSL", true),
+		MediaPublicBaseURL: os.Getenv("MEDIA_PUBLIC_BASE_URL"),
+
+		InvoiceBuyerPremiumRate: getEnvFloat("INVOICE_BUYER_PREMIUM_RATE", 0),
+		InvoiceTaxRate:          getEnvFloat("INVOICE_TAX_RATE", 0),
+
+		ChatEnabled:       getEnvBool("CHAT_ENABLED", false),
+		ChatMaxLength:     getEnvInt("CHAT_MAX_LENGTH", 280),
+		ChatRateLimit:     getEnvInt("CHAT_RATE_LIMIT", 5),
+		ChatRateWindow:    getEnvDuration("CHAT_RATE_WINDOW", 10*time.Second),
+		ChatHistorySize:   getEnvInt("CHAT_HISTORY_SIZE", 50),
+		ChatBlockedWords:  getEnvList("CHAT_BLOCKED_WORDS"),
+		ChatRejectBlocked: getEnvBool("CHAT_REJECT_BLOCKED", false),
+
+		LeaderboardSize:          getEnvInt("LEADERBOARD_SIZE", 10),
+		LeaderboardAnonymization: getEnv("LEADERBOARD_ANONYMIZATION", "paddles"),
+
+		EndingSoonWindow:   getEnvDuration("ENDING_SOON_WINDOW", 15*time.Minute),
+		EndingSoonInterval: getEnvDuration("ENDING_SOON_INTERVAL", 5*time.Second),
+
+		DisplayCurrencies: getEnvList("DISPLAY_CURRENCIES"),
+		FXBaseCurrency:    getEnv("FX_BASE_CURRENCY", "USD"),
+		FXRates:           getEnvList("FX_RATES"),
+	}
+}
+
+// Production reports if the app runs in production mode
+func (c *Config) Production() bool {
+	return c.AppEnv == EnvProduction
+}
+
+// TLSEnabled reports if the native HTTPS server is configured
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || len(c.TLSAutocertDomains) > 0
+}
+
+// getEnv returns the env variable value or def if it's not set
+func getEnv(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvBool parses a boolean env variable, returning def if it's not set or invalid
+func getEnvBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// getEnvInt parses an integer env variable, returning def if it's not set or invalid
+func getEnvInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// getEnvDuration parses a duration env variable (e.g. "5ms"), returning def if it's not set or invalid
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// getEnvFloat parses a float env variable, returning def if it's not set or invalid
+func getEnvFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// getEnvList parses a comma separated env variable, ignoring empty items
+func getEnvList(key string) []string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
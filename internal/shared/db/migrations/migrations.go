@@ -1,29 +1,158 @@
 package migrations
 
 import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
 	"github.com/cristianortiz/auctionEngine/internal/shared/db"
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"go.uber.org/zap"
 )
 
 var log = logger.GetLogger() // Instancia logger para el pakg
 
+// sqlFiles embeds the migrations in the binary, so they don't depend on the working directory
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// ErrMigrationsDirty is returned when the schema was left in a dirty state by a failed migration
+var ErrMigrationsDirty = errors.New("database migrations are in a dirty state")
+
+// ErrNoMigrationsApplied is returned when no migration has been applied yet
+var ErrNoMigrationsApplied = errors.New("no database migrations have been applied")
+
+// Info is the migration state of the database
+type Info struct {
+	Version uint `json:"version"` // last applied migration, 0 if none
+	Dirty   bool `json:"dirty"`   // the last migration failed half way and needs a manual fix
+	Latest  uint `json:"latest"`  // last migration embedded in the binary
+	Pending int  `json:"pending"` // embedded migrations not applied yet
+}
+
+// newMigrate creates a migrate instance over the embedded migrations
+func newMigrate() (*migrate.Migrate, error) {
+	src, err := iofs.New(sqlFiles, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+	return migrate.NewWithSourceInstance("iofs", src, db.BuildPostgresDSN())
+}
+
 func RunMigrations() error {
 	dbURL := db.BuildPostgresDSN()
 	log.Info("RunMigrations",
 		zap.String("posgresUrl", dbURL))
-	m, err := migrate.New(
-		"file://internal/shared/db/migrations/sql",
-		dbURL,
-	)
+	m, err := newMigrate()
 	if err != nil {
 		return err
 	}
+	defer m.Close()
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		return err
 	}
 	return nil
 }
+
+// Down rolls back the last steps applied migrations
+func Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	m, err := newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if err := m.Steps(-steps); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot roll back %d migrations: %w", steps, err)
+		}
+		return err
+	}
+	log.Info("Migrations rolled back", zap.Int("steps", steps))
+	return nil
+}
+
+// Status returns the current schema version and whether it is dirty
+func Status() (version uint, dirty bool, err error) {
+	m, err := newMigrate()
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, ErrNoMigrationsApplied
+	}
+	return version, dirty, err
+}
+
+// GetInfo returns the current schema version and how far it is from the embedded migrations
+func GetInfo() (Info, error) {
+	var info Info
+	version, dirty, err := Status()
+	if err != nil && !errors.Is(err, ErrNoMigrationsApplied) {
+		return info, err
+	}
+	info.Version, info.Dirty = version, dirty
+
+	versions, err := embeddedVersions()
+	if err != nil {
+		return info, err
+	}
+	for _, v := range versions {
+		if v > version {
+			info.Pending++
+		}
+		info.Latest = max(info.Latest, v)
+	}
+	return info, nil
+}
+
+// embeddedVersions lists the versions of the embedded migrations in ascending order
+func embeddedVersions() ([]uint, error) {
+	src, err := iofs.New(sqlFiles, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+	defer src.Close()
+	return listVersions(src)
+}
+
+func listVersions(src source.Driver) ([]uint, error) {
+	v, err := src.First()
+	if err != nil {
+		return nil, err
+	}
+	versions := []uint{v}
+	for {
+		v, err = src.Next(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+}
+
+// CheckStatus returns an error if migrations are missing or dirty, suitable for readiness probes
+func CheckStatus() error {
+	_, dirty, err := Status()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrMigrationsDirty
+	}
+	return nil
+}
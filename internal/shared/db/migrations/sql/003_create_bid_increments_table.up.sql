@@ -0,0 +1,17 @@
+-- table for tiered bid increments, a NULL lot_id means the global table
+CREATE TABLE IF NOT EXISTS bid_increments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    lot_id UUID, -- NULL for the global increment table
+    min_price DECIMAL(18, 2) NOT NULL, -- tier applies while current price >= min_price
+    increment DECIMAL(18, 2) NOT NULL CHECK (increment > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_bid_increments_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+-- only one tier per threshold for each lot (and for the global table)
+CREATE UNIQUE INDEX idx_bid_increments_lot_min_price
+    ON bid_increments (COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'::uuid), min_price);
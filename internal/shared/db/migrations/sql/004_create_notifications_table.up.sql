@@ -0,0 +1,29 @@
+-- table tracking the delivery of user notifications (email, webhook)
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    lot_id UUID NOT NULL,
+    kind VARCHAR(50) NOT NULL, -- e.g., 'lot_won', 'outbid'
+    channel VARCHAR(50) NOT NULL, -- e.g., 'email', 'webhook'
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL, -- e.g., 'pending', 'sent', 'failed'
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_notifications_user_id
+        FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_notifications_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
+CREATE INDEX idx_notifications_status ON notifications (status);
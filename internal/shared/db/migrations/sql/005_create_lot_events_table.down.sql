@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lot_events;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS event_seq;
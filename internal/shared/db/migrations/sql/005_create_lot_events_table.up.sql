@@ -0,0 +1,20 @@
+-- per lot monotonic event sequence, incremented when events are appended to the store
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS event_seq BIGINT NOT NULL DEFAULT 0;
+
+-- event store of lot events, used to replay missed events to reconnecting clients
+CREATE TABLE IF NOT EXISTS lot_events (
+    lot_id UUID NOT NULL,
+    seq BIGINT NOT NULL,
+    id UUID NOT NULL,
+    type VARCHAR(100) NOT NULL, -- e.g., 'bid.placed', 'lot.finished'
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (lot_id, seq),
+
+    CONSTRAINT fk_lot_events_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
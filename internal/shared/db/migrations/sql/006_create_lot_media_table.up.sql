@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS lot_media (
+    id UUID PRIMARY KEY,
+    lot_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- e.g., 'image', 'video'
+    url TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0, -- display order inside the lot
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_lot_media_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_lot_media_lot_id ON lot_media (lot_id, position);
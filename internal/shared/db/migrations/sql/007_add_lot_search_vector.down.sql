@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_auction_lots_search_vector;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS search_vector;
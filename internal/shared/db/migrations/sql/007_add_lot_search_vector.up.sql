@@ -0,0 +1,9 @@
+-- full-text search over lot title (weight A) and description (weight B),
+-- 'simple' config because lots are written in several languages
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_auction_lots_search_vector ON auction_lots USING GIN (search_vector);
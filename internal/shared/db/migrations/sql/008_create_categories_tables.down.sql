@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lot_categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    parent_id UUID NULL, -- NULL for top level categories
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_categories_parent_id
+        FOREIGN KEY (parent_id)
+        REFERENCES categories (id)
+        ON DELETE SET NULL
+);
+
+-- many-to-many lot categorization
+CREATE TABLE IF NOT EXISTS lot_categories (
+    lot_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+
+    PRIMARY KEY (lot_id, category_id),
+
+    CONSTRAINT fk_lot_categories_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_lot_categories_category_id
+        FOREIGN KEY (category_id)
+        REFERENCES categories (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_lot_categories_category_id ON lot_categories (category_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_bids_user_id_timestamp;
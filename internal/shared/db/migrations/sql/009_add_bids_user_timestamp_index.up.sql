@@ -0,0 +1,2 @@
+-- user bid history pages are ordered by the most recent bids
+CREATE INDEX IF NOT EXISTS idx_bids_user_id_timestamp ON bids (user_id, timestamp DESC);
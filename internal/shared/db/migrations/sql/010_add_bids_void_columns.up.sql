@@ -0,0 +1,5 @@
+-- voided bids are kept for auditing but ignored when computing the lot price and leader
+ALTER TABLE bids
+    ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS voided_by UUID,
+    ADD COLUMN IF NOT EXISTS void_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+-- per lot bidder aliases, broadcasts show the paddle number instead of the user ID
+CREATE TABLE IF NOT EXISTS lot_paddles (
+    lot_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    paddle_number INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (lot_id, user_id),
+    CONSTRAINT uq_lot_paddles_number UNIQUE (lot_id, paddle_number),
+    CONSTRAINT fk_lot_paddles_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_lot_paddles_user_id
+        FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fraud_alerts;
+DROP INDEX IF EXISTS idx_bids_lot_id_client_ip;
+ALTER TABLE bids DROP COLUMN IF EXISTS client_ip;
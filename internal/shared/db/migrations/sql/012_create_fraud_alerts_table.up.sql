@@ -0,0 +1,25 @@
+-- remote IP of the connection a bid was placed from, used by the fraud heuristics
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS client_ip TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_bids_lot_id_client_ip ON bids (lot_id, client_ip);
+
+CREATE TABLE IF NOT EXISTS fraud_alerts (
+    id UUID PRIMARY KEY,
+    lot_id UUID NOT NULL,
+    rule VARCHAR(50) NOT NULL, -- shared_ip_bidding, rapid_self_outbid
+    user_ids UUID[] NOT NULL, -- sorted, involved accounts
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, dismissed, confirmed
+    reviewed_by UUID,
+    review_note TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_fraud_alerts_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+-- a pattern keeps a single open alert while it's under review
+CREATE UNIQUE INDEX IF NOT EXISTS uq_fraud_alerts_open ON fraud_alerts (lot_id, rule, user_ids) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_fraud_alerts_status_created_at ON fraud_alerts (status, created_at DESC);
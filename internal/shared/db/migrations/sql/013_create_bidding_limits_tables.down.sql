@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bid_reservations;
+DROP TABLE IF EXISTS user_limits;
@@ -0,0 +1,27 @@
+-- max amount a user can commit in leading bids at the same time (deposit based)
+CREATE TABLE IF NOT EXISTS user_limits (
+    user_id UUID PRIMARY KEY,
+    bidding_limit NUMERIC(12, 2) NOT NULL CHECK (bidding_limit >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_user_limits_user_id
+        FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE
+);
+
+-- amount reserved from the user limit by their leading bid on each lot
+CREATE TABLE IF NOT EXISTS bid_reservations (
+    user_id UUID NOT NULL,
+    lot_id UUID NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, lot_id),
+    CONSTRAINT fk_bid_reservations_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bid_reservations_lot_id ON bid_reservations (lot_id);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY,
+    lot_id UUID NOT NULL UNIQUE, -- a lot is invoiced once, to its winner
+    buyer_id UUID NOT NULL,
+    bid_id UUID NOT NULL,
+    lot_title VARCHAR(255) NOT NULL,
+    hammer_price NUMERIC(12, 2) NOT NULL,
+    buyer_premium_rate NUMERIC(6, 4) NOT NULL,
+    buyer_premium NUMERIC(12, 2) NOT NULL,
+    tax_rate NUMERIC(6, 4) NOT NULL,
+    tax NUMERIC(12, 2) NOT NULL,
+    total NUMERIC(12, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'issued', -- issued, paid
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_invoices_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE RESTRICT,
+    CONSTRAINT fk_invoices_buyer_id
+        FOREIGN KEY (buyer_id)
+        REFERENCES users (id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_buyer_id_created_at ON invoices (buyer_id, created_at DESC);
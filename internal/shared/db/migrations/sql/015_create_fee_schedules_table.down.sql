@@ -0,0 +1,5 @@
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS flat_fee,
+    DROP COLUMN IF EXISTS fee_schedule_id;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS fee_schedule_id;
+DROP TABLE IF EXISTS fee_schedules;
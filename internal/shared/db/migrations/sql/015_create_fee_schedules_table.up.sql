@@ -0,0 +1,19 @@
+-- buyer's premium tiers are marginal: each rate applies to the part of the hammer price inside its tier
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    premium_tiers JSONB NOT NULL DEFAULT '[]', -- [{"up_to": 1000, "rate": 0.25}, {"up_to": 0, "rate": 0.2}], up_to 0 is unbounded
+    flat_fee NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    tax_rate NUMERIC(6, 4) NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE, -- applies to lots without a schedule
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_fee_schedules_default ON fee_schedules (is_default) WHERE is_default;
+
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS fee_schedule_id UUID
+    REFERENCES fee_schedules (id) ON DELETE SET NULL;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS fee_schedule_id UUID,
+    ADD COLUMN IF NOT EXISTS flat_fee NUMERIC(12, 2) NOT NULL DEFAULT 0;
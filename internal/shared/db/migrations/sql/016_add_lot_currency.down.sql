@@ -0,0 +1,2 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS currency;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS currency;
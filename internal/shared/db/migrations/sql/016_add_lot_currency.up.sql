@@ -0,0 +1,3 @@
+-- ISO 4217 code, amounts of the lot and its bids are in this currency
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
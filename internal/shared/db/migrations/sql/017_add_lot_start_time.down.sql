@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_auction_lots_state_start_time;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS start_time;
@@ -0,0 +1,5 @@
+-- start_time opens a lot automatically, until then the lot is in the 'preview' state: visible but not biddable
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS start_time TIMESTAMP WITH TIME ZONE;
+
+-- lots the scheduler has to open
+CREATE INDEX IF NOT EXISTS idx_auction_lots_state_start_time ON auction_lots (state, start_time);
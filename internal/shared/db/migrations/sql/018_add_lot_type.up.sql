@@ -0,0 +1,2 @@
+-- lot_type is the auction format: 'forward' (highest bid wins) or 'reverse' (procurement, lowest bid wins)
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS lot_type VARCHAR(20) NOT NULL DEFAULT 'forward';
@@ -0,0 +1,4 @@
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS scheduled_end_time;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS closing_price_threshold;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS closing_max_extension;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS closing_mode;
@@ -0,0 +1,11 @@
+-- closing policy of the lot: 'soft' (bids near the end extend it), 'hard' (closes at end_time) or
+-- 'price_threshold' (only bids moving the price by closing_price_threshold extend it)
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS closing_mode VARCHAR(20) NOT NULL DEFAULT 'soft';
+-- cap of the total extension over scheduled_end_time, zero means no cap
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS closing_max_extension INTERVAL NOT NULL DEFAULT '0';
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS closing_price_threshold DECIMAL(18, 2) NOT NULL DEFAULT 0;
+
+-- end time the lot was created with, end_time moves with the extensions
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS scheduled_end_time TIMESTAMP WITH TIME ZONE;
+UPDATE auction_lots SET scheduled_end_time = end_time WHERE scheduled_end_time IS NULL;
+ALTER TABLE auction_lots ALTER COLUMN scheduled_end_time SET NOT NULL;
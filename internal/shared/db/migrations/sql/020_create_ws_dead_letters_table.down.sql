@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ws_dead_letters;
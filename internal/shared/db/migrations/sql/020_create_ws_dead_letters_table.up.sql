@@ -0,0 +1,14 @@
+-- inbound WS msgs dropped before processing (full queues), kept so dropped bids can be audited
+CREATE TABLE IF NOT EXISTS ws_dead_letters (
+    id UUID PRIMARY KEY,
+    lot_id TEXT NOT NULL, -- as received from the connection
+    user_id UUID, -- NULL for anonymous connections
+    client_id TEXT NOT NULL,
+    message_type VARCHAR(50) NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL, -- raw msg, it may not be valid JSON
+    reason VARCHAR(50) NOT NULL, -- inbound_full, worker_busy
+    dropped_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ws_dead_letters_lot_id_dropped_at ON ws_dead_letters (lot_id, dropped_at);
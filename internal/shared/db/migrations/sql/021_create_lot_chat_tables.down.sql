@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lot_chat_mutes;
+DROP TABLE IF EXISTS lot_chat_messages;
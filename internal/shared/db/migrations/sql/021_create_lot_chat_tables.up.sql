@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS lot_chat_messages (
+    id UUID PRIMARY KEY,
+    lot_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    paddle INTEGER NOT NULL DEFAULT 0, -- 0 when the author never bid on the lot
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT fk_lot_chat_messages_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
+
+-- chat history of a lot
+CREATE INDEX IF NOT EXISTS idx_lot_chat_messages_lot_id_created_at ON lot_chat_messages (lot_id, created_at DESC);
+
+-- users silenced by an admin in the chat of a lot, a single mute per user and lot
+CREATE TABLE IF NOT EXISTS lot_chat_mutes (
+    lot_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    muted_by UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    until TIMESTAMP WITH TIME ZONE, -- NULL mutes until unmuted
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    PRIMARY KEY (lot_id, user_id),
+    CONSTRAINT fk_lot_chat_mutes_lot_id
+        FOREIGN KEY (lot_id)
+        REFERENCES auction_lots (id)
+        ON DELETE CASCADE
+);
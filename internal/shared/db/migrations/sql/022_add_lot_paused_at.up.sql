@@ -0,0 +1,3 @@
+-- start of the current pause of the lot, NULL unless the lot is paused. the end times are moved by the
+-- paused duration on resume
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP WITH TIME ZONE;
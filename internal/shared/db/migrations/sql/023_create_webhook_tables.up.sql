@@ -0,0 +1,40 @@
+-- external endpoints receiving auction events, secret signs the payloads (HMAC-SHA256)
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    min_bid_amount DECIMAL(18, 2), -- bid.placed threshold, NULL delivers every bid
+    secret TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- subscriptions of an event type
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_event_types ON webhook_subscriptions USING GIN (event_types);
+
+-- delivery log, payload is JSON (not JSONB) to keep the exact body, a redelivery sends the same bytes
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    lot_id UUID NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_webhook_deliveries_subscription_id
+        FOREIGN KEY (subscription_id)
+        REFERENCES webhook_subscriptions (id)
+        ON DELETE CASCADE
+);
+
+-- delivery log of a subscription
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id_created_at ON webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,4 @@
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS org_id;
+DROP INDEX IF EXISTS idx_auction_lots_org_id_state;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS org_id;
+DROP TABLE IF EXISTS organizations;
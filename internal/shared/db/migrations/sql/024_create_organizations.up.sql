@@ -0,0 +1,22 @@
+-- organizations (auction houses) hosted by the deployment, every lot belongs to one
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- default organization, owner of the data created before multi-tenancy (tenant.DefaultOrgID)
+INSERT INTO organizations (id, name, slug)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+    CONSTRAINT fk_auction_lots_org_id REFERENCES organizations (id);
+
+-- lots of an organization by state (admin listings, scoped searches)
+CREATE INDEX IF NOT EXISTS idx_auction_lots_org_id_state ON auction_lots (org_id, state);
+
+-- webhook subscriptions only receive the events of the lots of their organization
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+    CONSTRAINT fk_webhook_subscriptions_org_id REFERENCES organizations (id);
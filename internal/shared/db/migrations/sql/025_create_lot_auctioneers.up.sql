@@ -0,0 +1,8 @@
+-- auctioneers assigned to run a lot through the admin WS channel, org admins run every lot of their organization
+CREATE TABLE IF NOT EXISTS lot_auctioneers (
+    lot_id UUID NOT NULL REFERENCES auction_lots (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    assigned_by UUID NOT NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lot_id, user_id)
+);
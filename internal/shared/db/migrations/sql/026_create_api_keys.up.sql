@@ -0,0 +1,22 @@
+-- API keys of the machine clients, only the SHA-256 of the key is stored
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL,
+    user_id UUID NOT NULL, -- identity of the client, the bidder of the bids placed with the key
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_api_keys_org_id
+        FOREIGN KEY (org_id)
+        REFERENCES organizations (id)
+);
+
+-- keys of an organization, newest first
+CREATE INDEX IF NOT EXISTS idx_api_keys_org_id_created_at ON api_keys (org_id, created_at DESC);
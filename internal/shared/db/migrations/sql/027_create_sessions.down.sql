@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS token_revocations;
+DROP TABLE IF EXISTS session_refresh_tokens;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,44 @@
+-- sign ins of the users, their access tokens carry the session ID (sid claim) so revoking it rejects them
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    org_id UUID, -- NULL for the super admins of the whole platform
+    role VARCHAR(20) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoke_reason VARCHAR(50) NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_sessions_org_id
+        FOREIGN KEY (org_id)
+        REFERENCES organizations (id)
+);
+
+-- sessions of a user, newest first
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id_created_at ON sessions (user_id, created_at DESC);
+-- revocations read by the sync of every instance
+CREATE INDEX IF NOT EXISTS idx_sessions_revoked_at ON sessions (revoked_at) WHERE revoked_at IS NOT NULL;
+
+-- single use refresh tokens, a used one is kept to detect its reuse
+CREATE TABLE IF NOT EXISTS session_refresh_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    session_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_session_refresh_tokens_session_id
+        FOREIGN KEY (session_id)
+        REFERENCES sessions (id)
+        ON DELETE CASCADE
+);
+
+-- tokens of a user issued up to revoked_before are rejected, the nil UUID org_id covers every organization
+CREATE TABLE IF NOT EXISTS token_revocations (
+    user_id UUID NOT NULL,
+    org_id UUID NOT NULL,
+    revoked_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, org_id)
+);
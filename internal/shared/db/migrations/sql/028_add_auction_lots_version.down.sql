@@ -0,0 +1 @@
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- optimistic concurrency token of the lots, every update of a lot checks and advances it so a write based
+-- on a stale read (another instance saved the lot in between) fails instead of overwriting it
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
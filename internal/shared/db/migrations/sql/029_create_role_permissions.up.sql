@@ -0,0 +1,37 @@
+-- the permissions table of the RBAC: the permissions granted to each role, read by every instance
+-- periodically so a change applies without a deploy. a role without rows has no permissions
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+-- the built-in table (auth.DefaultPermissions)
+INSERT INTO role_permissions (role, permission) VALUES
+    ('bidder', 'bids:place'),
+    ('auctioneer', 'lots:run'),
+    ('auctioneer', 'chat:moderate'),
+    ('org_admin', 'lots:run'),
+    ('org_admin', 'chat:moderate'),
+    ('org_admin', 'lots:manage'),
+    ('org_admin', 'bidders:view'),
+    ('org_admin', 'lots:assign_auctioneers'),
+    ('org_admin', 'fraud:review'),
+    ('org_admin', 'webhooks:manage'),
+    ('org_admin', 'invoices:read'),
+    ('org_admin', 'api_keys:manage'),
+    ('org_admin', 'sessions:manage'),
+    ('super_admin', 'lots:run'),
+    ('super_admin', 'chat:moderate'),
+    ('super_admin', 'lots:manage'),
+    ('super_admin', 'bidders:view'),
+    ('super_admin', 'lots:assign_auctioneers'),
+    ('super_admin', 'fraud:review'),
+    ('super_admin', 'webhooks:manage'),
+    ('super_admin', 'invoices:read'),
+    ('super_admin', 'api_keys:manage'),
+    ('super_admin', 'sessions:manage'),
+    ('super_admin', 'catalog:manage'),
+    ('super_admin', 'organizations:manage'),
+    ('super_admin', 'platform:operate')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'personal_data:manage';
+DROP TABLE IF EXISTS user_erasure_requests;
@@ -0,0 +1,22 @@
+-- erasure requests of the users personal data, approved or rejected by an admin. user_id has no FK, the
+-- user is deleted once the request is completed and the request is kept as the record of the erasure
+CREATE TABLE IF NOT EXISTS user_erasure_requests (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL, -- pending, completed, rejected
+    reviewed_by UUID,
+    review_note TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    tombstone_id UUID, -- user the data was reattributed to, set once completed
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- a single pending request per user
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_erasure_requests_pending ON user_erasure_requests (user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_user_erasure_requests_created_at ON user_erasure_requests (created_at DESC);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('super_admin', 'personal_data:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE bids DROP COLUMN IF EXISTS received_at;
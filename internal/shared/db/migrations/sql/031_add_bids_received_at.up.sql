@@ -0,0 +1,2 @@
+-- when the server received the bid, the lot end is adjudicated on it. NULL for the bids placed without it
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS received_at TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+-- expressions configured by the operators on a lot, evaluated on every bid (see internal/shared/expr). an
+-- empty expression leaves the default behavior: the increment table and every bidder eligible
+CREATE TABLE IF NOT EXISTS lot_rules (
+    lot_id UUID PRIMARY KEY REFERENCES auction_lots (id) ON DELETE CASCADE,
+    min_increment TEXT NOT NULL DEFAULT '',
+    eligibility TEXT NOT NULL DEFAULT '',
+    updated_by UUID NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
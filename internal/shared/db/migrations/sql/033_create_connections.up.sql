@@ -0,0 +1,18 @@
+-- WS connections, one row per connection. the reconnections of a client keep its client_id, so its
+-- rows are the history of the client across network drops and instances
+CREATE TABLE IF NOT EXISTS connections (
+    id BIGSERIAL PRIMARY KEY,
+    client_id UUID NOT NULL,
+    user_id UUID, -- NULL for the anonymous spectators
+    org_id UUID,
+    lot_id VARCHAR(64) NOT NULL, -- the lot, or the lobby room of the organization
+    role VARCHAR(20) NOT NULL,
+    remote_ip VARCHAR(45) NOT NULL DEFAULT '',
+    resumed BOOLEAN NOT NULL DEFAULT FALSE,
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    disconnected_at TIMESTAMP WITH TIME ZONE
+);
+
+-- connections of a client and of a user, newest first
+CREATE INDEX IF NOT EXISTS idx_connections_client_id_connected_at ON connections (client_id, connected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_connections_user_id_connected_at ON connections (user_id, connected_at DESC) WHERE user_id IS NOT NULL;
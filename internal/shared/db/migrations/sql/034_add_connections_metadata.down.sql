@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_connections_remote_ip_connected_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS protocol;
+ALTER TABLE connections DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,6 @@
+-- device and protocol of the WS connections, for abuse investigations and capacity planning
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS protocol VARCHAR(32) NOT NULL DEFAULT '';
+
+-- connections of a remote IP, newest first
+CREATE INDEX IF NOT EXISTS idx_connections_remote_ip_connected_at ON connections (remote_ip, connected_at DESC);
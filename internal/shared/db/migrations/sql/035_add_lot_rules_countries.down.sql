@@ -0,0 +1,2 @@
+ALTER TABLE lot_rules DROP COLUMN IF EXISTS blocked_countries;
+ALTER TABLE lot_rules DROP COLUMN IF EXISTS allowed_countries;
@@ -0,0 +1,4 @@
+-- country lists of the lots restricted to some jurisdictions, ISO 3166-1 alpha-2 codes. checked on the
+-- country of the client IP at connection and bid time, empty lists let every country in
+ALTER TABLE lot_rules ADD COLUMN IF NOT EXISTS allowed_countries TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE lot_rules ADD COLUMN IF NOT EXISTS blocked_countries TEXT[] NOT NULL DEFAULT '{}';
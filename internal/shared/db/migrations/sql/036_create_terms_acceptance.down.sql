@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS terms_acceptance;
+ALTER TABLE lot_rules DROP COLUMN IF EXISTS terms_version;
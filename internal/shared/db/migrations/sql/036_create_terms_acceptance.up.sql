@@ -0,0 +1,14 @@
+-- version of the terms the bidders of a lot must accept before bidding, empty requires none
+ALTER TABLE lot_rules ADD COLUMN IF NOT EXISTS terms_version VARCHAR(64) NOT NULL DEFAULT '';
+
+-- the terms accepted by the bidders, a new version of the terms of a lot must be accepted again
+CREATE TABLE IF NOT EXISTS terms_acceptance (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    lot_id UUID NOT NULL REFERENCES auction_lots (id) ON DELETE CASCADE,
+    terms_version VARCHAR(64) NOT NULL,
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    accepted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, lot_id, terms_version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_terms_acceptance_lot_id ON terms_acceptance (lot_id, terms_version);
@@ -0,0 +1,2 @@
+ALTER TABLE lot_rules DROP COLUMN IF EXISTS require_verified;
+DROP TABLE IF EXISTS user_verifications;
@@ -0,0 +1,12 @@
+-- identity verification (KYC) status of the users, pushed by the KYC providers through the webhook.
+-- the users without a row were never checked
+CREATE TABLE IF NOT EXISTS user_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    provider VARCHAR(64) NOT NULL DEFAULT '',
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- the lots only verified bidders can bid on, e.g. the high value ones
+ALTER TABLE lot_rules ADD COLUMN IF NOT EXISTS require_verified BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE lot_rules DROP COLUMN IF EXISTS reserve_price;
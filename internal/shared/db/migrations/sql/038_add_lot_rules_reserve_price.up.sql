@@ -0,0 +1,2 @@
+-- price the seller wants the lot to reach, the bidders only see whether it's met. 0 means no reserve
+ALTER TABLE lot_rules ADD COLUMN IF NOT EXISTS reserve_price DECIMAL(18, 2) NOT NULL DEFAULT 0 CHECK (reserve_price >= 0);
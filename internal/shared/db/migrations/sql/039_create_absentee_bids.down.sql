@@ -0,0 +1 @@
+DROP TABLE IF EXISTS absentee_bids;
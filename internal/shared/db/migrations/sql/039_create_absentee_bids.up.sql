@@ -0,0 +1,21 @@
+-- bids left by the users on the lots not opened yet, entered by the engine when the lot opens and bid
+-- again up to their max each time their user is outbid.
+-- a user has at most one pending absentee bid per lot
+CREATE TABLE IF NOT EXISTS absentee_bids (
+    id UUID PRIMARY KEY,
+    lot_id UUID NOT NULL REFERENCES auction_lots (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    max_amount DECIMAL(18, 3) NOT NULL CHECK (max_amount > 0),
+    -- the bids entered for the user are placed from the IP the absentee bid was submitted from
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    bid_id UUID,
+    bid_amount DECIMAL(18, 3) NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_absentee_bids_lot_id ON absentee_bids (lot_id, status, submitted_at);
+CREATE INDEX IF NOT EXISTS idx_absentee_bids_user_id ON absentee_bids (user_id, submitted_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_absentee_bids_pending ON absentee_bids (lot_id, user_id) WHERE status = 'pending';
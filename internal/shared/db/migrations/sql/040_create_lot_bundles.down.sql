@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS lot_bundle_bids;
+DROP TABLE IF EXISTS lot_bundle_lots;
+DROP TABLE IF EXISTS lot_bundles;
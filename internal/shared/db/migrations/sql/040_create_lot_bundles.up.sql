@@ -0,0 +1,35 @@
+-- bundles of lots bid on as a whole, a bundle wins when its leading bid beats the sum of the winning
+-- bids of its lots once they all closed. the amounts keep 3 decimals for the currencies using them, e.g. KWD
+CREATE TABLE IF NOT EXISTS lot_bundles (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    state VARCHAR(16) NOT NULL DEFAULT 'open',
+    leading_bid_id UUID,
+    leading_user_id UUID,
+    leading_amount DECIMAL(18, 3) NOT NULL DEFAULT 0,
+    leading_at TIMESTAMP WITH TIME ZONE,
+    individual_sum DECIMAL(18, 3) NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    settled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS lot_bundle_lots (
+    bundle_id UUID NOT NULL REFERENCES lot_bundles (id) ON DELETE CASCADE,
+    lot_id UUID NOT NULL REFERENCES auction_lots (id) ON DELETE CASCADE,
+    PRIMARY KEY (bundle_id, lot_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lot_bundle_lots_lot_id ON lot_bundle_lots (lot_id);
+
+CREATE TABLE IF NOT EXISTS lot_bundle_bids (
+    id UUID PRIMARY KEY,
+    bundle_id UUID NOT NULL REFERENCES lot_bundles (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    amount DECIMAL(18, 3) NOT NULL CHECK (amount > 0),
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_lot_bundle_bids_bundle_id ON lot_bundle_bids (bundle_id, timestamp);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_invoices_lot_id;
+DROP INDEX IF EXISTS idx_invoices_bid_id;
+ALTER TABLE invoices ADD CONSTRAINT invoices_lot_id_key UNIQUE (lot_id);
+
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS unit_pricing;
+ALTER TABLE auction_lots DROP COLUMN IF EXISTS quantity;
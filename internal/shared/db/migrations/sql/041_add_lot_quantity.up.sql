@@ -0,0 +1,9 @@
+-- lots of several identical units, the quantity best bidders win one unit each paying their bid
+-- (pay_as_bid) or the lowest winning bid (uniform)
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS quantity INT NOT NULL DEFAULT 1 CHECK (quantity >= 1);
+ALTER TABLE auction_lots ADD COLUMN IF NOT EXISTS unit_pricing VARCHAR(16) NOT NULL DEFAULT 'pay_as_bid';
+
+-- a multi-unit lot is invoiced once per winning bid
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_lot_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_bid_id ON invoices (bid_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_lot_id ON invoices (lot_id);
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Reader runs read-only queries, implemented by *pgxpool.Pool and ReadRouter
+type Reader interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads marks ctx so the ReadRouter sends its queries to the primary, used to read
+// the result of a write just committed, which the replica may not have applied yet
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+func primaryReads(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return v
+}
+
+// BuildPostgresReplicaDSN returns the DSN of the read replica from DB_REPLICA_HOST and DB_REPLICA_PORT,
+// the rest of the settings are shared with the primary. it's empty when DB_REPLICA_HOST is not set
+func BuildPostgresReplicaDSN() string {
+	host := os.Getenv("DB_REPLICA_HOST")
+	if host == "" {
+		return ""
+	}
+	port := os.Getenv("DB_REPLICA_PORT")
+	if port == "" {
+		port = os.Getenv("DB_PORT")
+	}
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), host, port, os.Getenv("DB_NAME"), os.Getenv("DB_SSLMODE"),
+	)
+}
+
+// NewPostgresReplicaPool creates the pool of the read replica, nil when no replica is configured.
+// connections are opened lazily, an unreachable replica doesn't prevent the startup
+func NewPostgresReplicaPool(ctx context.Context) (*pgxpool.Pool, error) {
+	dsn := BuildPostgresReplicaDSN()
+	if dsn == "" {
+		return nil, nil
+	}
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse replica database config: %w", err)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create replica DB pool: %w", err)
+	}
+	return pool, nil
+}
+
+// ReadRouter sends read queries to the replica, falling back to the primary while the replica is unavailable.
+// a failed replica query is retried on the primary and the replica is skipped until a health check succeeds
+type ReadRouter struct {
+	primary     *pgxpool.Pool
+	replica     *pgxpool.Pool
+	replicaDown atomic.Bool
+}
+
+// NewReadRouter creates a new instance of ReadRouter, with a nil replica every query goes to the primary
+func NewReadRouter(primary, replica *pgxpool.Pool) *ReadRouter {
+	return &ReadRouter{primary: primary, replica: replica}
+}
+
+// Run pings the replica every interval until ctx is done, restoring it after a failure
+func (r *ReadRouter) Run(ctx context.Context, interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			err := r.replica.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				r.markDown(err)
+			} else if r.replicaDown.CompareAndSwap(true, false) {
+				logger.GetLogger().Info("DB read replica is available again, routing reads to it")
+			}
+		}
+	}
+}
+
+// Query implements Reader
+func (r *ReadRouter) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if !r.useReplica(ctx) {
+		return r.primary.Query(ctx, sql, args...)
+	}
+	rows, err := r.replica.Query(ctx, sql, args...)
+	if unavailable(ctx, err) {
+		r.markDown(err)
+		return r.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// QueryRow implements Reader
+func (r *ReadRouter) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if !r.useReplica(ctx) {
+		return r.primary.QueryRow(ctx, sql, args...)
+	}
+	return &fallbackRow{router: r, ctx: ctx, sql: sql, args: args, row: r.replica.QueryRow(ctx, sql, args...)}
+}
+
+func (r *ReadRouter) useReplica(ctx context.Context) bool {
+	return r.replica != nil && !r.replicaDown.Load() && !primaryReads(ctx)
+}
+
+func (r *ReadRouter) markDown(err error) {
+	if r.replicaDown.CompareAndSwap(false, true) {
+		logger.GetLogger().Warn("DB read replica is unavailable, routing reads to the primary", zap.Error(err))
+	}
+}
+
+// fallbackRow retries on the primary a replica row failing to scan because the replica is unavailable,
+// QueryRow errors are only known on Scan
+type fallbackRow struct {
+	router *ReadRouter
+	ctx    context.Context
+	sql    string
+	args   []any
+	row    pgx.Row
+}
+
+func (f *fallbackRow) Scan(dest ...any) error {
+	err := f.row.Scan(dest...)
+	if unavailable(f.ctx, err) {
+		f.router.markDown(err)
+		return f.router.primary.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+	}
+	return err
+}
+
+// unavailable reports whether err means the server can't be reached or can't serve queries (connection
+// exceptions and shutdowns), errors of the query itself and the caller own cancellation are returned as is
+func unavailable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57")
+	}
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) || errors.As(err, &netErr) || pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,407 @@
+// Package expr evaluates the small expressions the operators configure at runtime (lot rules), written in
+// the Go expression syntax over numbers, strings and booleans, e.g.
+//
+//	bidder.registered_hours > 24 && amount <= 10000
+//	max(5, current_price * 0.05)
+//
+// only the variables given to Compile and the functions min, max, abs, floor, ceil and round can be used
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxLength is the max length of the source of an expression
+const MaxLength = 1024
+
+var (
+	// ErrInvalidExpression is the parent of the errors of Compile
+	ErrInvalidExpression = errors.New("invalid expression")
+	// ErrEval is the parent of the errors of Eval
+	ErrEval = errors.New("expression evaluation failed")
+)
+
+// functions are the builtins by name with their number of args, -1 for variadic ones (at least one)
+var functions = map[string]int{
+	"min": -1, "max": -1, "abs": 1, "floor": 1, "ceil": 1, "round": 1,
+}
+
+// Program is a compiled expression, safe for concurrent use
+type Program struct {
+	source string
+	root   ast.Expr
+	vars   []string
+}
+
+// Compile parses source and checks it only uses the allowed vars, nested vars are written with dots like
+// bidder.registered_hours
+func Compile(source string, vars []string) (*Program, error) {
+	if len(source) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d chars", ErrInvalidExpression, MaxLength)
+	}
+	root, err := parser.ParseExpr(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	allowed := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		allowed[v] = true
+	}
+	p := &Program{source: source, root: root}
+	used := make(map[string]bool)
+	if err := p.check(root, allowed, used); err != nil {
+		return nil, err
+	}
+	for _, v := range vars {
+		if used[v] {
+			p.vars = append(p.vars, v)
+		}
+	}
+	return p, nil
+}
+
+// Source returns the source of the expression
+func (p *Program) Source() string { return p.source }
+
+// Uses reports if the expression reads the var, or a var nested under it when prefix ends with a dot. a
+// nil program reads none
+func (p *Program) Uses(prefix string) bool {
+	if p == nil {
+		return false
+	}
+	for _, v := range p.vars {
+		if v == prefix || (strings.HasSuffix(prefix, ".") && strings.HasPrefix(v, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Program) check(node ast.Expr, allowed, used map[string]bool) error {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT && n.Kind != token.STRING {
+			return fmt.Errorf("%w: unsupported literal %s", ErrInvalidExpression, n.Value)
+		}
+		return nil
+	case *ast.Ident:
+		if n.Name == "true" || n.Name == "false" {
+			return nil
+		}
+		return checkVar(n.Name, allowed, used)
+	case *ast.SelectorExpr:
+		name, ok := varName(n)
+		if !ok {
+			return fmt.Errorf("%w: unsupported selector", ErrInvalidExpression)
+		}
+		return checkVar(name, allowed, used)
+	case *ast.ParenExpr:
+		return p.check(n.X, allowed, used)
+	case *ast.UnaryExpr:
+		if n.Op != token.NOT && n.Op != token.SUB && n.Op != token.ADD {
+			return fmt.Errorf("%w: unsupported operator %s", ErrInvalidExpression, n.Op)
+		}
+		return p.check(n.X, allowed, used)
+	case *ast.BinaryExpr:
+		switch n.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+			token.LAND, token.LOR, token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		default:
+			return fmt.Errorf("%w: unsupported operator %s", ErrInvalidExpression, n.Op)
+		}
+		if err := p.check(n.X, allowed, used); err != nil {
+			return err
+		}
+		return p.check(n.Y, allowed, used)
+	case *ast.CallExpr:
+		fn, ok := n.Fun.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("%w: unsupported call", ErrInvalidExpression)
+		}
+		arity, ok := functions[fn.Name]
+		if !ok {
+			return fmt.Errorf("%w: unknown function %s", ErrInvalidExpression, fn.Name)
+		}
+		if (arity < 0 && len(n.Args) == 0) || (arity >= 0 && len(n.Args) != arity) || n.Ellipsis.IsValid() {
+			return fmt.Errorf("%w: wrong number of args for %s", ErrInvalidExpression, fn.Name)
+		}
+		for _, arg := range n.Args {
+			if err := p.check(arg, allowed, used); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported expression %T", ErrInvalidExpression, node)
+	}
+}
+
+func checkVar(name string, allowed, used map[string]bool) error {
+	if !allowed[name] {
+		return fmt.Errorf("%w: unknown variable %s", ErrInvalidExpression, name)
+	}
+	used[name] = true
+	return nil
+}
+
+// varName flattens a selector chain of idents into its dotted name
+func varName(node ast.Expr) (string, bool) {
+	switch n := node.(type) {
+	case *ast.Ident:
+		return n.Name, true
+	case *ast.SelectorExpr:
+		parent, ok := varName(n.X)
+		if !ok {
+			return "", false
+		}
+		return parent + "." + n.Sel.Name, true
+	}
+	return "", false
+}
+
+// Eval evaluates the expression with the values of the vars, which must be float64, int, bool or string
+func (p *Program) Eval(env map[string]any) (any, error) {
+	return eval(p.root, env)
+}
+
+// EvalBool evaluates an expression that must result in a boolean
+func (p *Program) EvalBool(env map[string]any) (bool, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: result is %T, want a boolean", ErrEval, v)
+	}
+	return b, nil
+}
+
+// EvalNumber evaluates an expression that must result in a finite number
+func (p *Program) EvalNumber(env map[string]any) (float64, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%w: result is %T, want a number", ErrEval, v)
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("%w: result is not a finite number", ErrEval)
+	}
+	return n, nil
+}
+
+func eval(node ast.Expr, env map[string]any) (any, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind == token.STRING {
+			return strconv.Unquote(n.Value)
+		}
+		return strconv.ParseFloat(strings.ReplaceAll(n.Value, "_", ""), 64)
+	case *ast.Ident:
+		switch n.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return lookup(n.Name, env)
+	case *ast.SelectorExpr:
+		name, _ := varName(n)
+		return lookup(name, env)
+	case *ast.ParenExpr:
+		return eval(n.X, env)
+	case *ast.UnaryExpr:
+		x, err := eval(n.X, env)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == token.NOT {
+			b, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: ! needs a boolean, got %T", ErrEval, x)
+			}
+			return !b, nil
+		}
+		f, ok := x.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs a number, got %T", ErrEval, n.Op, x)
+		}
+		if n.Op == token.SUB {
+			return -f, nil
+		}
+		return f, nil
+	case *ast.BinaryExpr:
+		return evalBinary(n, env)
+	case *ast.CallExpr:
+		return evalCall(n, env)
+	}
+	return nil, fmt.Errorf("%w: unsupported expression %T", ErrEval, node)
+}
+
+func lookup(name string, env map[string]any) (any, error) {
+	v, ok := env[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: variable %s is not set", ErrEval, name)
+	}
+	switch v := v.(type) {
+	case float64, bool, string:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf("%w: variable %s has unsupported type %T", ErrEval, name, v)
+}
+
+func evalBinary(n *ast.BinaryExpr, env map[string]any) (any, error) {
+	x, err := eval(n.X, env)
+	if err != nil {
+		return nil, err
+	}
+	// && and || short circuit, so a guard like has_bids && ... skips the rest
+	if n.Op == token.LAND || n.Op == token.LOR {
+		xb, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs booleans, got %T", ErrEval, n.Op, x)
+		}
+		if (n.Op == token.LAND && !xb) || (n.Op == token.LOR && xb) {
+			return xb, nil
+		}
+		y, err := eval(n.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		yb, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs booleans, got %T", ErrEval, n.Op, y)
+		}
+		return yb, nil
+	}
+	y, err := eval(n.Y, env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.Op {
+	case token.EQL:
+		return equal(x, y)
+	case token.NEQ:
+		eq, err := equal(x, y)
+		if err != nil {
+			return nil, err
+		}
+		return !eq, nil
+	}
+	if xs, ok := x.(string); ok {
+		ys, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s between string and %T", ErrEval, n.Op, y)
+		}
+		switch n.Op {
+		case token.LSS:
+			return xs < ys, nil
+		case token.LEQ:
+			return xs <= ys, nil
+		case token.GTR:
+			return xs > ys, nil
+		case token.GEQ:
+			return xs >= ys, nil
+		}
+		return nil, fmt.Errorf("%w: %s is not defined on strings", ErrEval, n.Op)
+	}
+	xf, xok := x.(float64)
+	yf, yok := y.(float64)
+	if !xok || !yok {
+		return nil, fmt.Errorf("%w: %s needs numbers, got %T and %T", ErrEval, n.Op, x, y)
+	}
+	switch n.Op {
+	case token.ADD:
+		return xf + yf, nil
+	case token.SUB:
+		return xf - yf, nil
+	case token.MUL:
+		return xf * yf, nil
+	case token.QUO, token.REM:
+		if yf == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrEval)
+		}
+		if n.Op == token.REM {
+			return math.Mod(xf, yf), nil
+		}
+		return xf / yf, nil
+	case token.LSS:
+		return xf < yf, nil
+	case token.LEQ:
+		return xf <= yf, nil
+	case token.GTR:
+		return xf > yf, nil
+	case token.GEQ:
+		return xf >= yf, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported operator %s", ErrEval, n.Op)
+}
+
+func equal(x, y any) (bool, error) {
+	switch xv := x.(type) {
+	case float64:
+		if yv, ok := y.(float64); ok {
+			return xv == yv, nil
+		}
+	case string:
+		if yv, ok := y.(string); ok {
+			return xv == yv, nil
+		}
+	case bool:
+		if yv, ok := y.(bool); ok {
+			return xv == yv, nil
+		}
+	}
+	return false, fmt.Errorf("%w: comparing %T with %T", ErrEval, x, y)
+}
+
+func evalCall(n *ast.CallExpr, env map[string]any) (any, error) {
+	name := n.Fun.(*ast.Ident).Name
+	args := make([]float64, len(n.Args))
+	for i, arg := range n.Args {
+		v, err := eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs numbers, got %T", ErrEval, name, v)
+		}
+		args[i] = f
+	}
+	switch name {
+	case "min", "max":
+		result := args[0]
+		for _, a := range args[1:] {
+			if name == "min" {
+				result = math.Min(result, a)
+			} else {
+				result = math.Max(result, a)
+			}
+		}
+		return result, nil
+	case "abs":
+		return math.Abs(args[0]), nil
+	case "floor":
+		return math.Floor(args[0]), nil
+	case "ceil":
+		return math.Ceil(args[0]), nil
+	case "round":
+		return math.Round(args[0]), nil
+	}
+	return nil, fmt.Errorf("%w: unknown function %s", ErrEval, name)
+}
@@ -0,0 +1,76 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := []string{"amount", "current_price", "has_bids", "bidder.registered_hours", "lot_type"}
+	env := map[string]any{
+		"amount":                  150.0,
+		"current_price":           100.0,
+		"has_bids":                false,
+		"bidder.registered_hours": 30,
+		"lot_type":                "forward",
+	}
+	tests := []struct {
+		source string
+		want   any
+	}{
+		{"bidder.registered_hours > 24", true},
+		{"amount - current_price >= 50 && lot_type == \"forward\"", true},
+		{"max(5, current_price * 0.05)", 5.0},
+		{"round(current_price / 3)", 33.0},
+		{"has_bids && amount / 0 > 1", false}, // short circuit, the division isn't evaluated
+		{"!has_bids || amount > 1_000", true},
+		{"-current_price + 1e3", 900.0},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.source, vars)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.source, err)
+		}
+		got, err := p.Eval(env)
+		if err != nil {
+			t.Fatalf("Eval(%q): %v", tt.source, err)
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRejects(t *testing.T) {
+	for _, source := range []string{
+		"amount >",              // syntax
+		"unknown > 1",           // unknown var
+		"bidder.email == \"x\"", // unknown nested var
+		"os.Exit(1)",            // call of a selector
+		"len(\"abc\")",          // unknown function
+		"abs(1, 2)",             // wrong arity
+		"amount << 2",           // unsupported operator
+		"[]int{1}",              // unsupported expression
+		"func() bool { return true }()",
+	} {
+		if _, err := Compile(source, []string{"amount", "bidder.registered_hours"}); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Compile(%q) err = %v, want ErrInvalidExpression", source, err)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	p, err := Compile("amount / (amount - 100) > 1", []string{"amount"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Eval(map[string]any{"amount": 100.0}); !errors.Is(err, ErrEval) {
+		t.Fatalf("division by zero: err = %v, want ErrEval", err)
+	}
+	if _, err := p.EvalNumber(map[string]any{"amount": 150.0}); !errors.Is(err, ErrEval) {
+		t.Fatalf("boolean result as number: err = %v, want ErrEval", err)
+	}
+	if _, err := p.Eval(map[string]any{}); !errors.Is(err, ErrEval) {
+		t.Fatalf("missing var: err = %v, want ErrEval", err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package geoip resolves the country of the client IPs, for the lots restricted to some jurisdictions.
+// the resolution is behind Resolver so a deployment can plug its provider, RangeResolver reads the
+// network to country CSV exported by the usual GeoIP databases
+package geoip
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrorCodeGeoRestricted is the error code of the connections and bids rejected by the country lists of a lot
+const ErrorCodeGeoRestricted = "GEO_RESTRICTED"
+
+var (
+	// ErrRestricted is returned when the country of the client is not allowed on the lot
+	ErrRestricted = errors.New("not available in your country")
+	// ErrInvalidCountry is returned for a country that is not an ISO 3166-1 alpha-2 code
+	ErrInvalidCountry = errors.New("invalid country code")
+)
+
+// Resolver returns the ISO 3166-1 alpha-2 country of an IP, "" when it's unknown (private networks,
+// addresses missing from the database)
+type Resolver interface {
+	Country(ctx context.Context, ip string) (string, error)
+}
+
+// Restriction are the country lists of a lot. a country in Blocked is always rejected, a non empty
+// Allowed rejects the others. an unknown country is rejected by an allow list and accepted by a block
+// list, so an allow list fails closed
+type Restriction struct {
+	Allowed []string
+	Blocked []string
+}
+
+// IsZero reports if the restriction lets every country in
+func (r Restriction) IsZero() bool {
+	return len(r.Allowed) == 0 && len(r.Blocked) == 0
+}
+
+// Permits reports if the clients of country, "" when unknown, are accepted
+func (r Restriction) Permits(country string) bool {
+	if country != "" && slices.Contains(r.Blocked, country) {
+		return false
+	}
+	if len(r.Allowed) == 0 {
+		return true
+	}
+	return country != "" && slices.Contains(r.Allowed, country)
+}
+
+// Check resolves the country of ip with resolver and returns ErrRestricted if it's not permitted.
+// a nil resolver resolves every IP as unknown, as well as an empty ip (bids not made over a connection)
+func (r Restriction) Check(ctx context.Context, resolver Resolver, ip string) error {
+	if r.IsZero() {
+		return nil
+	}
+	country := ""
+	if resolver != nil && ip != "" {
+		var err error
+		if country, err = resolver.Country(ctx, ip); err != nil {
+			return fmt.Errorf("failed to resolve the country of %s: %w", ip, err)
+		}
+	}
+	if !r.Permits(country) {
+		return fmt.Errorf("%w (%s)", ErrRestricted, cmp.Or(country, "unknown"))
+	}
+	return nil
+}
+
+// NormalizeCountries upper cases and dedupes the codes, it fails on a code that is not two letters
+func NormalizeCountries(codes []string) ([]string, error) {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCountry, code)
+		}
+		if !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+// RangeResolver resolves the countries from an in-memory list of networks
+type RangeResolver struct {
+	// ranges are sorted by first address, they don't overlap
+	ranges []ipRange
+}
+
+type ipRange struct {
+	first, last netip.Addr
+	country     string
+}
+
+// LoadFile reads a RangeResolver from the CSV file at path, see ParseRanges
+func LoadFile(path string) (*RangeResolver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: %w", err)
+	}
+	defer f.Close()
+	return ParseRanges(f)
+}
+
+// ParseRanges reads a RangeResolver from lines "network,country" where network is a CIDR prefix, e.g.
+// 81.2.69.0/24,GB. the blank lines, the # comments and a header line are skipped. the networks of the
+// GeoIP exports don't overlap, a network nested in another one is ignored
+func ParseRanges(r io.Reader) (*RangeResolver, error) {
+	var ranges []ipRange
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		network, country, ok := strings.Cut(line, ",")
+		if !ok {
+			return nil, fmt.Errorf("geoip: line %d: want network,country", n)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			if n == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("geoip: line %d: %w", n, err)
+		}
+		codes, err := NormalizeCountries([]string{country})
+		if err != nil {
+			return nil, fmt.Errorf("geoip: line %d: %w", n, err)
+		}
+		prefix = prefix.Masked()
+		ranges = append(ranges, ipRange{first: prefix.Addr(), last: lastAddr(prefix), country: codes[0]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("geoip: %w", err)
+	}
+	slices.SortStableFunc(ranges, func(a, b ipRange) int { return a.first.Compare(b.first) })
+	// the nested ranges are dropped so the lookup can binary search
+	kept := ranges[:0]
+	for _, rg := range ranges {
+		if len(kept) > 0 && rg.first.Compare(kept[len(kept)-1].last) <= 0 {
+			continue
+		}
+		kept = append(kept, rg)
+	}
+	return &RangeResolver{ranges: kept}, nil
+}
+
+// Country implements Resolver
+func (r *RangeResolver) Country(_ context.Context, ip string) (string, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", fmt.Errorf("geoip: %w", err)
+	}
+	addr = addr.Unmap()
+	i, _ := slices.BinarySearchFunc(r.ranges, addr, func(rg ipRange, addr netip.Addr) int {
+		return rg.first.Compare(addr)
+	})
+	// i is the first range starting after addr, unless one starts at it
+	if i < len(r.ranges) && r.ranges[i].first == addr {
+		return r.ranges[i].country, nil
+	}
+	if i > 0 && addr.Compare(r.ranges[i-1].last) <= 0 {
+		return r.ranges[i-1].country, nil
+	}
+	return "", nil
+}
+
+// Len returns the number of networks of the resolver
+func (r *RangeResolver) Len() int {
+	return len(r.ranges)
+}
+
+// lastAddr returns the last address of the masked prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 0x80 >> (bit % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
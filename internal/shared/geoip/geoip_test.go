@@ -0,0 +1,79 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testRanges = `network,country_iso_code
+# test networks
+81.2.69.0/24,gb
+81.2.69.128/25,FR
+2.125.160.0/19,US
+2001:db8::/32,DE
+`
+
+func TestRangeResolverCountry(t *testing.T) {
+	r, err := ParseRanges(strings.NewReader(testRanges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 3 {
+		t.Fatalf("resolver has %d networks, want 3 without the nested one", r.Len())
+	}
+	tests := map[string]string{
+		"81.2.69.0":          "GB",
+		"81.2.69.200":        "GB",
+		"81.2.70.1":          "",
+		"2.125.191.255":      "US",
+		"::ffff:81.2.69.142": "GB",
+		"2001:db8::1":        "DE",
+		"10.0.0.1":           "",
+	}
+	for ip, want := range tests {
+		got, err := r.Country(context.Background(), ip)
+		if err != nil || got != want {
+			t.Errorf("Country(%s) = %q, %v, want %q", ip, got, err, want)
+		}
+	}
+	if _, err := r.Country(context.Background(), "not an ip"); err == nil {
+		t.Error("Country of an invalid IP didn't fail")
+	}
+}
+
+func TestRestrictionCheck(t *testing.T) {
+	r, _ := ParseRanges(strings.NewReader(testRanges))
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		restriction Restriction
+		ip          string
+		allowed     bool
+	}{
+		{"no lists", Restriction{}, "10.0.0.1", true},
+		{"allowed country", Restriction{Allowed: []string{"GB"}}, "81.2.69.1", true},
+		{"other country", Restriction{Allowed: []string{"GB"}}, "2.125.160.1", false},
+		{"unknown country with an allow list", Restriction{Allowed: []string{"GB"}}, "10.0.0.1", false},
+		{"blocked country", Restriction{Blocked: []string{"US"}}, "2.125.160.1", false},
+		{"unknown country with a block list", Restriction{Blocked: []string{"US"}}, "10.0.0.1", true},
+		{"blocked wins over allowed", Restriction{Allowed: []string{"GB"}, Blocked: []string{"GB"}}, "81.2.69.1", false},
+	}
+	for _, tt := range tests {
+		err := tt.restriction.Check(ctx, r, tt.ip)
+		if tt.allowed && err != nil || !tt.allowed && !errors.Is(err, ErrRestricted) {
+			t.Errorf("%s: Check(%s) = %v, want allowed %v", tt.name, tt.ip, err, tt.allowed)
+		}
+	}
+}
+
+func TestNormalizeCountries(t *testing.T) {
+	codes, err := NormalizeCountries([]string{"us", " GB", "US"})
+	if err != nil || strings.Join(codes, ",") != "GB,US" {
+		t.Fatalf("NormalizeCountries = %v, %v, want GB,US", codes, err)
+	}
+	if _, err := NormalizeCountries([]string{"USA"}); !errors.Is(err, ErrInvalidCountry) {
+		t.Fatalf("NormalizeCountries(USA) = %v, want ErrInvalidCountry", err)
+	}
+}
@@ -0,0 +1,156 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/gofiber/fiber/v2"
+	fws "github.com/gofiber/websocket/v2" // Alias to avoid name conflicts
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequireRoles returns a middleware authenticating REST requests with an "Authorization: Bearer <token>"
+// or an "X-API-Key: <key>" header, callers whose role is not in roles are rejected, no roles accepts any authenticated caller.
+// the verified claims are available to handlers through ClaimsFrom, and the request context is scoped
+// to the caller organization
+func (s *Server) RequireRoles(roles ...auth.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := s.authenticate(c)
+		if err != nil {
+			return err
+		}
+		if len(roles) > 0 && !slices.Contains(roles, claims.Role) {
+			return fiber.NewError(fiber.StatusForbidden, "insufficient role")
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermission returns a middleware authenticating REST requests as RequireRoles, callers whose
+// role is not granted p by the permissions table are rejected
+func (s *Server) RequirePermission(p auth.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := s.authenticate(c)
+		if err != nil {
+			return err
+		}
+		if !claims.Can(p) {
+			return fiber.NewError(fiber.StatusForbidden, "insufficient permissions")
+		}
+		return c.Next()
+	}
+}
+
+// RequireLotPermission returns a middleware as RequirePermission for the routes of the lot in the param
+// route param, the roles limited to their assigned lots (auctioneers) must be assigned to it
+func (s *Server) RequireLotPermission(p auth.Permission, param string) fiber.Handler {
+	check := s.lotPermission(p, param)
+	return func(c *fiber.Ctx) error {
+		if _, err := s.authenticate(c); err != nil {
+			return err
+		}
+		return check(c)
+	}
+}
+
+// lotPermission returns a middleware checking p on the lot in the param route param for the claims
+// already verified, by authenticate or by the /ws middleware
+func (s *Server) lotPermission(p auth.Permission, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals(localsClaims).(*auth.Claims)
+		if !ok || !claims.Can(p) {
+			return fiber.NewError(fiber.StatusForbidden, "insufficient permissions")
+		}
+		if !claims.Role.LotAssigned() {
+			return c.Next()
+		}
+		lotID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+		}
+		assigned := false
+		if s.assigned != nil {
+			if assigned, err = s.assigned(c.UserContext(), lotID, claims.UserID); err != nil {
+				logger.FromContext(c.UserContext()).Error("Lot permission check failed", zap.Error(err))
+				return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+			}
+		}
+		if !assigned {
+			return fiber.NewError(fiber.StatusForbidden, "not assigned to the lot")
+		}
+		return c.Next()
+	}
+}
+
+// authenticate verifies the API key or the bearer token of the request, storing the claims in the
+// locals and scoping the request context to the caller organization
+func (s *Server) authenticate(c *fiber.Ctx) (*auth.Claims, error) {
+	var claims *auth.Claims
+	var err error
+	if key := c.Get(HeaderAPIKey); key != "" {
+		claims, err = s.authenticateAPIKey(c, strings.TrimSpace(key))
+	} else {
+		token, _ := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		claims, err = s.tokens.Verify(strings.TrimSpace(token))
+	}
+	if err != nil {
+		logger.FromContext(c.UserContext()).Warn("HTTP request rejected: authentication failed",
+			zap.String("path", c.Path()),
+			zap.String("remote_addr", c.IP()),
+			zap.Error(err),
+		)
+		if errors.Is(err, errAPIKeysUnavailable) {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal error")
+		}
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid or missing token")
+	}
+	c.Locals(localsClaims, claims)
+	// the org owned data read or changed by the request is scoped to the caller organization
+	c.SetUserContext(tenant.WithOrgID(c.UserContext(), claims.OrgID))
+	return claims, nil
+}
+
+// errAPIKeysUnavailable is returned by authenticateAPIKey when the key could not be checked
+var errAPIKeysUnavailable = errors.New("API keys unavailable")
+
+func (s *Server) authenticateAPIKey(c *fiber.Ctx, key string) (*auth.Claims, error) {
+	if s.apiKeys == nil {
+		return nil, fmt.Errorf("%w: API keys are not enabled", auth.ErrInvalidToken)
+	}
+	claims, err := s.apiKeys(c.UserContext(), key)
+	if err != nil && !errors.Is(err, auth.ErrInvalidToken) {
+		return nil, fmt.Errorf("%w: %w", errAPIKeysUnavailable, err)
+	}
+	return claims, err
+}
+
+// OptionalAuth returns a middleware for public routes that tailor their response to the caller,
+// requests without Authorization nor X-API-Key header pass through unauthenticated, invalid tokens
+// are rejected
+func (s *Server) OptionalAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) == "" && c.Get(HeaderAPIKey) == "" {
+			return c.Next()
+		}
+		return s.RequireRoles()(c)
+	}
+}
+
+// ClaimsFrom returns the claims verified by RequireRoles, nil on unauthenticated routes
+func ClaimsFrom(c *fiber.Ctx) *auth.Claims {
+	claims, _ := c.Locals(localsClaims).(*auth.Claims)
+	return claims
+}
+
+// ConnClaims returns the claims of a WS connection verified before the upgrade, anonymous
+// spectators get spectator claims without user
+func ConnClaims(c *fws.Conn) *auth.Claims {
+	claims, _ := c.Locals(localsClaims).(*auth.Claims)
+	return claims
+}
@@ -0,0 +1,23 @@
+package httpserver
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// handleLotConnections returns the demographics of the live WS connections of the lot on this instance
+func (s *Server) handleLotConnections(c *fiber.Ctx) error {
+	lotID, err := uuid.Parse(c.Params("lotid"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid lot ID")
+	}
+	ctx, cancel := context.WithTimeout(c.UserContext(), hubStatsTimeout)
+	defer cancel()
+	demographics, err := s.hub.LotDemographics(ctx, lotID.String())
+	if err != nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+	return c.JSON(demographics)
+}
@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// HeaderRequestID carries the correlation ID of a request, a client value is kept so calls can be
+// traced across services, it's always echoed in the response
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds the accepted client request IDs
+const maxRequestIDLength = 128
+
+// errorResponse is the JSON body of every failed request, RequestID is the support lookup key
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// correlationMiddleware stores the request correlation ID in the user context, where use cases and
+// repositories get it from, and logs the request with it
+func correlationMiddleware(c *fiber.Ctx) error {
+	id := strings.Clone(c.Get(HeaderRequestID))
+	if id == "" || len(id) > maxRequestIDLength {
+		id = logger.NewCorrelationID()
+	}
+	c.Set(HeaderRequestID, id)
+	c.SetUserContext(logger.WithCorrelationID(c.UserContext(), id))
+
+	logger.FromContext(c.UserContext()).Info("HTTP request",
+		zap.String("method", c.Method()),
+		zap.String("path", c.Path()),
+		zap.String("remote_addr", c.IP()),
+	)
+	return c.Next()
+}
+
+// errorHandler returns errors as JSON with the request correlation ID
+func errorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code = fiberErr.Code
+	}
+	return c.Status(code).JSON(errorResponse{
+		Error:     err.Error(),
+		RequestID: logger.CorrelationID(c.UserContext()),
+	})
+}
@@ -0,0 +1,52 @@
+package httpserver
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// hubStatsTimeout bounds the wait for the hub shards to answer a diagnostics snapshot
+const hubStatsTimeout = 2 * time.Second
+
+// DebugHubResponse is the JSON body of GET /debug/hub
+type DebugHubResponse struct {
+	Goroutines int                 `json:"goroutines"`
+	HeapAlloc  uint64              `json:"heap_alloc_bytes"`
+	HeapInuse  uint64              `json:"heap_inuse_bytes"`
+	NumGC      uint32              `json:"num_gc"`
+	Hub        *websocket.HubStats `json:"hub"`
+	Time       time.Time           `json:"time"`
+}
+
+// AddDebugStats registers GET /debug/<name> returning stats as JSON, for the counters of the modules.
+// like every /debug route it's restricted to the platform operators
+func (s *Server) AddDebugStats(name string, stats func() any) {
+	s.debug.Get("/"+name, func(c *fiber.Ctx) error {
+		return c.JSON(stats())
+	})
+}
+
+// handleDebugHub returns the runtime and hub internals, to diagnose leaks under sustained load
+func (s *Server) handleDebugHub(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), hubStatsTimeout)
+	defer cancel()
+	stats, err := s.hub.Stats(ctx)
+	if err != nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return c.JSON(DebugHubResponse{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapInuse:  mem.HeapInuse,
+		NumGC:      mem.NumGC,
+		Hub:        stats,
+		Time:       time.Now(),
+	})
+}
@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// checkTimeout bounds how long a single dependency check can take during a probe
+const checkTimeout = 2 * time.Second
+
+// HealthCheck verifies a single dependency, a nil error means the dependency is healthy
+type HealthCheck func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// CheckResult is the outcome of a single dependency check
+type CheckResult struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthResponse is the JSON body returned by /healthz and /readyz
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+	Time   time.Time              `json:"time"`
+}
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+// AddLivenessCheck registers a check executed by /healthz (and /readyz).
+// Liveness checks must only cover in-process components, a failure makes the orchestrator restart the pod
+func (s *Server) AddLivenessCheck(name string, check HealthCheck) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.livenessChecks = append(s.livenessChecks, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck registers a check executed by /readyz, used for external dependencies (DB, migrations)
+func (s *Server) AddReadinessCheck(name string, check HealthCheck) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.readinessChecks = append(s.readinessChecks, namedCheck{name: name, check: check})
+}
+
+func (s *Server) handleLiveness(c *fiber.Ctx) error {
+	s.checksMu.RLock()
+	checks := append([]namedCheck{}, s.livenessChecks...)
+	s.checksMu.RUnlock()
+	return writeHealth(c, runChecks(c.UserContext(), checks))
+}
+
+func (s *Server) handleReadiness(c *fiber.Ctx) error {
+	s.checksMu.RLock()
+	checks := append(append([]namedCheck{}, s.livenessChecks...), s.readinessChecks...)
+	s.checksMu.RUnlock()
+	return writeHealth(c, runChecks(c.UserContext(), checks))
+}
+
+// runChecks executes all checks concurrently, each one bounded by checkTimeout
+func runChecks(ctx context.Context, checks []namedCheck) HealthResponse {
+	resp := HealthResponse{
+		Status: statusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+		Time:   time.Now().UTC(),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(checkCtx)
+			result := CheckResult{Status: statusOK, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = statusFail
+				result.Error = err.Error()
+				log.Warn("Health check failed", zap.String("check", nc.name), zap.Error(err))
+			}
+
+			mu.Lock()
+			resp.Checks[nc.name] = result
+			if err != nil {
+				resp.Status = statusFail
+			}
+			mu.Unlock()
+		}(nc)
+	}
+	wg.Wait()
+	return resp
+}
+
+func writeHealth(c *fiber.Ctx, resp HealthResponse) error {
+	if resp.Status != statusOK {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,64 @@
+package httpserver
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelsResponse is the JSON body of GET /admin/log-level
+type LogLevelsResponse struct {
+	Default string            `json:"default"`
+	Modules map[string]string `json:"modules"`
+}
+
+// setLogLevelRequest is the JSON body of PUT /admin/log-level, an empty module changes the default level
+// and an empty level removes the module override
+type setLogLevelRequest struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+
+func (s *Server) handleGetLogLevels(c *fiber.Ctx) error {
+	return c.JSON(currentLogLevels())
+}
+
+// handleSetLogLevel changes a log level at runtime, without restart
+func (s *Server) handleSetLogLevel(c *fiber.Ctx) error {
+	var req setLogLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	levels := logger.GetLevels()
+	if req.Level == "" {
+		if req.Module == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "level is required for the default level")
+		}
+		levels.ResetModule(req.Module)
+	} else {
+		level, err := zapcore.ParseLevel(req.Level)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if req.Module == "" {
+			levels.SetDefault(level)
+		} else {
+			levels.SetModule(req.Module, level)
+		}
+	}
+	logger.FromContext(c.UserContext()).Info("Log level changed",
+		zap.String("module", req.Module),
+		zap.String("level", req.Level),
+	)
+	return c.JSON(currentLogLevels())
+}
+
+func currentLogLevels() LogLevelsResponse {
+	levels := logger.GetLevels()
+	resp := LogLevelsResponse{Default: levels.Default().String(), Modules: map[string]string{}}
+	for module, level := range levels.Modules() {
+		resp.Modules[module] = level.String()
+	}
+	return resp
+}
@@ -0,0 +1,18 @@
+package httpserver
+
+import (
+	"github.com/cristianortiz/auctionEngine/internal/shared/db/migrations"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// handleGetMigrations returns the schema version, its dirty flag and the embedded migrations not applied yet
+func (s *Server) handleGetMigrations(c *fiber.Ctx) error {
+	info, err := migrations.GetInfo()
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("Failed to read migration status", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to read migration status")
+	}
+	return c.JSON(info)
+}
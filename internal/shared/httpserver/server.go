@@ -1,57 +1,322 @@
 package httpserver
 
 import (
+	"cmp"
 	"context"
-	"os"
-	"os/signal"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cristianortiz/auctionEngine/api/openapi"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/geoip"
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
 	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/cristianortiz/auctionEngine/web/demo"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
 	fws "github.com/gofiber/websocket/v2" // Alias to avoid name conflicts
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Config holds the HTTP/WS server settings
+type Config struct {
+	// AllowedOrigins for WS upgrades, empty or "*" allows any origin
+	AllowedOrigins []string
+	// Tokens verifies the connection token required on WS upgrades
+	Tokens *auth.TokenService
+	// AllowAnonymousSpectators accepts token-less upgrades as read-only spectators
+	AllowAnonymousSpectators bool
+	// LotAccess reports if the lot is visible in ctx, scoped to the organization of the caller,
+	// lot upgrades are rejected with 404 otherwise. nil accepts every lot
+	LotAccess func(ctx context.Context, lotID uuid.UUID) (bool, error)
+	// GeoAccess checks the country of the client IP against the country lists of the lot, the upgrades
+	// it rejects with geoip.ErrRestricted get 451 and the GEO_RESTRICTED code. nil accepts every country
+	GeoAccess func(ctx context.Context, lotID uuid.UUID, clientIP string) error
+	// AuctioneerAssigned reports if the user is assigned to run the lot, the lot permissions of the
+	// roles limited to their assigned lots (auctioneers) are denied when it's nil
+	AuctioneerAssigned func(ctx context.Context, lotID, userID uuid.UUID) (bool, error)
+	// APIKeys authenticates the X-API-Key header of REST requests, accepted as an alternative to
+	// the bearer tokens. nil rejects the requests sending it
+	APIKeys func(ctx context.Context, key string) (*auth.Claims, error)
+	// TLS serves HTTPS and wss natively, without a TLS terminating proxy
+	TLS TLSConfig
+	// RequireSecure rejects WS upgrades not made over TLS (wss), for production
+	RequireSecure bool
+	// HSTSMaxAge of the Strict-Transport-Security header of HTTPS responses, 0 disables it
+	HSTSMaxAge time.Duration
+	// DrainTimeout bounds the shutdown hooks run on SIGTERM, ShutdownTimeout the in-flight HTTP requests
+	// after them. zero values use the defaults
+	DrainTimeout    time.Duration
+	ShutdownTimeout time.Duration
+	// RetryAfter is the wait suggested to the clients rejected while draining
+	RetryAfter time.Duration
+	// DemoPage serves the demo auction room at /demo/:lotid, for manual QA and demos
+	DemoPage bool
+	// WSCompression negotiates permessage-deflate on the lot WS upgrades offering it, the msgs are
+	// compressed by the hub, see websocket.CompressionConfig
+	WSCompression bool
+	// Connections records the WS connections, nil for none
+	Connections websocket.ConnectionLog
+	// ConnectionTokenTTL is how long a client can reconnect keeping its client ID, see
+	// wsproto.QueryConnectionToken. zero uses the default
+	ConnectionTokenTTL time.Duration
+}
+
+// defaultConnectionTokenTTL is the validity of the connection tokens when not configured
+const defaultConnectionTokenTTL = time.Hour
+
+// connectionLogTimeout bounds the recording of a connection, the connection goes on without it
+const connectionLogTimeout = 2 * time.Second
+
+// HeaderAPIKey carries the API key of the machine clients
+const HeaderAPIKey = "X-API-Key"
+
+// wsTokenCookie is the cookie name accepted as an alternative to the ?token= query param
+const wsTokenCookie = "auction_token"
+
+// localsClaims is the fiber Locals key where authenticated claims are stored
+const localsClaims = "claims"
+
+// localsQuery is the fiber Locals key where the upgrade query params are stored
+const localsQuery = "query"
+
+// localsRemoteIP is the fiber Locals key where the upgrade remote IP is stored
+const localsRemoteIP = "remoteIP"
+
+// localsUserAgent is the fiber Locals key where the upgrade User-Agent is stored
+const localsUserAgent = "userAgent"
+
 type Server struct {
 	app *fiber.App
+	api fiber.Router   // /api group where modules register their REST routes
 	hub *websocket.Hub // wbs hub reference
 	ctx context.Context
+	// debug is the /debug group of the platform operators, see AddDebugStats
+	debug fiber.Router
+	// cancelConns cancels ctx, the parent of the context of every WS connection, on Shutdown
+	cancelConns context.CancelFunc
+	// tokens verifies the bearer tokens of authenticated REST routes
+	tokens *auth.TokenService
+	// assigned reports the lot assignments of the auctioneers, see Config.AuctioneerAssigned
+	assigned func(ctx context.Context, lotID, userID uuid.UUID) (bool, error)
+	// apiKeys authenticates the API keys, see Config.APIKeys
+	apiKeys func(ctx context.Context, key string) (*auth.Claims, error)
+	// connections records the WS connections, connectionTokenTTL is the validity of their tokens
+	connections        websocket.ConnectionLog
+	connectionTokenTTL time.Duration
+	// tls configures the listener of Start, see Config.TLS
+	tls TLSConfig
+	// redirect serves the plain HTTP redirects to HTTPS when TLS is enabled
+	redirect *http.Server
+	// graceful shutdown, see Drain
+	draining        atomic.Bool
+	drainTimeout    time.Duration
+	shutdownTimeout time.Duration
+	retryAfter      time.Duration
+	hooksMu         sync.Mutex
+	shutdownHooks   []namedHook
+
+	checksMu        sync.RWMutex
+	livenessChecks  []namedCheck
+	readinessChecks []namedCheck
 }
 
 var log = logger.GetLogger() // logger instance
 // NewServer creates a new server instance, receiving wbs hub
-func NewServer(addr string, hub *websocket.Hub, ctx context.Context) *Server {
-	app := fiber.New()
-
-	// Middleware for logging
-	app.Use(func(c *fiber.Ctx) error {
-		log.Info("HTTP request",
-			zap.String("method", c.Method()),
-			zap.String("path", c.Path()),
-			zap.String("remote_addr", c.IP()),
-		)
-		return c.Next()
+func NewServer(addr string, hub *websocket.Hub, ctx context.Context, cfg Config) *Server {
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler})
+
+	// Middleware for correlation IDs and logging
+	app.Use(correlationMiddleware)
+	// the WS connections outlive their HTTP requests, their contexts derive from the server one so
+	// Shutdown ends them even if the parent ctx is not done yet
+	ctx, cancelConns := context.WithCancel(ctx)
+	// browser security headers, HSTS on HTTPS responses only
+	app.Use(secureHeaders(cfg.HSTSMaxAge))
+
+	srv := &Server{
+		app: app,
+		api: app.Group("/api"),
+		hub: hub,
+		ctx: ctx,
+
+		cancelConns: cancelConns,
+
+		tokens:   cfg.Tokens,
+		assigned: cfg.AuctioneerAssigned,
+		apiKeys:  cfg.APIKeys,
+		tls:      cfg.TLS,
+
+		connections:        cfg.Connections,
+		connectionTokenTTL: cmp.Or(cfg.ConnectionTokenTTL, defaultConnectionTokenTTL),
+
+		drainTimeout:    cmp.Or(cfg.DrainTimeout, defaultDrainTimeout),
+		shutdownTimeout: cmp.Or(cfg.ShutdownTimeout, defaultShutdownTimeout),
+		retryAfter:      cfg.RetryAfter,
+	}
+
+	// kubernetes probes: liveness only covers in-process components, readiness adds external dependencies
+	app.Get("/healthz", srv.handleLiveness)
+	app.Get("/readyz", srv.handleReadiness)
+	srv.AddLivenessCheck("websocket_hub", hub.Alive)
+	srv.AddReadinessCheck("draining", func(ctx context.Context) error {
+		if srv.Draining() {
+			return errDraining
+		}
+		return nil
 	})
 
-	// health check EP
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.SendString("OK, Welcome to AuctionEngine Project")
+	// runtime log level control, for debugging a module in production without restart
+	srv.api.Get("/admin/log-level", srv.RequirePermission(auth.PermOperatePlatform), srv.handleGetLogLevels)
+	srv.api.Put("/admin/log-level", srv.RequirePermission(auth.PermOperatePlatform), srv.handleSetLogLevel)
+	// schema version and dirty state, to check a deploy without a DB shell
+	srv.api.Get("/admin/migrations", srv.RequirePermission(auth.PermOperatePlatform), srv.handleGetMigrations)
+	// devices, protocols and networks of the connections of a lot, they show the bidder IPs
+	srv.api.Get("/lots/:lotid/connections", srv.RequirePermission(auth.PermViewBidders), srv.lotAccess(cfg.LotAccess), srv.handleLotConnections)
+	// REST API spec, pkg/client follows it
+	srv.api.Get("/openapi.yaml", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "application/yaml")
+		return c.Send(openapi.Spec)
 	})
 
+	// single page auction room on the lot WS endpoint, its origin must be allowed on the upgrades
+	if cfg.DemoPage {
+		app.Get("/demo/:lotid", func(c *fiber.Ctx) error {
+			if _, err := uuid.Parse(c.Params("lotid")); err != nil {
+				return fiber.NewError(fiber.StatusNotFound, "lot not found")
+			}
+			c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+			return c.Send(demo.Page)
+		})
+	}
+
+	// diagnostics: net/http/pprof profiles under /debug/pprof and hub internals, platform operators only
+	srv.debug = app.Group("/debug", srv.RequirePermission(auth.PermOperatePlatform))
+	srv.debug.Get("/hub", srv.handleDebugHub)
+	app.Use(pprof.New())
+
 	//fiber requires the WBS base route, like  /ws, has to managed by a middleware
+	// origin and token are validated here, so unauthenticated upgrades are rejected before a Client exists
 	app.Use("/ws", func(c *fiber.Ctx) error {
 		//returns true if the request is a WBS upgrade
-		if fws.IsWebSocketUpgrade(c) {
+		if !fws.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		// a draining server sends its clients to the other instances
+		if srv.Draining() {
+			return srv.rejectDraining(c)
+		}
+		if cfg.RequireSecure && !c.Secure() {
+			return fiber.NewError(fiber.StatusForbidden, "secure WebSocket (wss) required")
+		}
+		if origin := c.Get(fiber.HeaderOrigin); origin != "" && !originAllowed(cfg.AllowedOrigins, origin) {
+			logger.FromContext(c.UserContext()).Warn("WebSocket upgrade rejected: origin not allowed",
+				zap.String("origin", origin),
+				zap.String("remote_addr", c.IP()),
+			)
+			return fiber.NewError(fiber.StatusForbidden, "origin not allowed")
+		}
+		c.Locals(localsQuery, copyQuery(c.Queries()))
+		c.Locals(localsRemoteIP, strings.Clone(c.IP()))
+		c.Locals(localsUserAgent, strings.Clone(c.Get(fiber.HeaderUserAgent)))
+		token := c.Query("token")
+		if token == "" {
+			token = c.Cookies(wsTokenCookie)
+		}
+		if token == "" && cfg.AllowAnonymousSpectators {
 			c.Locals("allowed", true)
+			c.Locals(localsClaims, &auth.Claims{Role: auth.RoleSpectator})
 			return c.Next()
 		}
-		return fiber.ErrUpgradeRequired
+		claims, err := cfg.Tokens.Verify(token)
+		if err != nil {
+			logger.FromContext(c.UserContext()).Warn("WebSocket upgrade rejected: authentication failed",
+				zap.String("remote_addr", c.IP()),
+				zap.Error(err),
+			)
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid or missing token")
+		}
+		c.Locals("allowed", true)
+		c.Locals(localsClaims, claims)
+		c.SetUserContext(tenant.WithOrgID(c.UserContext(), claims.OrgID))
+		return c.Next()
 	})
 
+	// the lot connections offering the versioned subprotocol get it, the others speak it without negotiation
+	wsConfig := fws.Config{EnableCompression: cfg.WSCompression, Subprotocols: []string{wsproto.Subprotocol}}
 	//defines the specific route for auction by lotID
-	app.Get("/ws/auction/:lotid", fws.New(func(c *fws.Conn) {
+	app.Get("/ws/auction/:lotid", srv.lotAccess(cfg.LotAccess), srv.geoAccess(cfg.GeoAccess), fws.New(srv.lotConnHandler(ctx, hub, func(claims *auth.Claims) websocket.ClientRole {
+		if claims.Can(auth.PermPlaceBids) {
+			return websocket.RoleBidder
+		}
+		return websocket.RoleSpectator
+	}), wsConfig))
+	// auctioneer connections of a lot, they receive the lot updates and control the lot live. the staff
+	// allowed to run the lot is checked on the claims verified by the /ws middleware
+	app.Get("/ws/admin/auction/:lotid", srv.lotAccess(cfg.LotAccess), srv.lotPermission(auth.PermRunLots, "lotid"),
+		fws.New(srv.lotConnHandler(ctx, hub, func(*auth.Claims) websocket.ClientRole {
+			return websocket.RoleAuctioneer
+		}), wsConfig))
+
+	return srv
+}
+
+// lotAccess returns a middleware rejecting the upgrades to lots of other organizations, so their
+// updates are never broadcast to the connection. an invalid lot ID is left to the connection handler
+func (s *Server) lotAccess(access func(ctx context.Context, lotID uuid.UUID) (bool, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lotID, err := uuid.Parse(c.Params("lotid"))
+		if access == nil || err != nil {
+			return c.Next()
+		}
+		ok, err := access(c.UserContext(), lotID)
+		if err != nil {
+			logger.FromContext(c.UserContext()).Error("WebSocket upgrade failed: lot access check", zap.Error(err))
+			return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+		}
+		if !ok {
+			return fiber.NewError(fiber.StatusNotFound, "lot not found")
+		}
+		return c.Next()
+	}
+}
+
+// geoAccess returns a middleware rejecting the upgrades to a lot from the countries it doesn't allow, the
+// lobby and invalid lot IDs are left to the connection handler
+func (s *Server) geoAccess(access func(ctx context.Context, lotID uuid.UUID, clientIP string) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lotID, err := uuid.Parse(c.Params("lotid"))
+		if access == nil || err != nil {
+			return c.Next()
+		}
+		err = access(c.UserContext(), lotID, c.IP())
+		switch {
+		case err == nil:
+			return c.Next()
+		case errors.Is(err, geoip.ErrRestricted):
+			return fiber.NewError(fiber.StatusUnavailableForLegalReasons, geoip.ErrorCodeGeoRestricted+": "+err.Error())
+		default:
+			logger.FromContext(c.UserContext()).Error("WebSocket upgrade failed: country check", zap.Error(err))
+			return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+		}
+	}
+}
+
+// lotConnHandler returns the handler of the WS connections of a lot, registering each one in the hub
+// with the role given by roleOf for its claims
+func (s *Server) lotConnHandler(ctx context.Context, hub *websocket.Hub, roleOf func(*auth.Claims) websocket.ClientRole) func(*fws.Conn) {
+	return func(c *fws.Conn) {
 		//extract lotid parameters from url
 		lotID := c.Params("lotid")
 		if lotID == "" {
@@ -60,51 +325,219 @@ func NewServer(addr string, hub *websocket.Hub, ctx context.Context) *Server {
 			return
 		}
 
-		// temporal userId for testing purposes
-		userID := uuid.NewString()
-		log.Info("New WebSocket connection attempt", zap.String("lotID", lotID), zap.String("remote_addr", c.RemoteAddr().String()))
+		// claims were verified by the /ws middleware before the upgrade
+		claims, ok := c.Locals(localsClaims).(*auth.Claims)
+		if !ok {
+			log.Error("webSocket connection without authenticated claims")
+			c.Close()
+			return
+		}
+		log.Info("New WebSocket connection attempt",
+			zap.String("lotID", lotID),
+			zap.String("userID", claims.UserID.String()),
+			zap.String("role", string(claims.Role)),
+			zap.String("remote_addr", c.RemoteAddr().String()),
+		)
+
+		userID := ""
+		if claims.UserID != uuid.Nil {
+			userID = claims.UserID.String()
+		}
+
+		orgID := ""
+		if claims.OrgID != uuid.Nil {
+			orgID = claims.OrgID.String()
+		}
+
+		// the lobby connections join the lobby room of their organization, so its feed only lists their lots
+		if lotID == wsproto.LobbyRoom {
+			lotID = websocket.LobbyRoom(orgID)
+		}
+
+		sessionID := ""
+		if claims.SessionID != uuid.Nil {
+			sessionID = claims.SessionID.String()
+		}
 
 		//creates a new client instance
 		client := &websocket.Client{
-			Hub:   hub, //assigns the hub reference received by the server
-			Conn:  c,
-			Send:  make(chan []byte, 256),
-			LotID: lotID,
-			ID:    userID,
+			Hub:         hub, //assigns the hub reference received by the server
+			Conn:        c,
+			Send:        make(chan []byte, 256),
+			LotID:       lotID,
+			UserID:      userID,
+			OrgID:       orgID,
+			SessionID:   sessionID,
+			Role:        roleOf(claims),
+			ConnectedAt: time.Now(),
+		}
+		client.Query, _ = c.Locals(localsQuery).(map[string]string)
+		client.RemoteIP, _ = c.Locals(localsRemoteIP).(string)
+		client.UserAgent, _ = c.Locals(localsUserAgent).(string)
+		client.Protocol = c.Subprotocol()
+		s.identify(client, claims.UserID)
+
+		// the pumps share the context of the connection: the end of either one, or the server shutdown, ends both
+		connCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		if s.connections != nil {
+			s.recordConnection(ctx, client, func(ctx context.Context) error { return s.connections.Opened(ctx, client) })
+			defer s.recordConnection(ctx, client, func(ctx context.Context) error {
+				return s.connections.Closed(ctx, client, time.Now())
+			})
 		}
 
 		//register the client in the hub
 		hub.RegisterClient(client)
 		// starts the goroutines to write and red client messages
-		go client.WritePump(ctx)
-		client.ReadPump(ctx) //ReadPump blocks, its execute int handler goroutine
+		go func() {
+			defer cancel()
+			client.WritePump(connCtx)
+		}()
+		client.ReadPump(connCtx) //ReadPump blocks, its execute int handler goroutine
 		//ReadPump exits when connections closes or there ir an error
 		//defer function in ReadPump,takes care of unregister and close the connection
+	}
+}
 
-	}))
+// identify assigns the client ID of the connection of userID: the one of its connection token when the
+// client reconnects with a valid one, a new one otherwise. the client is greeted with its ID and the
+// token to present on its next reconnection
+func (s *Server) identify(client *websocket.Client, userID uuid.UUID) {
+	client.ID = uuid.NewString()
+	if token := client.Query[wsproto.QueryConnectionToken]; token != "" && s.tokens != nil {
+		clientID, err := s.tokens.VerifyConnection(token, userID, client.LotID)
+		if err == nil {
+			client.ID, client.Resumed = clientID, true
+		} else {
+			// the client goes on with a new identity, e.g. after its token expired
+			log.Debug("WebSocket connection token rejected", zap.String("lotID", client.LotID), zap.Error(err))
+		}
+	}
+	if s.tokens == nil {
+		return
+	}
+	token, err := s.tokens.IssueConnection(client.ID, userID, client.LotID, s.connectionTokenTTL)
+	if err != nil {
+		log.Error("failed to issue WebSocket connection token", zap.String("clientID", client.ID), zap.Error(err))
+		return
+	}
+	msg := wsproto.ServerConnectedMessage{BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeServerConnected}}
+	msg.Payload.ClientID = client.ID
+	msg.Payload.ConnectionToken = token
+	msg.Payload.Resumed = client.Resumed
+	client.Greeting, _ = json.Marshal(msg)
+}
 
-	srv := &Server{
-		app: app,
-		hub: hub,
-		ctx: ctx,
+// recordConnection runs record on the connection log, its failure is logged and the connection goes on
+func (s *Server) recordConnection(ctx context.Context, client *websocket.Client, record func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), connectionLogTimeout)
+	defer cancel()
+	if err := record(ctx); err != nil {
+		log.Error("failed to record WebSocket connection",
+			zap.String("clientID", client.ID),
+			zap.String("lotID", client.LotID),
+			zap.Error(err),
+		)
 	}
+}
 
-	return srv
+// copyQuery clones the query params, fiber strings point to the request buffer which is reused
+func copyQuery(query map[string]string) map[string]string {
+	copied := make(map[string]string, len(query))
+	for k, v := range query {
+		copied[strings.Clone(k)] = strings.Clone(v)
+	}
+	return copied
+}
+
+// originAllowed reports if origin is in the allowed list, an empty list or "*" allows any origin
+func originAllowed(allowed []string, origin string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, o := range allowed {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// API returns the /api router, modules register their REST routes on it
+func (s *Server) API() fiber.Router {
+	return s.api
+}
+
+// Routes returns the routes registered on the server, without middlewares nor the HEAD routes fiber
+// adds to every GET
+func (s *Server) Routes() []fiber.Route {
+	var routes []fiber.Route
+	for _, route := range s.app.GetRoutes(true) {
+		if route.Method != fiber.MethodHead {
+			routes = append(routes, route)
+		}
+	}
+	return routes
 }
 
+// Context returns the context of the WS connections, done on Shutdown. the modules serving their own WS
+// routes derive the contexts of their connections from it
+func (s *Server) Context() context.Context {
+	return s.ctx
+}
+
+// WS returns the /ws router, its upgrade requests passed the origin and token checks and the claims
+// are available through ConnClaims
+func (s *Server) WS() fiber.Router {
+	return s.app.Group("/ws")
+}
+
+// Start serves on addr until the server is shut down, SIGINT and SIGTERM drain it and shut it down.
+// the listener counts the bytes written to the connections, for the WS compression stats of the hub
 func (s *Server) Start(addr string) error {
-	// Manejo de cierre limpio con señal
-	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, os.Interrupt)
-		<-quit
-
-		log.Info("Shutting down HTTP server...")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = s.app.ShutdownWithContext(ctx)
-	}()
+	go s.shutdownOnSignal()
 
-	log.Info("HTTP server started", zap.String("addr", addr))
-	return s.app.Listen(addr)
+	if !s.tls.Enabled() {
+		ln, err := net.Listen(s.app.Config().Network, addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		log.Info("HTTP server started", zap.String("addr", addr))
+		return s.app.Listener(websocket.NewCountingListener(ln))
+	}
+	tlsConfig, redirect, err := s.tls.build()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	ln = websocket.NewCountingListener(ln)
+	if s.tls.RedirectAddr != "" {
+		s.redirect = newRedirectServer(s.tls.RedirectAddr, redirect)
+		go func() {
+			if err := s.redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("HTTP redirect server failed", zap.Error(err))
+			}
+		}()
+	}
+	log.Info("HTTPS server started",
+		zap.String("addr", addr),
+		zap.Bool("autocert", s.tls.CertFile == ""),
+		zap.String("redirect_addr", s.tls.RedirectAddr),
+	)
+	return s.app.Listener(tls.NewListener(ln, tlsConfig))
+}
+
+// Shutdown stops the server, waiting for the in-flight requests until ctx is done. the WS connections
+// are ended, as they are not waited by the HTTP shutdown. it does not drain, see Drain
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.cancelConns()
+	if s.redirect != nil {
+		_ = s.redirect.Shutdown(ctx)
+	}
+	return s.app.ShutdownWithContext(ctx)
 }
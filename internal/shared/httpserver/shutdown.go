@@ -0,0 +1,95 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// default bounds of the graceful shutdown started by a signal, see Config
+const (
+	defaultDrainTimeout    = 20 * time.Second
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+// errDraining fails the readiness probe while the server drains, so no new traffic is routed to it
+var errDraining = errors.New("server is shutting down")
+
+// ShutdownHook drains a component before the server stops, e.g. closing the WS connections or waiting
+// for the queued bids, it must return when ctx is done
+type ShutdownHook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook ShutdownHook
+}
+
+// AddShutdownHook registers a hook run by Drain, hooks run one after the other in registration order
+func (s *Server) AddShutdownHook(name string, hook ShutdownHook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, namedHook{name: name, hook: hook})
+}
+
+// Draining reports if the server is shutting down, WS upgrades are rejected and /readyz fails meanwhile
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// Drain stops accepting WS upgrades and runs the shutdown hooks, each one bounded by ctx. a failed hook
+// is logged and the next ones still run, the first error is returned
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+	s.hooksMu.Lock()
+	hooks := append([]namedHook{}, s.shutdownHooks...)
+	s.hooksMu.Unlock()
+
+	var first error
+	for _, h := range hooks {
+		start := time.Now()
+		if err := h.hook(ctx); err != nil {
+			log.Error("Shutdown hook failed", zap.String("hook", h.name), zap.Error(err))
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		log.Info("Shutdown hook done", zap.String("hook", h.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	return first
+}
+
+// shutdownOnSignal drains and stops the server on SIGINT or SIGTERM, the drain is bounded by the drain
+// timeout and the in-flight HTTP requests by the shutdown timeout
+func (s *Server) shutdownOnSignal() {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	sig := <-quit
+	signal.Stop(quit)
+
+	log.Info("Draining server before shutdown...", zap.String("signal", sig.String()), zap.Duration("timeout", s.drainTimeout))
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), s.drainTimeout)
+	_ = s.Drain(drainCtx)
+	cancelDrain()
+
+	log.Info("Shutting down HTTP server...")
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		log.Error("HTTP server shutdown failed", zap.Error(err))
+	}
+}
+
+// rejectDraining answers the WS upgrades received while draining with a 503 and a Retry-After
+func (s *Server) rejectDraining(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(s.retryAfter.Seconds()))))
+	return fiber.NewError(fiber.StatusServiceUnavailable, "server is restarting, retry later")
+}
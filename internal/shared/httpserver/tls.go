@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSConfig enables the native TLS of the server, with the certificate of CertFile and KeyFile or
+// with certificates of AutocertDomains issued by Let's Encrypt. the zero value serves plain HTTP
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// AutocertDomains are the domains the Let's Encrypt certificates are requested for, when CertFile is empty
+	AutocertDomains []string
+	// AutocertEmail is the contact of the Let's Encrypt account, optional
+	AutocertEmail string
+	// AutocertCacheDir keeps the issued certificates across restarts
+	AutocertCacheDir string
+	// RedirectAddr serves plain HTTP redirecting to HTTPS, and answering the ACME http-01 challenges
+	// with autocert, empty disables it
+	RedirectAddr string
+}
+
+// Enabled reports if the server serves TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// build returns the TLS config of the listener and the handler of the plain HTTP redirect
+func (c TLSConfig) build() (*tls.Config, http.Handler, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		// TLS 1.2 suites with forward secrecy and AEAD only, TLS 1.3 suites are not configurable
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+	if c.CertFile != "" {
+		if c.KeyFile == "" {
+			return nil, nil, errors.New("TLS key file is required with the cert file")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		return tlsConfig, http.HandlerFunc(redirectToHTTPS), nil
+	}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(c.AutocertDomains...),
+		Cache:      autocert.DirCache(c.AutocertCacheDir),
+		Email:      c.AutocertEmail,
+	}
+	tlsConfig.GetCertificate = manager.GetCertificate
+	// tls-alpn-01 challenges are answered on the TLS listener itself
+	tlsConfig.NextProtos = []string{"http/1.1", "acme-tls/1"}
+	return tlsConfig, manager.HTTPHandler(nil), nil
+}
+
+// redirectToHTTPS redirects a plain HTTP request to its HTTPS URL
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "use HTTPS", http.StatusBadRequest)
+		return
+	}
+	http.Redirect(w, r, "https://"+stripPort(r.Host)+r.URL.RequestURI(), http.StatusMovedPermanently)
+}
+
+// stripPort removes the port of a host, the redirect goes to the default HTTPS port
+func stripPort(host string) string {
+	for i := len(host) - 1; i >= 0; i-- {
+		switch host[i] {
+		case ':':
+			return host[:i]
+		case ']':
+			return host
+		}
+	}
+	return host
+}
+
+// newRedirectServer returns the plain HTTP server of the redirects
+func newRedirectServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       30 * time.Second,
+	}
+}
+
+// secureHeaders returns the middleware setting the browser security headers, HSTS is only sent on
+// HTTPS responses and hstsMaxAge 0 disables it
+func secureHeaders(hstsMaxAge time.Duration) fiber.Handler {
+	return helmet.New(helmet.Config{
+		HSTSMaxAge:    int(hstsMaxAge.Seconds()),
+		XFrameOptions: "DENY",
+		// the API is called from the origins allowed on WS upgrades, its resources are not same-origin only
+		CrossOriginResourcePolicy: "cross-origin",
+	})
+}
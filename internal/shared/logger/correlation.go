@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// correlationIDKey is the context key of the correlation ID
+type correlationIDKey struct{}
+
+// CorrelationIDField is the log field carrying the correlation ID
+const CorrelationIDField = "correlationID"
+
+// NewCorrelationID returns a new random correlation ID
+func NewCorrelationID() string {
+	return uuid.NewString()
+}
+
+// WithCorrelationID returns a copy of ctx carrying id, set once per inbound HTTP request, gRPC call or WS message
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of ctx, empty if there is none
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the logger with the correlation ID of ctx, so every line of a request can be
+// looked up from the ID returned to the client
+func FromContext(ctx context.Context) *zap.Logger {
+	if id := CorrelationID(ctx); id != "" {
+		return GetLogger().With(zap.String(CorrelationIDField, id))
+	}
+	return GetLogger()
+}
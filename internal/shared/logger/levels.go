@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels holds the default log level and the per module overrides, a module is a package path
+// under internal/ like "auction" or "shared/websocket", the longest matching module wins
+type Levels struct {
+	def zap.AtomicLevel
+
+	mu      sync.RWMutex
+	modules map[string]zapcore.Level
+	min     zapcore.Level // lowest enabled level, fast path of Enabled
+}
+
+func newLevels(def zap.AtomicLevel) *Levels {
+	l := &Levels{def: def, modules: map[string]zapcore.Level{}}
+	l.min = def.Level()
+	return l
+}
+
+// parse applies "module=level" items separated by commas
+func (l *Levels) parse(raw string) error {
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		module, rawLevel, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("invalid LOG_LEVELS item %q, expected module=level", item)
+		}
+		level, err := zapcore.ParseLevel(strings.TrimSpace(rawLevel))
+		if err != nil {
+			return fmt.Errorf("invalid LOG_LEVELS item %q: %w", item, err)
+		}
+		l.SetModule(strings.TrimSpace(module), level)
+	}
+	return nil
+}
+
+// Default returns the level of the modules without override
+func (l *Levels) Default() zapcore.Level {
+	return l.def.Level()
+}
+
+// SetDefault changes the level of the modules without override
+func (l *Levels) SetDefault(level zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.def.SetLevel(level)
+	l.updateMin()
+}
+
+// Modules returns a copy of the per module overrides
+func (l *Levels) Modules() map[string]zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return maps.Clone(l.modules)
+}
+
+// SetModule overrides the level of a module
+func (l *Levels) SetModule(module string, level zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modules[strings.Trim(module, "/")] = level
+	l.updateMin()
+}
+
+// ResetModule removes the override of a module, it goes back to the default level
+func (l *Levels) ResetModule(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.modules, strings.Trim(module, "/"))
+	l.updateMin()
+}
+
+// updateMin must be called with mu locked
+func (l *Levels) updateMin() {
+	l.min = l.def.Level()
+	for _, level := range l.modules {
+		l.min = min(l.min, level)
+	}
+}
+
+func (l *Levels) minLevel() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.min
+}
+
+// levelFor returns the level of the module of a source file
+func (l *Levels) levelFor(file string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.modules) == 0 {
+		return l.def.Level()
+	}
+	_, path, found := strings.Cut(file, "/internal/")
+	if !found {
+		return l.def.Level()
+	}
+	level, matched := l.def.Level(), -1
+	for module, moduleLevel := range l.modules {
+		if len(module) > matched && (path == module || strings.HasPrefix(path, module+"/")) {
+			level, matched = moduleLevel, len(module)
+		}
+	}
+	return level
+}
+
+// moduleCore filters the entries by the level of the module that logs them, the module is known
+// from the caller so the package loggers don't need to be named
+type moduleCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+// Enabled lets through the lowest level of any module, Write applies the module level
+func (c *moduleCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.minLevel()
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *moduleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write drops the entries under the level of their module, the caller is set by then
+func (c *moduleCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.levels.levelFor(ent.Caller.File) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
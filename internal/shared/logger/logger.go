@@ -1,22 +1,55 @@
 package logger
 
 import (
+	"os"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var (
 	logger *zap.Logger
+	levels *Levels
 	once   sync.Once
 )
 
-// GetLogger returns zap.Logger instance, but using singleton pattern creates only one reusable instace
-// development config by default
+// GetLogger returns zap.Logger instance, but using singleton pattern creates only one reusable instace.
+// it's configured from the process environment, because package level loggers are created before
+// the .env file is loaded:
+//   - LOG_ENV: "production" for JSON output with sampling, development console output otherwise
+//   - LOG_LEVEL: default level (debug, info, warn, error), info in production and debug in development
+//   - LOG_LEVELS: per module levels as "module=level" items, e.g. "auction=debug,shared/websocket=warn"
 func GetLogger() *zap.Logger {
 	once.Do(func() {
+		cfg := zap.NewDevelopmentConfig()
+		if os.Getenv("LOG_ENV") == "production" {
+			cfg = zap.NewProductionConfig()
+		}
+		if raw := os.Getenv("LOG_LEVEL"); raw != "" {
+			if err := cfg.Level.UnmarshalText([]byte(raw)); err != nil {
+				panic("failed logger setup : invalid LOG_LEVEL " + raw)
+			}
+		}
+		levels = newLevels(cfg.Level)
+		if err := levels.parse(os.Getenv("LOG_LEVELS")); err != nil {
+			panic("failed logger setup : " + err.Error())
+		}
+
+		// the built core accepts every level, the module levels filter the entries. sampling is applied
+		// over the module filter because the inner core Check is bypassed by it
+		cfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+		sampling := cfg.Sampling
+		cfg.Sampling = nil
 		var err error
-		logger, err = zap.NewDevelopment()
+		logger, err = cfg.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			core = &moduleCore{Core: core, levels: levels}
+			if sampling != nil {
+				core = zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
+			}
+			return core
+		}))
 		if err != nil {
 			panic("failed logger setup : " + err.Error())
 		}
@@ -24,3 +57,9 @@ func GetLogger() *zap.Logger {
 	})
 	return logger
 }
+
+// GetLevels returns the runtime adjustable log levels
+func GetLevels() *Levels {
+	GetLogger()
+	return levels
+}
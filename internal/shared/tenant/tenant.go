@@ -0,0 +1,48 @@
+// Package tenant carries the organization (auction house) a request acts for. The repositories of
+// the organization owned data filter their queries by it, work without organization (schedulers,
+// event consumers, anonymous reads) is not scoped and sees every organization
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultOrgID is the organization created by the migrations, it owns the data created before
+// multi-tenancy and is the organization of the tokens without org claim. its admins manage the organizations
+var DefaultOrgID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type orgIDKey struct{}
+
+// WithOrgID returns a copy of ctx scoped to the organization orgID, uuid.Nil leaves ctx unscoped
+func WithOrgID(ctx context.Context, orgID uuid.UUID) context.Context {
+	if orgID == uuid.Nil {
+		return ctx
+	}
+	return context.WithValue(ctx, orgIDKey{}, orgID)
+}
+
+// OrgID returns the organization ctx is scoped to, ok is false for unscoped work
+func OrgID(ctx context.Context) (orgID uuid.UUID, ok bool) {
+	orgID, ok = ctx.Value(orgIDKey{}).(uuid.UUID)
+	return orgID, ok
+}
+
+// Filter returns the org_id param of a query scoped as ctx, nil when unscoped so a
+// "($n::uuid IS NULL OR org_id = $n)" condition matches every organization
+func Filter(ctx context.Context) *uuid.UUID {
+	if orgID, ok := OrgID(ctx); ok {
+		return &orgID
+	}
+	return nil
+}
+
+// OrgIDOrDefault returns the organization of ctx, DefaultOrgID when unscoped, for the data created
+// by unscoped work
+func OrgIDOrDefault(ctx context.Context) uuid.UUID {
+	if orgID, ok := OrgID(ctx); ok {
+		return orgID
+	}
+	return DefaultOrgID
+}
@@ -0,0 +1,105 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ChannelConfig sets the buffer sizes of the hub channels and how long a send waits on a full one.
+// the register, unregister, broadcast and direct buffers are per shard
+type ChannelConfig struct {
+	RegisterBuffer   int
+	UnregisterBuffer int
+	BroadcastBuffer  int
+	DirectBuffer     int
+	InboundBuffer    int
+	// EnqueueTimeout bounds the wait of a send on a full channel, the work is dropped after it
+	EnqueueTimeout time.Duration
+}
+
+// DefaultChannelConfig buffers the bursts of a busy lot and waits up to 1s on a full channel
+func DefaultChannelConfig() ChannelConfig {
+	return ChannelConfig{
+		RegisterBuffer:   256,
+		UnregisterBuffer: 256,
+		BroadcastBuffer:  1024,
+		DirectBuffer:     256,
+		InboundBuffer:    1024,
+		EnqueueTimeout:   time.Second,
+	}
+}
+
+// withDefaults replaces the zero values of c with the defaults
+func (c ChannelConfig) withDefaults() ChannelConfig {
+	def := DefaultChannelConfig()
+	if c.RegisterBuffer <= 0 {
+		c.RegisterBuffer = def.RegisterBuffer
+	}
+	if c.UnregisterBuffer <= 0 {
+		c.UnregisterBuffer = def.UnregisterBuffer
+	}
+	if c.BroadcastBuffer <= 0 {
+		c.BroadcastBuffer = def.BroadcastBuffer
+	}
+	if c.DirectBuffer <= 0 {
+		c.DirectBuffer = def.DirectBuffer
+	}
+	if c.InboundBuffer <= 0 {
+		c.InboundBuffer = def.InboundBuffer
+	}
+	if c.EnqueueTimeout <= 0 {
+		c.EnqueueTimeout = def.EnqueueTimeout
+	}
+	return c
+}
+
+// channelGauge tracks the occupancy of a channel and the sends that had to wait or were dropped,
+// updated by the senders from any goroutine
+type channelGauge struct {
+	highWater atomic.Int64
+	blocked   atomic.Int64
+	timedOut  atomic.Int64
+}
+
+// depth returns the current occupancy of a channel of length and capacity with its counters
+func (g *channelGauge) depth(length, capacity int) ChannelDepth {
+	return ChannelDepth{
+		Len:       length,
+		Cap:       capacity,
+		HighWater: int(g.highWater.Load()),
+		Blocked:   g.blocked.Load(),
+		TimedOut:  g.timedOut.Load(),
+	}
+}
+
+// observe raises the high water mark to the current length of a channel
+func (g *channelGauge) observe(length int) {
+	for {
+		current := g.highWater.Load()
+		if int64(length) <= current || g.highWater.CompareAndSwap(current, int64(length)) {
+			return
+		}
+	}
+}
+
+// enqueue sends v to ch, waiting up to timeout when ch is full. it returns false when the send timed out
+// and v was dropped
+func enqueue[T any](ch chan T, v T, timeout time.Duration, gauge *channelGauge) bool {
+	select {
+	case ch <- v:
+		gauge.observe(len(ch))
+		return true
+	default:
+	}
+	gauge.blocked.Add(1)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ch <- v:
+		gauge.observe(len(ch))
+		return true
+	case <-timer.C:
+		gauge.timedOut.Add(1)
+		return false
+	}
+}
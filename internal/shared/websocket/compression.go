@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"compress/flate"
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+)
+
+// CompressionConfig sets the permessage-deflate compression of the msgs written by the hub, it only
+// applies to the connections whose client negotiated the extension on the upgrade
+type CompressionConfig struct {
+	Enabled bool
+	// Level is the flate level, from -2 (huffman only) to 9 (best compression), 0 uses the default
+	Level int
+	// MinSize is the size from which a msg is compressed, the small updates don't pay off the deflate
+	// cost. a batch is decided on the size of its first msg
+	MinSize int
+}
+
+// DefaultCompressionConfig compresses with the fastest level the msgs of 256 bytes or more
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{Enabled: true, Level: flate.BestSpeed, MinSize: 256}
+}
+
+// CompressionStats compares the bytes of the msgs written by the hub with the bytes they took on the
+// wire, frame headers and TLS records included. only the connections accepted by a listener wrapped
+// by NewCountingListener are measured
+type CompressionStats struct {
+	Enabled bool `json:"enabled"`
+	Level   int  `json:"level"`
+	MinSize int  `json:"min_size"`
+	// Frames written and how many of them were compressed
+	Frames           int64   `json:"frames"`
+	CompressedFrames int64   `json:"compressed_frames"`
+	RawBytes         int64   `json:"raw_bytes"`
+	WireBytes        int64   `json:"wire_bytes"`
+	Ratio            float64 `json:"ratio"` // wire_bytes / raw_bytes, 0 until a frame is measured
+}
+
+// compressionCounters are the totals behind CompressionStats, written by the WritePumps
+type compressionCounters struct {
+	frames           atomic.Int64
+	compressedFrames atomic.Int64
+	rawBytes         atomic.Int64
+	wireBytes        atomic.Int64
+}
+
+func (c *compressionCounters) record(raw, wire int64, compressed bool) {
+	c.frames.Add(1)
+	if compressed {
+		c.compressedFrames.Add(1)
+	}
+	c.rawBytes.Add(raw)
+	c.wireBytes.Add(wire)
+}
+
+func (c *compressionCounters) stats(cfg CompressionConfig) CompressionStats {
+	stats := CompressionStats{
+		Enabled:          cfg.Enabled,
+		Level:            cfg.Level,
+		MinSize:          cfg.MinSize,
+		Frames:           c.frames.Load(),
+		CompressedFrames: c.compressedFrames.Load(),
+		RawBytes:         c.rawBytes.Load(),
+		WireBytes:        c.wireBytes.Load(),
+	}
+	if stats.RawBytes > 0 {
+		stats.Ratio = float64(stats.WireBytes) / float64(stats.RawBytes)
+	}
+	return stats
+}
+
+// withDefaults fills the unset size and replaces an invalid level with the default one
+func (c CompressionConfig) withDefaults() CompressionConfig {
+	defaults := DefaultCompressionConfig()
+	if c.Level < flate.HuffmanOnly || c.Level > flate.BestCompression || c.Level == flate.NoCompression {
+		c.Level = defaults.Level
+	}
+	if c.MinSize <= 0 {
+		c.MinSize = defaults.MinSize
+	}
+	return c
+}
+
+// NewCountingListener wraps ln so the hub can measure the bytes its connections write, it must wrap the
+// TCP listener, below the TLS one
+func NewCountingListener(ln net.Listener) net.Listener {
+	return countingListener{Listener: ln}
+}
+
+type countingListener struct {
+	net.Listener
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn}, nil
+}
+
+// countingConn counts the bytes written to the connection
+type countingConn struct {
+	net.Conn
+	written atomic.Int64
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// wireCounter returns the countingConn under the connection of the client, nil when it wasn't accepted
+// by a counting listener. fasthttp hands the upgraded connections wrapped, UnsafeConn unwraps them
+func (c *Client) wireCounter() *countingConn {
+	if c.Conn == nil || c.Conn.Conn == nil {
+		return nil
+	}
+	conn := c.Conn.UnderlyingConn()
+	if hijacked, ok := conn.(interface{ UnsafeConn() net.Conn }); ok {
+		conn = hijacked.UnsafeConn()
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	counter, _ := conn.(*countingConn)
+	return counter
+}
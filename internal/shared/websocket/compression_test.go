@@ -0,0 +1,98 @@
+package websocket
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	fasthttpws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// runTestHub runs a hub configured with cfg until the end of the test
+func runTestHub(t *testing.T, cfg HubConfig) *Hub {
+	t.Helper()
+	h := NewHubWithConfig(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go h.Run(ctx)
+	go func() {
+		for {
+			select {
+			case <-h.Joined:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return h
+}
+
+// dialTestHub serves the connections of h on a counting listener and dials a client of lotID, the
+// connection runs the pumps of the hub
+func dialTestHub(t *testing.T, h *Hub, lotID string) *fasthttpws.Conn {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws/:lotid", websocket.New(func(c *websocket.Conn) {
+		client := &Client{Hub: h, Conn: c, Send: make(chan []byte, 8), LotID: c.Params("lotid"), ID: "c1",
+			Role: RoleSpectator, RemoteIP: c.RemoteAddr().String()}
+		h.RegisterClient(client)
+		// like the server handler, the end of the ReadPump ends the WritePump, which must be done with the
+		// connection before it's released
+		connCtx, cancelConn := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			client.WritePump(connCtx)
+		}()
+		client.ReadPump(connCtx)
+		cancelConn()
+		<-done
+	}, websocket.Config{EnableCompression: true}))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(NewCountingListener(ln))
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	dialer := fasthttpws.Dialer{EnableCompression: true}
+	conn, _, err := dialer.Dial("ws://"+ln.Addr().String()+"/ws/"+lotID, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	eventually(t, "client registered", func() bool { return registeredCount(h, lotID) == 1 })
+	return conn
+}
+
+func TestHubCompressionStats(t *testing.T) {
+	h := runTestHub(t, HubConfig{Shards: 1, Compression: CompressionConfig{Enabled: true, MinSize: 64}})
+	conn := dialTestHub(t, h, "lot-1")
+
+	message := `{"type":"initial_state","payload":{"description":"` + strings.Repeat("lorem ipsum dolor ", 200) + `"}}`
+	h.BroadcastMessageToLot("lot-1", []byte(message))
+	_, got, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != message {
+		t.Fatalf("got a msg of %d bytes, want the %d bytes broadcast", len(got), len(message))
+	}
+
+	var stats CompressionStats
+	eventually(t, "frame measured", func() bool {
+		stats = h.compressionCounters.stats(h.compression)
+		return stats.Frames == 1
+	})
+	if stats.CompressedFrames != 1 || stats.RawBytes != int64(len(message)) {
+		t.Fatalf("stats = %+v, want 1 compressed frame of %d raw bytes", stats, len(message))
+	}
+	if stats.WireBytes <= 0 || stats.WireBytes >= stats.RawBytes/4 {
+		t.Fatalf("wire bytes = %d, want the repetitive msg of %d bytes compressed", stats.WireBytes, stats.RawBytes)
+	}
+}
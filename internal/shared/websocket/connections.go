@@ -0,0 +1,15 @@
+package websocket
+
+import (
+	"context"
+	"time"
+)
+
+// ConnectionLog records the WS connections for the audit of who watched and bid on a lot, and when.
+// a failure to record a connection never rejects it
+type ConnectionLog interface {
+	// Opened records client, accepted at client.ConnectedAt
+	Opened(ctx context.Context, client *Client) error
+	// Closed records the end of the connection of client at at
+	Closed(ctx context.Context, client *Client, at time.Time) error
+}
@@ -0,0 +1,163 @@
+package websocket
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// demographicsTopIPs is the number of remote IPs listed by LotDemographics
+const demographicsTopIPs = 10
+
+// LotDemographics describes the live connections of a lot, from the metadata captured on their upgrade:
+// the devices, protocols and networks they come from, for abuse investigations and capacity planning
+type LotDemographics struct {
+	LotID   string `json:"lot_id"`
+	Clients int    `json:"clients"`
+	Users   int    `json:"users"` // authenticated users with at least one connection
+	// Resumed is the number of connections that took the client ID of a previous one
+	Resumed int `json:"resumed"`
+	// the connections counted by role, browser, OS, device class (desktop, mobile, bot, other) and WS
+	// subprotocol, "" is counted as "unknown" / "none"
+	Roles     map[string]int `json:"roles"`
+	Browsers  map[string]int `json:"browsers"`
+	OS        map[string]int `json:"os"`
+	Devices   map[string]int `json:"devices"`
+	Protocols map[string]int `json:"protocols"`
+	// DistinctIPs is the number of remote IPs, TopIPs the ones with the most connections
+	DistinctIPs int       `json:"distinct_ips"`
+	TopIPs      []IPCount `json:"top_ips"`
+	// AvgConnectedSeconds is the mean age of the connections
+	AvgConnectedSeconds float64 `json:"avg_connected_seconds"`
+}
+
+// IPCount is the number of connections from a remote IP
+type IPCount struct {
+	IP          string `json:"ip"`
+	Connections int    `json:"connections"`
+}
+
+type demographicsRequest struct {
+	lotID string
+	reply chan *LotDemographics
+}
+
+// LotDemographics returns the demographics of the live connections of lotID on this instance, it fails
+// with ErrHubNotRunning if the shard of the lot does not answer before ctx is done
+func (h *Hub) LotDemographics(ctx context.Context, lotID string) (*LotDemographics, error) {
+	req := &demographicsRequest{lotID: lotID, reply: make(chan *LotDemographics, 1)}
+	select {
+	case h.shardFor(lotID).demographics <- req:
+	case <-ctx.Done():
+		return nil, ErrHubNotRunning
+	}
+	select {
+	case demographics := <-req.reply:
+		return demographics, nil
+	case <-ctx.Done():
+		return nil, ErrHubNotRunning
+	}
+}
+
+// lotDemographics builds the LotDemographics of lotID, must be called from the shard run loop
+func (s *hubShard) lotDemographics(lotID string, now time.Time) *LotDemographics {
+	d := &LotDemographics{
+		LotID:     lotID,
+		Roles:     make(map[string]int),
+		Browsers:  make(map[string]int),
+		OS:        make(map[string]int),
+		Devices:   make(map[string]int),
+		Protocols: make(map[string]int),
+		TopIPs:    []IPCount{},
+	}
+	users := make(map[string]struct{})
+	ips := make(map[string]int)
+	var connectedFor time.Duration
+	for client := range s.clients[lotID] {
+		d.Clients++
+		if client.UserID != "" {
+			users[client.UserID] = struct{}{}
+		}
+		if client.Resumed {
+			d.Resumed++
+		}
+		d.Roles[string(client.Role)]++
+		browser, os, device := ParseUserAgent(client.UserAgent)
+		d.Browsers[browser]++
+		d.OS[os]++
+		d.Devices[device]++
+		d.Protocols[cmp.Or(client.Protocol, "none")]++
+		ips[cmp.Or(client.RemoteIP, "unknown")]++
+		if !client.ConnectedAt.IsZero() {
+			connectedFor += now.Sub(client.ConnectedAt)
+		}
+	}
+	d.Users = len(users)
+	d.DistinctIPs = len(ips)
+	for ip, n := range ips {
+		d.TopIPs = append(d.TopIPs, IPCount{IP: ip, Connections: n})
+	}
+	slices.SortFunc(d.TopIPs, func(a, b IPCount) int {
+		if a.Connections != b.Connections {
+			return b.Connections - a.Connections
+		}
+		return strings.Compare(a.IP, b.IP)
+	})
+	d.TopIPs = d.TopIPs[:min(len(d.TopIPs), demographicsTopIPs)]
+	if d.Clients > 0 {
+		d.AvgConnectedSeconds = (connectedFor / time.Duration(d.Clients)).Seconds()
+	}
+	return d
+}
+
+// ParseUserAgent classifies a User-Agent header into its browser, OS and device class. it only knows the
+// common families, the rest are "other", and an empty header is "unknown"
+func ParseUserAgent(ua string) (browser, os, device string) {
+	if ua == "" {
+		return "unknown", "unknown", "unknown"
+	}
+	lower := strings.ToLower(ua)
+	browser = firstMatch(lower, "other",
+		[2]string{"edg/", "edge"},
+		[2]string{"opr/", "opera"},
+		[2]string{"firefox/", "firefox"},
+		[2]string{"chrome/", "chrome"},
+		[2]string{"safari/", "safari"},
+		[2]string{"go-http-client", "go"},
+		[2]string{"okhttp", "okhttp"},
+		[2]string{"python", "python"},
+		[2]string{"node", "node"},
+		[2]string{"curl/", "curl"},
+	)
+	os = firstMatch(lower, "other",
+		[2]string{"android", "android"},
+		[2]string{"iphone", "ios"},
+		[2]string{"ipad", "ios"},
+		[2]string{"windows", "windows"},
+		[2]string{"mac os", "macos"},
+		[2]string{"linux", "linux"},
+	)
+	switch {
+	case strings.Contains(lower, "bot") || strings.Contains(lower, "spider") || strings.Contains(lower, "crawl"):
+		device = "bot"
+	case strings.Contains(lower, "mobile") || os == "android" || os == "ios":
+		device = "mobile"
+	case os == "windows" || os == "macos" || os == "linux":
+		device = "desktop"
+	default:
+		device = "other"
+	}
+	return browser, os, device
+}
+
+// firstMatch returns the name of the first of patterns contained in ua, fallback when none is
+func firstMatch(ua, fallback string, patterns ...[2]string) string {
+	for _, p := range patterns {
+		if strings.Contains(ua, p[0]) {
+			return p[1]
+		}
+	}
+	return fallback
+}
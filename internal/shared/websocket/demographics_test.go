@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		ua                  string
+		browser, os, device string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36", "chrome", "windows", "desktop"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Version/17.0 Mobile/15E148 Safari/604.1", "safari", "ios", "mobile"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36 EdgA/120.0 Edg/120.0", "edge", "android", "mobile"},
+		{"Go-http-client/1.1", "go", "other", "other"},
+		{"Googlebot/2.1 (+http://www.google.com/bot.html)", "other", "other", "bot"},
+		{"", "unknown", "unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		browser, os, device := ParseUserAgent(tt.ua)
+		if browser != tt.browser || os != tt.os || device != tt.device {
+			t.Errorf("ParseUserAgent(%q) = %s, %s, %s, want %s, %s, %s", tt.ua, browser, os, device, tt.browser, tt.os, tt.device)
+		}
+	}
+}
+
+// TestHubLotDemographics checks the demographics only count the connections of the lot
+func TestHubLotDemographics(t *testing.T) {
+	h := newTestHub(t, 2)
+	clients := []*Client{
+		newTestClient(h, "client-1", "lot-a", "user-1", 4),
+		newTestClient(h, "client-2", "lot-a", "user-1", 4),
+		newTestClient(h, "client-3", "lot-a", "", 4),
+		newTestClient(h, "client-4", "lot-b", "user-2", 4),
+	}
+	clients[0].RemoteIP, clients[1].RemoteIP, clients[2].RemoteIP = "10.0.0.1", "10.0.0.1", "10.0.0.2"
+	clients[0].Protocol = "auction.v1"
+	clients[2].Role = RoleSpectator
+	clients[1].Resumed = true
+	for _, client := range clients {
+		client.ConnectedAt = time.Now()
+		h.RegisterClient(client)
+	}
+	eventually(t, "registrations", func() bool { return registeredCount(h, "lot-a", "lot-b") == 4 })
+
+	d, err := h.LotDemographics(context.Background(), "lot-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Clients != 3 || d.Users != 1 || d.Resumed != 1 || d.DistinctIPs != 2 {
+		t.Fatalf("demographics %+v, want 3 clients of 1 user from 2 IPs, 1 resumed", d)
+	}
+	if d.Roles["bidder"] != 2 || d.Roles["spectator"] != 1 || d.Protocols["auction.v1"] != 1 || d.Protocols["none"] != 2 {
+		t.Fatalf("roles %v and protocols %v, want 2 bidders, 1 spectator and 1 negotiated protocol", d.Roles, d.Protocols)
+	}
+	if d.TopIPs[0] != (IPCount{IP: "10.0.0.1", Connections: 2}) {
+		t.Fatalf("top IPs %v, want 10.0.0.1 first", d.TopIPs)
+	}
+}
@@ -0,0 +1,120 @@
+package websocket
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HeartbeatConfig sets the ping/pong cadence of the connections and the stale connection reaping
+type HeartbeatConfig struct {
+	// PingPeriod is how often a ping is sent to each client, must be less than PongWait
+	PingPeriod time.Duration
+	// PongWait is the read deadline renewed by each pong, a connection silent for longer is closed by its ReadPump
+	PongWait time.Duration
+	// StaleAfter reaps the connections without a pong for longer, before PongWait expires. 0 disables the reaping
+	StaleAfter time.Duration
+	// ReapInterval is how often each hub shard looks for stale connections
+	ReapInterval time.Duration
+}
+
+// DefaultHeartbeatConfig pings every 54s with a 60s pong wait and no reaping
+func DefaultHeartbeatConfig() HeartbeatConfig {
+	return HeartbeatConfig{
+		PingPeriod:   pingPeriod,
+		PongWait:     pongWait,
+		ReapInterval: 10 * time.Second,
+	}
+}
+
+// staleThreshold is the silence after which a connection is counted as stale in the stats
+func (c HeartbeatConfig) staleThreshold() time.Duration {
+	if c.StaleAfter > 0 {
+		return c.StaleAfter
+	}
+	return c.PongWait
+}
+
+// LotHeartbeatStats summarizes the heartbeat round trips of the connections of a lot, the latency of a
+// connection is the round trip of its last answered ping
+type LotHeartbeatStats struct {
+	Measured    int     `json:"measured"` // connections that answered at least one ping
+	MedianMs    float64 `json:"median_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	Stale       int     `json:"stale"`         // connections without a pong for longer than the stale threshold
+	OldestPongS float64 `json:"oldest_pong_s"` // seconds since the last pong of the most silent connection
+}
+
+// pingPayload is the application data of a ping, the send time echoed back by the pong
+func pingPayload(now time.Time) []byte {
+	return strconv.AppendInt(nil, now.UnixNano(), 10)
+}
+
+// recordPong stores the time of a pong and the round trip of the ping it answers
+func (c *Client) recordPong(appData string, now time.Time) {
+	c.lastPong.Store(now.UnixNano())
+	sent, err := strconv.ParseInt(appData, 10, 64)
+	if err != nil || sent <= 0 || sent > now.UnixNano() {
+		return
+	}
+	c.heartbeatRTT.Store(now.UnixNano() - sent)
+}
+
+// silence returns the time since the last pong of the client, or since it was registered
+func (c *Client) silence(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, c.lastPong.Load()))
+}
+
+// reapStale unregisters the connections of the shard silent for longer than StaleAfter, must be called from
+// the shard run loop. closing Send makes the WritePump close the connection
+func (s *hubShard) reapStale(now time.Time) {
+	for lotID, clients := range s.clients {
+		for client := range clients {
+			silence := client.silence(now)
+			if silence <= s.hub.heartbeat.StaleAfter {
+				continue
+			}
+			s.evict(client)
+			s.reapedStale++
+			log.Warn("Stale client reaped",
+				zap.String("clientID", client.ID),
+				zap.String("lotID", lotID),
+				zap.Duration("silence", silence),
+			)
+		}
+	}
+}
+
+// heartbeatStats computes the heartbeat stats of the clients of a lot, must be called from the shard run loop
+func (s *hubShard) heartbeatStats(clients map[*Client]bool, now time.Time) LotHeartbeatStats {
+	var stats LotHeartbeatStats
+	threshold := s.hub.heartbeat.staleThreshold()
+	rtts := make([]time.Duration, 0, len(clients))
+	for client := range clients {
+		silence := client.silence(now)
+		if silence > threshold {
+			stats.Stale++
+		}
+		stats.OldestPongS = math.Max(stats.OldestPongS, silence.Seconds())
+		if rtt := client.heartbeatRTT.Load(); rtt > 0 {
+			rtts = append(rtts, time.Duration(rtt))
+		}
+	}
+	stats.Measured = len(rtts)
+	if len(rtts) > 0 {
+		slices.Sort(rtts)
+		stats.MedianMs = percentile(rtts, 0.50)
+		stats.P95Ms = percentile(rtts, 0.95)
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank p percentile of sorted in milliseconds
+func percentile(sorted []time.Duration, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	rank = min(max(rank, 0), len(sorted)-1)
+	return float64(sorted[rank].Microseconds()) / 1000
+}
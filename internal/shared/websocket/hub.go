@@ -2,6 +2,11 @@ package websocket
 
 import (
 	"context"
+	"errors"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
@@ -16,31 +21,80 @@ const (
 	// Time allowed to write a message to the peer.
 	writeWait = 10 * time.Second
 
-	// Time allowed to read the next pong message from the peer.
+	// Default time allowed to read the next pong message from the peer, see HeartbeatConfig.
 	pongWait = 60 * time.Second
 
-	// Send pings to peer with this period. Must be less than pongWait.
+	// Default period of the pings to peer. Must be less than pongWait.
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer.
+	// Default maximum message size allowed from peer, see InboundConfig.
 	maxMessageSize = 512
 )
 
-// Hub keeps client's registry and handle messages broadcasting
+// Hub keeps client's registry and handle messages broadcasting. the registry is split in shards by lot ID,
+// each one run by its own goroutine, so a registration storm or a hot lot only stalls the lots of its shard
 type Hub struct {
-	// Registered clients, grouped by lot ID.
-	// The keys of the outer map are lot IDs.
-	// The inner map keys are clients, and the boolean value is ignored.
-	clients map[string]map[*Client]bool
-	// Inbound messages from the clien
-	broadcast chan *Message
-	// Register requests from the clients.
-	register chan *Client
-	// Unregister requests from clients.
-	unregister      chan *Client
+	shards []*hubShard
+	// shard of each registered client by client ID, to route the msgs addressed to a single client
+	clientShards    sync.Map
 	InboundMessages chan *ClientMessage // this channel will be listened to by module-specific handlers (e.g, auction handler)
+	// Clients just registered, listened by module-specific handlers to send the initial state
+	Joined chan *Client
+	// Inbound msgs dropped because InboundMessages stayed full, listened by module-specific handlers
+	// to keep them as dead letters and ask the clients to retry
+	Dropped chan *DroppedMessage
+
+	// connection counts by lot and role, written by the shards and readable from any goroutine
+	countsMu sync.RWMutex
+	counts   map[string]map[ClientRole]int
+	// lots whose counts changed since the last DrainPresenceChanges call
+	presenceChanged map[string]struct{}
+	// subscribers by topic across the shards, to skip the topic publishes of the lots nobody follows
+	topicsMu    sync.RWMutex
+	topicCounts map[string]int
+
+	// ping/pong cadence and stale connection reaping
+	heartbeat HeartbeatConfig
+	// how long the sends to a full channel wait before dropping the work
+	enqueueTimeout time.Duration
+	inboundGauge   channelGauge
+	// permessage-deflate of the written msgs and the bytes they took, see CompressionStats
+	compression         CompressionConfig
+	compressionCounters compressionCounters
+	// read limit and validation of the msgs read from the clients
+	inbound         InboundConfig
+	inboundCounters inboundCounters
+	// close frame of the connections once CloseAll was called, nil while the hub accepts connections
+	closing atomic.Pointer[[]byte]
 }
 
+// HubConfig configures a Hub, the zero values of its fields use the defaults
+type HubConfig struct {
+	// Shards is the number of goroutines owning the registry, lots are spread among them by hash
+	Shards    int
+	Heartbeat HeartbeatConfig
+	Channels  ChannelConfig
+	// Compression of the written msgs, disabled when Enabled is false
+	Compression CompressionConfig
+	// Inbound checks the msgs read from the clients
+	Inbound InboundConfig
+}
+
+// ClientRole defines what a connection is allowed to do in its lot
+type ClientRole string
+
+const (
+	RoleSpectator  ClientRole = "spectator"  // read-only, receives updates
+	RoleBidder     ClientRole = "bidder"     // authenticated, allowed to send bids
+	RoleAuctioneer ClientRole = "auctioneer" // connected through the admin channel, runs the lot
+)
+
+// DropReasonInboundFull is the reason of the msgs dropped because InboundMessages stayed full
+const DropReasonInboundFull = "inbound_full"
+
+// ErrHubNotRunning is returned by Alive when the run loop of a shard does not answer in time
+var ErrHubNotRunning = errors.New("websocket hub is not running")
+
 // Client represents a ws individual connection
 type Client struct {
 	Hub *Hub
@@ -52,6 +106,40 @@ type Client struct {
 	LotID string
 	// Unique identifier for the client
 	ID string
+	// Authenticated user owning this connection, empty for anonymous spectators
+	UserID string
+	// OrgID is the organization of the token, empty for anonymous spectators
+	OrgID string
+	// SessionID is the session of the token, empty for the tokens issued outside a session
+	SessionID string
+	// Role of the connection in the lot
+	Role ClientRole
+	// RemoteIP of the upgrade request, honoring the proxy headers configured in the HTTP server
+	RemoteIP string
+	// Query params of the upgrade request, e.g. last_event_seq for resuming
+	Query map[string]string
+	// ConnectedAt is when the connection was accepted, Resumed tells if it took the ID of a previous
+	// connection of the client, see wsproto.QueryConnectionToken
+	ConnectedAt time.Time
+	Resumed     bool
+	// UserAgent of the upgrade request and Protocol, the WS subprotocol negotiated, empty for none
+	UserAgent string
+	Protocol  string
+	// Greeting is sent to the client as soon as it's registered, before any other msg, nil for none
+	Greeting []byte
+
+	// unix nanos of the last pong, or of the registration until the first one
+	lastPong atomic.Int64
+	// round trip in nanos of the last answered ping, 0 until the first pong
+	heartbeatRTT atomic.Int64
+	// payload of the close frame sent when the hub closes Send, set by the shard before closing it
+	closeFrame []byte
+	// lifecycle state, see clientPending
+	state atomic.Int32
+	// topics subscribed besides the lot room, only touched by the run loop of its shard
+	topics map[string]struct{}
+	// malformed msgs read from the client, only touched by its ReadPump
+	malformed int
 }
 
 type Message struct {
@@ -59,150 +147,306 @@ type Message struct {
 	Data  []byte
 }
 
+// DirectMessage is a private msg addressed either to a single client (ClientID), to every connection
+// of a user (UserID) or to every connection (All), whatever lot they are in
+type DirectMessage struct {
+	ClientID string
+	UserID   string
+	All      bool
+	Data     []byte
+	// Disconnect closes the addressed connections instead of sending Data, the ones of a user are
+	// narrowed to OrgID and SessionID when they are set
+	Disconnect bool
+	OrgID      string
+	SessionID  string
+}
+
+// DroppedMessage is an inbound msg the hub could not hand to the handlers
+type DroppedMessage struct {
+	ClientMessage
+	Reason    string
+	DroppedAt time.Time
+}
+
 // ClientMessage is used for wraping the client and data message received.
 // is used to send inbound messages from the client to the hub handlers
 type ClientMessage struct {
 	Client *Client
 	Data   []byte
+	// ReceivedAt is when the msg was read from the connection, before any queueing
+	ReceivedAt time.Time
 }
 
+// NewHub creates a Hub with the default configuration
 func NewHub() *Hub {
-	return &Hub{
-		broadcast:       make(chan *Message),
-		register:        make(chan *Client),
-		unregister:      make(chan *Client),
-		clients:         make(map[string]map[*Client]bool),
-		InboundMessages: make(chan *ClientMessage),
+	return NewHubWithConfig(HubConfig{})
+}
+
+// NewHubWithConfig creates a Hub with cfg
+func NewHubWithConfig(cfg HubConfig) *Hub {
+	heartbeat := DefaultHeartbeatConfig()
+	if cfg.Heartbeat.PongWait > 0 {
+		heartbeat.PongWait = cfg.Heartbeat.PongWait
+	}
+	if cfg.Heartbeat.PingPeriod > 0 {
+		heartbeat.PingPeriod = cfg.Heartbeat.PingPeriod
+	}
+	if heartbeat.PingPeriod >= heartbeat.PongWait {
+		heartbeat.PingPeriod = heartbeat.PongWait * 9 / 10
+	}
+	if cfg.Heartbeat.ReapInterval > 0 {
+		heartbeat.ReapInterval = cfg.Heartbeat.ReapInterval
+	}
+	heartbeat.StaleAfter = cfg.Heartbeat.StaleAfter
+	shards := cfg.Shards
+	if shards <= 0 {
+		shards = runtime.NumCPU()
+	}
+	channels := cfg.Channels.withDefaults()
+	h := &Hub{
+		InboundMessages: make(chan *ClientMessage, channels.InboundBuffer),
+		Joined:          make(chan *Client, 256),
+		Dropped:         make(chan *DroppedMessage, channels.InboundBuffer),
+		counts:          make(map[string]map[ClientRole]int),
+		presenceChanged: make(map[string]struct{}),
+		topicCounts:     make(map[string]int),
+		heartbeat:       heartbeat,
+		enqueueTimeout:  channels.EnqueueTimeout,
+		compression:     cfg.Compression.withDefaults(),
+		inbound:         cfg.Inbound.withDefaults(),
+	}
+	h.shards = make([]*hubShard, shards)
+	for i := range h.shards {
+		h.shards[i] = newHubShard(h, i, channels)
 	}
+	return h
 }
 
-// Run starts the hub listening in their channels
+// Run starts the hub shards listening in their channels, it blocks until ctx is done
 func (h *Hub) Run(ctx context.Context) {
-	log.Info("Websocker Hub started")
-	for {
-		select {
-		case <-ctx.Done(): // <-- Check context cancellation
-			log.Info("WebSocket Hub shutting down due to context cancellation")
-			// TODO: Consider graceful shutdown of clients
-			return // Exit the goroutine
-		case client := <-h.register:
-			// Register the client in lotId group
-			if _, ok := h.clients[client.LotID]; !ok {
-				h.clients[client.LotID] = make(map[*Client]bool)
-			}
-			h.clients[client.LotID][client] = true
-			log.Info("Client registered",
-				zap.String("clientID", client.ID),
-				zap.String("LotID", client.LotID),
-				zap.String("remote_addr", client.Conn.RemoteAddr().String()),
-				zap.Int("total_clients", func() int {
-					count := 0
-					for _, lotClients := range h.clients {
-						count += len(lotClients)
-					}
-					return count
-				}()),
-			)
+	log.Info("Websocker Hub started", zap.Int("shards", len(h.shards)))
+	var wg sync.WaitGroup
+	for _, shard := range h.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard.run(ctx)
+		}()
+	}
+	wg.Wait()
+	log.Info("WebSocket Hub shutting down due to context cancellation")
+}
 
-		case client := <-h.unregister:
-			// remove the client from LotID group
-			if clients, ok := h.clients[client.LotID]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.Send)
-					log.Info("Client unregistered",
-						zap.String("clientID", client.ID),
-						zap.String("lotID", client.LotID),
-						zap.String("remote_addr", client.Conn.RemoteAddr().String()),
-						zap.Int("total_clients", func() int { // Log total clients
-							count := 0
-							for _, lotClients := range h.clients {
-								count += len(lotClients)
-							}
-							return count
-						}()),
-					)
-					// Si no quedan clientes en este grupo, elimina el mapa
-					if len(clients) == 0 {
-						delete(h.clients, client.LotID)
-						log.Info("Lot group removed as empty", zap.String("LotID", client.LotID))
-					}
-				}
-			}
+// shardFor returns the shard owning the clients of lotID
+func (h *Hub) shardFor(lotID string) *hubShard {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(lotID))
+	return h.shards[hash.Sum32()%uint32(len(h.shards))]
+}
 
-		case message := <-h.broadcast:
-			//broadcast the message to all the clients in LotID group
-			if clients, ok := h.clients[message.LotID]; ok {
-				log.Debug("Broadcasting message to lot", zap.String("LotID", message.LotID), zap.Int("clients", len(clients)))
-				for client := range clients {
-					select {
-					case client.Send <- message.Data:
-						// message sended
-					default:
-						//message could not be sent, client probably disconneted, closing channel
-						close(client.Send)
-						//deleting client form client's map
-						delete(clients, client)
-						log.Warn("Failed to Send message to client, unregistering",
-							zap.String("clientID", client.ID), // Use client.ID
-							zap.String("lotID", client.LotID),
-							zap.String("remote_addr", client.Conn.RemoteAddr().String()),
-						)
-					}
-				}
-			}
+// adjustCount updates the per lot role counters, must be called from the run loop of a shard
+func (h *Hub) adjustCount(client *Client, delta int) {
+	h.countsMu.Lock()
+	defer h.countsMu.Unlock()
+	roles, ok := h.counts[client.LotID]
+	if !ok {
+		roles = make(map[ClientRole]int)
+		h.counts[client.LotID] = roles
+	}
+	roles[client.Role] += delta
+	h.presenceChanged[client.LotID] = struct{}{}
+	if roles[client.Role] <= 0 {
+		delete(roles, client.Role)
+	}
+	if len(roles) == 0 {
+		delete(h.counts, client.LotID)
+	}
+}
+
+// CountByRole returns the number of spectators and bidders connected to a lot
+func (h *Hub) CountByRole(lotID string) (spectators, bidders int) {
+	h.countsMu.RLock()
+	defer h.countsMu.RUnlock()
+	roles := h.counts[lotID]
+	return roles[RoleSpectator], roles[RoleBidder]
+}
+
+// DrainPresenceChanges returns the lots whose connection counts changed since the previous call,
+// allowing callers to debounce presence notifications instead of reacting to every join/leave
+func (h *Hub) DrainPresenceChanges() []string {
+	h.countsMu.Lock()
+	defer h.countsMu.Unlock()
+	lots := make([]string, 0, len(h.presenceChanged))
+	for lotID := range h.presenceChanged {
+		lots = append(lots, lotID)
+	}
+	clear(h.presenceChanged)
+	return lots
+}
+
+// Alive checks that the run loops of the shards are processing their channels, it fails if a loop
+// does not answer before ctx is done
+func (h *Hub) Alive(ctx context.Context) error {
+	for _, shard := range h.shards {
+		reply := make(chan struct{})
+		select {
+		case shard.ping <- reply:
+		case <-ctx.Done():
+			return ErrHubNotRunning
+		}
+		select {
+		case <-reply:
+		case <-ctx.Done():
+			return ErrHubNotRunning
 		}
 	}
+	return nil
 }
 
-// RegisterClient register a new client in the hub
+// RegisterClient register a new client in the hub, the connection is closed if the shard register
+// channel stays full for the enqueue timeout
 func (h *Hub) RegisterClient(client *Client) {
-	select { // Use select to avoid blocking if channel is full
-	case h.register <- client:
+	shard := h.shardFor(client.LotID)
+	if enqueue(shard.register, client, h.enqueueTimeout, &shard.registerGauge) {
 		log.Debug("Client queued for registration",
 			zap.String("clientID", client.ID),
 			zap.String("lotID", client.LotID),
 		)
-	default:
-		log.Error("Register channel is full, client registration failed",
-			zap.String("clientID", client.ID),
-			zap.String("lotID", client.LotID),
-		)
-		// Optionally close the client connection immediately if registration fails
-		_ = client.Conn.Close()
+		return
 	}
+	log.Error("Register channel is full, client registration timed out",
+		zap.String("clientID", client.ID),
+		zap.String("lotID", client.LotID),
+		zap.Duration("timeout", h.enqueueTimeout),
+	)
+	_ = client.Conn.Close()
 }
 
 // UnregisterClient delete a client from the hub
 func (h *Hub) UnregisterClient(client *Client) {
-	select { // Use select to avoid blocking if channel is full
-	case h.unregister <- client:
+	shard := h.shardFor(client.LotID)
+	if enqueue(shard.unregister, client, h.enqueueTimeout, &shard.unregisterGauge) {
 		log.Debug("Client queued for unregistration",
 			zap.String("clientID", client.ID),
 			zap.String("lotID", client.LotID),
 		)
-	default:
-		log.Error("Unregister channel is full, client unregistration failed",
-			zap.String("clientID", client.ID),
-			zap.String("lotID", client.LotID),
-		)
-		// The client might already be closing, not much to do here.
+		return
 	}
+	// the client stays registered until a broadcast finds its send buffer full or it is reaped as stale
+	log.Error("Unregister channel is full, client unregistration timed out",
+		zap.String("clientID", client.ID),
+		zap.String("lotID", client.LotID),
+		zap.Duration("timeout", h.enqueueTimeout),
+	)
 }
 
-// BroadcastMessageToLot sends a msg to all subscribed clients in a specific loID
+// BroadcastMessageToLot sends a msg to all subscribed clients in a specific loID, and to the subscribers
+// of its AuctionTopic. the msg is dropped if the shard broadcast channel stays full for the enqueue timeout
 func (h *Hub) BroadcastMessageToLot(lotID string, data []byte) {
-	select { // Use select to avoid blocking if channel is full
-	case h.broadcast <- &Message{LotID: lotID, Data: data}:
+	if topic := AuctionTopic(lotID); h.hasSubscribers(topic) {
+		h.Publish(topic, data)
+	}
+	shard := h.shardFor(lotID)
+	if enqueue(shard.broadcast, &Message{LotID: lotID, Data: data}, h.enqueueTimeout, &shard.broadcastGauge) {
 		log.Debug("Message queued for broadcast", zap.String("lotID", lotID))
+		return
+	}
+	log.Error("Broadcast channel is full, message dropped",
+		zap.String("lotID", lotID),
+		zap.Duration("timeout", h.enqueueTimeout),
+	)
+}
+
+// SendToClient sends a private msg to a single client, e.g. a bid confirmation or an error
+func (h *Hub) SendToClient(clientID string, data []byte) {
+	shard, ok := h.clientShards.Load(clientID)
+	if !ok {
+		log.Debug("Direct message to an unknown client dropped", zap.String("clientID", clientID))
+		return
+	}
+	shard.(*hubShard).sendDirectMessage(&DirectMessage{ClientID: clientID, Data: data})
+}
+
+// SendToUser sends a private msg to all the connections of a user, across all lots. the connections of
+// a user may be in any shard, so every shard gets the msg
+func (h *Hub) SendToUser(userID string, data []byte) {
+	for _, shard := range h.shards {
+		shard.sendDirectMessage(&DirectMessage{UserID: userID, Data: data})
+	}
+}
+
+// BroadcastToAll sends a msg to every connection of every lot, e.g. a maintenance notice
+func (h *Hub) BroadcastToAll(data []byte) {
+	for _, shard := range h.shards {
+		shard.sendDirectMessage(&DirectMessage{All: true, Data: data})
+	}
+}
+
+// Disconnect closes all the connections of a user, narrowed to the ones of an organization and of a
+// session when orgID and sessionID are not empty, e.g. when the user tokens are revoked
+func (h *Hub) Disconnect(userID, orgID, sessionID string) {
+	for _, shard := range h.shards {
+		shard.sendDirectMessage(&DirectMessage{UserID: userID, OrgID: orgID, SessionID: sessionID, Disconnect: true})
+	}
+}
+
+func (s *hubShard) sendDirectMessage(message *DirectMessage) {
+	if enqueue(s.direct, message, s.hub.enqueueTimeout, &s.directGauge) {
+		log.Debug("Direct message queued",
+			zap.String("clientID", message.ClientID),
+			zap.String("userID", message.UserID),
+		)
+		return
+	}
+	log.Error("Direct channel is full, message dropped",
+		zap.String("clientID", message.ClientID),
+		zap.String("userID", message.UserID),
+		zap.Duration("timeout", s.hub.enqueueTimeout),
+	)
+}
+
+// CloseAll stops accepting connections and closes every registered one with a close frame of code and
+// text, e.g. before a shutdown. the connections registered later are closed on registration. it returns
+// once every shard has closed its connections or with ctx error when ctx is done first
+func (h *Hub) CloseAll(ctx context.Context, code int, text string) error {
+	frame := websocket.FormatCloseMessage(code, text)
+	h.closing.Store(&frame)
+	replies := make([]chan struct{}, 0, len(h.shards))
+	for _, shard := range h.shards {
+		reply := make(chan struct{})
+		select {
+		case shard.closeAll <- reply:
+			replies = append(replies, reply)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for _, reply := range replies {
+		select {
+		case <-reply:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// drop hands a dropped msg to the Dropped listeners, without blocking the caller
+func (h *Hub) drop(message *DroppedMessage) {
+	select {
+	case h.Dropped <- message:
 	default:
-		log.Error("Broadcast channel is full, message dropped", zap.String("lotID", lotID))
-		// Handle case where broadcast channel is full (e.g., log error, implement retry)
+		log.Error("Dropped channel is full, dropped message lost",
+			zap.String("clientID", message.Client.ID),
+			zap.String("lotID", message.Client.LotID),
+			zap.String("reason", message.Reason),
+		)
 	}
 }
 
 // ReadPump reads msgs from client and send it to the hub (through broadcast channel)
-// this method must be executed in a separated go routine for each client
+// this method must be executed in a separated go routine for each client, it returns when the connection
+// fails or ctx, the context of the connection, is done
 func (c *Client) ReadPump(ctx context.Context) {
 	defer func() {
 		c.Hub.UnregisterClient(c)
@@ -215,9 +459,26 @@ func (c *Client) ReadPump(ctx context.Context) {
 	}()
 	// ping pong mechanisim to detect clients who disconnect abruptly, as closing web browser, network issues, if the client doesn't respond
 	// the ping with a pong inside pongWait, the server assumes a death connection and closes it
-	c.Conn.SetReadLimit(maxMessageSize)
+	c.Conn.SetReadLimit(int64(c.Hub.inbound.MaxMessageSize))
+	// the pongs also feed the liveness metrics: last pong time and heartbeat round trip
+	pongWait := c.Hub.heartbeat.PongWait
 	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
-	c.Conn.SetPongHandler(func(string) error { c.Conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+	c.Conn.SetPongHandler(func(appData string) error {
+		now := time.Now()
+		c.recordPong(appData, now)
+		c.Conn.SetReadDeadline(now.Add(pongWait))
+		// the deadline stays expired once ctx is done, even if it was done while renewing it
+		if ctx.Err() != nil {
+			c.Conn.SetReadDeadline(now)
+		}
+		return nil
+	})
+	// the read below blocks until the peer sends something, expiring its deadline makes it return as
+	// soon as ctx is done instead of at the next msg or pong timeout
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.Conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
 
 	log.Info("ReadPump started for client",
 		zap.String("clientID", c.ID),
@@ -239,7 +500,15 @@ func (c *Client) ReadPump(ctx context.Context) {
 		}
 
 		_, message, err := c.Conn.ReadMessage()
+		receivedAt := time.Now()
 		if err != nil {
+			if ctx.Err() != nil {
+				log.Info("ReadPump context cancelled for client",
+					zap.String("clientID", c.ID),
+					zap.String("lotID", c.LotID),
+				)
+				break
+			}
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
 				log.Error("WebSocket read error",
 					zap.String("clientID", c.ID),
@@ -264,24 +533,32 @@ func (c *Client) ReadPump(ctx context.Context) {
 			zap.String("lotID", c.LotID),
 			zap.ByteString("message", message),
 		)
+		if ok, closeConn := c.validateInbound(message); closeConn {
+			break
+		} else if !ok {
+			continue
+		}
 
 		// Send the received message to the Hub's InboundMessages channel
-		// Module-specific handlers will listen on this channel.
-		select {
-		case c.Hub.InboundMessages <- &ClientMessage{Client: c, Data: message}: // <-- Send message to InboundMessages
+		// Module-specific handlers will listen on this channel. while it is full this connection is not
+		// read, pushing back on the client, and after the enqueue timeout the message is dropped
+		if enqueue(c.Hub.InboundMessages, &ClientMessage{Client: c, Data: message, ReceivedAt: receivedAt}, c.Hub.enqueueTimeout, &c.Hub.inboundGauge) {
 			log.Debug("Message sent to Hub's InboundMessages channel",
 				zap.String("clientID", c.ID),
 				zap.String("lotID", c.LotID),
 			)
-		default:
-			// If the inbound channel is full, it means handlers are not keeping up.
-			// Log an error or implement backpressure/dropping logic.
+		} else {
+			// handlers are not keeping up
 			log.Error("Hub InboundMessages channel is full, dropping message",
 				zap.String("clientID", c.ID),
 				zap.String("lotID", c.LotID),
 				zap.ByteString("message", message),
 			)
-			// Optionally send an error back to the client? Might be too late.
+			c.Hub.drop(&DroppedMessage{
+				ClientMessage: ClientMessage{Client: c, Data: message, ReceivedAt: receivedAt},
+				Reason:        DropReasonInboundFull,
+				DroppedAt:     time.Now(),
+			})
 		}
 	}
 
@@ -290,9 +567,10 @@ func (c *Client) ReadPump(ctx context.Context) {
 // / WritePump pumps messages from the hub to the websocket connection.
 // A goroutine running WritePump is started for each connection. The
 // application ensures that there is at least one writer to a connection by
-// invoking WriteControl and WriteMessage from a single goroutine.
+// invoking WriteControl and WriteMessage from a single goroutine. it returns when the hub closes Send,
+// a write fails or ctx, the context of the connection, is done
 func (c *Client) WritePump(ctx context.Context) {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.Hub.heartbeat.PingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.Hub.UnregisterClient(c)
@@ -309,6 +587,12 @@ func (c *Client) WritePump(ctx context.Context) {
 		zap.String("lotID", c.LotID),
 		zap.String("remote_addr", c.Conn.RemoteAddr().String()),
 	)
+	compression := c.Hub.compression
+	if compression.Enabled {
+		// the level is valid, withDefaults replaced the invalid ones
+		_ = c.Conn.SetCompressionLevel(compression.Level)
+	}
+	wire := c.wireCounter()
 
 	for {
 		select {
@@ -339,7 +623,7 @@ func (c *Client) WritePump(ctx context.Context) {
 					zap.String("clientID", c.ID),
 					zap.String("lotID", c.LotID),
 				)
-				err := c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				err := c.Conn.WriteMessage(websocket.CloseMessage, c.closeFrame)
 				if err != nil {
 					log.Error("Failed to write close message after channel close",
 						zap.String("clientID", c.ID),
@@ -350,6 +634,15 @@ func (c *Client) WritePump(ctx context.Context) {
 				return // Exit the goroutine
 			}
 
+			// a no-op on the connections that didn't negotiate permessage-deflate
+			compressed := compression.Enabled && len(message) >= compression.MinSize
+			c.Conn.EnableWriteCompression(compressed)
+			var wireBefore int64
+			if wire != nil {
+				wireBefore = wire.written.Load()
+			}
+			raw := len(message)
+
 			w, err := c.Conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				log.Error("Failed to get next writer for client",
@@ -378,6 +671,7 @@ func (c *Client) WritePump(ctx context.Context) {
 					break
 				}
 				w.Write(msg)
+				raw += 1 + len(msg)
 			}
 
 			if err := w.Close(); err != nil {
@@ -388,10 +682,15 @@ func (c *Client) WritePump(ctx context.Context) {
 				)
 				return // Exit the goroutine on close error
 			}
+			if wire != nil {
+				c.Hub.compressionCounters.record(int64(raw), wire.written.Load()-wireBefore, compressed)
+			}
 
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if err := c.Conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+			// the ping carries its send time, echoed by the pong to measure the round trip
+			now := time.Now()
+			if err := c.Conn.WriteControl(websocket.PingMessage, pingPayload(now), now.Add(writeWait)); err != nil {
 				log.Error("Failed to write ping message to client",
 					zap.String("clientID", c.ID),
 					zap.String("lotID", c.LotID),
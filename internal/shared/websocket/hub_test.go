@@ -0,0 +1,338 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestHub runs a hub until the end of the test
+func newTestHub(t *testing.T, shards int) *Hub {
+	t.Helper()
+	h := NewHubWithConfig(HubConfig{Shards: shards})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// the joined clients are not consumed by any handler in these tests
+	go func() {
+		for {
+			select {
+			case <-h.Joined:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return h
+}
+
+// newTestClient creates a client without connection, sendBuffer is the capacity of its Send channel
+func newTestClient(h *Hub, id, lotID, userID string, sendBuffer int) *Client {
+	return &Client{
+		Hub:    h,
+		Send:   make(chan []byte, sendBuffer),
+		LotID:  lotID,
+		ID:     id,
+		UserID: userID,
+		Role:   RoleBidder,
+	}
+}
+
+// eventually fails the test if cond is not true within a few seconds
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitClosed reads client.Send until the hub closes it
+func waitClosed(t *testing.T, client *Client) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-client.Send:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("send channel of client %s not closed", client.ID)
+		}
+	}
+}
+
+func registeredCount(h *Hub, lotIDs ...string) int {
+	total := 0
+	for _, lotID := range lotIDs {
+		spectators, bidders := h.CountByRole(lotID)
+		total += spectators + bidders
+	}
+	return total
+}
+
+// TestHubDisconnectDuringBroadcast races the broadcasts filling the send buffers, which evict the slow
+// clients, against the unregistrations and disconnections of the same clients. every Send channel must be
+// closed exactly once, a second close panics
+func TestHubDisconnectDuringBroadcast(t *testing.T) {
+	h := newTestHub(t, 2)
+	lots := []string{"lot-a", "lot-b", "lot-c"}
+
+	var clients []*Client
+	for i := range 60 {
+		client := newTestClient(h, fmt.Sprintf("client-%d", i), lots[i%len(lots)], fmt.Sprintf("user-%d", i%10), 1)
+		clients = append(clients, client)
+		h.RegisterClient(client)
+	}
+	eventually(t, "registrations", func() bool { return registeredCount(h, lots...) == len(clients) })
+
+	var wg sync.WaitGroup
+	// broadcasts overflow the one msg buffers of the clients nobody reads
+	for _, lotID := range lots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				h.BroadcastMessageToLot(lotID, []byte("update"))
+			}
+		}()
+	}
+	// half of the clients are read, like a live write pump, until the hub closes them
+	for _, client := range clients[:len(clients)/2] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range client.Send {
+			}
+		}()
+	}
+	// the pumps of every client unregister it twice (read and write pump), while the users are disconnected
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.UnregisterClient(client)
+			h.UnregisterClient(client)
+		}()
+	}
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.Disconnect(fmt.Sprintf("user-%d", i), "", "")
+		}()
+	}
+	wg.Wait()
+
+	for _, client := range clients[len(clients)/2:] {
+		waitClosed(t, client)
+	}
+	for _, client := range clients {
+		if !client.Closed() {
+			t.Errorf("client %s not closed", client.ID)
+		}
+	}
+	if n := registeredCount(h, lots...); n != 0 {
+		t.Errorf("registered clients = %d, want 0", n)
+	}
+}
+
+// TestHubEvictedClientNotRegisteredAgain checks the tombstone of a closed client: registering it again
+// must not put its closed Send channel back in the registries, where the next broadcast would panic
+func TestHubEvictedClientNotRegisteredAgain(t *testing.T) {
+	h := newTestHub(t, 1)
+	client := newTestClient(h, "client-1", "lot-a", "user-1", 4)
+
+	h.RegisterClient(client)
+	eventually(t, "registration", func() bool { return registeredCount(h, "lot-a") == 1 })
+	h.UnregisterClient(client)
+	waitClosed(t, client)
+
+	h.RegisterClient(client)
+	h.BroadcastMessageToLot("lot-a", []byte("update"))
+	h.SendToUser("user-1", []byte("private"))
+	if err := h.Alive(context.Background()); err != nil {
+		t.Fatalf("hub not alive after registering a closed client: %v", err)
+	}
+	if n := registeredCount(h, "lot-a"); n != 0 {
+		t.Errorf("registered clients = %d, want 0", n)
+	}
+}
+
+// TestHubDuplicateRegistration checks a client registered twice is counted and closed once
+func TestHubDuplicateRegistration(t *testing.T) {
+	h := newTestHub(t, 1)
+	client := newTestClient(h, "client-1", "lot-a", "user-1", 4)
+
+	h.RegisterClient(client)
+	h.RegisterClient(client)
+	eventually(t, "registration", func() bool { return client.state.Load() == clientRegistered })
+	if err := h.Alive(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := registeredCount(h, "lot-a"); n != 1 {
+		t.Fatalf("registered clients = %d, want 1", n)
+	}
+
+	h.UnregisterClient(client)
+	waitClosed(t, client)
+	eventually(t, "unregistration", func() bool { return registeredCount(h, "lot-a") == 0 })
+}
+
+// TestHubResumedClientReplacesConnection checks a reconnection with the ID of a connection still registered
+// closes the old one, greets the new one first and keeps the lot counted once
+func TestHubResumedClientReplacesConnection(t *testing.T) {
+	h := newTestHub(t, 1)
+	previous := newTestClient(h, "client-1", "lot-a", "user-1", 4)
+	h.RegisterClient(previous)
+	eventually(t, "registration", func() bool { return registeredCount(h, "lot-a") == 1 })
+
+	resumed := newTestClient(h, "client-1", "lot-a", "user-1", 4)
+	resumed.Resumed = true
+	resumed.Greeting = []byte("hello")
+	h.RegisterClient(resumed)
+	waitClosed(t, previous)
+	eventually(t, "resumed registration", func() bool { return resumed.state.Load() == clientRegistered })
+	if n := registeredCount(h, "lot-a"); n != 1 {
+		t.Fatalf("registered clients = %d, want 1", n)
+	}
+
+	h.SendToClient("client-1", []byte("private"))
+	for _, want := range []string{"hello", "private"} {
+		select {
+		case got := <-resumed.Send:
+			if string(got) != want {
+				t.Fatalf("resumed client got %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("resumed client didn't get %q", want)
+		}
+	}
+}
+
+// TestHubCloseAllDuringRegistrations races CloseAll against registrations, every client ends closed
+// whether it was registered before the close or rejected after it
+func TestHubCloseAllDuringRegistrations(t *testing.T) {
+	h := newTestHub(t, 2)
+
+	var clients []*Client
+	for i := range 40 {
+		clients = append(clients, newTestClient(h, fmt.Sprintf("client-%d", i), fmt.Sprintf("lot-%d", i%4), fmt.Sprintf("user-%d", i), 1))
+	}
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.RegisterClient(client)
+			h.BroadcastMessageToLot(client.LotID, []byte("update"))
+		}()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.CloseAll(ctx, 1012, "restart"); err != nil {
+		t.Fatalf("CloseAll: %v", err)
+	}
+	wg.Wait()
+
+	for _, client := range clients {
+		waitClosed(t, client)
+		if !client.Closed() {
+			t.Errorf("client %s not closed", client.ID)
+		}
+	}
+	late := newTestClient(h, "client-late", "lot-0", "user-late", 1)
+	h.RegisterClient(late)
+	waitClosed(t, late)
+	if len(late.closeFrame) == 0 {
+		t.Error("client registered after CloseAll closed without the close frame")
+	}
+}
+
+// TestHubTopicSubscribers checks a topic reaches its subscribers in every shard, the lot broadcasts reach
+// the subscribers of the lot topic and the subscriptions end with the connection
+func TestHubTopicSubscribers(t *testing.T) {
+	h := newTestHub(t, 4)
+	var clients []*Client
+	for i := range 8 {
+		client := newTestClient(h, fmt.Sprintf("client-%d", i), fmt.Sprintf("lot-%d", i), fmt.Sprintf("user-%d", i), 4)
+		clients = append(clients, client)
+		h.RegisterClient(client)
+		h.Subscribe(client, "lobby")
+	}
+	watcher := newTestClient(h, "watcher", "lot-x", "user-x", 4)
+	h.RegisterClient(watcher)
+	h.Subscribe(watcher, AuctionTopic("lot-0"))
+	eventually(t, "subscriptions", func() bool { return h.hasSubscribers("lobby") && h.hasSubscribers(AuctionTopic("lot-0")) })
+	if err := h.Alive(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Publish("lobby", []byte("feed"))
+	for _, client := range clients {
+		select {
+		case msg := <-client.Send:
+			if string(msg) != "feed" {
+				t.Errorf("client %s got %q, want feed", client.ID, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("client %s didn't get the topic msg", client.ID)
+		}
+	}
+	h.BroadcastMessageToLot("lot-0", []byte("update"))
+	for _, client := range []*Client{clients[0], watcher} {
+		select {
+		case msg := <-client.Send:
+			if string(msg) != "update" {
+				t.Errorf("client %s got %q, want update", client.ID, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("client %s didn't get the lot update", client.ID)
+		}
+	}
+
+	h.UnregisterClient(watcher)
+	waitClosed(t, watcher)
+	eventually(t, "lot topic unsubscription", func() bool { return !h.hasSubscribers(AuctionTopic("lot-0")) })
+	for _, client := range clients {
+		h.Unsubscribe(client, "lobby")
+	}
+	eventually(t, "lobby unsubscriptions", func() bool { return !h.hasSubscribers("lobby") })
+}
+
+// TestHubBroadcastToAll checks a msg to all reaches every connection of every lot in every shard
+func TestHubBroadcastToAll(t *testing.T) {
+	h := newTestHub(t, 4)
+	var clients []*Client
+	for i := range 8 {
+		client := newTestClient(h, fmt.Sprintf("client-%d", i), fmt.Sprintf("lot-%d", i%3), fmt.Sprintf("user-%d", i), 1)
+		clients = append(clients, client)
+		h.RegisterClient(client)
+	}
+	eventually(t, "registrations", func() bool { return registeredCount(h, "lot-0", "lot-1", "lot-2") == len(clients) })
+
+	h.BroadcastToAll([]byte("notice"))
+	for _, client := range clients {
+		select {
+		case msg := <-client.Send:
+			if string(msg) != "notice" {
+				t.Errorf("client %s got %q, want notice", client.ID, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("client %s didn't get the msg to all", client.ID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"go.uber.org/zap"
+)
+
+// InboundConfig sets the checks of the msgs read from the clients, made by the ReadPumps before the msgs
+// reach InboundMessages
+type InboundConfig struct {
+	// MaxMessageSize is the read limit of the connections, a larger msg closes the connection with
+	// CloseMessageTooBig. 0 uses 512 bytes
+	MaxMessageSize int
+	// Validate rejects the malformed msgs, nil accepts them all
+	Validate func(data []byte) error
+	// Rejection is the reply sent to the client for a rejected msg, nil sends nothing
+	Rejection func(err error) []byte
+	// MaxMalformed closes the connection with ClosePolicyViolation once it sent that many rejected msgs,
+	// 0 never closes it
+	MaxMalformed int
+}
+
+func (c InboundConfig) withDefaults() InboundConfig {
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = maxMessageSize
+	}
+	return c
+}
+
+// inboundCounters are the totals of the rejected msgs, written by the ReadPumps
+type inboundCounters struct {
+	malformed       atomic.Int64
+	closedMalformed atomic.Int64
+}
+
+// validateInbound checks a msg read from the client, ok is false if the msg must not be handled and
+// closeConn is true once the connection sent too many malformed msgs, its close frame was already sent
+func (c *Client) validateInbound(message []byte) (ok, closeConn bool) {
+	inbound := c.Hub.inbound
+	if inbound.Validate == nil {
+		return true, false
+	}
+	err := inbound.Validate(message)
+	if err == nil {
+		return true, false
+	}
+	c.malformed++
+	c.Hub.inboundCounters.malformed.Add(1)
+	log.Warn("Malformed message from client",
+		zap.String("clientID", c.ID),
+		zap.String("lotID", c.LotID),
+		zap.Int("malformed", c.malformed),
+		zap.Error(err),
+	)
+	if inbound.MaxMalformed > 0 && c.malformed >= inbound.MaxMalformed {
+		c.Hub.inboundCounters.closedMalformed.Add(1)
+		closeFrame := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many malformed messages")
+		if err := c.Conn.WriteControl(websocket.CloseMessage, closeFrame, time.Now().Add(writeWait)); err != nil {
+			log.Error("Failed to send close control message",
+				zap.String("clientID", c.ID),
+				zap.String("lotID", c.LotID),
+				zap.Error(err),
+			)
+		}
+		return false, true
+	}
+	if inbound.Rejection != nil {
+		if reply := inbound.Rejection(err); reply != nil {
+			c.Hub.SendToClient(c.ID, reply)
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fasthttpws "github.com/fasthttp/websocket"
+)
+
+func TestReadPumpClosesAfterMalformedMessages(t *testing.T) {
+	h := runTestHub(t, HubConfig{Shards: 1, Inbound: InboundConfig{
+		Validate: func(data []byte) error {
+			if string(data) != "ok" {
+				return errors.New("malformed")
+			}
+			return nil
+		},
+		Rejection:    func(err error) []byte { return []byte(err.Error()) },
+		MaxMalformed: 2,
+	}})
+	conn := dialTestHub(t, h, "lot-1")
+
+	if err := conn.WriteMessage(fasthttpws.TextMessage, []byte("ok")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-h.InboundMessages:
+		if string(msg.Data) != "ok" {
+			t.Fatalf("inbound msg = %q, want ok", msg.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("valid msg not handed to the handlers")
+	}
+
+	if err := conn.WriteMessage(fasthttpws.TextMessage, []byte("bad")); err != nil {
+		t.Fatal(err)
+	}
+	_, reply, err := conn.ReadMessage()
+	if err != nil || string(reply) != "malformed" {
+		t.Fatalf("reply = %q, %v, want the rejection", reply, err)
+	}
+	if err := conn.WriteMessage(fasthttpws.TextMessage, []byte("bad")); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = conn.ReadMessage()
+	if !fasthttpws.IsCloseError(err, fasthttpws.ClosePolicyViolation) {
+		t.Fatalf("read error = %v, want a policy violation close", err)
+	}
+	if len(h.InboundMessages) != 0 {
+		t.Fatalf("%d malformed msgs reached the handlers", len(h.InboundMessages))
+	}
+	if got := h.inboundCounters.malformed.Load(); got != 2 {
+		t.Fatalf("malformed = %d, want 2", got)
+	}
+	if got := h.inboundCounters.closedMalformed.Load(); got != 1 {
+		t.Fatalf("closed malformed = %d, want 1", got)
+	}
+}
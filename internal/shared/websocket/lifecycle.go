@@ -0,0 +1,60 @@
+package websocket
+
+// lifecycle states of a Client, a client only moves forward: pending -> registered -> closed.
+// closed is a tombstone, the client is out of every registry of its shard, its Send channel is closed
+// and it's never registered again. the states are only changed by the run loop of the shard owning the
+// client, which is also the only sender on Send and the only one closing it
+const (
+	clientPending int32 = iota
+	clientRegistered
+	clientClosed
+)
+
+// Closed reports if the hub closed the client, its Send channel is closed or about to be
+func (c *Client) Closed() bool {
+	return c.state.Load() == clientClosed
+}
+
+// closeSend tombstones client and closes its Send channel, once: a client already closed is left as
+// is. must be called from the run loop of the shard owning the client
+func (s *hubShard) closeSend(client *Client) bool {
+	if client.state.Swap(clientClosed) == clientClosed {
+		return false
+	}
+	close(client.Send)
+	return true
+}
+
+// evict removes a registered client from the registries of the shard and closes it, it's the single
+// way out of the registries: unregistrations, full send buffers, stale reaping, disconnections and
+// shutdowns all go through it. it returns false when client is not registered, e.g. already evicted.
+// must be called from the run loop
+func (s *hubShard) evict(client *Client) bool {
+	if client.state.Load() != clientRegistered {
+		return false
+	}
+	if clients, ok := s.clients[client.LotID]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(s.clients, client.LotID)
+		}
+	}
+	for topic := range client.topics {
+		s.unsubscribe(client, topic)
+	}
+	s.unindex(client)
+	s.hub.adjustCount(client, -1)
+	return s.closeSend(client)
+}
+
+// remoteAddr returns the peer address of the connection, empty for a client without one. RemoteIP is
+// preferred: the shards log it after the handler of the connection may have returned and released the conn
+func (c *Client) remoteAddr() string {
+	if c.RemoteIP != "" {
+		return c.RemoteIP
+	}
+	if c.Conn == nil || c.Conn.Conn == nil {
+		return ""
+	}
+	return c.Conn.RemoteAddr().String()
+}
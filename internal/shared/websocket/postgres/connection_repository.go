@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ConnectionRepository implements websocket.ConnectionLog interface over the connections table
+type ConnectionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewConnectionRepository creates a new instance of ConnectionRepository
+func NewConnectionRepository(pool *pgxpool.Pool) *ConnectionRepository {
+	return &ConnectionRepository{pool: pool}
+}
+
+// Opened implements websocket.ConnectionLog
+func (r *ConnectionRepository) Opened(ctx context.Context, client *websocket.Client) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO connections (client_id, user_id, org_id, lot_id, role, remote_ip, user_agent, protocol, resumed, connected_at)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, '')::uuid, $4, $5, $6, $7, $8, $9, $10)`,
+		client.ID, client.UserID, client.OrgID, client.LotID, string(client.Role), client.RemoteIP,
+		client.UserAgent, client.Protocol, client.Resumed, client.ConnectedAt,
+	)
+	return err
+}
+
+// Closed implements websocket.ConnectionLog
+func (r *ConnectionRepository) Closed(ctx context.Context, client *websocket.Client, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE connections SET disconnected_at = $3
+		WHERE client_id = $1 AND connected_at = $2 AND disconnected_at IS NULL`,
+		client.ID, client.ConnectedAt, at,
+	)
+	return err
+}
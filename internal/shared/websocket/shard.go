@@ -0,0 +1,320 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// hubShard owns the clients of the lots hashed to it, its registries are only touched by its run loop
+type hubShard struct {
+	hub *Hub
+	id  int
+	// Registered clients, grouped by lot ID.
+	// The keys of the outer map are lot IDs.
+	// The inner map keys are clients, and the boolean value is ignored.
+	clients map[string]map[*Client]bool
+	// Registered clients indexed by client ID
+	byClient map[string]*Client
+	// Registered authenticated clients indexed by user ID, a user may have several connections
+	byUser map[string]map[*Client]bool
+	// Topic subscribers of the shard clients, by topic
+	topics map[string]map[*Client]bool
+	// Inbound messages from the clien
+	broadcast chan *Message
+	// Messages addressed to a single client or to the connections of a single user
+	direct chan *DirectMessage
+	// Register requests from the clients.
+	register chan *Client
+	// Unregister requests from clients.
+	unregister chan *Client
+	// Topic subscription changes of the shard clients
+	subscriptions chan *subscription
+	// Liveness probes, answered by the run loop to prove it is not stuck
+	ping chan chan struct{}
+	// Diagnostics snapshot requests, answered by the run loop which owns the registries
+	stats chan chan *shardStats
+	// demographics requests of the lots of the shard, answered by the run loop
+	demographics chan *demographicsRequest
+	// requests to close every connection, answered once they are closed
+	closeAll chan chan struct{}
+	// connections reaped as stale since start, written by run
+	reapedStale int64
+
+	// occupancy and contention of the channels filled by the Hub methods
+	registerGauge   channelGauge
+	unregisterGauge channelGauge
+	broadcastGauge  channelGauge
+	directGauge     channelGauge
+	subscribeGauge  channelGauge
+}
+
+func newHubShard(hub *Hub, id int, channels ChannelConfig) *hubShard {
+	return &hubShard{
+		hub:           hub,
+		id:            id,
+		clients:       make(map[string]map[*Client]bool),
+		byClient:      make(map[string]*Client),
+		byUser:        make(map[string]map[*Client]bool),
+		topics:        make(map[string]map[*Client]bool),
+		broadcast:     make(chan *Message, channels.BroadcastBuffer),
+		direct:        make(chan *DirectMessage, channels.DirectBuffer),
+		register:      make(chan *Client, channels.RegisterBuffer),
+		unregister:    make(chan *Client, channels.UnregisterBuffer),
+		subscriptions: make(chan *subscription, channels.RegisterBuffer),
+		ping:          make(chan chan struct{}),
+		stats:         make(chan chan *shardStats),
+		demographics:  make(chan *demographicsRequest),
+		closeAll:      make(chan chan struct{}),
+	}
+}
+
+// run listens in the shard channels until ctx is done
+func (s *hubShard) run(ctx context.Context) {
+	// a nil channel never fires, so without StaleAfter nothing is reaped
+	var reap <-chan time.Time
+	if s.hub.heartbeat.StaleAfter > 0 {
+		ticker := time.NewTicker(s.hub.heartbeat.ReapInterval)
+		defer ticker.Stop()
+		reap = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done(): // <-- Check context cancellation
+			// TODO: Consider graceful shutdown of clients
+			return // Exit the goroutine
+		case reply := <-s.ping:
+			close(reply)
+		case reply := <-s.stats:
+			reply <- s.snapshot()
+		case req := <-s.demographics:
+			req.reply <- s.lotDemographics(req.lotID, time.Now())
+		case reply := <-s.closeAll:
+			s.closeClients()
+			close(reply)
+		case now := <-reap:
+			s.reapStale(now)
+		case client := <-s.register:
+			s.add(client)
+		case client := <-s.unregister:
+			// with buffered channels the select may pick the unregister before the register queued before
+			// it, the queued registrations are applied first so the unregister always finds its client
+			s.drainRegistrations()
+			s.remove(client)
+		case sub := <-s.subscriptions:
+			s.applySubscription(sub)
+
+		case message := <-s.broadcast:
+			//broadcast the message to all the clients in LotID group, and to the subscribers of the topic
+			if clients, ok := s.clients[message.LotID]; ok {
+				log.Debug("Broadcasting message to lot", zap.String("LotID", message.LotID), zap.Int("clients", len(clients)))
+				s.deliver(clients, message)
+			}
+			if subscribers, ok := s.topics[message.LotID]; ok {
+				log.Debug("Broadcasting message to topic", zap.String("topic", message.LotID), zap.Int("clients", len(subscribers)))
+				s.deliver(subscribers, message)
+			}
+
+		case message := <-s.direct:
+			if message.Disconnect {
+				s.disconnect(message)
+				continue
+			}
+			// a full buffer only skips that connection, private msgs never unregister clients
+			if message.All {
+				for _, client := range s.byClient {
+					s.sendDirect(client, message.Data)
+				}
+				continue
+			}
+			if message.ClientID != "" {
+				if client, ok := s.byClient[message.ClientID]; ok {
+					s.sendDirect(client, message.Data)
+				}
+			}
+			if message.UserID != "" {
+				for client := range s.byUser[message.UserID] {
+					s.sendDirect(client, message.Data)
+				}
+			}
+		}
+	}
+}
+
+// add registers a client in its lot group, must be called from the run loop
+func (s *hubShard) add(client *Client) {
+	// a client is registered once, a duplicate registration or one of an evicted client is ignored
+	if client.state.Load() != clientPending {
+		log.Warn("Client registration ignored, already registered or closed", zap.String("clientID", client.ID))
+		return
+	}
+	// a closing hub closes the connections instead of registering them
+	if frame := s.hub.closing.Load(); frame != nil {
+		client.closeFrame = *frame
+		s.closeSend(client)
+		log.Info("Client rejected, hub closing", zap.String("clientID", client.ID), zap.String("lotID", client.LotID))
+		return
+	}
+	// a resumed client replaces its previous connection if the server didn't notice it was lost yet,
+	// both are on the lot so on this shard
+	if previous, ok := s.byClient[client.ID]; ok && s.evict(previous) {
+		log.Info("Client connection replaced by its reconnection", zap.String("clientID", client.ID), zap.String("lotID", client.LotID))
+	}
+	client.state.Store(clientRegistered)
+	// the silence of a client is measured from its registration until the first pong
+	client.lastPong.CompareAndSwap(0, time.Now().UnixNano())
+	// Register the client in lotId group
+	if _, ok := s.clients[client.LotID]; !ok {
+		s.clients[client.LotID] = make(map[*Client]bool)
+	}
+	s.clients[client.LotID][client] = true
+	s.index(client)
+	s.hub.adjustCount(client, 1)
+	if client.Greeting != nil {
+		s.sendDirect(client, client.Greeting)
+	}
+	// once registered the client receives live msgs, handlers may now send it the initial state
+	select {
+	case s.hub.Joined <- client:
+	default:
+		log.Warn("Joined channel is full, initial state skipped", zap.String("clientID", client.ID))
+	}
+	log.Info("Client registered",
+		zap.String("clientID", client.ID),
+		zap.String("LotID", client.LotID),
+		zap.String("remote_addr", client.remoteAddr()),
+		zap.Int("shard", s.id),
+		zap.Int("shard_clients", func() int {
+			count := 0
+			for _, lotClients := range s.clients {
+				count += len(lotClients)
+			}
+			return count
+		}()),
+	)
+}
+
+// remove unregisters a client from its lot group, must be called from the run loop
+func (s *hubShard) remove(client *Client) {
+	if !s.evict(client) {
+		return
+	}
+	log.Info("Client unregistered",
+		zap.String("clientID", client.ID),
+		zap.String("lotID", client.LotID),
+		zap.String("remote_addr", client.remoteAddr()),
+		zap.Int("shard", s.id),
+		zap.Int("shard_clients", func() int { // Log total clients of the shard
+			count := 0
+			for _, lotClients := range s.clients {
+				count += len(lotClients)
+			}
+			return count
+		}()),
+	)
+}
+
+// disconnect closes the connections of the user of message matching its org and session, must be
+// called from the run loop
+func (s *hubShard) disconnect(message *DirectMessage) {
+	// queued registrations are applied first, so the connections they add are closed too
+	s.drainRegistrations()
+	for client := range s.byUser[message.UserID] {
+		if (message.OrgID != "" && client.OrgID != message.OrgID) || (message.SessionID != "" && client.SessionID != message.SessionID) {
+			continue
+		}
+		log.Info("Disconnecting client", zap.String("clientID", client.ID), zap.String("userID", client.UserID))
+		// closing Send makes the write pump send a close frame and end the connection
+		s.remove(client)
+	}
+}
+
+// closeClients closes every connection of the shard with the close frame of the hub, must be called
+// from the run loop
+func (s *hubShard) closeClients() {
+	// queued registrations are closed too
+	s.drainRegistrations()
+	frame := s.hub.closing.Load()
+	closed := 0
+	for _, clients := range s.clients {
+		for client := range clients {
+			if frame != nil {
+				client.closeFrame = *frame
+			}
+			// closing Send makes the write pump send the close frame and end the connection
+			s.remove(client)
+			closed++
+		}
+	}
+	log.Info("Shard connections closed", zap.Int("shard", s.id), zap.Int("clients", closed))
+}
+
+// drainRegistrations applies the registrations waiting in the register channel, must be called from the run loop
+func (s *hubShard) drainRegistrations() {
+	for {
+		select {
+		case client := <-s.register:
+			s.add(client)
+		default:
+			return
+		}
+	}
+}
+
+// sendDirect tries to deliver a private msg to client without blocking the run loop
+func (s *hubShard) sendDirect(client *Client, data []byte) {
+	select {
+	case client.Send <- data:
+	default:
+		log.Warn("Failed to Send direct message to client, send buffer full",
+			zap.String("clientID", client.ID),
+			zap.String("userID", client.UserID),
+		)
+	}
+}
+
+// index adds a client to the client and user indexes, must be called from the run loop
+func (s *hubShard) index(client *Client) {
+	s.byClient[client.ID] = client
+	s.hub.clientShards.Store(client.ID, s)
+	if client.UserID == "" {
+		return
+	}
+	if _, ok := s.byUser[client.UserID]; !ok {
+		s.byUser[client.UserID] = make(map[*Client]bool)
+	}
+	s.byUser[client.UserID][client] = true
+}
+
+// unindex removes a client from the client and user indexes, must be called from the run loop
+func (s *hubShard) unindex(client *Client) {
+	if s.byClient[client.ID] == client {
+		delete(s.byClient, client.ID)
+		s.hub.clientShards.CompareAndDelete(client.ID, s)
+	}
+	if conns, ok := s.byUser[client.UserID]; ok {
+		delete(conns, client)
+		if len(conns) == 0 {
+			delete(s.byUser, client.UserID)
+		}
+	}
+}
+
+// deliver sends a broadcast msg to clients without blocking the run loop, must be called from the run loop
+func (s *hubShard) deliver(clients map[*Client]bool, message *Message) {
+	for client := range clients {
+		select {
+		case client.Send <- message.Data:
+			// message sended
+		default:
+			// the client is not keeping up, probably disconnected: it's evicted and its connection closed
+			s.evict(client)
+			log.Warn("Failed to Send message to client, unregistering",
+				zap.String("clientID", client.ID), // Use client.ID
+				zap.String("lotID", client.LotID),
+				zap.String("remote_addr", client.remoteAddr()),
+			)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package websocket
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// HubStats is a diagnostics snapshot of the hub, used to find leaks and slow consumers under load
+type HubStats struct {
+	Clients  int                     `json:"clients"`
+	Users    int                     `json:"users"` // authenticated users with at least one connection
+	Lots     []LotStats              `json:"lots"`  // ordered by clients, busiest first
+	Channels map[string]ChannelDepth `json:"channels"`
+	// Shards are the per shard registries and channels, ordered by index. Channels only has the
+	// channels shared by all the shards
+	Shards []ShardStats `json:"shards"`
+	// ReapedStale is the number of connections reaped for missing their pongs since start
+	ReapedStale int64 `json:"reaped_stale"`
+	// MalformedMessages is the number of inbound msgs rejected by the validation since start, and
+	// ClosedMalformed the connections closed for sending too many of them
+	MalformedMessages int64 `json:"malformed_messages"`
+	ClosedMalformed   int64 `json:"closed_malformed"`
+	// Compression compares the written msgs with the bytes they took on the wire
+	Compression CompressionStats `json:"compression"`
+}
+
+// LotStats are the connections of a lot and the depth of their send buffers
+type LotStats struct {
+	LotID      string `json:"lot_id"`
+	Clients    int    `json:"clients"`
+	Spectators int    `json:"spectators"`
+	Bidders    int    `json:"bidders"`
+	// QueuedMessages is the sum of the msgs waiting in the send buffers of the lot clients,
+	// a growing value means the WritePumps can't keep up
+	QueuedMessages int `json:"queued_messages"`
+	MaxQueueDepth  int `json:"max_queue_depth"`
+	// Heartbeat is the connectivity of the lot viewers, from their ping/pong round trips
+	Heartbeat LotHeartbeatStats `json:"heartbeat"`
+}
+
+// ChannelDepth is the number of buffered elements of a channel and its capacity. the counters of the
+// channels filled by senders waiting on them (see ChannelConfig) are totals since start
+type ChannelDepth struct {
+	Len       int   `json:"len"`
+	Cap       int   `json:"cap"`
+	HighWater int   `json:"high_water"` // max buffered elements seen after a send
+	Blocked   int64 `json:"blocked"`    // sends that found the channel full and had to wait
+	TimedOut  int64 `json:"timed_out"`  // sends dropped after waiting EnqueueTimeout
+}
+
+// ShardStats are the connections and channel depths of a hub shard, an unbalanced shard points to a hot lot
+type ShardStats struct {
+	Index    int                     `json:"index"`
+	Clients  int                     `json:"clients"`
+	Lots     int                     `json:"lots"`
+	Channels map[string]ChannelDepth `json:"channels"`
+}
+
+// shardStats is the snapshot of a single shard, merged by Stats into the HubStats
+type shardStats struct {
+	ShardStats
+	lots        []LotStats
+	users       map[string]struct{}
+	reapedStale int64
+}
+
+// Stats returns a snapshot of the hub, it fails with ErrHubNotRunning if the run loop of a shard
+// does not answer before ctx is done
+func (h *Hub) Stats(ctx context.Context) (*HubStats, error) {
+	stats := &HubStats{
+		Lots: []LotStats{},
+		Channels: map[string]ChannelDepth{
+			"inbound_messages": h.inboundGauge.depth(len(h.InboundMessages), cap(h.InboundMessages)),
+			"joined":           {Len: len(h.Joined), Cap: cap(h.Joined)},
+			"dropped":          {Len: len(h.Dropped), Cap: cap(h.Dropped)},
+		},
+		Shards:            make([]ShardStats, 0, len(h.shards)),
+		MalformedMessages: h.inboundCounters.malformed.Load(),
+		ClosedMalformed:   h.inboundCounters.closedMalformed.Load(),
+		Compression:       h.compressionCounters.stats(h.compression),
+	}
+	users := make(map[string]struct{})
+	for _, shard := range h.shards {
+		reply := make(chan *shardStats, 1)
+		select {
+		case shard.stats <- reply:
+		case <-ctx.Done():
+			return nil, ErrHubNotRunning
+		}
+		var snapshot *shardStats
+		select {
+		case snapshot = <-reply:
+		case <-ctx.Done():
+			return nil, ErrHubNotRunning
+		}
+		stats.Clients += snapshot.Clients
+		stats.Lots = append(stats.Lots, snapshot.lots...)
+		stats.ReapedStale += snapshot.reapedStale
+		stats.Shards = append(stats.Shards, snapshot.ShardStats)
+		for userID := range snapshot.users {
+			users[userID] = struct{}{}
+		}
+	}
+	stats.Users = len(users)
+	sort.Slice(stats.Lots, func(i, j int) bool { return stats.Lots[i].Clients > stats.Lots[j].Clients })
+	return stats, nil
+}
+
+// snapshot builds the shardStats, must be called from the shard run loop
+func (s *hubShard) snapshot() *shardStats {
+	now := time.Now()
+	stats := &shardStats{
+		ShardStats: ShardStats{
+			Index: s.id,
+			Lots:  len(s.clients),
+			Channels: map[string]ChannelDepth{
+				"broadcast":  s.broadcastGauge.depth(len(s.broadcast), cap(s.broadcast)),
+				"direct":     s.directGauge.depth(len(s.direct), cap(s.direct)),
+				"register":   s.registerGauge.depth(len(s.register), cap(s.register)),
+				"unregister": s.unregisterGauge.depth(len(s.unregister), cap(s.unregister)),
+				"subscribe":  s.subscribeGauge.depth(len(s.subscriptions), cap(s.subscriptions)),
+			},
+		},
+		lots:        make([]LotStats, 0, len(s.clients)),
+		users:       make(map[string]struct{}, len(s.byUser)),
+		reapedStale: s.reapedStale,
+	}
+	for userID := range s.byUser {
+		stats.users[userID] = struct{}{}
+	}
+	for lotID, clients := range s.clients {
+		lot := LotStats{LotID: lotID, Clients: len(clients), Heartbeat: s.heartbeatStats(clients, now)}
+		for client := range clients {
+			switch client.Role {
+			case RoleBidder:
+				lot.Bidders++
+			default:
+				lot.Spectators++
+			}
+			depth := len(client.Send)
+			lot.QueuedMessages += depth
+			lot.MaxQueueDepth = max(lot.MaxQueueDepth, depth)
+		}
+		stats.Clients += lot.Clients
+		stats.lots = append(stats.lots, lot)
+	}
+	return stats
+}
@@ -0,0 +1,146 @@
+package websocket
+
+import (
+	"strings"
+
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"go.uber.org/zap"
+)
+
+// maxClientTopics bounds the topics a connection can subscribe to, the subscriptions over it are ignored
+const maxClientTopics = 32
+
+// subscription is a request to add or remove a client from the subscribers of a topic
+type subscription struct {
+	client    *Client
+	topic     string
+	subscribe bool
+}
+
+// ScopedTopic returns the hub key of topic for the connections of the organization orgID, the
+// anonymous connections (empty orgID) use the topic itself. feeds listing the lots of every organization
+// publish to the topic of each organization and to the unscoped one
+func ScopedTopic(topic, orgID string) string {
+	if orgID == "" {
+		return topic
+	}
+	return topic + "@" + orgID
+}
+
+// AuctionTopic returns the topic of the updates of a lot, broadcast to the lot room and to its subscribers
+func AuctionTopic(lotID string) string {
+	return wsproto.TopicAuctionPrefix + lotID
+}
+
+// LobbyRoom returns the room of the lobby connections of the organization orgID, the same key as the
+// lobby topic of the organization so the lobby feeds reach both the lobby connections and the subscribers
+func LobbyRoom(orgID string) string {
+	return ScopedTopic(wsproto.TopicLobby, orgID)
+}
+
+// IsLobbyRoom reports if the room of a client is a lobby room instead of a lot
+func IsLobbyRoom(room string) bool {
+	topic, _, _ := strings.Cut(room, "@")
+	return topic == wsproto.TopicLobby
+}
+
+// Subscribe adds client to the subscribers of topic, besides its lot room. a topic reaches its
+// subscribers in every shard, the subscription ends with the connection
+func (h *Hub) Subscribe(client *Client, topic string) {
+	h.subscribe(&subscription{client: client, topic: topic, subscribe: true})
+}
+
+// Unsubscribe removes client from the subscribers of topic
+func (h *Hub) Unsubscribe(client *Client, topic string) {
+	h.subscribe(&subscription{client: client, topic: topic})
+}
+
+func (h *Hub) subscribe(sub *subscription) {
+	shard := h.shardFor(sub.client.LotID)
+	if enqueue(shard.subscriptions, sub, h.enqueueTimeout, &shard.subscribeGauge) {
+		return
+	}
+	log.Error("Subscription channel is full, subscription dropped",
+		zap.String("clientID", sub.client.ID),
+		zap.String("topic", sub.topic),
+		zap.Duration("timeout", h.enqueueTimeout),
+	)
+}
+
+// Publish sends a msg to the clients of the topic room and to the topic subscribers, whatever their
+// lot. the msg is dropped in the shards whose broadcast channel stays full for the enqueue timeout
+func (h *Hub) Publish(topic string, data []byte) {
+	for _, shard := range h.shards {
+		if !enqueue(shard.broadcast, &Message{LotID: topic, Data: data}, h.enqueueTimeout, &shard.broadcastGauge) {
+			log.Error("Broadcast channel is full, topic message dropped",
+				zap.String("topic", topic),
+				zap.Int("shard", shard.id),
+				zap.Duration("timeout", h.enqueueTimeout),
+			)
+		}
+	}
+}
+
+// hasSubscribers reports if any client is subscribed to topic, readable from any goroutine
+func (h *Hub) hasSubscribers(topic string) bool {
+	h.topicsMu.RLock()
+	defer h.topicsMu.RUnlock()
+	return h.topicCounts[topic] > 0
+}
+
+// adjustTopicCount updates the subscribers count of topic, must be called from the run loop of a shard
+func (h *Hub) adjustTopicCount(topic string, delta int) {
+	h.topicsMu.Lock()
+	defer h.topicsMu.Unlock()
+	h.topicCounts[topic] += delta
+	if h.topicCounts[topic] <= 0 {
+		delete(h.topicCounts, topic)
+	}
+}
+
+// applySubscription adds or removes the client of sub from the topic subscribers, must be called from the run loop
+func (s *hubShard) applySubscription(sub *subscription) {
+	client := sub.client
+	// the subscriptions of a client not registered yet are applied after its registration
+	s.drainRegistrations()
+	if client.state.Load() != clientRegistered {
+		return
+	}
+	if !sub.subscribe {
+		s.unsubscribe(client, sub.topic)
+		return
+	}
+	// the clients of the topic room already get its msgs
+	if _, ok := client.topics[sub.topic]; ok || sub.topic == client.LotID {
+		return
+	}
+	if len(client.topics) >= maxClientTopics {
+		log.Warn("Subscription ignored, too many topics", zap.String("clientID", client.ID), zap.String("topic", sub.topic))
+		return
+	}
+	if client.topics == nil {
+		client.topics = make(map[string]struct{})
+	}
+	client.topics[sub.topic] = struct{}{}
+	if _, ok := s.topics[sub.topic]; !ok {
+		s.topics[sub.topic] = make(map[*Client]bool)
+	}
+	s.topics[sub.topic][client] = true
+	s.hub.adjustTopicCount(sub.topic, 1)
+	log.Debug("Client subscribed", zap.String("clientID", client.ID), zap.String("topic", sub.topic))
+}
+
+// unsubscribe removes client from the subscribers of topic, must be called from the run loop
+func (s *hubShard) unsubscribe(client *Client, topic string) {
+	if _, ok := client.topics[topic]; !ok {
+		return
+	}
+	delete(client.topics, topic)
+	if subscribers, ok := s.topics[topic]; ok {
+		delete(subscribers, client)
+		if len(subscribers) == 0 {
+			delete(s.topics, topic)
+		}
+	}
+	s.hub.adjustTopicCount(topic, -1)
+}
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRequestsLimit = 50
+	maxRequestsLimit     = 200
+)
+
+// TokenRevoker revokes every token and session of a user and closes their live connections, implemented
+// by the sessions module
+type TokenRevoker func(ctx context.Context, userID uuid.UUID) error
+
+// ErasureRequestDTO is the output DTO of an erasure request
+type ErasureRequestDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	RequestedBy uuid.UUID  `json:"requested_by"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewNote  string     `json:"review_note,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	TombstoneID *uuid.UUID `json:"tombstone_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ReviewErasureDTO is the input of DataRequestsUseCase.Approve and Reject
+type ReviewErasureDTO struct {
+	RequestID  uuid.UUID
+	ReviewerID uuid.UUID
+	Note       string
+}
+
+// DataRequestsUseCase runs the data subject requests of the users: the export of their data and the
+// erasure workflow, where a recorded request is approved (erasing the data) or rejected by an admin
+type DataRequestsUseCase struct {
+	users    domain.UserRepository
+	data     domain.UserDataRepository
+	requests domain.ErasureRequestRepository
+	revoke   TokenRevoker
+}
+
+// NewDataRequestsUseCase creates a new instance of DataRequestsUseCase
+func NewDataRequestsUseCase(users domain.UserRepository, data domain.UserDataRepository,
+	requests domain.ErasureRequestRepository, revoke TokenRevoker) *DataRequestsUseCase {
+	return &DataRequestsUseCase{users: users, data: data, requests: requests, revoke: revoke}
+}
+
+// Export returns all the data tied to the user
+func (uc *DataRequestsUseCase) Export(ctx context.Context, userID uuid.UUID) (*domain.UserData, error) {
+	data, err := uc.data.Export(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to export data of user %s: %w", userID, err)
+	}
+	logger.FromContext(ctx).Info("User data exported", zap.String("userID", userID.String()))
+	return data, nil
+}
+
+// RequestErasure records a pending erasure request of the user, nothing is erased until it's approved
+func (uc *DataRequestsUseCase) RequestErasure(ctx context.Context, userID, requestedBy uuid.UUID, reason string) (*ErasureRequestDTO, error) {
+	user, err := uc.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to get user %s: %w", userID, err)
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	request := domain.NewErasureRequest(userID, requestedBy, strings.TrimSpace(reason))
+	if err := uc.requests.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to create erasure request: %w", err)
+	}
+	logger.FromContext(ctx).Info("Erasure requested",
+		zap.String("requestID", request.ID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("requestedBy", requestedBy.String()),
+	)
+	dto := toErasureRequestDTO(request)
+	return &dto, nil
+}
+
+// List returns a page of erasure requests, newest first, status filters them when not empty
+func (uc *DataRequestsUseCase) List(ctx context.Context, status string, limit, offset int) ([]ErasureRequestDTO, error) {
+	switch domain.ErasureStatus(status) {
+	case "", domain.ErasurePending, domain.ErasureCompleted, domain.ErasureRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidErasureStatus, status)
+	}
+	if limit <= 0 {
+		limit = defaultRequestsLimit
+	}
+	requests, err := uc.requests.List(ctx, domain.ErasureStatus(status), min(limit, maxRequestsLimit), max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to list erasure requests: %w", err)
+	}
+	dtos := make([]ErasureRequestDTO, 0, len(requests))
+	for _, request := range requests {
+		dtos = append(dtos, toErasureRequestDTO(request))
+	}
+	return dtos, nil
+}
+
+// Approve erases the data of the user of a pending request: their tokens are revoked first, then their
+// bids and the other records the auctions depend on are reattributed to a new tombstone user and the
+// personal fields are cleared
+func (uc *DataRequestsUseCase) Approve(ctx context.Context, cmd ReviewErasureDTO) (*ErasureRequestDTO, error) {
+	request, err := uc.requests.GetByID(ctx, cmd.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to get erasure request %s: %w", cmd.RequestID, err)
+	}
+	if request.Status != domain.ErasurePending {
+		return nil, fmt.Errorf("data requests use case: %w", domain.ErrErasureRequestAlreadyClosed)
+	}
+	if err := uc.revoke(ctx, request.UserID); err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to revoke tokens of user %s: %w", request.UserID, err)
+	}
+	tombstoneID := uuid.New()
+	if err := uc.data.Erase(ctx, request.UserID, tombstoneID); err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to erase data of user %s: %w", request.UserID, err)
+	}
+	if err := request.Complete(cmd.ReviewerID, tombstoneID, strings.TrimSpace(cmd.Note), time.Now()); err != nil {
+		return nil, fmt.Errorf("data requests use case: %w", err)
+	}
+	if err := uc.requests.SaveReview(ctx, request); err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to save review of erasure request %s: %w", request.ID, err)
+	}
+	logger.FromContext(ctx).Info("User data erased",
+		zap.String("requestID", request.ID.String()),
+		zap.String("tombstoneID", tombstoneID.String()),
+		zap.String("reviewerID", cmd.ReviewerID.String()),
+	)
+	dto := toErasureRequestDTO(request)
+	return &dto, nil
+}
+
+// Reject closes a pending request without erasing anything
+func (uc *DataRequestsUseCase) Reject(ctx context.Context, cmd ReviewErasureDTO) (*ErasureRequestDTO, error) {
+	request, err := uc.requests.GetByID(ctx, cmd.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to get erasure request %s: %w", cmd.RequestID, err)
+	}
+	if err := request.Reject(cmd.ReviewerID, strings.TrimSpace(cmd.Note), time.Now()); err != nil {
+		return nil, fmt.Errorf("data requests use case: %w", err)
+	}
+	if err := uc.requests.SaveReview(ctx, request); err != nil {
+		return nil, fmt.Errorf("data requests use case: failed to save review of erasure request %s: %w", request.ID, err)
+	}
+	logger.FromContext(ctx).Info("Erasure request rejected",
+		zap.String("requestID", request.ID.String()),
+		zap.String("reviewerID", cmd.ReviewerID.String()),
+	)
+	dto := toErasureRequestDTO(request)
+	return &dto, nil
+}
+
+func toErasureRequestDTO(r *domain.ErasureRequest) ErasureRequestDTO {
+	return ErasureRequestDTO{
+		ID:          r.ID,
+		UserID:      r.UserID,
+		RequestedBy: r.RequestedBy,
+		Reason:      r.Reason,
+		Status:      string(r.Status),
+		ReviewedBy:  r.ReviewedBy,
+		ReviewNote:  r.ReviewNote,
+		ReviewedAt:  r.ReviewedAt,
+		TombstoneID: r.TombstoneID,
+		CreatedAt:   r.CreatedAt,
+	}
+}
@@ -0,0 +1,6 @@
+package application
+
+import "errors"
+
+// ErrInvalidErasureStatus is returned when listing the erasure requests with an unknown status
+var ErrInvalidErasureStatus = errors.New("invalid erasure request status")
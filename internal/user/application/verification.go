@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidVerification is returned when a KYC provider reports an unknown status or no user
+var ErrInvalidVerification = errors.New("invalid user verification")
+
+// maxProviderLength and maxReferenceLength bound the provider fields, the size of their columns
+const (
+	maxProviderLength  = 64
+	maxReferenceLength = 255
+)
+
+// UpdateVerificationDTO is the status of a user reported by a KYC provider
+type UpdateVerificationDTO struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Status    string    `json:"status"`
+	Provider  string    `json:"provider"`
+	Reference string    `json:"reference"`
+}
+
+type cachedVerification struct {
+	verified bool
+	expires  time.Time
+}
+
+// VerificationUseCase keeps the identity verification (KYC) status of the users reported by the KYC
+// providers and tells the other modules which users are verified. the statuses are cached for cacheTTL,
+// an update received by another instance applies here once the cached status expires
+type VerificationUseCase struct {
+	repo     domain.UserVerificationRepository
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedVerification
+}
+
+// NewVerificationUseCase creates a new instance of VerificationUseCase
+func NewVerificationUseCase(repo domain.UserVerificationRepository, cacheTTL time.Duration) *VerificationUseCase {
+	return &VerificationUseCase{repo: repo, cacheTTL: cacheTTL, cache: make(map[uuid.UUID]cachedVerification)}
+}
+
+// IsVerified reports if the last status reported for the user is verified
+func (uc *VerificationUseCase) IsVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	uc.mu.Lock()
+	entry, ok := uc.cache[userID]
+	uc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.verified, nil
+	}
+	v, err := uc.repo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrVerificationNotFound) {
+		return false, fmt.Errorf("verification use case: failed to get verification of user %s: %w", userID, err)
+	}
+	verified := v != nil && v.Status == domain.VerificationVerified
+	uc.mu.Lock()
+	uc.cache[userID] = cachedVerification{verified: verified, expires: time.Now().Add(uc.cacheTTL)}
+	uc.mu.Unlock()
+	return verified, nil
+}
+
+// Update records the status of a user reported by a KYC provider, it applies to the next bids of the user
+func (uc *VerificationUseCase) Update(ctx context.Context, input UpdateVerificationDTO) (*domain.UserVerification, error) {
+	status := domain.VerificationStatus(strings.ToLower(strings.TrimSpace(input.Status)))
+	if input.UserID == uuid.Nil || !status.Valid() {
+		return nil, fmt.Errorf("%w: want a user_id and a status among pending, verified and rejected", ErrInvalidVerification)
+	}
+	v := &domain.UserVerification{
+		UserID:    input.UserID,
+		Status:    status,
+		Provider:  strings.TrimSpace(input.Provider),
+		Reference: strings.TrimSpace(input.Reference),
+	}
+	if len(v.Provider) > maxProviderLength || len(v.Reference) > maxReferenceLength {
+		return nil, fmt.Errorf("%w: provider or reference too long", ErrInvalidVerification)
+	}
+	if err := uc.repo.Save(ctx, v); err != nil {
+		return nil, fmt.Errorf("verification use case: failed to save verification of user %s: %w", v.UserID, err)
+	}
+	uc.mu.Lock()
+	delete(uc.cache, v.UserID)
+	uc.mu.Unlock()
+	logger.FromContext(ctx).Info("User verification updated",
+		zap.String("userID", v.UserID.String()),
+		zap.String("status", string(v.Status)),
+		zap.String("provider", v.Provider),
+		zap.String("reference", v.Reference),
+	)
+	return v, nil
+}
+
+// StubVerificationService reports every user as verified, for the deployments and test environments
+// without a KYC provider
+type StubVerificationService struct{}
+
+// IsVerified implements the verification check, every user is verified
+func (StubVerificationService) IsVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return true, nil
+}
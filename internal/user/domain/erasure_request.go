@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErasureStatus is the status of an erasure request in the admin workflow
+type ErasureStatus string
+
+const (
+	ErasurePending   ErasureStatus = "pending"
+	ErasureCompleted ErasureStatus = "completed" // approved, the user data is erased
+	ErasureRejected  ErasureStatus = "rejected"
+)
+
+// ErasureRequest is the request of a user to erase their personal data, recorded by an admin and
+// approved or rejected by another review. once approved the bids of the user are kept for the integrity
+// of the auctions, reattributed to a tombstone user without personal data
+type ErasureRequest struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	RequestedBy uuid.UUID
+	Reason      string
+	Status      ErasureStatus
+	ReviewedBy  *uuid.UUID
+	ReviewNote  string
+	ReviewedAt  *time.Time
+	// TombstoneID is the user the data of the erased one was reattributed to, set once completed
+	TombstoneID *uuid.UUID
+	CreatedAt   time.Time
+}
+
+// NewErasureRequest creates a new pending ErasureRequest
+func NewErasureRequest(userID, requestedBy uuid.UUID, reason string) *ErasureRequest {
+	return &ErasureRequest{
+		ID:          uuid.New(),
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		Reason:      reason,
+		Status:      ErasurePending,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Complete closes a pending request once the data of its user was reattributed to tombstoneID
+func (r *ErasureRequest) Complete(reviewerID, tombstoneID uuid.UUID, note string, at time.Time) error {
+	if r.Status != ErasurePending {
+		return ErrErasureRequestAlreadyClosed
+	}
+	r.Status = ErasureCompleted
+	r.ReviewedBy = &reviewerID
+	r.ReviewNote = note
+	r.ReviewedAt = &at
+	r.TombstoneID = &tombstoneID
+	return nil
+}
+
+// Reject closes a pending request without erasing anything, e.g. when the data must be kept by law
+func (r *ErasureRequest) Reject(reviewerID uuid.UUID, note string, at time.Time) error {
+	if r.Status != ErasurePending {
+		return ErrErasureRequestAlreadyClosed
+	}
+	r.Status = ErasureRejected
+	r.ReviewedBy = &reviewerID
+	r.ReviewNote = note
+	r.ReviewedAt = &at
+	return nil
+}
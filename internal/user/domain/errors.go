@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUserNotFound                = errors.New("user not found")
+	ErrErasureRequestNotFound      = errors.New("erasure request not found")
+	ErrErasureRequestAlreadyClosed = errors.New("erasure request is already approved or rejected")
+	ErrErasureRequestPending       = errors.New("the user already has a pending erasure request")
+	ErrVerificationNotFound        = errors.New("user verification not found")
+)
@@ -9,3 +9,31 @@ import (
 type UserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 }
+
+// UserDataRepository reads and erases the personal data tied to a user across the modules tables
+type UserDataRepository interface {
+	// Export returns all the data tied to the user, or ErrUserNotFound
+	Export(ctx context.Context, userID uuid.UUID) (*UserData, error)
+	// Erase reattributes the bids, paddles, invoices, chat msgs and sessions of the user to a new tombstone
+	// user with tombstoneID, clears their personal fields and deletes the user, all in one TX
+	Erase(ctx context.Context, userID, tombstoneID uuid.UUID) error
+}
+
+// UserVerificationRepository persists the verification status of the users
+type UserVerificationRepository interface {
+	// Get returns ErrVerificationNotFound for a user never checked
+	Get(ctx context.Context, userID uuid.UUID) (*UserVerification, error)
+	// Save inserts or replaces the status of the user
+	Save(ctx context.Context, v *UserVerification) error
+}
+
+// ErasureRequestRepository persists the erasure requests
+type ErasureRequestRepository interface {
+	// Create stores a new request, it returns ErrErasureRequestPending if the user already has a pending one
+	Create(ctx context.Context, r *ErasureRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*ErasureRequest, error)
+	// List returns a page of requests, newest first, an empty status matches any status
+	List(ctx context.Context, status ErasureStatus, limit, offset int) ([]*ErasureRequest, error)
+	// SaveReview stores the review fields of r
+	SaveReview(ctx context.Context, r *ErasureRequest) error
+}
@@ -4,5 +4,7 @@ import "github.com/google/uuid"
 
 // User represents  the domain user entity
 type User struct {
-	ID uuid.UUID
+	ID       uuid.UUID
+	Username string
+	Email    string
 }
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserData is the export of all the data the engine keeps tied to a user, the document handed over on a
+// data subject access request. the watched lots are live subscriptions only, no watchlist is stored
+type UserData struct {
+	User          UserRecord           `json:"user"`
+	Bids          []BidRecord          `json:"bids"`
+	Paddles       []PaddleRecord       `json:"paddles"`
+	Sessions      []SessionRecord      `json:"sessions"`
+	Invoices      []InvoiceRecord      `json:"invoices"`
+	ChatMessages  []ChatRecord         `json:"chat_messages"`
+	Notifications []NotificationRecord `json:"notifications"`
+	// BiddingLimit is nil when no limit applies to the user
+	BiddingLimit *float64  `json:"bidding_limit,omitempty"`
+	ExportedAt   time.Time `json:"exported_at"`
+}
+
+// UserRecord is the account of the user
+type UserRecord struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BidRecord is a bid of the user, voided ones included
+type BidRecord struct {
+	ID        uuid.UUID  `json:"id"`
+	LotID     uuid.UUID  `json:"lot_id"`
+	Amount    float64    `json:"amount"`
+	Timestamp time.Time  `json:"timestamp"`
+	ClientIP  string     `json:"client_ip,omitempty"`
+	VoidedAt  *time.Time `json:"voided_at,omitempty"`
+}
+
+// PaddleRecord is the alias of the user on a lot
+type PaddleRecord struct {
+	LotID  uuid.UUID `json:"lot_id"`
+	Paddle int       `json:"paddle"`
+}
+
+// SessionRecord is a sign in of the user
+type SessionRecord struct {
+	ID        uuid.UUID  `json:"id"`
+	Role      string     `json:"role"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	IP        string     `json:"ip,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// InvoiceRecord is an invoice of a lot won by the user
+type InvoiceRecord struct {
+	ID        uuid.UUID `json:"id"`
+	LotID     uuid.UUID `json:"lot_id"`
+	LotTitle  string    `json:"lot_title"`
+	Total     float64   `json:"total"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatRecord is a msg of the user in a lot chat
+type ChatRecord struct {
+	LotID     uuid.UUID `json:"lot_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationRecord is a notification sent to the user
+type NotificationRecord struct {
+	LotID     uuid.UUID `json:"lot_id"`
+	Kind      string    `json:"kind"`
+	Channel   string    `json:"channel"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationStatus is the status of the identity verification (KYC) of a user, set by the KYC provider
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationVerified VerificationStatus = "verified"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+// Valid reports if s is a known status
+func (s VerificationStatus) Valid() bool {
+	switch s {
+	case VerificationPending, VerificationVerified, VerificationRejected:
+		return true
+	}
+	return false
+}
+
+// UserVerification is the last verification status of a user reported by a KYC provider, the users
+// without one were never checked
+type UserVerification struct {
+	UserID uuid.UUID
+	Status VerificationStatus
+	// Provider and Reference identify the check on the provider side, for support lookups
+	Provider  string
+	Reference string
+	UpdatedAt time.Time
+}
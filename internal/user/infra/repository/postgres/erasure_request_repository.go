@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUniqueViolation is the Postgres error code of unique constraint violations
+const pgUniqueViolation = "23505"
+
+// ErasureRequestRepository implements domain.ErasureRequestRepository interface
+type ErasureRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewErasureRequestRepository creates a new instance of ErasureRequestRepository
+func NewErasureRequestRepository(pool *pgxpool.Pool) *ErasureRequestRepository {
+	return &ErasureRequestRepository{pool: pool}
+}
+
+const erasureRequestColumns = `id, user_id, requested_by, reason, status, reviewed_by, review_note, reviewed_at, tombstone_id, created_at`
+
+// Create inserts the request, the unique index on the pending requests rejects a second one of the user
+func (r *ErasureRequestRepository) Create(ctx context.Context, req *domain.ErasureRequest) error {
+	query := `
+        INSERT INTO user_erasure_requests (id, user_id, requested_by, reason, status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := r.pool.Exec(ctx, query, req.ID, req.UserID, req.RequestedBy, req.Reason, req.Status, req.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return domain.ErrErasureRequestPending
+	}
+	return err
+}
+
+// GetByID returns a request, or domain.ErrErasureRequestNotFound
+func (r *ErasureRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error) {
+	query := `SELECT ` + erasureRequestColumns + ` FROM user_erasure_requests WHERE id = $1`
+	req, err := scanErasureRequest(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrErasureRequestNotFound
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// List returns a page of requests, newest first
+func (r *ErasureRequestRepository) List(ctx context.Context, status domain.ErasureStatus, limit, offset int) ([]*domain.ErasureRequest, error) {
+	query := `
+        SELECT ` + erasureRequestColumns + `
+        FROM user_erasure_requests
+        WHERE ($1 = '' OR status = $1)
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.pool.Query(ctx, query, string(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.ErasureRequest
+	for rows.Next() {
+		req, err := scanErasureRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// SaveReview stores the review fields of the request
+func (r *ErasureRequestRepository) SaveReview(ctx context.Context, req *domain.ErasureRequest) error {
+	query := `
+        UPDATE user_erasure_requests
+        SET status = $2, reviewed_by = $3, review_note = $4, reviewed_at = $5, tombstone_id = $6
+        WHERE id = $1
+    `
+	_, err := r.pool.Exec(ctx, query, req.ID, req.Status, req.ReviewedBy, req.ReviewNote, req.ReviewedAt, req.TombstoneID)
+	return err
+}
+
+func scanErasureRequest(row pgx.Row) (*domain.ErasureRequest, error) {
+	req := &domain.ErasureRequest{}
+	err := row.Scan(
+		&req.ID,
+		&req.UserID,
+		&req.RequestedBy,
+		&req.Reason,
+		&req.Status,
+		&req.ReviewedBy,
+		&req.ReviewNote,
+		&req.ReviewedAt,
+		&req.TombstoneID,
+		&req.CreatedAt,
+	)
+	return req, err
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserDataRepository implements domain.UserDataRepository interface over the tables of every module
+type UserDataRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserDataRepository creates a new instance of UserDataRepository
+func NewUserDataRepository(pool *pgxpool.Pool) *UserDataRepository {
+	return &UserDataRepository{pool: pool}
+}
+
+// Export reads the data of the user in a single read only TX, so the export is consistent
+func (r *UserDataRepository) Export(ctx context.Context, userID uuid.UUID) (*domain.UserData, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	data := &domain.UserData{ExportedAt: time.Now()}
+	err = tx.QueryRow(ctx, `SELECT id, username, email, created_at FROM users WHERE id = $1`, userID).
+		Scan(&data.User.ID, &data.User.Username, &data.User.Email, &data.User.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	err = tx.QueryRow(ctx, `SELECT bidding_limit::float8 FROM user_limits WHERE user_id = $1`, userID).Scan(&data.BiddingLimit)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	data.Bids, err = collect(ctx, tx, `
+        SELECT id, lot_id, amount::float8, timestamp, client_ip, voided_at
+        FROM bids WHERE user_id = $1 ORDER BY timestamp
+    `, userID, func(row pgx.Row, b *domain.BidRecord) error {
+		return row.Scan(&b.ID, &b.LotID, &b.Amount, &b.Timestamp, &b.ClientIP, &b.VoidedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data.Paddles, err = collect(ctx, tx, `
+        SELECT lot_id, paddle_number FROM lot_paddles WHERE user_id = $1 ORDER BY created_at
+    `, userID, func(row pgx.Row, p *domain.PaddleRecord) error {
+		return row.Scan(&p.LotID, &p.Paddle)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data.Sessions, err = collect(ctx, tx, `
+        SELECT id, role, user_agent, ip, created_at, expires_at, revoked_at
+        FROM sessions WHERE user_id = $1 ORDER BY created_at
+    `, userID, func(row pgx.Row, s *domain.SessionRecord) error {
+		return row.Scan(&s.ID, &s.Role, &s.UserAgent, &s.IP, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data.Invoices, err = collect(ctx, tx, `
+        SELECT id, lot_id, lot_title, total::float8, status, created_at
+        FROM invoices WHERE buyer_id = $1 ORDER BY created_at
+    `, userID, func(row pgx.Row, i *domain.InvoiceRecord) error {
+		return row.Scan(&i.ID, &i.LotID, &i.LotTitle, &i.Total, &i.Status, &i.CreatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data.ChatMessages, err = collect(ctx, tx, `
+        SELECT lot_id, text, created_at FROM lot_chat_messages WHERE user_id = $1 ORDER BY created_at
+    `, userID, func(row pgx.Row, m *domain.ChatRecord) error {
+		return row.Scan(&m.LotID, &m.Text, &m.CreatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data.Notifications, err = collect(ctx, tx, `
+        SELECT lot_id, kind, channel, subject, created_at FROM notifications WHERE user_id = $1 ORDER BY created_at
+    `, userID, func(row pgx.Row, n *domain.NotificationRecord) error {
+		return row.Scan(&n.LotID, &n.Kind, &n.Channel, &n.Subject, &n.CreatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// erasureStatements reattribute the records of the user ($1) the auctions depend on to the tombstone
+// user ($2) clearing their personal fields, and delete the rest. the user itself is deleted last, its
+// notifications are deleted by the FK cascade
+var erasureStatements = []string{
+	`UPDATE bids SET user_id = $2, client_ip = '' WHERE user_id = $1`,
+	`UPDATE bids SET voided_by = $2 WHERE voided_by = $1`,
+	`UPDATE lot_paddles SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE invoices SET buyer_id = $2 WHERE buyer_id = $1`,
+	`UPDATE bid_reservations SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE lot_chat_messages SET user_id = $2, text = '' WHERE user_id = $1`,
+	`UPDATE lot_chat_mutes SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE fraud_alerts SET user_ids = array_replace(user_ids, $1, $2) WHERE $1 = ANY(user_ids)`,
+	`UPDATE lot_events SET payload = replace(payload::text, $1::text, $2::text)::jsonb WHERE payload::text LIKE '%' || $1::text || '%'`,
+	`UPDATE ws_dead_letters SET user_id = $2, payload = '' WHERE user_id = $1`,
+	`UPDATE sessions SET user_id = $2, user_agent = '', ip = '',
+        revoked_at = COALESCE(revoked_at, NOW()), revoke_reason = CASE WHEN revoked_at IS NULL THEN 'erased' ELSE revoke_reason END
+        WHERE user_id = $1`,
+	`UPDATE api_keys SET user_id = $2, revoked_at = COALESCE(revoked_at, NOW()) WHERE user_id = $1`,
+	`DELETE FROM user_limits WHERE user_id = $1`,
+	`DELETE FROM lot_auctioneers WHERE user_id = $1`,
+	`DELETE FROM token_revocations WHERE user_id = $1`,
+	`DELETE FROM users WHERE id = $1`,
+}
+
+// Erase creates the tombstone user and runs erasureStatements in one TX, it returns ErrUserNotFound when
+// the user doesn't exist, e.g. it was already erased
+func (r *UserDataRepository) Erase(ctx context.Context, userID, tombstoneID uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// the tombstone can't sign in, '!' is never a valid password hash
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO users (id, username, email, password_hash)
+        SELECT $2, 'erased-' || $2::text, $2::text || '@erased.invalid', '!'
+        FROM users WHERE id = $1
+    `, userID, tombstoneID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	for _, stmt := range erasureStatements {
+		if _, err := tx.Exec(ctx, stmt, userID, tombstoneID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// collect runs query with the user ID and scans every row into a new T
+func collect[T any](ctx context.Context, tx pgx.Tx, query string, userID uuid.UUID, scan func(pgx.Row, *T) error) ([]T, error) {
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := scan(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -6,8 +6,8 @@ import (
 
 	"github.com/cristianortiz/auctionEngine/internal/user/domain" // Importa el dominio del usuario
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // UserRepository implementa la interfaz domain.UserRepository para PostgreSQL.
@@ -22,10 +22,10 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 
 // GetByID obtiene un usuario por su ID desde la base de datos.
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `SELECT id FROM users WHERE id = $1`
+	query := `SELECT id, username, email FROM users WHERE id = $1`
 
-	var userID uuid.UUID
-	err := r.db.QueryRow(ctx, query, id).Scan(&userID)
+	user := &domain.User{}
+	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Username, &user.Email)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -36,11 +36,6 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 		return nil, err
 	}
 
-	// Si se encontró el usuario, crea y retorna la entidad User
-	user := &domain.User{
-		ID: userID,
-	}
-
 	return user, nil
 }
 
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgForeignKeyViolation is the Postgres error code of foreign key violations
+const pgForeignKeyViolation = "23503"
+
+// UserVerificationRepository implements domain.UserVerificationRepository interface
+type UserVerificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserVerificationRepository creates a new instance of UserVerificationRepository
+func NewUserVerificationRepository(pool *pgxpool.Pool) *UserVerificationRepository {
+	return &UserVerificationRepository{pool: pool}
+}
+
+// Get returns the verification of the user, or domain.ErrVerificationNotFound
+func (r *UserVerificationRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.UserVerification, error) {
+	query := `
+        SELECT user_id, status, provider, reference, updated_at
+        FROM user_verifications
+        WHERE user_id = $1
+    `
+	v := &domain.UserVerification{}
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&v.UserID, &v.Status, &v.Provider, &v.Reference, &v.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrVerificationNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// Save upserts the verification of the user, it returns domain.ErrUserNotFound for an unknown user
+func (r *UserVerificationRepository) Save(ctx context.Context, v *domain.UserVerification) error {
+	query := `
+        INSERT INTO user_verifications (user_id, status, provider, reference, updated_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (user_id) DO UPDATE SET
+            status = EXCLUDED.status,
+            provider = EXCLUDED.provider,
+            reference = EXCLUDED.reference,
+            updated_at = EXCLUDED.updated_at
+        RETURNING updated_at
+    `
+	err := r.pool.QueryRow(ctx, query, v.UserID, v.Status, v.Provider, v.Reference).Scan(&v.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return domain.ErrUserNotFound
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/user/application"
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DataRequestHandler exposes the export and the erasure workflow of the users personal data to admins
+type DataRequestHandler struct {
+	requestsUC *application.DataRequestsUseCase
+}
+
+// NewDataRequestHandler creates a new instance of DataRequestHandler
+func NewDataRequestHandler(requestsUC *application.DataRequestsUseCase) *DataRequestHandler {
+	return &DataRequestHandler{requestsUC: requestsUC}
+}
+
+// erasureRequest is the JSON body of POST /admin/users/:id/erasure-requests
+type erasureRequest struct {
+	Reason string `json:"reason"`
+}
+
+// reviewErasureRequest is the JSON body of POST /admin/erasure-requests/:id/approve and reject
+type reviewErasureRequest struct {
+	Note string `json:"note"`
+}
+
+// RegisterRoutes registers the data request endpoints, all of them guarded by requireAdmin
+func (h *DataRequestHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Get("/admin/users/:id/data-export", requireAdmin, h.exportData)
+	router.Post("/admin/users/:id/erasure-requests", requireAdmin, h.requestErasure)
+	router.Get("/admin/erasure-requests", requireAdmin, h.listRequests)
+	router.Post("/admin/erasure-requests/:id/approve", requireAdmin, h.approve)
+	router.Post("/admin/erasure-requests/:id/reject", requireAdmin, h.reject)
+}
+
+func (h *DataRequestHandler) exportData(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	data, err := h.requestsUC.Export(c.UserContext(), userID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="user-`+userID.String()+`.json"`)
+	return c.JSON(data)
+}
+
+func (h *DataRequestHandler) requestErasure(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+	var req erasureRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	request, err := h.requestsUC.RequestErasure(c.UserContext(), userID, httpserver.ClaimsFrom(c).UserID, req.Reason)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// listRequests handles GET /admin/erasure-requests?status=&limit=&offset=
+func (h *DataRequestHandler) listRequests(c *fiber.Ctx) error {
+	requests, err := h.requestsUC.List(c.UserContext(), c.Query("status"), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(requests)
+}
+
+func (h *DataRequestHandler) approve(c *fiber.Ctx) error {
+	cmd, err := reviewCommand(c)
+	if err != nil {
+		return err
+	}
+	request, err := h.requestsUC.Approve(c.UserContext(), cmd)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(request)
+}
+
+func (h *DataRequestHandler) reject(c *fiber.Ctx) error {
+	cmd, err := reviewCommand(c)
+	if err != nil {
+		return err
+	}
+	request, err := h.requestsUC.Reject(c.UserContext(), cmd)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(request)
+}
+
+// reviewCommand reads the review of the erasure request in the path, the body is optional
+func reviewCommand(c *fiber.Ctx) (application.ReviewErasureDTO, error) {
+	requestID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return application.ReviewErasureDTO{}, fiber.NewError(fiber.StatusBadRequest, "invalid erasure request ID")
+	}
+	var req reviewErasureRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return application.ReviewErasureDTO{}, fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+	return application.ReviewErasureDTO{
+		RequestID:  requestID,
+		ReviewerID: httpserver.ClaimsFrom(c).UserID,
+		Note:       req.Note,
+	}, nil
+}
+
+// toHTTPError maps user domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrErasureRequestNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidErasureStatus):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrErasureRequestAlreadyClosed), errors.Is(err, domain.ErrErasureRequestPending):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/user/application"
+	"github.com/cristianortiz/auctionEngine/internal/user/domain"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderKYCSignature is the signature of the KYC webhook calls, "t=<unix time>,v1=<hex HMAC-SHA256>"
+	// of "<unix time>.<body>" with the shared secret, the scheme of the webhooks we send
+	HeaderKYCSignature = "X-KYC-Signature"
+	// kycSignatureTolerance is how old a signed call can be, older ones are rejected as replays
+	kycSignatureTolerance = 5 * time.Minute
+)
+
+// VerificationHandler receives the verification statuses pushed by the KYC providers
+type VerificationHandler struct {
+	verificationUC *application.VerificationUseCase
+	secret         string
+}
+
+// NewVerificationHandler creates a new instance of VerificationHandler, the calls must be signed with
+// secret. an empty secret rejects every call, e.g. on the deployments without a KYC provider
+func NewVerificationHandler(verificationUC *application.VerificationUseCase, secret string) *VerificationHandler {
+	return &VerificationHandler{verificationUC: verificationUC, secret: secret}
+}
+
+// RegisterRoutes registers the KYC webhook, authenticated by its signature instead of a token
+func (h *VerificationHandler) RegisterRoutes(router fiber.Router) {
+	router.Post("/webhooks/kyc", h.updateVerification)
+}
+
+func (h *VerificationHandler) updateVerification(c *fiber.Ctx) error {
+	if !validKYCSignature(h.secret, c.Get(HeaderKYCSignature), c.Body(), time.Now()) {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid signature")
+	}
+	var req application.UpdateVerificationDTO
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if _, err := h.verificationUC.Update(c.UserContext(), req); err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidVerification):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		default:
+			logger.FromContext(c.UserContext()).Error("KYC webhook failed", zap.Error(err))
+			return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+		}
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// validKYCSignature checks header is the signature of body with secret, made less than
+// kycSignatureTolerance before now
+func validKYCSignature(secret, header string, body []byte, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > kycSignatureTolerance || age < -kycSignatureTolerance {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,189 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy configures the delivery retries of a webhook
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // delay before the 2nd attempt, doubled on every retry
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries 6 times starting at 2s up to 5m
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 6, BaseDelay: 2 * time.Second, MaxDelay: 5 * time.Minute}
+
+// backoff returns the delay before attempt (1 based), exponential with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+// EventDTO is an auction event to dispatch, BidAmount is the amount of a bid.placed event
+type EventDTO struct {
+	ID         uuid.UUID
+	Type       domain.EventType
+	LotID      uuid.UUID
+	OccurredAt time.Time
+	BidAmount  float64
+	Data       any
+}
+
+// eventBody is the JSON body posted to the endpoints
+type eventBody struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	LotID      uuid.UUID `json:"lot_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// DispatchEventUseCase delivers the auction events to the matching subscriptions, logging every delivery
+type DispatchEventUseCase struct {
+	subscriptions domain.SubscriptionRepository
+	deliveries    domain.DeliveryRepository
+	sender        domain.Sender
+	retry         RetryPolicy
+}
+
+// NewDispatchEventUseCase creates a new instance of DispatchEventUseCase
+func NewDispatchEventUseCase(subscriptions domain.SubscriptionRepository,
+	deliveries domain.DeliveryRepository,
+	sender domain.Sender,
+	retry RetryPolicy) *DispatchEventUseCase {
+	return &DispatchEventUseCase{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		sender:        sender,
+		retry:         retry,
+	}
+}
+
+// Execute delivers event to every matching subscription concurrently, so a slow endpoint doesn't
+// delay the others. It only returns an error if the deliveries could not be created, delivery
+// failures are tracked in the delivery log
+func (uc *DispatchEventUseCase) Execute(ctx context.Context, event EventDTO) error {
+	subs, err := uc.subscriptions.ListActive(ctx, event.Type, event.LotID)
+	if err != nil {
+		return fmt.Errorf("dispatch event use case: failed to list subscriptions: %w", err)
+	}
+	var matching []*domain.Subscription
+	for _, sub := range subs {
+		if sub.Matches(event.Type, event.BidAmount) {
+			matching = append(matching, sub)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(eventBody{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		LotID:      event.LotID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("dispatch event use case: failed to encode event %s: %w", event.ID, err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, sub := range matching {
+		d := domain.NewDelivery(sub.ID, event.ID, event.Type, event.LotID, payload)
+		if err := uc.deliveries.Save(ctx, d); err != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("dispatch event use case: failed to save delivery for subscription %s: %w", sub.ID, err))
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uc.deliver(ctx, sub, d, uc.retry.MaxAttempts)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Redeliver sends a logged delivery again with a single attempt, e.g. after the endpoint was fixed
+func (uc *DispatchEventUseCase) Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*DeliveryDTO, error) {
+	d, err := uc.deliveries.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch event use case: failed to get delivery %s: %w", deliveryID, err)
+	}
+	if d.SubscriptionID != subscriptionID {
+		return nil, fmt.Errorf("dispatch event use case: %w: %s", domain.ErrDeliveryNotFound, deliveryID)
+	}
+	sub, err := uc.subscriptions.GetByID(ctx, d.SubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch event use case: failed to get subscription %s: %w", d.SubscriptionID, err)
+	}
+	d.Retry()
+	uc.deliver(ctx, sub, d, 1)
+	dto := toDeliveryDTO(d)
+	return &dto, nil
+}
+
+// deliver sends d retrying with exponential backoff, persisting the status after every attempt
+func (uc *DispatchEventUseCase) deliver(ctx context.Context, sub *domain.Subscription, d *domain.Delivery, maxAttempts int) {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		status, err := uc.sender.Send(ctx, sub, d)
+		if err == nil {
+			d.MarkDelivered(status, time.Now())
+			uc.saveStatus(ctx, d)
+			return
+		}
+
+		// a rejected payload (4xx) will be rejected again
+		giveUp := attempt == maxAttempts || errors.Is(err, domain.ErrPermanentFailure)
+		d.MarkAttemptFailed(status, err, giveUp)
+		uc.saveStatus(ctx, d)
+		logger.FromContext(ctx).Warn("Webhook delivery failed",
+			zap.String("deliveryID", d.ID.String()),
+			zap.String("subscriptionID", sub.ID.String()),
+			zap.Int("attempt", attempt),
+			zap.Int("responseStatus", status),
+			zap.Bool("giveUp", giveUp),
+			zap.Error(err),
+		)
+		if giveUp {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(uc.retry.backoff(attempt)):
+		}
+	}
+}
+
+func (uc *DispatchEventUseCase) saveStatus(ctx context.Context, d *domain.Delivery) {
+	if err := uc.deliveries.Save(ctx, d); err != nil {
+		logger.FromContext(ctx).Error("Failed to save webhook delivery status",
+			zap.String("deliveryID", d.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	// secretPrefix marks the signing secrets, so they are easy to recognize in the receiver config
+	secretPrefix = "whsec_"
+)
+
+// SubscriptionDTO is the output DTO of a webhook subscription, Secret is only set when it's
+// created or rotated, it can't be read back later
+type SubscriptionDTO struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	EventTypes   []string  `json:"event_types"`
+	MinBidAmount *float64  `json:"min_bid_amount,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Active       bool      `json:"active"`
+	Secret       string    `json:"secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateSubscriptionDTO is the input of ManageSubscriptionsUseCase.Create
+type CreateSubscriptionDTO struct {
+	URL          string
+	EventTypes   []string
+	MinBidAmount *float64
+	Description  string
+}
+
+// UpdateSubscriptionDTO is the input of ManageSubscriptionsUseCase.Update, nil fields are left as they are
+type UpdateSubscriptionDTO struct {
+	ID           uuid.UUID
+	URL          *string
+	EventTypes   []string
+	MinBidAmount *float64
+	// ClearMinBidAmount removes the bid.placed threshold, MinBidAmount is ignored when set
+	ClearMinBidAmount bool
+	Description       *string
+	Active            *bool
+	RotateSecret      bool
+}
+
+// DeliveryDTO is the output DTO of a delivery of the log
+type DeliveryDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	EventID        uuid.UUID  `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	LotID          uuid.UUID  `json:"lot_id"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// ManageSubscriptionsUseCase is the admin management of the webhook subscriptions and their delivery log
+type ManageSubscriptionsUseCase struct {
+	subscriptions domain.SubscriptionRepository
+	deliveries    domain.DeliveryRepository
+}
+
+// NewManageSubscriptionsUseCase creates a new instance of ManageSubscriptionsUseCase
+func NewManageSubscriptionsUseCase(subscriptions domain.SubscriptionRepository, deliveries domain.DeliveryRepository) *ManageSubscriptionsUseCase {
+	return &ManageSubscriptionsUseCase{subscriptions: subscriptions, deliveries: deliveries}
+}
+
+// Create registers a new subscription with a random signing secret, returned only in this response
+func (uc *ManageSubscriptionsUseCase) Create(ctx context.Context, cmd CreateSubscriptionDTO) (*SubscriptionDTO, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to generate secret: %w", err)
+	}
+	sub, err := domain.NewSubscription(cmd.URL, toEventTypes(cmd.EventTypes), cmd.MinBidAmount, cmd.Description, secret)
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: %w", err)
+	}
+	sub.OrgID = tenant.OrgIDOrDefault(ctx)
+	if err := uc.subscriptions.Save(ctx, sub); err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to save subscription: %w", err)
+	}
+	logger.FromContext(ctx).Info("Webhook subscription created",
+		zap.String("subscriptionID", sub.ID.String()),
+		zap.String("orgID", sub.OrgID.String()),
+		zap.String("url", sub.URL),
+		zap.Strings("eventTypes", cmd.EventTypes),
+	)
+	dto := toSubscriptionDTO(sub)
+	dto.Secret = sub.Secret
+	return &dto, nil
+}
+
+// List returns a page of subscriptions, newest first
+func (uc *ManageSubscriptionsUseCase) List(ctx context.Context, limit, offset int) ([]SubscriptionDTO, error) {
+	limit, offset = pageBounds(limit, offset)
+	subs, err := uc.subscriptions.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to list subscriptions: %w", err)
+	}
+	dtos := make([]SubscriptionDTO, 0, len(subs))
+	for _, sub := range subs {
+		dtos = append(dtos, toSubscriptionDTO(sub))
+	}
+	return dtos, nil
+}
+
+// Get returns a subscription
+func (uc *ManageSubscriptionsUseCase) Get(ctx context.Context, id uuid.UUID) (*SubscriptionDTO, error) {
+	sub, err := uc.subscriptions.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to get subscription %s: %w", id, err)
+	}
+	dto := toSubscriptionDTO(sub)
+	return &dto, nil
+}
+
+// Update edits a subscription, the new secret is returned when it's rotated
+func (uc *ManageSubscriptionsUseCase) Update(ctx context.Context, cmd UpdateSubscriptionDTO) (*SubscriptionDTO, error) {
+	sub, err := uc.subscriptions.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to get subscription %s: %w", cmd.ID, err)
+	}
+	if cmd.URL != nil {
+		if err := sub.SetURL(*cmd.URL); err != nil {
+			return nil, fmt.Errorf("manage subscriptions use case: %w", err)
+		}
+	}
+	if cmd.EventTypes != nil {
+		if err := sub.SetEventTypes(toEventTypes(cmd.EventTypes)); err != nil {
+			return nil, fmt.Errorf("manage subscriptions use case: %w", err)
+		}
+	}
+	switch {
+	case cmd.ClearMinBidAmount:
+		_ = sub.SetMinBidAmount(nil)
+	case cmd.MinBidAmount != nil:
+		if err := sub.SetMinBidAmount(cmd.MinBidAmount); err != nil {
+			return nil, fmt.Errorf("manage subscriptions use case: %w", err)
+		}
+	}
+	if cmd.Description != nil {
+		sub.Description = strings.TrimSpace(*cmd.Description)
+	}
+	if cmd.Active != nil {
+		sub.Active = *cmd.Active
+	}
+	if cmd.RotateSecret {
+		if sub.Secret, err = newSecret(); err != nil {
+			return nil, fmt.Errorf("manage subscriptions use case: failed to generate secret: %w", err)
+		}
+	}
+	sub.UpdatedAt = time.Now()
+
+	if err := uc.subscriptions.Save(ctx, sub); err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to save subscription %s: %w", sub.ID, err)
+	}
+	logger.FromContext(ctx).Info("Webhook subscription updated",
+		zap.String("subscriptionID", sub.ID.String()),
+		zap.Bool("active", sub.Active),
+		zap.Bool("secretRotated", cmd.RotateSecret),
+	)
+	dto := toSubscriptionDTO(sub)
+	if cmd.RotateSecret {
+		dto.Secret = sub.Secret
+	}
+	return &dto, nil
+}
+
+// Delete removes a subscription and its delivery log
+func (uc *ManageSubscriptionsUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := uc.subscriptions.Delete(ctx, id); err != nil {
+		return fmt.Errorf("manage subscriptions use case: failed to delete subscription %s: %w", id, err)
+	}
+	logger.FromContext(ctx).Info("Webhook subscription deleted", zap.String("subscriptionID", id.String()))
+	return nil
+}
+
+// Deliveries returns a page of the delivery log of a subscription, newest first, status filters it when not empty
+func (uc *ManageSubscriptionsUseCase) Deliveries(ctx context.Context, subscriptionID uuid.UUID, status string, limit, offset int) ([]DeliveryDTO, error) {
+	switch domain.DeliveryStatus(status) {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidDeliveryState, status)
+	}
+	// a 404 for an unknown subscription instead of an empty log
+	if _, err := uc.subscriptions.GetByID(ctx, subscriptionID); err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to get subscription %s: %w", subscriptionID, err)
+	}
+	limit, offset = pageBounds(limit, offset)
+	deliveries, err := uc.deliveries.ListBySubscription(ctx, subscriptionID, domain.DeliveryStatus(status), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("manage subscriptions use case: failed to list deliveries: %w", err)
+	}
+	dtos := make([]DeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, toDeliveryDTO(d))
+	}
+	return dtos, nil
+}
+
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	return min(limit, maxPageLimit), max(offset, 0)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+func toEventTypes(types []string) []domain.EventType {
+	eventTypes := make([]domain.EventType, 0, len(types))
+	for _, t := range types {
+		eventTypes = append(eventTypes, domain.EventType(strings.TrimSpace(t)))
+	}
+	return eventTypes
+}
+
+func toSubscriptionDTO(sub *domain.Subscription) SubscriptionDTO {
+	eventTypes := make([]string, 0, len(sub.EventTypes))
+	for _, t := range sub.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return SubscriptionDTO{
+		ID:           sub.ID,
+		URL:          sub.URL,
+		EventTypes:   eventTypes,
+		MinBidAmount: sub.MinBidAmount,
+		Description:  sub.Description,
+		Active:       sub.Active,
+		CreatedAt:    sub.CreatedAt,
+		UpdatedAt:    sub.UpdatedAt,
+	}
+}
+
+func toDeliveryDTO(d *domain.Delivery) DeliveryDTO {
+	return DeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		LotID:          d.LotID,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
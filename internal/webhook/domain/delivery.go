@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryStatus is the status of the delivery of an event to a subscription
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is an event sent to a subscription, tracked until the endpoint accepts it or it gives up.
+// Payload is the exact signed body, so redeliveries send the same bytes
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      EventType
+	LotID          uuid.UUID
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int // HTTP status of the last attempt, 0 when no response was received
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// NewDelivery creates a new pending Delivery
+func NewDelivery(subscriptionID, eventID uuid.UUID, eventType EventType, lotID uuid.UUID, payload []byte) *Delivery {
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		LotID:          lotID,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// MarkAttemptFailed records a failed delivery attempt, the delivery is failed once it gives up
+func (d *Delivery) MarkAttemptFailed(responseStatus int, err error, giveUp bool) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.LastError = err.Error()
+	if giveUp {
+		d.Status = DeliveryFailed
+	}
+}
+
+// MarkDelivered records a successful delivery
+func (d *Delivery) MarkDelivered(responseStatus int, at time.Time) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.Status = DeliveryDelivered
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+// Retry puts a failed delivery back to pending for a manual redelivery
+func (d *Delivery) Retry() {
+	d.Status = DeliveryPending
+}
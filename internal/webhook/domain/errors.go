@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrInvalidDeliveryState = errors.New("invalid webhook delivery status")
+	// ErrPermanentFailure is returned by a Sender when retrying can't succeed, e.g. a 4xx response
+	ErrPermanentFailure = errors.New("webhook endpoint rejected the delivery")
+)
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository persists the webhook subscriptions
+type SubscriptionRepository interface {
+	// Save inserts the subscription or updates it if it already exists
+	Save(ctx context.Context, s *Subscription) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	// List returns a page of subscriptions, newest first
+	List(ctx context.Context, limit, offset int) ([]*Subscription, error)
+	// ListActive returns the active subscriptions registered for eventType of the organization owning lotID
+	ListActive(ctx context.Context, eventType EventType, lotID uuid.UUID) ([]*Subscription, error)
+	// Delete removes the subscription and its deliveries, or returns ErrSubscriptionNotFound
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DeliveryRepository persists the delivery log of the subscriptions
+type DeliveryRepository interface {
+	// Save inserts the delivery or updates its status if it already exists
+	Save(ctx context.Context, d *Delivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	// ListBySubscription returns a page of deliveries of a subscription, newest first,
+	// an empty status matches any status
+	ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, status DeliveryStatus, limit, offset int) ([]*Delivery, error)
+}
+
+// Sender posts a delivery to the endpoint of its subscription, it returns the HTTP status
+// of the response (0 when none) and an error for any non 2xx response
+type Sender interface {
+	Send(ctx context.Context, s *Subscription, d *Delivery) (int, error)
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType is an auction event type a subscription can receive, named as the auction events
+type EventType string
+
+const (
+	EventBidPlaced        EventType = "bid.placed"
+	EventBidVoided        EventType = "bid.voided"
+	EventLotStarted       EventType = "lot.started"
+	EventLotFinished      EventType = "lot.finished"
+	EventLotCancelled     EventType = "lot.cancelled"
+	EventLotPaused        EventType = "lot.paused"
+	EventLotResumed       EventType = "lot.resumed"
+	EventWinnerDetermined EventType = "lot.winner_determined"
+)
+
+// EventTypes are the event types a subscription can register for, the private ones (e.g. bid.outbid) are left out
+var EventTypes = []EventType{
+	EventBidPlaced,
+	EventBidVoided,
+	EventLotStarted,
+	EventLotFinished,
+	EventLotCancelled,
+	EventLotPaused,
+	EventLotResumed,
+	EventWinnerDetermined,
+}
+
+// Subscription is an external endpoint receiving the auction events of the registered types
+type Subscription struct {
+	ID uuid.UUID
+	// OrgID is the organization owning the subscription, it only receives the events of its lots
+	OrgID      uuid.UUID
+	URL        string
+	EventTypes []EventType
+	// MinBidAmount only applies to bid.placed, smaller bids are not delivered, nil delivers every bid
+	MinBidAmount *float64
+	// Secret signs the payloads, the receiver verifies the signature with it
+	Secret      string
+	Description string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewSubscription creates a new active Subscription, it returns ErrInvalidSubscription if the params are not valid
+func NewSubscription(rawURL string, eventTypes []EventType, minBidAmount *float64, description, secret string) (*Subscription, error) {
+	now := time.Now()
+	s := &Subscription{
+		ID:          uuid.New(),
+		Secret:      secret,
+		Description: strings.TrimSpace(description),
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.SetURL(rawURL); err != nil {
+		return nil, err
+	}
+	if err := s.SetEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+	if err := s.SetMinBidAmount(minBidAmount); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetURL changes the endpoint, it must be an absolute http(s) URL
+func (s *Subscription) SetURL(rawURL string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	s.URL = u.String()
+	return nil
+}
+
+// SetEventTypes changes the registered event types, at least one is required
+func (s *Subscription) SetEventTypes(eventTypes []EventType) error {
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidSubscription)
+	}
+	for _, t := range eventTypes {
+		if !slices.Contains(EventTypes, t) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidSubscription, t)
+		}
+	}
+	sorted := slices.Clone(eventTypes)
+	slices.Sort(sorted)
+	s.EventTypes = slices.Compact(sorted)
+	return nil
+}
+
+// SetMinBidAmount changes the bid.placed threshold, nil removes it
+func (s *Subscription) SetMinBidAmount(amount *float64) error {
+	if amount != nil && *amount < 0 {
+		return fmt.Errorf("%w: min_bid_amount can't be negative", ErrInvalidSubscription)
+	}
+	s.MinBidAmount = amount
+	return nil
+}
+
+// Matches reports if the event must be delivered to the subscription, bidAmount is the amount
+// of a bid.placed event and is ignored for the other types
+func (s *Subscription) Matches(eventType EventType, bidAmount float64) bool {
+	if !s.Active || !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	if eventType == EventBidPlaced && s.MinBidAmount != nil && bidAmount < *s.MinBidAmount {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package listener
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	auctiondomain "github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/application"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"go.uber.org/zap"
+)
+
+// dispatchTimeout bounds the delivery (including retries) of an event to its subscriptions
+const dispatchTimeout = 30 * time.Minute
+
+// AuctionEventListener forwards the auction events to the webhook subscriptions, it implements
+// auction domain.EventPublisher so it can be plugged next to the broker publisher
+type AuctionEventListener struct {
+	ctx        context.Context
+	dispatchUC *application.DispatchEventUseCase
+}
+
+// NewAuctionEventListener creates a new instance of AuctionEventListener, ctx bounds the background deliveries
+func NewAuctionEventListener(ctx context.Context, dispatchUC *application.DispatchEventUseCase) *AuctionEventListener {
+	return &AuctionEventListener{ctx: ctx, dispatchUC: dispatchUC}
+}
+
+// Publish implements auction domain.EventPublisher, the events are delivered in background
+// so slow endpoints and retries never block the auction flow
+func (l *AuctionEventListener) Publish(ctx context.Context, events ...auctiondomain.Event) error {
+	// the background work keeps the correlation ID of the request that produced the events
+	correlationID := logger.CorrelationID(ctx)
+	for _, event := range events {
+		eventType := domain.EventType(event.Type)
+		if !slices.Contains(domain.EventTypes, eventType) {
+			continue
+		}
+		dto := application.EventDTO{
+			ID:         event.ID,
+			Type:       eventType,
+			LotID:      event.LotID,
+			OccurredAt: event.OccurredAt,
+			Data:       event.Payload,
+		}
+		if event.Type == auctiondomain.EventBidPlaced {
+			payload, ok := event.Payload.(auctiondomain.BidPlacedPayload)
+			if !ok {
+				logger.FromContext(ctx).Error("AuctionEventListener: unexpected payload", zap.String("type", string(event.Type)))
+				continue
+			}
+			dto.BidAmount = payload.Amount
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(logger.WithCorrelationID(l.ctx, correlationID), dispatchTimeout)
+			defer cancel()
+			if err := l.dispatchUC.Execute(ctx, dto); err != nil {
+				logger.FromContext(ctx).Error("AuctionEventListener: failed to dispatch webhook event",
+					zap.String("eventID", dto.ID.String()),
+					zap.String("type", string(dto.Type)),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+	return nil
+}
+
+// Close implements auction domain.EventPublisher
+func (l *AuctionEventListener) Close() error { return nil }
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeliveryRepository implements domain.DeliveryRepository interface
+type DeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDeliveryRepository creates a new instance of DeliveryRepository
+func NewDeliveryRepository(pool *pgxpool.Pool) *DeliveryRepository {
+	return &DeliveryRepository{pool: pool}
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, lot_id, payload, status, attempts, response_status, last_error, created_at, delivered_at`
+
+// Save inserts the delivery or updates its status if it already exists
+func (r *DeliveryRepository) Save(ctx context.Context, d *domain.Delivery) error {
+	query := `
+        INSERT INTO webhook_deliveries (` + deliveryColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (id) DO UPDATE
+        SET
+            status = EXCLUDED.status,
+            attempts = EXCLUDED.attempts,
+            response_status = EXCLUDED.response_status,
+            last_error = EXCLUDED.last_error,
+            delivered_at = EXCLUDED.delivered_at,
+            updated_at = NOW();
+    `
+	_, err := r.pool.Exec(ctx, query,
+		d.ID,
+		d.SubscriptionID,
+		d.EventID,
+		d.EventType,
+		d.LotID,
+		string(d.Payload),
+		d.Status,
+		d.Attempts,
+		d.ResponseStatus,
+		d.LastError,
+		d.CreatedAt,
+		d.DeliveredAt,
+	)
+	return err
+}
+
+// GetByID returns a delivery, or domain.ErrDeliveryNotFound
+func (r *DeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	d, err := scanDelivery(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// ListBySubscription returns a page of deliveries of a subscription, newest first
+func (r *DeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, status domain.DeliveryStatus, limit, offset int) ([]*domain.Delivery, error) {
+	query := `
+        SELECT ` + deliveryColumns + `
+        FROM webhook_deliveries
+        WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+        ORDER BY created_at DESC
+        LIMIT $3 OFFSET $4
+    `
+	rows, err := r.pool.Query(ctx, query, subscriptionID, string(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func scanDelivery(row pgx.Row) (*domain.Delivery, error) {
+	d := &domain.Delivery{}
+	var payload string
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.LotID,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseStatus,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	d.Payload = []byte(payload)
+	return d, err
+}
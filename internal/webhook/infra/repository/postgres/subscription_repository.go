@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/tenant"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SubscriptionRepository implements domain.SubscriptionRepository interface
+type SubscriptionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSubscriptionRepository creates a new instance of SubscriptionRepository
+func NewSubscriptionRepository(pool *pgxpool.Pool) *SubscriptionRepository {
+	return &SubscriptionRepository{pool: pool}
+}
+
+const subscriptionColumns = `id, url, event_types, min_bid_amount, secret, description, active, created_at, updated_at, org_id`
+
+// Save inserts the subscription or updates it if it already exists
+func (r *SubscriptionRepository) Save(ctx context.Context, s *domain.Subscription) error {
+	query := `
+        INSERT INTO webhook_subscriptions (` + subscriptionColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (id) DO UPDATE
+        SET
+            url = EXCLUDED.url,
+            event_types = EXCLUDED.event_types,
+            min_bid_amount = EXCLUDED.min_bid_amount,
+            secret = EXCLUDED.secret,
+            description = EXCLUDED.description,
+            active = EXCLUDED.active,
+            updated_at = EXCLUDED.updated_at;
+    `
+	eventTypes := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	_, err := r.pool.Exec(ctx, query,
+		s.ID,
+		s.URL,
+		eventTypes,
+		s.MinBidAmount,
+		s.Secret,
+		s.Description,
+		s.Active,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.OrgID,
+	)
+	return err
+}
+
+// GetByID returns a subscription, or domain.ErrSubscriptionNotFound, subscriptions of other organizations are not found
+func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+	s, err := scanSubscription(r.pool.QueryRow(ctx, query, id, tenant.Filter(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// List returns a page of subscriptions, newest first
+func (r *SubscriptionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Subscription, error) {
+	query := `
+        SELECT ` + subscriptionColumns + `
+        FROM webhook_subscriptions
+        WHERE ($3::uuid IS NULL OR org_id = $3)
+        ORDER BY created_at DESC
+        LIMIT $1 OFFSET $2
+    `
+	return r.query(ctx, query, limit, offset, tenant.Filter(ctx))
+}
+
+// ListActive returns the active subscriptions registered for eventType of the organization owning lotID
+func (r *SubscriptionRepository) ListActive(ctx context.Context, eventType domain.EventType, lotID uuid.UUID) ([]*domain.Subscription, error) {
+	query := `
+        SELECT ` + subscriptionColumns + `
+        FROM webhook_subscriptions
+        WHERE active AND event_types @> ARRAY[$1::TEXT]
+          AND org_id = (SELECT org_id FROM auction_lots WHERE id = $2)
+    `
+	return r.query(ctx, query, string(eventType), lotID)
+}
+
+// Delete removes the subscription, its deliveries are removed by the FK cascade
+func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1 AND ($2::uuid IS NULL OR org_id = $2)`
+	tag, err := r.pool.Exec(ctx, query, id, tenant.Filter(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*domain.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.Subscription, error) {
+	s := &domain.Subscription{}
+	var eventTypes []string
+	err := row.Scan(
+		&s.ID,
+		&s.URL,
+		&eventTypes,
+		&s.MinBidAmount,
+		&s.Secret,
+		&s.Description,
+		&s.Active,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.OrgID,
+	)
+	for _, t := range eventTypes {
+		s.EventTypes = append(s.EventTypes, domain.EventType(t))
+	}
+	return s, err
+}
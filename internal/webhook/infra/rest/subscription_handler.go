@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/logger"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/application"
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SubscriptionHandler exposes the webhook subscriptions management API to admins
+type SubscriptionHandler struct {
+	manageUC   *application.ManageSubscriptionsUseCase
+	dispatchUC *application.DispatchEventUseCase
+}
+
+// NewSubscriptionHandler creates a new instance of SubscriptionHandler
+func NewSubscriptionHandler(manageUC *application.ManageSubscriptionsUseCase, dispatchUC *application.DispatchEventUseCase) *SubscriptionHandler {
+	return &SubscriptionHandler{manageUC: manageUC, dispatchUC: dispatchUC}
+}
+
+// createSubscriptionRequest is the JSON body of POST /admin/webhooks
+type createSubscriptionRequest struct {
+	URL          string   `json:"url"`
+	EventTypes   []string `json:"event_types"`
+	MinBidAmount *float64 `json:"min_bid_amount"`
+	Description  string   `json:"description"`
+}
+
+// updateSubscriptionRequest is the JSON body of PATCH /admin/webhooks/:id, omitted fields are left as they are
+type updateSubscriptionRequest struct {
+	URL               *string  `json:"url"`
+	EventTypes        []string `json:"event_types"`
+	MinBidAmount      *float64 `json:"min_bid_amount"`
+	ClearMinBidAmount bool     `json:"clear_min_bid_amount"`
+	Description       *string  `json:"description"`
+	Active            *bool    `json:"active"`
+	RotateSecret      bool     `json:"rotate_secret"`
+}
+
+// RegisterRoutes registers the subscription endpoints, all of them guarded by requireAdmin
+func (h *SubscriptionHandler) RegisterRoutes(router fiber.Router, requireAdmin fiber.Handler) {
+	router.Post("/admin/webhooks", requireAdmin, h.createSubscription)
+	router.Get("/admin/webhooks", requireAdmin, h.listSubscriptions)
+	router.Get("/admin/webhooks/:id", requireAdmin, h.getSubscription)
+	router.Patch("/admin/webhooks/:id", requireAdmin, h.updateSubscription)
+	router.Delete("/admin/webhooks/:id", requireAdmin, h.deleteSubscription)
+	router.Get("/admin/webhooks/:id/deliveries", requireAdmin, h.listDeliveries)
+	router.Post("/admin/webhooks/:id/deliveries/:deliveryID/redeliver", requireAdmin, h.redeliver)
+}
+
+// createSubscription returns the new subscription with its signing secret, the only time it's shown
+func (h *SubscriptionHandler) createSubscription(c *fiber.Ctx) error {
+	var req createSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	sub, err := h.manageUC.Create(c.UserContext(), application.CreateSubscriptionDTO{
+		URL:          req.URL,
+		EventTypes:   req.EventTypes,
+		MinBidAmount: req.MinBidAmount,
+		Description:  req.Description,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(sub)
+}
+
+// listSubscriptions handles GET /admin/webhooks?limit=&offset=
+func (h *SubscriptionHandler) listSubscriptions(c *fiber.Ctx) error {
+	subs, err := h.manageUC.List(c.UserContext(), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(subs)
+}
+
+func (h *SubscriptionHandler) getSubscription(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid subscription ID")
+	}
+	sub, err := h.manageUC.Get(c.UserContext(), id)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(sub)
+}
+
+func (h *SubscriptionHandler) updateSubscription(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid subscription ID")
+	}
+	var req updateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	sub, err := h.manageUC.Update(c.UserContext(), application.UpdateSubscriptionDTO{
+		ID:                id,
+		URL:               req.URL,
+		EventTypes:        req.EventTypes,
+		MinBidAmount:      req.MinBidAmount,
+		ClearMinBidAmount: req.ClearMinBidAmount,
+		Description:       req.Description,
+		Active:            req.Active,
+		RotateSecret:      req.RotateSecret,
+	})
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(sub)
+}
+
+func (h *SubscriptionHandler) deleteSubscription(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid subscription ID")
+	}
+	if err := h.manageUC.Delete(c.UserContext(), id); err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// listDeliveries handles GET /admin/webhooks/:id/deliveries?status=&limit=&offset=
+func (h *SubscriptionHandler) listDeliveries(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid subscription ID")
+	}
+	deliveries, err := h.manageUC.Deliveries(c.UserContext(), id, c.Query("status"), c.QueryInt("limit"), c.QueryInt("offset"))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(deliveries)
+}
+
+// redeliver sends a logged delivery again, once, and returns its updated log entry
+func (h *SubscriptionHandler) redeliver(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid subscription ID")
+	}
+	deliveryID, err := uuid.Parse(c.Params("deliveryID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid delivery ID")
+	}
+	delivery, err := h.dispatchUC.Redeliver(c.UserContext(), id, deliveryID)
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+	return c.JSON(delivery)
+}
+
+// toHTTPError maps webhook domain errors to HTTP errors
+func toHTTPError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionNotFound), errors.Is(err, domain.ErrDeliveryNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidSubscription), errors.Is(err, domain.ErrInvalidDeliveryState):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	default:
+		logger.FromContext(c.UserContext()).Error("REST request failed", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "internal error")
+	}
+}
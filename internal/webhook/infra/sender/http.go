@@ -0,0 +1,78 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/webhook/domain"
+)
+
+const (
+	// HeaderSignature carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">", receivers
+	// recompute it with the subscription secret and should reject old timestamps to prevent replays
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// HTTPSender implements domain.Sender posting the signed payloads as JSON
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a new instance of HTTPSender, timeout bounds every attempt
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Sign returns the signature header value of body signed with secret at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send implements domain.Sender, a 4xx response other than 408 and 429 is a permanent failure
+func (s *HTTPSender) Send(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auctionEngine-webhooks/1")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, d.ID.String())
+	// signed on every attempt, so the timestamp of a retry is fresh
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, time.Now(), d.Payload))
+	// lets the receiver de-duplicate retried deliveries
+	req.Header.Set("Idempotency-Key", d.ID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain a bit of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return resp.StatusCode, fmt.Errorf("%w: status %d", domain.ErrPermanentFailure, resp.StatusCode)
+	default:
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,1093 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: auction/v1/auction.proto
+
+package auctionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PlaceBidRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	LotId string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	// bidder, calls with an x-api-key metadata may leave it empty to bid as the user of the key
+	UserId string  `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// optional ISO 4217 code the amount is meant in, must match the lot currency
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceBidRequest) Reset() {
+	*x = PlaceBidRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceBidRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceBidRequest) ProtoMessage() {}
+
+func (x *PlaceBidRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceBidRequest.ProtoReflect.Descriptor instead.
+func (*PlaceBidRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlaceBidRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *PlaceBidRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PlaceBidRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PlaceBidRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type PlaceBidResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bid           *Bid                   `protobuf:"bytes,1,opt,name=bid,proto3" json:"bid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceBidResponse) Reset() {
+	*x = PlaceBidResponse{}
+	mi := &file_auction_v1_auction_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceBidResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceBidResponse) ProtoMessage() {}
+
+func (x *PlaceBidResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceBidResponse.ProtoReflect.Descriptor instead.
+func (*PlaceBidResponse) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlaceBidResponse) GetBid() *Bid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+type GetLotStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLotStateRequest) Reset() {
+	*x = GetLotStateRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLotStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLotStateRequest) ProtoMessage() {}
+
+func (x *GetLotStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLotStateRequest.ProtoReflect.Descriptor instead.
+func (*GetLotStateRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLotStateRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+type ListActiveLotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveLotsRequest) Reset() {
+	*x = ListActiveLotsRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveLotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveLotsRequest) ProtoMessage() {}
+
+func (x *ListActiveLotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveLotsRequest.ProtoReflect.Descriptor instead.
+func (*ListActiveLotsRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{3}
+}
+
+type ListActiveLotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lots          []*LotState            `protobuf:"bytes,1,rep,name=lots,proto3" json:"lots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveLotsResponse) Reset() {
+	*x = ListActiveLotsResponse{}
+	mi := &file_auction_v1_auction_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveLotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveLotsResponse) ProtoMessage() {}
+
+func (x *ListActiveLotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveLotsResponse.ProtoReflect.Descriptor instead.
+func (*ListActiveLotsResponse) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListActiveLotsResponse) GetLots() []*LotState {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+type CreateLotRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Title        string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description  string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	InitialPrice float64                `protobuf:"fixed64,3,opt,name=initial_price,json=initialPrice,proto3" json:"initial_price,omitempty"`
+	EndTime      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// anti-sniping extension in seconds, 0 uses the server default
+	TimeExtensionSeconds int64 `protobuf:"varint,5,opt,name=time_extension_seconds,json=timeExtensionSeconds,proto3" json:"time_extension_seconds,omitempty"`
+	// ISO 4217 code of the lot prices, empty uses USD
+	Currency string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	// scheduled opening, the lot stays in preview until then. unset creates a pending lot
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// forward (highest bid wins) or reverse (procurement, lowest bid wins), empty creates a forward lot
+	Type string `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
+	// closing policy: soft (default, bids near the end extend the lot), hard (closes at end_time) or
+	// price_threshold (only bids moving the price by extension_price_threshold extend the lot)
+	ClosingMode string `protobuf:"bytes,9,opt,name=closing_mode,json=closingMode,proto3" json:"closing_mode,omitempty"`
+	// cap of the total extension, 0 means no cap
+	MaxExtensionSeconds     int64   `protobuf:"varint,10,opt,name=max_extension_seconds,json=maxExtensionSeconds,proto3" json:"max_extension_seconds,omitempty"`
+	ExtensionPriceThreshold float64 `protobuf:"fixed64,11,opt,name=extension_price_threshold,json=extensionPriceThreshold,proto3" json:"extension_price_threshold,omitempty"`
+	// identical units of the lot, 0 creates a single item lot. the quantity best bidders of a forward lot
+	// win one unit each
+	Quantity int32 `protobuf:"varint,12,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// what the winners of several units pay: pay_as_bid (default, their own bid) or uniform (the lowest
+	// winning bid)
+	Pricing       string `protobuf:"bytes,13,opt,name=pricing,proto3" json:"pricing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLotRequest) Reset() {
+	*x = CreateLotRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLotRequest) ProtoMessage() {}
+
+func (x *CreateLotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLotRequest.ProtoReflect.Descriptor instead.
+func (*CreateLotRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateLotRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateLotRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateLotRequest) GetInitialPrice() float64 {
+	if x != nil {
+		return x.InitialPrice
+	}
+	return 0
+}
+
+func (x *CreateLotRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *CreateLotRequest) GetTimeExtensionSeconds() int64 {
+	if x != nil {
+		return x.TimeExtensionSeconds
+	}
+	return 0
+}
+
+func (x *CreateLotRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateLotRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CreateLotRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateLotRequest) GetClosingMode() string {
+	if x != nil {
+		return x.ClosingMode
+	}
+	return ""
+}
+
+func (x *CreateLotRequest) GetMaxExtensionSeconds() int64 {
+	if x != nil {
+		return x.MaxExtensionSeconds
+	}
+	return 0
+}
+
+func (x *CreateLotRequest) GetExtensionPriceThreshold() float64 {
+	if x != nil {
+		return x.ExtensionPriceThreshold
+	}
+	return 0
+}
+
+func (x *CreateLotRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CreateLotRequest) GetPricing() string {
+	if x != nil {
+		return x.Pricing
+	}
+	return ""
+}
+
+// fields left unset are not changed
+type UpdateLotRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	LotId string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	// admin editing the lot, recorded in the lot event log
+	UpdatedBy     string                 `protobuf:"bytes,2,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	Title         *string                `protobuf:"bytes,3,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Description   *string                `protobuf:"bytes,4,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	InitialPrice  *float64               `protobuf:"fixed64,5,opt,name=initial_price,json=initialPrice,proto3,oneof" json:"initial_price,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLotRequest) Reset() {
+	*x = UpdateLotRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLotRequest) ProtoMessage() {}
+
+func (x *UpdateLotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLotRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLotRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateLotRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *UpdateLotRequest) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *UpdateLotRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateLotRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateLotRequest) GetInitialPrice() float64 {
+	if x != nil && x.InitialPrice != nil {
+		return *x.InitialPrice
+	}
+	return 0
+}
+
+func (x *UpdateLotRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type StartLotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartLotRequest) Reset() {
+	*x = StartLotRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartLotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartLotRequest) ProtoMessage() {}
+
+func (x *StartLotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartLotRequest.ProtoReflect.Descriptor instead.
+func (*StartLotRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartLotRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+type CancelLotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelLotRequest) Reset() {
+	*x = CancelLotRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelLotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelLotRequest) ProtoMessage() {}
+
+func (x *CancelLotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelLotRequest.ProtoReflect.Descriptor instead.
+func (*CancelLotRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelLotRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+type WatchLotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLotRequest) Reset() {
+	*x = WatchLotRequest{}
+	mi := &file_auction_v1_auction_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLotRequest) ProtoMessage() {}
+
+func (x *WatchLotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLotRequest.ProtoReflect.Descriptor instead.
+func (*WatchLotRequest) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchLotRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+type Bid struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LotId         string                 `protobuf:"bytes,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bid) Reset() {
+	*x = Bid{}
+	mi := &file_auction_v1_auction_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bid) ProtoMessage() {}
+
+func (x *Bid) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bid.ProtoReflect.Descriptor instead.
+func (*Bid) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Bid) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Bid) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *Bid) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Bid) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Bid) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type LotState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	InitialPrice  float64                `protobuf:"fixed64,4,opt,name=initial_price,json=initialPrice,proto3" json:"initial_price,omitempty"`
+	CurrentPrice  float64                `protobuf:"fixed64,5,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	State         string                 `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`
+	LastBidAmount float64                `protobuf:"fixed64,8,opt,name=last_bid_amount,json=lastBidAmount,proto3" json:"last_bid_amount,omitempty"`
+	LastBidUserId string                 `protobuf:"bytes,9,opt,name=last_bid_user_id,json=lastBidUserId,proto3" json:"last_bid_user_id,omitempty"`
+	LastBidTime   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_bid_time,json=lastBidTime,proto3" json:"last_bid_time,omitempty"`
+	// per lot monotonic sequence of the last applied event, gaps mean missed updates
+	Seq int64 `protobuf:"varint,11,opt,name=seq,proto3" json:"seq,omitempty"`
+	// per lot alias of the last bidder
+	LastBidPaddle int32  `protobuf:"varint,12,opt,name=last_bid_paddle,json=lastBidPaddle,proto3" json:"last_bid_paddle,omitempty"`
+	Currency      string `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	// current price converted to other currencies, informative only
+	IndicativePrices map[string]float64 `protobuf:"bytes,14,rep,name=indicative_prices,json=indicativePrices,proto3" json:"indicative_prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// scheduled opening of a lot in preview
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// forward or reverse
+	Type        string `protobuf:"bytes,16,opt,name=type,proto3" json:"type,omitempty"`
+	ClosingMode string `protobuf:"bytes,17,opt,name=closing_mode,json=closingMode,proto3" json:"closing_mode,omitempty"`
+	// latest end time the extensions can reach, unset without cap
+	MaxEndTime *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=max_end_time,json=maxEndTime,proto3" json:"max_end_time,omitempty"`
+	// units of the lot, 1 for a single item
+	Quantity int32 `protobuf:"varint,19,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// units of a multi-unit lot no bid holds yet
+	UnitsAvailable int32 `protobuf:"varint,20,opt,name=units_available,json=unitsAvailable,proto3" json:"units_available,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LotState) Reset() {
+	*x = LotState{}
+	mi := &file_auction_v1_auction_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LotState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotState) ProtoMessage() {}
+
+func (x *LotState) ProtoReflect() protoreflect.Message {
+	mi := &file_auction_v1_auction_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotState.ProtoReflect.Descriptor instead.
+func (*LotState) Descriptor() ([]byte, []int) {
+	return file_auction_v1_auction_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LotState) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *LotState) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LotState) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LotState) GetInitialPrice() float64 {
+	if x != nil {
+		return x.InitialPrice
+	}
+	return 0
+}
+
+func (x *LotState) GetCurrentPrice() float64 {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+func (x *LotState) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *LotState) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LotState) GetLastBidAmount() float64 {
+	if x != nil {
+		return x.LastBidAmount
+	}
+	return 0
+}
+
+func (x *LotState) GetLastBidUserId() string {
+	if x != nil {
+		return x.LastBidUserId
+	}
+	return ""
+}
+
+func (x *LotState) GetLastBidTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBidTime
+	}
+	return nil
+}
+
+func (x *LotState) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *LotState) GetLastBidPaddle() int32 {
+	if x != nil {
+		return x.LastBidPaddle
+	}
+	return 0
+}
+
+func (x *LotState) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *LotState) GetIndicativePrices() map[string]float64 {
+	if x != nil {
+		return x.IndicativePrices
+	}
+	return nil
+}
+
+func (x *LotState) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *LotState) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LotState) GetClosingMode() string {
+	if x != nil {
+		return x.ClosingMode
+	}
+	return ""
+}
+
+func (x *LotState) GetMaxEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaxEndTime
+	}
+	return nil
+}
+
+func (x *LotState) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LotState) GetUnitsAvailable() int32 {
+	if x != nil {
+		return x.UnitsAvailable
+	}
+	return 0
+}
+
+var File_auction_v1_auction_proto protoreflect.FileDescriptor
+
+const file_auction_v1_auction_proto_rawDesc = "" +
+	"\n" +
+	"\x18auction/v1/auction.proto\x12\n" +
+	"auction.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"u\n" +
+	"\x0fPlaceBidRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"5\n" +
+	"\x10PlaceBidResponse\x12!\n" +
+	"\x03bid\x18\x01 \x01(\v2\x0f.auction.v1.BidR\x03bid\"+\n" +
+	"\x12GetLotStateRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\"\x17\n" +
+	"\x15ListActiveLotsRequest\"B\n" +
+	"\x16ListActiveLotsResponse\x12(\n" +
+	"\x04lots\x18\x01 \x03(\v2\x14.auction.v1.LotStateR\x04lots\"\x90\x04\n" +
+	"\x10CreateLotRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12#\n" +
+	"\rinitial_price\x18\x03 \x01(\x01R\finitialPrice\x125\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x124\n" +
+	"\x16time_extension_seconds\x18\x05 \x01(\x03R\x14timeExtensionSeconds\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x129\n" +
+	"\n" +
+	"start_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12\x12\n" +
+	"\x04type\x18\b \x01(\tR\x04type\x12!\n" +
+	"\fclosing_mode\x18\t \x01(\tR\vclosingMode\x122\n" +
+	"\x15max_extension_seconds\x18\n" +
+	" \x01(\x03R\x13maxExtensionSeconds\x12:\n" +
+	"\x19extension_price_threshold\x18\v \x01(\x01R\x17extensionPriceThreshold\x12\x1a\n" +
+	"\bquantity\x18\f \x01(\x05R\bquantity\x12\x18\n" +
+	"\apricing\x18\r \x01(\tR\apricing\"\x97\x02\n" +
+	"\x10UpdateLotRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x02 \x01(\tR\tupdatedBy\x12\x19\n" +
+	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x04 \x01(\tH\x01R\vdescription\x88\x01\x01\x12(\n" +
+	"\rinitial_price\x18\x05 \x01(\x01H\x02R\finitialPrice\x88\x01\x01\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTimeB\b\n" +
+	"\x06_titleB\x0e\n" +
+	"\f_descriptionB\x10\n" +
+	"\x0e_initial_price\"(\n" +
+	"\x0fStartLotRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\")\n" +
+	"\x10CancelLotRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\"(\n" +
+	"\x0fWatchLotRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\"\x97\x01\n" +
+	"\x03Bid\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\tR\x05lotId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xea\x06\n" +
+	"\bLotState\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\rinitial_price\x18\x04 \x01(\x01R\finitialPrice\x12#\n" +
+	"\rcurrent_price\x18\x05 \x01(\x01R\fcurrentPrice\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05state\x18\a \x01(\tR\x05state\x12&\n" +
+	"\x0flast_bid_amount\x18\b \x01(\x01R\rlastBidAmount\x12'\n" +
+	"\x10last_bid_user_id\x18\t \x01(\tR\rlastBidUserId\x12>\n" +
+	"\rlast_bid_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vlastBidTime\x12\x10\n" +
+	"\x03seq\x18\v \x01(\x03R\x03seq\x12&\n" +
+	"\x0flast_bid_paddle\x18\f \x01(\x05R\rlastBidPaddle\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\x12W\n" +
+	"\x11indicative_prices\x18\x0e \x03(\v2*.auction.v1.LotState.IndicativePricesEntryR\x10indicativePrices\x129\n" +
+	"\n" +
+	"start_time\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12\x12\n" +
+	"\x04type\x18\x10 \x01(\tR\x04type\x12!\n" +
+	"\fclosing_mode\x18\x11 \x01(\tR\vclosingMode\x12<\n" +
+	"\fmax_end_time\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"maxEndTime\x12\x1a\n" +
+	"\bquantity\x18\x13 \x01(\x05R\bquantity\x12'\n" +
+	"\x0funits_available\x18\x14 \x01(\x05R\x0eunitsAvailable\x1aC\n" +
+	"\x15IndicativePricesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x012\xb8\x04\n" +
+	"\x0eAuctionService\x12E\n" +
+	"\bPlaceBid\x12\x1b.auction.v1.PlaceBidRequest\x1a\x1c.auction.v1.PlaceBidResponse\x12C\n" +
+	"\vGetLotState\x12\x1e.auction.v1.GetLotStateRequest\x1a\x14.auction.v1.LotState\x12W\n" +
+	"\x0eListActiveLots\x12!.auction.v1.ListActiveLotsRequest\x1a\".auction.v1.ListActiveLotsResponse\x12?\n" +
+	"\tCreateLot\x12\x1c.auction.v1.CreateLotRequest\x1a\x14.auction.v1.LotState\x12?\n" +
+	"\tUpdateLot\x12\x1c.auction.v1.UpdateLotRequest\x1a\x14.auction.v1.LotState\x12=\n" +
+	"\bStartLot\x12\x1b.auction.v1.StartLotRequest\x1a\x14.auction.v1.LotState\x12?\n" +
+	"\tCancelLot\x12\x1c.auction.v1.CancelLotRequest\x1a\x14.auction.v1.LotState\x12?\n" +
+	"\bWatchLot\x12\x1b.auction.v1.WatchLotRequest\x1a\x14.auction.v1.LotState0\x01BCZAgithub.com/cristianortiz/auctionEngine/pkg/auctionpb/v1;auctionpbb\x06proto3"
+
+var (
+	file_auction_v1_auction_proto_rawDescOnce sync.Once
+	file_auction_v1_auction_proto_rawDescData []byte
+)
+
+func file_auction_v1_auction_proto_rawDescGZIP() []byte {
+	file_auction_v1_auction_proto_rawDescOnce.Do(func() {
+		file_auction_v1_auction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_auction_v1_auction_proto_rawDesc), len(file_auction_v1_auction_proto_rawDesc)))
+	})
+	return file_auction_v1_auction_proto_rawDescData
+}
+
+var file_auction_v1_auction_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_auction_v1_auction_proto_goTypes = []any{
+	(*PlaceBidRequest)(nil),        // 0: auction.v1.PlaceBidRequest
+	(*PlaceBidResponse)(nil),       // 1: auction.v1.PlaceBidResponse
+	(*GetLotStateRequest)(nil),     // 2: auction.v1.GetLotStateRequest
+	(*ListActiveLotsRequest)(nil),  // 3: auction.v1.ListActiveLotsRequest
+	(*ListActiveLotsResponse)(nil), // 4: auction.v1.ListActiveLotsResponse
+	(*CreateLotRequest)(nil),       // 5: auction.v1.CreateLotRequest
+	(*UpdateLotRequest)(nil),       // 6: auction.v1.UpdateLotRequest
+	(*StartLotRequest)(nil),        // 7: auction.v1.StartLotRequest
+	(*CancelLotRequest)(nil),       // 8: auction.v1.CancelLotRequest
+	(*WatchLotRequest)(nil),        // 9: auction.v1.WatchLotRequest
+	(*Bid)(nil),                    // 10: auction.v1.Bid
+	(*LotState)(nil),               // 11: auction.v1.LotState
+	nil,                            // 12: auction.v1.LotState.IndicativePricesEntry
+	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
+}
+var file_auction_v1_auction_proto_depIdxs = []int32{
+	10, // 0: auction.v1.PlaceBidResponse.bid:type_name -> auction.v1.Bid
+	11, // 1: auction.v1.ListActiveLotsResponse.lots:type_name -> auction.v1.LotState
+	13, // 2: auction.v1.CreateLotRequest.end_time:type_name -> google.protobuf.Timestamp
+	13, // 3: auction.v1.CreateLotRequest.start_time:type_name -> google.protobuf.Timestamp
+	13, // 4: auction.v1.UpdateLotRequest.end_time:type_name -> google.protobuf.Timestamp
+	13, // 5: auction.v1.Bid.timestamp:type_name -> google.protobuf.Timestamp
+	13, // 6: auction.v1.LotState.end_time:type_name -> google.protobuf.Timestamp
+	13, // 7: auction.v1.LotState.last_bid_time:type_name -> google.protobuf.Timestamp
+	12, // 8: auction.v1.LotState.indicative_prices:type_name -> auction.v1.LotState.IndicativePricesEntry
+	13, // 9: auction.v1.LotState.start_time:type_name -> google.protobuf.Timestamp
+	13, // 10: auction.v1.LotState.max_end_time:type_name -> google.protobuf.Timestamp
+	0,  // 11: auction.v1.AuctionService.PlaceBid:input_type -> auction.v1.PlaceBidRequest
+	2,  // 12: auction.v1.AuctionService.GetLotState:input_type -> auction.v1.GetLotStateRequest
+	3,  // 13: auction.v1.AuctionService.ListActiveLots:input_type -> auction.v1.ListActiveLotsRequest
+	5,  // 14: auction.v1.AuctionService.CreateLot:input_type -> auction.v1.CreateLotRequest
+	6,  // 15: auction.v1.AuctionService.UpdateLot:input_type -> auction.v1.UpdateLotRequest
+	7,  // 16: auction.v1.AuctionService.StartLot:input_type -> auction.v1.StartLotRequest
+	8,  // 17: auction.v1.AuctionService.CancelLot:input_type -> auction.v1.CancelLotRequest
+	9,  // 18: auction.v1.AuctionService.WatchLot:input_type -> auction.v1.WatchLotRequest
+	1,  // 19: auction.v1.AuctionService.PlaceBid:output_type -> auction.v1.PlaceBidResponse
+	11, // 20: auction.v1.AuctionService.GetLotState:output_type -> auction.v1.LotState
+	4,  // 21: auction.v1.AuctionService.ListActiveLots:output_type -> auction.v1.ListActiveLotsResponse
+	11, // 22: auction.v1.AuctionService.CreateLot:output_type -> auction.v1.LotState
+	11, // 23: auction.v1.AuctionService.UpdateLot:output_type -> auction.v1.LotState
+	11, // 24: auction.v1.AuctionService.StartLot:output_type -> auction.v1.LotState
+	11, // 25: auction.v1.AuctionService.CancelLot:output_type -> auction.v1.LotState
+	11, // 26: auction.v1.AuctionService.WatchLot:output_type -> auction.v1.LotState
+	19, // [19:27] is the sub-list for method output_type
+	11, // [11:19] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_auction_v1_auction_proto_init() }
+func file_auction_v1_auction_proto_init() {
+	if File_auction_v1_auction_proto != nil {
+		return
+	}
+	file_auction_v1_auction_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auction_v1_auction_proto_rawDesc), len(file_auction_v1_auction_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_auction_v1_auction_proto_goTypes,
+		DependencyIndexes: file_auction_v1_auction_proto_depIdxs,
+		MessageInfos:      file_auction_v1_auction_proto_msgTypes,
+	}.Build()
+	File_auction_v1_auction_proto = out.File
+	file_auction_v1_auction_proto_goTypes = nil
+	file_auction_v1_auction_proto_depIdxs = nil
+}
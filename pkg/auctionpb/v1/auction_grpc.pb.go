@@ -0,0 +1,411 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: auction/v1/auction.proto
+
+package auctionpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AuctionService_PlaceBid_FullMethodName       = "/auction.v1.AuctionService/PlaceBid"
+	AuctionService_GetLotState_FullMethodName    = "/auction.v1.AuctionService/GetLotState"
+	AuctionService_ListActiveLots_FullMethodName = "/auction.v1.AuctionService/ListActiveLots"
+	AuctionService_CreateLot_FullMethodName      = "/auction.v1.AuctionService/CreateLot"
+	AuctionService_UpdateLot_FullMethodName      = "/auction.v1.AuctionService/UpdateLot"
+	AuctionService_StartLot_FullMethodName       = "/auction.v1.AuctionService/StartLot"
+	AuctionService_CancelLot_FullMethodName      = "/auction.v1.AuctionService/CancelLot"
+	AuctionService_WatchLot_FullMethodName       = "/auction.v1.AuctionService/WatchLot"
+)
+
+// AuctionServiceClient is the client API for AuctionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AuctionService exposes the auction engine to other backend services (payments, inventory, ...)
+type AuctionServiceClient interface {
+	// PlaceBid places a bid on behalf of user_id
+	PlaceBid(ctx context.Context, in *PlaceBidRequest, opts ...grpc.CallOption) (*PlaceBidResponse, error)
+	// GetLotState returns the current state of a lot
+	GetLotState(ctx context.Context, in *GetLotStateRequest, opts ...grpc.CallOption) (*LotState, error)
+	// ListActiveLots returns all the active lots
+	ListActiveLots(ctx context.Context, in *ListActiveLotsRequest, opts ...grpc.CallOption) (*ListActiveLotsResponse, error)
+	// CreateLot creates a new pending lot
+	CreateLot(ctx context.Context, in *CreateLotRequest, opts ...grpc.CallOption) (*LotState, error)
+	// UpdateLot edits the listing of a pending or preview lot
+	UpdateLot(ctx context.Context, in *UpdateLotRequest, opts ...grpc.CallOption) (*LotState, error)
+	// StartLot opens a pending lot for bidding
+	StartLot(ctx context.Context, in *StartLotRequest, opts ...grpc.CallOption) (*LotState, error)
+	// CancelLot cancels a pending or active lot
+	CancelLot(ctx context.Context, in *CancelLotRequest, opts ...grpc.CallOption) (*LotState, error)
+	// WatchLot streams the current state of a lot followed by every update until the client cancels
+	WatchLot(ctx context.Context, in *WatchLotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LotState], error)
+}
+
+type auctionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuctionServiceClient(cc grpc.ClientConnInterface) AuctionServiceClient {
+	return &auctionServiceClient{cc}
+}
+
+func (c *auctionServiceClient) PlaceBid(ctx context.Context, in *PlaceBidRequest, opts ...grpc.CallOption) (*PlaceBidResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaceBidResponse)
+	err := c.cc.Invoke(ctx, AuctionService_PlaceBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) GetLotState(ctx context.Context, in *GetLotStateRequest, opts ...grpc.CallOption) (*LotState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LotState)
+	err := c.cc.Invoke(ctx, AuctionService_GetLotState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) ListActiveLots(ctx context.Context, in *ListActiveLotsRequest, opts ...grpc.CallOption) (*ListActiveLotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActiveLotsResponse)
+	err := c.cc.Invoke(ctx, AuctionService_ListActiveLots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) CreateLot(ctx context.Context, in *CreateLotRequest, opts ...grpc.CallOption) (*LotState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LotState)
+	err := c.cc.Invoke(ctx, AuctionService_CreateLot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) UpdateLot(ctx context.Context, in *UpdateLotRequest, opts ...grpc.CallOption) (*LotState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LotState)
+	err := c.cc.Invoke(ctx, AuctionService_UpdateLot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) StartLot(ctx context.Context, in *StartLotRequest, opts ...grpc.CallOption) (*LotState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LotState)
+	err := c.cc.Invoke(ctx, AuctionService_StartLot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) CancelLot(ctx context.Context, in *CancelLotRequest, opts ...grpc.CallOption) (*LotState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LotState)
+	err := c.cc.Invoke(ctx, AuctionService_CancelLot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) WatchLot(ctx context.Context, in *WatchLotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LotState], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuctionService_ServiceDesc.Streams[0], AuctionService_WatchLot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLotRequest, LotState]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuctionService_WatchLotClient = grpc.ServerStreamingClient[LotState]
+
+// AuctionServiceServer is the server API for AuctionService service.
+// All implementations must embed UnimplementedAuctionServiceServer
+// for forward compatibility.
+//
+// AuctionService exposes the auction engine to other backend services (payments, inventory, ...)
+type AuctionServiceServer interface {
+	// PlaceBid places a bid on behalf of user_id
+	PlaceBid(context.Context, *PlaceBidRequest) (*PlaceBidResponse, error)
+	// GetLotState returns the current state of a lot
+	GetLotState(context.Context, *GetLotStateRequest) (*LotState, error)
+	// ListActiveLots returns all the active lots
+	ListActiveLots(context.Context, *ListActiveLotsRequest) (*ListActiveLotsResponse, error)
+	// CreateLot creates a new pending lot
+	CreateLot(context.Context, *CreateLotRequest) (*LotState, error)
+	// UpdateLot edits the listing of a pending or preview lot
+	UpdateLot(context.Context, *UpdateLotRequest) (*LotState, error)
+	// StartLot opens a pending lot for bidding
+	StartLot(context.Context, *StartLotRequest) (*LotState, error)
+	// CancelLot cancels a pending or active lot
+	CancelLot(context.Context, *CancelLotRequest) (*LotState, error)
+	// WatchLot streams the current state of a lot followed by every update until the client cancels
+	WatchLot(*WatchLotRequest, grpc.ServerStreamingServer[LotState]) error
+	mustEmbedUnimplementedAuctionServiceServer()
+}
+
+// UnimplementedAuctionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuctionServiceServer struct{}
+
+func (UnimplementedAuctionServiceServer) PlaceBid(context.Context, *PlaceBidRequest) (*PlaceBidResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PlaceBid not implemented")
+}
+func (UnimplementedAuctionServiceServer) GetLotState(context.Context, *GetLotStateRequest) (*LotState, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLotState not implemented")
+}
+func (UnimplementedAuctionServiceServer) ListActiveLots(context.Context, *ListActiveLotsRequest) (*ListActiveLotsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListActiveLots not implemented")
+}
+func (UnimplementedAuctionServiceServer) CreateLot(context.Context, *CreateLotRequest) (*LotState, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateLot not implemented")
+}
+func (UnimplementedAuctionServiceServer) UpdateLot(context.Context, *UpdateLotRequest) (*LotState, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateLot not implemented")
+}
+func (UnimplementedAuctionServiceServer) StartLot(context.Context, *StartLotRequest) (*LotState, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartLot not implemented")
+}
+func (UnimplementedAuctionServiceServer) CancelLot(context.Context, *CancelLotRequest) (*LotState, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelLot not implemented")
+}
+func (UnimplementedAuctionServiceServer) WatchLot(*WatchLotRequest, grpc.ServerStreamingServer[LotState]) error {
+	return status.Error(codes.Unimplemented, "method WatchLot not implemented")
+}
+func (UnimplementedAuctionServiceServer) mustEmbedUnimplementedAuctionServiceServer() {}
+func (UnimplementedAuctionServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeAuctionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuctionServiceServer will
+// result in compilation errors.
+type UnsafeAuctionServiceServer interface {
+	mustEmbedUnimplementedAuctionServiceServer()
+}
+
+func RegisterAuctionServiceServer(s grpc.ServiceRegistrar, srv AuctionServiceServer) {
+	// If the following call panics, it indicates UnimplementedAuctionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuctionService_ServiceDesc, srv)
+}
+
+func _AuctionService_PlaceBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).PlaceBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_PlaceBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).PlaceBid(ctx, req.(*PlaceBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_GetLotState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLotStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).GetLotState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_GetLotState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).GetLotState(ctx, req.(*GetLotStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_ListActiveLots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveLotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).ListActiveLots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_ListActiveLots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).ListActiveLots(ctx, req.(*ListActiveLotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_CreateLot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).CreateLot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_CreateLot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).CreateLot(ctx, req.(*CreateLotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_UpdateLot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).UpdateLot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_UpdateLot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).UpdateLot(ctx, req.(*UpdateLotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_StartLot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartLotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).StartLot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_StartLot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).StartLot(ctx, req.(*StartLotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_CancelLot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelLotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).CancelLot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_CancelLot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).CancelLot(ctx, req.(*CancelLotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_WatchLot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuctionServiceServer).WatchLot(m, &grpc.GenericServerStream[WatchLotRequest, LotState]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuctionService_WatchLotServer = grpc.ServerStreamingServer[LotState]
+
+// AuctionService_ServiceDesc is the grpc.ServiceDesc for AuctionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuctionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auction.v1.AuctionService",
+	HandlerType: (*AuctionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceBid",
+			Handler:    _AuctionService_PlaceBid_Handler,
+		},
+		{
+			MethodName: "GetLotState",
+			Handler:    _AuctionService_GetLotState_Handler,
+		},
+		{
+			MethodName: "ListActiveLots",
+			Handler:    _AuctionService_ListActiveLots_Handler,
+		},
+		{
+			MethodName: "CreateLot",
+			Handler:    _AuctionService_CreateLot_Handler,
+		},
+		{
+			MethodName: "UpdateLot",
+			Handler:    _AuctionService_UpdateLot_Handler,
+		},
+		{
+			MethodName: "StartLot",
+			Handler:    _AuctionService_StartLot_Handler,
+		},
+		{
+			MethodName: "CancelLot",
+			Handler:    _AuctionService_CancelLot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLot",
+			Handler:       _AuctionService_WatchLot_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "auction/v1/auction.proto",
+}
@@ -0,0 +1,457 @@
+// Package bidderclient is a Go client of the engine WS protocol for bots and test harnesses: it keeps a
+// connection to a lot open, reconnecting and resuming from the last seen seq when it drops, places
+// bids waiting for their outcome and fans the server msgs out to subscribers
+package bidderclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/pkg/wsproto"
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrClosed is returned by the operations of a closed client
+	ErrClosed = errors.New("bidder client closed")
+	// ErrNotConnected is returned when a msg is sent while the client is reconnecting
+	ErrNotConnected = errors.New("bidder client not connected")
+	// ErrDisconnected is returned by PlaceBid when the connection dropped before the bid result arrived,
+	// the bid may or may not have been placed
+	ErrDisconnected = errors.New("connection lost before the bid result")
+	// ErrBidRejected is wrapped by the error of PlaceBid for bids the engine refused
+	ErrBidRejected = errors.New("bid rejected")
+)
+
+const (
+	defaultReconnectMin = 500 * time.Millisecond
+	defaultReconnectMax = 30 * time.Second
+	defaultMaxRetries   = 3
+	// subscriberBuffer is the buffer of the subscription channels, msgs to a full channel are dropped
+	subscriberBuffer = 256
+	writeWait        = 10 * time.Second
+)
+
+// Config holds the client settings, only URL and LotID are required
+type Config struct {
+	// URL of the engine, e.g. ws://localhost:8080, or wss:// when the engine serves TLS
+	URL   string
+	LotID uuid.UUID
+	// Token authenticates the user, without it the client connects as an anonymous spectator
+	// (if the engine allows it) and can't bid
+	Token string
+	// Dialer defaults to websocket.DefaultDialer
+	Dialer *websocket.Dialer
+	// ReconnectMin and ReconnectMax bound the exponential backoff between reconnection attempts
+	ReconnectMin time.Duration
+	ReconnectMax time.Duration
+	// MaxRetries is how many times a bid dropped by the engine under load is resent
+	MaxRetries int
+	// OnConnect is called after every (re)connection, OnDisconnect with the error that dropped it
+	OnConnect    func()
+	OnDisconnect func(err error)
+}
+
+// Client is a connection to a lot that survives network drops and engine restarts
+type Client struct {
+	cfg    Config
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	seq     int64 // last lot update seen, sent as last_event_seq on reconnect
+	pending map[string]chan wsproto.Envelope
+	subs    map[*subscription]struct{}
+	// connToken is the connection_token of the last server_connected, sent on reconnect to keep the client ID
+	connToken string
+
+	writeMu sync.Mutex
+}
+
+type subscription struct {
+	types map[wsproto.MessageType]bool // empty receives every msg
+	ch    chan wsproto.Envelope
+}
+
+// Connect dials the lot and keeps the connection alive until ctx is done or Close is called, the first
+// dial must succeed
+func Connect(ctx context.Context, cfg Config) (*Client, error) {
+	if cfg.URL == "" || cfg.LotID == uuid.Nil {
+		return nil, errors.New("bidder client: URL and lot ID are required")
+	}
+	if cfg.Dialer == nil {
+		cfg.Dialer = websocket.DefaultDialer
+	}
+	if cfg.ReconnectMin <= 0 {
+		cfg.ReconnectMin = defaultReconnectMin
+	}
+	if cfg.ReconnectMax < cfg.ReconnectMin {
+		cfg.ReconnectMax = max(defaultReconnectMax, cfg.ReconnectMin)
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+
+	c := &Client{
+		cfg:     cfg,
+		done:    make(chan struct{}),
+		pending: make(map[string]chan wsproto.Envelope),
+		subs:    make(map[*subscription]struct{}),
+	}
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.cancel()
+		case <-c.ctx.Done():
+		}
+	}()
+	c.setConn(conn)
+	go c.run(conn)
+	return c, nil
+}
+
+// Close ends the connection and the subscriptions, pending bids fail with ErrClosed
+func (c *Client) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// Seq returns the seq of the last lot update received
+func (c *Client) Seq() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seq
+}
+
+// Subscribe returns a channel receiving the server msgs of the given types, every msg without types.
+// msgs are dropped while the channel is full, so slow consumers should resync from the next
+// server_initial_state or server_lot_update. The channel is closed by cancel or when the client closes
+func (c *Client) Subscribe(types ...wsproto.MessageType) (<-chan wsproto.Envelope, func()) {
+	sub := &subscription{types: make(map[wsproto.MessageType]bool), ch: make(chan wsproto.Envelope, subscriberBuffer)}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subs == nil {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	c.subs[sub] = struct{}{}
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if _, ok := c.subs[sub]; ok {
+				delete(c.subs, sub)
+				close(sub.ch)
+			}
+		})
+	}
+}
+
+// PlaceBid bids amount on the lot and waits for its outcome. Rejected bids return the result with an
+// error wrapping ErrBidRejected, bids dropped by the engine under load are resent up to MaxRetries times
+func (c *Client) PlaceBid(ctx context.Context, amount float64) (*wsproto.ServerBidResultMessage, error) {
+	msg := wsproto.ClientBidMessage{
+		BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeClientBid},
+		MessageID:   uuid.NewString(),
+	}
+	msg.Payload.LotID = c.cfg.LotID
+	msg.Payload.Amount = amount
+	return c.bid(ctx, msg.MessageID, msg)
+}
+
+// QuickBid bids the next min bid of the lot, whatever its price when the engine processes the bid, and
+// waits for its outcome as PlaceBid does. maxAmount bounds the amount when set, the result carries the
+// amount bid
+func (c *Client) QuickBid(ctx context.Context, maxAmount float64) (*wsproto.ServerBidResultMessage, error) {
+	msg := wsproto.ClientQuickBidMessage{
+		BaseMessage: wsproto.BaseMessage{Type: wsproto.MessageTypeClientQuickBid},
+		MessageID:   uuid.NewString(),
+	}
+	msg.Payload.LotID = c.cfg.LotID
+	msg.Payload.MaxAmount = maxAmount
+	return c.bid(ctx, msg.MessageID, msg)
+}
+
+// bid sends the bid msg with messageID and waits for its bid result
+func (c *Client) bid(ctx context.Context, messageID string, msg any) (*wsproto.ServerBidResultMessage, error) {
+	replies := make(chan wsproto.Envelope, 1)
+	c.mu.Lock()
+	if c.pending == nil {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	c.pending[messageID] = replies
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		if c.pending != nil {
+			delete(c.pending, messageID)
+		}
+		c.mu.Unlock()
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if err := c.send(msg); err != nil {
+			return nil, err
+		}
+		var reply wsproto.Envelope
+		var ok bool
+		select {
+		case reply, ok = <-replies:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !ok {
+			return nil, c.disconnectErr()
+		}
+
+		switch reply.Type {
+		case wsproto.MessageTypeServerBidResult:
+			var result wsproto.ServerBidResultMessage
+			if err := json.Unmarshal(reply.Payload, &result.Payload); err != nil {
+				return nil, fmt.Errorf("invalid bid result: %w", err)
+			}
+			result.Type = reply.Type
+			if result.Payload.Status != wsproto.BidResultAccepted {
+				return &result, fmt.Errorf("%w: %s", ErrBidRejected, result.Payload.Error)
+			}
+			return &result, nil
+		case wsproto.MessageTypeServerRetry:
+			if attempt >= c.cfg.MaxRetries {
+				return nil, fmt.Errorf("bid dropped by the engine %d times", attempt+1)
+			}
+			var retry wsproto.ServerRetryMessage
+			_ = json.Unmarshal(reply.Payload, &retry.Payload)
+			select {
+			case <-time.After(time.Duration(retry.Payload.RetryAfterMs) * time.Millisecond):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// a new channel, the old one may be closed by a reconnection
+			replies = make(chan wsproto.Envelope, 1)
+			c.mu.Lock()
+			if c.pending == nil {
+				c.mu.Unlock()
+				return nil, ErrClosed
+			}
+			c.pending[messageID] = replies
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Send writes any client msg, e.g. a wsproto.ClientChatMessage, it fails while the client is reconnecting
+func (c *Client) Send(msg any) error {
+	return c.send(msg)
+}
+
+func (c *Client) send(msg any) error {
+	c.mu.Lock()
+	conn, closed := c.conn, c.subs == nil
+	c.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	if conn == nil {
+		return ErrNotConnected
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to send msg: %w", err)
+	}
+	return nil
+}
+
+// disconnectErr tells a pending bid why its reply channel was closed
+func (c *Client) disconnectErr() error {
+	if c.ctx.Err() != nil {
+		return ErrClosed
+	}
+	return ErrDisconnected
+}
+
+// run reads conn until it fails, then reconnects with backoff until the client closes
+func (c *Client) run(conn *websocket.Conn) {
+	defer c.shutdown()
+	// the read loop is blocked on the connection, closing it on shutdown makes it return
+	go func() {
+		<-c.ctx.Done()
+		c.mu.Lock()
+		if c.conn != nil {
+			_ = c.conn.Close()
+		}
+		c.mu.Unlock()
+	}()
+
+	for {
+		err := c.readLoop(conn)
+		c.setConn(nil)
+		_ = conn.Close()
+		if c.ctx.Err() != nil {
+			return
+		}
+		if c.cfg.OnDisconnect != nil {
+			c.cfg.OnDisconnect(err)
+		}
+		if conn = c.reconnect(restartWait(err)); conn == nil {
+			return
+		}
+		c.setConn(conn)
+	}
+}
+
+// reconnect dials until it succeeds or the client closes, nil means closed. the first dial waits at
+// least wait, the retry after of a restarting server
+func (c *Client) reconnect(wait time.Duration) *websocket.Conn {
+	backoff := max(c.cfg.ReconnectMin, wait)
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-c.ctx.Done():
+			return nil
+		}
+		conn, err := c.dial(c.ctx)
+		if err == nil {
+			return conn
+		}
+		backoff = min(backoff*2, c.cfg.ReconnectMax)
+	}
+}
+
+// restartWait returns the retry after of the close frame of a restarting server, 0 for other errors
+func restartWait(err error) time.Duration {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != wsproto.CloseServiceRestart {
+		return 0
+	}
+	var reason wsproto.RestartCloseReason
+	if json.Unmarshal([]byte(closeErr.Text), &reason) != nil {
+		return 0
+	}
+	return time.Duration(reason.RetryAfterMs) * time.Millisecond
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	u, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid engine URL: %w", err)
+	}
+	u = u.JoinPath("/ws/auction", c.cfg.LotID.String())
+	q := u.Query()
+	if c.cfg.Token != "" {
+		q.Set("token", c.cfg.Token)
+	}
+	if seq := c.Seq(); seq > 0 {
+		q.Set(wsproto.QueryLastEventSeq, strconv.FormatInt(seq, 10))
+	}
+	c.mu.Lock()
+	if c.connToken != "" {
+		q.Set(wsproto.QueryConnectionToken, c.connToken)
+	}
+	c.mu.Unlock()
+	u.RawQuery = q.Encode()
+
+	conn, _, err := c.cfg.Dialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to lot %s: %w", c.cfg.LotID, err)
+	}
+	return conn, nil
+}
+
+// setConn swaps the current connection, pending bids of a lost connection fail with ErrDisconnected
+func (c *Client) setConn(conn *websocket.Conn) {
+	c.mu.Lock()
+	c.conn = conn
+	if conn == nil {
+		for id, replies := range c.pending {
+			close(replies)
+			delete(c.pending, id)
+		}
+	}
+	c.mu.Unlock()
+	if conn != nil && c.cfg.OnConnect != nil {
+		c.cfg.OnConnect()
+	}
+}
+
+func (c *Client) readLoop(conn *websocket.Conn) error {
+	for {
+		var msg wsproto.Envelope
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		c.dispatch(msg)
+	}
+}
+
+// dispatch tracks the lot seq, routes bid replies to their PlaceBid and fans msg out to the subscribers
+func (c *Client) dispatch(msg wsproto.Envelope) {
+	var ids struct {
+		MessageID       string `json:"message_id"`
+		Seq             int64  `json:"seq"`
+		ConnectionToken string `json:"connection_token"`
+	}
+	_ = json.Unmarshal(msg.Payload, &ids)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch msg.Type {
+	case wsproto.MessageTypeServerConnected:
+		c.connToken = ids.ConnectionToken
+	case wsproto.MessageTypeServerInitialState, wsproto.MessageTypeServerLotUpdate, wsproto.MessageTypeServerLotDelta:
+		c.seq = max(c.seq, ids.Seq)
+	case wsproto.MessageTypeServerBidResult, wsproto.MessageTypeServerRetry:
+		if replies, ok := c.pending[ids.MessageID]; ok && ids.MessageID != "" {
+			select {
+			case replies <- msg:
+			default:
+			}
+		}
+	}
+	for sub := range c.subs {
+		if len(sub.types) > 0 && !sub.types[msg.Type] {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+		}
+	}
+}
+
+// shutdown fails the pending bids and closes the subscriptions once the client is closed
+func (c *Client) shutdown() {
+	c.mu.Lock()
+	for id, replies := range c.pending {
+		close(replies)
+		delete(c.pending, id)
+	}
+	c.pending = nil
+	for sub := range c.subs {
+		close(sub.ch)
+	}
+	c.subs = nil
+	c.mu.Unlock()
+	close(c.done)
+}
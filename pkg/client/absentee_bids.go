@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// SubmitAbsenteeBid leaves an absentee bid on a lot not opened yet, entered by the engine when it opens
+func (c *Client) SubmitAbsenteeBid(ctx context.Context, lotID uuid.UUID, req SubmitAbsenteeBidRequest) (*AbsenteeBid, error) {
+	var bid AbsenteeBid
+	if err := c.doJSON(ctx, http.MethodPost, "/api/lots/"+lotID.String()+"/absentee-bids", nil, req, &bid); err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// ListLotAbsenteeBids returns the absentee bids of a lot in submission order, admins only
+func (c *Client) ListLotAbsenteeBids(ctx context.Context, lotID uuid.UUID) ([]AbsenteeBid, error) {
+	var bids []AbsenteeBid
+	err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/absentee-bids", nil, nil, &bids)
+	return bids, err
+}
+
+// ListMyAbsenteeBids returns the absentee bids of the caller, newest first
+func (c *Client) ListMyAbsenteeBids(ctx context.Context, page Page) ([]AbsenteeBid, error) {
+	var bids []AbsenteeBid
+	err := c.doJSON(ctx, http.MethodGet, "/api/users/me/absentee-bids", page.values(nil), nil, &bids)
+	return bids, err
+}
+
+// GetMyAbsenteeBid returns an absentee bid of the caller with its processing status
+func (c *Client) GetMyAbsenteeBid(ctx context.Context, id uuid.UUID) (*AbsenteeBid, error) {
+	var bid AbsenteeBid
+	if err := c.doJSON(ctx, http.MethodGet, "/api/users/me/absentee-bids/"+id.String(), nil, nil, &bid); err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// CancelMyAbsenteeBid cancels a pending absentee bid of the caller
+func (c *Client) CancelMyAbsenteeBid(ctx context.Context, id uuid.UUID) (*AbsenteeBid, error) {
+	var bid AbsenteeBid
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/users/me/absentee-bids/"+id.String(), nil, nil, &bid); err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
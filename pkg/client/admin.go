@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// StartReplay replays the recorded events of a lot at speed (0 is 1x), admins only
+func (c *Client) StartReplay(ctx context.Context, lotID uuid.UUID, speed float64) (*Replay, error) {
+	body := struct {
+		LotID uuid.UUID `json:"lot_id"`
+		Speed float64   `json:"speed,omitempty"`
+	}{LotID: lotID, Speed: speed}
+	var replay Replay
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/replays", nil, body, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// ListReplays returns the running and finished replays, admins only
+func (c *Client) ListReplays(ctx context.Context) ([]Replay, error) {
+	var replays []Replay
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/replays", nil, nil, &replays)
+	return replays, err
+}
+
+// StopReplay stops a replay, admins only
+func (c *Client) StopReplay(ctx context.Context, replayID uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/admin/replays/"+replayID.String(), nil, nil, nil)
+}
+
+// ListFraudAlerts returns the fraud alerts, an empty status lists all of them, admins only
+func (c *Client) ListFraudAlerts(ctx context.Context, status string, page Page) ([]FraudAlert, error) {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+	var alerts []FraudAlert
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/fraud-alerts", page.values(q), nil, &alerts)
+	return alerts, err
+}
+
+// ReviewFraudAlert records the review of an alert, status is dismissed or confirmed, admins only
+func (c *Client) ReviewFraudAlert(ctx context.Context, alertID uuid.UUID, status, note string) (*FraudAlert, error) {
+	body := struct {
+		Status string `json:"status"`
+		Note   string `json:"note,omitempty"`
+	}{Status: status, Note: note}
+	var alert FraudAlert
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/fraud-alerts/"+alertID.String()+"/review", nil, body, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetLogLevels returns the runtime log levels, admins only
+func (c *Client) GetLogLevels(ctx context.Context) (*LogLevels, error) {
+	var levels LogLevels
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/log-level", nil, nil, &levels); err != nil {
+		return nil, err
+	}
+	return &levels, nil
+}
+
+// SetLogLevel changes a log level at runtime, an empty module changes the default level and an
+// empty level removes the module override, admins only
+func (c *Client) SetLogLevel(ctx context.Context, module, level string) (*LogLevels, error) {
+	body := struct {
+		Module string `json:"module"`
+		Level  string `json:"level"`
+	}{Module: module, Level: level}
+	var levels LogLevels
+	if err := c.doJSON(ctx, http.MethodPut, "/api/admin/log-level", nil, body, &levels); err != nil {
+		return nil, err
+	}
+	return &levels, nil
+}
+
+// GetMigrations returns the schema version of the engine database, admins only
+func (c *Client) GetMigrations(ctx context.Context) (*MigrationInfo, error) {
+	var info MigrationInfo
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/migrations", nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AnnounceMaintenance sends a maintenance notice to every connected client, admins only
+func (c *Client) AnnounceMaintenance(ctx context.Context, req MaintenanceNoticeRequest) (*MaintenanceNotice, error) {
+	var notice MaintenanceNotice
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/maintenance-notices", nil, req, &notice); err != nil {
+		return nil, err
+	}
+	return &notice, nil
+}
+
+// GetMaintenanceMode returns the state of the read-only mode, admins only
+func (c *Client) GetMaintenanceMode(ctx context.Context) (*MaintenanceMode, error) {
+	var mode MaintenanceMode
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/maintenance-mode", nil, nil, &mode); err != nil {
+		return nil, err
+	}
+	return &mode, nil
+}
+
+// SetMaintenanceMode turns the read-only mode on or off, admins only
+func (c *Client) SetMaintenanceMode(ctx context.Context, readOnly bool) (*MaintenanceMode, error) {
+	var mode MaintenanceMode
+	if err := c.doJSON(ctx, http.MethodPut, "/api/admin/maintenance-mode", nil, MaintenanceMode{ReadOnly: readOnly}, &mode); err != nil {
+		return nil, err
+	}
+	return &mode, nil
+}
+
+// ExportUserData returns all the data tied to a user, admins only
+func (c *Client) ExportUserData(ctx context.Context, userID uuid.UUID) (*UserData, error) {
+	var data UserData
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/users/"+userID.String()+"/data-export", nil, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// RequestUserErasure records a pending request to erase the personal data of a user, admins only
+func (c *Client) RequestUserErasure(ctx context.Context, userID uuid.UUID, reason string) (*ErasureRequest, error) {
+	body := struct {
+		Reason string `json:"reason,omitempty"`
+	}{Reason: reason}
+	var request ErasureRequest
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/users/"+userID.String()+"/erasure-requests", nil, body, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListErasureRequests returns the erasure requests, an empty status lists all of them, admins only
+func (c *Client) ListErasureRequests(ctx context.Context, status string, page Page) ([]ErasureRequest, error) {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+	var requests []ErasureRequest
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/erasure-requests", page.values(q), nil, &requests)
+	return requests, err
+}
+
+// ApproveErasureRequest erases the personal data of the user of a pending request, admins only
+func (c *Client) ApproveErasureRequest(ctx context.Context, requestID uuid.UUID, note string) (*ErasureRequest, error) {
+	return c.reviewErasureRequest(ctx, requestID, "approve", note)
+}
+
+// RejectErasureRequest closes a pending request without erasing anything, admins only
+func (c *Client) RejectErasureRequest(ctx context.Context, requestID uuid.UUID, note string) (*ErasureRequest, error) {
+	return c.reviewErasureRequest(ctx, requestID, "reject", note)
+}
+
+func (c *Client) reviewErasureRequest(ctx context.Context, requestID uuid.UUID, action, note string) (*ErasureRequest, error) {
+	body := struct {
+		Note string `json:"note,omitempty"`
+	}{Note: note}
+	var request ErasureRequest
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/erasure-requests/"+requestID.String()+"/"+action, nil, body, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetLiveness runs the liveness probe, a failing probe returns its checks with an APIError
+func (c *Client) GetLiveness(ctx context.Context) (*Health, error) {
+	return c.health(ctx, "/healthz")
+}
+
+// GetReadiness runs the readiness probe, a failing probe returns its checks with an APIError
+func (c *Client) GetReadiness(ctx context.Context) (*Health, error) {
+	return c.health(ctx, "/readyz")
+}
+
+// health decodes the probe body also on 503, where it tells the failing checks
+func (c *Client) health(ctx context.Context, path string) (*Health, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	var health Health
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: "undecodable health response", RequestID: resp.Header.Get(HeaderRequestID)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &health, &APIError{StatusCode: resp.StatusCode, Message: health.Status, RequestID: resp.Header.Get(HeaderRequestID)}
+	}
+	return &health, nil
+}
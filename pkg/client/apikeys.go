@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// IssueAPIKey issues a key in the caller organization, the returned Key is only shown once, admins only
+func (c *Client) IssueAPIKey(ctx context.Context, req IssueAPIKeyRequest) (*APIKey, error) {
+	var key APIKey
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/api-keys", nil, req, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns the keys of the caller organization, newest first, admins only
+func (c *Client) ListAPIKeys(ctx context.Context, page Page) ([]APIKey, error) {
+	var keys []APIKey
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/api-keys", page.values(nil), nil, &keys)
+	return keys, err
+}
+
+// GetAPIKey returns a key without its Key, admins only
+func (c *Client) GetAPIKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	var key APIKey
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/api-keys/"+id.String(), nil, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RotateAPIKey replaces a key, the returned Key is only shown once and the previous one stops working, admins only
+func (c *Client) RotateAPIKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	var key APIKey
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/api-keys/"+id.String()+"/rotate", nil, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey disables a key for good, admins only
+func (c *Client) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	var key APIKey
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/admin/api-keys/"+id.String(), nil, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CreateBundle groups lots to be bid on as a whole, admins only
+func (c *Client) CreateBundle(ctx context.Context, req CreateBundleRequest) (*LotBundle, error) {
+	var bundle LotBundle
+	if err := c.doJSON(ctx, http.MethodPost, "/api/bundles", nil, req, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// GetBundle returns a bundle with the leading bids of its lots
+func (c *Client) GetBundle(ctx context.Context, id uuid.UUID) (*LotBundle, error) {
+	var bundle LotBundle
+	if err := c.doJSON(ctx, http.MethodGet, "/api/bundles/"+id.String(), nil, nil, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// PlaceBundleBid bids amount on all the lots of a bundle, it must beat the leading bundle bid and the
+// sum of the leading bids of the lots
+func (c *Client) PlaceBundleBid(ctx context.Context, id uuid.UUID, amount float64) (*LotBundle, error) {
+	var bundle LotBundle
+	body := struct {
+		Amount float64 `json:"amount"`
+	}{amount}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/bundles/"+id.String()+"/bids", nil, body, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// ListCategories returns all the categories
+func (c *Client) ListCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	err := c.doJSON(ctx, http.MethodGet, "/api/categories", nil, nil, &categories)
+	return categories, err
+}
+
+// CreateCategory creates a category, admins only
+func (c *Client) CreateCategory(ctx context.Context, req CreateCategoryRequest) (*Category, error) {
+	var category Category
+	if err := c.doJSON(ctx, http.MethodPost, "/api/categories", nil, req, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListCategoryLots returns the lots of a category and its subcategories, category is an ID or a slug
+func (c *Client) ListCategoryLots(ctx context.Context, category string, state LotStatus, page Page) ([]LotState, error) {
+	q := url.Values{}
+	if state != "" {
+		q.Set("state", string(state))
+	}
+	var lots []LotState
+	err := c.doJSON(ctx, http.MethodGet, "/api/categories/"+escape(category)+"/lots", page.values(q), nil, &lots)
+	return lots, err
+}
+
+// SetLotCategories replaces the categories of a lot, admins only
+func (c *Client) SetLotCategories(ctx context.Context, lotID uuid.UUID, categoryIDs []uuid.UUID) ([]Category, error) {
+	body := struct {
+		CategoryIDs []uuid.UUID `json:"category_ids"`
+	}{CategoryIDs: categoryIDs}
+	var categories []Category
+	err := c.doJSON(ctx, http.MethodPut, "/api/lots/"+lotID.String()+"/categories", nil, body, &categories)
+	return categories, err
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// GetChatHistory returns the recent chat msgs of a lot
+func (c *Client) GetChatHistory(ctx context.Context, lotID uuid.UUID) ([]ChatMessage, error) {
+	var msgs []ChatMessage
+	err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/chat", nil, nil, &msgs)
+	return msgs, err
+}
+
+// MuteChatUser silences a user in the lot chat, admins only
+func (c *Client) MuteChatUser(ctx context.Context, lotID uuid.UUID, req MuteRequest) (*ChatMute, error) {
+	body := struct {
+		UserID          uuid.UUID `json:"user_id"`
+		DurationSeconds int       `json:"duration_seconds"`
+		Reason          string    `json:"reason,omitempty"`
+	}{UserID: req.UserID, DurationSeconds: int(req.Duration.Seconds()), Reason: req.Reason}
+	var mute ChatMute
+	if err := c.doJSON(ctx, http.MethodPost, "/api/lots/"+lotID.String()+"/chat/mutes", nil, body, &mute); err != nil {
+		return nil, err
+	}
+	return &mute, nil
+}
+
+// UnmuteChatUser lifts the mute of a user in the lot chat, admins only
+func (c *Client) UnmuteChatUser(ctx context.Context, lotID, userID uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/lots/"+lotID.String()+"/chat/mutes/"+userID.String(), nil, nil, nil)
+}
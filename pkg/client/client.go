@@ -0,0 +1,233 @@
+// Package client is a typed Go client of the auction engine REST API, it follows the spec at
+// api/openapi/openapi.yaml (also served at /api/openapi.yaml) and its methods are named after the
+// spec operationIds
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderRequestID carries the correlation ID of a request, the server logs it and echoes it back
+const HeaderRequestID = "X-Request-ID"
+
+// HeaderAPIKey carries the API key of a request
+const HeaderAPIKey = "X-API-Key"
+
+// defaultTimeout bounds the requests of the default HTTP client, long-polls included
+const defaultTimeout = 90 * time.Second
+
+// Client calls the REST API of an auction engine
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	apiKey     string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client, for custom transports and timeouts
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken authenticates the requests with a bearer token, required by the admin and "me" operations
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey authenticates the requests with an API key, an alternative to WithToken for machine clients
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// New creates a client of the engine at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is a non 2xx response of the API
+type APIError struct {
+	StatusCode int
+	Message    string `json:"error"`
+	RequestID  string `json:"request_id"`
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("auction API: %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("auction API: %d %s (request id %s)", e.StatusCode, e.Message, e.RequestID)
+}
+
+// IsNotFound reports if err is an API 404
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports if err is an API 409, usually an operation not allowed in the lot state
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// Page holds the pagination params of the list operations, zero values use the server defaults
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p Page) values(q url.Values) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return q
+}
+
+// doJSON sends body as JSON (nil sends no body) and decodes the response into out (nil discards it)
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req, out)
+}
+
+// doMultipart uploads file in the form field
+func (c *Client) doMultipart(ctx context.Context, path, field, filename string, file io.Reader, out any) error {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		return fmt.Errorf("failed to create multipart form: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read upload file: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("failed to create multipart form: %w", err)
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.do(req, out)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(HeaderAPIKey, c.apiKey)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return apiError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", req.Method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// doDownload copies the body of a GET response to w as it's received, e.g. an export
+func (c *Client) doDownload(ctx context.Context, path string, query url.Values, w io.Writer) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return apiError(resp)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read %s %s response: %w", req.Method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// apiError is the error of a failed response
+func apiError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get(HeaderRequestID)
+	}
+	return apiErr
+}
+
+// escape escapes a path param
+func escape(param string) string {
+	return url.PathEscape(param)
+}
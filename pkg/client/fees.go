@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ListFeeSchedules returns the fee schedules, admins only
+func (c *Client) ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error) {
+	var schedules []FeeSchedule
+	err := c.doJSON(ctx, http.MethodGet, "/api/fee-schedules", nil, nil, &schedules)
+	return schedules, err
+}
+
+// CreateFeeSchedule creates a fee schedule, admins only
+func (c *Client) CreateFeeSchedule(ctx context.Context, req CreateFeeScheduleRequest) (*FeeSchedule, error) {
+	var schedule FeeSchedule
+	if err := c.doJSON(ctx, http.MethodPost, "/api/fee-schedules", nil, req, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SetLotFeeSchedule assigns a fee schedule to a lot, nil restores the default one. It returns the
+// schedule now applying to the lot, admins only
+func (c *Client) SetLotFeeSchedule(ctx context.Context, lotID uuid.UUID, scheduleID *uuid.UUID) (*FeeSchedule, error) {
+	body := struct {
+		FeeScheduleID *uuid.UUID `json:"fee_schedule_id"`
+	}{FeeScheduleID: scheduleID}
+	var schedule FeeSchedule
+	if err := c.doJSON(ctx, http.MethodPut, "/api/lots/"+lotID.String()+"/fee-schedule", nil, body, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
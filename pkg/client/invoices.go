@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// GetInvoice returns an invoice, only for admins and its buyer
+func (c *Client) GetInvoice(ctx context.Context, invoiceID uuid.UUID) (*Invoice, error) {
+	var invoice Invoice
+	if err := c.doJSON(ctx, http.MethodGet, "/api/invoices/"+invoiceID.String(), nil, nil, &invoice); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// GetLotInvoice returns the invoice of a won lot, only for admins and its buyer
+func (c *Client) GetLotInvoice(ctx context.Context, lotID uuid.UUID) (*Invoice, error) {
+	var invoice Invoice
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/invoice", nil, nil, &invoice); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// ListMyInvoices returns the invoices of the caller
+func (c *Client) ListMyInvoices(ctx context.Context, page Page) ([]Invoice, error) {
+	var invoices []Invoice
+	err := c.doJSON(ctx, http.MethodGet, "/api/users/me/invoices", page.values(nil), nil, &invoices)
+	return invoices, err
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchLots searches lots by text, state and category
+func (c *Client) SearchLots(ctx context.Context, params SearchLotsParams) ([]LotState, error) {
+	q := url.Values{}
+	if params.Query != "" {
+		q.Set("q", params.Query)
+	}
+	if params.State != "" {
+		q.Set("state", string(params.State))
+	}
+	if params.Category != "" {
+		q.Set("category", params.Category)
+	}
+	var lots []LotState
+	err := c.doJSON(ctx, http.MethodGet, "/api/lots/search", params.Page.values(q), nil, &lots)
+	return lots, err
+}
+
+// GetEndingSoon returns the active lots closing within the next minutes, the closest end first. 0 minutes
+// uses the server window and 0 limit returns every lot
+func (c *Client) GetEndingSoon(ctx context.Context, minutes, limit int) (*EndingSoon, error) {
+	q := url.Values{}
+	if minutes > 0 {
+		q.Set("minutes", strconv.Itoa(minutes))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var feed EndingSoon
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/ending-soon", q, nil, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetLotState returns the current state of a lot
+func (c *Client) GetLotState(ctx context.Context, lotID uuid.UUID) (*LotState, error) {
+	var lot LotState
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/state", nil, nil, &lot); err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// WaitForLotChange long-polls the lot state: it returns once the lot seq is greater than sinceSeq
+// or wait elapsed (the server caps it to 60s), the returned state tells which one by its Seq
+func (c *Client) WaitForLotChange(ctx context.Context, lotID uuid.UUID, sinceSeq int64, wait time.Duration) (*LotState, error) {
+	q := url.Values{}
+	q.Set("since_seq", strconv.FormatInt(sinceSeq, 10))
+	q.Set("wait", wait.String())
+	var lot LotState
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/state", q, nil, &lot); err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// GetLotStats returns the bid statistics of a lot, points caps its price history (0 uses the server default)
+func (c *Client) GetLotStats(ctx context.Context, lotID uuid.UUID, points int) (*LotStats, error) {
+	var q url.Values
+	if points > 0 {
+		q = url.Values{}
+		q.Set("points", strconv.Itoa(points))
+	}
+	var stats LotStats
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/stats", q, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetPriceHistory returns the price chart of a lot, with a non zero after only the later points, points
+// caps its length (0 uses the server default)
+func (c *Client) GetPriceHistory(ctx context.Context, lotID uuid.UUID, after time.Time, points int) (*PriceHistory, error) {
+	q := url.Values{}
+	if !after.IsZero() {
+		q.Set("after", after.Format(time.RFC3339Nano))
+	}
+	if points > 0 {
+		q.Set("points", strconv.Itoa(points))
+	}
+	var history PriceHistory
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/price-history", q, nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// GetLotLeaderboard returns the top bidders of a lot, the users are only set for admins and the caller own entry
+func (c *Client) GetLotLeaderboard(ctx context.Context, lotID uuid.UUID) (*Leaderboard, error) {
+	var board Leaderboard
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/leaderboard", nil, nil, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// UpdateLot edits a lot not opened yet and returns its new state, admins only
+func (c *Client) UpdateLot(ctx context.Context, lotID uuid.UUID, req UpdateLotRequest) (*LotState, error) {
+	var lot LotState
+	if err := c.doJSON(ctx, http.MethodPatch, "/api/lots/"+lotID.String(), nil, req, &lot); err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// ExportAuction writes the export of a lot to w as it's received, format is csv or json (the AuctionExport
+// model), admins only
+func (c *Client) ExportAuction(ctx context.Context, lotID uuid.UUID, format string, w io.Writer) error {
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	return c.doDownload(ctx, "/api/auctions/"+lotID.String()+"/export", q, w)
+}
+
+// ExportLotEvents writes the event history of a lot to w as it's received, a LotEvent per line ordered by
+// seq. afterSeq resumes an interrupted export, admins only
+func (c *Client) ExportLotEvents(ctx context.Context, lotID uuid.UUID, afterSeq int64, w io.Writer) error {
+	q := url.Values{}
+	if afterSeq > 0 {
+		q.Set("after_seq", strconv.FormatInt(afterSeq, 10))
+	}
+	return c.doDownload(ctx, "/api/lots/"+lotID.String()+"/events.ndjson", q, w)
+}
+
+// VoidBid retracts a bid and returns the corrected lot state, admins only
+func (c *Client) VoidBid(ctx context.Context, lotID, bidID uuid.UUID, reason string) (*LotState, error) {
+	body := struct {
+		Reason string `json:"reason,omitempty"`
+	}{Reason: reason}
+	var lot LotState
+	path := "/api/lots/" + lotID.String() + "/bids/" + bidID.String() + "/void"
+	if err := c.doJSON(ctx, http.MethodPost, path, nil, body, &lot); err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// ListLotAuctioneers returns the auctioneers assigned to run a lot, admins only
+func (c *Client) ListLotAuctioneers(ctx context.Context, lotID uuid.UUID) ([]LotAuctioneer, error) {
+	var auctioneers []LotAuctioneer
+	err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/auctioneers", nil, nil, &auctioneers)
+	return auctioneers, err
+}
+
+// AssignLotAuctioneer lets an auctioneer run a lot through the admin WS channel, admins only
+func (c *Client) AssignLotAuctioneer(ctx context.Context, lotID, userID uuid.UUID) (*LotAuctioneer, error) {
+	var auctioneer LotAuctioneer
+	if err := c.doJSON(ctx, http.MethodPut, "/api/lots/"+lotID.String()+"/auctioneers/"+userID.String(), nil, nil, &auctioneer); err != nil {
+		return nil, err
+	}
+	return &auctioneer, nil
+}
+
+// UnassignLotAuctioneer removes an auctioneer from a lot, admins only
+func (c *Client) UnassignLotAuctioneer(ctx context.Context, lotID, userID uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/lots/"+lotID.String()+"/auctioneers/"+userID.String(), nil, nil, nil)
+}
+
+// GetLotRules returns the expressions evaluated on the bids of a lot, admins only
+func (c *Client) GetLotRules(ctx context.Context, lotID uuid.UUID) (*LotRules, error) {
+	var rules LotRules
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/rules", nil, nil, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// SetLotRules replaces the expressions evaluated on the bids of a lot, admins only
+func (c *Client) SetLotRules(ctx context.Context, lotID uuid.UUID, req SetLotRulesRequest) (*LotRules, error) {
+	var rules LotRules
+	if err := c.doJSON(ctx, http.MethodPut, "/api/lots/"+lotID.String()+"/rules", nil, req, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// DeleteLotRules removes the rules of a lot, its bids follow the increment table again, admins only
+func (c *Client) DeleteLotRules(ctx context.Context, lotID uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/lots/"+lotID.String()+"/rules", nil, nil, nil)
+}
+
+// GetLotConnections returns the demographics of the live WS connections of a lot on the instance serving
+// the request, admins allowed to see the bidders only
+func (c *Client) GetLotConnections(ctx context.Context, lotID uuid.UUID) (*LotConnections, error) {
+	var connections LotConnections
+	if err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/connections", nil, nil, &connections); err != nil {
+		return nil, err
+	}
+	return &connections, nil
+}
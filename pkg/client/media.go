@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ListLotMedia returns the media of a lot in display order
+func (c *Client) ListLotMedia(ctx context.Context, lotID uuid.UUID) ([]LotMedia, error) {
+	var media []LotMedia
+	err := c.doJSON(ctx, http.MethodGet, "/api/lots/"+lotID.String()+"/media", nil, nil, &media)
+	return media, err
+}
+
+// AttachLotMedia attaches an already hosted image or video to a lot, admins only
+func (c *Client) AttachLotMedia(ctx context.Context, lotID uuid.UUID, kind, mediaURL string) (*LotMedia, error) {
+	body := struct {
+		Kind string `json:"kind"`
+		URL  string `json:"url"`
+	}{Kind: kind, URL: mediaURL}
+	var media LotMedia
+	if err := c.doJSON(ctx, http.MethodPost, "/api/lots/"+lotID.String()+"/media", nil, body, &media); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// UploadLotMedia uploads a media file to the engine storage and attaches it to a lot, admins only
+func (c *Client) UploadLotMedia(ctx context.Context, lotID uuid.UUID, filename string, file io.Reader) (*LotMedia, error) {
+	var media LotMedia
+	if err := c.doMultipart(ctx, "/api/lots/"+lotID.String()+"/media/upload", "file", filename, file, &media); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// RemoveLotMedia removes a media of a lot, admins only
+func (c *Client) RemoveLotMedia(ctx context.Context, lotID, mediaID uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/lots/"+lotID.String()+"/media/"+mediaID.String(), nil, nil, nil)
+}
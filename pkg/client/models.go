@@ -0,0 +1,712 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LotStatus is the state of a lot
+type LotStatus string
+
+const (
+	LotPending   LotStatus = "pending"
+	LotPreview   LotStatus = "preview"
+	LotActive    LotStatus = "active"
+	LotPaused    LotStatus = "paused"
+	LotFinished  LotStatus = "finished"
+	LotCancelled LotStatus = "cancelled"
+)
+
+// LotState is the state of a lot, LastBidUserID is only set for admins and the bidder
+type LotState struct {
+	LotID            uuid.UUID          `json:"lot_id"`
+	Title            string             `json:"title"`
+	Description      string             `json:"description"`
+	InitialPrice     float64            `json:"initial_price"`
+	CurrentPrice     float64            `json:"current_price"`
+	Currency         string             `json:"currency"`
+	Type             string             `json:"type"`
+	EndTime          time.Time          `json:"end_time"`
+	StartTime        *time.Time         `json:"start_time,omitempty"`
+	ClosingMode      string             `json:"closing_mode"`
+	MaxEndTime       *time.Time         `json:"max_end_time,omitempty"`
+	State            LotStatus          `json:"state"`
+	PausedAt         *time.Time         `json:"paused_at,omitempty"`
+	Seq              int64              `json:"seq"`
+	LastBidAmount    float64            `json:"last_bid_amount,omitempty"`
+	LastBidUserID    uuid.UUID          `json:"last_bid_user_id,omitempty"`
+	LastBidPaddle    int                `json:"last_bid_paddle,omitempty"`
+	LastBidTime      *time.Time         `json:"last_bid_time,omitempty"`
+	Connections      ConnectionCounts   `json:"connections"`
+	Media            []LotMedia         `json:"media"`
+	Categories       []Category         `json:"categories"`
+	Fees             *FeeEstimate       `json:"fees,omitempty"`
+	IndicativePrices map[string]float64 `json:"indicative_prices,omitempty"`
+	Bidding          *BiddingTerms      `json:"bidding,omitempty"`
+	Units            *LotUnits          `json:"units,omitempty"`
+}
+
+// LotUnits are the units of a multi-unit lot, the Quantity best bidders win one each and the current
+// price is the one to take a unit. Pricing is pay_as_bid or uniform
+type LotUnits struct {
+	Quantity     int       `json:"quantity"`
+	Available    int       `json:"available"`
+	Pricing      string    `json:"pricing"`
+	StandingBids []float64 `json:"standing_bids"`
+}
+
+// LotStats is the bid snapshot of a lot with its aggregate statistics
+type LotStats struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	State         LotStatus `json:"state"`
+	Currency      string    `json:"currency"`
+	InitialPrice  float64   `json:"initial_price"`
+	CurrentPrice  float64   `json:"current_price"`
+	BidCount      int       `json:"bid_count"`
+	UniqueBidders int       `json:"unique_bidders"`
+	// AvgIncrement is the mean price change between consecutive bids
+	AvgIncrement float64      `json:"avg_increment"`
+	Velocity     BidVelocity  `json:"velocity"`
+	FirstBidAt   *time.Time   `json:"first_bid_at,omitempty"`
+	LastBidAt    *time.Time   `json:"last_bid_at,omitempty"`
+	PriceHistory []PricePoint `json:"price_history"`
+	ComputedAt   time.Time    `json:"computed_at"`
+}
+
+// BidVelocity is the bid rate of a lot over the last WindowSeconds
+type BidVelocity struct {
+	WindowSeconds int     `json:"window_seconds"`
+	Bids          int     `json:"bids"`
+	BidsPerMinute float64 `json:"bids_per_minute"`
+}
+
+// PriceHistory is the price chart of a lot, oldest point first
+type PriceHistory struct {
+	LotID    uuid.UUID    `json:"lot_id"`
+	Currency string       `json:"currency"`
+	Points   []PricePoint `json:"points"`
+}
+
+// EndingSoon is the active lots closing within WithinSeconds, the closest end first
+type EndingSoon struct {
+	WithinSeconds int             `json:"within_seconds"`
+	Lots          []EndingSoonLot `json:"lots"`
+	ComputedAt    time.Time       `json:"computed_at"`
+}
+
+// EndingSoonLot is a lot of the ending-soon feed
+type EndingSoonLot struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	Title         string    `json:"title"`
+	CurrentPrice  float64   `json:"current_price"`
+	Currency      string    `json:"currency"`
+	EndTime       time.Time `json:"end_time"`
+	EndsInSeconds int       `json:"ends_in_seconds"`
+}
+
+// Leaderboard is the top bidders of a lot, best bid first
+type Leaderboard struct {
+	LotID    uuid.UUID          `json:"lot_id"`
+	Currency string             `json:"currency"`
+	Entries  []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardEntry is a ranked bidder of a lot, Paddle is 0 when the leaderboards are masked
+type LeaderboardEntry struct {
+	Rank    int       `json:"rank"`
+	Paddle  int       `json:"paddle,omitempty"`
+	UserID  uuid.UUID `json:"user_id,omitzero"`
+	BestBid float64   `json:"best_bid"`
+	BidAt   time.Time `json:"bid_at"`
+}
+
+// PricePoint is the price of a lot after a bid, a point of its price chart
+type PricePoint struct {
+	At    time.Time `json:"at"`
+	Price float64   `json:"price"`
+}
+
+// ConnectionCounts holds the number of live connections to a lot by role
+type ConnectionCounts struct {
+	Spectators int `json:"spectators"`
+	Bidders    int `json:"bidders"`
+	Total      int `json:"total"`
+}
+
+// FeeEstimate is the buyer's premium, fees and tax if the lot was won at the current price
+type FeeEstimate struct {
+	ScheduleID     *uuid.UUID `json:"schedule_id,omitempty"`
+	BuyerPremium   float64    `json:"buyer_premium"`
+	FlatFee        float64    `json:"flat_fee"`
+	Tax            float64    `json:"tax"`
+	EstimatedTotal float64    `json:"estimated_total"`
+}
+
+// BiddingTerms are what the next bid on a lot needs, NextMinBid is the lowest amount accepted (the highest
+// one on reverse lots). ReserveMet is nil when the lot has no reserve price
+type BiddingTerms struct {
+	MinIncrement            float64 `json:"min_increment"`
+	NextMinBid              float64 `json:"next_min_bid"`
+	ReserveMet              *bool   `json:"reserve_met,omitempty"`
+	BidCount                int     `json:"bid_count"`
+	ExtensionSeconds        int64   `json:"extension_seconds"`
+	ExtensionPriceThreshold float64 `json:"extension_price_threshold,omitempty"`
+}
+
+// SearchLotsParams filters SearchLots, Category is an ID or a slug
+type SearchLotsParams struct {
+	Query    string
+	State    LotStatus
+	Category string
+	Page
+}
+
+// UpdateLotRequest edits a lot not opened yet, nil fields are left as they are
+type UpdateLotRequest struct {
+	Title        *string    `json:"title,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	InitialPrice *float64   `json:"initial_price,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+}
+
+// LotAuctioneer is an auctioneer assigned to run a lot
+type LotAuctioneer struct {
+	LotID      uuid.UUID `json:"lot_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	AssignedBy uuid.UUID `json:"assigned_by"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// LotRules are the expressions evaluated on the bids of a lot and its country lists, see SetLotRules
+type LotRules struct {
+	LotID            uuid.UUID `json:"lot_id"`
+	MinIncrement     string    `json:"min_increment"`
+	Eligibility      string    `json:"eligibility"`
+	AllowedCountries []string  `json:"allowed_countries"`
+	BlockedCountries []string  `json:"blocked_countries"`
+	TermsVersion     string    `json:"terms_version"`
+	RequireVerified  bool      `json:"require_verified"`
+	ReservePrice     float64   `json:"reserve_price"`
+	UpdatedBy        uuid.UUID `json:"updated_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// SetLotRulesRequest sets the rules of a lot, an empty rule keeps the default behavior. the country
+// lists are ISO 3166-1 alpha-2 codes, the clients of another country get GEO_RESTRICTED. the bidders
+// must accept TermsVersion before bidding, the others get TERMS_REQUIRED. RequireVerified rejects the
+// bidders without a verified identity with VERIFICATION_REQUIRED. only whether ReservePrice is met is shown
+// to the bidders
+type SetLotRulesRequest struct {
+	MinIncrement     string   `json:"min_increment"`
+	Eligibility      string   `json:"eligibility"`
+	AllowedCountries []string `json:"allowed_countries,omitempty"`
+	BlockedCountries []string `json:"blocked_countries,omitempty"`
+	TermsVersion     string   `json:"terms_version,omitempty"`
+	RequireVerified  bool     `json:"require_verified,omitempty"`
+	ReservePrice     float64  `json:"reserve_price,omitempty"`
+}
+
+// LotConnections are the demographics of the live WS connections of a lot, the connections are counted
+// by role, browser, OS, device class and WS subprotocol
+type LotConnections struct {
+	LotID               string         `json:"lot_id"`
+	Clients             int            `json:"clients"`
+	Users               int            `json:"users"`
+	Resumed             int            `json:"resumed"`
+	Roles               map[string]int `json:"roles"`
+	Browsers            map[string]int `json:"browsers"`
+	OS                  map[string]int `json:"os"`
+	Devices             map[string]int `json:"devices"`
+	Protocols           map[string]int `json:"protocols"`
+	DistinctIPs         int            `json:"distinct_ips"`
+	TopIPs              []IPCount      `json:"top_ips"`
+	AvgConnectedSeconds float64        `json:"avg_connected_seconds"`
+}
+
+// IPCount is the number of connections from a remote IP
+type IPCount struct {
+	IP          string `json:"ip"`
+	Connections int    `json:"connections"`
+}
+
+// Category is a browsing category, ParentID is nil for top level ones
+type Category struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// CreateCategoryRequest creates a category, an empty Slug is derived from Name
+type CreateCategoryRequest struct {
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug,omitempty"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// LotMedia is an image or video of a lot
+type LotMedia struct {
+	ID       uuid.UUID `json:"id"`
+	Kind     string    `json:"kind"`
+	URL      string    `json:"url"`
+	Position int       `json:"position"`
+}
+
+// ChatMessage is a msg of the lot chat, Paddle is 0 when the author never bid on the lot
+type ChatMessage struct {
+	ID     uuid.UUID `json:"id"`
+	LotID  uuid.UUID `json:"lot_id"`
+	Paddle int       `json:"paddle,omitempty"`
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// ChatMute silences a user in the lot chat, Until is nil until unmuted
+type ChatMute struct {
+	LotID   uuid.UUID  `json:"lot_id"`
+	UserID  uuid.UUID  `json:"user_id"`
+	MutedBy uuid.UUID  `json:"muted_by"`
+	Reason  string     `json:"reason,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+}
+
+// MuteRequest mutes a user in the lot chat, a zero Duration mutes until unmuted
+type MuteRequest struct {
+	UserID   uuid.UUID
+	Duration time.Duration
+	Reason   string
+}
+
+// FeeTier is a buyer's premium rate applied to the hammer price up to UpTo
+type FeeTier struct {
+	UpTo float64 `json:"up_to"`
+	Rate float64 `json:"rate"`
+}
+
+// FeeSchedule is the set of charges added to the hammer price of a lot when it's invoiced
+type FeeSchedule struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	PremiumTiers []FeeTier `json:"premium_tiers"`
+	FlatFee      float64   `json:"flat_fee"`
+	TaxRate      float64   `json:"tax_rate"`
+	IsDefault    bool      `json:"is_default"`
+}
+
+// CreateFeeScheduleRequest creates a fee schedule
+type CreateFeeScheduleRequest struct {
+	Name         string    `json:"name"`
+	PremiumTiers []FeeTier `json:"premium_tiers"`
+	FlatFee      float64   `json:"flat_fee"`
+	TaxRate      float64   `json:"tax_rate"`
+	IsDefault    bool      `json:"is_default"`
+}
+
+// UserBid is a bid of the caller
+type UserBid struct {
+	BidID     uuid.UUID  `json:"bid_id"`
+	LotID     uuid.UUID  `json:"lot_id"`
+	Amount    float64    `json:"amount"`
+	Timestamp time.Time  `json:"timestamp"`
+	VoidedAt  *time.Time `json:"voided_at,omitempty"`
+}
+
+// UserLot is a lot the caller bid on with the caller position on it
+type UserLot struct {
+	LotID        uuid.UUID `json:"lot_id"`
+	Title        string    `json:"title"`
+	State        LotStatus `json:"state"`
+	CurrentPrice float64   `json:"current_price"`
+	Currency     string    `json:"currency"`
+	EndTime      time.Time `json:"end_time"`
+	Seq          int64     `json:"seq"`
+	HighestBid   float64   `json:"highest_bid"`
+	BidCount     int       `json:"bid_count"`
+	LastBidAt    time.Time `json:"last_bid_at"`
+	Leading      bool      `json:"leading"`
+}
+
+// SubmitAbsenteeBidRequest is the body of SubmitAbsenteeBid, Currency is optional
+type SubmitAbsenteeBidRequest struct {
+	MaxAmount float64 `json:"max_amount"`
+	Currency  string  `json:"currency,omitempty"`
+}
+
+// AbsenteeBid is a bid left on a lot before it opens, Status is pending, processing, entered, failed or cancelled
+type AbsenteeBid struct {
+	ID          uuid.UUID  `json:"id"`
+	LotID       uuid.UUID  `json:"lot_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	MaxAmount   float64    `json:"max_amount"`
+	Status      string     `json:"status"`
+	BidID       *uuid.UUID `json:"bid_id,omitempty"`
+	BidAmount   float64    `json:"bid_amount,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// CreateBundleRequest is the body of CreateBundle, a bundle has at least 2 lots
+type CreateBundleRequest struct {
+	Title  string      `json:"title"`
+	LotIDs []uuid.UUID `json:"lot_ids"`
+}
+
+// BundleLot is a lot of a bundle with its leading bid, 0 without bids
+type BundleLot struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	Title         string    `json:"title"`
+	State         string    `json:"state"`
+	LeadingAmount float64   `json:"leading_amount"`
+	HasBids       bool      `json:"has_bids"`
+}
+
+// LotBundle is a bundle of lots bid on as a whole, State is open, won, lost or cancelled. IndividualSum
+// is the sum of the leading bids of the lots, of their winning bids once settled
+type LotBundle struct {
+	ID            uuid.UUID   `json:"id"`
+	Title         string      `json:"title"`
+	Currency      string      `json:"currency"`
+	State         string      `json:"state"`
+	Lots          []BundleLot `json:"lots"`
+	IndividualSum float64     `json:"individual_sum"`
+	LeadingAmount float64     `json:"leading_amount"`
+	LeadingBidID  *uuid.UUID  `json:"leading_bid_id,omitempty"`
+	LeadingUserID uuid.UUID   `json:"leading_user_id,omitempty"`
+	Beating       bool        `json:"beating"`
+	CreatedAt     time.Time   `json:"created_at"`
+	SettledAt     *time.Time  `json:"settled_at,omitempty"`
+}
+
+// Invoice is the invoice of a won lot
+type Invoice struct {
+	ID               uuid.UUID  `json:"id"`
+	LotID            uuid.UUID  `json:"lot_id"`
+	BuyerID          uuid.UUID  `json:"buyer_id"`
+	BidID            uuid.UUID  `json:"bid_id"`
+	LotTitle         string     `json:"lot_title"`
+	HammerPrice      float64    `json:"hammer_price"`
+	Currency         string     `json:"currency"`
+	FeeScheduleID    *uuid.UUID `json:"fee_schedule_id,omitempty"`
+	BuyerPremiumRate float64    `json:"buyer_premium_rate"`
+	BuyerPremium     float64    `json:"buyer_premium"`
+	FlatFee          float64    `json:"flat_fee"`
+	TaxRate          float64    `json:"tax_rate"`
+	Tax              float64    `json:"tax"`
+	Total            float64    `json:"total"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// AuctionExport is the JSON export of a lot, its result and all its bids oldest first
+type AuctionExport struct {
+	Result struct {
+		LotID        uuid.UUID  `json:"lot_id"`
+		Title        string     `json:"title"`
+		State        string     `json:"state"`
+		Currency     string     `json:"currency"`
+		InitialPrice float64    `json:"initial_price"`
+		FinalPrice   float64    `json:"final_price"`
+		EndTime      time.Time  `json:"end_time"`
+		WinningBidID *uuid.UUID `json:"winning_bid_id,omitempty"`
+		WinnerID     *uuid.UUID `json:"winner_id,omitempty"`
+		WinnerPaddle int        `json:"winner_paddle,omitempty"`
+		// WinningBidIDs are the bids winning a unit of a multi-unit lot
+		WinningBidIDs []uuid.UUID `json:"winning_bid_ids,omitempty"`
+	} `json:"result"`
+	Bids []ExportedBid `json:"bids"`
+}
+
+// ExportedBid is a bid of an auction export, voided bids included
+type ExportedBid struct {
+	BidID      uuid.UUID  `json:"bid_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Paddle     int        `json:"paddle"`
+	Amount     float64    `json:"amount"`
+	Timestamp  time.Time  `json:"timestamp"`
+	Winning    bool       `json:"winning"`
+	VoidedAt   *time.Time `json:"voided_at,omitempty"`
+	VoidReason string     `json:"void_reason,omitempty"`
+	ReceivedAt *time.Time `json:"received_at,omitempty"`
+}
+
+// LotEvent is a line of a lot events export, Payload holds the fields of its Type
+type LotEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	Seq        int64           `json:"seq"`
+	Type       string          `json:"type"`
+	LotID      uuid.UUID       `json:"lot_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Replay is a replay of the recorded events of a lot
+type Replay struct {
+	ID        uuid.UUID `json:"id"`
+	LotID     uuid.UUID `json:"lot_id"`
+	Speed     float64   `json:"speed"`
+	Events    int       `json:"events"`
+	Played    int       `json:"played"`
+	StartedAt time.Time `json:"started_at"`
+	Done      bool      `json:"done"`
+}
+
+// FraudAlert is a suspicious bidding pattern detected on a lot
+type FraudAlert struct {
+	ID         uuid.UUID   `json:"id"`
+	LotID      uuid.UUID   `json:"lot_id"`
+	Rule       string      `json:"rule"`
+	UserIDs    []uuid.UUID `json:"user_ids"`
+	Details    string      `json:"details"`
+	Status     string      `json:"status"`
+	ReviewedBy *uuid.UUID  `json:"reviewed_by,omitempty"`
+	ReviewNote string      `json:"review_note,omitempty"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// WebhookSubscription is an endpoint receiving auction events, Secret is only set when it's created or rotated
+type WebhookSubscription struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	EventTypes   []string  `json:"event_types"`
+	MinBidAmount *float64  `json:"min_bid_amount,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Active       bool      `json:"active"`
+	Secret       string    `json:"secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateWebhookRequest registers an endpoint, MinBidAmount filters the bid.placed events
+type CreateWebhookRequest struct {
+	URL          string   `json:"url"`
+	EventTypes   []string `json:"event_types"`
+	MinBidAmount *float64 `json:"min_bid_amount,omitempty"`
+	Description  string   `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest edits a subscription, nil fields are left as they are
+type UpdateWebhookRequest struct {
+	URL               *string  `json:"url,omitempty"`
+	EventTypes        []string `json:"event_types,omitempty"`
+	MinBidAmount      *float64 `json:"min_bid_amount,omitempty"`
+	ClearMinBidAmount bool     `json:"clear_min_bid_amount,omitempty"`
+	Description       *string  `json:"description,omitempty"`
+	Active            *bool    `json:"active,omitempty"`
+	RotateSecret      bool     `json:"rotate_secret,omitempty"`
+}
+
+// WebhookDelivery is an entry of the delivery log of a subscription
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	EventID        uuid.UUID  `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	LotID          uuid.UUID  `json:"lot_id"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// SessionTokens are the tokens of a session, RefreshToken is single use
+type SessionTokens struct {
+	SessionID        uuid.UUID `json:"session_id"`
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// Session is a sign in of a user
+type Session struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	OrgID        *uuid.UUID `json:"org_id,omitempty"`
+	Role         string     `json:"role"`
+	UserAgent    string     `json:"user_agent,omitempty"`
+	IP           string     `json:"ip,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RefreshedAt  time.Time  `json:"refreshed_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
+
+// UserRevocation is the result of the revocation of the tokens of a user
+type UserRevocation struct {
+	UserID          uuid.UUID `json:"user_id"`
+	RevokedBefore   time.Time `json:"revoked_before"`
+	RevokedSessions int       `json:"revoked_sessions"`
+}
+
+// API key scopes
+const (
+	ScopeRead  = "read"
+	ScopeBid   = "bid"
+	ScopeAdmin = "admin"
+)
+
+// APIKey authenticates a machine client, Key is only set when it's issued or rotated
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Key        string     `json:"key,omitempty"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssueAPIKeyRequest issues a key with scopes, a nil UserID gives the key an identity of its own
+type IssueAPIKeyRequest struct {
+	Name   string     `json:"name"`
+	Scopes []string   `json:"scopes"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// Organization is an auction house hosted by the engine, its admins only see its lots
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOrganizationRequest adds an organization, an empty Slug is derived from Name
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// LogLevels are the runtime log levels, the default one and the module overrides
+type LogLevels struct {
+	Default string            `json:"default"`
+	Modules map[string]string `json:"modules"`
+}
+
+// MigrationInfo is the schema version of the engine database
+type MigrationInfo struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+	Latest  uint `json:"latest"`
+	Pending int  `json:"pending"`
+}
+
+// MaintenanceNoticeRequest is a maintenance notice to announce, the downtime window is optional
+type MaintenanceNoticeRequest struct {
+	Message string `json:"message"`
+	// Severity is info, warning or critical, empty is info
+	Severity string     `json:"severity,omitempty"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	// ReadOnly makes the server reject the bids from now on
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// MaintenanceNotice is a maintenance notice sent to every connected client
+type MaintenanceNotice struct {
+	Message  string     `json:"message"`
+	Severity string     `json:"severity"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	ReadOnly bool       `json:"read_only"`
+	IssuedAt time.Time  `json:"issued_at"`
+}
+
+// MaintenanceMode is the state of the read-only mode, the bids are rejected while it's on
+type MaintenanceMode struct {
+	ReadOnly bool `json:"read_only"`
+}
+
+// UserData is the export of all the data tied to a user
+type UserData struct {
+	User struct {
+		ID        uuid.UUID `json:"id"`
+		Username  string    `json:"username"`
+		Email     string    `json:"email"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"user"`
+	Bids []struct {
+		ID        uuid.UUID  `json:"id"`
+		LotID     uuid.UUID  `json:"lot_id"`
+		Amount    float64    `json:"amount"`
+		Timestamp time.Time  `json:"timestamp"`
+		ClientIP  string     `json:"client_ip,omitempty"`
+		VoidedAt  *time.Time `json:"voided_at,omitempty"`
+	} `json:"bids"`
+	Paddles []struct {
+		LotID  uuid.UUID `json:"lot_id"`
+		Paddle int       `json:"paddle"`
+	} `json:"paddles"`
+	Sessions []struct {
+		ID        uuid.UUID  `json:"id"`
+		Role      string     `json:"role"`
+		UserAgent string     `json:"user_agent,omitempty"`
+		IP        string     `json:"ip,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+		ExpiresAt time.Time  `json:"expires_at"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	} `json:"sessions"`
+	Invoices []struct {
+		ID        uuid.UUID `json:"id"`
+		LotID     uuid.UUID `json:"lot_id"`
+		LotTitle  string    `json:"lot_title"`
+		Total     float64   `json:"total"`
+		Status    string    `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"invoices"`
+	ChatMessages []struct {
+		LotID     uuid.UUID `json:"lot_id"`
+		Text      string    `json:"text"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"chat_messages"`
+	Notifications []struct {
+		LotID     uuid.UUID `json:"lot_id"`
+		Kind      string    `json:"kind"`
+		Channel   string    `json:"channel"`
+		Subject   string    `json:"subject"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"notifications"`
+	BiddingLimit *float64  `json:"bidding_limit,omitempty"`
+	ExportedAt   time.Time `json:"exported_at"`
+}
+
+// ErasureRequest is a request to erase the personal data of a user, TombstoneID is the user their bids
+// were reattributed to once completed
+type ErasureRequest struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	RequestedBy uuid.UUID  `json:"requested_by"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewNote  string     `json:"review_note,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	TombstoneID *uuid.UUID `json:"tombstone_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CheckResult is the result of a health check
+type CheckResult struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// Health is the response of the health probes
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+	Time   time.Time              `json:"time"`
+}
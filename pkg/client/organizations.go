@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CreateOrganization adds an organization, admins of the default organization only
+func (c *Client) CreateOrganization(ctx context.Context, req CreateOrganizationRequest) (*Organization, error) {
+	var org Organization
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/organizations", nil, req, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// ListOrganizations returns the organizations ordered by name, admins of the default organization only
+func (c *Client) ListOrganizations(ctx context.Context, page Page) ([]Organization, error) {
+	var orgs []Organization
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/organizations", page.values(nil), nil, &orgs)
+	return orgs, err
+}
+
+// GetOrganization returns an organization, admins of the default organization only
+func (c *Client) GetOrganization(ctx context.Context, id uuid.UUID) (*Organization, error) {
+	var org Organization
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/organizations/"+id.String(), nil, nil, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// StartSession exchanges the client token, issued outside a session, for the tokens of a new session.
+// the client keeps its token, use WithToken on a new client for the session access token
+func (c *Client) StartSession(ctx context.Context) (*SessionTokens, error) {
+	var tokens SessionTokens
+	if err := c.doJSON(ctx, http.MethodPost, "/api/auth/sessions", nil, nil, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// RefreshSession exchanges a refresh token for new tokens, the refresh token can't be used again
+func (c *Client) RefreshSession(ctx context.Context, refreshToken string) (*SessionTokens, error) {
+	var tokens SessionTokens
+	req := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{RefreshToken: refreshToken}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/auth/refresh", nil, req, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// SignOut revokes the session of the client token
+func (c *Client) SignOut(ctx context.Context) error {
+	return c.doJSON(ctx, http.MethodPost, "/api/auth/sign-out", nil, nil, nil)
+}
+
+// ListMySessions returns the sessions of the caller, newest first
+func (c *Client) ListMySessions(ctx context.Context, page Page) ([]Session, error) {
+	var sessions []Session
+	err := c.doJSON(ctx, http.MethodGet, "/api/users/me/sessions", page.values(nil), nil, &sessions)
+	return sessions, err
+}
+
+// RevokeMySession revokes a session of the caller
+func (c *Client) RevokeMySession(ctx context.Context, id uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/users/me/sessions/"+id.String(), nil, nil, nil)
+}
+
+// ListUserSessions returns the sessions of a user, newest first, admins only
+func (c *Client) ListUserSessions(ctx context.Context, userID uuid.UUID, page Page) ([]Session, error) {
+	var sessions []Session
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/users/"+userID.String()+"/sessions", page.values(nil), nil, &sessions)
+	return sessions, err
+}
+
+// RevokeUserTokens revokes every session and token of a user issued up to now, admins only
+func (c *Client) RevokeUserTokens(ctx context.Context, userID uuid.UUID) (*UserRevocation, error) {
+	var revocation UserRevocation
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/users/"+userID.String()+"/revoke-tokens", nil, nil, &revocation); err != nil {
+		return nil, err
+	}
+	return &revocation, nil
+}
+
+// RevokeSession revokes a session of any user, admins only
+func (c *Client) RevokeSession(ctx context.Context, id uuid.UUID) (*Session, error) {
+	var session Session
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/admin/sessions/"+id.String(), nil, nil, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
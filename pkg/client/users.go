@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListMyBids returns the bids of the caller, newest first
+func (c *Client) ListMyBids(ctx context.Context, page Page) ([]UserBid, error) {
+	var bids []UserBid
+	err := c.doJSON(ctx, http.MethodGet, "/api/users/me/bids", page.values(nil), nil, &bids)
+	return bids, err
+}
+
+// ListMyLots returns the lots the caller bid on, LotActive lists the "my active bids" view
+func (c *Client) ListMyLots(ctx context.Context, state LotStatus, page Page) ([]UserLot, error) {
+	q := url.Values{}
+	if state != "" {
+		q.Set("state", string(state))
+	}
+	var lots []UserLot
+	err := c.doJSON(ctx, http.MethodGet, "/api/users/me/lots", page.values(q), nil, &lots)
+	return lots, err
+}
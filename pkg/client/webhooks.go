@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhook registers an endpoint for event types, the returned Secret is only shown once, admins only
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/webhooks", nil, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ListWebhooks returns the webhook subscriptions, newest first, admins only
+func (c *Client) ListWebhooks(ctx context.Context, page Page) ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/webhooks", page.values(nil), nil, &subs)
+	return subs, err
+}
+
+// GetWebhook returns a webhook subscription, admins only
+func (c *Client) GetWebhook(ctx context.Context, id uuid.UUID) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/webhooks/"+id.String(), nil, nil, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateWebhook edits a webhook subscription, admins only
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.doJSON(ctx, http.MethodPatch, "/api/admin/webhooks/"+id.String(), nil, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// DeleteWebhook removes a webhook subscription and its delivery log, admins only
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/admin/webhooks/"+id.String(), nil, nil, nil)
+}
+
+// ListWebhookDeliveries returns the delivery log of a subscription, an empty status lists all of them, admins only
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id uuid.UUID, status string, page Page) ([]WebhookDelivery, error) {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+	var deliveries []WebhookDelivery
+	err := c.doJSON(ctx, http.MethodGet, "/api/admin/webhooks/"+id.String()+"/deliveries", page.values(q), nil, &deliveries)
+	return deliveries, err
+}
+
+// RedeliverWebhook sends a logged delivery again with a single attempt, admins only
+func (c *Client) RedeliverWebhook(ctx context.Context, id, deliveryID uuid.UUID) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	path := "/api/admin/webhooks/" + id.String() + "/deliveries/" + deliveryID.String() + "/redeliver"
+	if err := c.doJSON(ctx, http.MethodPost, path, nil, nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
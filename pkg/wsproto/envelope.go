@@ -0,0 +1,50 @@
+// Package wsproto is the WS protocol of the auction engine, the msgs exchanged on /ws/auction/:lotid
+// and /ws/admin/auction/:lotid. Every msg is a JSON object with a type and a payload, decode it as an
+// Envelope first and then its payload into the DTO of its type
+package wsproto
+
+import "encoding/json"
+
+// Subprotocol is the versioned WS subprotocol of the lot connections, the clients offer it in the
+// Sec-WebSocket-Protocol header of the upgrade. the connections not offering it speak the same protocol
+const Subprotocol = "auction.v1"
+
+// QueryLastEventSeq is the connect query param of a resuming client, the seq of the last lot update it
+// applied. the server replays the missed events in a server_replay before the initial state
+const QueryLastEventSeq = "last_event_seq"
+
+// QueryConnectionToken is the connect query param of a reconnecting client, the connection_token of
+// its last server_connected. the connection keeps the client ID of the previous one
+const QueryConnectionToken = "connection_token"
+
+// LobbyRoom is the lot ID of the global lobby room, /ws/auction/_lobby, its connections get the
+// TopicLobby msgs instead of the updates of a lot. the lots of other organizations are left out
+const LobbyRoom = "_lobby"
+
+// Topics a connection can subscribe to with client_subscribe, besides its lot room. the msgs of a topic
+// are the ones of its room: the server_ending_soon feed for TopicLobby, the updates of the lot for
+// TopicAuctionPrefix+<lot ID> and the server_lot_opened msgs of the lots of the category for
+// TopicCategoryPrefix+<category slug>
+const (
+	TopicLobby          = "lobby"
+	TopicAuctionPrefix  = "auction:"
+	TopicCategoryPrefix = "category:"
+)
+
+// CloseServiceRestart is the close code of the connections closed by a restarting server, the close
+// text is a RestartCloseReason and the client should reconnect after its RetryAfterMs, resuming with
+// QueryLastEventSeq. another instance usually serves the reconnection
+const CloseServiceRestart = 1012
+
+// RestartCloseReason is the JSON close text of CloseServiceRestart
+type RestartCloseReason struct {
+	RetryAfterMs int64 `json:"retry_after_ms"`
+}
+
+// Envelope is a msg of any type with its payload still encoded
+type Envelope struct {
+	Type MessageType `json:"type"`
+	// MessageID is the client chosen ID of the msgs that carry one
+	MessageID string          `json:"message_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
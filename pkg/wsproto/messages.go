@@ -0,0 +1,641 @@
+package wsproto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageType defines ws type message
+type MessageType string
+
+const (
+	MessageTypeClientBid          MessageType = "client_bid"           // client msg to make a bid
+	MessageTypeServerLotUpdate    MessageType = "server_lot_update"    // server  msg with lot update
+	MessageTypeServerError        MessageType = "server_error"         // server msg indicating error
+	MessageTypeServerInfo         MessageType = "server_info"          // server msg with general info
+	MessageTypeClientJoinLot      MessageType = "client_join_lot"      // client msg to join a lot (optional if the path is no used)
+	MessageTypeServerInitialState MessageType = "server_initial_state" // server msgw with lot initial state
+	MessageTypeServerPresence     MessageType = "server_presence"      // server msg with live participation counts
+	MessageTypeServerOutbid       MessageType = "server_outbid"        // server msg to a user who lost the lead of a lot
+	MessageTypeServerBidAccepted  MessageType = "server_bid_accepted"  // server msg confirming a bid to its sender
+	MessageTypeServerLotWon       MessageType = "server_lot_won"       // server msg to the winner of a lot
+	MessageTypeServerReplay       MessageType = "server_replay"        // server msg with the lot events missed by a resuming client
+	MessageTypeServerLotDelta     MessageType = "server_lot_delta"     // server msg with only the changed fields of a lot update
+	MessageTypeServerLotOpened    MessageType = "server_lot_opened"    // server msg to every connection of a lot when it opens for bidding
+	MessageTypeServerRetry        MessageType = "server_retry"         // server msg to a client whose msg was dropped under load
+	MessageTypeServerBidAck       MessageType = "server_bid_ack"       // server msg telling a bidder its bid is being processed
+	MessageTypeServerBidResult    MessageType = "server_bid_result"    // server msg with the final outcome of a bid sent with message_id
+	MessageTypeClientChat         MessageType = "client_chat"          // client msg to the lot chat
+	MessageTypeServerChat         MessageType = "server_chat"          // server msg with a chat msg, to every connection of the lot
+	MessageTypeServerChatHistory  MessageType = "server_chat_history"  // server msg with the recent chat of a lot, sent on join
+	MessageTypeClientAuctioneer   MessageType = "client_auctioneer"    // auctioneer msg controlling a live lot, admin WS channel only
+	MessageTypeServerFairWarning  MessageType = "server_fair_warning"  // server msg to every connection of a lot, the auctioneer is about to close
+	MessageTypeServerLotPaused    MessageType = "server_lot_paused"    // server msg to every connection of a lot when its bidding is suspended
+	MessageTypeServerLotResumed   MessageType = "server_lot_resumed"   // server msg to every connection of a lot when its bidding reopens
+	MessageTypeServerCommentary   MessageType = "server_commentary"    // server msg with a comment of the auctioneer to the lot
+	MessageTypeServerPricePoint   MessageType = "server_price_point"   // server msg with a new point of the lot price chart
+	MessageTypeServerLeaderboard  MessageType = "server_leaderboard"   // server msg with the top bidders of a lot, after each bid
+	MessageTypeServerEndingSoon   MessageType = "server_ending_soon"   // server msg to the lobby room with the lots about to close
+	MessageTypeClientSubscribe    MessageType = "client_subscribe"     // client msg to receive the msgs of a topic
+	MessageTypeClientUnsubscribe  MessageType = "client_unsubscribe"   // client msg to stop receiving the msgs of a topic
+	MessageTypeServerSubscribed   MessageType = "server_subscribed"    // server msg confirming a client_subscribe or client_unsubscribe
+	MessageTypeClientPingTime     MessageType = "client_ping_time"     // client msg asking for the server time, to correct the skew of its clock
+	MessageTypeServerTime         MessageType = "server_time"          // server msg with the server time, sent on join and as the reply of client_ping_time
+	MessageTypeServerConnected    MessageType = "server_connected"     // server msg with the identity of the connection, the first msg of every connection
+)
+
+// msgs of the terms acceptance, required before bidding on the lots with terms
+const (
+	MessageTypeClientAcceptTerms   MessageType = "client_accept_terms"   // client msg accepting the terms of the lot
+	MessageTypeServerTermsAccepted MessageType = "server_terms_accepted" // server msg confirming a client_accept_terms
+)
+
+// MessageTypeClientQuickBid is the client msg bidding the next min bid of the lot, whatever its price
+// when the bid is processed
+const MessageTypeClientQuickBid MessageType = "client_quick_bid"
+
+// MessageTypeServerBundleUpdate is the server msg to every connection of the lots of a bundle when a
+// bundle bid is placed and when the bundle is settled
+const MessageTypeServerBundleUpdate MessageType = "server_bundle_update"
+
+// BaseMessage is base struct for all the WS messages, includes a Type field for identify the message type
+type BaseMessage struct {
+	Type MessageType `json:"type"`
+}
+
+// ClientBidMessage is DTO for a bid message sended vy the client
+type ClientBidMessage struct {
+	BaseMessage
+	// MessageID is an optional client chosen ID, bids sent with it get a server_bid_ack and a final
+	// server_bid_result carrying it instead of server_bid_accepted / server_error
+	MessageID string `json:"message_id,omitempty"`
+	Payload   struct {
+		LotID  uuid.UUID `json:"lot_id"`
+		UserID uuid.UUID `json:"user_id"`
+		Amount float64   `json:"amount"`
+		// Currency is optional, when set it must match the lot currency
+		Currency string `json:"currency,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerLotUpdateMessage is DTO for a lot update msg sended by the server
+type ServerLotUpdateMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID         uuid.UUID        `json:"lot_id"`
+		CurrentPrice  float64          `json:"current_price"`
+		EndTime       time.Time        `json:"end_time"`
+		State         string           `json:"state"` // Use string for domain state
+		Seq           int64            `json:"seq"`   // per lot monotonic, gaps mean missed updates
+		LastBidAmount float64          `json:"last_bid_amount,omitempty"`
+		LastBidPaddle int              `json:"last_bid_paddle,omitempty"` // bidders are only identified by paddle in broadcasts
+		LastBidTime   *time.Time       `json:"last_bid_time,omitempty"`
+		Connections   ConnectionCounts `json:"connections"`
+		ServerTime    int64            `json:"server_time"` // epoch millis when the update was sent
+		// NextMinBid is the lowest amount the next bid must reach, the amount of a client_quick_bid
+		NextMinBid float64 `json:"next_min_bid,omitempty"`
+		// Units are the units of a multi-unit lot, unset for a single item
+		Units *LotUnits `json:"units,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerLotDeltaMessage is the compact DTO for a lot update, it only carries the fields changed since
+// the update with BaseSeq. values are absolute, so applying it over any state at or after BaseSeq is safe,
+// clients holding an older state must wait for the next full server_lot_update snapshot
+type ServerLotDeltaMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID         uuid.UUID         `json:"lot_id"`
+		Seq           int64             `json:"seq"`
+		BaseSeq       int64             `json:"base_seq"`
+		CurrentPrice  *float64          `json:"current_price,omitempty"`
+		EndTime       *time.Time        `json:"end_time,omitempty"`
+		LastBidAmount *float64          `json:"last_bid_amount,omitempty"`
+		LastBidPaddle *int              `json:"last_bid_paddle,omitempty"`
+		LastBidTime   *time.Time        `json:"last_bid_time,omitempty"`
+		Connections   *ConnectionCounts `json:"connections,omitempty"`
+		ServerTime    int64             `json:"server_time"` // epoch millis when the delta was sent
+		NextMinBid    *float64          `json:"next_min_bid,omitempty"`
+		// Units is set whenever the units of a multi-unit lot changed, with all of them
+		Units *LotUnits `json:"units,omitempty"`
+	} `json:"payload"`
+}
+
+// LotUnits is the availability of the units of a multi-unit lot, its current price is the one to take a unit
+type LotUnits struct {
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"` // units no bid holds yet
+	Pricing   string `json:"pricing"`   // pay_as_bid or uniform
+	// StandingBids are the amounts of the bids holding a unit, best first
+	StandingBids []float64 `json:"standing_bids"`
+}
+
+// ConnectionCounts is the number of live connections to a lot by role
+type ConnectionCounts struct {
+	Spectators int `json:"spectators"`
+	Bidders    int `json:"bidders"`
+	Total      int `json:"total"`
+}
+
+type ServerErrorMessage struct {
+	BaseMessage
+	Payload struct {
+		Error string `json:"error"`
+		// Code identifies the errors the clients can handle, e.g. amount_precision or MAINTENANCE, empty for the others
+		Code string `json:"code,omitempty"`
+		// CorrelationID identifies the failed message in the server logs, for support lookups
+		CorrelationID string `json:"correlation_id,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerInfoMessage es el DTO para un mensaje de información general enviado por el servidor.
+// the maintenance notices, sent to every connection, also carry their severity and downtime window
+type ServerInfoMessage struct {
+	BaseMessage
+	Payload struct {
+		Message string `json:"message"`
+		// Severity of a maintenance notice: info, warning or critical
+		Severity string     `json:"severity,omitempty"`
+		StartsAt *time.Time `json:"starts_at,omitempty"`
+		EndsAt   *time.Time `json:"ends_at,omitempty"`
+		// ReadOnly is true while the server rejects the bids
+		ReadOnly bool `json:"read_only,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerInitialStateMessage es el DTO para el estado inicial del lote enviado al cliente al conectarse.
+type ServerInitialStateMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID         uuid.UUID        `json:"lot_id"`
+		Title         string           `json:"title"`
+		Description   string           `json:"description"`
+		InitialPrice  float64          `json:"initial_price"`
+		CurrentPrice  float64          `json:"current_price"`
+		Currency      string           `json:"currency"`
+		Type          string           `json:"type"` // forward, or reverse when the lowest bid wins
+		EndTime       time.Time        `json:"end_time"`
+		StartTime     *time.Time       `json:"start_time,omitempty"` // scheduled opening of a lot in preview
+		ClosingMode   string           `json:"closing_mode"`
+		MaxEndTime    *time.Time       `json:"max_end_time,omitempty"` // latest end time the extensions can reach
+		State         string           `json:"state"`
+		PausedAt      *time.Time       `json:"paused_at,omitempty"` // set while the lot is paused, bidding is disabled
+		Seq           int64            `json:"seq"`
+		LastBidAmount float64          `json:"last_bid_amount,omitempty"`
+		LastBidUserID uuid.UUID        `json:"last_bid_user_id,omitempty"` // only set when the client is the last bidder
+		LastBidPaddle int              `json:"last_bid_paddle,omitempty"`
+		YourPaddle    int              `json:"your_paddle,omitempty"` // paddle of the client user, once they bid on the lot
+		LastBidTime   *time.Time       `json:"last_bid_time,omitempty"`
+		Connections   ConnectionCounts `json:"connections"`
+		Media         []MediaItem      `json:"media"`
+		Categories    []CategoryItem   `json:"categories"`
+		// total the buyer would pay at the current price, with premium, fees and tax
+		EstimatedTotal float64 `json:"estimated_total,omitempty"`
+		// current price converted to other currencies, informative only
+		IndicativePrices map[string]float64 `json:"indicative_prices,omitempty"`
+		// TermsVersion is the version of the terms to accept with client_accept_terms before bidding, empty
+		// for the lots without terms. TermsAccepted is true once the client user accepted it
+		TermsVersion  string `json:"terms_version,omitempty"`
+		TermsAccepted bool   `json:"terms_accepted,omitempty"`
+		// MinIncrement and NextMinBid are what the next bid needs, NextMinBid being the lowest amount accepted
+		// (the highest one on reverse lots). ReserveMet is unset on the lots without reserve price
+		MinIncrement float64 `json:"min_increment"`
+		NextMinBid   float64 `json:"next_min_bid"`
+		ReserveMet   *bool   `json:"reserve_met,omitempty"`
+		BidCount     int     `json:"bid_count"`
+		// ExtensionSeconds is how much a bid near the end extends the lot, in the price_threshold closing
+		// mode only the bids moving the price by ExtensionPriceThreshold do
+		ExtensionSeconds        int64   `json:"extension_seconds"`
+		ExtensionPriceThreshold float64 `json:"extension_price_threshold,omitempty"`
+		// Units are the units of a multi-unit lot, unset for a single item
+		Units *LotUnits `json:"units,omitempty"`
+		// ServerTime is the epoch millis when the state was sent
+		ServerTime int64 `json:"server_time"`
+		// maybe include a list of recents bids here
+		// RecentBids []*BidDTO `json:"recent_bids,omitempty"` //BidDTO needed
+	} `json:"payload"`
+}
+
+// MediaItem is an image or video of the lot
+type MediaItem struct {
+	ID   uuid.UUID `json:"id"`
+	Kind string    `json:"kind"` // image or video
+	URL  string    `json:"url"`
+}
+
+// CategoryItem is a category the lot is listed in
+type CategoryItem struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+// ServerReplayMessage is the DTO for the lot events missed since the last_event_seq sent on connect,
+// it's sent before the initial state
+type ServerReplayMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID  uuid.UUID     `json:"lot_id"`
+		Events []ReplayEvent `json:"events"`
+		// Truncated is true when too many events were missed, the initial state is authoritative
+		Truncated bool `json:"truncated"`
+	} `json:"payload"`
+}
+
+// ReplayEvent is a stored lot event
+type ReplayEvent struct {
+	Seq        int64     `json:"seq"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Payload    any       `json:"payload"`
+}
+
+// type BidDTO struct {
+// 	ID uuid.UUID `json:"id"`
+// 	UserID uuid.UUID `json:"user_id"`
+// 	Amount float64 `json:"amount"`
+// 	Timestamp time.Time `json:"timestamp"`
+// }
+
+// ServerPresenceMessage is the DTO for the periodic live participation msg of a lot
+type ServerPresenceMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID         string `json:"lot_id"`
+		Viewers       int    `json:"viewers"`        // all connections, spectators and bidders
+		Spectators    int    `json:"spectators"`     // read-only connections
+		Bidders       int    `json:"bidders"`        // connections allowed to bid
+		ActiveBidders int    `json:"active_bidders"` // distinct users who bid within the activity window
+	} `json:"payload"`
+}
+
+// ServerOutbidMessage is the DTO for the private msg sent to a user who was outbid
+type ServerOutbidMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID      uuid.UUID `json:"lot_id"`
+		YourAmount float64   `json:"your_amount"`
+		NewAmount  float64   `json:"new_amount"`
+		OutbidAt   time.Time `json:"outbid_at"`
+	} `json:"payload"`
+}
+
+// ServerBidAcceptedMessage is the DTO for the private confirmation sent to the bidder
+type ServerBidAcceptedMessage struct {
+	BaseMessage
+	Payload struct {
+		BidID     uuid.UUID `json:"bid_id"`
+		LotID     uuid.UUID `json:"lot_id"`
+		Amount    float64   `json:"amount"`
+		Paddle    int       `json:"paddle"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"payload"`
+}
+
+// ServerBidAckMessage is the DTO for the private msg sent as soon as a bid is taken for processing,
+// before it's validated against the lot and stored
+type ServerBidAckMessage struct {
+	BaseMessage
+	Payload struct {
+		MessageID  string    `json:"message_id"`
+		LotID      uuid.UUID `json:"lot_id"`
+		Amount     float64   `json:"amount"`
+		ReceivedAt time.Time `json:"received_at"`
+		// QueuePosition and EstimatedDelayMs are only set when the bid waits behind others of the lot,
+		// the number of commands ahead and the expected wait before it's processed
+		QueuePosition    int   `json:"queue_position,omitempty"`
+		EstimatedDelayMs int64 `json:"estimated_delay_ms,omitempty"`
+	} `json:"payload"`
+}
+
+// bid result statuses
+const (
+	BidResultAccepted = "accepted"
+	BidResultRejected = "rejected"
+)
+
+// ServerBidResultMessage is the DTO for the private msg with the final outcome of a bid, the bid fields
+// are only set when it was accepted and Error only when it was rejected
+type ServerBidResultMessage struct {
+	BaseMessage
+	Payload struct {
+		MessageID string     `json:"message_id"`
+		LotID     uuid.UUID  `json:"lot_id"`
+		Status    string     `json:"status"` // accepted or rejected
+		BidID     *uuid.UUID `json:"bid_id,omitempty"`
+		Amount    float64    `json:"amount"`
+		Paddle    int        `json:"paddle,omitempty"`
+		Timestamp *time.Time `json:"timestamp,omitempty"`
+		Error     string     `json:"error,omitempty"`
+		// Code identifies the rejections the clients can handle, e.g. amount_precision or MAINTENANCE
+		Code string `json:"code,omitempty"`
+		// CorrelationID identifies the bid in the server logs, for support lookups
+		CorrelationID string `json:"correlation_id,omitempty"`
+		// ReceivedAt is when the server read the bid, a bid received at or after the lot end time is
+		// rejected whenever the client sent it
+		ReceivedAt time.Time `json:"received_at"`
+		// ProcessingMs is the time from ReceivedAt to the outcome, queueing included
+		ProcessingMs int64 `json:"processing_ms"`
+	} `json:"payload"`
+}
+
+// ServerLotWonMessage is the DTO for the private msg sent to the winner of a lot
+type ServerLotWonMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID      uuid.UUID `json:"lot_id"`
+		BidID      uuid.UUID `json:"bid_id"`
+		Amount     float64   `json:"amount"`
+		FinalPrice float64   `json:"final_price"`
+	} `json:"payload"`
+}
+
+// ServerLotOpenedMessage is the DTO for the msg broadcast to a lot when it opens for bidding
+type ServerLotOpenedMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID        uuid.UUID `json:"lot_id"`
+		OpenedAt     time.Time `json:"opened_at"`
+		InitialPrice float64   `json:"initial_price"`
+		EndTime      time.Time `json:"end_time"`
+	} `json:"payload"`
+}
+
+// ServerRetryMessage is the DTO for the private msg telling a client that its msg was dropped under load
+// and was not processed, the client should resend it after RetryAfterMs
+type ServerRetryMessage struct {
+	BaseMessage
+	Payload struct {
+		MessageType  string `json:"message_type,omitempty"` // type of the dropped msg
+		MessageID    string `json:"message_id,omitempty"`   // client ID of the dropped msg, if it had one
+		Reason       string `json:"reason"`                 // inbound_full or worker_busy
+		RetryAfterMs int64  `json:"retry_after_ms"`
+		// DeadLetterID identifies the stored copy of the msg, nil if it couldn't be stored
+		DeadLetterID *uuid.UUID `json:"dead_letter_id,omitempty"`
+	} `json:"payload"`
+}
+
+// ClientChatMessage is the DTO for a msg sent by the client to the lot chat
+type ClientChatMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID uuid.UUID `json:"lot_id"`
+		Text  string    `json:"text"`
+	} `json:"payload"`
+}
+
+// ChatItem is a msg of the lot chat, authors are only identified by paddle
+type ChatItem struct {
+	ID     uuid.UUID `json:"id"`
+	Paddle int       `json:"paddle,omitempty"` // 0 when the author never bid on the lot
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// ServerChatMessage is the DTO for a chat msg broadcast to the lot
+type ServerChatMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID uuid.UUID `json:"lot_id"`
+		ChatItem
+	} `json:"payload"`
+}
+
+// ServerChatHistoryMessage is the DTO for the recent chat msgs sent to a joining client, oldest first
+type ServerChatHistoryMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID    uuid.UUID  `json:"lot_id"`
+		Messages []ChatItem `json:"messages"`
+	} `json:"payload"`
+}
+
+// auctioneer actions of a client_auctioneer msg
+const (
+	AuctioneerActionFairWarning = "fair_warning"
+	AuctioneerActionPause       = "pause"
+	AuctioneerActionResume      = "resume"
+	AuctioneerActionCommentary  = "commentary"
+)
+
+// ClientAuctioneerMessage is the DTO for a live control msg of the auctioneer of a lot
+type ClientAuctioneerMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID  uuid.UUID `json:"lot_id"`
+		Action string    `json:"action"` // fair_warning, pause, resume or commentary
+		// Text is the comment, or the optional reason of a pause or note of a fair warning
+		Text string `json:"text,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerFairWarningMessage is the DTO for the fair warning broadcast to a lot, last call before it closes
+type ServerFairWarningMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID        uuid.UUID `json:"lot_id"`
+		Text         string    `json:"text,omitempty"`
+		CurrentPrice float64   `json:"current_price"`
+		EndTime      time.Time `json:"end_time"`
+		IssuedAt     time.Time `json:"issued_at"`
+	} `json:"payload"`
+}
+
+// ServerLotPausedMessage is the DTO for the msg broadcast to a lot when its bidding is suspended,
+// clients should disable bidding until server_lot_resumed
+type ServerLotPausedMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID    uuid.UUID `json:"lot_id"`
+		Reason   string    `json:"reason,omitempty"`
+		PausedAt time.Time `json:"paused_at"`
+	} `json:"payload"`
+}
+
+// ServerLotResumedMessage is the DTO for the msg broadcast to a lot when its bidding reopens
+type ServerLotResumedMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID     uuid.UUID `json:"lot_id"`
+		ResumedAt time.Time `json:"resumed_at"`
+		EndTime   time.Time `json:"end_time"`
+	} `json:"payload"`
+}
+
+// ServerCommentaryMessage is the DTO for a comment of the auctioneer broadcast to a lot
+type ServerCommentaryMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID  uuid.UUID `json:"lot_id"`
+		Text   string    `json:"text"`
+		SentAt time.Time `json:"sent_at"`
+	} `json:"payload"`
+}
+
+// ServerPricePointMessage is the DTO for the compact msg broadcast to a lot when its price changes (bids and
+// voided bids), the points extend the chart fetched from GET /api/lots/:id/price-history
+type ServerPricePointMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID uuid.UUID `json:"lot_id"`
+		Seq   int64     `json:"seq"`
+		At    time.Time `json:"at"`
+		Price float64   `json:"price"`
+	} `json:"payload"`
+}
+
+// ServerLeaderboardMessage is the DTO for the msg broadcast to a lot with its top bidders after each bid,
+// best bid first. the paddles are left out when the engine masks the leaderboards
+type ServerLeaderboardMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID    uuid.UUID          `json:"lot_id"`
+		Currency string             `json:"currency"`
+		Entries  []LeaderboardEntry `json:"entries"`
+	} `json:"payload"`
+}
+
+// LeaderboardEntry is a ranked bidder of a lot leaderboard
+type LeaderboardEntry struct {
+	Rank    int       `json:"rank"`
+	Paddle  int       `json:"paddle,omitempty"`
+	BestBid float64   `json:"best_bid"`
+	BidAt   time.Time `json:"bid_at"`
+}
+
+// ServerEndingSoonMessage is the DTO for the msg broadcast periodically to the lobby room, and sent on
+// join, with the active lots closing within the next WithinSeconds, the closest end first
+type ServerEndingSoonMessage struct {
+	BaseMessage
+	Payload struct {
+		WithinSeconds int             `json:"within_seconds"`
+		Lots          []EndingSoonLot `json:"lots"`
+		ComputedAt    time.Time       `json:"computed_at"`
+	} `json:"payload"`
+}
+
+// EndingSoonLot is a lot of the ending-soon feed
+type EndingSoonLot struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	Title         string    `json:"title"`
+	CurrentPrice  float64   `json:"current_price"`
+	Currency      string    `json:"currency"`
+	EndTime       time.Time `json:"end_time"`
+	EndsInSeconds int       `json:"ends_in_seconds"`
+}
+
+// ClientSubscriptionMessage is the DTO for the client_subscribe and client_unsubscribe msgs, Topic is one
+// of the topics documented with TopicLobby
+type ClientSubscriptionMessage struct {
+	BaseMessage
+	Payload struct {
+		Topic string `json:"topic"`
+	} `json:"payload"`
+}
+
+// ServerSubscribedMessage is the DTO for the confirmation of a subscription change, Subscribed is false
+// after a client_unsubscribe
+type ServerSubscribedMessage struct {
+	BaseMessage
+	Payload struct {
+		Topic      string `json:"topic"`
+		Subscribed bool   `json:"subscribed"`
+	} `json:"payload"`
+}
+
+// ClientPingTimeMessage is the DTO for the msg asking for the server time, ClientTime is the optional
+// epoch millis of the client when it sent the msg, echoed in the server_time reply
+type ClientPingTimeMessage struct {
+	BaseMessage
+	Payload struct {
+		ClientTime int64 `json:"client_time,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerTimeMessage is the DTO for the server time, the countdowns to the lot end times should run on
+// it: a client estimates its skew as ServerTime - (ClientTime + round trip / 2), the round trip measured
+// between sending client_ping_time and receiving this reply
+type ServerTimeMessage struct {
+	BaseMessage
+	Payload struct {
+		ServerTime int64 `json:"server_time"` // epoch millis
+		// ClientTime is the client_time of the client_ping_time answered, 0 on join
+		ClientTime int64 `json:"client_time,omitempty"`
+	} `json:"payload"`
+}
+
+// ServerConnectedMessage is the DTO for the identity of a connection, the first msg it receives. a
+// reconnecting client sends ConnectionToken back as QueryConnectionToken to keep ClientID, the private
+// msgs of the previous connection, e.g. the result of a bid in flight, then reach the new one
+type ServerConnectedMessage struct {
+	BaseMessage
+	Payload struct {
+		ClientID        string `json:"client_id"`
+		ConnectionToken string `json:"connection_token"`
+		// Resumed is true when the connection took the identity of a previous one
+		Resumed bool `json:"resumed"`
+	} `json:"payload"`
+}
+
+// ClientAcceptTermsMessage is the DTO for the msg accepting the terms of a lot, TermsVersion must be the
+// terms_version of the lot state: the bids on the lots with terms are rejected with TERMS_REQUIRED until
+// the current version is accepted
+type ClientAcceptTermsMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID        uuid.UUID `json:"lot_id"`
+		TermsVersion string    `json:"terms_version"`
+	} `json:"payload"`
+}
+
+// ServerTermsAcceptedMessage is the DTO for the confirmation of a client_accept_terms
+type ServerTermsAcceptedMessage struct {
+	BaseMessage
+	Payload struct {
+		LotID        uuid.UUID `json:"lot_id"`
+		TermsVersion string    `json:"terms_version"`
+		AcceptedAt   time.Time `json:"accepted_at"`
+	} `json:"payload"`
+}
+
+// ClientQuickBidMessage is the DTO for a quick bid, the server bids the next min bid of the lot at the
+// time it processes the bid, the next_min_bid of the last lot update when no other bid came first.
+// MessageID works as in client_bid, the bid result carries the amount bid
+type ClientQuickBidMessage struct {
+	BaseMessage
+	MessageID string `json:"message_id,omitempty"`
+	Payload   struct {
+		LotID uuid.UUID `json:"lot_id"`
+		// MaxAmount optionally bounds the amount, the bid is rejected with QUICK_BID_LIMIT when the next
+		// min bid is above it (below it on reverse lots)
+		MaxAmount float64 `json:"max_amount,omitempty"`
+	} `json:"payload"`
+}
+
+// BundleLot is a lot of a bundle with its leading bid, 0 without bids
+type BundleLot struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	LeadingAmount float64   `json:"leading_amount"`
+}
+
+// ServerBundleUpdateMessage is the DTO for the state of a bundle of lots bid on as a whole, the bundle
+// wins its lots if its leading bid still beats the sum of their leading bids when they all closed
+type ServerBundleUpdateMessage struct {
+	BaseMessage
+	Payload struct {
+		BundleID uuid.UUID `json:"bundle_id"`
+		Title    string    `json:"title"`
+		Currency string    `json:"currency"`
+		// State is open, won, lost or cancelled
+		State string      `json:"state"`
+		Lots  []BundleLot `json:"lots"`
+		// IndividualSum is the sum of the leading bids of the lots, of their winning bids once settled
+		IndividualSum float64 `json:"individual_sum"`
+		LeadingAmount float64 `json:"leading_amount"`
+		// Beating reports if the leading bundle bid beats individual_sum
+		Beating bool `json:"beating"`
+		// ServerTime is the epoch millis when the update was sent
+		ServerTime int64 `json:"server_time"`
+	} `json:"payload"`
+}
@@ -0,0 +1,133 @@
+package wsproto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CodeMalformedMessage is the server_error code of the msgs rejected by ValidateInbound, the connection is
+// closed with ClosePolicyViolation after a few of them
+const CodeMalformedMessage = "MALFORMED_MESSAGE"
+
+// ClosePolicyViolation is the close code of the connections that sent too many malformed msgs
+const ClosePolicyViolation = 1008
+
+// InboundLimits are the max sizes in bytes of the client msgs by type, chat and auctioneer msgs carry
+// free text and get more room
+var InboundLimits = map[MessageType]int{
+	MessageTypeClientBid:         512,
+	MessageTypeClientJoinLot:     256,
+	MessageTypeClientChat:        2048,
+	MessageTypeClientAuctioneer:  2048,
+	MessageTypeClientSubscribe:   256,
+	MessageTypeClientUnsubscribe: 256,
+	MessageTypeClientPingTime:    256,
+	MessageTypeClientAcceptTerms: 256,
+	MessageTypeClientQuickBid:    256,
+}
+
+var (
+	// ErrMalformedMessage is the parent of every error of ValidateInbound
+	ErrMalformedMessage   = errors.New("malformed message")
+	ErrUnknownMessageType = fmt.Errorf("%w: unknown message type", ErrMalformedMessage)
+	ErrMessageTooLarge    = fmt.Errorf("%w: message too large", ErrMalformedMessage)
+)
+
+// MaxInboundSize is the largest of InboundLimits, the read limit of the connections
+func MaxInboundSize() int {
+	size := 0
+	for _, limit := range InboundLimits {
+		size = max(size, limit)
+	}
+	return size
+}
+
+// ValidateInbound checks a client msg before it's handled: its type must be a client type, it must fit the
+// limit of its type and it must decode into the DTO of its type without unknown fields and with the
+// required fields set. the errors wrap ErrMalformedMessage
+func ValidateInbound(data []byte) error {
+	var base BaseMessage
+	if err := json.Unmarshal(data, &base); err != nil {
+		return fmt.Errorf("%w: invalid JSON", ErrMalformedMessage)
+	}
+	limit, ok := InboundLimits[base.Type]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownMessageType, base.Type)
+	}
+	if len(data) > limit {
+		return fmt.Errorf("%w: %s is limited to %d bytes", ErrMessageTooLarge, base.Type, limit)
+	}
+	switch base.Type {
+	case MessageTypeClientBid:
+		var msg ClientBidMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		// the amount itself is checked by the engine, which answers with the amount error codes
+		if msg.Payload.LotID == uuid.Nil {
+			return fmt.Errorf("%w: payload.lot_id is required", ErrMalformedMessage)
+		}
+	case MessageTypeClientQuickBid:
+		var msg ClientQuickBidMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		if msg.Payload.LotID == uuid.Nil {
+			return fmt.Errorf("%w: payload.lot_id is required", ErrMalformedMessage)
+		}
+	case MessageTypeClientChat:
+		var msg ClientChatMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		if msg.Payload.LotID == uuid.Nil {
+			return fmt.Errorf("%w: payload.lot_id is required", ErrMalformedMessage)
+		}
+	case MessageTypeClientAuctioneer:
+		var msg ClientAuctioneerMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		switch msg.Payload.Action {
+		case AuctioneerActionFairWarning, AuctioneerActionPause, AuctioneerActionResume, AuctioneerActionCommentary:
+		default:
+			return fmt.Errorf("%w: unknown auctioneer action %q", ErrMalformedMessage, msg.Payload.Action)
+		}
+	case MessageTypeClientSubscribe, MessageTypeClientUnsubscribe:
+		var msg ClientSubscriptionMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		if msg.Payload.Topic == "" {
+			return fmt.Errorf("%w: payload.topic is required", ErrMalformedMessage)
+		}
+	case MessageTypeClientPingTime:
+		var msg ClientPingTimeMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+	case MessageTypeClientAcceptTerms:
+		var msg ClientAcceptTermsMessage
+		if err := decodeStrict(data, &msg); err != nil {
+			return err
+		}
+		if msg.Payload.LotID == uuid.Nil || msg.Payload.TermsVersion == "" {
+			return fmt.Errorf("%w: payload.lot_id and payload.terms_version are required", ErrMalformedMessage)
+		}
+	}
+	return nil
+}
+
+// decodeStrict decodes data into msg rejecting the fields msg doesn't have
+func decodeStrict(data []byte, msg any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	return nil
+}
@@ -20,6 +20,13 @@ INSERT INTO auction_lots (title, description, initial_price, current_price, end_
 ('Test Bike Auction', 'A fast bike for testing.', 1000.00, 1000.00, NOW() + INTERVAL '15 minutes', 'active', NULL, INTERVAL '45 seconds');
 -- Nota: Si ejecutas esto varias veces, creará nuevos lotes cada vez.
 
+-- Tabla global de incrementos (lot_id NULL): +5 bajo 100, +10 bajo 500, +50 sobre 500
+INSERT INTO bid_increments (lot_id, min_price, increment) VALUES
+(NULL, 0.00, 5.00),
+(NULL, 100.00, 10.00),
+(NULL, 500.00, 50.00)
+ON CONFLICT DO NOTHING;
+
 -- Re-habilitar triggers si los deshabilitaste
 -- SET session_replication_role = origin;
 
@@ -0,0 +1,80 @@
+//go:build integration
+
+// Package integration runs the auction engine against a real Postgres started with testcontainers-go,
+// it needs a Docker daemon and is built with the integration build tag:
+//
+//	go test -tags integration ./test/integration/...
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/shared/db"
+	"github.com/cristianortiz/auctionEngine/internal/shared/db/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// pool is connected to the test database, shared by every test
+var pool *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run boots Postgres, applies the embedded migrations and runs the tests, the container is removed at the end
+func run(m *testing.M) int {
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, "postgres:16-alpine",
+		postgres.WithDatabase("auction_test"),
+		postgres.WithUsername("auction"),
+		postgres.WithPassword("auction"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start postgres container:", err)
+		return 1
+	}
+	defer func() { _ = container.Terminate(ctx) }()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to get postgres host:", err)
+		return 1
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to get postgres port:", err)
+		return 1
+	}
+	// the migrations and the DB helpers read the connection settings from the env, like the server
+	for key, value := range map[string]string{
+		"DB_HOST":     host,
+		"DB_PORT":     port.Port(),
+		"DB_USER":     "auction",
+		"DB_PASSWORD": "auction",
+		"DB_NAME":     "auction_test",
+		"DB_SSLMODE":  "disable",
+	} {
+		os.Setenv(key, value)
+	}
+
+	if err := migrations.RunMigrations(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to run migrations:", err)
+		return 1
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	pool, err = pgxpool.New(connectCtx, db.BuildPostgresDSN())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to postgres:", err)
+		return 1
+	}
+	defer pool.Close()
+
+	return m.Run()
+}
@@ -0,0 +1,51 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/messaging"
+	"github.com/google/uuid"
+)
+
+// TestPGNotifyBridge checks a lot change notified by an instance reaches the others, and not itself
+func TestPGNotifyBridge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sender, receiver := messaging.NewPGNotifyBridge(pool), messaging.NewPGNotifyBridge(pool)
+
+	type change struct {
+		lotID uuid.UUID
+		seq   int64
+	}
+	received, own := make(chan change, 1), make(chan change, 1)
+	go receiver.Listen(ctx, func(_ context.Context, lotID uuid.UUID, seq int64) { received <- change{lotID, seq} })
+	go sender.Listen(ctx, func(_ context.Context, lotID uuid.UUID, seq int64) { own <- change{lotID, seq} })
+
+	lotID := uuid.New()
+	// the listeners subscribe asynchronously, the change is notified until it's received
+	deadline := time.After(10 * time.Second)
+	for {
+		if err := sender.NotifyLotChanged(ctx, lotID, 7); err != nil {
+			t.Fatalf("notify: %v", err)
+		}
+		select {
+		case got := <-received:
+			if got.lotID != lotID || got.seq != 7 {
+				t.Fatalf("received %+v, want lot %s seq 7", got, lotID)
+			}
+			select {
+			case got := <-own:
+				t.Fatalf("the sender received its own change %+v", got)
+			case <-time.After(100 * time.Millisecond):
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("the change was not received")
+		}
+	}
+}
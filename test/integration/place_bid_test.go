@@ -0,0 +1,92 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/repository/postgres"
+)
+
+// TestPlaceBidConcurrent has 100 bidders bidding on the same lot at once, every bid must be either stored
+// or rejected as outbid, and the stored ones must leave the lot priced at the highest bid
+func TestPlaceBidConcurrent(t *testing.T) {
+	const bidders = 100
+	const initialPrice = 1000.0
+
+	modes := map[string]application.BidBatching{
+		"per_bid": {},
+		"batched": {Window: 5 * time.Millisecond, MaxBids: 100},
+	}
+	for name, batching := range modes {
+		t.Run(name, func(t *testing.T) {
+			s := newStack(t, batching)
+			lotID := s.createActiveLot(t, initialPrice)
+			users := seedUsers(t, bidders)
+
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				accepted []float64
+				start    = make(chan struct{})
+			)
+			for i, userID := range users {
+				// amounts far apart, so no bid is rejected only by the increment table
+				amount := initialPrice + float64(i+1)*100
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					_, err := s.service.PlaceBid(context.Background(), application.PlaceBidDTO{
+						LotID:  lotID,
+						UserID: userID,
+						Amount: amount,
+					})
+					switch {
+					case err == nil:
+						mu.Lock()
+						accepted = append(accepted, amount)
+						mu.Unlock()
+					case errors.Is(err, domain.ErrBidAmountTooLow), errors.Is(err, domain.ErrBidIncrementTooSmall):
+					default:
+						t.Errorf("unexpected error bidding %.2f: %v", amount, err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			highest := initialPrice + bidders*100
+			state, err := s.service.GetLotState(context.Background(), lotID)
+			if err != nil {
+				t.Fatalf("failed to get lot state: %v", err)
+			}
+			if state.CurrentPrice != highest {
+				t.Errorf("current price = %.2f, want the highest bid %.2f", state.CurrentPrice, highest)
+			}
+
+			stored, err := postgres.NewBidRepository(pool).GetBidsByLotID(context.Background(), lotID)
+			if err != nil {
+				t.Fatalf("failed to get bids: %v", err)
+			}
+			if len(stored) != len(accepted) {
+				t.Fatalf("%d bids stored, %d accepted", len(stored), len(accepted))
+			}
+			// in storage order every bid must outbid the previous one
+			for i := 1; i < len(stored); i++ {
+				if stored[i].Amount <= stored[i-1].Amount {
+					t.Errorf("bid %d of %.2f stored after a bid of %.2f", i, stored[i].Amount, stored[i-1].Amount)
+				}
+			}
+			if state.Seq < int64(len(stored)) {
+				t.Errorf("lot seq = %d, want at least one event per stored bid (%d)", state.Seq, len(stored))
+			}
+		})
+	}
+}
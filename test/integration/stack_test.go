@@ -0,0 +1,104 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/messaging"
+	"github.com/cristianortiz/auctionEngine/internal/auction/infra/repository/postgres"
+	wsh "github.com/cristianortiz/auctionEngine/internal/auction/infra/websocket"
+	"github.com/cristianortiz/auctionEngine/internal/shared/websocket"
+	"github.com/google/uuid"
+)
+
+// stack is the auction module wired like cmd/main.go, without the downstream modules
+type stack struct {
+	service     application.AuctionService
+	hub         *websocket.Hub
+	deadLetters *application.DeadLetterUseCase
+}
+
+// newStack wires the auction service over the test database, the hub runs until the test ends
+func newStack(t *testing.T, batching application.BidBatching) *stack {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	lotRepo := postgres.NewAuctionLotRepository(pool)
+	bidRepo := postgres.NewBidRepository(pool)
+	lotEventRepo := postgres.NewLotEventRepository(pool)
+	mediaRepo := postgres.NewLotMediaRepository(pool)
+	categoryRepo := postgres.NewCategoryRepository(pool)
+	feeScheduleRepo := postgres.NewFeeScheduleRepository(pool, domain.FlatRateFeeSchedule(0, 0))
+	paddleRepo := postgres.NewPaddleRepository(pool)
+	reservationRepo := postgres.NewBidReservationRepository(pool)
+	transactor := postgres.NewTransactor(pool)
+	clock := domain.SystemClock{}
+	updates := application.NewLotUpdateBroker()
+
+	hub := websocket.NewHub()
+	go hub.Run(ctx)
+
+	placeBidUC := application.NewPlaceBidUseCase(lotRepo, bidRepo, postgres.NewBidIncrementRepository(pool), lotEventRepo,
+		paddleRepo, postgres.NewUserLimitRepository(pool), reservationRepo, transactor, clock)
+	service := application.NewAuctionService(
+		placeBidUC,
+		application.NewGetLotStateUseCase(lotRepo, bidRepo, mediaRepo, categoryRepo, paddleRepo, feeScheduleRepo, nil, hub),
+		application.NewListActiveLotsUseCase(lotRepo, categoryRepo),
+		application.NewFinalizeLotUseCase(lotRepo, bidRepo, lotEventRepo, transactor, clock),
+		application.NewGetLotEventsUseCase(lotEventRepo),
+		application.NewCreateLotUseCase(lotRepo, transactor, clock),
+		application.NewUpdateLotUseCase(lotRepo, lotEventRepo, transactor, clock),
+		application.NewLotLifecycleUseCase(lotRepo, lotEventRepo, reservationRepo, transactor, clock),
+		application.NewSearchLotsUseCase(lotRepo, categoryRepo),
+		application.NewVoidBidUseCase(lotRepo, bidRepo, lotEventRepo, reservationRepo, transactor, clock),
+		updates,
+		// outbid, won and opened notifications reach the WS clients through the private notifier and the lot broadcaster
+		messaging.NewFanoutPublisher(messaging.NewLogPublisher(), wsh.NewPrivateNotifier(hub), wsh.NewLotBroadcaster(hub)),
+		application.NewLotCommandQueue(128, time.Minute, batching),
+		application.NewReplayEngine(lotRepo, lotEventRepo, updates, time.Minute),
+	)
+	deadLetters := application.NewDeadLetterUseCase(postgres.NewDeadLetterRepository(pool))
+	return &stack{service: service, hub: hub, deadLetters: deadLetters}
+}
+
+// createActiveLot creates a lot open for bidding until an hour from now
+func (s *stack) createActiveLot(t *testing.T, initialPrice float64) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	lot, err := s.service.CreateLot(ctx, application.CreateLotDTO{
+		Title:        "Integration lot " + t.Name(),
+		Description:  "created by the integration tests",
+		InitialPrice: initialPrice,
+		EndTime:      time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create lot: %v", err)
+	}
+	if _, err := s.service.StartLot(ctx, lot.LotID); err != nil {
+		t.Fatalf("failed to start lot: %v", err)
+	}
+	return lot.LotID
+}
+
+// seedUsers inserts n users, bids reference the users table
+func seedUsers(t *testing.T, n int) []uuid.UUID {
+	t.Helper()
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+		_, err := pool.Exec(context.Background(),
+			`INSERT INTO users (id, username, email, password_hash) VALUES ($1, $2, $3, 'x')`,
+			ids[i], fmt.Sprintf("user-%s", ids[i]), fmt.Sprintf("%s@example.com", ids[i]))
+		if err != nil {
+			t.Fatalf("failed to seed user: %v", err)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,190 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cristianortiz/auctionEngine/internal/auction/application"
+	"github.com/cristianortiz/auctionEngine/internal/auction/domain"
+	wsh "github.com/cristianortiz/auctionEngine/internal/auction/infra/websocket"
+	"github.com/cristianortiz/auctionEngine/internal/shared/auth"
+	"github.com/cristianortiz/auctionEngine/internal/shared/httpserver"
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+)
+
+const tokenSecret = "integration-test-secret"
+
+type wsMessage struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// startServer serves the auction WS endpoint of s on a free local port and returns its base URL
+func startServer(t *testing.T, s *stack) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	presence := wsh.NewPresenceBroadcaster(s.hub, 100*time.Millisecond)
+	go presence.Run(ctx)
+	handler := wsh.NewAuctionWSHandler(s.service, s.hub, presence, s.deadLetters, nil, nil, domain.SystemClock{}, 4, 64)
+	go handler.ListenForMessages(ctx)
+	go handler.ListenForJoins(ctx)
+	go handler.ListenForDropped(ctx)
+	go handler.ForwardLotUpdates(ctx)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	server := httpserver.NewServer(addr, s.hub, ctx, httpserver.Config{Tokens: auth.NewTokenService(tokenSecret)})
+	go func() { _ = server.Start(addr) }()
+	t.Cleanup(func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	})
+
+	// Start returns before listening, wait until the port accepts connections
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return "ws://" + addr
+}
+
+// dial connects userID as a bidder to the lot and consumes the initial state
+func dial(t *testing.T, baseURL string, lotID, userID uuid.UUID) *websocket.Conn {
+	t.Helper()
+	token, err := auth.NewTokenService(tokenSecret).Issue(auth.Claims{UserID: userID, Role: auth.RoleBidder}, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	u := fmt.Sprintf("%s/ws/auction/%s?%s", baseURL, lotID, url.Values{"token": {token}}.Encode())
+	conn, _, err := websocket.DefaultDialer.Dial(u, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitFor(t, conn, "server_initial_state", nil)
+	return conn
+}
+
+// waitFor reads messages until one of type msgType for which match returns true (nil matches any)
+func waitFor(t *testing.T, conn *websocket.Conn, msgType string, match func(payload json.RawMessage) bool) json.RawMessage {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		if msg.Type == "server_error" && msgType != "server_error" {
+			t.Fatalf("waiting for %s: server error %s", msgType, msg.Payload)
+		}
+		if msg.Type == msgType && (match == nil || match(msg.Payload)) {
+			return msg.Payload
+		}
+	}
+}
+
+func sendBid(t *testing.T, conn *websocket.Conn, lotID uuid.UUID, amount float64) {
+	t.Helper()
+	err := conn.WriteJSON(map[string]any{
+		"type":    "client_bid",
+		"payload": map[string]any{"lot_id": lotID, "amount": amount},
+	})
+	if err != nil {
+		t.Fatalf("failed to send bid: %v", err)
+	}
+}
+
+// lastBidIs matches the lot broadcasts (full updates and deltas) carrying amount as the last bid
+func lastBidIs(amount float64) func(json.RawMessage) bool {
+	return func(payload json.RawMessage) bool {
+		var lot struct {
+			LastBidAmount *float64 `json:"last_bid_amount"`
+		}
+		return json.Unmarshal(payload, &lot) == nil && lot.LastBidAmount != nil && *lot.LastBidAmount == amount
+	}
+}
+
+// TestWebSocketBidFlow places bids through the WS endpoint: the bidder gets the confirmation, the other
+// bidder of the lot gets the broadcast, and the first one is told when it's outbid
+func TestWebSocketBidFlow(t *testing.T) {
+	s := newStack(t, application.BidBatching{})
+	lotID := s.createActiveLot(t, 100)
+	users := seedUsers(t, 2)
+	baseURL := startServer(t, s)
+
+	alice := dial(t, baseURL, lotID, users[0])
+	bob := dial(t, baseURL, lotID, users[1])
+
+	sendBid(t, alice, lotID, 150)
+	var accepted struct {
+		Amount float64 `json:"amount"`
+		Paddle int     `json:"paddle"`
+	}
+	if err := json.Unmarshal(waitFor(t, alice, "server_bid_accepted", nil), &accepted); err != nil {
+		t.Fatalf("invalid server_bid_accepted: %v", err)
+	}
+	if accepted.Amount != 150 {
+		t.Errorf("accepted amount = %.2f, want 150", accepted.Amount)
+	}
+	if accepted.Paddle == 0 {
+		t.Error("accepted bid has no paddle")
+	}
+	waitForLotBid(t, bob, 150)
+
+	sendBid(t, bob, lotID, 200)
+	waitFor(t, bob, "server_bid_accepted", nil)
+	waitFor(t, alice, "server_outbid", nil)
+
+	// a bid below the current price is rejected to its sender only
+	sendBid(t, alice, lotID, 120)
+	waitFor(t, alice, "server_error", nil)
+
+	state, err := s.service.GetLotState(context.Background(), lotID)
+	if err != nil {
+		t.Fatalf("failed to get lot state: %v", err)
+	}
+	if state.CurrentPrice != 200 {
+		t.Errorf("current price = %.2f, want 200", state.CurrentPrice)
+	}
+}
+
+// waitForLotBid waits for the lot broadcast of a bid, sent as a full update or as a delta
+func waitForLotBid(t *testing.T, conn *websocket.Conn, amount float64) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("waiting for the broadcast of the %.2f bid: %v", amount, err)
+		}
+		if (msg.Type == "server_lot_update" || msg.Type == "server_lot_delta") && lastBidIs(amount)(msg.Payload) {
+			return
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Package demo embeds the demo auction room, a single page connecting to the lot WS endpoint for manual
+// QA and demos
+package demo
+
+import _ "embed"
+
+// Page is the HTML of the auction room, served at /demo/:lotid. it reads the lot ID from its path and
+// the token from the ?token= query param or its form
+//
+//go:embed index.html
+var Page []byte